@@ -0,0 +1,404 @@
+// WHY: Operators inspect governance history from a shell, not from Go.
+// These subcommands read the persistent ledger only; none of them can
+// append a receipt or reach the corridor.
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/kernel"
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+// runAudit dispatches audit subcommands
+func runAudit(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "usage: oi-kernel audit verify|export|tail|query|dashboard [flags]")
+		return 2
+	}
+
+	switch args[0] {
+	case "verify":
+		return runAuditVerify(args[1:], stdout, stderr)
+	case "export":
+		return runAuditExport(args[1:], stdout, stderr)
+	case "tail":
+		return runAuditTail(args[1:], stdout, stderr)
+	case "query":
+		return runAuditQuery(args[1:], stdout, stderr)
+	case "dashboard":
+		return runAuditDashboard(args[1:], stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "oi-kernel: unknown audit subcommand %q\n", args[0])
+		return 2
+	}
+}
+
+// runAuditVerify checks hashes, linkage, signatures, checkpoints, and seals
+func runAuditVerify(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("oi-kernel audit verify", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	ledgerPath := flags.String("ledger", "", "JSONL receipt file to verify")
+	pubKeyPath := flags.String("pubkey", "", "PEM public key that signed the receipts")
+	keyPath := flags.String("key", "", "PEM private key that signed the receipts (its public half is used)")
+	keyID := flags.String("key-id", kernel.AuditKeyID, "key ID the receipts were signed under")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *ledgerPath == "" {
+		fmt.Fprintln(stderr, "oi-kernel audit verify: -ledger is required")
+		return 2
+	}
+	if *pubKeyPath != "" && *keyPath != "" {
+		fmt.Fprintln(stderr, "oi-kernel audit verify: use -pubkey or -key, not both")
+		return 2
+	}
+
+	ledger, err := audit.ReadFileLedger(*ledgerPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "oi-kernel audit verify: FAIL: %v\n", err)
+		return 1
+	}
+	defer ledger.Close()
+
+	if err := trustVerifyKey(ledger, *keyID, *pubKeyPath, *keyPath); err != nil {
+		fmt.Fprintf(stderr, "oi-kernel audit verify: %v\n", err)
+		return 2
+	}
+
+	// WHY: A signed ledger with no trusted key fails here rather than
+	// passing on hashes alone.
+	if _, err := ledger.Verify(); err != nil {
+		fmt.Fprintf(stderr, "oi-kernel audit verify: FAIL: %v\n", err)
+		return 1
+	}
+
+	receipts := ledger.GetReceipts()
+	last := receipts[len(receipts)-1]
+	fmt.Fprintf(stdout, "OK: %d receipts verified through sequence %d (head %s)\n",
+		len(receipts), last.Sequence, last.CurrentHash)
+	return 0
+}
+
+// trustVerifyKey trusts the key given on the command line, if any
+func trustVerifyKey(ledger *audit.Ledger, keyID, pubKeyPath, keyPath string) error {
+	public, err := loadVerifyKey(keyID, pubKeyPath, keyPath)
+	if err != nil || public == nil {
+		return err
+	}
+	return ledger.TrustKey(keyID, public)
+}
+
+// loadVerifyKey loads the public key given on the command line, as a
+// public key or the public half of a private one; nil if none was given
+func loadVerifyKey(keyID, pubKeyPath, keyPath string) (crypto.PublicKey, error) {
+	switch {
+	case pubKeyPath != "":
+		return signing.LoadPublicKey(pubKeyPath)
+	case keyPath != "":
+		signer, err := signing.LoadLocalSigner(keyID, keyPath)
+		if err != nil {
+			return nil, err
+		}
+		return signer.Public(), nil
+	default:
+		return nil, nil
+	}
+}
+
+// runAuditExport writes a persistent ledger in a SIEM-friendly format
+func runAuditExport(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("oi-kernel audit export", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	ledgerPath := flags.String("ledger", "", "JSONL receipt file to export")
+	formatName := flags.String("format", string(audit.FormatJSONL), "export format: jsonl, csv, cef, or otlp")
+	outPath := flags.String("out", "", "output file (default: stdout)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *ledgerPath == "" {
+		fmt.Fprintln(stderr, "oi-kernel audit export: -ledger is required")
+		return 2
+	}
+
+	format, err := audit.ParseExportFormat(*formatName)
+	if err != nil {
+		fmt.Fprintf(stderr, "oi-kernel audit export: %v\n", err)
+		return 2
+	}
+
+	// Opening the ledger verifies the chain, so a tampered file is never exported
+	ledger, err := audit.ReadFileLedger(*ledgerPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "oi-kernel audit export: %v\n", err)
+		return 1
+	}
+	defer ledger.Close()
+
+	out := stdout
+	if *outPath != "" {
+		file, err := os.OpenFile(*outPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+		if err != nil {
+			fmt.Fprintf(stderr, "oi-kernel audit export: %v\n", err)
+			return 1
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if err := ledger.Export(out, format); err != nil {
+		fmt.Fprintf(stderr, "oi-kernel audit export: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runAuditTail prints the newest receipts and, with -f, follows appends.
+// WHY: tail is a live view, not an integrity check; it prints what the
+// file holds without verifying the chain. Use audit verify for that.
+func runAuditTail(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("oi-kernel audit tail", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	ledgerPath := flags.String("ledger", "", "JSONL receipt file to tail")
+	count := flags.Int("n", 10, "number of receipts to print before following")
+	follow := flags.Bool("f", false, "keep printing receipts as they are appended")
+	formatName := flags.String("format", string(audit.FormatJSONL), "line format: jsonl or cef")
+	interval := flags.Duration("interval", time.Second, "poll interval with -f")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *ledgerPath == "" {
+		fmt.Fprintln(stderr, "oi-kernel audit tail: -ledger is required")
+		return 2
+	}
+	if *count < 0 || *interval <= 0 {
+		fmt.Fprintln(stderr, "oi-kernel audit tail: -n must be non-negative and -interval positive")
+		return 2
+	}
+
+	format, err := parseLineFormat(*formatName)
+	if err != nil {
+		fmt.Fprintf(stderr, "oi-kernel audit tail: %v\n", err)
+		return 2
+	}
+
+	receipts, offset, err := readAppended(*ledgerPath, 0)
+	if err != nil {
+		fmt.Fprintf(stderr, "oi-kernel audit tail: %v\n", err)
+		return 1
+	}
+	if len(receipts) > *count {
+		receipts = receipts[len(receipts)-*count:]
+	}
+	if err := audit.ExportReceipts(stdout, receipts, format); err != nil {
+		fmt.Fprintf(stderr, "oi-kernel audit tail: %v\n", err)
+		return 1
+	}
+	if !*follow {
+		return 0
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	stop := make(chan struct{})
+	go func() {
+		<-interrupt
+		close(stop)
+	}()
+
+	if err := followLedger(*ledgerPath, offset, stdout, format, *interval, stop); err != nil {
+		fmt.Fprintf(stderr, "oi-kernel audit tail: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// parseLineFormat accepts only formats that write one receipt per line,
+// since tail prints receipts in batches as they arrive
+func parseLineFormat(name string) (audit.ExportFormat, error) {
+	format, err := audit.ParseExportFormat(name)
+	if err != nil {
+		return "", err
+	}
+	if format != audit.FormatJSONL && format != audit.FormatCEF {
+		return "", fmt.Errorf("format %q is not line-oriented (want jsonl or cef)", name)
+	}
+	return format, nil
+}
+
+// followLedger polls the ledger file and prints receipts appended after
+// offset until stop is closed
+func followLedger(path string, offset int64, out io.Writer, format audit.ExportFormat, interval time.Duration, stop <-chan struct{}) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+
+		receipts, next, err := readAppended(path, offset)
+		if err != nil {
+			return err
+		}
+		if err := audit.ExportReceipts(out, receipts, format); err != nil {
+			return err
+		}
+		offset = next
+	}
+}
+
+// readAppended decodes the complete receipt lines after offset and returns
+// the offset just past them. A line still being written is left for the
+// next read.
+func readAppended(path string, offset int64) ([]audit.Receipt, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, offset, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, offset, err
+	}
+	// WHY: The ledger is append-only; a shrinking file means it was
+	// replaced or truncated, which tail must report rather than paper over.
+	if info.Size() < offset {
+		return nil, offset, fmt.Errorf("ledger %s shrank from %d to %d bytes", path, offset, info.Size())
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset, err
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, offset, err
+	}
+
+	end := bytes.LastIndexByte(data, '\n')
+	if end < 0 {
+		return nil, offset, nil
+	}
+	receipts, err := audit.ReadReceipts(bytes.NewReader(data[:end+1]))
+	if err != nil {
+		return nil, offset, err
+	}
+	return receipts, offset + int64(end+1), nil
+}
+
+// runAuditQuery prints receipts matching a filter, one page at a time
+func runAuditQuery(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("oi-kernel audit query", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	ledgerPath := flags.String("ledger", "", "JSONL receipt file to query")
+	eventTypes := flags.String("type", "", "comma-separated event types")
+	principal := flags.String("principal", "", "principal ID")
+	namespace := flags.String("namespace", "", "namespace ID")
+	token := flags.String("token", "", "token digest")
+	decision := flags.String("decision", "", "cdi_decision outcome: ALLOW, DENY, or DEGRADE")
+	minSeverity := flags.String("min-severity", "", "minimum severity: info, warn, or critical")
+	categories := flags.String("category", "", "comma-separated categories")
+	since := flags.String("since", "", "earliest timestamp, inclusive (RFC 3339 or Unix seconds)")
+	until := flags.String("until", "", "latest timestamp, exclusive (RFC 3339 or Unix seconds)")
+	from := flags.Int64("from", 0, "first sequence to consider (pagination cursor)")
+	limit := flags.Int("limit", audit.DefaultQueryLimit, "maximum receipts to print")
+	formatName := flags.String("format", string(audit.FormatJSONL), "output format: jsonl, csv, cef, or otlp")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *ledgerPath == "" {
+		fmt.Fprintln(stderr, "oi-kernel audit query: -ledger is required")
+		return 2
+	}
+
+	format, err := audit.ParseExportFormat(*formatName)
+	if err != nil {
+		fmt.Fprintf(stderr, "oi-kernel audit query: %v\n", err)
+		return 2
+	}
+
+	filter := audit.ReceiptFilter{
+		EventTypes:   splitList(*eventTypes),
+		PrincipalID:  *principal,
+		NamespaceID:  *namespace,
+		TokenDigest:  *token,
+		Decision:     strings.ToUpper(*decision),
+		MinSeverity:  audit.Severity(strings.ToLower(*minSeverity)),
+		FromSequence: *from,
+		Limit:        *limit,
+	}
+	for _, category := range splitList(*categories) {
+		filter.Categories = append(filter.Categories, audit.Category(strings.ToLower(category)))
+	}
+	if filter.Since, err = parseTimestamp(*since); err != nil {
+		fmt.Fprintf(stderr, "oi-kernel audit query: -since: %v\n", err)
+		return 2
+	}
+	if filter.Until, err = parseTimestamp(*until); err != nil {
+		fmt.Fprintf(stderr, "oi-kernel audit query: -until: %v\n", err)
+		return 2
+	}
+
+	// Opening the ledger verifies the chain, so answers never come from a tampered file
+	ledger, err := audit.ReadFileLedger(*ledgerPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "oi-kernel audit query: %v\n", err)
+		return 1
+	}
+	defer ledger.Close()
+
+	page, err := ledger.Query(filter)
+	if err != nil {
+		fmt.Fprintf(stderr, "oi-kernel audit query: %v\n", err)
+		return 2
+	}
+	if err := audit.ExportReceipts(stdout, page.Receipts, format); err != nil {
+		fmt.Fprintf(stderr, "oi-kernel audit query: %v\n", err)
+		return 1
+	}
+	if page.HasMore {
+		fmt.Fprintf(stderr, "oi-kernel audit query: more results; rerun with -from %d\n", page.NextSequence)
+	}
+	return 0
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseTimestamp accepts RFC 3339 or Unix seconds; empty means unbounded
+func parseTimestamp(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return seconds, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, fmt.Errorf("want RFC 3339 or Unix seconds, got %q", value)
+	}
+	return parsed.Unix(), nil
+}
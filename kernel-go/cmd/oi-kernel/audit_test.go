@@ -0,0 +1,199 @@
+// WHY: These tests prove the audit subcommands read the persistent ledger
+// faithfully and fail closed on tampered files or missing trust.
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+)
+
+// writeAuditKey writes a PKCS#8 private key and its PKIX public key
+func writeAuditKey(t *testing.T, dir string) (keyPath, pubKeyPath string) {
+	t.Helper()
+	public, private, _ := ed25519.GenerateKey(rand.Reader)
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("marshal private: %v", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatalf("marshal public: %v", err)
+	}
+
+	keyPath = filepath.Join(dir, "audit.pem")
+	pubKeyPath = filepath.Join(dir, "audit.pub.pem")
+	os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0o600)
+	os.WriteFile(pubKeyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0o600)
+	return keyPath, pubKeyPath
+}
+
+// signedLedger runs one request and returns the ledger and key paths
+func signedLedger(t *testing.T) (ledgerPath, keyPath, pubKeyPath string) {
+	t.Helper()
+	dir := t.TempDir()
+	keyPath, pubKeyPath = writeAuditKey(t, dir)
+	ledgerPath = filepath.Join(dir, "receipts.jsonl")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-input", "hello", "-ledger", ledgerPath, "-key", keyPath}, &stdout, &stderr); code != 0 {
+		t.Fatalf("run failed (%d): %s", code, stderr.String())
+	}
+	return ledgerPath, keyPath, pubKeyPath
+}
+
+// TestAuditVerifyRequiresTrustAndDetectsTampering proves verify passes only
+// with the signing key and fails on an edited ledger
+func TestAuditVerifyRequiresTrustAndDetectsTampering(t *testing.T) {
+	ledgerPath, keyPath, pubKeyPath := signedLedger(t)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"audit", "verify", "-ledger", ledgerPath}, &stdout, &stderr); code != 1 {
+		t.Fatalf("signed ledger without a trusted key must fail, got %d", code)
+	}
+
+	for _, keyFlag := range [][]string{{"-pubkey", pubKeyPath}, {"-key", keyPath}} {
+		stdout.Reset()
+		args := append([]string{"audit", "verify", "-ledger", ledgerPath}, keyFlag...)
+		if code := run(args, &stdout, &stderr); code != 0 {
+			t.Fatalf("verify %v failed (%d): %s", keyFlag, code, stderr.String())
+		}
+		if !strings.HasPrefix(stdout.String(), "OK: ") {
+			t.Fatalf("unexpected verify output: %s", stdout.String())
+		}
+	}
+
+	data, _ := os.ReadFile(ledgerPath)
+	os.WriteFile(ledgerPath, bytes.Replace(data, []byte("ALLOW"), []byte("DENY"), 1), 0o600)
+	if code := run([]string{"audit", "verify", "-ledger", ledgerPath, "-pubkey", pubKeyPath}, &stdout, &stderr); code != 1 {
+		t.Fatalf("tampered ledger must fail verification, got %d", code)
+	}
+}
+
+// TestAuditCommandsOnlyReadTheLedger proves verify, export, and query
+// fail on a missing ledger without creating it, and leave an existing
+// one byte for byte as it was
+func TestAuditCommandsOnlyReadTheLedger(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.jsonl")
+	for _, command := range []string{"verify", "export", "query"} {
+		var stdout, stderr bytes.Buffer
+		if code := run([]string{"audit", command, "-ledger", missing}, &stdout, &stderr); code != 1 {
+			t.Fatalf("%s of a missing ledger must fail, got %d: %s", command, code, stdout.String())
+		}
+		if _, err := os.Stat(missing); !os.IsNotExist(err) {
+			t.Fatalf("%s must not create the ledger", command)
+		}
+	}
+
+	ledgerPath, _, pubKeyPath := signedLedger(t)
+	before, _ := os.ReadFile(ledgerPath)
+	for _, args := range [][]string{
+		{"audit", "verify", "-ledger", ledgerPath, "-pubkey", pubKeyPath},
+		{"audit", "export", "-ledger", ledgerPath},
+		{"audit", "query", "-ledger", ledgerPath},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := run(args, &stdout, &stderr); code != 0 {
+			t.Fatalf("%v failed (%d): %s", args[:2], code, stderr.String())
+		}
+	}
+	if after, _ := os.ReadFile(ledgerPath); !bytes.Equal(before, after) {
+		t.Fatal("reading the ledger must not change it")
+	}
+}
+
+// TestAuditQueryFiltersReceipts proves query applies the ledger filter
+func TestAuditQueryFiltersReceipts(t *testing.T) {
+	ledgerPath, _, _ := signedLedger(t)
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"audit", "query", "-ledger", ledgerPath, "-type", "cdi_decision", "-decision", "allow"}
+	if code := run(args, &stdout, &stderr); code != 0 {
+		t.Fatalf("query failed (%d): %s", code, stderr.String())
+	}
+
+	receipts, err := audit.ReadReceipts(&stdout)
+	if err != nil {
+		t.Fatalf("decode query output: %v", err)
+	}
+	if len(receipts) == 0 {
+		t.Fatal("query returned no cdi_decision receipts")
+	}
+	for _, receipt := range receipts {
+		if receipt.EventType != "cdi_decision" {
+			t.Fatalf("query returned %s", receipt.EventType)
+		}
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := run([]string{"audit", "query", "-ledger", ledgerPath, "-limit", "1"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("paged query failed (%d): %s", code, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "rerun with -from") {
+		t.Fatalf("paged query must report the next cursor: %s", stderr.String())
+	}
+
+	if code := run([]string{"audit", "query", "-ledger", ledgerPath, "-since", "yesterday"}, &stdout, &stderr); code != 2 {
+		t.Fatalf("bad timestamp must be a usage error, got %d", code)
+	}
+}
+
+// TestAuditTailFollowsAppends proves tail prints the newest receipts and
+// then only the receipts appended while following
+func TestAuditTailFollowsAppends(t *testing.T) {
+	ledgerPath, keyPath, _ := signedLedger(t)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"audit", "tail", "-ledger", ledgerPath, "-n", "2"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("tail failed (%d): %s", code, stderr.String())
+	}
+	if lines := strings.Count(stdout.String(), "\n"); lines != 2 {
+		t.Fatalf("tail -n 2 printed %d lines", lines)
+	}
+
+	existing, offset, err := readAppended(ledgerPath, 0)
+	if err != nil {
+		t.Fatalf("read ledger: %v", err)
+	}
+
+	var followed bytes.Buffer
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- followLedger(ledgerPath, offset, &followed, audit.FormatJSONL, 10*time.Millisecond, stop)
+	}()
+
+	if code := run([]string{"-input", "again", "-ledger", ledgerPath, "-key", keyPath}, &stdout, &stderr); code != 0 {
+		t.Fatalf("second run failed (%d): %s", code, stderr.String())
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(stop)
+	if err := <-done; err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+
+	appended, err := audit.ReadReceipts(&followed)
+	if err != nil {
+		t.Fatalf("decode followed output: %v", err)
+	}
+	if len(appended) == 0 {
+		t.Fatal("follow printed no appended receipts")
+	}
+	if appended[0].Sequence != existing[len(existing)-1].Sequence+1 {
+		t.Fatalf("follow started at sequence %d, want %d", appended[0].Sequence, existing[len(existing)-1].Sequence+1)
+	}
+
+	if code := run([]string{"audit", "tail", "-ledger", ledgerPath, "-format", "csv"}, &stdout, &stderr); code != 2 {
+		t.Fatalf("csv is not line-oriented and must be refused, got %d", code)
+	}
+}
@@ -0,0 +1,187 @@
+// WHY: Tuning governance means replaying real traffic against a policy and
+// reading what it decided, at a volume no one types into a REPL. batch
+// reads captured requests as JSON lines on stdin, runs each through the
+// corridor of one kernel, in order, as live traffic would be, and writes
+// one JSON result per line: the decision, the shaped content or refusal,
+// and a summary of the receipts the request left. It adds no authority
+// of its own: each line goes through kernel.Execute, and with an identity
+// issuer configured each line runs only as the principal its bearer names.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/config"
+	"github.com/user/oi/kernel-go/internal/kernel"
+	"github.com/user/oi/kernel-go/internal/server"
+)
+
+// maxBatchLine bounds one request line
+const maxBatchLine = 1 << 20
+
+// batchRequest is one line of batch input
+type batchRequest struct {
+	server.ExecuteRequest
+
+	// ID, if given, is the request ID its receipts carry
+	ID string `json:"id,omitempty"`
+
+	// Bearer is the line's bearer token; OI_BEARER_TOKEN if empty
+	Bearer string `json:"bearer,omitempty"`
+}
+
+// batchResult is one line of batch output
+type batchResult struct {
+	// Line is the input line the result answers
+	Line int `json:"line"`
+
+	RequestID string `json:"request_id,omitempty"`
+
+	// Decision is CDI's decision on the input (ALLOW, DENY, DEGRADE), or
+	// empty if the request was refused before CDI judged it
+	Decision string `json:"decision,omitempty"`
+
+	Success  bool            `json:"success"`
+	Content  string          `json:"content,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	Receipts *receiptSummary `json:"receipts,omitempty"`
+}
+
+// receiptSummary summarizes the receipts one request left
+type receiptSummary struct {
+	Count         int            `json:"count"`
+	ByType        map[string]int `json:"by_type"`
+	MaxSeverity   audit.Severity `json:"max_severity,omitempty"`
+	FirstSequence int64          `json:"first_sequence"`
+	LastSequence  int64          `json:"last_sequence"`
+}
+
+// runBatch runs each JSON request line of stdin and writes its result to
+// stdout; it fails if any line could not be run
+func runBatch(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("oi-kernel batch", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var cfg config.Config
+	cfg.Bind(flags, config.Identity)
+	if err := cfg.Parse(flags, args); err != nil {
+		return 2
+	}
+	k, err := cfg.Build("batch_principal", "batch_namespace")
+	if err != nil {
+		fmt.Fprintf(stderr, "oi-kernel batch: %v\n", err)
+		return config.ExitCode(err)
+	}
+	defer k.Close()
+
+	out := json.NewEncoder(stdout)
+	out.SetEscapeHTML(false)
+	scanner := bufio.NewScanner(stdin)
+	scanner.Buffer(make([]byte, 64*1024), maxBatchLine)
+	line, failed := 0, 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		result := runBatchLine(k.State, scanner.Bytes())
+		result.Line = line
+		if result.RequestID == "" {
+			failed++
+		}
+		if err := out.Encode(result); err != nil {
+			fmt.Fprintf(stderr, "oi-kernel batch: %v\n", err)
+			return 1
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(stderr, "oi-kernel batch: line %d: %v\n", line+1, err)
+		return 1
+	}
+	if failed > 0 {
+		fmt.Fprintf(stderr, "oi-kernel batch: %d of %d lines could not be run\n", failed, line)
+		return 1
+	}
+	return 0
+}
+
+// runBatchLine runs one request line; a line that is not a request is a
+// result with only its error
+func runBatchLine(state *kernel.SystemState, data []byte) batchResult {
+	var req batchRequest
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		return batchResult{Error: fmt.Sprintf("malformed request: %v", err)}
+	}
+	if req.Metadata == nil {
+		req.Metadata = map[string]interface{}{}
+	}
+	bearer := req.Bearer
+	if bearer == "" {
+		bearer = os.Getenv("OI_BEARER_TOKEN")
+	}
+
+	// WHY: IDs come from the caller and may repeat; only receipts after
+	// this head can be this line's
+	from := state.AuditLedger.HeadSequence() + 1
+	resp, err := kernel.Execute(&kernel.Request{
+		ID:          req.ID,
+		RawInput:    req.Input,
+		Metadata:    req.Metadata,
+		NamespaceID: req.NamespaceID,
+		SessionID:   req.SessionID,
+		BearerToken: bearer,
+	}, state)
+	if resp == nil {
+		return batchResult{Error: fmt.Sprintf("execute: %v", err)}
+	}
+	result := batchResult{
+		RequestID: resp.RequestID,
+		Success:   resp.Success,
+		Content:   resp.Content,
+		Error:     resp.Error,
+		Receipts:  summarizeReceipts(state, resp.RequestID, from),
+	}
+	for _, stage := range resp.AuditTrail {
+		if decision, ok := strings.CutPrefix(stage, "cdi_decision: "); ok {
+			result.Decision = decision
+		}
+	}
+	return result
+}
+
+// summarizeReceipts summarizes the receipts requestID left from sequence
+// from on
+func summarizeReceipts(state *kernel.SystemState, requestID string, from int64) *receiptSummary {
+	summary := &receiptSummary{ByType: map[string]int{}}
+	filter := audit.ReceiptFilter{RequestID: requestID, FromSequence: from, Limit: audit.MaxQueryLimit}
+	for {
+		page, err := state.AuditLedger.Query(filter)
+		if err != nil {
+			return summary
+		}
+		for _, receipt := range page.Receipts {
+			if summary.Count == 0 {
+				summary.FirstSequence = receipt.Sequence
+			}
+			summary.Count++
+			summary.LastSequence = receipt.Sequence
+			summary.ByType[receipt.EventType]++
+			if summary.MaxSeverity == "" || receipt.Severity.AtLeast(summary.MaxSeverity) {
+				summary.MaxSeverity = receipt.Severity
+			}
+		}
+		if !page.HasMore {
+			return summary
+		}
+		filter.FromSequence = page.NextSequence
+	}
+}
@@ -0,0 +1,91 @@
+// WHY: These tests prove batch mode answers every request line with its
+// decision and receipts, in order, on one kernel, and reports lines it
+// could not run without stopping the batch.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestBatchAnswersEachLine proves each request line gets one result line
+// with its decision, content or refusal, and receipt summary
+func TestBatchAnswersEachLine(t *testing.T) {
+	input := strings.Join([]string{
+		`{"input": "hello", "id": "req-1"}`,
+		``,
+		`{"input": "risky", "metadata": {"sensitivity": "high"}}`,
+		`{"input": "medium", "metadata": {"sensitivity": "medium"}}`,
+		`not json`,
+		`{"input": "x", "unknown": true}`,
+	}, "\n")
+
+	var stdout, stderr bytes.Buffer
+	if code := runBatch(nil, strings.NewReader(input), &stdout, &stderr); code != 1 {
+		t.Fatalf("a batch with malformed lines must fail once done, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "2 of 6 lines could not be run") {
+		t.Fatalf("the failed lines must be counted: %s", stderr.String())
+	}
+
+	var results []batchResult
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		var result batchResult
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			t.Fatalf("result line is not JSON: %s", scanner.Text())
+		}
+		results = append(results, result)
+	}
+	if len(results) != 5 {
+		t.Fatalf("want a result per non-blank line, got %d:\n%s", len(results), stdout.String())
+	}
+
+	allowed := results[0]
+	if allowed.Line != 1 || allowed.RequestID != "req-1" || allowed.Decision != "ALLOW" || !allowed.Success || allowed.Content == "" {
+		t.Fatalf("clean request: %+v", allowed)
+	}
+	if allowed.Receipts == nil || allowed.Receipts.ByType["cdi_decision"] != 1 || allowed.Receipts.Count < 2 ||
+		allowed.Receipts.LastSequence < allowed.Receipts.FirstSequence {
+		t.Fatalf("clean request receipts: %+v", allowed.Receipts)
+	}
+
+	denied := results[1]
+	if denied.Line != 3 || denied.Decision != "DENY" || denied.Success || !strings.Contains(denied.Error, "high_risk_requires_consent") {
+		t.Fatalf("high-sensitivity request: %+v", denied)
+	}
+	if denied.Receipts.FirstSequence <= allowed.Receipts.LastSequence {
+		t.Fatal("lines must run in order on one kernel")
+	}
+	if results[2].Decision != "DEGRADE" {
+		t.Fatalf("medium-sensitivity request: %+v", results[2])
+	}
+
+	for _, malformed := range results[3:] {
+		if malformed.RequestID != "" || malformed.Receipts != nil || !strings.Contains(malformed.Error, "malformed request") {
+			t.Fatalf("malformed line: %+v", malformed)
+		}
+	}
+}
+
+// TestBatchScopesReceiptsToTheirLine proves a line reusing an earlier
+// line's ID is summarized by its own receipts only
+func TestBatchScopesReceiptsToTheirLine(t *testing.T) {
+	input := `{"input": "hello", "id": "same"}` + "\n" + `{"input": "hello", "id": "same"}` + "\n"
+
+	var stdout, stderr bytes.Buffer
+	if code := runBatch(nil, strings.NewReader(input), &stdout, &stderr); code != 0 {
+		t.Fatalf("batch failed (%d): %s", code, stderr.String())
+	}
+	var first, second batchResult
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 || json.Unmarshal([]byte(lines[0]), &first) != nil || json.Unmarshal([]byte(lines[1]), &second) != nil {
+		t.Fatalf("want two results, got:\n%s", stdout.String())
+	}
+	if second.Receipts.ByType["cdi_decision"] != 1 || second.Receipts.FirstSequence <= first.Receipts.LastSequence {
+		t.Fatalf("the second line must not count the first's receipts: %+v then %+v", first.Receipts, second.Receipts)
+	}
+}
@@ -0,0 +1,34 @@
+// WHY: A config that fails to load takes a kernel down at deploy time, or,
+// worse, keeps it from starting during an incident. `config validate`
+// checks a config the way serve and run would load it, without standing a
+// kernel up or writing to its ledger, and lists every problem at once.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/user/oi/kernel-go/internal/config"
+)
+
+// runConfig dispatches config subcommands
+func runConfig(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "validate" {
+		fmt.Fprintln(stderr, "usage: oi-kernel config validate [-config oi.json] [flags]")
+		return 2
+	}
+	flags := flag.NewFlagSet("oi-kernel config validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var cfg config.Config
+	cfg.Bind(flags, config.Identity|config.Admin|config.Serve)
+	if err := cfg.Parse(flags, args[1:]); err != nil {
+		return 2
+	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(stderr, "oi-kernel config validate: FAIL:\n%v\n", err)
+		return 1
+	}
+	fmt.Fprintln(stdout, "OK: config is valid")
+	return 0
+}
@@ -0,0 +1,40 @@
+// WHY: These tests prove config validate reports a config's problems
+// without standing a kernel up.
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestConfigValidate proves a loadable config passes and one that is not
+// fails with every problem listed
+func TestConfigValidate(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.json")
+	bad := filepath.Join(dir, "bad.json")
+	os.WriteFile(good, []byte(`{"ledger": "receipts.jsonl", "serve": {"addr": ":8080"}}`), 0o600)
+	os.WriteFile(bad, []byte(`{"adapters": "missing.json", "identity": {"jwks": "missing_keys.json"}}`), 0o600)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"config", "validate", "-config", good}, &stdout, &stderr); code != 0 || !strings.Contains(stdout.String(), "OK") {
+		t.Fatalf("a loadable config must validate (%d): %s", code, stderr.String())
+	}
+	if code := run([]string{"config", "validate", "-config", bad}, &stdout, &stderr); code != 1 {
+		t.Fatalf("an unloadable config must fail, got %d", code)
+	}
+	for _, want := range []string{"missing.json", "missing_keys.json"} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Fatalf("validate must list %s:\n%s", want, stderr.String())
+		}
+	}
+	if code := run([]string{"config", "validate", "-governance", "bundle.json"}, &stdout, &stderr); code != 2 {
+		t.Fatal("settings wrong in themselves must be a usage error")
+	}
+	if code := run([]string{"config"}, &stdout, &stderr); code != 2 {
+		t.Fatal("config without validate must be a usage error")
+	}
+}
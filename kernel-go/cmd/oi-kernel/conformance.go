@@ -0,0 +1,155 @@
+// WHY: The conformance suites in tools/conformance prove the kernel as
+// built; an operator also needs to know that the kernel as configured,
+// with their governance bundle and adapter manifest, still has no side
+// door, still yields to STOP, and still keeps tenants apart. This
+// subcommand runs those probes against kernels built from the same
+// settings as run and serve, but with the ledger and memory kept in
+// memory, so probing leaves no trace in the records of a real deployment.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/adapters"
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/config"
+	"github.com/user/oi/kernel-go/internal/kernel"
+	"github.com/user/oi/kernel-go/internal/memory"
+)
+
+// conformanceProbe is one conformance requirement checked against a
+// freshly built kernel
+type conformanceProbe struct {
+	id    string
+	name  string
+	check func(state *kernel.SystemState) error
+}
+
+var conformanceProbes = []conformanceProbe{
+	{"C1", "corridor bypass", probeCorridorBypass},
+	{"C7", "STOP dominance", probeStopDominance},
+	{"C9", "namespace isolation", probeNamespaceIsolation},
+}
+
+// runConformance runs every probe and reports each; it fails if any does
+func runConformance(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("oi-kernel conformance", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var cfg config.Config
+	cfg.Bind(flags, 0)
+	if err := cfg.Parse(flags, args); err != nil {
+		return 2
+	}
+	cfg.Ledger, cfg.Memory = "", ""
+
+	failed := 0
+	for _, probe := range conformanceProbes {
+		k, err := cfg.Build("conformance_principal", "conformance_namespace")
+		if err != nil {
+			fmt.Fprintf(stderr, "oi-kernel conformance: %v\n", err)
+			return config.ExitCode(err)
+		}
+		err = probe.check(k.State)
+		k.Close()
+		if err != nil {
+			failed++
+			fmt.Fprintf(stdout, "FAIL %s %s: %v\n", probe.id, probe.name, err)
+			continue
+		}
+		fmt.Fprintf(stdout, "PASS %s %s\n", probe.id, probe.name)
+	}
+	if failed > 0 {
+		fmt.Fprintf(stderr, "oi-kernel conformance: %d of %d probes failed\n", failed, len(conformanceProbes))
+		return 1
+	}
+	return 0
+}
+
+// probeCorridorBypass proves no registered adapter can be invoked without
+// a token
+func probeCorridorBypass(state *kernel.SystemState) error {
+	for _, name := range state.AdapterRegistry.ListAdapters() {
+		if _, err := state.AdapterRegistry.Invoke(name, nil, state.PostureLevel(), map[string]interface{}{}); err == nil {
+			return fmt.Errorf("adapter %s accepted a tokenless call", name)
+		}
+	}
+	return nil
+}
+
+// probeStopDominance proves a global STOP revokes every token, a revoked
+// token drives nothing, the corridor refuses before execution while the
+// STOP holds, and the STOP is ledgered
+func probeStopDominance(state *kernel.SystemState) error {
+	token, err := probeToken(state, "conformance_namespace", "conformance_principal", state.ModelAdapter)
+	if err != nil {
+		return err
+	}
+	state.RevokeAllTokens()
+
+	for digest, held := range state.ActiveCapabilityTokens {
+		if held.RevokedAt == nil {
+			return fmt.Errorf("token %s survived a global STOP", digest)
+		}
+	}
+	if _, err := state.AdapterRegistry.Invoke(state.ModelAdapter, token, state.PostureLevel(), map[string]interface{}{}); err == nil {
+		return errors.New("a token revoked by STOP still drove the model adapter")
+	}
+	resp, _ := kernel.Execute(&kernel.Request{RawInput: "conformance probe", Metadata: map[string]interface{}{}}, state)
+	if resp == nil || resp.Success || slices.Contains(resp.AuditTrail, "kernel_execute_start") {
+		return errors.New("the corridor executed a request under a global STOP")
+	}
+	page, err := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"stop_event"}})
+	if err != nil || len(page.Receipts) == 0 {
+		return errors.New("the STOP left no stop_event receipt")
+	}
+	return nil
+}
+
+// probeNamespaceIsolation proves a token minted in one namespace can
+// neither drive an adapter nor reach memory for another, and that each
+// attempt is ledgered
+func probeNamespaceIsolation(state *kernel.SystemState) error {
+	token, err := probeToken(state, "tenant_a", "alice", state.ModelAdapter, kernel.ScopeMemoryRead, kernel.ScopeMemoryWrite)
+	if err != nil {
+		return err
+	}
+	_, err = state.AdapterRegistry.InvokeInNamespace(context.Background(), "tenant_b", state.ModelAdapter, token, state.PostureLevel(), map[string]interface{}{})
+	var foreign *adapters.NamespaceError
+	if !errors.As(err, &foreign) {
+		return fmt.Errorf("a tenant_a token drove %s for tenant_b: %v", state.ModelAdapter, err)
+	}
+	if _, err := state.ReadMemoryIn("tenant_b", token, memory.PartitionDurable, "plan"); err == nil {
+		return errors.New("a tenant_a token read tenant_b memory")
+	}
+	if err := state.WriteMemoryIn("tenant_b", token, memory.PartitionDurable, "plan", "poisoned", nil); err == nil {
+		return errors.New("a tenant_a token wrote tenant_b memory")
+	}
+	page, err := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"namespace_violation"}})
+	if err != nil || len(page.Receipts) < 2 {
+		return errors.New("the refused memory access left no namespace_violation receipts")
+	}
+	return nil
+}
+
+// probeToken mints and registers a token in namespace for principal
+func probeToken(state *kernel.SystemState, namespace, principal string, scope ...string) (*capabilities.Token, error) {
+	token, err := capabilities.Mint("conformance", principal, "conformance", scope,
+		capabilities.Limits{MaxDepth: 10, MaxBudget: 100},
+		time.Minute,
+		capabilities.PostureBounds{MinPosture: 1, MaxPosture: 4},
+		namespace, principal)
+	if err != nil {
+		return nil, fmt.Errorf("mint: %w", err)
+	}
+	if err := state.AddToken(token); err != nil {
+		return nil, fmt.Errorf("register: %w", err)
+	}
+	return token, nil
+}
@@ -0,0 +1,41 @@
+// WHY: These tests prove the conformance probes pass on a kernel as
+// configured and leave no trace in its ledger.
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestConformancePassesWithoutTouchingTheLedger proves every probe passes
+// on the default kernel and on one with a manifest, and the configured
+// ledger is never written
+func TestConformancePassesWithoutTouchingTheLedger(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "adapters.json")
+	manifest := `{"model_adapter": "assistant", "adapters": [{"name": "assistant", "type": "mock"}]}`
+	if err := os.WriteFile(manifestPath, []byte(manifest), 0o600); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	ledgerPath := filepath.Join(dir, "receipts.jsonl")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"conformance", "-adapters", manifestPath, "-ledger", ledgerPath}, &stdout, &stderr); code != 0 {
+		t.Fatalf("conformance failed (%d):\n%s%s", code, stdout.String(), stderr.String())
+	}
+	for _, id := range []string{"PASS C1", "PASS C7", "PASS C9"} {
+		if !strings.Contains(stdout.String(), id) {
+			t.Fatalf("missing %s:\n%s", id, stdout.String())
+		}
+	}
+	if _, err := os.Stat(ledgerPath); !os.IsNotExist(err) {
+		t.Fatal("conformance probes must not write the configured ledger")
+	}
+
+	if code := run([]string{"conformance", "-adapters", manifestPath + ".missing"}, &stdout, &stderr); code != 2 {
+		t.Fatalf("a kernel that cannot be configured must not be probed, got %d", code)
+	}
+}
@@ -0,0 +1,102 @@
+// WHY: Auditors who do not work in a shell still have to read the
+// governance record. `audit dashboard` serves internal/dashboard over a
+// ledger file: the receipt chain with each receipt's checks, decisions,
+// token lifecycles, and posture history, filtered in a browser. Like the
+// other audit subcommands it only reads the file, and it re-reads it on
+// every view, so it can be pointed at a running kernel's ledger. It
+// listens on loopback unless told otherwise, since it asks no credential.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/dashboard"
+	"github.com/user/oi/kernel-go/internal/kernel"
+)
+
+// runAuditDashboard serves the audit dashboard until interrupted
+func runAuditDashboard(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("oi-kernel audit dashboard", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	ledgerPath := flags.String("ledger", "", "JSONL receipt file to show")
+	pubKeyPath := flags.String("pubkey", "", "PEM public key that signed the receipts")
+	keyPath := flags.String("key", "", "PEM private key that signed the receipts (its public half is used)")
+	keyID := flags.String("key-id", kernel.AuditKeyID, "key ID the receipts were signed under")
+	addr := flags.String("addr", "127.0.0.1:8083", "address to serve the dashboard on")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *ledgerPath == "" {
+		fmt.Fprintln(stderr, "oi-kernel audit dashboard: -ledger is required")
+		return 2
+	}
+	if *pubKeyPath != "" && *keyPath != "" {
+		fmt.Fprintln(stderr, "oi-kernel audit dashboard: use -pubkey or -key, not both")
+		return 2
+	}
+
+	d, err := newDashboard(*ledgerPath, *keyID, *pubKeyPath, *keyPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "oi-kernel audit dashboard: %v\n", err)
+		return 2
+	}
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		fmt.Fprintf(stderr, "oi-kernel audit dashboard: %v\n", err)
+		return 1
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	srv := &http.Server{Handler: d, ReadHeaderTimeout: 10 * time.Second}
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(listener) }()
+	fmt.Fprintf(stdout, "oi-kernel audit dashboard: serving %s on http://%s/\n", *ledgerPath, listener.Addr())
+
+	select {
+	case err := <-served:
+		fmt.Fprintf(stderr, "oi-kernel audit dashboard: %v\n", err)
+		return 1
+	case <-interrupt:
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(stderr, "oi-kernel audit dashboard: shutdown: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// newDashboard builds a dashboard over the ledger file, checking its
+// signatures against the key given, if any
+func newDashboard(ledgerPath, keyID, pubKeyPath, keyPath string) (*dashboard.Dashboard, error) {
+	public, err := loadVerifyKey(keyID, pubKeyPath, keyPath)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(ledgerPath); err != nil {
+		return nil, err
+	}
+	return dashboard.New(dashboard.Options{
+		// WHY: A line the kernel is still writing is left for the next view
+		Load: func() ([]audit.Receipt, error) {
+			receipts, _, err := readAppended(ledgerPath, 0)
+			return receipts, err
+		},
+		KeyID: keyID,
+		Key:   public,
+	})
+}
@@ -0,0 +1,53 @@
+// WHY: Proves `audit dashboard` shows the ledger file a kernel wrote,
+// verified against its key, and still shows it once it has been edited.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/user/oi/kernel-go/internal/dashboard"
+	"github.com/user/oi/kernel-go/internal/kernel"
+)
+
+// TestAuditDashboardReadsTheLedgerFile proves the dashboard verifies the
+// file against the key given and reports an edit instead of refusing it
+func TestAuditDashboardReadsTheLedgerFile(t *testing.T) {
+	ledgerPath, _, pubKeyPath := signedLedger(t)
+	d, err := newDashboard(ledgerPath, kernel.AuditKeyID, pubKeyPath, "")
+	if err != nil {
+		t.Fatalf("new dashboard: %v", err)
+	}
+	status := func() dashboard.Status {
+		rec := httptest.NewRecorder()
+		d.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/status", nil))
+		var status dashboard.Status
+		if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+			t.Fatalf("decode status: %v", err)
+		}
+		return status
+	}
+	if s := status(); !s.Verified || s.Receipts == 0 {
+		t.Fatalf("the kernel's own ledger must verify, got %+v", s)
+	}
+
+	data, _ := os.ReadFile(ledgerPath)
+	os.WriteFile(ledgerPath, bytes.Replace(data, []byte("ALLOW"), []byte("DENY"), 1), 0o600)
+	if s := status(); s.Verified || s.Broken == 0 {
+		t.Fatalf("an edited ledger must be shown failing, got %+v", s)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"audit", "dashboard"}, &stdout, &stderr); code != 2 {
+		t.Fatalf("a dashboard without -ledger must be a usage error, got %d", code)
+	}
+	missing := filepath.Join(t.TempDir(), "missing.jsonl")
+	if code := run([]string{"audit", "dashboard", "-ledger", missing}, &stdout, &stderr); code != 2 {
+		t.Fatalf("a dashboard over no ledger must be a usage error, got %d", code)
+	}
+}
@@ -0,0 +1,156 @@
+// WHY: The kernel is a library; this binary is the operator's tool around
+// it: push requests through the corridor, serve it, poke at it
+// interactively, read the governance record back out, and act on a
+// running kernel. It adds no authority of its own: every request still
+// goes through kernel.Execute, and every subcommand that stands a kernel
+// up does so from the same settings (internal/config), as flags, a
+// -config file, or both.
+//
+// Usage:
+//
+//	oi-kernel run -input "text" [kernel flags] [-identity-jwks keys.json -identity-issuer URL -identity-audience aud]
+//	oi-kernel serve -identity-jwks keys.json [kernel flags] [-addr :8080] [-grpc-addr :9090] [-admin-addr 127.0.0.1:8081 -admin-jwks keys.json] ...
+//	oi-kernel repl [kernel flags]
+//	oi-kernel batch [kernel flags] [-identity-jwks keys.json ...] < requests.jsonl > results.jsonl
+//	oi-kernel audit verify -ledger receipts.jsonl [-pubkey audit_key.pub.pem | -key audit_key.pem]
+//	oi-kernel audit export -ledger receipts.jsonl [-format jsonl|csv|cef|otlp] [-out file]
+//	oi-kernel audit tail -ledger receipts.jsonl [-n 10] [-f] [-format jsonl|cef]
+//	oi-kernel audit query -ledger receipts.jsonl [-type t1,t2] [-principal id] [-decision DENY] ...
+//	oi-kernel audit dashboard -ledger receipts.jsonl [-pubkey audit_key.pub.pem | -key audit_key.pem] [-addr 127.0.0.1:8083]
+//	oi-kernel tokens list -url https://kernel:8081
+//	oi-kernel tokens revoke -url https://kernel:8081 digest
+//	oi-kernel stop -url https://kernel/admin/stop [-scope global|principal|token] [-target id] [-reason user_panic|integrity_failure|operator_action]
+//	oi-kernel conformance [kernel flags]
+//	oi-kernel config validate [kernel, identity, admin, and server flags]
+//
+// Kernel flags: [-config oi.json] [-ledger receipts.jsonl] [-key audit_key.pem] [-memory dir] [-adapters manifest.json] [-openai-url URL -model name] [-governance bundle.json -governance-pin fingerprint] [-stop-file path]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/user/oi/kernel-go/internal/adapters"
+	"github.com/user/oi/kernel-go/internal/cli"
+	"github.com/user/oi/kernel-go/internal/config"
+	"github.com/user/oi/kernel-go/internal/kernel"
+)
+
+// command is a subcommand: it runs args and returns the process exit code
+type command func(args []string, stdout, stderr io.Writer) int
+
+// commands are the subcommands by name
+var commands = map[string]command{
+	"run": runRequest,
+	"serve": func(args []string, stdout, stderr io.Writer) int {
+		return cli.Serve("oi-kernel serve", args, stdout, stderr)
+	},
+	"repl": func(args []string, stdout, stderr io.Writer) int {
+		return runRepl(args, os.Stdin, stdout, stderr)
+	},
+	"batch": func(args []string, stdout, stderr io.Writer) int {
+		return runBatch(args, os.Stdin, stdout, stderr)
+	},
+	"audit":       runAudit,
+	"tokens":      runTokens,
+	"stop":        runStop,
+	"conformance": runConformance,
+	"config":      runConfig,
+}
+
+func main() {
+	// WHY: Isolated adapters re-execute this binary as their launcher
+	adapters.RunIsolationLauncher(os.Args[1:])
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run dispatches to a subcommand and returns the process exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+	// WHY: Before subcommands, flags alone ran one request; scripts that
+	// still do so keep working
+	if strings.HasPrefix(args[0], "-") {
+		return runRequest(args, stdout, stderr)
+	}
+	if args[0] == "help" {
+		usage(stdout)
+		return 0
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "oi-kernel: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+	return cmd(args[1:], stdout, stderr)
+}
+
+// usage lists the subcommands
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(w, "usage: oi-kernel <command> [flags]\ncommands: %s\n'oi-kernel <command> -h' lists a command's flags\n", strings.Join(names, ", "))
+}
+
+// runRequest sends a single request through the corridor
+func runRequest(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("oi-kernel run", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	input := flags.String("input", "", "request text to send through the corridor")
+	var cfg config.Config
+	cfg.Bind(flags, config.Identity)
+	if err := cfg.Parse(flags, args); err != nil {
+		return 2
+	}
+	if *input == "" {
+		fmt.Fprintln(stderr, "oi-kernel run: -input is required")
+		return 2
+	}
+
+	k, err := cfg.Build("cli_principal", "cli_namespace")
+	if err != nil {
+		fmt.Fprintf(stderr, "oi-kernel run: %v\n", err)
+		return config.ExitCode(err)
+	}
+	defer k.Close()
+	state := k.State
+
+	// WHY: STOP from the host works however far the request has got
+	disarm, err := state.ArmHostStop(cfg.StopFile, func(trigger string) {
+		fmt.Fprintf(stderr, "oi-kernel run: STOP pulled by %s\n", trigger)
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "oi-kernel run: %v\n", err)
+		return 2
+	}
+	defer disarm()
+
+	// WHY: With an identity issuer configured, the request runs as the
+	// principal its bearer token names, not the CLI's own
+	resp, err := kernel.Execute(&kernel.Request{
+		RawInput:    *input,
+		Metadata:    map[string]interface{}{},
+		BearerToken: os.Getenv("OI_BEARER_TOKEN"),
+	}, state)
+	if err != nil {
+		fmt.Fprintf(stderr, "oi-kernel run: %v\n", err)
+		return 1
+	}
+	if !resp.Success {
+		fmt.Fprintf(stderr, "oi-kernel run: %s\n", resp.Error)
+		return 1
+	}
+
+	fmt.Fprintln(stdout, resp.Content)
+	return 0
+}
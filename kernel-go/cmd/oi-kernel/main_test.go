@@ -0,0 +1,148 @@
+// WHY: These tests prove the CLI writes a persistent ledger and exports it,
+// without adding any path to capability outside the corridor.
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/kernel"
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+// TestRunThenExportLedger proves a run's receipts can be exported as CSV
+func TestRunThenExportLedger(t *testing.T) {
+	ledgerPath := filepath.Join(t.TempDir(), "receipts.jsonl")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-input", "hello", "-ledger", ledgerPath}, &stdout, &stderr); code != 0 {
+		t.Fatalf("run failed (%d): %s", code, stderr.String())
+	}
+
+	stdout.Reset()
+	if code := run([]string{"audit", "export", "-ledger", ledgerPath, "-format", "csv"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("export failed (%d): %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "cdi_decision") || !strings.Contains(stdout.String(), "adapter_attempt") {
+		t.Fatalf("export missing corridor receipts:\n%s", stdout.String())
+	}
+
+	if code := run([]string{"audit", "export", "-ledger", ledgerPath, "-format", "xml"}, &stdout, &stderr); code == 0 {
+		t.Fatal("unknown export format must fail")
+	}
+}
+
+// TestRunRoutesThroughManifestAdapter proves adapters declared in a
+// manifest are registered and the manifest's model adapter serves requests
+func TestRunRoutesThroughManifestAdapter(t *testing.T) {
+	manifestPath := filepath.Join(t.TempDir(), "adapters.json")
+	manifest := `{"model_adapter": "assistant", "adapters": [{"name": "assistant", "type": "mock"}]}`
+	if err := os.WriteFile(manifestPath, []byte(manifest), 0o600); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-input", "hello", "-adapters", manifestPath}, &stdout, &stderr); code != 0 {
+		t.Fatalf("run failed (%d): %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "mock adapter assistant invoked") {
+		t.Fatalf("request should route to the manifest adapter, got %q", stdout.String())
+	}
+
+	if code := run([]string{"-input", "hello", "-adapters", manifestPath + ".missing"}, &stdout, &stderr); code == 0 {
+		t.Fatal("missing manifest must fail")
+	}
+}
+
+// TestRunRefusesUnverifiedGovernance proves a governance bundle loads only
+// under its pinned key, and the CLI does not run without it
+func TestRunRefusesUnverifiedGovernance(t *testing.T) {
+	signer, err := signing.GenerateLocalSigner("governance")
+	if err != nil {
+		t.Fatalf("signer: %v", err)
+	}
+	capsule := kernel.DefaultGovernanceCapsule()
+	capsule.Rules = map[string]interface{}{"exists": true}
+	bundle, err := kernel.SignGovernance(capsule, signer)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	data, _ := json.Marshal(bundle)
+	bundlePath := filepath.Join(t.TempDir(), "governance.json")
+	if err := os.WriteFile(bundlePath, data, 0o600); err != nil {
+		t.Fatalf("write bundle: %v", err)
+	}
+	pin, _ := signing.Fingerprint(signer.Public())
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-input", "hello", "-governance", bundlePath, "-governance-pin", pin}, &stdout, &stderr); code != 0 {
+		t.Fatalf("run failed (%d): %s", code, stderr.String())
+	}
+	if code := run([]string{"-input", "hello", "-governance", bundlePath, "-governance-pin", "0000"}, &stdout, &stderr); code == 0 {
+		t.Fatal("a bundle not signed by the pinned key must stop the run")
+	}
+	if code := run([]string{"-input", "hello", "-governance", bundlePath}, &stdout, &stderr); code != 2 {
+		t.Fatal("a bundle without a pin must be a usage error")
+	}
+}
+
+// TestRunRequiresVerifiedIdentity proves that with an identity issuer
+// configured the CLI runs only on a bearer token the issuer signed
+func TestRunRequiresVerifiedIdentity(t *testing.T) {
+	public, key, _ := ed25519.GenerateKey(rand.Reader)
+	keys, _ := json.Marshal(map[string]interface{}{"keys": []map[string]string{
+		{"kty": "OKP", "crv": "Ed25519", "kid": "issuer_key", "x": base64.RawURLEncoding.EncodeToString(public)},
+	}})
+	jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(jwksPath, keys, 0o600); err != nil {
+		t.Fatalf("write key set: %v", err)
+	}
+	head, _ := json.Marshal(map[string]string{"alg": "EdDSA", "kid": "issuer_key"})
+	body, _ := json.Marshal(map[string]interface{}{
+		"iss": "https://issuer.example", "aud": "oi-kernel", "sub": "alice", "namespace": "tenant_a",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	signed := base64.RawURLEncoding.EncodeToString(head) + "." + base64.RawURLEncoding.EncodeToString(body)
+	bearer := signed + "." + base64.RawURLEncoding.EncodeToString(ed25519.Sign(key, []byte(signed)))
+	args := []string{"-input", "hello", "-identity-jwks", jwksPath, "-identity-issuer", "https://issuer.example", "-identity-audience", "oi-kernel"}
+
+	var stdout, stderr bytes.Buffer
+	t.Setenv("OI_BEARER_TOKEN", "")
+	if code := run(args, &stdout, &stderr); code != 1 {
+		t.Fatalf("a run without a bearer token must be refused, got %d", code)
+	}
+	t.Setenv("OI_BEARER_TOKEN", bearer)
+	if code := run(args, &stdout, &stderr); code != 0 {
+		t.Fatalf("run failed (%d): %s", code, stderr.String())
+	}
+	if code := run([]string{"-input", "hello", "-identity-issuer", "https://issuer.example"}, &stdout, &stderr); code != 2 {
+		t.Fatal("an issuer without a key set must be a usage error")
+	}
+}
+
+// TestRunDispatchesSubcommands proves run is a subcommand, flags alone
+// still run a request, and an unknown command is a usage error
+func TestRunDispatchesSubcommands(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"run", "-input", "hello"}, &stdout, &stderr); code != 0 || stdout.Len() == 0 {
+		t.Fatalf("run failed (%d): %s", code, stderr.String())
+	}
+	if code := run([]string{"bogus"}, &stdout, &stderr); code != 2 || !strings.Contains(stderr.String(), "unknown command") {
+		t.Fatalf("an unknown command must be a usage error, got %d", code)
+	}
+	if code := run(nil, &stdout, &stderr); code != 2 {
+		t.Fatal("no command must be a usage error")
+	}
+	stdout.Reset()
+	if code := run([]string{"help"}, &stdout, &stderr); code != 0 || !strings.Contains(stdout.String(), "conformance") {
+		t.Fatalf("help must list the commands:\n%s", stdout.String())
+	}
+}
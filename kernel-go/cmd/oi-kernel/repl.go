@@ -0,0 +1,363 @@
+// WHY: Governance behavior is easiest to understand by poking at it: send
+// a request, pull STOP, raise the posture, grant a consent, and watch the
+// decisions and receipts change. One-shot runs start from a fresh kernel
+// each time, so none of that carries over. The REPL keeps one kernel
+// across turns and prints each turn's receipts inline. It adds no
+// authority of its own: requests still go through kernel.Execute, and the
+// commands are the kernel's own STOP, Resume, posture, and consent calls,
+// each ledgered as it would be anywhere else.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/config"
+	"github.com/user/oi/kernel-go/internal/kernel"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// replPrincipal requests and downgrades in the REPL are made as
+const replPrincipal = "repl_principal"
+
+// replHelp lists the REPL's commands
+const replHelp = `Lines not starting with ':' are sent through the corridor. Commands:
+  :stop [global | principal <id> | token <digest>]   pull STOP
+  :resume <justification>                            lift the global STOP
+  :posture <level> [reason]                          raise, or lower with the posture_downgrade consent
+  :consent grant <scope> [ttl] | revoke <scope> | list
+  :meta [key=value ...]                              set request metadata (key= clears), or show it
+  :tokens                                            list the tokens held
+  :integrity                                         show integrity, posture, and STOPs in force
+  :verify                                            verify the audit ledger
+  :receipts [n]                                      show the last n receipts (default 10)
+  :help, :quit`
+
+// repl is a live kernel and the request metadata the user has set
+type repl struct {
+	state    *kernel.SystemState
+	metadata map[string]interface{}
+	out      io.Writer
+
+	// seen is the sequence after the last receipt printed
+	seen int64
+}
+
+// runRepl reads requests and commands from stdin until EOF or :quit
+func runRepl(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("oi-kernel repl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var cfg config.Config
+	cfg.Bind(flags, 0)
+	if err := cfg.Parse(flags, args); err != nil {
+		return 2
+	}
+	k, err := cfg.Build(replPrincipal, "repl_namespace")
+	if err != nil {
+		fmt.Fprintf(stderr, "oi-kernel repl: %v\n", err)
+		return config.ExitCode(err)
+	}
+	defer k.Close()
+	state := k.State
+	disarm, err := state.ArmHostStop(cfg.StopFile, func(trigger string) {
+		fmt.Fprintf(stdout, "\nSTOP pulled by %s; :resume lifts it\n", trigger)
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "oi-kernel repl: %v\n", err)
+		return 2
+	}
+	defer disarm()
+
+	r := &repl{state: state, metadata: map[string]interface{}{}, out: stdout}
+	r.seen = r.nextSequence()
+	fmt.Fprintln(stdout, "oi-kernel repl: one kernel for the session; :help for commands")
+	scanner := bufio.NewScanner(stdin)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for {
+		fmt.Fprint(stdout, "oi> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(stdout)
+			break
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == ":quit" || line == ":exit" {
+			break
+		}
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			if err := r.command(strings.Fields(line)); err != nil {
+				fmt.Fprintf(stdout, "error: %v\n", err)
+			}
+		} else {
+			r.execute(line)
+		}
+		r.printReceipts()
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(stderr, "oi-kernel repl: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// execute sends line through the corridor and prints what it decided
+func (r *repl) execute(line string) {
+	metadata := make(map[string]interface{}, len(r.metadata))
+	for key, value := range r.metadata {
+		metadata[key] = value
+	}
+	resp, err := kernel.Execute(&kernel.Request{
+		RawInput: line,
+		Metadata: metadata,
+	}, r.state)
+	if resp == nil {
+		fmt.Fprintf(r.out, "error: %v\n", err)
+		return
+	}
+	fmt.Fprintf(r.out, "trail: %s\n", strings.Join(resp.AuditTrail, " → "))
+	if resp.Success {
+		fmt.Fprintf(r.out, "ok: %s\n", resp.Content)
+		return
+	}
+	fmt.Fprintf(r.out, "refused: %s\n", resp.Error)
+}
+
+// command carries out one REPL command
+func (r *repl) command(fields []string) error {
+	args := fields[1:]
+	switch fields[0] {
+	case ":help":
+		fmt.Fprintln(r.out, replHelp)
+	case ":stop":
+		return r.stop(args)
+	case ":resume":
+		if len(args) == 0 {
+			return fmt.Errorf("usage: :resume <justification>")
+		}
+		if err := r.state.Resume(kernel.ResumeRequest{Scope: audit.StopScopeGlobal, Justification: strings.Join(args, " ")}); err != nil {
+			return err
+		}
+		fmt.Fprintln(r.out, "resumed")
+	case ":posture":
+		return r.posture(args)
+	case ":consent":
+		return r.consent(args)
+	case ":meta":
+		return r.meta(args)
+	case ":tokens":
+		r.tokens()
+	case ":integrity":
+		r.integrity()
+	case ":verify":
+		if err := r.state.VerifyAndEnforce(); err != nil {
+			return fmt.Errorf("ledger does not verify: %w", err)
+		}
+		fmt.Fprintln(r.out, "ledger verified")
+	case ":receipts":
+		n := 10
+		if len(args) > 0 {
+			parsed, err := strconv.Atoi(args[0])
+			if err != nil || parsed <= 0 {
+				return fmt.Errorf("usage: :receipts [n]")
+			}
+			n = parsed
+		}
+		receipts := r.state.AuditLedger.GetReceipts()
+		for _, receipt := range receipts[max(len(receipts)-n, 0):] {
+			fmt.Fprintln(r.out, formatReceipt(receipt))
+		}
+	default:
+		return fmt.Errorf("unknown command %s (:help lists them)", fields[0])
+	}
+	return nil
+}
+
+// stop pulls the STOP args name
+func (r *repl) stop(args []string) error {
+	scope := audit.StopScopeGlobal
+	if len(args) > 0 {
+		scope = args[0]
+	}
+	switch {
+	case scope == audit.StopScopeGlobal && len(args) <= 1:
+		r.state.RevokeAllTokens()
+		fmt.Fprintln(r.out, "global STOP pulled; :resume lifts it")
+	case scope == audit.StopScopePrincipal && len(args) == 2:
+		fmt.Fprintf(r.out, "STOP on %s revoked %d tokens\n", args[1], r.state.RevokeTokensFor(args[1]))
+	case scope == audit.StopScopeToken && len(args) == 2:
+		if !r.state.RevokeToken(args[1]) {
+			return fmt.Errorf("no active token %s", args[1])
+		}
+		fmt.Fprintf(r.out, "token %s revoked\n", args[1])
+	default:
+		return fmt.Errorf("usage: :stop [global | principal <id> | token <digest>]")
+	}
+	return nil
+}
+
+// posture moves the kernel's posture as args ask
+func (r *repl) posture(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: :posture <level> [reason]")
+	}
+	level, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(args[0]), "P"))
+	if err != nil || !posture.IsValid(level) {
+		return fmt.Errorf("posture %q is not defined", args[0])
+	}
+	reason := "repl"
+	if len(args) > 1 {
+		reason = strings.Join(args[1:], " ")
+	}
+	current := r.state.Posture.Level()
+	switch {
+	case level > current:
+		r.state.Posture.Raise(level, reason)
+	case level < current:
+		if err := r.state.LowerPosture("", posture.Downgrade{Level: level, Reason: reason, RequestedBy: replPrincipal}); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(r.out, "posture %s\n", posture.Name(r.state.Posture.Level()))
+	return nil
+}
+
+// consent grants, revokes, or lists consents
+func (r *repl) consent(args []string) error {
+	switch {
+	case len(args) >= 2 && len(args) <= 3 && args[0] == "grant":
+		var ttl time.Duration
+		if len(args) == 3 {
+			parsed, err := time.ParseDuration(args[2])
+			if err != nil {
+				return err
+			}
+			ttl = parsed
+		}
+		if err := r.state.GrantConsent(args[1], ttl, "repl"); err != nil {
+			return err
+		}
+		fmt.Fprintf(r.out, "consent %s granted\n", args[1])
+	case len(args) == 2 && args[0] == "revoke":
+		if err := r.state.RevokeConsent(args[1]); err != nil {
+			return err
+		}
+		fmt.Fprintf(r.out, "consent %s revoked\n", args[1])
+	case len(args) == 1 && args[0] == "list":
+		for _, consent := range r.state.ListConsents() {
+			expires := "until revoked"
+			if consent.ExpiresAt != 0 {
+				expires = "until " + time.Unix(consent.ExpiresAt, 0).UTC().Format(time.RFC3339)
+			}
+			fmt.Fprintf(r.out, "%s %s\n", consent.Scope, expires)
+		}
+	default:
+		return fmt.Errorf("usage: :consent grant <scope> [ttl] | revoke <scope> | list")
+	}
+	return nil
+}
+
+// meta sets request metadata, or shows it with no arguments
+func (r *repl) meta(args []string) error {
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("usage: :meta [key=value ...]")
+		}
+		if value == "" {
+			delete(r.metadata, key)
+			continue
+		}
+		r.metadata[key] = value
+	}
+	keys := make([]string, 0, len(r.metadata))
+	for key := range r.metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(r.out, "%s=%v\n", key, r.metadata[key])
+	}
+	return nil
+}
+
+// tokens lists the tokens the kernel holds
+func (r *repl) tokens() {
+	digests := make([]string, 0, len(r.state.ActiveCapabilityTokens))
+	for digest := range r.state.ActiveCapabilityTokens {
+		digests = append(digests, digest)
+	}
+	sort.Strings(digests)
+	for _, digest := range digests {
+		token := r.state.ActiveCapabilityTokens[digest]
+		status := "active"
+		if token.RevokedAt != nil {
+			status = "revoked"
+		}
+		fmt.Fprintf(r.out, "%s %s %s %s\n", digest, token.PrincipalID, strings.Join(token.Scope, ","), status)
+	}
+}
+
+// integrity shows what the kernel's integrity rests on
+func (r *repl) integrity() {
+	fmt.Fprintf(r.out, "integrity %s, posture %s\n", r.state.GetIntegrityState(), posture.Name(r.state.PostureLevel()))
+	for _, halt := range r.state.Halts() {
+		fmt.Fprintf(r.out, "STOP in force: %s %s (%s)\n", halt.Scope, halt.Target, halt.Reason)
+	}
+}
+
+// nextSequence is the sequence the next receipt will take
+func (r *repl) nextSequence() int64 {
+	receipts := r.state.AuditLedger.GetReceipts()
+	if len(receipts) == 0 {
+		return 0
+	}
+	return receipts[len(receipts)-1].Sequence + 1
+}
+
+// printReceipts prints the receipts written since the last call
+func (r *repl) printReceipts() {
+	filter := audit.ReceiptFilter{FromSequence: r.seen, Limit: audit.MaxQueryLimit}
+	for {
+		page, err := r.state.AuditLedger.Query(filter)
+		if err != nil {
+			return
+		}
+		for _, receipt := range page.Receipts {
+			fmt.Fprintln(r.out, "  "+formatReceipt(receipt))
+			r.seen = receipt.Sequence + 1
+		}
+		if !page.HasMore {
+			return
+		}
+		filter.FromSequence = page.NextSequence
+	}
+}
+
+// formatReceipt renders a receipt on one line: its sequence, type,
+// severity, and event data but for the attribution every receipt carries
+func formatReceipt(receipt audit.Receipt) string {
+	keys := make([]string, 0, len(receipt.EventData))
+	for key := range receipt.EventData {
+		switch key {
+		case "principal_id", "namespace_id", "request_id":
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	fmt.Fprintf(&b, "#%d %s [%s]", receipt.Sequence, receipt.EventType, receipt.Severity)
+	for _, key := range keys {
+		fmt.Fprintf(&b, " %s=%v", key, receipt.EventData[key])
+	}
+	return b.String()
+}
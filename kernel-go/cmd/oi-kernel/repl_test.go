@@ -0,0 +1,111 @@
+// WHY: These tests prove the REPL keeps one kernel across turns, so STOP,
+// consent, and metadata set in one turn govern the next, and that each
+// turn's receipts are printed as they are written.
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// replSession runs the REPL over lines and returns what it printed
+func replSession(t *testing.T, lines ...string) string {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	if code := runRepl(nil, strings.NewReader(strings.Join(lines, "\n")+"\n"), &stdout, &stderr); code != 0 {
+		t.Fatalf("repl failed (%d): %s", code, stderr.String())
+	}
+	return stdout.String()
+}
+
+// TestReplConsentGovernsLaterTurns proves consent and metadata set in one
+// turn govern the requests of later turns
+func TestReplConsentGovernsLaterTurns(t *testing.T) {
+	out := replSession(t,
+		":meta sensitivity=high",
+		"first",
+		":consent grant high_risk_operations",
+		"second",
+		":meta sensitivity=",
+		"third",
+		":consent list",
+	)
+	turns := strings.Split(out, "oi> ")
+	if len(turns) < 8 {
+		t.Fatalf("expected a prompt per line, got:\n%s", out)
+	}
+	if !strings.Contains(turns[2], "refused: request denied: high_risk_requires_consent") || !strings.Contains(turns[2], "cdi_decision [") {
+		t.Fatalf("high-sensitivity request without consent should be refused with its decision receipt:\n%s", turns[2])
+	}
+	if !strings.Contains(turns[3], "consent high_risk_operations granted") {
+		t.Fatalf("grant should be confirmed:\n%s", turns[3])
+	}
+	if !strings.Contains(turns[4], "refused:") || strings.Contains(turns[4], "high_risk_requires_consent") {
+		t.Fatalf("the granted consent should no longer be the refusal:\n%s", turns[4])
+	}
+	if !strings.Contains(turns[6], "ok: ") || !strings.Contains(turns[6], "cdi_decision [info]") {
+		t.Fatalf("request should succeed once the metadata is cleared:\n%s", turns[6])
+	}
+	if !strings.Contains(turns[7], "high_risk_operations until revoked") {
+		t.Fatalf("consent list should show the grant:\n%s", turns[7])
+	}
+}
+
+// TestReplStopAndResume proves STOP pulled in the REPL halts later
+// requests until it is resumed
+func TestReplStopAndResume(t *testing.T) {
+	out := replSession(t,
+		"before",
+		":stop",
+		"during",
+		":integrity",
+		":resume demo finished",
+		"after",
+		":quit",
+		"never sent",
+	)
+	turns := strings.Split(out, "oi> ")
+	if !strings.Contains(turns[1], "ok: ") {
+		t.Fatalf("request before STOP should succeed:\n%s", turns[1])
+	}
+	if !strings.Contains(turns[2], "global STOP pulled") || !strings.Contains(turns[2], "stop_event [") {
+		t.Fatalf("STOP should be confirmed with its receipt:\n%s", turns[2])
+	}
+	if !strings.Contains(turns[3], "refused:") {
+		t.Fatalf("request during STOP should be refused:\n%s", turns[3])
+	}
+	if !strings.Contains(turns[4], "STOP in force: global") {
+		t.Fatalf("integrity should show the STOP in force:\n%s", turns[4])
+	}
+	if !strings.Contains(turns[6], "ok: ") {
+		t.Fatalf("request after resume should succeed:\n%s", turns[6])
+	}
+	if strings.Contains(out, "never sent") {
+		t.Fatalf(":quit should end the session:\n%s", out)
+	}
+}
+
+// TestReplPostureAndErrors proves posture commands move the kernel and
+// malformed commands are reported without ending the session
+func TestReplPostureAndErrors(t *testing.T) {
+	out := replSession(t,
+		":posture 3 incident",
+		":posture 9",
+		":bogus",
+		":meta novalue",
+		":verify",
+	)
+	turns := strings.Split(out, "oi> ")
+	if !strings.Contains(turns[1], "posture ") || !strings.Contains(turns[1], "posture_change [") {
+		t.Fatalf("raising posture should be confirmed with its receipt:\n%s", turns[1])
+	}
+	for i := 2; i <= 4; i++ {
+		if !strings.Contains(turns[i], "error: ") {
+			t.Fatalf("turn %d should report an error:\n%s", i, turns[i])
+		}
+	}
+	if !strings.Contains(turns[5], "ledger verified") {
+		t.Fatalf("verify should pass on an untampered ledger:\n%s", turns[5])
+	}
+}
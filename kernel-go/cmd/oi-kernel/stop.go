@@ -0,0 +1,83 @@
+// WHY: An operator or a user must be able to pull STOP on a running kernel
+// from a shell. This subcommand only posts to the kernel's STOP admin
+// endpoint with the caller's bearer token; the kernel authenticates it,
+// decides what it may revoke, and ledgers the result.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/kernel"
+)
+
+// stopTimeout bounds how long the CLI waits on the admin endpoint
+const stopTimeout = 30 * time.Second
+
+// runStop posts a STOP to a kernel's admin endpoint and prints its summary
+func runStop(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("oi-kernel stop", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	endpoint := flags.String("url", "", "URL of the kernel's STOP admin endpoint (bearer token from OI_BEARER_TOKEN)")
+	scope := flags.String("scope", "global", "what to STOP: global, principal, or token")
+	target := flags.String("target", "", "principal to STOP (default: the caller) or token digest to STOP")
+	reason := flags.String("reason", "operator_action", "why: user_panic, integrity_failure, or operator_action")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *endpoint == "" {
+		fmt.Fprintln(stderr, "oi-kernel stop: -url is required")
+		return 2
+	}
+	bearer := os.Getenv("OI_BEARER_TOKEN")
+	if bearer == "" {
+		fmt.Fprintln(stderr, "oi-kernel stop: OI_BEARER_TOKEN is required")
+		return 2
+	}
+
+	body, _ := json.Marshal(kernel.StopRequest{Scope: *scope, Target: *target, Reason: *reason})
+	req, err := http.NewRequest(http.MethodPost, *endpoint, bytes.NewReader(body))
+	if err != nil {
+		fmt.Fprintf(stderr, "oi-kernel stop: %v\n", err)
+		return 2
+	}
+	req.Header.Set("Authorization", "Bearer "+bearer)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: stopTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		fmt.Fprintf(stderr, "oi-kernel stop: %v\n", err)
+		return 1
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var refusal struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&refusal)
+		fmt.Fprintf(stderr, "oi-kernel stop: %s: %s\n", resp.Status, refusal.Error)
+		return 1
+	}
+	var summary kernel.StopSummary
+	if err := json.NewDecoder(resp.Body).Decode(&summary); err != nil {
+		fmt.Fprintf(stderr, "oi-kernel stop: reading summary: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "STOP %s", summary.Scope)
+	if summary.Target != "" {
+		fmt.Fprintf(stdout, " %s", summary.Target)
+	}
+	fmt.Fprintf(stdout, " (%s): %d tokens revoked, %d calls cancelled\n", summary.Reason, summary.TokensRevoked, summary.CallsCancelled)
+	for _, digest := range summary.TokenDigests {
+		fmt.Fprintf(stdout, "  %s\n", digest)
+	}
+	return 0
+}
@@ -0,0 +1,68 @@
+// WHY: These tests prove the stop subcommand pulls STOP on a running
+// kernel only through its authenticated admin endpoint.
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/identity"
+	"github.com/user/oi/kernel-go/internal/kernel"
+)
+
+// TestStopRevokesThroughAdminEndpoint proves the CLI's STOP reaches the
+// kernel with the caller's identity and prints what it revoked
+func TestStopRevokesThroughAdminEndpoint(t *testing.T) {
+	public, key, _ := ed25519.GenerateKey(rand.Reader)
+	state := kernel.NewSystemState("test_principal", "test_namespace")
+	if err := state.SetIdentityVerifier(&identity.Verifier{
+		Issuer:   "https://issuer.example",
+		Audience: "oi-kernel",
+		Keys:     identity.KeySet{"issuer_key": public},
+	}); err != nil {
+		t.Fatalf("set verifier: %v", err)
+	}
+	token, _ := capabilities.Mint("issuer", "subject", "audience", []string{"scope1"},
+		capabilities.Limits{}, 5*time.Minute, capabilities.PostureBounds{MinPosture: 1, MaxPosture: 4},
+		"tenant_a", "alice")
+	state.AddToken(token)
+	server := httptest.NewServer(state.StopHandler())
+	defer server.Close()
+
+	head, _ := json.Marshal(map[string]string{"alg": "EdDSA", "kid": "issuer_key"})
+	body, _ := json.Marshal(map[string]interface{}{
+		"iss": "https://issuer.example", "aud": "oi-kernel", "sub": "alice", "namespace": "tenant_a",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	signed := base64.RawURLEncoding.EncodeToString(head) + "." + base64.RawURLEncoding.EncodeToString(body)
+	bearer := signed + "." + base64.RawURLEncoding.EncodeToString(ed25519.Sign(key, []byte(signed)))
+
+	var stdout, stderr bytes.Buffer
+	t.Setenv("OI_BEARER_TOKEN", "")
+	if code := run([]string{"stop", "-url", server.URL}, &stdout, &stderr); code != 2 {
+		t.Fatalf("a STOP without a bearer token must be a usage error, got %d", code)
+	}
+	t.Setenv("OI_BEARER_TOKEN", bearer)
+	if code := run([]string{"stop", "-url", server.URL, "-scope", "principal", "-target", "bob"}, &stdout, &stderr); code != 1 {
+		t.Fatalf("a STOP on another principal must be refused, got %d", code)
+	}
+	if token.RevokedAt != nil {
+		t.Fatal("a refused STOP must revoke nothing")
+	}
+
+	stdout.Reset()
+	if code := run([]string{"stop", "-url", server.URL, "-scope", "principal"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("stop failed (%d): %s", code, stderr.String())
+	}
+	if token.RevokedAt == nil || !strings.Contains(stdout.String(), "1 tokens revoked") || !strings.Contains(stdout.String(), token.Digest) {
+		t.Fatalf("STOP must revoke alice's token and say so, got %q", stdout.String())
+	}
+}
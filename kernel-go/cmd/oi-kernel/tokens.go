@@ -0,0 +1,134 @@
+// WHY: An operator chasing a leaked or misbehaving capability needs to see
+// the tokens a running kernel holds and revoke one, from a shell. These
+// subcommands only call the kernel's admin surface with the operator's
+// admin token; the kernel checks the token's role, does the work, and
+// ledgers it as an admin action.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/kernel"
+)
+
+// runTokens dispatches tokens subcommands
+func runTokens(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "usage: oi-kernel tokens list|revoke -url https://kernel:8081 [digest]")
+		return 2
+	}
+	switch args[0] {
+	case "list":
+		return runTokensList(args[1:], stdout, stderr)
+	case "revoke":
+		return runTokensRevoke(args[1:], stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "oi-kernel tokens: unknown subcommand %q\n", args[0])
+		return 2
+	}
+}
+
+// runTokensList prints the tokens a running kernel holds
+func runTokensList(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("oi-kernel tokens list", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	endpoint := flags.String("url", "", "URL of the kernel's admin surface (admin token from OI_ADMIN_TOKEN)")
+	asJSON := flags.Bool("json", false, "print the tokens as JSON")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	var tokens []kernel.TokenInfo
+	if code := adminCall(flags.Name(), http.MethodGet, *endpoint, "/admin/tokens", &tokens, stderr); code != 0 {
+		return code
+	}
+	if *asJSON {
+		out := json.NewEncoder(stdout)
+		out.SetIndent("", "  ")
+		out.Encode(tokens)
+		return 0
+	}
+	for _, token := range tokens {
+		status := "active"
+		switch {
+		case token.RevokedAt != nil:
+			status = "revoked " + token.RevokedAt.UTC().Format(time.RFC3339)
+		case !token.Active:
+			status = "expired"
+		}
+		fmt.Fprintf(stdout, "%s %s/%s %s expires %s %s\n", token.Digest, token.NamespaceID, token.PrincipalID,
+			strings.Join(token.Scope, ","), token.ExpiresAt.UTC().Format(time.RFC3339), status)
+	}
+	return 0
+}
+
+// runTokensRevoke revokes one token on a running kernel
+func runTokensRevoke(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("oi-kernel tokens revoke", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	endpoint := flags.String("url", "", "URL of the kernel's admin surface (admin token from OI_ADMIN_TOKEN)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintln(stderr, "oi-kernel tokens revoke: give exactly one token digest")
+		return 2
+	}
+	digest := flags.Arg(0)
+	if code := adminCall(flags.Name(), http.MethodPost, *endpoint, "/admin/tokens/"+url.PathEscape(digest)+"/revoke", nil, stderr); code != 0 {
+		return code
+	}
+	fmt.Fprintf(stdout, "token %s revoked\n", digest)
+	return 0
+}
+
+// adminCall calls path on the admin surface at endpoint with the admin
+// token in OI_ADMIN_TOKEN, decoding the answer into out if it is not nil,
+// and returns the process exit code
+func adminCall(name, method, endpoint, path string, out interface{}, stderr io.Writer) int {
+	if endpoint == "" {
+		fmt.Fprintf(stderr, "%s: -url is required\n", name)
+		return 2
+	}
+	bearer := os.Getenv("OI_ADMIN_TOKEN")
+	if bearer == "" {
+		fmt.Fprintf(stderr, "%s: OI_ADMIN_TOKEN is required\n", name)
+		return 2
+	}
+	req, err := http.NewRequest(method, strings.TrimSuffix(endpoint, "/")+path, nil)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", name, err)
+		return 2
+	}
+	req.Header.Set("Authorization", "Bearer "+bearer)
+
+	client := &http.Client{Timeout: stopTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", name, err)
+		return 1
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var refusal struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&refusal)
+		fmt.Fprintf(stderr, "%s: %s: %s\n", name, resp.Status, refusal.Error)
+		return 1
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			fmt.Fprintf(stderr, "%s: reading answer: %v\n", name, err)
+			return 1
+		}
+	}
+	return 0
+}
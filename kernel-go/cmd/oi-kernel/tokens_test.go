@@ -0,0 +1,81 @@
+// WHY: These tests prove the tokens subcommands act on a running kernel
+// only through its admin surface, as the admin token's role allows.
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/identity"
+	"github.com/user/oi/kernel-go/internal/kernel"
+)
+
+// TestTokensListAndRevoke proves an operator lists and revokes tokens
+// through the admin surface, and an auditor may list but not revoke
+func TestTokensListAndRevoke(t *testing.T) {
+	public, key, _ := ed25519.GenerateKey(rand.Reader)
+	state := kernel.NewSystemState("test_principal", "test_namespace")
+	if err := state.SetAdminVerifier(&identity.Verifier{
+		Issuer:   "https://admin.example",
+		Audience: "oi-admin",
+		Keys:     identity.KeySet{"admin_key": public},
+	}); err != nil {
+		t.Fatalf("set admin verifier: %v", err)
+	}
+	token, _ := capabilities.Mint("issuer", "subject", "audience", []string{"scope1"},
+		capabilities.Limits{}, 5*time.Minute, capabilities.PostureBounds{MinPosture: 1, MaxPosture: 4},
+		"tenant_a", "alice")
+	state.AddToken(token)
+	server := httptest.NewServer(state.AdminHandler())
+	defer server.Close()
+
+	adminToken := func(role string) string {
+		head, _ := json.Marshal(map[string]string{"alg": "EdDSA", "kid": "admin_key"})
+		body, _ := json.Marshal(map[string]interface{}{
+			"iss": "https://admin.example", "aud": "oi-admin", "sub": "ops", "namespace": "ops", kernel.AdminRoleClaim: role,
+			"exp": time.Now().Add(time.Hour).Unix(),
+		})
+		signed := base64.RawURLEncoding.EncodeToString(head) + "." + base64.RawURLEncoding.EncodeToString(body)
+		return signed + "." + base64.RawURLEncoding.EncodeToString(ed25519.Sign(key, []byte(signed)))
+	}
+
+	var stdout, stderr bytes.Buffer
+	t.Setenv("OI_ADMIN_TOKEN", "")
+	if code := run([]string{"tokens", "list", "-url", server.URL}, &stdout, &stderr); code != 2 {
+		t.Fatalf("listing without an admin token must be a usage error, got %d", code)
+	}
+
+	t.Setenv("OI_ADMIN_TOKEN", adminToken("auditor"))
+	if code := run([]string{"tokens", "list", "-url", server.URL}, &stdout, &stderr); code != 0 {
+		t.Fatalf("list failed (%d): %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), token.Digest) || !strings.Contains(stdout.String(), "tenant_a/alice") {
+		t.Fatalf("list should show the token held:\n%s", stdout.String())
+	}
+	if code := run([]string{"tokens", "revoke", "-url", server.URL, token.Digest}, &stdout, &stderr); code != 1 {
+		t.Fatalf("an auditor must not revoke, got %d", code)
+	}
+	if token.RevokedAt != nil {
+		t.Fatal("a refused revocation must revoke nothing")
+	}
+
+	t.Setenv("OI_ADMIN_TOKEN", adminToken("operator"))
+	stdout.Reset()
+	if code := run([]string{"tokens", "revoke", "-url", server.URL, token.Digest}, &stdout, &stderr); code != 0 {
+		t.Fatalf("revoke failed (%d): %s", code, stderr.String())
+	}
+	if token.RevokedAt == nil || !strings.Contains(stdout.String(), "revoked") {
+		t.Fatalf("an operator's revocation must revoke the token: %s", stdout.String())
+	}
+	if code := run([]string{"tokens", "revoke", "-url", server.URL}, &stdout, &stderr); code != 2 {
+		t.Fatal("a revocation without a digest must be a usage error")
+	}
+}
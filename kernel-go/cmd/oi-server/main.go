@@ -0,0 +1,30 @@
+// WHY: Other processes need the corridor without linking the kernel. This
+// binary serves it over HTTP, and over gRPC with -grpc-addr (see
+// internal/server and proto/oi/kernel/v1), and adds no authority
+// of its own: it refuses to start without an identity issuer, so every
+// request runs as the principal its bearer token names. It is
+// `oi-kernel serve` under its own name; both run internal/cli.Serve.
+//
+// Usage:
+//
+//	oi-server -identity-jwks keys.json -identity-issuer URL -identity-audience aud [-config oi.json] [-addr :8080] [-ledger receipts.jsonl] [-key audit_key.pem] [-memory dir] [-adapters manifest.json] [-governance bundle.json -governance-pin fingerprint] [-grpc-addr :9090] [-admin-addr 127.0.0.1:8081 -admin-jwks keys.json -admin-issuer URL -admin-audience aud] [-max-body bytes] [-stop-file path] [-quiesce 10s]
+package main
+
+import (
+	"io"
+	"os"
+
+	"github.com/user/oi/kernel-go/internal/adapters"
+	"github.com/user/oi/kernel-go/internal/cli"
+)
+
+func main() {
+	// WHY: Isolated adapters re-execute this binary as their launcher
+	adapters.RunIsolationLauncher(os.Args[1:])
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run serves a kernel until shut down and returns the process exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	return cli.Serve("oi-server", args, stdout, stderr)
+}
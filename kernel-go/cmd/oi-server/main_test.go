@@ -0,0 +1,35 @@
+// WHY: Proves the server refuses to start in any configuration where a
+// request could run without a verified principal.
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestServerRequiresAnIdentityIssuer proves the server will not start
+// without a verifiable identity issuer
+func TestServerRequiresAnIdentityIssuer(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-addr", "127.0.0.1:0"}, &stdout, &stderr); code != 2 || !strings.Contains(stderr.String(), "-identity-jwks is required") {
+		t.Fatalf("a server without an identity issuer must not start, got %d: %s", code, stderr.String())
+	}
+
+	stderr.Reset()
+	missing := filepath.Join(t.TempDir(), "keys.json")
+	if code := run([]string{"-addr", "127.0.0.1:0", "-identity-jwks", missing}, &stdout, &stderr); code != 2 {
+		t.Fatalf("a server whose issuer keys cannot be loaded must not start, got %d: %s", code, stderr.String())
+	}
+
+	stderr.Reset()
+	if code := run([]string{"-identity-jwks", missing, "-governance", "bundle.json"}, &stdout, &stderr); code != 2 || !strings.Contains(stderr.String(), "go together") {
+		t.Fatalf("-governance without -governance-pin must be refused, got %d: %s", code, stderr.String())
+	}
+
+	stderr.Reset()
+	if code := run([]string{"-identity-jwks", missing, "-admin-addr", "127.0.0.1:0"}, &stdout, &stderr); code != 2 || !strings.Contains(stderr.String(), "-admin-jwks go together") {
+		t.Fatalf("-admin-addr without an admin issuer must be refused, got %d: %s", code, stderr.String())
+	}
+}
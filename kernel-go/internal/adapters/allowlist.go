@@ -0,0 +1,45 @@
+// WHY: A token's scope says what one grant may reach, but tokens are
+// minted before the posture can move and a broad template can outlive the
+// calm it was written for. The posture allowlist is checked on every call
+// against the posture the call runs at, so raising the posture takes
+// write-capable and network adapters out of reach at once, whatever the
+// tokens in flight allow.
+package adapters
+
+import (
+	"fmt"
+
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// PostureAllowlist returns the adapters reachable at a posture level
+type PostureAllowlist func(level int) (posture.AdapterAllowlist, error)
+
+// SetPostureAllowlist makes every call check fn's allowlist for its
+// posture, on top of its token; nil removes the check
+func (r *Registry) SetPostureAllowlist(fn PostureAllowlist) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.allowlist = fn
+}
+
+// checkAllowlist refuses a call to adapter at currentPosture the posture
+// allowlist leaves out. WHY: Fail-closed - an allowlist that cannot be
+// read refuses the call.
+func (r *Registry) checkAllowlist(adapter Adapter, currentPosture int) error {
+	r.mu.RLock()
+	fn := r.allowlist
+	r.mu.RUnlock()
+	if fn == nil {
+		return nil
+	}
+	allowlist, err := fn(currentPosture)
+	if err != nil {
+		return fmt.Errorf("posture allowlist unavailable at P%d: %w", currentPosture, err)
+	}
+	declaration := adapter.Declare()
+	if err := allowlist.Permits(declaration.Name, declaration.Risk, declaration.SideEffects); err != nil {
+		return fmt.Errorf("not reachable at P%d: %w", currentPosture, err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+// WHY: These tests prove the posture allowlist is enforced on every call
+// at the call's posture, whatever the token allows, and an allowlist that
+// cannot be read refuses the call.
+package adapters
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/user/oi/kernel-go/internal/cdi"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// TestPostureAllowlistTakesAdaptersOutOfReach proves raising the posture
+// refuses a write-capable network adapter the token still covers, while a
+// side-effect-free one stays in reach
+func TestPostureAllowlistTakesAdaptersOutOfReach(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(NewMockAdapter("reader"))
+	registry.Register(&misdeclaredAdapter{MockAdapter: NewMockAdapter("mailer"), declaration: cdi.CapabilityDeclaration{
+		Name: "mailer", Risk: cdi.RiskHigh, SideEffects: []string{cdi.SideEffectNetwork, cdi.SideEffectWrite},
+	}})
+	policy := posture.DefaultPolicy()
+	registry.SetPostureAllowlist(func(level int) (posture.AdapterAllowlist, error) {
+		return policy.Level(level).Adapters, nil
+	})
+
+	if _, err := registry.Invoke("mailer", mintLimitToken(t, "mailer"), posture.P3, map[string]interface{}{}); err != nil {
+		t.Fatalf("a network writer must be in reach at P3: %v", err)
+	}
+	if _, err := registry.Invoke("mailer", mintLimitToken(t, "mailer"), posture.P4, map[string]interface{}{}); err == nil {
+		t.Fatal("a network writer must be out of reach at P4")
+	}
+	if _, err := registry.Invoke("reader", mintLimitToken(t, "reader"), posture.P4, map[string]interface{}{}); err != nil {
+		t.Fatalf("a low-risk adapter without side effects must stay in reach at P4: %v", err)
+	}
+
+	registry.SetPostureAllowlist(func(level int) (posture.AdapterAllowlist, error) {
+		return posture.AdapterAllowlist{}, errors.New("no posture policy defined")
+	})
+	if _, err := registry.Invoke("reader", mintLimitToken(t, "reader"), posture.P1, map[string]interface{}{}); err == nil {
+		t.Fatal("an unreadable allowlist must refuse the call")
+	}
+}
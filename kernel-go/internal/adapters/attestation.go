@@ -0,0 +1,194 @@
+// WHY: A plugin is a separate binary talking over a pipe, so neither side
+// can take the other on trust. The registry refuses to start a plugin
+// whose executable does not hash to the build pinned in governance, and
+// checks the running plugin reports the same build. The plugin, in turn,
+// refuses tokens until the kernel presents a signed attestation naming its
+// registry identity and policy version, so a plugin binary copied out of
+// the deployment cannot be driven by anything else that speaks the
+// protocol.
+package adapters
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+// DefaultAttestationMaxAge bounds how old a kernel attestation a plugin accepts
+const DefaultAttestationMaxAge = time.Minute
+
+// attestationDomain separates attestation signatures from every other
+// signature the kernel key makes
+const attestationDomain = "oi-kernel-attestation-v1"
+
+// KernelAttestation is the kernel's signed statement to one plugin process
+type KernelAttestation struct {
+	RegistryID    string
+	PolicyVersion string
+
+	// Adapter and BuildHash name the plugin and the build the registry
+	// verified, so the statement cannot be replayed to another plugin
+	Adapter   string
+	BuildHash string
+
+	// Nonce is fresh per plugin process
+	Nonce    string
+	IssuedAt time.Time
+
+	KeyID     string
+	Signature []byte
+}
+
+// signingMessage is the canonical encoding the signature covers
+func (k *KernelAttestation) signingMessage() []byte {
+	message, _ := json.Marshal([]string{
+		attestationDomain, k.RegistryID, k.PolicyVersion, k.Adapter, k.BuildHash,
+		k.Nonce, k.IssuedAt.UTC().Format(time.RFC3339Nano), k.KeyID,
+	})
+	return message
+}
+
+// Attestor is the registry side of mutual attestation
+type Attestor struct {
+	// RegistryID identifies this kernel's registry to plugins
+	RegistryID string
+
+	// Signer signs kernel attestations; nil offers none, so plugins that
+	// require one refuse every call
+	Signer signing.Signer
+
+	// PolicyVersion reports the governance policy version
+	PolicyVersion func() string
+
+	// BuildHashes reports the pinned build hash of each plugin by adapter
+	// name. WHY: A plugin with no pin is an unknown binary and never starts.
+	BuildHashes func() map[string]string
+}
+
+// HashBuild returns the build hash of an executable: the hex SHA-256 of
+// its contents, as pinned in governance
+func HashBuild(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// checkBuild hashes the plugin executable and checks it against the pin
+func (a *Attestor) checkBuild(adapter string, path string) (string, error) {
+	var pinned string
+	if a.BuildHashes != nil {
+		pinned = a.BuildHashes()[adapter]
+	}
+	if pinned == "" {
+		return "", fmt.Errorf("no build pinned for %s; unknown binaries are refused", adapter)
+	}
+	hash, err := HashBuild(path)
+	if err != nil {
+		return "", fmt.Errorf("hash build: %w", err)
+	}
+	if hash != pinned {
+		return "", fmt.Errorf("build %s does not match the pinned build", hash)
+	}
+	return hash, nil
+}
+
+// attest issues a signed attestation for one plugin process; it returns
+// nil when the attestor has no signer
+func (a *Attestor) attest(adapter string, buildHash string, nonce string) (*KernelAttestation, error) {
+	if a.Signer == nil {
+		return nil, nil
+	}
+	attestation := &KernelAttestation{
+		RegistryID: a.RegistryID,
+		Adapter:    adapter,
+		BuildHash:  buildHash,
+		Nonce:      nonce,
+		IssuedAt:   time.Now().UTC(),
+		KeyID:      a.Signer.KeyID(),
+	}
+	if a.PolicyVersion != nil {
+		attestation.PolicyVersion = a.PolicyVersion()
+	}
+	signature, err := a.Signer.Sign(attestation.signingMessage())
+	if err != nil {
+		return nil, fmt.Errorf("sign attestation: %w", err)
+	}
+	attestation.Signature = signature
+	return attestation, nil
+}
+
+// attestationNonce returns a fresh random nonce
+func attestationNonce() (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(nonce), nil
+}
+
+// PluginTrust is what a plugin requires of the kernel driving it
+type PluginTrust struct {
+	// Keys holds the kernel attestation keys the plugin trusts
+	Keys *signing.KeyRing
+
+	// RegistryIDs and PolicyVersions list what the plugin accepts; empty
+	// accepts any the trusted keys sign
+	RegistryIDs    []string
+	PolicyVersions []string
+
+	// MaxAge bounds the attestation's age (default DefaultAttestationMaxAge)
+	MaxAge time.Duration
+}
+
+// verify checks an attestation addressed to this plugin
+func (t *PluginTrust) verify(attestation *KernelAttestation, adapter string, buildHash string, now time.Time) error {
+	if attestation == nil {
+		return fmt.Errorf("kernel presented no attestation")
+	}
+	if t.Keys == nil {
+		return fmt.Errorf("no trusted kernel keys")
+	}
+	if err := t.Keys.Verify(attestation.KeyID, attestation.signingMessage(), attestation.Signature); err != nil {
+		return fmt.Errorf("kernel attestation: %w", err)
+	}
+	if attestation.Adapter != adapter || attestation.BuildHash != buildHash {
+		return fmt.Errorf("kernel attestation is addressed to %s build %s", attestation.Adapter, attestation.BuildHash)
+	}
+	maxAge := t.MaxAge
+	if maxAge <= 0 {
+		maxAge = DefaultAttestationMaxAge
+	}
+	if age := now.Sub(attestation.IssuedAt); age > maxAge || age < -maxAge {
+		return fmt.Errorf("kernel attestation issued %s is not fresh", attestation.IssuedAt.Format(time.RFC3339))
+	}
+	if len(t.RegistryIDs) > 0 && !containsString(t.RegistryIDs, attestation.RegistryID) {
+		return fmt.Errorf("registry %q is not trusted", attestation.RegistryID)
+	}
+	if len(t.PolicyVersions) > 0 && !containsString(t.PolicyVersions, attestation.PolicyVersion) {
+		return fmt.Errorf("policy version %q is not accepted", attestation.PolicyVersion)
+	}
+	return nil
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
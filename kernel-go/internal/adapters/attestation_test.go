@@ -0,0 +1,163 @@
+// WHY: These tests prove the registry starts only pinned plugin builds,
+// and an attested plugin refuses calls unless the kernel driving it signs
+// a fresh attestation with a key and policy version it trusts. The test
+// binary doubles as the plugin (see TestAttestedPluginHelperProcess).
+package adapters
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/posture"
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+// attestedHelperEnv carries the kernel public key the helper trusts
+const attestedHelperEnv = "OI_ATTESTED_PLUGIN_KEY"
+
+// TestAttestedPluginHelperProcess is an attested plugin; it only runs
+// when started by a plugin host
+func TestAttestedPluginHelperProcess(t *testing.T) {
+	key := os.Getenv(attestedHelperEnv)
+	if key == "" {
+		return
+	}
+	public, _ := hex.DecodeString(key)
+	keys := signing.NewKeyRing()
+	keys.Add("kernel_attestation", ed25519.PublicKey(public))
+	ServeAttestedPlugin(helperAdapter{name: "attested"}, PluginTrust{Keys: keys, PolicyVersions: []string{"v1"}})
+	os.Exit(0)
+}
+
+// newAttestedPlugin starts the test binary as an attested plugin trusting
+// trusted, driven by attestor
+func newAttestedPlugin(t *testing.T, trusted *signing.LocalSigner, attestor *Attestor) (*PluginAdapter, error) {
+	t.Helper()
+	adapter, err := NewPluginAdapter(PluginConfig{
+		Name:     "attested",
+		Path:     os.Args[0],
+		Args:     []string{"-test.run=^TestAttestedPluginHelperProcess$"},
+		Env:      []string{attestedHelperEnv + "=" + hex.EncodeToString(trusted.Public().(ed25519.PublicKey))},
+		Attestor: attestor,
+	})
+	if err == nil {
+		t.Cleanup(func() { adapter.Close() })
+	}
+	return adapter, err
+}
+
+// pinned returns build pins mapping name to the test binary's hash
+func pinned(t *testing.T, name string) func() map[string]string {
+	t.Helper()
+	hash, err := HashBuild(os.Args[0])
+	if err != nil {
+		t.Fatalf("hash build: %v", err)
+	}
+	return func() map[string]string { return map[string]string{name: hash} }
+}
+
+// TestRegistryStartsOnlyPinnedBuilds proves a plugin without a pin, or
+// whose executable differs from its pin, never starts
+func TestRegistryStartsOnlyPinnedBuilds(t *testing.T) {
+	if _, err := newHelperPluginAttested(t, &Attestor{}); err == nil || !strings.Contains(err.Error(), "unknown binaries") {
+		t.Fatalf("an unpinned plugin must be refused, got %v", err)
+	}
+	wrong := func() map[string]string { return map[string]string{"echo": strings.Repeat("0", 64)} }
+	if _, err := newHelperPluginAttested(t, &Attestor{BuildHashes: wrong}); err == nil || !strings.Contains(err.Error(), "pinned build") {
+		t.Fatalf("a plugin not matching its pin must be refused, got %v", err)
+	}
+
+	adapter, err := newHelperPluginAttested(t, &Attestor{BuildHashes: pinned(t, "echo")})
+	if err != nil {
+		t.Fatalf("a pinned plugin must start: %v", err)
+	}
+	if _, err := adapter.Invoke(context.Background(), mintPluginToken(t, "echo"), map[string]interface{}{ParamPosture: posture.P2}); err != nil {
+		t.Fatalf("invoke: %v", err)
+	}
+}
+
+// newHelperPluginAttested starts the plain helper plugin under attestor
+func newHelperPluginAttested(t *testing.T, attestor *Attestor) (*PluginAdapter, error) {
+	t.Helper()
+	adapter, err := NewPluginAdapter(PluginConfig{
+		Name:     "echo",
+		Path:     os.Args[0],
+		Args:     []string{"-test.run=^TestPluginHelperProcess$"},
+		Env:      []string{pluginHelperEnv + "=echo"},
+		Attestor: attestor,
+	})
+	if err == nil {
+		t.Cleanup(func() { adapter.Close() })
+	}
+	return adapter, err
+}
+
+// TestAttestedPluginNeedsTrustedKernel proves an attested plugin serves a
+// kernel signing with the key it trusts, and refuses one without a key, with
+// another key, or on a policy version it does not accept
+func TestAttestedPluginNeedsTrustedKernel(t *testing.T) {
+	kernelKey, _ := signing.GenerateLocalSigner("kernel_attestation")
+	otherKey, _ := signing.GenerateLocalSigner("kernel_attestation")
+	version := func(v string) func() string { return func() string { return v } }
+
+	adapter, err := newAttestedPlugin(t, kernelKey, &Attestor{RegistryID: "test_namespace", Signer: kernelKey,
+		PolicyVersion: version("v1"), BuildHashes: pinned(t, "attested")})
+	if err != nil {
+		t.Fatalf("a trusted kernel must be accepted: %v", err)
+	}
+	result, err := adapter.Invoke(context.Background(), mintPluginToken(t, "attested"), map[string]interface{}{ParamPosture: posture.P1})
+	if err != nil || result.Content != "pong" {
+		t.Fatalf("invoke after attestation: %v", err)
+	}
+
+	for name, attestor := range map[string]*Attestor{
+		"no kernel key":  {BuildHashes: pinned(t, "attested")},
+		"untrusted key":  {Signer: otherKey, PolicyVersion: version("v1"), BuildHashes: pinned(t, "attested")},
+		"policy version": {Signer: kernelKey, PolicyVersion: version("v2"), BuildHashes: pinned(t, "attested")},
+	} {
+		if _, err := newAttestedPlugin(t, kernelKey, attestor); err == nil {
+			t.Errorf("%s: the plugin must refuse the kernel", name)
+		}
+	}
+}
+
+// TestPluginTrustRejectsMisdirectedAttestations proves an attestation is
+// bound to its plugin, build, and time, and to its signature
+func TestPluginTrustRejectsMisdirectedAttestations(t *testing.T) {
+	kernelKey, _ := signing.GenerateLocalSigner("kernel_attestation")
+	keys := signing.NewKeyRing()
+	keys.AddSigner(kernelKey)
+	trust := PluginTrust{Keys: keys, RegistryIDs: []string{"home"}}
+	attestor := &Attestor{RegistryID: "home", Signer: kernelKey}
+
+	attestation, err := attestor.attest("search", "abc", "n1")
+	if err != nil {
+		t.Fatalf("attest: %v", err)
+	}
+	now := time.Now()
+	if err := trust.verify(attestation, "search", "abc", now); err != nil {
+		t.Fatalf("a valid attestation must verify: %v", err)
+	}
+	if trust.verify(attestation, "mail", "abc", now) == nil || trust.verify(attestation, "search", "def", now) == nil {
+		t.Fatal("an attestation must not verify for another plugin or build")
+	}
+	if trust.verify(attestation, "search", "abc", now.Add(2*DefaultAttestationMaxAge)) == nil {
+		t.Fatal("a stale attestation must be refused")
+	}
+
+	tampered := *attestation
+	tampered.RegistryID = "work"
+	if trust.verify(&tampered, "search", "abc", now) == nil {
+		t.Fatal("a tampered attestation must be refused")
+	}
+	other := &Attestor{RegistryID: "work", Signer: kernelKey}
+	foreign, _ := other.attest("search", "abc", "n2")
+	if trust.verify(foreign, "search", "abc", now) == nil {
+		t.Fatal("an untrusted registry must be refused")
+	}
+}
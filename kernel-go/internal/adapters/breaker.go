@@ -0,0 +1,209 @@
+// WHY: A failing or stalled backend makes every call through its adapter
+// wait out a timeout and fail anyway, holding corridor slots the whole
+// time. A circuit breaker notices the pattern, fails fast while the
+// backend recovers, and lets a single probe decide when to resume.
+package adapters
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Breaker states
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half_open"
+)
+
+// Reasons a breaker changes state
+const (
+	BreakerReasonErrorRate = "error_rate"
+	BreakerReasonLatency   = "latency"
+	BreakerReasonCooldown  = "cooldown_elapsed"
+	BreakerReasonProbeOK   = "probe_succeeded"
+	BreakerReasonProbeFail = "probe_failed"
+)
+
+// Defaults for circuit breakers
+const (
+	DefaultBreakerWindow   = 20
+	DefaultBreakerMinCalls = 10
+	DefaultBreakerOpenFor  = 30 * time.Second
+)
+
+// BreakerConfig sets when an adapter's breaker trips. A rate of zero
+// disables that trigger.
+type BreakerConfig struct {
+	// Window is how many recent calls the rates are computed over
+	// (default DefaultBreakerWindow)
+	Window int
+
+	// MinCalls is how many calls the window needs before it can trip
+	// (default DefaultBreakerMinCalls, at most Window)
+	MinCalls int
+
+	// ErrorRate trips the breaker when this fraction of calls failed
+	ErrorRate float64
+
+	// SlowCall is the latency above which a call counts as slow, and
+	// SlowRate trips the breaker when this fraction of calls were slow
+	SlowCall time.Duration
+	SlowRate float64
+
+	// OpenFor is how long the breaker fails fast before probing
+	// (default DefaultBreakerOpenFor)
+	OpenFor time.Duration
+}
+
+// BreakerTransition reports a breaker state change
+type BreakerTransition struct {
+	Adapter string
+	From    string
+	To      string
+	Reason  string
+}
+
+// BreakerOpenError reports a call refused because the breaker is open.
+// WHY: Distinct from the adapter's own errors, so callers and receipts
+// show the call never reached the backend.
+type BreakerOpenError struct {
+	Adapter string
+	State   string
+}
+
+func (e *BreakerOpenError) Error() string {
+	return fmt.Sprintf("adapter %s circuit %s: failing fast", e.Adapter, e.State)
+}
+
+// breakerOutcome is one recorded call
+type breakerOutcome struct {
+	failed bool
+	slow   bool
+}
+
+// circuitBreaker tracks one adapter's recent outcomes and state
+type circuitBreaker struct {
+	mu       sync.Mutex
+	adapter  string
+	config   BreakerConfig
+	state    string
+	outcomes []breakerOutcome // ring buffer of the last Window calls
+	next     int
+	openedAt time.Time
+	probing  bool
+	now      func() time.Time
+}
+
+// newCircuitBreaker validates config and returns a closed breaker
+func newCircuitBreaker(adapterName string, config BreakerConfig) (*circuitBreaker, error) {
+	if config.ErrorRate < 0 || config.ErrorRate > 1 || config.SlowRate < 0 || config.SlowRate > 1 {
+		return nil, fmt.Errorf("breaker rates must be between 0 and 1")
+	}
+	if config.ErrorRate == 0 && (config.SlowRate == 0 || config.SlowCall <= 0) {
+		return nil, fmt.Errorf("breaker needs an error rate or a slow call threshold and rate")
+	}
+	if config.Window <= 0 {
+		config.Window = DefaultBreakerWindow
+	}
+	if config.MinCalls <= 0 {
+		config.MinCalls = DefaultBreakerMinCalls
+	}
+	if config.MinCalls > config.Window {
+		config.MinCalls = config.Window
+	}
+	if config.OpenFor <= 0 {
+		config.OpenFor = DefaultBreakerOpenFor
+	}
+	return &circuitBreaker{adapter: adapterName, config: config, state: BreakerClosed, now: time.Now}, nil
+}
+
+// allow admits a call or fails fast. Admitting the first call after the
+// cool-off moves the breaker to half-open and makes that call the probe.
+func (b *circuitBreaker) allow() (*BreakerTransition, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if b.now().Sub(b.openedAt) < b.config.OpenFor {
+			return nil, &BreakerOpenError{Adapter: b.adapter, State: b.state}
+		}
+		b.probing = true
+		return b.transition(BreakerHalfOpen, BreakerReasonCooldown), nil
+	case BreakerHalfOpen:
+		if b.probing {
+			return nil, &BreakerOpenError{Adapter: b.adapter, State: b.state}
+		}
+		b.probing = true
+	}
+	return nil, nil
+}
+
+// record notes an admitted call's outcome and trips or resets the breaker
+func (b *circuitBreaker) record(failed bool, latency time.Duration) *BreakerTransition {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	slow := b.config.SlowCall > 0 && latency > b.config.SlowCall
+	if b.state == BreakerHalfOpen {
+		b.probing = false
+		if failed || slow {
+			b.openedAt = b.now()
+			return b.transition(BreakerOpen, BreakerReasonProbeFail)
+		}
+		b.outcomes, b.next = nil, 0
+		return b.transition(BreakerClosed, BreakerReasonProbeOK)
+	}
+	if b.state != BreakerClosed {
+		return nil
+	}
+
+	outcome := breakerOutcome{failed: failed, slow: slow}
+	if len(b.outcomes) < b.config.Window {
+		b.outcomes = append(b.outcomes, outcome)
+	} else {
+		b.outcomes[b.next] = outcome
+	}
+	b.next = (b.next + 1) % b.config.Window
+
+	if len(b.outcomes) < b.config.MinCalls {
+		return nil
+	}
+	failures, slowCalls := 0, 0
+	for _, o := range b.outcomes {
+		if o.failed {
+			failures++
+		}
+		if o.slow {
+			slowCalls++
+		}
+	}
+	calls := float64(len(b.outcomes))
+	reason := ""
+	switch {
+	case b.config.ErrorRate > 0 && float64(failures)/calls >= b.config.ErrorRate:
+		reason = BreakerReasonErrorRate
+	case b.config.SlowRate > 0 && float64(slowCalls)/calls >= b.config.SlowRate:
+		reason = BreakerReasonLatency
+	default:
+		return nil
+	}
+	b.openedAt = b.now()
+	return b.transition(BreakerOpen, reason)
+}
+
+// transition moves to state and describes the change; b.mu is held
+func (b *circuitBreaker) transition(to, reason string) *BreakerTransition {
+	change := &BreakerTransition{Adapter: b.adapter, From: b.state, To: to, Reason: reason}
+	b.state = to
+	return change
+}
+
+// currentState returns the breaker state
+func (b *circuitBreaker) currentState() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
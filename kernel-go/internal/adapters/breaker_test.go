@@ -0,0 +1,121 @@
+// WHY: These tests prove a tripped breaker stops calls reaching a failing
+// backend, and that one probe decides whether to resume.
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+)
+
+// failingAdapter fails while failing is set and counts calls that reach it
+type failingAdapter struct {
+	*MockAdapter
+	failing bool
+	calls   int
+}
+
+func (f *failingAdapter) Invoke(ctx context.Context, token *capabilities.Token, params map[string]interface{}) (*AdapterResult, error) {
+	f.calls++
+	if f.failing {
+		return nil, fmt.Errorf("backend unavailable")
+	}
+	return f.MockAdapter.Invoke(context.Background(), token, params)
+}
+
+// TestCircuitBreakerTripsAndProbes proves the breaker opens on error rate,
+// fails fast without reaching the adapter, and closes after a good probe
+func TestCircuitBreakerTripsAndProbes(t *testing.T) {
+	registry := NewRegistry()
+	adapter := &failingAdapter{MockAdapter: NewMockAdapter("backend"), failing: true}
+	registry.Register(adapter)
+	if err := registry.SetCircuitBreaker("backend", BreakerConfig{Window: 4, MinCalls: 4, ErrorRate: 0.5, OpenFor: time.Minute}); err != nil {
+		t.Fatalf("set breaker: %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	registry.breaker("backend").now = func() time.Time { return now }
+
+	var changes []BreakerTransition
+	registry.OnBreakerChange(func(change BreakerTransition) { changes = append(changes, change) })
+	invoke := func() error {
+		_, err := registry.Invoke("backend", mintLimitToken(t, "backend"), 1, map[string]interface{}{})
+		return err
+	}
+
+	for i := 0; i < 4; i++ {
+		invoke()
+	}
+	if registry.BreakerState("backend") != BreakerOpen {
+		t.Fatalf("breaker should be open, got %s", registry.BreakerState("backend"))
+	}
+	var open *BreakerOpenError
+	if err := invoke(); !errors.As(err, &open) {
+		t.Fatalf("open breaker must fail fast with BreakerOpenError, got %v", err)
+	}
+	if adapter.calls != 4 {
+		t.Fatalf("fail-fast calls must not reach the adapter: %d calls", adapter.calls)
+	}
+
+	// After the cool-off a failed probe reopens the breaker
+	now = now.Add(time.Minute)
+	if err := invoke(); err == nil || errors.As(err, &open) {
+		t.Fatalf("probe should reach the adapter and fail: %v", err)
+	}
+	if registry.BreakerState("backend") != BreakerOpen {
+		t.Fatal("failed probe must reopen the breaker")
+	}
+
+	// A good probe closes it
+	adapter.failing = false
+	now = now.Add(time.Minute)
+	if err := invoke(); err != nil {
+		t.Fatalf("probe should succeed: %v", err)
+	}
+	if registry.BreakerState("backend") != BreakerClosed {
+		t.Fatal("good probe must close the breaker")
+	}
+
+	want := []BreakerTransition{
+		{"backend", BreakerClosed, BreakerOpen, BreakerReasonErrorRate},
+		{"backend", BreakerOpen, BreakerHalfOpen, BreakerReasonCooldown},
+		{"backend", BreakerHalfOpen, BreakerOpen, BreakerReasonProbeFail},
+		{"backend", BreakerOpen, BreakerHalfOpen, BreakerReasonCooldown},
+		{"backend", BreakerHalfOpen, BreakerClosed, BreakerReasonProbeOK},
+	}
+	if fmt.Sprint(changes) != fmt.Sprint(want) {
+		t.Fatalf("transitions:\n got %v\nwant %v", changes, want)
+	}
+}
+
+// TestCircuitBreakerTripsOnLatency proves slow successful calls trip the
+// breaker, and a half-open breaker admits only one probe
+func TestCircuitBreakerTripsOnLatency(t *testing.T) {
+	breaker, err := newCircuitBreaker("slow", BreakerConfig{Window: 2, MinCalls: 2, SlowCall: time.Second, SlowRate: 1})
+	if err != nil {
+		t.Fatalf("new breaker: %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	breaker.now = func() time.Time { return now }
+
+	breaker.record(false, 2*time.Second)
+	change := breaker.record(false, 3*time.Second)
+	if change == nil || change.To != BreakerOpen || change.Reason != BreakerReasonLatency {
+		t.Fatalf("slow calls should trip on latency: %+v", change)
+	}
+
+	now = now.Add(DefaultBreakerOpenFor)
+	if _, err := breaker.allow(); err != nil {
+		t.Fatalf("first call after cool-off is the probe: %v", err)
+	}
+	if _, err := breaker.allow(); err == nil {
+		t.Fatal("half-open breaker must admit only one probe")
+	}
+
+	if _, err := newCircuitBreaker("none", BreakerConfig{Window: 5}); err == nil {
+		t.Fatal("breaker without a trigger must be refused")
+	}
+}
@@ -0,0 +1,341 @@
+// WHY: A calendar is the reference for an adapter that both reads and
+// writes. Listing events is a read scoped per calendar, and what comes back
+// is labeled like any other external content, since event text is written
+// by other people. Creating an event needs the write scope, the
+// calendar_write consent, and the proposal check; inviting attendees sends
+// mail on the user's behalf, so it is judged as high risk and confirmed
+// from P2.
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/cdi"
+	"github.com/user/oi/kernel-go/internal/cif"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// Calendar operations, passed as ParamOp
+const (
+	CalendarList   = "list"
+	CalendarCreate = "create"
+)
+
+// Calendar parameters; an event's description is ParamBody
+const (
+	ParamCalendar  = "calendar"
+	ParamTitle     = "title"
+	ParamStart     = "start"
+	ParamEnd       = "end"
+	ParamAttendees = "attendees"
+)
+
+// Defaults for calendar adapters
+const (
+	DefaultCalendarName     = "calendar"
+	DefaultCalendarTimeout  = 15 * time.Second
+	DefaultCalendarMaxRcpts = 20
+
+	// ConsentCalendarWrite is the consent creating events needs by default
+	ConsentCalendarWrite = "calendar_write"
+)
+
+// CalendarConfig configures a calendar adapter over a JSON HTTP API:
+// GET {Endpoint}/calendars/{id}/events?start=&end= returns {"events": [...]}
+// and POST to the same path creates an event and returns {"id": "..."}
+type CalendarConfig struct {
+	// Name is the adapter name and the scope tokens must carry (default DefaultCalendarName)
+	Name string
+
+	// Endpoint is the API base URL
+	Endpoint string
+
+	// Headers are sent with every request, e.g. API credentials
+	Headers map[string]string
+
+	// Timeout bounds each request (default DefaultCalendarTimeout)
+	Timeout time.Duration
+
+	// MaxPosture is the most constrained posture events may be created at
+	// (default and at most posture.P3); listing is allowed at any posture
+	MaxPosture int
+
+	// MaxAttendees bounds one event's invitations (default DefaultCalendarMaxRcpts)
+	MaxAttendees int
+
+	// Policy judges every event created; Policy.Consent defaults to ConsentCalendarWrite
+	Policy SideEffectPolicy
+
+	// Client is the HTTP client; nil uses a default client
+	Client *http.Client
+}
+
+// CalendarEvent is one event as the API exchanges it
+type CalendarEvent struct {
+	ID          string    `json:"id,omitempty"`
+	Title       string    `json:"title"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Description string    `json:"description,omitempty"`
+	Attendees   []string  `json:"attendees,omitempty"`
+}
+
+// CalendarAdapter lists and creates calendar events
+type CalendarAdapter struct {
+	config CalendarConfig
+	client *http.Client
+}
+
+// NewCalendarAdapter validates the configuration and creates the adapter.
+// The API is not contacted until the first call.
+func NewCalendarAdapter(config CalendarConfig) (*CalendarAdapter, error) {
+	if config.Name == "" {
+		config.Name = DefaultCalendarName
+	}
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("calendar adapter %s: endpoint %q must be an http(s) URL", config.Name, config.Endpoint)
+	}
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+	if config.MaxPosture == 0 {
+		config.MaxPosture = posture.P3
+	}
+	if !posture.IsValid(config.MaxPosture) || config.MaxPosture > posture.P3 {
+		return nil, fmt.Errorf("calendar adapter %s: max posture %d not permitted (at most %d)", config.Name, config.MaxPosture, posture.P3)
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultCalendarTimeout
+	}
+	if config.MaxAttendees <= 0 {
+		config.MaxAttendees = DefaultCalendarMaxRcpts
+	}
+	if config.Policy.Consent == "" {
+		config.Policy.Consent = ConsentCalendarWrite
+	}
+
+	client := config.Client
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &CalendarAdapter{config: config, client: client}, nil
+}
+
+// Name returns the adapter identifier
+func (a *CalendarAdapter) Name() string {
+	return a.config.Name
+}
+
+// Declare reports the adapter as high risk: it reads over the network and
+// creates events that may send invitations
+func (a *CalendarAdapter) Declare() cdi.CapabilityDeclaration {
+	return cdi.CapabilityDeclaration{
+		Name:        a.config.Name,
+		Risk:        cdi.RiskHigh,
+		SideEffects: []string{cdi.SideEffectNetwork, cdi.SideEffectRead, cdi.SideEffectWrite},
+		Scopes:      []string{"calendar:read:/*", "calendar:write:/*"},
+	}
+}
+
+// VerifyToken checks token validity and adapter scope. Calendar scopes and
+// the write posture bound need the request, so they are checked in Invoke.
+func (a *CalendarAdapter) VerifyToken(token *capabilities.Token, currentPosture int) error {
+	if token == nil {
+		return fmt.Errorf("nil token - tokenless invocation rejected")
+	}
+
+	valid, err := token.Verify(currentPosture)
+	if !valid {
+		return fmt.Errorf("token verification failed: %w", err)
+	}
+
+	if !token.HasScope(a.config.Name) {
+		return fmt.Errorf("token does not have scope for adapter %s", a.config.Name)
+	}
+
+	return nil
+}
+
+// Invoke lists or creates events on one calendar
+func (a *CalendarAdapter) Invoke(ctx context.Context, token *capabilities.Token, params map[string]interface{}) (*AdapterResult, error) {
+	if token == nil {
+		return nil, fmt.Errorf("nil token - invoke rejected")
+	}
+
+	currentPosture, ok := params[ParamPosture].(int)
+	if !ok {
+		return nil, fmt.Errorf("calendar adapter %s: missing verified posture", a.config.Name)
+	}
+	calendar, _ := params[ParamCalendar].(string)
+	// WHY: The ID becomes a scope target and a URL path element, so
+	// anything that could name another calendar in either is refused
+	if calendar == "" || calendar == "." || calendar == ".." || strings.ContainsAny(calendar, "/*\\?#") {
+		return nil, fmt.Errorf("calendar adapter %s: invalid calendar %q", a.config.Name, calendar)
+	}
+	eventsURL := a.config.Endpoint + "/calendars/" + url.PathEscape(calendar) + "/events"
+
+	op, _ := params[ParamOp].(string)
+	switch op {
+	case CalendarList:
+		scope := "calendar:read:/" + calendar
+		if !token.HasScope(scope) {
+			return nil, fmt.Errorf("calendar adapter %s: token does not grant %s", a.config.Name, scope)
+		}
+		return a.list(ctx, eventsURL, calendar, params)
+	case CalendarCreate:
+		scope := "calendar:write:/" + calendar
+		if !token.HasScope(scope) {
+			return nil, fmt.Errorf("calendar adapter %s: token does not grant %s", a.config.Name, scope)
+		}
+		if currentPosture > a.config.MaxPosture {
+			return nil, fmt.Errorf("calendar adapter %s: creating events not permitted at posture %d (max %d)", a.config.Name, currentPosture, a.config.MaxPosture)
+		}
+		return a.create(ctx, eventsURL, calendar, currentPosture, params)
+	default:
+		return nil, fmt.Errorf("calendar adapter %s: unknown operation %q", a.config.Name, op)
+	}
+}
+
+// list fetches the events in an optional window and labels them
+func (a *CalendarAdapter) list(ctx context.Context, eventsURL string, calendar string, params map[string]interface{}) (*AdapterResult, error) {
+	query := url.Values{}
+	for _, name := range []string{ParamStart, ParamEnd} {
+		if value, ok := params[name].(string); ok && value != "" {
+			when, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("calendar adapter %s: %s: %w", a.config.Name, name, err)
+			}
+			query.Set(name, when.UTC().Format(time.RFC3339))
+		}
+	}
+	if len(query) > 0 {
+		eventsURL += "?" + query.Encode()
+	}
+
+	responseBody, err := sendExchange(ctx, a.client, http.MethodGet, eventsURL, a.config.Headers, nil, a.config.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("calendar adapter %s: list: %w", a.config.Name, err)
+	}
+	var listed struct {
+		Events []CalendarEvent `json:"events"`
+	}
+	if err := json.Unmarshal(responseBody, &listed); err != nil {
+		return nil, fmt.Errorf("calendar adapter %s: decode events: %w", a.config.Name, err)
+	}
+	content, err := json.Marshal(listed.Events)
+	if err != nil {
+		return nil, fmt.Errorf("calendar adapter %s: encode events: %w", a.config.Name, err)
+	}
+
+	// WHY: Titles and descriptions are written by whoever sent the invite
+	labeled, err := cif.Ingress(string(content), map[string]interface{}{"source": a.config.Name, "calendar": calendar})
+	if err != nil {
+		return nil, fmt.Errorf("calendar adapter %s: label events: %w", a.config.Name, err)
+	}
+
+	return &AdapterResult{
+		Status:      StatusSuccess,
+		Content:     string(content),
+		ContentType: ContentTypeJSON,
+		Provenance:  Provenance{Source: eventsURL, TaintLabels: labeled.TaintLabels},
+		Exchange:    exchangeOf(nil, responseBody),
+		Details:     map[string]interface{}{"calendar": calendar, "events": len(listed.Events)},
+	}, nil
+}
+
+// create judges and creates one event
+func (a *CalendarAdapter) create(ctx context.Context, eventsURL string, calendar string, currentPosture int, params map[string]interface{}) (*AdapterResult, error) {
+	event, err := a.event(params)
+	if err != nil {
+		return nil, fmt.Errorf("calendar adapter %s: %w", a.config.Name, err)
+	}
+	requestBody, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("calendar adapter %s: encode event: %w", a.config.Name, err)
+	}
+
+	risk := cdi.RiskMedium
+	summary := fmt.Sprintf("create %q on calendar %s", event.Title, calendar)
+	if len(event.Attendees) > 0 {
+		risk = cdi.RiskHigh
+		summary += " and invite " + strings.Join(event.Attendees, ", ")
+	}
+	decision, err := a.config.Policy.judge(ctx, ConfirmationRequest{
+		Adapter: a.config.Name,
+		Action:  "calendar:write:/" + calendar,
+		Risk:    risk,
+		Posture: currentPosture,
+		Summary: summary,
+	}, event, requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("calendar adapter %s: %w", a.config.Name, err)
+	}
+
+	responseBody, err := sendExchange(ctx, a.client, http.MethodPost, eventsURL, a.config.Headers, requestBody, a.config.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("calendar adapter %s: create: %w", a.config.Name, err)
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(responseBody, &created); err != nil || created.ID == "" {
+		return nil, fmt.Errorf("calendar adapter %s: create returned no event id", a.config.Name)
+	}
+
+	return &AdapterResult{
+		Status:      StatusSuccess,
+		Content:     fmt.Sprintf("created event %s on calendar %s", created.ID, calendar),
+		ContentType: ContentTypeText,
+		Provenance:  Provenance{Source: eventsURL, DecisionID: decision.DecisionID},
+		Exchange:    exchangeOf(requestBody, responseBody),
+		Write:       &WriteCommitment{Target: eventsURL + "/" + url.PathEscape(created.ID), ContentHash: HashExchange(requestBody)},
+		Details:     map[string]interface{}{"calendar": calendar, "event_id": created.ID, "attendees": event.Attendees, "confirmed": decision.Metadata["confirmed"]},
+	}, nil
+}
+
+// event builds the event to create from the parameters
+func (a *CalendarAdapter) event(params map[string]interface{}) (*CalendarEvent, error) {
+	event := &CalendarEvent{}
+	event.Title, _ = params[ParamTitle].(string)
+	event.Description, _ = params[ParamBody].(string)
+	if strings.TrimSpace(event.Title) == "" {
+		return nil, fmt.Errorf("%q is required", ParamTitle)
+	}
+
+	for name, into := range map[string]*time.Time{ParamStart: &event.Start, ParamEnd: &event.End} {
+		value, _ := params[name].(string)
+		when, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be an RFC 3339 time: %w", name, err)
+		}
+		*into = when.UTC()
+	}
+	if !event.End.After(event.Start) {
+		return nil, fmt.Errorf("event must end after it starts")
+	}
+
+	if value, ok := params[ParamAttendees]; ok {
+		list, err := execArgs(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q must be a list of addresses", ParamAttendees)
+		}
+		if len(list) > a.config.MaxAttendees {
+			return nil, fmt.Errorf("%d attendees exceeds the limit of %d", len(list), a.config.MaxAttendees)
+		}
+		for _, text := range list {
+			address, err := mail.ParseAddress(text)
+			if err != nil {
+				return nil, fmt.Errorf("attendee %q: %w", text, err)
+			}
+			event.Attendees = append(event.Attendees, address.Address)
+		}
+	}
+	return event, nil
+}
@@ -0,0 +1,112 @@
+// WHY: Sending mail or inviting people cannot be undone, so an adapter
+// that does either must be gated by more than a scope. Its actions need a
+// standing consent, go through a CDI proposal check, and, where the posture
+// demands it, wait for the user to confirm the exact action. The
+// confirmation is bound to a digest of the bytes that will be sent, so
+// what the user saw is what goes out.
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/user/oi/kernel-go/internal/cdi"
+	"github.com/user/oi/kernel-go/internal/cif"
+)
+
+// ConfirmationRequest describes one action awaiting the user's confirmation
+type ConfirmationRequest struct {
+	Adapter string
+	Action  string // the scope the action exercises
+	Risk    string
+	Posture int
+
+	// Summary is what the user is asked to confirm, e.g. the recipients
+	// and subject of an email
+	Summary string
+
+	// Digest is HashExchange of the exact bytes the action will send
+	Digest string
+
+	// DecisionID is the CDI decision that required the confirmation
+	DecisionID string
+}
+
+// Confirmer asks the user to confirm an action and returns nil only if
+// they did
+type Confirmer interface {
+	Confirm(ctx context.Context, request ConfirmationRequest) error
+}
+
+// ConfirmFunc adapts a function to Confirmer
+type ConfirmFunc func(ctx context.Context, request ConfirmationRequest) error
+
+// Confirm calls f
+func (f ConfirmFunc) Confirm(ctx context.Context, request ConfirmationRequest) error {
+	return f(ctx, request)
+}
+
+// SideEffectPolicy is how a side-effect adapter's actions are judged
+type SideEffectPolicy struct {
+	// Consent names the consent every action needs (adapter default)
+	Consent string
+
+	// Consents reports the active consents; nil means none are active
+	Consents func() map[string]bool
+
+	// IntegrityState reports the kernel integrity state; nil means the
+	// adapter does not consult it
+	IntegrityState func() string
+
+	// Confirmer asks the user to confirm actions the posture says need it;
+	// nil refuses them
+	Confirmer Confirmer
+}
+
+// judge labels the arguments and asks CDI to approve the action, asking
+// the user to confirm it if CDI says a confirmation would
+func (p SideEffectPolicy) judge(ctx context.Context, request ConfirmationRequest, arguments interface{}, payload []byte) (*cdi.DecisionResult, error) {
+	encoded, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, fmt.Errorf("encode arguments: %w", err)
+	}
+	labeled, err := cif.Ingress(string(encoded), nil)
+	if err != nil {
+		return nil, fmt.Errorf("arguments: %w", err)
+	}
+
+	proposal := &cdi.ProposalContext{
+		Action:       request.Action,
+		Arguments:    labeled,
+		Risk:         request.Risk,
+		PostureLevel: request.Posture,
+		Consent:      p.Consent,
+	}
+	if p.Consents != nil {
+		proposal.ActiveConsents = p.Consents()
+	}
+	if p.IntegrityState != nil {
+		proposal.IntegrityState = p.IntegrityState()
+	}
+
+	decision, err := cdi.DecideProposal(proposal)
+	if err != nil {
+		return nil, fmt.Errorf("proposal check: %w", err)
+	}
+	if decision.Decision == cdi.DENY && decision.Metadata["obligation"] == cdi.ObligationConfirm && p.Confirmer != nil {
+		request.Digest = HashExchange(payload)
+		request.DecisionID = decision.DecisionID
+		if err := p.Confirmer.Confirm(ctx, request); err != nil {
+			return nil, fmt.Errorf("%s not confirmed: %w", request.Action, err)
+		}
+		proposal.Confirmed = true
+		if decision, err = cdi.DecideProposal(proposal); err != nil {
+			return nil, fmt.Errorf("proposal check: %w", err)
+		}
+	}
+	if decision.Decision != cdi.ALLOW {
+		return nil, fmt.Errorf("CDI %s %s: %s", decision.Decision, request.Action, decision.Reason)
+	}
+	return decision, nil
+}
@@ -0,0 +1,360 @@
+// WHY: Email is the reference for an adapter whose side effects cannot be
+// recalled. Each recipient needs its own scope ("email:send:/example.com/alice"),
+// sending needs the outbound_email consent, and above P1 the user confirms
+// the exact message before it leaves. The message is composed before it
+// is judged, so the confirmation and the ledger's write commitment cover
+// the bytes actually sent.
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/cdi"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// Defaults for email adapters
+const (
+	DefaultEmailName     = "email"
+	DefaultEmailTimeout  = 30 * time.Second
+	DefaultEmailMaxBody  = 64 << 10
+	DefaultEmailMaxRcpts = 10
+
+	// ConsentOutboundEmail is the consent sending needs by default
+	ConsentOutboundEmail = "outbound_email"
+)
+
+// Email parameters; the message text is ParamBody
+const (
+	ParamTo      = "to"
+	ParamSubject = "subject"
+)
+
+// EmailConfig configures an outbound SMTP email adapter
+type EmailConfig struct {
+	// Name is the adapter name and the scope tokens must carry (default DefaultEmailName)
+	Name string
+
+	// SMTPAddr is the submission server as host:port
+	SMTPAddr string
+
+	// From is the sender address on every message
+	From string
+
+	// Username and Password authenticate to the server; empty sends
+	// unauthenticated. WHY: Credentials only travel over TLS, so
+	// authentication requires a server offering STARTTLS.
+	Username string
+	Password string
+
+	// MaxPosture is the most constrained posture email may send at
+	// (default and at most posture.P3). WHY: P4 is read-only.
+	MaxPosture int
+
+	// Timeout bounds one send (default DefaultEmailTimeout)
+	Timeout time.Duration
+
+	// MaxBodyBytes and MaxRecipients bound one message (defaults
+	// DefaultEmailMaxBody and DefaultEmailMaxRcpts)
+	MaxBodyBytes  int
+	MaxRecipients int
+
+	// Policy judges every send; Policy.Consent defaults to ConsentOutboundEmail
+	Policy SideEffectPolicy
+
+	// TLSConfig is used for STARTTLS; nil verifies against the SMTP host
+	TLSConfig *tls.Config
+}
+
+// EmailAdapter sends email over SMTP
+type EmailAdapter struct {
+	config EmailConfig
+	host   string
+}
+
+// NewEmailAdapter validates the configuration and creates the adapter. The
+// server is not contacted until the first send.
+func NewEmailAdapter(config EmailConfig) (*EmailAdapter, error) {
+	if config.Name == "" {
+		config.Name = DefaultEmailName
+	}
+	host, _, err := net.SplitHostPort(config.SMTPAddr)
+	if err != nil {
+		return nil, fmt.Errorf("email adapter %s: smtp address: %w", config.Name, err)
+	}
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, fmt.Errorf("email adapter %s: from address: %w", config.Name, err)
+	}
+	config.From = from.Address
+	if config.MaxPosture == 0 {
+		config.MaxPosture = posture.P3
+	}
+	if !posture.IsValid(config.MaxPosture) || config.MaxPosture > posture.P3 {
+		return nil, fmt.Errorf("email adapter %s: max posture %d not permitted (at most %d)", config.Name, config.MaxPosture, posture.P3)
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultEmailTimeout
+	}
+	if config.MaxBodyBytes <= 0 {
+		config.MaxBodyBytes = DefaultEmailMaxBody
+	}
+	if config.MaxRecipients <= 0 {
+		config.MaxRecipients = DefaultEmailMaxRcpts
+	}
+	if config.Policy.Consent == "" {
+		config.Policy.Consent = ConsentOutboundEmail
+	}
+	return &EmailAdapter{config: config, host: host}, nil
+}
+
+// Name returns the adapter identifier
+func (a *EmailAdapter) Name() string {
+	return a.config.Name
+}
+
+// Declare reports that the adapter sends over the network and writes
+// state it cannot take back
+func (a *EmailAdapter) Declare() cdi.CapabilityDeclaration {
+	return cdi.CapabilityDeclaration{
+		Name:        a.config.Name,
+		Risk:        cdi.RiskHigh,
+		SideEffects: []string{cdi.SideEffectNetwork, cdi.SideEffectWrite},
+		Scopes:      []string{"email:send:/**"},
+	}
+}
+
+// VerifyToken checks token validity, adapter scope, and the posture bound.
+// Recipient scopes need the request, so they are checked in Invoke.
+func (a *EmailAdapter) VerifyToken(token *capabilities.Token, currentPosture int) error {
+	if token == nil {
+		return fmt.Errorf("nil token - tokenless invocation rejected")
+	}
+
+	valid, err := token.Verify(currentPosture)
+	if !valid {
+		return fmt.Errorf("token verification failed: %w", err)
+	}
+
+	if !token.HasScope(a.config.Name) {
+		return fmt.Errorf("token does not have scope for adapter %s", a.config.Name)
+	}
+
+	if !posture.IsValid(currentPosture) || currentPosture > a.config.MaxPosture {
+		return fmt.Errorf("adapter %s not permitted at posture %d (max %d)", a.config.Name, currentPosture, a.config.MaxPosture)
+	}
+
+	return nil
+}
+
+// Invoke composes, judges, and sends one message
+func (a *EmailAdapter) Invoke(ctx context.Context, token *capabilities.Token, params map[string]interface{}) (*AdapterResult, error) {
+	if token == nil {
+		return nil, fmt.Errorf("nil token - invoke rejected")
+	}
+
+	currentPosture, ok := params[ParamPosture].(int)
+	if !ok {
+		return nil, fmt.Errorf("email adapter %s: missing verified posture", a.config.Name)
+	}
+	recipients, err := a.recipients(params[ParamTo])
+	if err != nil {
+		return nil, fmt.Errorf("email adapter %s: %w", a.config.Name, err)
+	}
+	for _, recipient := range recipients {
+		scope, err := emailScope(recipient)
+		if err != nil {
+			return nil, fmt.Errorf("email adapter %s: %w", a.config.Name, err)
+		}
+		if !token.HasScope(scope) {
+			return nil, fmt.Errorf("email adapter %s: token does not grant %s", a.config.Name, scope)
+		}
+	}
+
+	subject, _ := params[ParamSubject].(string)
+	body, _ := params[ParamBody].(string)
+	if strings.ContainsAny(subject, "\r\n") {
+		return nil, fmt.Errorf("email adapter %s: subject contains a line break", a.config.Name)
+	}
+	if len(body) > a.config.MaxBodyBytes {
+		return nil, fmt.Errorf("email adapter %s: body exceeds %d bytes", a.config.Name, a.config.MaxBodyBytes)
+	}
+
+	message, messageID, err := a.compose(recipients, subject, body)
+	if err != nil {
+		return nil, fmt.Errorf("email adapter %s: %w", a.config.Name, err)
+	}
+	decision, err := a.config.Policy.judge(ctx, ConfirmationRequest{
+		Adapter: a.config.Name,
+		Action:  "email:send",
+		Risk:    cdi.RiskHigh,
+		Posture: currentPosture,
+		Summary: fmt.Sprintf("send email to %s: %q", strings.Join(recipients, ", "), subject),
+	}, map[string]interface{}{ParamTo: recipients, ParamSubject: subject, ParamBody: body}, message)
+	if err != nil {
+		return nil, fmt.Errorf("email adapter %s: %w", a.config.Name, err)
+	}
+
+	if err := a.send(ctx, recipients, message); err != nil {
+		return nil, fmt.Errorf("email adapter %s: send: %w", a.config.Name, err)
+	}
+
+	return &AdapterResult{
+		Status:      StatusSuccess,
+		Content:     fmt.Sprintf("sent to %d recipient(s)", len(recipients)),
+		ContentType: ContentTypeText,
+		Provenance:  Provenance{Source: "smtp://" + a.config.SMTPAddr, DecisionID: decision.DecisionID},
+		Write:       &WriteCommitment{Target: "mailto:" + strings.Join(recipients, ","), ContentHash: HashExchange(message)},
+		Details:     map[string]interface{}{"recipients": recipients, "message_id": messageID, "confirmed": decision.Metadata["confirmed"]},
+	}, nil
+}
+
+// recipients accepts one address or a list and returns the bare addresses
+func (a *EmailAdapter) recipients(value interface{}) ([]string, error) {
+	var raw []string
+	switch v := value.(type) {
+	case string:
+		raw = []string{v}
+	default:
+		list, err := execArgs(value)
+		if err != nil || len(list) == 0 {
+			return nil, fmt.Errorf("%q must be an address or a list of addresses", ParamTo)
+		}
+		raw = list
+	}
+	if len(raw) > a.config.MaxRecipients {
+		return nil, fmt.Errorf("%d recipients exceeds the limit of %d", len(raw), a.config.MaxRecipients)
+	}
+	addresses := make([]string, len(raw))
+	for i, text := range raw {
+		address, err := mail.ParseAddress(text)
+		if err != nil {
+			return nil, fmt.Errorf("recipient %q: %w", text, err)
+		}
+		addresses[i] = address.Address
+	}
+	return addresses, nil
+}
+
+// emailScope returns the scope sending to an address needs
+func emailScope(address string) (string, error) {
+	at := strings.LastIndex(address, "@")
+	if at <= 0 || at == len(address)-1 {
+		return "", fmt.Errorf("recipient %q has no domain", address)
+	}
+	local, domain := address[:at], strings.ToLower(address[at+1:])
+	// WHY: Scope targets split on "/" and treat "*" as a wildcard, so an
+	// address containing either could name more than one mailbox; a line
+	// break would let it add headers
+	if strings.ContainsAny(address, "/*\r\n") || local == "." || local == ".." {
+		return "", fmt.Errorf("recipient %q cannot be scoped", address)
+	}
+	return "email:send:/" + domain + "/" + local, nil
+}
+
+// compose renders the message with a fresh Message-ID
+func (a *EmailAdapter) compose(recipients []string, subject string, body string) ([]byte, string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, "", err
+	}
+	messageID := "<" + hex.EncodeToString(id) + "@" + a.host + ">"
+
+	var message bytes.Buffer
+	header := func(name, value string) {
+		fmt.Fprintf(&message, "%s: %s\r\n", name, value)
+	}
+	header("From", a.config.From)
+	header("To", strings.Join(recipients, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", subject))
+	header("Date", time.Now().UTC().Format(time.RFC1123Z))
+	header("Message-ID", messageID)
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+	message.WriteString("\r\n")
+
+	encoder := quotedprintable.NewWriter(&message)
+	body = strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n")
+	if _, err := encoder.Write([]byte(body)); err != nil {
+		return nil, "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, "", err
+	}
+	return message.Bytes(), messageID, nil
+}
+
+// send delivers the message to the submission server
+func (a *EmailAdapter) send(ctx context.Context, recipients []string, message []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, a.config.Timeout)
+	defer cancel()
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", a.config.SMTPAddr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	// WHY: net/smtp has no context, so cancellation closes the connection
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, a.host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		config := a.config.TLSConfig
+		if config == nil {
+			config = &tls.Config{ServerName: a.host}
+		}
+		if err := client.StartTLS(config); err != nil {
+			return err
+		}
+	}
+	if a.config.Username != "" {
+		if _, isTLS := client.TLSConnectionState(); !isTLS {
+			return fmt.Errorf("server does not offer STARTTLS; refusing to send credentials")
+		}
+		if err := client.Auth(smtp.PlainAuth("", a.config.Username, a.config.Password, a.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(a.config.From); err != nil {
+		return err
+	}
+	for _, recipient := range recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("recipient %s: %w", recipient, err)
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(message); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,232 @@
+// WHY: These tests prove email needs a scope per recipient and the
+// outbound consent, that above P1 nothing is sent until the user confirms
+// the exact message, and that headers cannot be injected.
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// fakeSMTPServer accepts messages without TLS or authentication and
+// records what it was given
+type fakeSMTPServer struct {
+	listener net.Listener
+
+	mu         sync.Mutex
+	recipients []string
+	messages   []string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := &fakeSMTPServer{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 fake ESMTP")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		verb := strings.ToUpper(strings.Fields(line + " x")[0])
+		switch verb {
+		case "EHLO", "HELO":
+			text.PrintfLine("250 fake")
+		case "MAIL":
+			text.PrintfLine("250 ok")
+		case "RCPT":
+			s.mu.Lock()
+			s.recipients = append(s.recipients, strings.Trim(strings.TrimPrefix(line, "RCPT TO:"), "<>"))
+			s.mu.Unlock()
+			text.PrintfLine("250 ok")
+		case "DATA":
+			text.PrintfLine("354 go ahead")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, string(data))
+			s.mu.Unlock()
+			text.PrintfLine("250 queued")
+		case "QUIT":
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("502 unsupported")
+		}
+	}
+}
+
+func (s *fakeSMTPServer) sent() ([]string, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.recipients...), append([]string(nil), s.messages...)
+}
+
+// mintEmailToken mints a token for the email adapter with extra scopes
+func mintEmailToken(t *testing.T, scopes ...string) *capabilities.Token {
+	t.Helper()
+	token, err := capabilities.Mint("kernel", "test_subject", "adapters", append([]string{DefaultEmailName}, scopes...),
+		capabilities.Limits{}, time.Minute,
+		capabilities.PostureBounds{MinPosture: posture.P1, MaxPosture: posture.P4},
+		"test_namespace", "test_principal")
+	if err != nil {
+		t.Fatalf("mint: %v", err)
+	}
+	return token
+}
+
+func consented(names ...string) func() map[string]bool {
+	return func() map[string]bool {
+		consents := map[string]bool{}
+		for _, name := range names {
+			consents[name] = true
+		}
+		return consents
+	}
+}
+
+// TestEmailNeedsRecipientScopeAndConsent proves a send is refused without
+// a scope for every recipient or without the outbound consent
+func TestEmailNeedsRecipientScopeAndConsent(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	adapter, err := NewEmailAdapter(EmailConfig{SMTPAddr: server.listener.Addr().String(), From: "oi@example.org",
+		Policy: SideEffectPolicy{Consents: consented(ConsentOutboundEmail)}})
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	params := map[string]interface{}{ParamPosture: posture.P1, ParamTo: []string{"alice@Example.com", "bob@example.com"},
+		ParamSubject: "Lunch", ParamBody: "Noon on Friday?"}
+
+	token := mintEmailToken(t, "email:send:/example.com/alice")
+	if _, err := adapter.Invoke(context.Background(), token, params); err == nil || !strings.Contains(err.Error(), "email:send:/example.com/bob") {
+		t.Fatalf("a recipient outside the scope must be refused, got %v", err)
+	}
+
+	token = mintEmailToken(t, "email:send:/example.com/*")
+	result, err := adapter.Invoke(context.Background(), token, params)
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	recipients, messages := server.sent()
+	if len(messages) != 1 || strings.Join(recipients, ",") != "alice@Example.com,bob@example.com" {
+		t.Fatalf("server got %v / %d messages", recipients, len(messages))
+	}
+	if result.Write == nil || result.Provenance.DecisionID == "" {
+		t.Fatalf("a send must commit the message and cite its decision: %+v", result)
+	}
+
+	unconsented, _ := NewEmailAdapter(EmailConfig{SMTPAddr: server.listener.Addr().String(), From: "oi@example.org"})
+	if _, err := unconsented.Invoke(context.Background(), token, params); err == nil || !strings.Contains(err.Error(), "proposal_requires_consent") {
+		t.Fatalf("sending without consent must be refused, got %v", err)
+	}
+	if _, messages := server.sent(); len(messages) != 1 {
+		t.Fatal("a refused send must not reach the server")
+	}
+}
+
+// TestEmailConfirmationCoversTheSentMessage proves the user is asked to
+// confirm above P1, the digest they confirm is the message committed, and
+// a refusal or a missing confirmer sends nothing
+func TestEmailConfirmationCoversTheSentMessage(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	var asked []ConfirmationRequest
+	confirmer := ConfirmFunc(func(ctx context.Context, request ConfirmationRequest) error {
+		asked = append(asked, request)
+		if strings.Contains(request.Summary, "mallory") {
+			return fmt.Errorf("declined")
+		}
+		return nil
+	})
+	adapter, err := NewEmailAdapter(EmailConfig{SMTPAddr: server.listener.Addr().String(), From: "oi@example.org",
+		Policy: SideEffectPolicy{Consents: consented(ConsentOutboundEmail), Confirmer: confirmer}})
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	token := mintEmailToken(t, "email:send:/example.com/*")
+
+	result, err := adapter.Invoke(context.Background(), token, map[string]interface{}{
+		ParamPosture: posture.P2, ParamTo: "alice@example.com", ParamSubject: "Report", ParamBody: "Attached."})
+	if err != nil {
+		t.Fatalf("confirmed send: %v", err)
+	}
+	if len(asked) != 1 || asked[0].Digest != result.Write.ContentHash || asked[0].DecisionID == "" {
+		t.Fatalf("confirmation must cover the committed message: %+v vs %s", asked, result.Write.ContentHash)
+	}
+	if result.Details["confirmed"] != true {
+		t.Fatal("the result must record that the send was confirmed")
+	}
+	_, messages := server.sent()
+	if len(messages) != 1 || !strings.Contains(messages[0], result.Details["message_id"].(string)) {
+		t.Fatal("the message sent must be the message confirmed")
+	}
+
+	if _, err := adapter.Invoke(context.Background(), token, map[string]interface{}{
+		ParamPosture: posture.P2, ParamTo: "mallory@example.com", ParamSubject: "Report", ParamBody: "Attached."}); err == nil {
+		t.Fatal("a declined confirmation must refuse the send")
+	}
+
+	unconfirmed, _ := NewEmailAdapter(EmailConfig{SMTPAddr: server.listener.Addr().String(), From: "oi@example.org",
+		Policy: SideEffectPolicy{Consents: consented(ConsentOutboundEmail)}})
+	if _, err := unconfirmed.Invoke(context.Background(), token, map[string]interface{}{
+		ParamPosture: posture.P2, ParamTo: "alice@example.com", ParamSubject: "Report", ParamBody: "Attached."}); err == nil ||
+		!strings.Contains(err.Error(), "proposal_requires_confirmation") {
+		t.Fatalf("a send needing confirmation must be refused without a confirmer, got %v", err)
+	}
+	if _, messages := server.sent(); len(messages) != 1 {
+		t.Fatal("refused sends must not reach the server")
+	}
+}
+
+// TestEmailRefusesHeaderInjection proves line breaks and wildcard
+// characters cannot smuggle headers or widen a recipient scope
+func TestEmailRefusesHeaderInjection(t *testing.T) {
+	adapter, err := NewEmailAdapter(EmailConfig{SMTPAddr: "127.0.0.1:1", From: "oi@example.org"})
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	token := mintEmailToken(t, "email:send:/**")
+	for name, params := range map[string]map[string]interface{}{
+		"subject line break": {ParamTo: "alice@example.com", ParamSubject: "Hi\r\nBcc: eve@example.com"},
+		"wildcard recipient": {ParamTo: `"*"@example.com`},
+		"path recipient":     {ParamTo: `"a/b"@example.com`},
+	} {
+		params[ParamPosture] = posture.P1
+		if _, err := adapter.Invoke(context.Background(), token, params); err == nil {
+			t.Errorf("%s: must be refused", name)
+		}
+	}
+
+	if _, err := NewEmailAdapter(EmailConfig{SMTPAddr: "127.0.0.1:25", From: "oi@example.org", MaxPosture: posture.P4}); err == nil {
+		t.Fatal("email must not be permitted at P4")
+	}
+}
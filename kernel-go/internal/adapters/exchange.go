@@ -0,0 +1,72 @@
+// WHY: Adapters that talk to an external service must leave evidence of
+// exactly what crossed the boundary without copying content into the
+// ledger. Hashing the raw request and response bodies gives auditors a
+// commitment they can check against provider logs.
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// maxExchangeResponseBytes caps how much of a response body is read
+const maxExchangeResponseBytes = 4 << 20
+
+// HashExchange returns the hex SHA-256 of a raw wire body
+func HashExchange(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// postExchange POSTs a JSON body and returns the raw response body
+func postExchange(ctx context.Context, client *http.Client, url string, headers map[string]string, body []byte, timeout time.Duration) ([]byte, error) {
+	return sendExchange(ctx, client, http.MethodPost, url, headers, body, timeout)
+}
+
+// sendExchange sends a request with an optional JSON body and returns the
+// raw response body.
+// WHY: Error bodies may echo the prompt, so only the status is reported,
+// and a stalled server is cut off at the timeout rather than holding the
+// corridor open.
+func sendExchange(ctx context.Context, client *http.Client, method string, url string, headers map[string]string, body []byte, timeout time.Duration) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timed out after %s", timeout)
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxExchangeResponseBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if len(respBody) > maxExchangeResponseBytes {
+		return nil, fmt.Errorf("response exceeds %d bytes", maxExchangeResponseBytes)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return respBody, nil
+}
@@ -0,0 +1,446 @@
+// WHY: Running a command is the widest capability an agent can hold, so
+// the exec adapter is the most constrained one. Commands run as a separate
+// process (or container) with a scrubbed environment and resource limits,
+// only for tokens that name the exact executable ("exec:run:/usr/bin/ls")
+// and only at the permissive end of the posture range. Output goes back
+// through CIF egress, never raw, so a command cannot smuggle instructions
+// or exceed the leak budget.
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/cdi"
+	"github.com/user/oi/kernel-go/internal/cif"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// Defaults for exec adapters
+const (
+	DefaultExecName       = "exec"
+	DefaultExecTimeout    = 30 * time.Second
+	DefaultExecCPUSeconds = 10
+	DefaultExecMemory     = 512 << 20
+	DefaultExecFileBytes  = 16 << 20
+	DefaultExecOpenFiles  = 64
+	DefaultExecOutput     = 64 << 10
+)
+
+// Exec parameters
+const (
+	ParamCommand = "command"
+	ParamArgs    = "args"
+)
+
+// sandboxPath is the only PATH commands see
+const sandboxPath = "/usr/local/bin:/usr/bin:/bin"
+
+// ContainerConfig runs commands in a throwaway container instead of a
+// host process
+type ContainerConfig struct {
+	// Runtime is a docker-compatible CLI ("docker", "podman")
+	Runtime string
+
+	// Image is the container image commands run in
+	Image string
+}
+
+// ExecConfig configures a sandboxed exec adapter
+type ExecConfig struct {
+	// Name is the adapter name and the scope tokens must carry (default DefaultExecName)
+	Name string
+
+	// MaxPosture is the most constrained posture exec may run at (default
+	// posture.P1, at most posture.P2). WHY: P2 and above require
+	// confirmation for high-risk operations; P3 and P4 never run commands.
+	MaxPosture int
+
+	// Timeout bounds wall-clock time (default DefaultExecTimeout)
+	Timeout time.Duration
+
+	// Resource limits applied to the command
+	CPUSeconds  int   // default DefaultExecCPUSeconds
+	MemoryBytes int64 // default DefaultExecMemory
+	FileBytes   int64 // largest file the command may write; default DefaultExecFileBytes
+	OpenFiles   int   // default DefaultExecOpenFiles
+
+	// MaxOutputBytes caps captured stdout and stderr each and is the CIF
+	// leak budget for them (default DefaultExecOutput)
+	MaxOutputBytes int
+
+	// WorkDir is the command's working directory; empty means a fresh
+	// temporary directory per invocation, removed afterwards
+	WorkDir string
+
+	// Container, if set, runs commands in a container
+	Container *ContainerConfig
+
+	// Isolation, if set, runs commands under an isolation profile instead
+	// of the ulimit wrapper; it cannot be combined with Container
+	Isolation *Isolation
+}
+
+// ExecAdapter runs commands in a sandbox
+type ExecAdapter struct {
+	config  ExecConfig
+	runtime string // resolved container runtime path, if any
+}
+
+// NewExecAdapter validates the configuration and creates the adapter
+func NewExecAdapter(config ExecConfig) (*ExecAdapter, error) {
+	if config.Name == "" {
+		config.Name = DefaultExecName
+	}
+	if config.MaxPosture == 0 {
+		config.MaxPosture = posture.P1
+	}
+	if !posture.IsValid(config.MaxPosture) || config.MaxPosture > posture.P2 {
+		return nil, fmt.Errorf("exec adapter %s: max posture %d not permitted (at most %d)", config.Name, config.MaxPosture, posture.P2)
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultExecTimeout
+	}
+	if config.CPUSeconds <= 0 {
+		config.CPUSeconds = DefaultExecCPUSeconds
+	}
+	if config.MemoryBytes <= 0 {
+		config.MemoryBytes = DefaultExecMemory
+	}
+	if config.FileBytes <= 0 {
+		config.FileBytes = DefaultExecFileBytes
+	}
+	if config.OpenFiles <= 0 {
+		config.OpenFiles = DefaultExecOpenFiles
+	}
+	if config.MaxOutputBytes <= 0 {
+		config.MaxOutputBytes = DefaultExecOutput
+	}
+	if config.WorkDir != "" && !filepath.IsAbs(config.WorkDir) {
+		return nil, fmt.Errorf("exec adapter %s: work dir %q is not absolute", config.Name, config.WorkDir)
+	}
+
+	adapter := &ExecAdapter{config: config}
+	if config.Container != nil {
+		if config.Container.Image == "" {
+			return nil, fmt.Errorf("exec adapter %s: container has no image", config.Name)
+		}
+		runtime, err := exec.LookPath(config.Container.Runtime)
+		if err != nil {
+			return nil, fmt.Errorf("exec adapter %s: container runtime: %w", config.Name, err)
+		}
+		adapter.runtime = runtime
+	}
+	if config.Isolation != nil {
+		if config.Container != nil {
+			return nil, fmt.Errorf("exec adapter %s: isolation and container are exclusive", config.Name)
+		}
+		if err := config.Isolation.validate(); err != nil {
+			return nil, fmt.Errorf("exec adapter %s: %w", config.Name, err)
+		}
+	}
+	return adapter, nil
+}
+
+// Name returns the adapter identifier
+func (a *ExecAdapter) Name() string {
+	return a.config.Name
+}
+
+// Declare reports that commands run and may write
+func (a *ExecAdapter) Declare() cdi.CapabilityDeclaration {
+	return cdi.CapabilityDeclaration{
+		Name:        a.config.Name,
+		Risk:        cdi.RiskHigh,
+		SideEffects: []string{cdi.SideEffectExec, cdi.SideEffectWrite},
+		Scopes:      []string{"exec:*"},
+	}
+}
+
+// VerifyToken checks token validity, adapter scope, and the posture bound.
+// The command scope needs the request, so it is checked in Invoke.
+func (a *ExecAdapter) VerifyToken(token *capabilities.Token, currentPosture int) error {
+	if token == nil {
+		return fmt.Errorf("nil token - tokenless invocation rejected")
+	}
+
+	valid, err := token.Verify(currentPosture)
+	if !valid {
+		return fmt.Errorf("token verification failed: %w", err)
+	}
+
+	if !token.HasScope(a.config.Name) {
+		return fmt.Errorf("token does not have scope for adapter %s", a.config.Name)
+	}
+
+	if !posture.IsValid(currentPosture) || currentPosture > a.config.MaxPosture {
+		return fmt.Errorf("adapter %s not permitted at posture %d (max %d)", a.config.Name, currentPosture, a.config.MaxPosture)
+	}
+
+	return nil
+}
+
+// Invoke runs one command and returns its egress-filtered output
+func (a *ExecAdapter) Invoke(ctx context.Context, token *capabilities.Token, params map[string]interface{}) (*AdapterResult, error) {
+	if token == nil {
+		return nil, fmt.Errorf("nil token - invoke rejected")
+	}
+
+	currentPosture, ok := params[ParamPosture].(int)
+	if !ok {
+		return nil, fmt.Errorf("exec adapter %s: missing verified posture", a.config.Name)
+	}
+	command, _ := params[ParamCommand].(string)
+	if command == "" {
+		return nil, fmt.Errorf("exec adapter %s: missing %q parameter", a.config.Name, ParamCommand)
+	}
+	args, err := execArgs(params[ParamArgs])
+	if err != nil {
+		return nil, fmt.Errorf("exec adapter %s: %w", a.config.Name, err)
+	}
+
+	executable, err := a.resolveCommand(command)
+	if err != nil {
+		return nil, fmt.Errorf("exec adapter %s: %w", a.config.Name, err)
+	}
+	scope := "exec:run:" + executable
+	if !token.HasScope(scope) {
+		return nil, fmt.Errorf("exec adapter %s: token does not grant %s", a.config.Name, scope)
+	}
+
+	workDir := a.config.WorkDir
+	if workDir == "" {
+		workDir, err = os.MkdirTemp("", "oi-exec-")
+		if err != nil {
+			return nil, fmt.Errorf("exec adapter %s: work dir: %w", a.config.Name, err)
+		}
+		defer os.RemoveAll(workDir)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, a.config.Timeout)
+	defer cancel()
+
+	cmd, cleanup, err := a.command(ctx, executable, args, workDir)
+	if err != nil {
+		return nil, fmt.Errorf("exec adapter %s: %w", a.config.Name, err)
+	}
+	defer cleanup()
+	cmd.Dir = workDir
+	cmd.Env = []string{"PATH=" + sandboxPath, "HOME=" + workDir, "TMPDIR=" + workDir, "LANG=C.UTF-8"}
+	cmd.WaitDelay = time.Second
+	stdout := &cappedBuffer{limit: a.config.MaxOutputBytes}
+	stderr := &cappedBuffer{limit: a.config.MaxOutputBytes}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	runErr := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("exec adapter %s: timed out after %s", a.config.Name, a.config.Timeout)
+	}
+	exitCode := 0
+	if runErr != nil {
+		exitErr, ok := runErr.(*exec.ExitError)
+		if !ok {
+			return nil, fmt.Errorf("exec adapter %s: %w", a.config.Name, runErr)
+		}
+		exitCode = exitErr.ExitCode()
+	}
+
+	outResponse, err := a.egress(stdout, currentPosture)
+	if err != nil {
+		return nil, fmt.Errorf("exec adapter %s: stdout egress: %w", a.config.Name, err)
+	}
+	errResponse, err := a.egress(stderr, currentPosture)
+	if err != nil {
+		return nil, fmt.Errorf("exec adapter %s: stderr egress: %w", a.config.Name, err)
+	}
+
+	request, _ := json.Marshal(append([]string{executable}, args...))
+	status := StatusSuccess
+	if exitCode != 0 {
+		status = StatusFailed
+	}
+	return &AdapterResult{
+		Status:      status,
+		Content:     outResponse.Content,
+		ContentType: ContentTypeText,
+		Provenance:  Provenance{Source: executable},
+		Exchange:    exchangeOf(request, append(append([]byte(nil), stdout.data...), stderr.data...)),
+		Details: map[string]interface{}{
+			"exit_code":        exitCode,
+			"stdout":           outResponse.Content,
+			"stderr":           errResponse.Content,
+			"stdout_redacted":  outResponse.Redacted,
+			"stderr_redacted":  errResponse.Redacted,
+			"output_truncated": stdout.truncated || stderr.truncated,
+		},
+	}, nil
+}
+
+// resolveCommand finds the executable the scope is checked against. On the
+// host that is the canonical path after following symlinks, so a link
+// named like an allowed tool cannot run something else.
+func (a *ExecAdapter) resolveCommand(command string) (string, error) {
+	if a.config.Container != nil {
+		if !filepath.IsAbs(command) {
+			return "", fmt.Errorf("container commands must be absolute paths, got %q", command)
+		}
+		return filepath.Clean(command), nil
+	}
+
+	path := command
+	if !strings.Contains(command, "/") {
+		path = ""
+		for _, dir := range filepath.SplitList(sandboxPath) {
+			candidate := filepath.Join(dir, command)
+			if info, err := os.Stat(candidate); err == nil && info.Mode().IsRegular() && info.Mode()&0o111 != 0 {
+				path = candidate
+				break
+			}
+		}
+		if path == "" {
+			return "", fmt.Errorf("command %q not found in %s", command, sandboxPath)
+		}
+	}
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("command path %q is not absolute", command)
+	}
+	return filepath.EvalSymlinks(filepath.Clean(path))
+}
+
+// command builds the sandboxed command and a cleanup to call after it exits
+func (a *ExecAdapter) command(ctx context.Context, executable string, args []string, workDir string) (*exec.Cmd, func(), error) {
+	if a.config.Isolation != nil {
+		return a.config.Isolation.command(ctx, a.config.Name, executable, args, a.limits())
+	}
+	argv := a.sandboxArgv(executable, args, workDir)
+	return exec.CommandContext(ctx, argv[0], argv[1:]...), func() {}, nil
+}
+
+// sandboxArgv wraps the command with resource limits, either in a
+// container or under the shell's ulimit
+func (a *ExecAdapter) sandboxArgv(executable string, args []string, workDir string) []string {
+	if a.config.Container != nil {
+		argv := []string{
+			a.runtime, "run", "--rm",
+			"--network=none",
+			"--read-only",
+			"--cap-drop=ALL",
+			"--security-opt=no-new-privileges",
+			"--pids-limit=64",
+			fmt.Sprintf("--memory=%d", a.config.MemoryBytes),
+			fmt.Sprintf("--ulimit=cpu=%d", a.config.CPUSeconds),
+			fmt.Sprintf("--ulimit=nofile=%d", a.config.OpenFiles),
+			fmt.Sprintf("--ulimit=fsize=%d", a.config.FileBytes),
+			"--user=65534:65534",
+			"--workdir=/workspace",
+			"--volume=" + workDir + ":/workspace",
+			a.config.Container.Image,
+			executable,
+		}
+		return append(argv, args...)
+	}
+
+	return a.limits().argv(executable, args)
+}
+
+// limits returns the configured resource limits
+func (a *ExecAdapter) limits() rlimits {
+	return rlimits{
+		CPUSeconds:  a.config.CPUSeconds,
+		MemoryBytes: a.config.MemoryBytes,
+		FileBytes:   a.config.FileBytes,
+		OpenFiles:   a.config.OpenFiles,
+	}
+}
+
+// rlimits are OS resource limits for a child process; zero leaves a limit unset
+type rlimits struct {
+	CPUSeconds  int
+	MemoryBytes int64
+	FileBytes   int64
+	OpenFiles   int
+}
+
+// argv wraps a command so it runs under the limits.
+// WHY: ulimit in a wrapper shell sets rlimits on the exec'd command
+// without platform-specific syscalls; "$0" "$@" passes arguments
+// through untouched, never re-parsed by the shell.
+func (l rlimits) argv(executable string, args []string) []string {
+	script := ""
+	if l.CPUSeconds > 0 {
+		script += fmt.Sprintf("ulimit -t %d && ", l.CPUSeconds)
+	}
+	if l.MemoryBytes > 0 {
+		script += fmt.Sprintf("ulimit -v %d && ", l.MemoryBytes/1024)
+	}
+	if l.FileBytes > 0 {
+		script += fmt.Sprintf("ulimit -f %d && ", l.FileBytes/512)
+	}
+	if l.OpenFiles > 0 {
+		script += fmt.Sprintf("ulimit -n %d && ", l.OpenFiles)
+	}
+	script += `exec "$0" "$@"`
+	return append([]string{"/bin/sh", "-c", script, executable}, args...)
+}
+
+// egress passes captured output through CIF egress with the output cap as
+// the leak budget
+func (a *ExecAdapter) egress(output *cappedBuffer, currentPosture int) (*cif.UserResponse, error) {
+	return cif.Egress(&cif.OutputArtifact{
+		Content:          string(output.data),
+		SensitivityLevel: "medium",
+		LeakBudgetUsed:   len(output.data),
+		Metadata:         map[string]interface{}{"source": a.config.Name},
+	}, currentPosture, a.config.MaxOutputBytes)
+}
+
+// execArgs accepts args as []string or a decoded JSON list of strings
+func execArgs(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []string:
+		return v, nil
+	case []interface{}:
+		args := make([]string, len(v))
+		for i, arg := range v {
+			text, ok := arg.(string)
+			if !ok {
+				return nil, fmt.Errorf("argument %d is %T, want string", i, arg)
+			}
+			args[i] = text
+		}
+		return args, nil
+	default:
+		return nil, fmt.Errorf("%q must be a list of strings", ParamArgs)
+	}
+}
+
+// cappedBuffer keeps the first limit bytes written and discards the rest.
+// WHY: A command must not exhaust kernel memory by printing forever.
+type cappedBuffer struct {
+	limit     int
+	data      []byte
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	room := b.limit - len(b.data)
+	if room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.data = append(b.data, p[:room]...)
+		}
+		return len(p), nil
+	}
+	b.data = append(b.data, p...)
+	return len(p), nil
+}
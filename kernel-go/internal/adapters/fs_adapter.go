@@ -0,0 +1,298 @@
+// WHY: File access is authority over the user's workspace. This adapter
+// resolves every path to its canonical form before checking it, so "..",
+// symlinks, and duplicate slashes cannot smuggle an operation outside
+// Limits.WorkspaceBounds, and it checks read, write, and list against
+// separate scopes ("fs:read:/ws/**", "fs:write:/ws/out/**"). Every write
+// reports the content hash so the ledger records exactly what was written.
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/cdi"
+)
+
+// Filesystem operations
+const (
+	FSRead  = "read"
+	FSWrite = "write"
+	FSList  = "list"
+)
+
+// Filesystem parameters
+const (
+	ParamOp      = "op"
+	ParamPath    = "path"
+	ParamContent = "content"
+)
+
+// Defaults for filesystem adapters
+const (
+	DefaultFSName     = "fs"
+	DefaultFSMaxBytes = 1 << 20
+)
+
+// FSConfig configures a workspace-contained filesystem adapter
+type FSConfig struct {
+	// Name is the adapter name and the scope tokens must carry (default DefaultFSName)
+	Name string
+
+	// MaxBytes caps file reads and writes (default DefaultFSMaxBytes)
+	MaxBytes int
+}
+
+// FSAdapter reads, writes, and lists files inside a token's workspace bounds
+type FSAdapter struct {
+	config FSConfig
+}
+
+// NewFSAdapter creates the adapter
+func NewFSAdapter(config FSConfig) *FSAdapter {
+	if config.Name == "" {
+		config.Name = DefaultFSName
+	}
+	if config.MaxBytes <= 0 {
+		config.MaxBytes = DefaultFSMaxBytes
+	}
+	return &FSAdapter{config: config}
+}
+
+// Name returns the adapter identifier
+func (a *FSAdapter) Name() string {
+	return a.config.Name
+}
+
+// Declare reports filesystem reads and writes
+func (a *FSAdapter) Declare() cdi.CapabilityDeclaration {
+	return cdi.CapabilityDeclaration{
+		Name:        a.config.Name,
+		Risk:        cdi.RiskHigh,
+		SideEffects: []string{cdi.SideEffectRead, cdi.SideEffectWrite},
+		Scopes:      []string{"fs:*"},
+	}
+}
+
+// VerifyToken checks token validity and adapter scope. Path checks need
+// the request, so they happen in Invoke.
+func (a *FSAdapter) VerifyToken(token *capabilities.Token, currentPosture int) error {
+	if token == nil {
+		return fmt.Errorf("nil token - tokenless invocation rejected")
+	}
+
+	valid, err := token.Verify(currentPosture)
+	if !valid {
+		return fmt.Errorf("token verification failed: %w", err)
+	}
+
+	if !token.HasScope(a.config.Name) {
+		return fmt.Errorf("token does not have scope for adapter %s", a.config.Name)
+	}
+
+	return nil
+}
+
+// Invoke performs one filesystem operation
+func (a *FSAdapter) Invoke(_ context.Context, token *capabilities.Token, params map[string]interface{}) (*AdapterResult, error) {
+	if token == nil {
+		return nil, fmt.Errorf("nil token - invoke rejected")
+	}
+
+	op, _ := params[ParamOp].(string)
+	path, _ := params[ParamPath].(string)
+	if path == "" {
+		return nil, fmt.Errorf("fs adapter %s: missing %q parameter", a.config.Name, ParamPath)
+	}
+
+	var (
+		result *AdapterResult
+		err    error
+	)
+	switch op {
+	case FSRead:
+		result, err = a.read(token, path)
+	case FSWrite:
+		content, ok := params[ParamContent].(string)
+		if !ok {
+			return nil, fmt.Errorf("fs adapter %s: write requires %q parameter", a.config.Name, ParamContent)
+		}
+		result, err = a.write(token, path, content)
+	case FSList:
+		result, err = a.list(token, path)
+	default:
+		return nil, fmt.Errorf("fs adapter %s: unknown operation %q", a.config.Name, op)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fs adapter %s: %w", a.config.Name, err)
+	}
+	return result, nil
+}
+
+func (a *FSAdapter) read(token *capabilities.Token, path string) (*AdapterResult, error) {
+	canonical, err := resolveExisting(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizePath(token, FSRead, canonical); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(canonical)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, int64(a.config.MaxBytes)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > a.config.MaxBytes {
+		return nil, fmt.Errorf("%s exceeds %d bytes", canonical, a.config.MaxBytes)
+	}
+
+	return &AdapterResult{
+		Status:      StatusSuccess,
+		Content:     string(data),
+		ContentType: http.DetectContentType(data),
+		Provenance:  Provenance{Source: canonical},
+		Details:     map[string]interface{}{"path": canonical, "content_hash": HashExchange(data)},
+	}, nil
+}
+
+func (a *FSAdapter) write(token *capabilities.Token, path string, content string) (*AdapterResult, error) {
+	if len(content) > a.config.MaxBytes {
+		return nil, fmt.Errorf("content exceeds %d bytes", a.config.MaxBytes)
+	}
+
+	canonical, err := resolveForWrite(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizePath(token, FSWrite, canonical); err != nil {
+		return nil, err
+	}
+
+	// WHY: The path was checked by name; the write goes through a handle
+	// opened without following links, so a swap since cannot redirect it
+	file, err := openForWrite(canonical)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.WriteString(content); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+
+	return &AdapterResult{
+		Status:      StatusSuccess,
+		Content:     fmt.Sprintf("wrote %d bytes to %s", len(content), canonical),
+		ContentType: ContentTypeText,
+		Provenance:  Provenance{Source: canonical},
+		Write:       &WriteCommitment{Target: canonical, ContentHash: HashExchange([]byte(content))},
+		Details:     map[string]interface{}{"path": canonical},
+	}, nil
+}
+
+func (a *FSAdapter) list(token *capabilities.Token, path string) (*AdapterResult, error) {
+	canonical, err := resolveExisting(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizePath(token, FSList, canonical); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(canonical)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			name += "/"
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return &AdapterResult{
+		Status:      StatusSuccess,
+		Content:     strings.Join(names, "\n"),
+		ContentType: ContentTypeText,
+		Provenance:  Provenance{Source: canonical},
+		Details:     map[string]interface{}{"path": canonical, "entries": names},
+	}, nil
+}
+
+// authorizePath checks a canonical path against the operation's scope and
+// the token's workspace bounds.
+// WHY: Fail closed - a token without filesystem bounds has no workspace.
+func authorizePath(token *capabilities.Token, op string, canonical string) error {
+	scope := "fs:" + op + ":" + canonical
+	if !token.HasScope(scope) {
+		return fmt.Errorf("token does not grant %s", scope)
+	}
+
+	for _, bound := range token.Limits.WorkspaceBounds {
+		if !filepath.IsAbs(bound) {
+			continue
+		}
+		root, err := filepath.EvalSymlinks(filepath.Clean(bound))
+		if err != nil {
+			continue
+		}
+		if canonical == root || strings.HasPrefix(canonical, strings.TrimSuffix(root, string(filepath.Separator))+string(filepath.Separator)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is outside the token's workspace bounds", canonical)
+}
+
+// resolveExisting returns the canonical path of an existing file or
+// directory, following every symlink
+func resolveExisting(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("path %q is not absolute", path)
+	}
+	return filepath.EvalSymlinks(filepath.Clean(path))
+}
+
+// resolveForWrite canonicalizes the parent directory and refuses to write
+// through a symlink.
+// WHY: Writing to a link would let the link's target, not the named path,
+// receive the content, which is exactly a symlink escape.
+func resolveForWrite(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("path %q is not absolute", path)
+	}
+	cleaned := filepath.Clean(path)
+
+	parent, err := filepath.EvalSymlinks(filepath.Dir(cleaned))
+	if err != nil {
+		return "", err
+	}
+	canonical := filepath.Join(parent, filepath.Base(cleaned))
+
+	info, err := os.Lstat(canonical)
+	switch {
+	case err == nil && info.Mode()&os.ModeSymlink != 0:
+		return "", fmt.Errorf("refusing to write through symlink %s", canonical)
+	case err == nil && !info.Mode().IsRegular():
+		return "", fmt.Errorf("%s is not a regular file", canonical)
+	case err != nil && !os.IsNotExist(err):
+		return "", err
+	}
+	return canonical, nil
+}
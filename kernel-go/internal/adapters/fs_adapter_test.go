@@ -0,0 +1,119 @@
+// WHY: These tests prove the filesystem adapter keeps every operation
+// inside the workspace bounds, separates read and write authority, and
+// cannot be steered out of the workspace by symlinks or "..".
+package adapters
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// fsWorkspace creates a canonical workspace root and a sibling outside it
+func fsWorkspace(t *testing.T) (workspace, outside string) {
+	t.Helper()
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("resolve temp dir: %v", err)
+	}
+	workspace = filepath.Join(root, "workspace")
+	outside = filepath.Join(root, "outside")
+	os.Mkdir(workspace, 0o700)
+	os.Mkdir(outside, 0o700)
+	os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0o600)
+	return workspace, outside
+}
+
+// mintFSToken mints a token for the fs adapter bounded to workspace
+func mintFSToken(t *testing.T, workspace string, scopes ...string) *capabilities.Token {
+	t.Helper()
+	token, err := capabilities.Mint("kernel", "test_subject", "adapters", append([]string{DefaultFSName}, scopes...),
+		capabilities.Limits{WorkspaceBounds: []string{workspace}}, time.Minute,
+		capabilities.PostureBounds{MinPosture: posture.P1, MaxPosture: posture.P4},
+		"test_namespace", "test_principal")
+	if err != nil {
+		t.Fatalf("mint: %v", err)
+	}
+	return token
+}
+
+// TestFSAdapterSeparatesReadAndWrite proves write authority is separate
+// from read authority and writes report their content hash
+func TestFSAdapterSeparatesReadAndWrite(t *testing.T) {
+	workspace, _ := fsWorkspace(t)
+	adapter := NewFSAdapter(FSConfig{})
+	path := filepath.Join(workspace, "notes.txt")
+
+	reader := mintFSToken(t, workspace, "fs:read:"+workspace+"/**", "fs:list:"+workspace)
+	if _, err := adapter.Invoke(context.Background(), reader, map[string]interface{}{ParamOp: FSWrite, ParamPath: path, ParamContent: "x"}); err == nil {
+		t.Fatal("read-only token must not write")
+	}
+
+	writer := mintFSToken(t, workspace, "fs:write:"+workspace+"/**")
+	result, err := adapter.Invoke(context.Background(), writer, map[string]interface{}{ParamOp: FSWrite, ParamPath: path, ParamContent: "hello"})
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if result.Write == nil || result.Write.Target != path || result.Write.ContentHash != HashExchange([]byte("hello")) {
+		t.Fatalf("write must report target and content hash, got %+v", result.Write)
+	}
+	if _, err := adapter.Invoke(context.Background(), writer, map[string]interface{}{ParamOp: FSRead, ParamPath: path}); err == nil {
+		t.Fatal("write-only token must not read")
+	}
+
+	result, err = adapter.Invoke(context.Background(), reader, map[string]interface{}{ParamOp: FSRead, ParamPath: path})
+	if err != nil || result.Content != "hello" {
+		t.Fatalf("read: %v", err)
+	}
+	result, err = adapter.Invoke(context.Background(), reader, map[string]interface{}{ParamOp: FSList, ParamPath: workspace})
+	if err != nil || result.Content != "notes.txt" {
+		t.Fatalf("list: %v %v", result, err)
+	}
+}
+
+// TestFSAdapterDeniesEscapes proves "..", symlinks, and missing bounds
+// cannot reach files outside the workspace
+func TestFSAdapterDeniesEscapes(t *testing.T) {
+	workspace, outside := fsWorkspace(t)
+	adapter := NewFSAdapter(FSConfig{})
+
+	// A scope broad enough to cover everything leaves only the bounds to enforce containment
+	token := mintFSToken(t, workspace, "fs:*")
+
+	os.Symlink(filepath.Join(outside, "secret.txt"), filepath.Join(workspace, "link.txt"))
+	os.Symlink(outside, filepath.Join(workspace, "linkdir"))
+
+	attempts := []map[string]interface{}{
+		{ParamOp: FSRead, ParamPath: filepath.Join(workspace, "..", "outside", "secret.txt")},
+		{ParamOp: FSRead, ParamPath: filepath.Join(workspace, "link.txt")},
+		{ParamOp: FSList, ParamPath: filepath.Join(workspace, "linkdir")},
+		{ParamOp: FSWrite, ParamPath: filepath.Join(workspace, "link.txt"), ParamContent: "overwrite"},
+		{ParamOp: FSWrite, ParamPath: filepath.Join(workspace, "linkdir", "new.txt"), ParamContent: "escape"},
+		{ParamOp: FSRead, ParamPath: "workspace/relative.txt"},
+	}
+	for _, params := range attempts {
+		if _, err := adapter.Invoke(context.Background(), token, params); err == nil {
+			t.Fatalf("escape must be refused: %v", params)
+		}
+	}
+
+	if data, _ := os.ReadFile(filepath.Join(outside, "secret.txt")); string(data) != "secret" {
+		t.Fatal("file outside the workspace was modified")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "new.txt")); err == nil {
+		t.Fatal("file created outside the workspace")
+	}
+
+	unbounded, _ := capabilities.Mint("kernel", "test_subject", "adapters", []string{DefaultFSName, "fs:*"},
+		capabilities.Limits{}, time.Minute,
+		capabilities.PostureBounds{MinPosture: posture.P1, MaxPosture: posture.P4},
+		"test_namespace", "test_principal")
+	if _, err := adapter.Invoke(context.Background(), unbounded, map[string]interface{}{ParamOp: FSList, ParamPath: workspace}); err == nil {
+		t.Fatal("token without workspace bounds must be refused")
+	}
+}
@@ -0,0 +1,73 @@
+// WHY: A path checked and then opened by name can be swapped in between:
+// a directory renamed away and replaced by a symlink, or the file itself
+// replaced by one, and the write lands outside the workspace. On Linux
+// the write walks down from / one directory at a time with openat and
+// O_NOFOLLOW, so every component is the one checked or the write fails,
+// and the file is written through the handle that was opened.
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// openForWrite opens the canonical path for writing, creating it, without
+// following a symlink anywhere along it
+func openForWrite(canonical string) (*os.File, error) {
+	dir, err := openDirNoFollow(filepath.Dir(canonical))
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.Close(dir)
+
+	// WHY: O_NONBLOCK keeps a FIFO swapped in from blocking the open; the
+	// file is checked to be regular before it is truncated
+	fd, err := syscall.Openat(dir, filepath.Base(canonical), syscall.O_WRONLY|syscall.O_CREAT|syscall.O_NOFOLLOW|syscall.O_NONBLOCK|syscall.O_CLOEXEC, 0o600)
+	if errors.Is(err, syscall.ELOOP) {
+		return nil, fmt.Errorf("refusing to write through symlink %s", canonical)
+	}
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: canonical, Err: err}
+	}
+	file := os.NewFile(uintptr(fd), canonical)
+	info, err := file.Stat()
+	if err == nil && !info.Mode().IsRegular() {
+		err = fmt.Errorf("%s is not a regular file", canonical)
+	}
+	if err == nil {
+		err = file.Truncate(0)
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+// openDirNoFollow opens the canonical directory dir, refusing a symlink
+// at any component
+func openDirNoFollow(dir string) (int, error) {
+	fd, err := syscall.Open("/", syscall.O_RDONLY|syscall.O_DIRECTORY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return -1, &os.PathError{Op: "open", Path: "/", Err: err}
+	}
+	for _, name := range strings.Split(strings.Trim(dir, "/"), "/") {
+		if name == "" {
+			continue
+		}
+		next, err := syscall.Openat(fd, name, syscall.O_RDONLY|syscall.O_DIRECTORY|syscall.O_NOFOLLOW|syscall.O_CLOEXEC, 0)
+		syscall.Close(fd)
+		if errors.Is(err, syscall.ELOOP) || errors.Is(err, syscall.ENOTDIR) {
+			return -1, fmt.Errorf("%s changed under the write: a component is no longer a directory", dir)
+		}
+		if err != nil {
+			return -1, &os.PathError{Op: "open", Path: dir, Err: err}
+		}
+		fd = next
+	}
+	return fd, nil
+}
@@ -0,0 +1,70 @@
+// WHY: These tests prove a write checked by name cannot be redirected by a
+// symlink swapped in before it opens, at the file or at a directory.
+package adapters
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestOpenForWriteRefusesSwappedLinks proves the open itself refuses a
+// symlink that replaced the file or a directory after the path was checked
+func TestOpenForWriteRefusesSwappedLinks(t *testing.T) {
+	workspace, outside := fsWorkspace(t)
+	target := filepath.Join(outside, "secret.txt")
+	if err := os.WriteFile(target, []byte("untouched"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The file is swapped for a link after resolveForWrite checked it
+	out := filepath.Join(workspace, "out.txt")
+	checked, err := resolveForWrite(out)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if err := os.Symlink(target, out); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := openForWrite(checked); err == nil || !strings.Contains(err.Error(), "symlink") {
+		t.Fatalf("a swapped-in link to the file must be refused, got %v", err)
+	}
+
+	// A directory is renamed away and replaced by a link outside
+	dir := filepath.Join(workspace, "reports")
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	checked, err = resolveForWrite(filepath.Join(dir, "secret.txt"))
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if err := os.Rename(dir, dir+".old"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := openForWrite(checked); err == nil {
+		t.Fatal("a directory swapped for a link must be refused")
+	}
+
+	if data, _ := os.ReadFile(target); string(data) != "untouched" {
+		t.Fatalf("the file outside the workspace was written: %q", data)
+	}
+
+	// An ordinary write goes through and replaces what was there
+	file, err := openForWrite(filepath.Join(workspace, "plain.txt"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	file.WriteString("first write, longer")
+	file.Close()
+	file, _ = openForWrite(filepath.Join(workspace, "plain.txt"))
+	file.WriteString("second")
+	file.Close()
+	if data, _ := os.ReadFile(filepath.Join(workspace, "plain.txt")); string(data) != "second" {
+		t.Fatalf("a write must replace the file's content, got %q", data)
+	}
+}
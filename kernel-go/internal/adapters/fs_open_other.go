@@ -0,0 +1,21 @@
+//go:build !linux
+
+package adapters
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// openForWrite opens the canonical path for writing, creating it, inside
+// its parent directory.
+// WHY: Without openat walking, os.Root still keeps the open inside the
+// directory that was checked, and refuses a link leading out of it.
+func openForWrite(canonical string) (*os.File, error) {
+	root, err := os.OpenRoot(filepath.Dir(canonical))
+	if err != nil {
+		return nil, err
+	}
+	defer root.Close()
+	return root.OpenFile(filepath.Base(canonical), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+}
@@ -0,0 +1,322 @@
+// WHY: Fetched web content is the classic carrier for injected
+// instructions. This adapter lets a token reach only the methods and hosts
+// its scope names ("net:get:example.com") inside any URL workspace bounds,
+// re-checks every redirect, and never hands the body back: the body goes to
+// the quarantine partition with CIF labels attached, and callers get a
+// reference. Content cannot become authority without a promotion ritual.
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/cdi"
+	"github.com/user/oi/kernel-go/internal/cif"
+	"github.com/user/oi/kernel-go/internal/memory"
+)
+
+// Defaults for HTTP fetch adapters
+const (
+	DefaultHTTPFetchName    = "http_fetch"
+	DefaultHTTPFetchTimeout = 15 * time.Second
+
+	// DefaultHTTPFetchMaxBytes matches the CIF ingress size limit, since
+	// every body is labeled by CIF
+	DefaultHTTPFetchMaxBytes = 100 * 1024
+
+	// maxFetchRedirects bounds redirect chains; each hop is re-authorized
+	maxFetchRedirects = 5
+)
+
+// Fetch parameters
+const (
+	ParamURL    = "url"
+	ParamMethod = "method"
+	ParamBody   = "body"
+)
+
+// LabelExternalFetch marks content that arrived from the network
+const LabelExternalFetch = "external_fetch"
+
+// fetchMethods are the HTTP methods the adapter supports at all
+var fetchMethods = map[string]bool{
+	http.MethodGet:  true,
+	http.MethodHead: true,
+	http.MethodPost: true,
+}
+
+// HTTPFetchConfig configures a governed HTTP fetch adapter
+type HTTPFetchConfig struct {
+	// Name is the adapter name and the scope tokens must carry (default DefaultHTTPFetchName)
+	Name string
+
+	// Memory receives response bodies in its quarantine partition
+	Memory *memory.Manager
+
+	// Timeout bounds each fetch including redirects (default DefaultHTTPFetchTimeout)
+	Timeout time.Duration
+
+	// MaxBodyBytes caps response bodies (default DefaultHTTPFetchMaxBytes)
+	MaxBodyBytes int
+
+	// Transport overrides the HTTP transport, mainly for tests
+	Transport http.RoundTripper
+}
+
+// HTTPFetchAdapter performs scope-bounded HTTP requests
+type HTTPFetchAdapter struct {
+	config HTTPFetchConfig
+}
+
+// NewHTTPFetchAdapter validates the configuration and creates the adapter
+func NewHTTPFetchAdapter(config HTTPFetchConfig) (*HTTPFetchAdapter, error) {
+	if config.Name == "" {
+		config.Name = DefaultHTTPFetchName
+	}
+	if config.Memory == nil {
+		return nil, fmt.Errorf("http fetch adapter %s: no memory manager for quarantine", config.Name)
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultHTTPFetchTimeout
+	}
+	if config.MaxBodyBytes <= 0 || config.MaxBodyBytes > DefaultHTTPFetchMaxBytes {
+		config.MaxBodyBytes = DefaultHTTPFetchMaxBytes
+	}
+	return &HTTPFetchAdapter{config: config}, nil
+}
+
+// Name returns the adapter identifier
+func (a *HTTPFetchAdapter) Name() string {
+	return a.config.Name
+}
+
+// Declare reports network reads and, because POST is supported, writes
+func (a *HTTPFetchAdapter) Declare() cdi.CapabilityDeclaration {
+	return cdi.CapabilityDeclaration{
+		Name:        a.config.Name,
+		Risk:        cdi.RiskMedium,
+		SideEffects: []string{cdi.SideEffectNetwork, cdi.SideEffectRead, cdi.SideEffectWrite},
+		Scopes:      []string{"net:*"},
+	}
+}
+
+// VerifyToken checks token validity and adapter scope. Destination checks
+// need the request, so they happen in Invoke.
+func (a *HTTPFetchAdapter) VerifyToken(token *capabilities.Token, currentPosture int) error {
+	if token == nil {
+		return fmt.Errorf("nil token - tokenless invocation rejected")
+	}
+
+	valid, err := token.Verify(currentPosture)
+	if !valid {
+		return fmt.Errorf("token verification failed: %w", err)
+	}
+
+	if !token.HasScope(a.config.Name) {
+		return fmt.Errorf("token does not have scope for adapter %s", a.config.Name)
+	}
+
+	return nil
+}
+
+// FetchScope returns the scope a request needs: "net:<method>:<host>"
+func FetchScope(method string, target *url.URL) string {
+	return "net:" + strings.ToLower(method) + ":" + strings.ToLower(target.Hostname())
+}
+
+// authorize checks a destination against the token's scope and URL bounds
+func (a *HTTPFetchAdapter) authorize(token *capabilities.Token, method string, target *url.URL) error {
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return fmt.Errorf("scheme %q not permitted", target.Scheme)
+	}
+	if target.User != nil {
+		return fmt.Errorf("credentials in URL not permitted")
+	}
+	if !fetchMethods[method] {
+		return fmt.Errorf("method %s not supported", method)
+	}
+
+	scope := FetchScope(method, target)
+	if !token.HasScope(scope) {
+		return fmt.Errorf("token does not grant %s", scope)
+	}
+	if !withinURLBounds(token.Limits.WorkspaceBounds, target) {
+		return fmt.Errorf("%s is outside the token's workspace bounds", redactURL(target))
+	}
+	return nil
+}
+
+// withinURLBounds reports whether target lies under one of the http(s)
+// workspace bounds. WHY: Bounds narrow a scope; tokens whose bounds name
+// no URLs (filesystem roots only, or none) are limited by scope alone.
+func withinURLBounds(bounds []string, target *url.URL) bool {
+	sawURLBound := false
+	for _, bound := range bounds {
+		root, err := url.Parse(bound)
+		if err != nil || (root.Scheme != "http" && root.Scheme != "https") {
+			continue
+		}
+		sawURLBound = true
+
+		if root.Scheme != target.Scheme || !strings.EqualFold(root.Host, target.Host) {
+			continue
+		}
+		if pathWithin(root.Path, target.Path) {
+			return true
+		}
+	}
+	return !sawURLBound
+}
+
+// pathWithin reports whether path equals root or lies beneath it,
+// comparing whole elements so "/api" does not cover "/apix"
+func pathWithin(root, path string) bool {
+	root = strings.TrimRight(root, "/")
+	if root == "" {
+		return true
+	}
+	for _, element := range strings.Split(path, "/") {
+		if element == "." || element == ".." {
+			return false
+		}
+	}
+	return path == root || strings.HasPrefix(path, root+"/")
+}
+
+// redactURL drops the query, which may carry secrets, from error messages
+func redactURL(target *url.URL) string {
+	return target.Scheme + "://" + target.Host + target.Path
+}
+
+// Invoke performs the request and quarantines the response body
+func (a *HTTPFetchAdapter) Invoke(ctx context.Context, token *capabilities.Token, params map[string]interface{}) (*AdapterResult, error) {
+	if token == nil {
+		return nil, fmt.Errorf("nil token - invoke rejected")
+	}
+
+	rawURL, ok := params[ParamURL].(string)
+	if !ok || rawURL == "" {
+		return nil, fmt.Errorf("http fetch adapter %s: missing %q parameter", a.config.Name, ParamURL)
+	}
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("http fetch adapter %s: invalid URL: %w", a.config.Name, err)
+	}
+	method := http.MethodGet
+	if requested, ok := params[ParamMethod].(string); ok && requested != "" {
+		method = strings.ToUpper(requested)
+	}
+	body, _ := params[ParamBody].(string)
+	if body != "" && method != http.MethodPost {
+		return nil, fmt.Errorf("http fetch adapter %s: %s requests carry no body", a.config.Name, method)
+	}
+
+	if err := a.authorize(token, method, target); err != nil {
+		return nil, fmt.Errorf("http fetch adapter %s: %w", a.config.Name, err)
+	}
+
+	client := &http.Client{
+		Transport: a.config.Transport,
+		// WHY: A redirect is a new destination and needs its own authorization
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxFetchRedirects {
+				return fmt.Errorf("more than %d redirects", maxFetchRedirects)
+			}
+			return a.authorize(token, req.Method, req.URL)
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, a.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), strings.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("http fetch adapter %s: %w", a.config.Name, err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("http fetch adapter %s: timed out after %s", a.config.Name, a.config.Timeout)
+		}
+		return nil, fmt.Errorf("http fetch adapter %s: %w", a.config.Name, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, int64(a.config.MaxBodyBytes)+1))
+	if err != nil {
+		return nil, fmt.Errorf("http fetch adapter %s: read response: %w", a.config.Name, err)
+	}
+	if len(respBody) > a.config.MaxBodyBytes {
+		return nil, fmt.Errorf("http fetch adapter %s: response exceeds %d bytes", a.config.Name, a.config.MaxBodyBytes)
+	}
+
+	finalURL := redactURL(resp.Request.URL)
+	result := &AdapterResult{
+		Status:      StatusSuccess,
+		ContentType: ContentTypeText,
+		Provenance:  Provenance{Source: finalURL},
+		Exchange:    exchangeOf([]byte(method+" "+target.String()+"\n\n"+body), respBody),
+		Details: map[string]interface{}{
+			"http_status":    resp.StatusCode,
+			"url":            finalURL,
+			"content_type":   resp.Header.Get("Content-Type"),
+			"content_length": len(respBody),
+		},
+	}
+	if len(bytes.TrimSpace(respBody)) == 0 {
+		result.Content = fmt.Sprintf("fetched %s: HTTP %d, empty body", finalURL, resp.StatusCode)
+		return result, nil
+	}
+
+	entryID, labels, err := a.quarantine(token, finalURL, resp, respBody)
+	if err != nil {
+		return nil, fmt.Errorf("http fetch adapter %s: %w", a.config.Name, err)
+	}
+	result.Details["quarantine_id"] = entryID
+	result.Provenance.TaintLabels = labels
+	result.Content = fmt.Sprintf("fetched %s: HTTP %d, %d bytes quarantined as %s", finalURL, resp.StatusCode, len(respBody), entryID)
+	return result, nil
+}
+
+// quarantine labels the body through CIF ingress and writes it to the
+// quarantine partition, returning the entry ID and labels
+func (a *HTTPFetchAdapter) quarantine(token *capabilities.Token, finalURL string, resp *http.Response, body []byte) (string, []string, error) {
+	labeled, err := cif.Ingress(string(body), map[string]interface{}{
+		"source": a.config.Name,
+		"url":    finalURL,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("label response: %w", err)
+	}
+	labels := append(append([]string(nil), labeled.TaintLabels...), LabelExternalFetch)
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", nil, fmt.Errorf("quarantine entry ID: %w", err)
+	}
+	entryID := a.config.Name + ":" + hex.EncodeToString(id)
+
+	err = a.config.Memory.Namespace(token.NamespaceID).WriteAs(token.PrincipalID, memory.PartitionQuarantine, entryID, labeled.SanitizedInput, map[string]interface{}{
+		"source":       a.config.Name,
+		"url":          finalURL,
+		"http_status":  resp.StatusCode,
+		"content_type": resp.Header.Get("Content-Type"),
+		"taint_labels": labels,
+		"input_hash":   labeled.InputHash,
+		"token_digest": token.Digest,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("quarantine response: %w", err)
+	}
+	return entryID, labels, nil
+}
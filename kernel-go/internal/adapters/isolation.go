@@ -0,0 +1,273 @@
+// WHY: Scope checks decide what an adapter is asked to do; they cannot
+// stop a compromised adapter process from doing something else. An
+// isolation profile limits what the process can do at all: resource
+// limits, a cgroup the operator sized, and a seccomp filter that refuses
+// administrative syscalls and, unless the adapter declares network access,
+// every socket but a local one. The profile follows from the adapter's
+// declared risk, so the riskiest adapters get the tightest box.
+//
+// Isolation applies to adapters that run as processes (exec and plugin);
+// an in-process adapter such as http_fetch is isolated by hosting it as a
+// plugin. The filter and limits are installed by a launcher: the kernel
+// binary re-executed with IsolationLauncherArg, which applies them to
+// itself and then execs the command, so nothing runs unconfined.
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/user/oi/kernel-go/internal/cdi"
+)
+
+// Isolation profile names
+const (
+	IsolationBaseline   = "baseline"   // low risk: resource limits
+	IsolationRestricted = "restricted" // medium risk: plus cgroup and seccomp
+	IsolationConfined   = "confined"   // high risk: tighter limits throughout
+	IsolationAuto       = "auto"       // manifests: choose from the declared risk
+)
+
+// IsolationLauncherArg is the first argument that makes RunIsolationLauncher
+// act as the launcher
+const IsolationLauncherArg = "__oi_isolate"
+
+// IsolationProfile is what an isolated adapter process may use
+type IsolationProfile struct {
+	Name string
+
+	// Resource limits set before the command starts; zero leaves a limit unset
+	CPUSeconds  int
+	MemoryBytes int64
+	FileBytes   int64
+	OpenFiles   int
+
+	// Cgroup, if set, places each process in its own cgroup v2 group
+	Cgroup *CgroupLimits
+
+	// DenySyscalls are refused with EPERM by a seccomp filter
+	DenySyscalls []string
+
+	// DenyNetwork refuses every socket except AF_UNIX
+	DenyNetwork bool
+}
+
+// CgroupLimits are written to the process's cgroup; zero leaves a limit unset
+type CgroupLimits struct {
+	MemoryMax int64   // bytes, memory.max
+	CPUMax    float64 // CPUs, cpu.max
+	PidsMax   int     // processes and threads, pids.max
+}
+
+// adminSyscalls are refused from restricted upward.
+// WHY: No adapter needs to load kernel code, change mounts or namespaces,
+// trace or read other processes, or touch the keyring; each is a step
+// from a compromised adapter to a compromised host.
+var adminSyscalls = []string{
+	"acct", "add_key", "bpf", "chroot", "delete_module", "finit_module",
+	"init_module", "kexec_file_load", "kexec_load", "keyctl", "mount",
+	"open_by_handle_at", "perf_event_open", "pivot_root", "process_vm_readv",
+	"process_vm_writev", "ptrace", "reboot", "request_key", "setns",
+	"swapoff", "swapon", "umount2", "unshare", "userfaultfd",
+}
+
+// IsolationProfileNamed returns a built-in profile. Network is allowed;
+// IsolationProfileFor decides it from the declaration.
+func IsolationProfileNamed(name string) (IsolationProfile, error) {
+	switch name {
+	case IsolationBaseline:
+		return IsolationProfile{
+			Name:        IsolationBaseline,
+			MemoryBytes: 4 << 30,
+			OpenFiles:   1024,
+		}, nil
+	case IsolationRestricted:
+		return IsolationProfile{
+			Name:         IsolationRestricted,
+			MemoryBytes:  2 << 30,
+			FileBytes:    1 << 30,
+			OpenFiles:    256,
+			Cgroup:       &CgroupLimits{MemoryMax: 1 << 30, CPUMax: 1, PidsMax: 64},
+			DenySyscalls: adminSyscalls,
+		}, nil
+	case IsolationConfined:
+		return IsolationProfile{
+			Name:         IsolationConfined,
+			CPUSeconds:   300,
+			MemoryBytes:  1 << 30,
+			FileBytes:    64 << 20,
+			OpenFiles:    64,
+			Cgroup:       &CgroupLimits{MemoryMax: 512 << 20, CPUMax: 0.5, PidsMax: 32},
+			DenySyscalls: adminSyscalls,
+		}, nil
+	default:
+		return IsolationProfile{}, fmt.Errorf("unknown isolation profile %q", name)
+	}
+}
+
+// IsolationProfileFor chooses the profile for a declaration: baseline for
+// low risk, restricted for medium, confined for high
+func IsolationProfileFor(declaration cdi.CapabilityDeclaration) IsolationProfile {
+	name := IsolationConfined
+	switch declaration.Risk {
+	case cdi.RiskLow:
+		name = IsolationBaseline
+	case cdi.RiskMedium:
+		name = IsolationRestricted
+	}
+	profile, _ := isolationProfile(name, declaration)
+	return profile
+}
+
+// isolationProfile returns the named profile, or the declaration's with
+// IsolationAuto. Above baseline, network is denied unless the adapter
+// declares it.
+func isolationProfile(name string, declaration cdi.CapabilityDeclaration) (IsolationProfile, error) {
+	if name == IsolationAuto {
+		return IsolationProfileFor(declaration), nil
+	}
+	profile, err := IsolationProfileNamed(name)
+	if err != nil {
+		return IsolationProfile{}, err
+	}
+	if name != IsolationBaseline {
+		profile.DenyNetwork = !declares(declaration, cdi.SideEffectNetwork)
+	}
+	return profile, nil
+}
+
+// declares reports whether the declaration lists a side effect
+func declares(declaration cdi.CapabilityDeclaration, effect string) bool {
+	for _, declared := range declaration.SideEffects {
+		if declared == effect {
+			return true
+		}
+	}
+	return false
+}
+
+// Isolation runs an adapter's processes under a profile
+type Isolation struct {
+	Profile IsolationProfile
+
+	// Launcher is the command that runs RunIsolationLauncher on its
+	// arguments (default: the running executable)
+	Launcher []string
+
+	// CgroupRoot is a delegated cgroup v2 directory; each process gets a
+	// group beneath it. Required when the profile sets Cgroup.
+	CgroupRoot string
+}
+
+// launchPolicy is what the launcher applies to itself before exec
+type launchPolicy struct {
+	Limits       rlimits
+	DenySyscalls []string `json:",omitempty"`
+	DenyNetwork  bool     `json:",omitempty"`
+}
+
+// validate checks the isolation can be applied on this host.
+// WHY: A profile that cannot be enforced must stop the adapter from
+// starting, not let it run unconfined.
+func (iso *Isolation) validate() error {
+	if err := isolationAvailable(); err != nil {
+		return err
+	}
+	if iso.Profile.Cgroup != nil && iso.CgroupRoot == "" {
+		return fmt.Errorf("isolation profile %s needs a cgroup root", iso.Profile.Name)
+	}
+	if iso.CgroupRoot != "" {
+		if err := checkCgroupRoot(iso.CgroupRoot); err != nil {
+			return fmt.Errorf("isolation profile %s: %w", iso.Profile.Name, err)
+		}
+	}
+	if err := checkSeccomp(iso.Profile.DenySyscalls, iso.Profile.DenyNetwork); err != nil {
+		return fmt.Errorf("isolation profile %s: %w", iso.Profile.Name, err)
+	}
+	return nil
+}
+
+// command returns a command running executable under the isolation and a
+// cleanup to call once it has exited. The adapter's own limits apply too;
+// where both set one, the tighter wins.
+func (iso *Isolation) command(ctx context.Context, name string, executable string, args []string, own rlimits) (*exec.Cmd, func(), error) {
+	policy, err := json.Marshal(launchPolicy{
+		Limits:       tighterLimits(own, iso.Profile.limits()),
+		DenySyscalls: iso.Profile.DenySyscalls,
+		DenyNetwork:  iso.Profile.DenyNetwork,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	launcher := iso.Launcher
+	if len(launcher) == 0 {
+		self, err := os.Executable()
+		if err != nil {
+			return nil, nil, fmt.Errorf("isolation launcher: %w", err)
+		}
+		launcher = []string{self}
+	}
+	argv := append(append([]string{}, launcher[1:]...), IsolationLauncherArg, string(policy), executable)
+	cmd := exec.CommandContext(ctx, launcher[0], append(argv, args...)...)
+
+	cleanup := func() {}
+	if iso.Profile.Cgroup != nil {
+		cleanup, err = placeInCgroup(cmd, iso.CgroupRoot, name, *iso.Profile.Cgroup)
+		if err != nil {
+			return nil, nil, fmt.Errorf("isolation profile %s: %w", iso.Profile.Name, err)
+		}
+	}
+	return cmd, cleanup, nil
+}
+
+// limits returns the profile's resource limits
+func (p IsolationProfile) limits() rlimits {
+	return rlimits{CPUSeconds: p.CPUSeconds, MemoryBytes: p.MemoryBytes, FileBytes: p.FileBytes, OpenFiles: p.OpenFiles}
+}
+
+// tighterLimits takes each limit from whichever of a and b is smaller and set
+func tighterLimits(a, b rlimits) rlimits {
+	return rlimits{
+		CPUSeconds:  int(tighter(int64(a.CPUSeconds), int64(b.CPUSeconds))),
+		MemoryBytes: tighter(a.MemoryBytes, b.MemoryBytes),
+		FileBytes:   tighter(a.FileBytes, b.FileBytes),
+		OpenFiles:   int(tighter(int64(a.OpenFiles), int64(b.OpenFiles))),
+	}
+}
+
+func tighter(a, b int64) int64 {
+	if a <= 0 || (b > 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// RunIsolationLauncher acts as the launcher when args start with
+// IsolationLauncherArg and otherwise returns at once. Binaries that host
+// isolated adapters call it first thing in main with os.Args[1:]. As the
+// launcher it applies the policy, execs the command, and never returns;
+// if the policy cannot be applied it exits without running the command.
+func RunIsolationLauncher(args []string) {
+	if len(args) == 0 || args[0] != IsolationLauncherArg {
+		return
+	}
+	if len(args) < 3 {
+		fmt.Fprintln(os.Stderr, "oi isolation: usage: "+IsolationLauncherArg+" POLICY COMMAND [ARG...]")
+		os.Exit(126)
+	}
+	var policy launchPolicy
+	decoder := json.NewDecoder(strings.NewReader(args[1]))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&policy); err != nil {
+		fmt.Fprintf(os.Stderr, "oi isolation: policy: %v\n", err)
+		os.Exit(126)
+	}
+	err := launch(policy, args[2], args[2:])
+	fmt.Fprintf(os.Stderr, "oi isolation: %v\n", err)
+	os.Exit(126)
+}
@@ -0,0 +1,236 @@
+// WHY: rlimits, no_new_privs, and seccomp filters are inherited across
+// exec, so the launcher installs them on itself and then becomes the
+// command. A cgroup is joined at clone time through CgroupFD, so the
+// process never runs outside it, and on exit cgroup.kill takes any
+// children it left behind.
+package adapters
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// Linux constants the syscall package does not export
+const (
+	prSetNoNewPrivs     = 38
+	seccompModeFilter   = 2
+	cgroup2SuperMagic   = 0x63677270
+	seccompRetKill      = 0x80000000 // SECCOMP_RET_KILL_PROCESS
+	seccompRetErrno     = 0x00050000
+	seccompRetAllow     = 0x7fff0000
+	seccompDataNr       = 0    // offsetof(struct seccomp_data, nr)
+	seccompDataArch     = 4    // offsetof(struct seccomp_data, arch)
+	seccompDataArg0     = 16   // low word of args[0] on little-endian arches
+	bpfLoadWord         = 0x20 // BPF_LD | BPF_W | BPF_ABS
+	bpfJumpEqual        = 0x15 // BPF_JMP | BPF_JEQ | BPF_K
+	bpfJumpGreaterEqual = 0x35 // BPF_JMP | BPF_JGE | BPF_K
+	bpfReturn           = 0x06 // BPF_RET | BPF_K
+)
+
+// sockFilter is one classic BPF instruction (struct sock_filter)
+type sockFilter struct {
+	Code uint16
+	Jt   uint8
+	Jf   uint8
+	K    uint32
+}
+
+// sockFprog is a BPF program (struct sock_fprog)
+type sockFprog struct {
+	Len    uint16
+	Filter *sockFilter
+}
+
+// networkSyscalls can open a non-local socket. WHY: io_uring can create
+// sockets without calling socket(2), so it is refused outright.
+var networkSyscalls = []string{"io_uring_setup"}
+
+// isolationAvailable reports whether isolation can run on this host
+func isolationAvailable() error {
+	return nil
+}
+
+// checkSeccomp checks a filter can be built for the policy
+func checkSeccomp(deny []string, denyNetwork bool) error {
+	_, err := seccompFilter(deny, denyNetwork)
+	return err
+}
+
+// seccompFilter builds a filter that kills foreign-architecture calls,
+// refuses the denied syscalls with EPERM, and, if denyNetwork, refuses
+// sockets outside AF_UNIX. It returns nil when there is nothing to deny.
+func seccompFilter(deny []string, denyNetwork bool) ([]sockFilter, error) {
+	if len(deny) == 0 && !denyNetwork {
+		return nil, nil
+	}
+	if syscallNumbers == nil {
+		return nil, fmt.Errorf("seccomp filters are not supported on %s", runtime.GOARCH)
+	}
+	if denyNetwork {
+		deny = append(append([]string{}, deny...), networkSyscalls...)
+	}
+
+	refuse := sockFilter{Code: bpfReturn, K: seccompRetErrno | uint32(syscall.EPERM)}
+	allow := sockFilter{Code: bpfReturn, K: seccompRetAllow}
+	filter := []sockFilter{
+		{Code: bpfLoadWord, K: seccompDataArch},
+		{Code: bpfJumpEqual, Jt: 1, K: auditArch},
+		{Code: bpfReturn, K: seccompRetKill},
+		{Code: bpfLoadWord, K: seccompDataNr},
+	}
+	if x32SyscallBit != 0 {
+		// WHY: x32 calls reach the same kernel entry points under other numbers
+		filter = append(filter, sockFilter{Code: bpfJumpGreaterEqual, Jf: 1, K: x32SyscallBit}, refuse)
+	}
+	for _, name := range deny {
+		number, ok := syscallNumbers[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown syscall %q", name)
+		}
+		filter = append(filter, sockFilter{Code: bpfJumpEqual, Jf: 1, K: number}, refuse)
+	}
+	if denyNetwork {
+		filter = append(filter,
+			sockFilter{Code: bpfJumpEqual, Jf: 3, K: syscallNumbers["socket"]},
+			sockFilter{Code: bpfLoadWord, K: seccompDataArg0},
+			sockFilter{Code: bpfJumpEqual, Jt: 1, K: syscall.AF_UNIX},
+			refuse,
+		)
+	}
+	return append(filter, allow), nil
+}
+
+// launch applies the policy to this process and execs the command
+func launch(policy launchPolicy, executable string, argv []string) error {
+	filter, err := seccompFilter(policy.DenySyscalls, policy.DenyNetwork)
+	if err != nil {
+		return err
+	}
+
+	// WHY: A seccomp filter attaches to the calling thread, and exec must
+	// happen on that same thread to carry it over
+	runtime.LockOSThread()
+	if err := policy.Limits.apply(); err != nil {
+		return err
+	}
+	if _, _, errno := syscall.RawSyscall6(syscall.SYS_PRCTL, prSetNoNewPrivs, 1, 0, 0, 0, 0); errno != 0 {
+		return fmt.Errorf("no_new_privs: %w", errno)
+	}
+	if filter != nil {
+		program := sockFprog{Len: uint16(len(filter)), Filter: &filter[0]}
+		if _, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, syscall.PR_SET_SECCOMP, seccompModeFilter, uintptr(unsafe.Pointer(&program))); errno != 0 {
+			return fmt.Errorf("seccomp: %w", errno)
+		}
+	}
+	return syscall.Exec(executable, argv, os.Environ())
+}
+
+// apply sets the limits on this process, soft and hard alike
+func (l rlimits) apply() error {
+	set := func(resource int, value uint64) error {
+		if err := syscall.Setrlimit(resource, &syscall.Rlimit{Cur: value, Max: value}); err != nil {
+			return fmt.Errorf("rlimit %d: %w", resource, err)
+		}
+		return nil
+	}
+	if l.CPUSeconds > 0 {
+		if err := set(syscall.RLIMIT_CPU, uint64(l.CPUSeconds)); err != nil {
+			return err
+		}
+	}
+	if l.MemoryBytes > 0 {
+		if err := set(syscall.RLIMIT_AS, uint64(l.MemoryBytes)); err != nil {
+			return err
+		}
+	}
+	if l.FileBytes > 0 {
+		if err := set(syscall.RLIMIT_FSIZE, uint64(l.FileBytes)); err != nil {
+			return err
+		}
+	}
+	if l.OpenFiles > 0 {
+		if err := set(syscall.RLIMIT_NOFILE, uint64(l.OpenFiles)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkCgroupRoot checks root is a cgroup v2 directory
+func checkCgroupRoot(root string) error {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(root, &stat); err != nil {
+		return fmt.Errorf("cgroup root: %w", err)
+	}
+	if stat.Type != cgroup2SuperMagic {
+		return fmt.Errorf("cgroup root %s is not a cgroup v2 directory", root)
+	}
+	return nil
+}
+
+// placeInCgroup creates a cgroup under root with the limits and arranges
+// for cmd to start in it. The cleanup kills anything left in the group
+// and removes it.
+func placeInCgroup(cmd *exec.Cmd, root string, name string, limits CgroupLimits) (func(), error) {
+	if err := checkCgroupRoot(root); err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp(root, "oi-"+cgroupName(name)+"-")
+	if err != nil {
+		return nil, fmt.Errorf("cgroup: %w", err)
+	}
+
+	settings := map[string]string{}
+	if limits.MemoryMax > 0 {
+		settings["memory.max"] = strconv.FormatInt(limits.MemoryMax, 10)
+	}
+	if limits.CPUMax > 0 {
+		const period = 100000
+		settings["cpu.max"] = fmt.Sprintf("%d %d", int64(limits.CPUMax*period), period)
+	}
+	if limits.PidsMax > 0 {
+		settings["pids.max"] = strconv.Itoa(limits.PidsMax)
+	}
+	for file, value := range settings {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(value), 0); err != nil {
+			os.Remove(dir)
+			return nil, fmt.Errorf("cgroup %s: %w", file, err)
+		}
+	}
+
+	fd, err := syscall.Open(dir, syscall.O_RDONLY|syscall.O_DIRECTORY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		os.Remove(dir)
+		return nil, fmt.Errorf("cgroup: %w", err)
+	}
+	cmd.SysProcAttr = &syscall.SysProcAttr{UseCgroupFD: true, CgroupFD: fd}
+
+	return func() {
+		syscall.Close(fd)
+		os.WriteFile(filepath.Join(dir, "cgroup.kill"), []byte("1"), 0)
+		for attempt := 0; attempt < 50; attempt++ {
+			if err := os.Remove(dir); err == nil || os.IsNotExist(err) {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}, nil
+}
+
+// cgroupName makes an adapter name safe as a cgroup directory name
+func cgroupName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
@@ -0,0 +1,38 @@
+package adapters
+
+// auditArch is AUDIT_ARCH_X86_64
+const auditArch = 0xc000003e
+
+// x32SyscallBit marks x32 ABI syscall numbers
+const x32SyscallBit = 0x40000000
+
+// syscallNumbers are the syscalls isolation profiles may deny
+var syscallNumbers = map[string]uint32{
+	"acct":              163,
+	"add_key":           248,
+	"bpf":               321,
+	"chroot":            161,
+	"delete_module":     176,
+	"finit_module":      313,
+	"init_module":       175,
+	"io_uring_setup":    425,
+	"kexec_file_load":   320,
+	"kexec_load":        246,
+	"keyctl":            250,
+	"mount":             165,
+	"open_by_handle_at": 304,
+	"perf_event_open":   298,
+	"pivot_root":        155,
+	"process_vm_readv":  310,
+	"process_vm_writev": 311,
+	"ptrace":            101,
+	"reboot":            169,
+	"request_key":       249,
+	"setns":             308,
+	"socket":            41,
+	"swapoff":           168,
+	"swapon":            167,
+	"umount2":           166,
+	"unshare":           272,
+	"userfaultfd":       323,
+}
@@ -0,0 +1,38 @@
+package adapters
+
+// auditArch is AUDIT_ARCH_AARCH64
+const auditArch = 0xc00000b7
+
+// x32SyscallBit is zero: arm64 has no second syscall ABI to refuse
+const x32SyscallBit = 0
+
+// syscallNumbers are the syscalls isolation profiles may deny
+var syscallNumbers = map[string]uint32{
+	"acct":              89,
+	"add_key":           217,
+	"bpf":               280,
+	"chroot":            51,
+	"delete_module":     106,
+	"finit_module":      273,
+	"init_module":       105,
+	"io_uring_setup":    425,
+	"kexec_file_load":   294,
+	"kexec_load":        104,
+	"keyctl":            219,
+	"mount":             40,
+	"open_by_handle_at": 265,
+	"perf_event_open":   241,
+	"pivot_root":        41,
+	"process_vm_readv":  270,
+	"process_vm_writev": 271,
+	"ptrace":            117,
+	"reboot":            142,
+	"request_key":       218,
+	"setns":             268,
+	"socket":            198,
+	"swapoff":           225,
+	"swapon":            224,
+	"umount2":           39,
+	"unshare":           97,
+	"userfaultfd":       282,
+}
@@ -0,0 +1,12 @@
+//go:build linux && !amd64 && !arm64
+
+package adapters
+
+// Seccomp filters are built only for amd64 and arm64; profiles that deny
+// syscalls refuse to start elsewhere
+const (
+	auditArch     = 0
+	x32SyscallBit = 0
+)
+
+var syscallNumbers map[string]uint32
@@ -0,0 +1,89 @@
+package adapters
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// probeMarker makes the test binary act as the probe command
+const probeMarker = "oi-isolation-probe"
+
+// TestIsolatedCommandRunsUnderFilterAndLimits proves an isolated exec
+// command cannot open network sockets or call denied syscalls, and runs
+// with the tighter of the adapter's and profile's limits
+func TestIsolatedCommandRunsUnderFilterAndLimits(t *testing.T) {
+	if runtime.GOARCH != "amd64" && runtime.GOARCH != "arm64" {
+		t.Skip("seccomp isolation needs amd64 or arm64")
+	}
+	self, err := os.Executable()
+	if err != nil {
+		t.Fatalf("executable: %v", err)
+	}
+
+	// WHY: The Go runtime reserves more address space than the exec default
+	adapter, err := NewExecAdapter(ExecConfig{MemoryBytes: 64 << 30, Isolation: &Isolation{
+		Profile:  IsolationProfile{Name: "test", OpenFiles: 32, DenySyscalls: []string{"unshare"}, DenyNetwork: true},
+		Launcher: []string{self, "-test.run=^TestIsolationLauncherHelper$", "--"},
+	}})
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	registry := NewRegistry()
+	registry.Register(adapter)
+
+	result, err := registry.Invoke(DefaultExecName, mintExecToken(t, "exec:*"), posture.P1, map[string]interface{}{
+		ParamCommand: self,
+		ParamArgs:    []string{"-test.run=^TestIsolationProbeHelper$", "--", probeMarker},
+	})
+	if err != nil {
+		t.Fatalf("invoke: %v", err)
+	}
+	stdout := result.Details["stdout"].(string)
+	for _, want := range []string{"inet=" + syscall.EPERM.Error(), "unix=ok", "unshare=" + syscall.EPERM.Error(), "nofile=32"} {
+		if !strings.Contains(stdout, want) {
+			t.Fatalf("probe output missing %q:\n%s\nstderr:\n%s", want, stdout, result.Details["stderr"])
+		}
+	}
+}
+
+// TestIsolationLauncherHelper is the launcher when the test binary is
+// re-executed with IsolationLauncherArg; otherwise it does nothing
+func TestIsolationLauncherHelper(t *testing.T) {
+	for i, arg := range os.Args {
+		if arg == IsolationLauncherArg {
+			RunIsolationLauncher(os.Args[i:])
+		}
+	}
+}
+
+// TestIsolationProbeHelper reports what the confined process may do when
+// the test binary runs as the probe; otherwise it does nothing
+func TestIsolationProbeHelper(t *testing.T) {
+	if os.Args[len(os.Args)-1] != probeMarker {
+		return
+	}
+	outcome := func(err error) string {
+		if err != nil {
+			return err.Error()
+		}
+		return "ok"
+	}
+	inet, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_STREAM, 0)
+	if err == nil {
+		syscall.Close(inet)
+	}
+	unix, unixErr := syscall.Socket(syscall.AF_UNIX, syscall.SOCK_STREAM, 0)
+	if unixErr == nil {
+		syscall.Close(unix)
+	}
+	var limit syscall.Rlimit
+	syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit)
+	fmt.Printf("inet=%s unix=%s unshare=%s nofile=%d\n", outcome(err), outcome(unixErr), outcome(syscall.Unshare(0)), limit.Cur)
+	os.Exit(0)
+}
@@ -0,0 +1,32 @@
+//go:build !linux
+
+package adapters
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// isolationAvailable refuses isolation outside Linux.
+// WHY: The launcher relies on seccomp, cgroup v2, and rlimits inherited
+// across exec; without them a profile would be a label, not a limit.
+func isolationAvailable() error {
+	return fmt.Errorf("adapter isolation is not supported on %s", runtime.GOOS)
+}
+
+func checkSeccomp(deny []string, denyNetwork bool) error {
+	return isolationAvailable()
+}
+
+func checkCgroupRoot(root string) error {
+	return isolationAvailable()
+}
+
+func placeInCgroup(cmd *exec.Cmd, root string, name string, limits CgroupLimits) (func(), error) {
+	return nil, isolationAvailable()
+}
+
+func launch(policy launchPolicy, executable string, argv []string) error {
+	return isolationAvailable()
+}
@@ -0,0 +1,84 @@
+// WHY: These tests prove the profile follows the declared risk, that a
+// profile which cannot be enforced stops the adapter from starting, and
+// that an isolated command really runs under the filter and limits
+// (isolation_linux_test.go).
+package adapters
+
+import (
+	"testing"
+
+	"github.com/user/oi/kernel-go/internal/cdi"
+)
+
+// TestIsolationProfileFollowsDeclaredRisk proves riskier adapters get
+// tighter profiles and network only when they declare it
+func TestIsolationProfileFollowsDeclaredRisk(t *testing.T) {
+	cases := []struct {
+		declaration cdi.CapabilityDeclaration
+		profile     string
+		denyNetwork bool
+	}{
+		{cdi.CapabilityDeclaration{Risk: cdi.RiskLow}, IsolationBaseline, false},
+		{cdi.CapabilityDeclaration{Risk: cdi.RiskMedium, SideEffects: []string{cdi.SideEffectNetwork}}, IsolationRestricted, false},
+		{cdi.CapabilityDeclaration{Risk: cdi.RiskMedium, SideEffects: []string{cdi.SideEffectRead}}, IsolationRestricted, true},
+		{cdi.CapabilityDeclaration{Risk: cdi.RiskHigh, SideEffects: []string{cdi.SideEffectExec}}, IsolationConfined, true},
+		{cdi.CapabilityDeclaration{Risk: "unknown"}, IsolationConfined, true},
+	}
+	for _, tc := range cases {
+		profile := IsolationProfileFor(tc.declaration)
+		if profile.Name != tc.profile || profile.DenyNetwork != tc.denyNetwork {
+			t.Errorf("risk %s %v: got %s (deny network %v), want %s (%v)",
+				tc.declaration.Risk, tc.declaration.SideEffects, profile.Name, profile.DenyNetwork, tc.profile, tc.denyNetwork)
+		}
+	}
+
+	baseline, _ := IsolationProfileNamed(IsolationBaseline)
+	confined, _ := IsolationProfileNamed(IsolationConfined)
+	if len(baseline.DenySyscalls) != 0 || len(confined.DenySyscalls) == 0 || confined.Cgroup == nil {
+		t.Fatal("confined must add seccomp and a cgroup over baseline")
+	}
+	if _, err := isolationProfile("lenient", cdi.CapabilityDeclaration{Risk: cdi.RiskLow}); err == nil {
+		t.Fatal("unknown profile names must be refused")
+	}
+	auto, err := isolationProfile(IsolationAuto, cdi.CapabilityDeclaration{Risk: cdi.RiskHigh})
+	if err != nil || auto.Name != IsolationConfined {
+		t.Fatalf("auto must choose from the declaration, got %s (%v)", auto.Name, err)
+	}
+}
+
+// TestTighterLimitsTakesTheSmallerOfEach proves the adapter's limits can
+// only tighten a profile
+func TestTighterLimitsTakesTheSmallerOfEach(t *testing.T) {
+	got := tighterLimits(rlimits{CPUSeconds: 10, MemoryBytes: 1 << 30}, rlimits{CPUSeconds: 60, MemoryBytes: 1 << 20, OpenFiles: 32})
+	want := rlimits{CPUSeconds: 10, MemoryBytes: 1 << 20, OpenFiles: 32}
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+// TestUnenforceableIsolationStopsTheAdapter proves a profile that cannot
+// be applied refuses to start rather than running unconfined
+func TestUnenforceableIsolationStopsTheAdapter(t *testing.T) {
+	confined, _ := IsolationProfileNamed(IsolationConfined)
+	if _, err := NewExecAdapter(ExecConfig{Isolation: &Isolation{Profile: confined}}); err == nil {
+		t.Fatal("a cgroup profile without a cgroup root must be refused")
+	}
+	if _, err := NewExecAdapter(ExecConfig{Isolation: &Isolation{Profile: confined, CgroupRoot: t.TempDir()}}); err == nil {
+		t.Fatal("a cgroup root outside cgroup v2 must be refused")
+	}
+	bogus := IsolationProfile{Name: "bogus", DenySyscalls: []string{"no_such_syscall"}}
+	if _, err := NewExecAdapter(ExecConfig{Isolation: &Isolation{Profile: bogus}}); err == nil {
+		t.Fatal("a filter naming an unknown syscall must be refused")
+	}
+	if _, err := NewExecAdapter(ExecConfig{
+		Container: &ContainerConfig{Runtime: "sh", Image: "scratch"},
+		Isolation: &Isolation{Profile: IsolationProfile{Name: "limits"}},
+	}); err == nil {
+		t.Fatal("isolation and container must be exclusive")
+	}
+
+	spec := AdapterSpec{Name: "exec", Type: ManifestTypeExec, Options: []byte(`{"isolation":"auto"}`)}
+	if _, err := spec.Build(ManifestDeps{}); err == nil {
+		t.Fatal("a manifest asking for confined isolation without a cgroup root must not build")
+	}
+}
@@ -0,0 +1,159 @@
+// WHY: Rotating a credential or upgrading an adapter should not need a
+// kernel restart, and must not cut off calls already under way. Removing
+// or swapping an adapter takes effect for new calls at once; the old
+// instance drains its in-flight calls and is closed only when the last
+// one returns. Every change is reported so the kernel can ledger it.
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Lifecycle actions reported in AdapterChange
+const (
+	AdapterDeregistered = "deregistered"
+	AdapterReplaced     = "replaced"
+	AdapterDrained      = "drained" // the old instance's last call returned
+)
+
+// AdapterChange reports a deregistration, replacement, or completed drain
+type AdapterChange struct {
+	Adapter  string
+	Action   string
+	InFlight int // calls still running on the old instance when reported
+}
+
+// adapterCalls counts in-flight calls to one registered adapter instance
+type adapterCalls struct {
+	mu       sync.Mutex
+	active   int
+	draining bool
+	drained  chan struct{} // closed once draining and active reaches zero
+}
+
+func newAdapterCalls() *adapterCalls {
+	return &adapterCalls{drained: make(chan struct{})}
+}
+
+// begin records a call; the caller holds the registry lock that found the
+// instance, so no call can begin once the instance is unregistered
+func (c *adapterCalls) begin() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.active++
+}
+
+// end records a returned call
+func (c *adapterCalls) end() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.active--
+	if c.draining && c.active == 0 {
+		close(c.drained)
+	}
+}
+
+// drain marks the instance as draining and returns the number of calls
+// still in flight and a channel closed when they have all returned
+func (c *adapterCalls) drain() (int, <-chan struct{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.draining {
+		c.draining = true
+		if c.active == 0 {
+			close(c.drained)
+		}
+	}
+	return c.active, c.drained
+}
+
+// OnAdapterChange registers fn to receive every lifecycle change.
+// WHY: The registry has no ledger; the kernel records the changes.
+func (r *Registry) OnAdapterChange(fn func(AdapterChange)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onAdapterChange = fn
+}
+
+// reportChange passes a lifecycle change to the observer, if any
+func (r *Registry) reportChange(change AdapterChange) {
+	r.mu.RLock()
+	fn := r.onAdapterChange
+	r.mu.RUnlock()
+	if fn != nil {
+		fn(change)
+	}
+}
+
+// Deregister removes an adapter along with its rate limit, breaker,
+// timeout, and stats. New calls fail at once; Deregister then waits for in-flight
+// calls until ctx is done. If they have not all returned by then it
+// returns an error, and the adapter is closed when the last one does.
+func (r *Registry) Deregister(ctx context.Context, name string) error {
+	r.mu.Lock()
+	adapter, exists := r.adapters[name]
+	if !exists {
+		r.mu.Unlock()
+		return fmt.Errorf("adapter %s not found", name)
+	}
+	calls := r.calls[name]
+	delete(r.adapters, name)
+	delete(r.calls, name)
+	delete(r.limiters, name)
+	delete(r.breakers, name)
+	delete(r.timeouts, name)
+	r.mu.Unlock()
+	r.stats.forget(name)
+
+	return r.retire(ctx, name, AdapterDeregistered, adapter, calls)
+}
+
+// Replace swaps in a new implementation under an existing name, keeping its
+// rate limit, breaker, timeout, and stats. New calls go to the replacement at
+// once; the old instance drains as in Deregister.
+func (r *Registry) Replace(ctx context.Context, adapter Adapter) error {
+	name := adapter.Name()
+	if err := checkDeclaration(adapter); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	old, exists := r.adapters[name]
+	if !exists {
+		r.mu.Unlock()
+		return fmt.Errorf("adapter %s not found", name)
+	}
+	calls := r.calls[name]
+	r.adapters[name] = adapter
+	r.calls[name] = newAdapterCalls()
+	r.mu.Unlock()
+
+	return r.retire(ctx, name, AdapterReplaced, old, calls)
+}
+
+// retire reports the change, then drains and closes the old instance
+func (r *Registry) retire(ctx context.Context, name string, action string, old Adapter, calls *adapterCalls) error {
+	inFlight, drained := calls.drain()
+	r.reportChange(AdapterChange{Adapter: name, Action: action, InFlight: inFlight})
+
+	finish := func() {
+		if closer, ok := old.(io.Closer); ok {
+			closer.Close()
+		}
+		r.reportChange(AdapterChange{Adapter: name, Action: AdapterDrained})
+	}
+	select {
+	case <-drained:
+		finish()
+		return nil
+	case <-ctx.Done():
+		go func() {
+			<-drained
+			finish()
+		}()
+		return fmt.Errorf("adapter %s %s but still draining: %w", name, action, ctx.Err())
+	}
+}
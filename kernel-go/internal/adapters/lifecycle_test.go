@@ -0,0 +1,116 @@
+// WHY: These tests prove an adapter can be swapped or removed without
+// cutting off calls under way, and the retired instance is closed only
+// after its last call returns.
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// closableAdapter blocks until released and records whether it was closed
+type closableAdapter struct {
+	*stuckAdapter
+	closed chan struct{}
+}
+
+func (c *closableAdapter) Close() error {
+	close(c.closed)
+	return nil
+}
+
+// activeCalls returns the in-flight calls on the adapter's current instance
+func activeCalls(registry *Registry, name string) int {
+	registry.mu.RLock()
+	calls := registry.calls[name]
+	registry.mu.RUnlock()
+	calls.mu.Lock()
+	defer calls.mu.Unlock()
+	return calls.active
+}
+
+// TestReplaceDrainsInFlightCalls proves new calls reach the replacement at
+// once while the old instance finishes its call and is then closed
+func TestReplaceDrainsInFlightCalls(t *testing.T) {
+	registry := NewRegistry()
+	old := &closableAdapter{
+		stuckAdapter: &stuckAdapter{MockAdapter: NewMockAdapter("model"), release: make(chan struct{})},
+		closed:       make(chan struct{}),
+	}
+	registry.Register(old)
+	var changes []AdapterChange
+	changed := make(chan struct{}, 4)
+	registry.OnAdapterChange(func(change AdapterChange) {
+		changes = append(changes, change)
+		changed <- struct{}{}
+	})
+
+	inFlight := make(chan error, 1)
+	go func() {
+		_, err := registry.Invoke("model", mintLimitToken(t, "model"), 1, map[string]interface{}{})
+		inFlight <- err
+	}()
+	for activeCalls(registry, "model") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	replacement := NewMockAdapter("model")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := registry.Replace(ctx, replacement); err == nil {
+		t.Fatal("replace should report the old instance is still draining")
+	}
+	if _, err := registry.Invoke("model", mintLimitToken(t, "model"), 1, map[string]interface{}{}); err != nil {
+		t.Fatalf("new calls should reach the replacement: %v", err)
+	}
+	if len(replacement.GetInvocations()) != 1 {
+		t.Fatal("replacement did not receive the new call")
+	}
+	select {
+	case <-old.closed:
+		t.Fatal("old instance closed with a call in flight")
+	default:
+	}
+
+	close(old.release)
+	if err := <-inFlight; err != nil {
+		t.Fatalf("in-flight call should complete on the old instance: %v", err)
+	}
+	<-old.closed
+	<-changed
+	<-changed
+	want := []AdapterChange{{"model", AdapterReplaced, 1}, {"model", AdapterDrained, 0}}
+	if fmt.Sprint(changes) != fmt.Sprint(want) {
+		t.Fatalf("changes:\n got %v\nwant %v", changes, want)
+	}
+
+	if err := registry.Replace(context.Background(), NewMockAdapter("other")); err == nil {
+		t.Fatal("replacing an unregistered adapter must fail")
+	}
+}
+
+// TestDeregisterRemovesAdapterAndSettings proves a deregistered adapter
+// refuses new calls and its limits do not survive a later registration
+func TestDeregisterRemovesAdapterAndSettings(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(NewMockAdapter("tool"))
+	registry.SetRateLimit("tool", RateLimit{QPS: 1})
+	registry.SetTimeout("tool", time.Second)
+
+	if err := registry.Deregister(context.Background(), "tool"); err != nil {
+		t.Fatalf("deregister: %v", err)
+	}
+	if _, err := registry.Invoke("tool", mintLimitToken(t, "tool"), 1, map[string]interface{}{}); err == nil {
+		t.Fatal("deregistered adapter must refuse calls")
+	}
+	if err := registry.Deregister(context.Background(), "tool"); err == nil {
+		t.Fatal("deregistering twice must fail")
+	}
+
+	registry.Register(NewMockAdapter("tool"))
+	if registry.limiter("tool") != nil || registry.timeout("tool") != 0 {
+		t.Fatal("settings must not carry over to a new registration")
+	}
+}
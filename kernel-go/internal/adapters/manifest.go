@@ -0,0 +1,571 @@
+// WHY: Which adapters exist is deployment policy, and policy should be
+// reviewable without reading main(). A manifest declares every adapter the
+// kernel will register, what it connects to, which scopes a token must
+// carry to use it, and the most constrained posture it may run at.
+// Credentials are named by reference only, so the manifest itself can be
+// committed and reviewed.
+//
+// Manifests are JSON. YAML would need a parser from outside the standard
+// library, which the kernel does not take on.
+package adapters
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/cdi"
+	"github.com/user/oi/kernel-go/internal/memory"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// Adapter types a manifest may declare
+const (
+	ManifestTypeMock      = "mock"
+	ManifestTypeOpenAI    = "openai"
+	ManifestTypeOllama    = "ollama"
+	ManifestTypeHTTPFetch = "http_fetch"
+	ManifestTypeFS        = "fs"
+	ManifestTypeExec      = "exec"
+	ManifestTypeSQL       = "sql"
+	ManifestTypeMCP       = "mcp"
+	ManifestTypePlugin    = "plugin"
+	ManifestTypeEmail     = "email"
+	ManifestTypeCalendar  = "calendar"
+	ManifestTypeRetrieval = "retrieval"
+)
+
+// Manifest declares the adapters a kernel registers at startup
+type Manifest struct {
+	// ModelAdapter names the adapter requests route to; empty keeps the default
+	ModelAdapter string `json:"model_adapter"`
+
+	Adapters []AdapterSpec `json:"adapters"`
+}
+
+// AdapterSpec declares one adapter
+type AdapterSpec struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+
+	// Endpoint is the URL (openai, ollama, mcp, calendar, retrieval), executable path
+	// (plugin), SMTP host:port (email), or data source name (sql) the
+	// adapter connects to
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Credentials references a secret as "env:NAME" or "file:PATH"
+	Credentials string `json:"credentials,omitempty"`
+
+	// Scopes are required of every token in addition to the adapter name
+	Scopes []string `json:"scopes,omitempty"`
+
+	// MaxPosture is the most constrained posture the adapter may run at;
+	// zero leaves the adapter's own bound
+	MaxPosture int `json:"max_posture,omitempty"`
+
+	// Timeout bounds each call, as a Go duration ("30s"), in place of
+	// DefaultAdapterTimeout
+	Timeout string `json:"timeout,omitempty"`
+
+	// RateLimit, if set, throttles calls (see Registry.SetRateLimit)
+	RateLimit *ManifestRateLimit `json:"rate_limit,omitempty"`
+
+	// Breaker, if set, fails calls fast while the adapter is failing or
+	// slow (see Registry.SetCircuitBreaker)
+	Breaker *ManifestBreaker `json:"breaker,omitempty"`
+
+	// Options holds type-specific settings
+	Options json.RawMessage `json:"options,omitempty"`
+}
+
+// ManifestRateLimit declares an adapter's RateLimit
+type ManifestRateLimit struct {
+	QPS           float64 `json:"qps,omitempty"`
+	Burst         int     `json:"burst,omitempty"`
+	MaxConcurrent int     `json:"max_concurrent,omitempty"`
+}
+
+// ManifestBreaker declares an adapter's BreakerConfig, with durations as
+// Go durations ("2s")
+type ManifestBreaker struct {
+	Window    int     `json:"window,omitempty"`
+	MinCalls  int     `json:"min_calls,omitempty"`
+	ErrorRate float64 `json:"error_rate,omitempty"`
+	SlowCall  string  `json:"slow_call,omitempty"`
+	SlowRate  float64 `json:"slow_rate,omitempty"`
+	OpenFor   string  `json:"open_for,omitempty"`
+}
+
+// config converts the declaration, checking it as SetCircuitBreaker would
+func (b ManifestBreaker) config(name string) (BreakerConfig, error) {
+	config := BreakerConfig{Window: b.Window, MinCalls: b.MinCalls, ErrorRate: b.ErrorRate, SlowRate: b.SlowRate}
+	for _, duration := range []struct {
+		field  string
+		value  string
+		target *time.Duration
+	}{
+		{"slow_call", b.SlowCall, &config.SlowCall},
+		{"open_for", b.OpenFor, &config.OpenFor},
+	} {
+		if duration.value == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(duration.value)
+		if err != nil {
+			return BreakerConfig{}, fmt.Errorf("adapter %s: breaker %s: %w", name, duration.field, err)
+		}
+		*duration.target = parsed
+	}
+	if _, err := newCircuitBreaker(name, config); err != nil {
+		return BreakerConfig{}, fmt.Errorf("adapter %s: breaker: %w", name, err)
+	}
+	return config, nil
+}
+
+// ManifestDeps are kernel services some adapter types need
+type ManifestDeps struct {
+	// Memory receives http_fetch and retrieval quarantine writes
+	Memory *memory.Manager
+
+	// Consents, IntegrityState, and Confirmer judge email and calendar
+	// writes (see SideEffectPolicy)
+	Consents       func() map[string]bool
+	IntegrityState func() string
+	Confirmer      Confirmer
+
+	// Attestor, if set, pins and attests every plugin
+	Attestor *Attestor
+}
+
+// LoadManifest reads and validates a JSON manifest.
+// WHY: Unknown fields are errors, so a misspelled limit is refused
+// instead of silently ignored.
+func LoadManifest(path string) (*Manifest, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return nil, fmt.Errorf("adapter manifest %s: YAML is not supported; use JSON", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("adapter manifest: %w", err)
+	}
+
+	var manifest Manifest
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("adapter manifest %s: %w", path, err)
+	}
+	if err := manifest.Validate(); err != nil {
+		return nil, fmt.Errorf("adapter manifest %s: %w", path, err)
+	}
+	return &manifest, nil
+}
+
+// Validate checks names, scopes, postures, and credential references
+// without contacting anything
+func (m *Manifest) Validate() error {
+	names := map[string]bool{}
+	for i, spec := range m.Adapters {
+		if spec.Name == "" {
+			return fmt.Errorf("adapter %d: empty name", i)
+		}
+		if names[spec.Name] {
+			return fmt.Errorf("adapter %s declared twice", spec.Name)
+		}
+		names[spec.Name] = true
+
+		for _, scope := range spec.Scopes {
+			if _, err := capabilities.ParseScope(scope); err != nil {
+				return fmt.Errorf("adapter %s: %w", spec.Name, err)
+			}
+		}
+		if spec.MaxPosture != 0 && !posture.IsValid(spec.MaxPosture) {
+			return fmt.Errorf("adapter %s: invalid max posture %d", spec.Name, spec.MaxPosture)
+		}
+		if spec.Timeout != "" {
+			if timeout, err := time.ParseDuration(spec.Timeout); err != nil {
+				return fmt.Errorf("adapter %s: timeout: %w", spec.Name, err)
+			} else if timeout < 0 {
+				return fmt.Errorf("adapter %s: negative timeout %s", spec.Name, spec.Timeout)
+			}
+		}
+		if spec.RateLimit != nil {
+			if _, err := newAdapterLimiter(RateLimit(*spec.RateLimit)); err != nil {
+				return fmt.Errorf("adapter %s: rate limit: %w", spec.Name, err)
+			}
+		}
+		if spec.Breaker != nil {
+			if _, err := spec.Breaker.config(spec.Name); err != nil {
+				return err
+			}
+		}
+		if spec.Credentials != "" && !strings.HasPrefix(spec.Credentials, "env:") && !strings.HasPrefix(spec.Credentials, "file:") {
+			return fmt.Errorf("adapter %s: credentials must be a reference (env:NAME or file:PATH), not a value", spec.Name)
+		}
+	}
+	if m.ModelAdapter != "" && !names[m.ModelAdapter] {
+		return fmt.Errorf("model adapter %s is not declared", m.ModelAdapter)
+	}
+	return nil
+}
+
+// Register builds every declared adapter and registers it. Nothing is
+// registered unless every adapter builds.
+func (m *Manifest) Register(registry *Registry, deps ManifestDeps) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	for _, spec := range m.Adapters {
+		if _, err := registry.Get(spec.Name); err == nil {
+			return fmt.Errorf("adapter %s already registered", spec.Name)
+		}
+	}
+
+	built := make([]Adapter, 0, len(m.Adapters))
+	for _, spec := range m.Adapters {
+		adapter, err := spec.Build(deps)
+		if err != nil {
+			closeAdapters(built)
+			return err
+		}
+		built = append(built, adapter)
+	}
+	for i, adapter := range built {
+		if err := registry.Register(adapter); err != nil {
+			return err
+		}
+		if err := m.Adapters[i].apply(registry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply sets the registry's per-adapter controls the spec declares.
+// WHY: The registry bounds every call by its own timeout, so one set only
+// in the adapter's config would still be cut off at the default
+func (s AdapterSpec) apply(registry *Registry) error {
+	if s.Timeout != "" {
+		timeout, err := time.ParseDuration(s.Timeout)
+		if err == nil {
+			err = registry.SetTimeout(s.Name, timeout)
+		}
+		if err != nil {
+			return fmt.Errorf("adapter %s: timeout: %w", s.Name, err)
+		}
+	}
+	if s.RateLimit != nil {
+		if err := registry.SetRateLimit(s.Name, RateLimit(*s.RateLimit)); err != nil {
+			return err
+		}
+	}
+	if s.Breaker != nil {
+		config, err := s.Breaker.config(s.Name)
+		if err == nil {
+			err = registry.SetCircuitBreaker(s.Name, config)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// closeAdapters releases adapters that hold processes or connections
+func closeAdapters(built []Adapter) {
+	for _, adapter := range built {
+		if closer, ok := adapter.(*declaredAdapter).Adapter.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+}
+
+// Build constructs the adapter a spec declares, wrapped so the declared
+// scopes and posture bound are enforced whatever the type
+func (s AdapterSpec) Build(deps ManifestDeps) (Adapter, error) {
+	var timeout time.Duration
+	if s.Timeout != "" {
+		parsed, err := time.ParseDuration(s.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("adapter %s: timeout: %w", s.Name, err)
+		}
+		timeout = parsed
+	}
+	secret, err := resolveCredentials(s.Credentials)
+	if err != nil {
+		return nil, fmt.Errorf("adapter %s: %w", s.Name, err)
+	}
+
+	adapter, err := s.build(deps, timeout, secret)
+	if err != nil {
+		return nil, err
+	}
+	return &declaredAdapter{Adapter: adapter, scopes: s.Scopes, maxPosture: s.MaxPosture}, nil
+}
+
+// build constructs the unwrapped adapter for the spec's type
+func (s AdapterSpec) build(deps ManifestDeps, timeout time.Duration, secret string) (Adapter, error) {
+	// WHY: A credential a type cannot use is a misconfiguration, and
+	// silently dropping it would hide where a secret was meant to go
+	if secret != "" && s.Type != ManifestTypeOpenAI && s.Type != ManifestTypeMCP &&
+		s.Type != ManifestTypeEmail && s.Type != ManifestTypeCalendar && s.Type != ManifestTypeRetrieval {
+		return nil, fmt.Errorf("adapter %s: type %s takes no credentials", s.Name, s.Type)
+	}
+
+	switch s.Type {
+	case ManifestTypeMock:
+		return NewMockAdapter(s.Name), nil
+
+	case ManifestTypeOpenAI:
+		var options struct {
+			Model string `json:"model"`
+		}
+		if err := s.decodeOptions(&options); err != nil {
+			return nil, err
+		}
+		return NewOpenAIAdapter(OpenAIConfig{
+			Name: s.Name, BaseURL: s.Endpoint, APIKey: secret, Model: options.Model,
+			MaxPosture: s.MaxPosture, Timeout: timeout,
+		})
+
+	case ManifestTypeOllama:
+		var options struct {
+			Models map[int]string `json:"models"`
+		}
+		if err := s.decodeOptions(&options); err != nil {
+			return nil, err
+		}
+		return NewOllamaAdapter(OllamaConfig{Name: s.Name, BaseURL: s.Endpoint, Models: options.Models, Timeout: timeout})
+
+	case ManifestTypeHTTPFetch:
+		var options struct {
+			MaxBodyBytes int `json:"max_body_bytes"`
+		}
+		if err := s.decodeOptions(&options); err != nil {
+			return nil, err
+		}
+		return NewHTTPFetchAdapter(HTTPFetchConfig{Name: s.Name, Memory: deps.Memory, Timeout: timeout, MaxBodyBytes: options.MaxBodyBytes})
+
+	case ManifestTypeFS:
+		var options struct {
+			MaxBytes int `json:"max_bytes"`
+		}
+		if err := s.decodeOptions(&options); err != nil {
+			return nil, err
+		}
+		return NewFSAdapter(FSConfig{Name: s.Name, MaxBytes: options.MaxBytes}), nil
+
+	case ManifestTypeExec:
+		var options struct {
+			WorkDir        string `json:"work_dir"`
+			ContainerImage string `json:"container_image"`
+			Runtime        string `json:"container_runtime"`
+			Isolation      string `json:"isolation"`
+			CgroupRoot     string `json:"cgroup_root"`
+		}
+		if err := s.decodeOptions(&options); err != nil {
+			return nil, err
+		}
+		config := ExecConfig{Name: s.Name, MaxPosture: s.MaxPosture, Timeout: timeout, WorkDir: options.WorkDir}
+		if options.ContainerImage != "" {
+			config.Container = &ContainerConfig{Runtime: options.Runtime, Image: options.ContainerImage}
+		}
+		isolation, err := s.isolation(options.Isolation, options.CgroupRoot, (&ExecAdapter{config: config}).Declare())
+		if err != nil {
+			return nil, err
+		}
+		config.Isolation = isolation
+		return NewExecAdapter(config)
+
+	case ManifestTypeSQL:
+		var options struct {
+			Driver        string `json:"driver"`
+			DefaultSchema string `json:"default_schema"`
+			MaxRows       int    `json:"max_rows"`
+		}
+		if err := s.decodeOptions(&options); err != nil {
+			return nil, err
+		}
+		db, err := sql.Open(options.Driver, s.Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("adapter %s: %w", s.Name, err)
+		}
+		return NewSQLAdapter(SQLConfig{Name: s.Name, DB: db, DefaultSchema: options.DefaultSchema, MaxRows: options.MaxRows, Timeout: timeout})
+
+	case ManifestTypeMCP:
+		var options struct {
+			Server string `json:"server"`
+		}
+		if err := s.decodeOptions(&options); err != nil {
+			return nil, err
+		}
+		config := MCPConfig{Name: s.Name, Server: options.Server, Endpoint: s.Endpoint, Timeout: timeout}
+		if secret != "" {
+			config.Headers = map[string]string{"Authorization": "Bearer " + secret}
+		}
+		return NewMCPAdapter(config)
+
+	case ManifestTypePlugin:
+		var options struct {
+			Args        []string `json:"args"`
+			Env         []string `json:"env"`
+			Risk        string   `json:"risk"`
+			SideEffects []string `json:"side_effects"`
+			Isolation   string   `json:"isolation"`
+			CgroupRoot  string   `json:"cgroup_root"`
+		}
+		if err := s.decodeOptions(&options); err != nil {
+			return nil, err
+		}
+		config := PluginConfig{Name: s.Name, Path: s.Endpoint, Args: options.Args, Env: options.Env, Timeout: timeout,
+			Risk: options.Risk, SideEffects: options.SideEffects, Attestor: deps.Attestor}
+		isolation, err := s.isolation(options.Isolation, options.CgroupRoot, (&PluginAdapter{config: config}).Declare())
+		if err != nil {
+			return nil, err
+		}
+		config.Isolation = isolation
+		return NewPluginAdapter(config)
+
+	case ManifestTypeEmail:
+		var options struct {
+			From     string `json:"from"`
+			Username string `json:"username"`
+			Consent  string `json:"consent"`
+		}
+		if err := s.decodeOptions(&options); err != nil {
+			return nil, err
+		}
+		if secret != "" && options.Username == "" {
+			return nil, fmt.Errorf("adapter %s: credentials need a username", s.Name)
+		}
+		return NewEmailAdapter(EmailConfig{Name: s.Name, SMTPAddr: s.Endpoint, From: options.From,
+			Username: options.Username, Password: secret, Timeout: timeout, Policy: deps.policy(options.Consent)})
+
+	case ManifestTypeCalendar:
+		var options struct {
+			Consent string `json:"consent"`
+		}
+		if err := s.decodeOptions(&options); err != nil {
+			return nil, err
+		}
+		config := CalendarConfig{Name: s.Name, Endpoint: s.Endpoint, Timeout: timeout, Policy: deps.policy(options.Consent)}
+		if secret != "" {
+			config.Headers = map[string]string{"Authorization": "Bearer " + secret}
+		}
+		return NewCalendarAdapter(config)
+
+	case ManifestTypeRetrieval:
+		if err := s.decodeOptions(&struct{}{}); err != nil {
+			return nil, err
+		}
+		config := RetrievalConfig{Name: s.Name, Endpoint: s.Endpoint, Memory: deps.Memory, Timeout: timeout}
+		if secret != "" {
+			config.Headers = map[string]string{"Authorization": "Bearer " + secret}
+		}
+		return NewRetrievalAdapter(config)
+
+	default:
+		return nil, fmt.Errorf("adapter %s: unknown type %q", s.Name, s.Type)
+	}
+}
+
+// policy builds a side-effect policy from the kernel's services; an empty
+// consent keeps the adapter's default
+func (d ManifestDeps) policy(consent string) SideEffectPolicy {
+	return SideEffectPolicy{Consent: consent, Consents: d.Consents, IntegrityState: d.IntegrityState, Confirmer: d.Confirmer}
+}
+
+// isolation builds the isolation an entry's options ask for: a profile
+// name, or IsolationAuto to choose one from the declared risk
+func (s AdapterSpec) isolation(profile string, cgroupRoot string, declaration cdi.CapabilityDeclaration) (*Isolation, error) {
+	if profile == "" {
+		if cgroupRoot != "" {
+			return nil, fmt.Errorf("adapter %s: cgroup_root set without isolation", s.Name)
+		}
+		return nil, nil
+	}
+	chosen, err := isolationProfile(profile, declaration)
+	if err != nil {
+		return nil, fmt.Errorf("adapter %s: %w", s.Name, err)
+	}
+	return &Isolation{Profile: chosen, CgroupRoot: cgroupRoot}, nil
+}
+
+// decodeOptions decodes the spec's options, refusing unknown fields
+func (s AdapterSpec) decodeOptions(target interface{}) error {
+	if len(s.Options) == 0 {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(s.Options))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		return fmt.Errorf("adapter %s: options: %w", s.Name, err)
+	}
+	return nil
+}
+
+// resolveCredentials reads the secret a credentials reference names
+func resolveCredentials(reference string) (string, error) {
+	switch {
+	case reference == "":
+		return "", nil
+	case strings.HasPrefix(reference, "env:"):
+		name := strings.TrimPrefix(reference, "env:")
+		value := os.Getenv(name)
+		if value == "" {
+			return "", fmt.Errorf("credentials: environment variable %s is not set", name)
+		}
+		return value, nil
+	case strings.HasPrefix(reference, "file:"):
+		data, err := os.ReadFile(strings.TrimPrefix(reference, "file:"))
+		if err != nil {
+			return "", fmt.Errorf("credentials: %w", err)
+		}
+		value := strings.TrimSpace(string(data))
+		if value == "" {
+			return "", fmt.Errorf("credentials file is empty")
+		}
+		return value, nil
+	default:
+		return "", fmt.Errorf("credentials must be a reference (env:NAME or file:PATH)")
+	}
+}
+
+// declaredAdapter enforces a manifest's scopes and posture bound on top of
+// the adapter's own checks
+type declaredAdapter struct {
+	Adapter
+	scopes     []string
+	maxPosture int
+}
+
+// Declare adds the manifest's scopes to the adapter's own declaration
+func (d *declaredAdapter) Declare() cdi.CapabilityDeclaration {
+	declaration := d.Adapter.Declare()
+	declaration.Scopes = append(append([]string{}, declaration.Scopes...), d.scopes...)
+	return declaration
+}
+
+// VerifyToken runs the adapter's checks, then the declared ones
+func (d *declaredAdapter) VerifyToken(token *capabilities.Token, currentPosture int) error {
+	if err := d.Adapter.VerifyToken(token, currentPosture); err != nil {
+		return err
+	}
+	for _, scope := range d.scopes {
+		if !token.HasScope(scope) {
+			return fmt.Errorf("token does not have declared scope %s for adapter %s", scope, d.Name())
+		}
+	}
+	if d.maxPosture != 0 && currentPosture > d.maxPosture {
+		return fmt.Errorf("adapter %s not permitted at posture %d (max %d)", d.Name(), currentPosture, d.maxPosture)
+	}
+	return nil
+}
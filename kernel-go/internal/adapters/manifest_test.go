@@ -0,0 +1,124 @@
+// WHY: These tests prove manifests are validated strictly, credentials are
+// only ever references, and declared scopes and postures are enforced.
+package adapters
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+)
+
+// writeManifest writes content to a manifest file named name
+func writeManifest(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	return path
+}
+
+// TestManifestRegistersDeclaredAdapters proves a manifest builds its
+// adapters, resolves credential references, and enforces declared scopes
+// and max posture on top of each adapter's own checks
+func TestManifestRegistersDeclaredAdapters(t *testing.T) {
+	t.Setenv("OI_TEST_OPENAI_KEY", "sk-test")
+	path := writeManifest(t, "adapters.json", `{
+		"model_adapter": "assistant",
+		"adapters": [
+			{"name": "assistant", "type": "mock", "scopes": ["model:chat"], "max_posture": 2,
+			 "rate_limit": {"qps": 100, "max_concurrent": 4},
+			 "breaker": {"error_rate": 0.5, "slow_call": "2s", "slow_rate": 0.5, "open_for": "1m"}},
+			{"name": "openai", "type": "openai", "endpoint": "http://127.0.0.1:1/v1",
+			 "credentials": "env:OI_TEST_OPENAI_KEY", "timeout": "5s", "options": {"model": "gpt-test"}}
+		]
+	}`)
+
+	manifest, err := LoadManifest(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	registry := NewRegistry()
+	if err := manifest.Register(registry, ManifestDeps{}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if manifest.ModelAdapter != "assistant" || len(registry.ListAdapters()) != 2 {
+		t.Fatalf("unexpected registration: %v", registry.ListAdapters())
+	}
+	if registry.timeout("openai") != 5*time.Second || registry.timeout("assistant") != 0 {
+		t.Fatalf("the declared timeout must bound calls in the registry, got %s", registry.timeout("openai"))
+	}
+	if limiter := registry.limiter("assistant"); limiter == nil || limiter.limit.MaxConcurrent != 4 || registry.limiter("openai") != nil {
+		t.Fatal("the declared rate limit must be applied to its adapter only")
+	}
+	if breaker := registry.breaker("assistant"); breaker == nil || breaker.config.SlowCall != 2*time.Second || breaker.config.OpenFor != time.Minute {
+		t.Fatal("the declared breaker must be applied to its adapter")
+	}
+
+	mint := func(scopes ...string) *capabilities.Token {
+		token, err := capabilities.Mint("kernel", "test_subject", "adapters", scopes, capabilities.Limits{}, time.Minute,
+			capabilities.PostureBounds{MinPosture: 1, MaxPosture: 4}, "test_namespace", "test_principal")
+		if err != nil {
+			t.Fatalf("mint: %v", err)
+		}
+		return token
+	}
+	if _, err := registry.Invoke("assistant", mint("assistant"), 1, map[string]interface{}{}); err == nil {
+		t.Fatal("token without the declared scope must be refused")
+	}
+	if _, err := registry.Invoke("assistant", mint("assistant", "model:chat"), 3, map[string]interface{}{}); err == nil {
+		t.Fatal("posture beyond the declared max must be refused")
+	}
+	if _, err := registry.Invoke("assistant", mint("assistant", "model:chat"), 2, map[string]interface{}{}); err != nil {
+		t.Fatalf("declared scope at permitted posture should succeed: %v", err)
+	}
+}
+
+// TestManifestRejectsUnsafeDeclarations proves inline secrets, unknown
+// fields and types, and YAML are refused, and a failing manifest
+// registers nothing
+func TestManifestRejectsUnsafeDeclarations(t *testing.T) {
+	rejected := map[string]string{
+		"inline secret":  `{"adapters": [{"name": "a", "type": "openai", "credentials": "sk-live-123"}]}`,
+		"unknown field":  `{"adapters": [{"name": "a", "type": "mock", "max_postur": 2}]}`,
+		"bad scope":      `{"adapters": [{"name": "a", "type": "mock", "scopes": ["fs:re*d"]}]}`,
+		"bad posture":    `{"adapters": [{"name": "a", "type": "mock", "max_posture": 7}]}`,
+		"duplicate":      `{"adapters": [{"name": "a", "type": "mock"}, {"name": "a", "type": "mock"}]}`,
+		"missing model":  `{"model_adapter": "b", "adapters": [{"name": "a", "type": "mock"}]}`,
+		"unknown option": `{"adapters": [{"name": "a", "type": "fs", "options": {"max_byte": 1}}]}`,
+		"email password": `{"adapters": [{"name": "a", "type": "email", "endpoint": "127.0.0.1:25", "credentials": "env:HOME", "options": {"from": "oi@example.org"}}]}`,
+		"bad rate limit": `{"adapters": [{"name": "a", "type": "mock", "rate_limit": {"qps": -1}}]}`,
+		"idle breaker":   `{"adapters": [{"name": "a", "type": "mock", "breaker": {"window": 10}}]}`,
+		"bad breaker":    `{"adapters": [{"name": "a", "type": "mock", "breaker": {"error_rate": 0.5, "open_for": "soon"}}]}`,
+	}
+	for name, content := range rejected {
+		manifest, err := LoadManifest(writeManifest(t, "adapters.json", content))
+		if err == nil {
+			err = manifest.Register(NewRegistry(), ManifestDeps{})
+		}
+		if err == nil {
+			t.Fatalf("%s: manifest must be rejected", name)
+		}
+	}
+
+	if _, err := LoadManifest(writeManifest(t, "adapters.yaml", "adapters: []")); err == nil || !strings.Contains(err.Error(), "YAML") {
+		t.Fatalf("YAML manifests must be refused with a clear error: %v", err)
+	}
+
+	manifest, err := LoadManifest(writeManifest(t, "adapters.json",
+		`{"adapters": [{"name": "good", "type": "mock"}, {"name": "bad", "type": "telepathy"}]}`))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	registry := NewRegistry()
+	if err := manifest.Register(registry, ManifestDeps{}); err == nil {
+		t.Fatal("unknown adapter type must fail registration")
+	}
+	if len(registry.ListAdapters()) != 0 {
+		t.Fatalf("failed manifest must register nothing, got %v", registry.ListAdapters())
+	}
+}
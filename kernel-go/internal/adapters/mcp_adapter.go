@@ -0,0 +1,517 @@
+// WHY: MCP (Model Context Protocol) servers expose arbitrary tools, many of
+// them with side effects. This bridge makes each tool an explicit grant:
+// the server's tool manifest is translated into "mcp:call:<server>.<tool>"
+// scopes, and every call is token-checked and then judged by CDI as a
+// proposal before it reaches the server. Tool output is external content
+// and comes back CIF-labeled.
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/cdi"
+	"github.com/user/oi/kernel-go/internal/cif"
+)
+
+// Defaults for MCP bridge adapters
+const (
+	DefaultMCPName    = "mcp"
+	DefaultMCPTimeout = 30 * time.Second
+
+	// MCPProtocolVersion is the protocol revision sent at initialization
+	MCPProtocolVersion = "2025-03-26"
+)
+
+// Invocation parameters for MCP tool calls
+const (
+	ParamTool      = "tool"
+	ParamArguments = "arguments"
+)
+
+// mcpNamePattern limits server and tool names to characters that cannot
+// change the meaning of a scope string
+var mcpNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// MCPConfig configures a bridge to one MCP server over streamable HTTP
+type MCPConfig struct {
+	// Name is the adapter name and the scope tokens must carry (default DefaultMCPName)
+	Name string
+
+	// Server names the MCP server in tool scopes ("mcp:call:<Server>.<tool>")
+	Server string
+
+	// Endpoint is the server's MCP HTTP endpoint
+	Endpoint string
+
+	// Headers are sent with every request, e.g. server credentials
+	Headers map[string]string
+
+	// Timeout bounds each request (default DefaultMCPTimeout)
+	Timeout time.Duration
+
+	// IntegrityState reports the kernel integrity state for proposal
+	// checks; nil means the bridge does not consult it
+	IntegrityState func() string
+
+	// Client is the HTTP client; nil uses a default client
+	Client *http.Client
+}
+
+// MCPTool is one server tool translated into a governed capability
+type MCPTool struct {
+	Name        string
+	Description string
+
+	// Scope is the grant a token needs to call the tool
+	Scope string
+
+	// Risk comes from the tool's annotations and feeds the CDI proposal check
+	Risk string
+
+	InputSchema json.RawMessage
+}
+
+// MCPAdapter bridges one MCP server's tools into the registry
+type MCPAdapter struct {
+	config MCPConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	tools     map[string]MCPTool
+	sessionID string
+	nextID    int
+}
+
+// NewMCPAdapter validates the configuration and creates the bridge. The
+// server is not contacted until Discover or the first Invoke.
+func NewMCPAdapter(config MCPConfig) (*MCPAdapter, error) {
+	if config.Name == "" {
+		config.Name = DefaultMCPName
+	}
+	if !mcpNamePattern.MatchString(config.Server) {
+		return nil, fmt.Errorf("mcp adapter %s: server name %q must match %s", config.Name, config.Server, mcpNamePattern)
+	}
+	if config.Endpoint == "" {
+		return nil, fmt.Errorf("mcp adapter %s: empty endpoint", config.Name)
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultMCPTimeout
+	}
+
+	client := config.Client
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &MCPAdapter{config: config, client: client}, nil
+}
+
+// Name returns the adapter identifier
+func (a *MCPAdapter) Name() string {
+	return a.config.Name
+}
+
+// Declare reports the bridge as high risk with writes.
+// WHY: Registration happens before tool discovery, and MCP treats an
+// unannotated tool as destructive.
+func (a *MCPAdapter) Declare() cdi.CapabilityDeclaration {
+	return cdi.CapabilityDeclaration{
+		Name:        a.config.Name,
+		Risk:        cdi.RiskHigh,
+		SideEffects: []string{cdi.SideEffectNetwork, cdi.SideEffectWrite},
+		Scopes:      []string{"mcp:call:" + a.config.Server + ".*"},
+	}
+}
+
+// MCPToolScope returns the scope that grants calling tool on server
+func MCPToolScope(server, tool string) string {
+	return "mcp:call:" + server + "." + tool
+}
+
+// VerifyToken checks token validity and adapter scope. Tool scopes need the
+// tool name, so they are checked in Invoke.
+func (a *MCPAdapter) VerifyToken(token *capabilities.Token, currentPosture int) error {
+	if token == nil {
+		return fmt.Errorf("nil token - tokenless invocation rejected")
+	}
+
+	valid, err := token.Verify(currentPosture)
+	if !valid {
+		return fmt.Errorf("token verification failed: %w", err)
+	}
+
+	if !token.HasScope(a.config.Name) {
+		return fmt.Errorf("token does not have scope for adapter %s", a.config.Name)
+	}
+
+	return nil
+}
+
+// mcpToolDescriptor is the tools/list wire format, limited to used fields
+type mcpToolDescriptor struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+	Annotations struct {
+		ReadOnlyHint    *bool `json:"readOnlyHint"`
+		DestructiveHint *bool `json:"destructiveHint"`
+	} `json:"annotations"`
+}
+
+// Discover initializes a session and loads the server's tool manifest.
+// WHY: Tools whose names could not be expressed safely as a scope are not
+// exposed at all, rather than exposed under a mangled name.
+func (a *MCPAdapter) Discover() ([]MCPTool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.discoverLocked(context.Background())
+}
+
+// discoverLocked runs the initialize handshake and tools/list; a.mu is held
+func (a *MCPAdapter) discoverLocked(ctx context.Context) ([]MCPTool, error) {
+	a.sessionID = ""
+	if _, _, _, err := a.rpc(ctx, "initialize", map[string]interface{}{
+		"protocolVersion": MCPProtocolVersion,
+		"capabilities":    map[string]interface{}{},
+		"clientInfo":      map[string]interface{}{"name": "oi-kernel", "version": "1"},
+	}); err != nil {
+		return nil, fmt.Errorf("mcp adapter %s: initialize: %w", a.config.Name, err)
+	}
+	if err := a.notify(ctx, "notifications/initialized"); err != nil {
+		return nil, fmt.Errorf("mcp adapter %s: initialized: %w", a.config.Name, err)
+	}
+
+	tools := map[string]MCPTool{}
+	cursor := ""
+	for {
+		params := map[string]interface{}{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+		raw, _, _, err := a.rpc(ctx, "tools/list", params)
+		if err != nil {
+			return nil, fmt.Errorf("mcp adapter %s: tools/list: %w", a.config.Name, err)
+		}
+		var page struct {
+			Tools      []mcpToolDescriptor `json:"tools"`
+			NextCursor string              `json:"nextCursor"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return nil, fmt.Errorf("mcp adapter %s: decode tools: %w", a.config.Name, err)
+		}
+		for _, descriptor := range page.Tools {
+			if !mcpNamePattern.MatchString(descriptor.Name) {
+				continue
+			}
+			tools[descriptor.Name] = MCPTool{
+				Name:        descriptor.Name,
+				Description: descriptor.Description,
+				Scope:       MCPToolScope(a.config.Server, descriptor.Name),
+				Risk:        mcpToolRisk(descriptor),
+				InputSchema: descriptor.InputSchema,
+			}
+		}
+		if page.NextCursor == "" || page.NextCursor == cursor {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	a.tools = tools
+	return sortedTools(tools), nil
+}
+
+// mcpToolRisk maps tool annotations to a proposal risk.
+// WHY: MCP defaults an unannotated tool to destructive, so only an explicit
+// read-only or non-destructive hint lowers the risk.
+func mcpToolRisk(descriptor mcpToolDescriptor) string {
+	hints := descriptor.Annotations
+	if hints.ReadOnlyHint != nil && *hints.ReadOnlyHint {
+		return cdi.RiskLow
+	}
+	if hints.DestructiveHint != nil && !*hints.DestructiveHint {
+		return cdi.RiskMedium
+	}
+	return cdi.RiskHigh
+}
+
+// Tools returns the discovered tools sorted by name
+func (a *MCPAdapter) Tools() []MCPTool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return sortedTools(a.tools)
+}
+
+// DeclaredScopes returns the scope of every discovered tool, for token
+// templates that grant the bridge
+func (a *MCPAdapter) DeclaredScopes() []string {
+	tools := a.Tools()
+	scopes := make([]string, len(tools))
+	for i, tool := range tools {
+		scopes[i] = tool.Scope
+	}
+	return scopes
+}
+
+// sortedTools flattens the tool map in name order
+func sortedTools(tools map[string]MCPTool) []MCPTool {
+	sorted := make([]MCPTool, 0, len(tools))
+	for _, tool := range tools {
+		sorted = append(sorted, tool)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return sorted
+}
+
+// Invoke calls one tool after scope and CDI proposal checks
+func (a *MCPAdapter) Invoke(ctx context.Context, token *capabilities.Token, params map[string]interface{}) (*AdapterResult, error) {
+	if token == nil {
+		return nil, fmt.Errorf("nil token - invoke rejected")
+	}
+
+	name, _ := params[ParamTool].(string)
+	if name == "" {
+		return nil, fmt.Errorf("mcp adapter %s: missing %q parameter", a.config.Name, ParamTool)
+	}
+	arguments := map[string]interface{}{}
+	switch v := params[ParamArguments].(type) {
+	case nil:
+	case map[string]interface{}:
+		arguments = v
+	default:
+		return nil, fmt.Errorf("mcp adapter %s: %q must be an object", a.config.Name, ParamArguments)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.tools == nil {
+		if _, err := a.discoverLocked(ctx); err != nil {
+			return nil, err
+		}
+	}
+	tool, ok := a.tools[name]
+	if !ok {
+		return nil, fmt.Errorf("mcp adapter %s: server %s has no tool %q", a.config.Name, a.config.Server, name)
+	}
+	if !token.HasScope(tool.Scope) {
+		return nil, fmt.Errorf("mcp adapter %s: token does not grant %s", a.config.Name, tool.Scope)
+	}
+
+	decision, err := a.judge(tool, arguments, params[ParamPosture])
+	if err != nil {
+		return nil, err
+	}
+
+	raw, requestBody, responseBody, err := a.rpc(ctx, "tools/call", map[string]interface{}{
+		"name":      tool.Name,
+		"arguments": arguments,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("mcp adapter %s: %s: %w", a.config.Name, tool.Name, err)
+	}
+
+	var called struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		IsError bool `json:"isError"`
+	}
+	if err := json.Unmarshal(raw, &called); err != nil {
+		return nil, fmt.Errorf("mcp adapter %s: decode %s result: %w", a.config.Name, tool.Name, err)
+	}
+	texts := []string{}
+	for _, item := range called.Content {
+		if item.Type == "text" {
+			texts = append(texts, item.Text)
+		}
+	}
+	// WHY: The error text is tool output too, so it is not echoed
+	if called.IsError {
+		return nil, fmt.Errorf("mcp adapter %s: tool %s reported an error", a.config.Name, tool.Name)
+	}
+
+	message := strings.Join(texts, "\n")
+	labels := []string{"clean"}
+	if message != "" {
+		labeled, err := cif.Ingress(message, nil)
+		if err != nil {
+			return nil, fmt.Errorf("mcp adapter %s: label %s result: %w", a.config.Name, tool.Name, err)
+		}
+		labels = labeled.TaintLabels
+	}
+
+	return &AdapterResult{
+		Status:      StatusSuccess,
+		Content:     message,
+		ContentType: ContentTypeText,
+		Provenance:  Provenance{Source: tool.Scope, TaintLabels: labels, DecisionID: decision.DecisionID},
+		Exchange:    exchangeOf(requestBody, responseBody),
+		Details:     map[string]interface{}{"tool": tool.Name},
+	}, nil
+}
+
+// judge labels the arguments and asks CDI to approve the call
+func (a *MCPAdapter) judge(tool MCPTool, arguments map[string]interface{}, verifiedPosture interface{}) (*cdi.DecisionResult, error) {
+	encoded, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, fmt.Errorf("mcp adapter %s: encode arguments: %w", a.config.Name, err)
+	}
+	labeled, err := cif.Ingress(string(encoded), nil)
+	if err != nil {
+		return nil, fmt.Errorf("mcp adapter %s: arguments: %w", a.config.Name, err)
+	}
+
+	// A missing posture stays 0, which CDI refuses
+	currentPosture, _ := verifiedPosture.(int)
+	integrity := ""
+	if a.config.IntegrityState != nil {
+		integrity = a.config.IntegrityState()
+	}
+
+	decision, err := cdi.DecideProposal(&cdi.ProposalContext{
+		Action:         tool.Scope,
+		Arguments:      labeled,
+		Risk:           tool.Risk,
+		PostureLevel:   currentPosture,
+		IntegrityState: integrity,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("mcp adapter %s: proposal check: %w", a.config.Name, err)
+	}
+	if decision.Decision != cdi.ALLOW {
+		return nil, fmt.Errorf("mcp adapter %s: CDI %s call to %s: %s", a.config.Name, decision.Decision, tool.Name, decision.Reason)
+	}
+	return decision, nil
+}
+
+// mcpResponse is a JSON-RPC 2.0 response
+type mcpResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// rpc sends one JSON-RPC request and returns the result along with the raw
+// request and response bodies; a.mu is held
+func (a *MCPAdapter) rpc(ctx context.Context, method string, params interface{}) (json.RawMessage, []byte, []byte, error) {
+	a.nextID++
+	id := a.nextID
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	respBody, header, err := a.post(ctx, body)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if session := header.Get("Mcp-Session-Id"); session != "" && method == "initialize" {
+		a.sessionID = session
+	}
+	if strings.HasPrefix(header.Get("Content-Type"), "text/event-stream") {
+		respBody = mcpEventData(respBody)
+	}
+
+	var resp mcpResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, nil, nil, fmt.Errorf("decode response: %w", err)
+	}
+	if string(resp.ID) != fmt.Sprint(id) {
+		return nil, nil, nil, fmt.Errorf("response id %s does not match request %d", resp.ID, id)
+	}
+	if resp.Error != nil {
+		return nil, nil, nil, fmt.Errorf("server error %d", resp.Error.Code)
+	}
+	return resp.Result, body, respBody, nil
+}
+
+// notify sends a JSON-RPC notification; a.mu is held
+func (a *MCPAdapter) notify(ctx context.Context, method string) error {
+	body, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "method": method})
+	if err != nil {
+		return err
+	}
+	_, _, err = a.post(ctx, body)
+	return err
+}
+
+// post sends one message to the endpoint.
+// WHY: As with postExchange, error bodies are never surfaced and the
+// response is size-capped.
+func (a *MCPAdapter) post(ctx context.Context, body []byte) ([]byte, http.Header, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.config.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	for name, value := range a.config.Headers {
+		req.Header.Set(name, value)
+	}
+	if a.sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", a.sessionID)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, nil, fmt.Errorf("timed out after %s", a.config.Timeout)
+		}
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxExchangeResponseBytes+1))
+	if err != nil {
+		return nil, nil, fmt.Errorf("read response: %w", err)
+	}
+	if len(respBody) > maxExchangeResponseBytes {
+		return nil, nil, fmt.Errorf("response exceeds %d bytes", maxExchangeResponseBytes)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return nil, nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return respBody, resp.Header, nil
+}
+
+// mcpEventData returns the data of the last event in a server-sent event
+// stream, which carries the JSON-RPC response
+func mcpEventData(stream []byte) []byte {
+	var data, last []byte
+	for _, line := range strings.Split(string(stream), "\n") {
+		line = strings.TrimRight(line, "\r")
+		switch {
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " ")...)
+		case line == "" && len(data) > 0:
+			last, data = data, nil
+		}
+	}
+	if len(data) > 0 {
+		last = data
+	}
+	return last
+}
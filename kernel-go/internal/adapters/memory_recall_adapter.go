@@ -0,0 +1,196 @@
+// WHY: Long-term memory lets an assistant recall what it was told, but a
+// recalled entry is still text from the past: it may carry instructions
+// that were harmless when stored, or detail too sensitive for the current
+// posture. The recall adapter searches only the token's namespace, reads
+// each match through the audited durable read, and passes it back through
+// CIF labeling and the CDI output decision before handing it on; an entry
+// either gate refuses is withheld, never summarized or trimmed.
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/cdi"
+	"github.com/user/oi/kernel-go/internal/cif"
+	"github.com/user/oi/kernel-go/internal/memory"
+)
+
+// Defaults for memory recall adapters; the query text is ParamQuery and
+// the number of matches ParamTopK
+const (
+	DefaultMemoryRecallName = "memory_recall"
+	DefaultRecallTopK       = 5
+	MaxRecallTopK           = 20
+)
+
+// LabelRecalled marks content recalled from durable memory
+const LabelRecalled = "recalled_memory"
+
+// MemoryRecallConfig configures a memory recall adapter
+type MemoryRecallConfig struct {
+	// Name is the adapter name and the scope tokens must carry (default DefaultMemoryRecallName)
+	Name string
+
+	// Memory is searched in each token's namespace; it needs an embedder
+	Memory *memory.Manager
+}
+
+// RecalledMemory is one recalled entry as handed on to the caller. Text
+// is empty and Reason says why when the entry was withheld.
+type RecalledMemory struct {
+	ID       string   `json:"id"`
+	Score    float64  `json:"score"`
+	Labels   []string `json:"labels"`
+	Withheld bool     `json:"withheld,omitempty"`
+	Reason   string   `json:"reason,omitempty"`
+	Text     string   `json:"text,omitempty"`
+}
+
+// MemoryRecallAdapter searches durable memory and gates what it returns
+type MemoryRecallAdapter struct {
+	config MemoryRecallConfig
+}
+
+// NewMemoryRecallAdapter validates the configuration and creates the adapter
+func NewMemoryRecallAdapter(config MemoryRecallConfig) (*MemoryRecallAdapter, error) {
+	if config.Name == "" {
+		config.Name = DefaultMemoryRecallName
+	}
+	if config.Memory == nil {
+		return nil, fmt.Errorf("memory recall adapter %s: no memory manager", config.Name)
+	}
+	return &MemoryRecallAdapter{config: config}, nil
+}
+
+// Name returns the adapter identifier
+func (a *MemoryRecallAdapter) Name() string {
+	return a.config.Name
+}
+
+// Declare reports a local read
+func (a *MemoryRecallAdapter) Declare() cdi.CapabilityDeclaration {
+	return cdi.CapabilityDeclaration{
+		Name:        a.config.Name,
+		Risk:        cdi.RiskLow,
+		SideEffects: []string{cdi.SideEffectRead},
+		Scopes:      []string{a.config.Name},
+	}
+}
+
+// VerifyToken checks token validity and adapter scope
+func (a *MemoryRecallAdapter) VerifyToken(token *capabilities.Token, currentPosture int) error {
+	if token == nil {
+		return fmt.Errorf("nil token - tokenless invocation rejected")
+	}
+
+	valid, err := token.Verify(currentPosture)
+	if !valid {
+		return fmt.Errorf("token verification failed: %w", err)
+	}
+
+	if !token.HasScope(a.config.Name) {
+		return fmt.Errorf("token does not have scope for adapter %s", a.config.Name)
+	}
+
+	return nil
+}
+
+// Invoke recalls the durable entries closest to the query and returns
+// those CIF and CDI let through
+func (a *MemoryRecallAdapter) Invoke(ctx context.Context, token *capabilities.Token, params map[string]interface{}) (*AdapterResult, error) {
+	if token == nil {
+		return nil, fmt.Errorf("nil token - invoke rejected")
+	}
+
+	query, _ := params[ParamQuery].(string)
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("memory recall adapter %s: missing %q parameter", a.config.Name, ParamQuery)
+	}
+	topK := topKParam(params, DefaultRecallTopK)
+	if topK < 1 || topK > MaxRecallTopK {
+		return nil, fmt.Errorf("memory recall adapter %s: %s must be between 1 and %d", a.config.Name, ParamTopK, MaxRecallTopK)
+	}
+	// WHY: CDI's output decision depends on the posture the call was verified at
+	currentPosture, ok := params[ParamPosture].(int)
+	if !ok {
+		return nil, fmt.Errorf("memory recall adapter %s: missing verified posture", a.config.Name)
+	}
+
+	view := a.config.Memory.Namespace(token.NamespaceID)
+	matches, err := view.Search(ctx, query, topK)
+	if err != nil {
+		return nil, fmt.Errorf("memory recall adapter %s: %w", a.config.Name, err)
+	}
+
+	recalled := make([]RecalledMemory, 0, len(matches))
+	labels := []string{LabelRecalled}
+	withheld := 0
+	for _, match := range matches {
+		entry, err := view.Read(memory.PartitionDurable, match.ID)
+		if err != nil {
+			// Deleted since the search; there is nothing left to recall
+			continue
+		}
+		item, err := a.gate(entry, match.Score, currentPosture)
+		if err != nil {
+			return nil, fmt.Errorf("memory recall adapter %s: %w", a.config.Name, err)
+		}
+		if item.Withheld {
+			withheld++
+		}
+		labels = mergeLabels(labels, item.Labels)
+		recalled = append(recalled, *item)
+	}
+
+	content, err := json.Marshal(recalled)
+	if err != nil {
+		return nil, fmt.Errorf("memory recall adapter %s: encode recalled entries: %w", a.config.Name, err)
+	}
+	return &AdapterResult{
+		Status:      StatusSuccess,
+		Content:     string(content),
+		ContentType: ContentTypeJSON,
+		Provenance:  Provenance{Source: "memory:" + memory.PartitionDurable, TaintLabels: labels},
+		Details:     map[string]interface{}{"recalled": len(recalled), "withheld": withheld},
+	}, nil
+}
+
+// gate re-ingests one entry through CIF and asks CDI whether it may leave
+// at the current posture, withholding its text if either refuses
+func (a *MemoryRecallAdapter) gate(entry *memory.Entry, score float64, currentPosture int) (*RecalledMemory, error) {
+	item := &RecalledMemory{ID: entry.ID, Score: score}
+	metadata := map[string]interface{}{"source": a.config.Name, "partition": memory.PartitionDurable}
+	if marked, ok := entry.Metadata[memory.MetadataSensitivity].(string); ok {
+		metadata["sensitivity"] = marked
+	}
+	labeled, err := cif.Ingress(entry.Content, metadata)
+	if err != nil {
+		// Empty and oversized entries cannot be labeled, so nothing of them is handed on
+		item.Labels = []string{LabelRecalled, "unlabeled"}
+		item.Withheld = true
+		item.Reason = "unlabeled"
+		return item, nil
+	}
+	item.Labels = append(append([]string(nil), labeled.TaintLabels...), LabelRecalled)
+	if labeled.IsTainted() {
+		item.Withheld = true
+		item.Reason = "tainted"
+		return item, nil
+	}
+
+	decision, err := cdi.DecideOutput(labeled.SanitizedInput, labeled.SensitivityLevel, currentPosture)
+	if err != nil {
+		return nil, fmt.Errorf("output decision for %s: %w", entry.ID, err)
+	}
+	if decision.Decision != cdi.ALLOW {
+		item.Withheld = true
+		item.Reason = decision.Reason
+		return item, nil
+	}
+	item.Text = labeled.SanitizedInput
+	return item, nil
+}
@@ -0,0 +1,81 @@
+// WHY: Concerns that apply to every adapter (metrics, extra verification,
+// parameter scrubbing, result labeling) belong at the chokepoint, not
+// reimplemented in each adapter where one forgotten copy is a hole.
+// Interceptors run inside Registry.Invoke, after the token checks, so they
+// can only add refusals and narrow what flows through; they never see a
+// call the registry would not make.
+package adapters
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+)
+
+// Call is one adapter invocation as seen by interceptors
+type Call struct {
+	Adapter string
+	Token   *capabilities.Token
+	Posture int
+
+	// Params are the invocation parameters; pre-invoke interceptors may
+	// change them. ParamPosture is reset to the verified posture after they
+	// run.
+	Params map[string]interface{}
+}
+
+// Outcome is what a call produced; post-invoke interceptors may replace
+// the result or turn it into an error
+type Outcome struct {
+	Result  *AdapterResult // nil when Err is set
+	Err     error
+	Elapsed time.Duration
+}
+
+// PreInvoke runs before the adapter; an error refuses the call
+type PreInvoke func(call *Call) error
+
+// PostInvoke runs after every invocation that reached the adapter,
+// including failed and timed-out ones
+type PostInvoke func(call *Call, outcome *Outcome)
+
+// BeforeInvoke adds fn to the pre-invoke interceptors; they run in the
+// order added
+func (r *Registry) BeforeInvoke(fn PreInvoke) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.preInvoke = append(r.preInvoke, fn)
+}
+
+// AfterInvoke adds fn to the post-invoke interceptors; they run in the
+// order added
+func (r *Registry) AfterInvoke(fn PostInvoke) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.postInvoke = append(r.postInvoke, fn)
+}
+
+// interceptors returns a snapshot of the registered interceptors
+func (r *Registry) interceptors() ([]PreInvoke, []PostInvoke) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]PreInvoke(nil), r.preInvoke...), append([]PostInvoke(nil), r.postInvoke...)
+}
+
+// runPreInvoke runs pre-invoke interceptors until one refuses
+func runPreInvoke(interceptors []PreInvoke, call *Call) error {
+	for _, fn := range interceptors {
+		if err := fn(call); err != nil {
+			return fmt.Errorf("adapter %s: refused before invoke: %w", call.Adapter, err)
+		}
+	}
+	return nil
+}
+
+// runPostInvoke runs every post-invoke interceptor
+func runPostInvoke(interceptors []PostInvoke, call *Call, outcome *Outcome) {
+	for _, fn := range interceptors {
+		fn(call, outcome)
+	}
+}
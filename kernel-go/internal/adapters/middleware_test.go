@@ -0,0 +1,86 @@
+// WHY: These tests prove interceptors can scrub, refuse, observe, and
+// label calls at the chokepoint, but cannot override the verified posture.
+package adapters
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestInterceptorsScrubRefuseAndLabel proves pre-invoke interceptors see
+// and change params, refusals never reach the adapter, and post-invoke
+// interceptors observe and label results
+func TestInterceptorsScrubRefuseAndLabel(t *testing.T) {
+	registry := NewRegistry()
+	adapter := NewMockAdapter("tool")
+	registry.Register(adapter)
+
+	registry.BeforeInvoke(func(call *Call) error {
+		if call.Params["blocked"] == true {
+			return fmt.Errorf("blocked by policy")
+		}
+		delete(call.Params, "api_key")
+		call.Params[ParamPosture] = 4
+		return nil
+	})
+	var observed []*Outcome
+	registry.AfterInvoke(func(call *Call, outcome *Outcome) {
+		observed = append(observed, outcome)
+		if outcome.Result != nil {
+			outcome.Result.Provenance.TaintLabels = append(outcome.Result.Provenance.TaintLabels, "reviewed")
+		}
+	})
+
+	result, err := registry.Invoke("tool", mintLimitToken(t, "tool"), 1, map[string]interface{}{"api_key": "sk-live", "q": "x"})
+	if err != nil {
+		t.Fatalf("invoke: %v", err)
+	}
+	seen := adapter.GetInvocations()[0].Params
+	if _, leaked := seen["api_key"]; leaked || seen["q"] != "x" {
+		t.Fatalf("scrubbed params should reach the adapter: %v", seen)
+	}
+	if seen[ParamPosture] != 1 {
+		t.Fatalf("interceptors must not override the verified posture, adapter saw %v", seen[ParamPosture])
+	}
+	if len(result.Provenance.TaintLabels) != 1 || result.Provenance.TaintLabels[0] != "reviewed" {
+		t.Fatalf("post-invoke label missing: %v", result.Provenance.TaintLabels)
+	}
+
+	if _, err := registry.Invoke("tool", mintLimitToken(t, "tool"), 1, map[string]interface{}{"blocked": true}); err == nil {
+		t.Fatal("pre-invoke refusal must fail the call")
+	}
+	if len(adapter.GetInvocations()) != 1 || len(observed) != 1 {
+		t.Fatal("refused calls must not reach the adapter or post-invoke interceptors")
+	}
+
+	if _, err := registry.Invoke("tool", nil, 1, map[string]interface{}{}); err == nil || len(observed) != 1 {
+		t.Fatal("calls failing token verification must not reach interceptors")
+	}
+}
+
+// TestPostInvokeSeesFailuresAndCanRefuse proves post-invoke interceptors
+// observe adapter errors and can turn a result into an error
+func TestPostInvokeSeesFailuresAndCanRefuse(t *testing.T) {
+	registry := NewRegistry()
+	failing := &failingAdapter{MockAdapter: NewMockAdapter("backend"), failing: true}
+	registry.Register(failing)
+
+	errs := 0
+	registry.AfterInvoke(func(call *Call, outcome *Outcome) {
+		if outcome.Err != nil {
+			errs++
+			return
+		}
+		outcome.Result, outcome.Err = nil, fmt.Errorf("result withheld")
+	})
+
+	if _, err := registry.Invoke("backend", mintLimitToken(t, "backend"), 1, map[string]interface{}{}); err == nil || errs != 1 {
+		t.Fatalf("adapter failure should be observed: err=%v observed=%d", err, errs)
+	}
+
+	failing.failing = false
+	_, err := registry.Invoke("backend", mintLimitToken(t, "backend"), 1, map[string]interface{}{})
+	if err == nil || err.Error() != "result withheld" {
+		t.Fatalf("post-invoke refusal should fail the call, got %v", err)
+	}
+}
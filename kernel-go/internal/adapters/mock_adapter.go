@@ -3,9 +3,11 @@
 package adapters
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/cdi"
 )
 
 // MockAdapter is a test adapter that records invocations
@@ -18,7 +20,7 @@ type MockAdapter struct {
 type Invocation struct {
 	TokenDigest string
 	Params      map[string]interface{}
-	Result      interface{}
+	Result      *AdapterResult
 }
 
 // NewMockAdapter creates a new mock adapter
@@ -34,17 +36,24 @@ func (m *MockAdapter) Name() string {
 	return m.name
 }
 
+// Declare reports that the mock has no side effects
+func (m *MockAdapter) Declare() cdi.CapabilityDeclaration {
+	return cdi.CapabilityDeclaration{Name: m.name, Risk: cdi.RiskLow}
+}
+
 // Invoke executes the mock operation and records the invocation
-func (m *MockAdapter) Invoke(token *capabilities.Token, params map[string]interface{}) (interface{}, error) {
+func (m *MockAdapter) Invoke(_ context.Context, token *capabilities.Token, params map[string]interface{}) (*AdapterResult, error) {
 	// Check for nil token
 	if token == nil {
 		return nil, fmt.Errorf("nil token - invoke rejected")
 	}
 
 	// Record the invocation
-	result := map[string]interface{}{
-		"status":  "success",
-		"message": fmt.Sprintf("mock adapter %s invoked", m.name),
+	result := &AdapterResult{
+		Status:      StatusSuccess,
+		Content:     fmt.Sprintf("mock adapter %s invoked", m.name),
+		ContentType: ContentTypeText,
+		Provenance:  Provenance{Source: m.name},
 	}
 
 	m.invocations = append(m.invocations, Invocation{
@@ -70,7 +79,7 @@ func (m *MockAdapter) VerifyToken(token *capabilities.Token, currentPosture int)
 	}
 
 	// Check if token has required scope for this adapter
-	if !token.HasScope(m.name) && !token.HasScope("*") {
+	if !token.HasScope(m.name) {
 		return fmt.Errorf("token does not have scope for adapter %s", m.name)
 	}
 
@@ -0,0 +1,40 @@
+// WHY: A token names the namespace it was minted in, but the registry
+// never compared it with the namespace a call acted for, so a token minted
+// for one tenant could drive an adapter on another's behalf. A call made
+// through InvokeInNamespace is refused before the adapter, its limits, or
+// its breaker are reached unless the token was minted in the namespace
+// the call acts for.
+package adapters
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+)
+
+// NamespaceError reports a call refused because its token was minted in
+// another namespace.
+// WHY: A distinct type lets the kernel record a cross-namespace attempt
+// as a security event, not an ordinary authorization failure.
+type NamespaceError struct {
+	Adapter        string
+	Namespace      string
+	TokenNamespace string
+}
+
+func (e *NamespaceError) Error() string {
+	return fmt.Sprintf("adapter %s: token minted in namespace %q cannot act for namespace %q", e.Adapter, e.TokenNamespace, e.Namespace)
+}
+
+// InvokeInNamespace is InvokeContext for a call acting for namespace; a
+// token minted in any other namespace is refused with a NamespaceError
+func (r *Registry) InvokeInNamespace(ctx context.Context, namespace string, adapterName string, token *capabilities.Token, currentPosture int, params map[string]interface{}) (*AdapterResult, error) {
+	if token != nil && token.NamespaceID != namespace {
+		if _, err := r.Get(adapterName); err == nil {
+			r.stats.refused(adapterName)
+		}
+		return nil, &NamespaceError{Adapter: adapterName, Namespace: namespace, TokenNamespace: token.NamespaceID}
+	}
+	return r.InvokeContext(ctx, adapterName, token, currentPosture, params)
+}
@@ -0,0 +1,38 @@
+// WHY: Proves a token minted in one namespace never reaches an adapter
+// for a call acting for another, and that the refusal is typed and
+// counted.
+package adapters
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// TestInvokeInNamespaceRefusesForeignTokens proves only a token minted in
+// the call's namespace reaches the adapter
+func TestInvokeInNamespaceRefusesForeignTokens(t *testing.T) {
+	registry := NewRegistry()
+	mock := NewMockAdapter("model")
+	registry.Register(mock)
+	token := mintLimitToken(t, "model")
+
+	_, err := registry.InvokeInNamespace(context.Background(), "other_namespace", "model", token, 1, map[string]interface{}{})
+	var foreign *NamespaceError
+	if !errors.As(err, &foreign) || foreign.TokenNamespace != "test_namespace" || foreign.Namespace != "other_namespace" {
+		t.Fatalf("want a NamespaceError, got %v", err)
+	}
+	if len(mock.GetInvocations()) != 0 {
+		t.Fatal("a foreign token must not reach the adapter")
+	}
+	if stats, _ := registry.StatsFor("model"); stats.Refused != 1 {
+		t.Fatalf("the refusal must be counted, got %+v", stats)
+	}
+
+	if _, err := registry.InvokeInNamespace(context.Background(), "test_namespace", "model", token, 1, map[string]interface{}{}); err != nil {
+		t.Fatalf("a token of the call's namespace must be honoured: %v", err)
+	}
+	if _, err := registry.InvokeInNamespace(context.Background(), "test_namespace", "model", nil, 1, map[string]interface{}{}); err == nil {
+		t.Fatal("a tokenless call must still be refused")
+	}
+}
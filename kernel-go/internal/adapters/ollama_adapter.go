@@ -0,0 +1,212 @@
+// WHY: Air-gapped deployments cannot reach a hosted model, but they still
+// need a real model behind the corridor to exercise it end to end. This
+// adapter speaks the Ollama chat API to a local inference server and picks
+// the model by posture: operators map the more constrained postures to
+// smaller, more predictable models, and a posture with no model is refused.
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/cdi"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// Defaults for local model adapters
+const (
+	DefaultOllamaURL     = "http://127.0.0.1:11434"
+	DefaultOllamaTimeout = 2 * time.Minute
+)
+
+// OllamaConfig configures a local Ollama chat adapter
+type OllamaConfig struct {
+	// Name is the adapter name and the scope tokens must carry
+	Name string
+
+	// BaseURL is the server root (default DefaultOllamaURL)
+	BaseURL string
+
+	// Models maps each permitted posture level to the model used at that
+	// posture. Postures without an entry are refused.
+	Models map[int]string
+
+	// Timeout bounds each call (default DefaultOllamaTimeout; local
+	// inference on CPU is slow)
+	Timeout time.Duration
+
+	// Client overrides the HTTP client, mainly for tests
+	Client *http.Client
+}
+
+// OllamaAdapter calls a local Ollama /api/chat endpoint
+type OllamaAdapter struct {
+	config OllamaConfig
+	client *http.Client
+}
+
+// NewOllamaAdapter validates the configuration and creates the adapter
+func NewOllamaAdapter(config OllamaConfig) (*OllamaAdapter, error) {
+	if config.Name == "" {
+		return nil, fmt.Errorf("ollama adapter: empty name")
+	}
+	if len(config.Models) == 0 {
+		return nil, fmt.Errorf("ollama adapter %s: no posture models configured", config.Name)
+	}
+
+	models := make(map[int]string, len(config.Models))
+	for level, model := range config.Models {
+		if !posture.IsValid(level) {
+			return nil, fmt.Errorf("ollama adapter %s: invalid posture %d", config.Name, level)
+		}
+		if model == "" {
+			return nil, fmt.Errorf("ollama adapter %s: empty model for posture %d", config.Name, level)
+		}
+		models[level] = model
+	}
+	config.Models = models
+
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultOllamaURL
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultOllamaTimeout
+	}
+
+	client := config.Client
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &OllamaAdapter{config: config, client: client}, nil
+}
+
+// Name returns the adapter identifier
+func (a *OllamaAdapter) Name() string {
+	return a.config.Name
+}
+
+// Declare reports that prompts go to the configured Ollama server
+func (a *OllamaAdapter) Declare() cdi.CapabilityDeclaration {
+	return cdi.CapabilityDeclaration{
+		Name:        a.config.Name,
+		Risk:        cdi.RiskLow,
+		SideEffects: []string{cdi.SideEffectNetwork},
+	}
+}
+
+// ModelFor returns the model configured for a posture
+func (a *OllamaAdapter) ModelFor(currentPosture int) (string, error) {
+	model, ok := a.config.Models[currentPosture]
+	if !ok {
+		return "", fmt.Errorf("adapter %s has no model for posture %d", a.config.Name, currentPosture)
+	}
+	return model, nil
+}
+
+// VerifyToken checks token validity, scope, and that the posture has a model
+func (a *OllamaAdapter) VerifyToken(token *capabilities.Token, currentPosture int) error {
+	if token == nil {
+		return fmt.Errorf("nil token - tokenless invocation rejected")
+	}
+
+	valid, err := token.Verify(currentPosture)
+	if !valid {
+		return fmt.Errorf("token verification failed: %w", err)
+	}
+
+	if !token.HasScope(a.config.Name) {
+		return fmt.Errorf("token does not have scope for adapter %s", a.config.Name)
+	}
+
+	_, err = a.ModelFor(currentPosture)
+	return err
+}
+
+// Ollama chat wire format, limited to the fields the adapter uses
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []openAIMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+	Options  *ollamaOptions  `json:"options,omitempty"`
+}
+
+type ollamaOptions struct {
+	NumPredict int `json:"num_predict"`
+}
+
+type ollamaResponse struct {
+	Model           string        `json:"model"`
+	Message         openAIMessage `json:"message"`
+	Done            bool          `json:"done"`
+	PromptEvalCount int           `json:"prompt_eval_count"`
+	EvalCount       int           `json:"eval_count"`
+}
+
+// Invoke sends the request input to the model selected for the verified
+// posture. The token's MaxBudget, when set, caps generated tokens.
+func (a *OllamaAdapter) Invoke(ctx context.Context, token *capabilities.Token, params map[string]interface{}) (*AdapterResult, error) {
+	if token == nil {
+		return nil, fmt.Errorf("nil token - invoke rejected")
+	}
+
+	input, ok := params[capabilities.ParamInput].(string)
+	if !ok {
+		return nil, fmt.Errorf("ollama adapter %s: missing %q parameter", a.config.Name, capabilities.ParamInput)
+	}
+	// WHY: Without a verified posture the model choice would be a guess
+	currentPosture, ok := params[ParamPosture].(int)
+	if !ok {
+		return nil, fmt.Errorf("ollama adapter %s: missing verified posture", a.config.Name)
+	}
+	model, err := a.ModelFor(currentPosture)
+	if err != nil {
+		return nil, err
+	}
+
+	request := ollamaRequest{
+		Model:    model,
+		Messages: []openAIMessage{{Role: "user", Content: input}},
+	}
+	if token.Limits.MaxBudget > 0 {
+		request.Options = &ollamaOptions{NumPredict: token.Limits.MaxBudget}
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("ollama adapter %s: encode request: %w", a.config.Name, err)
+	}
+
+	respBody, err := postExchange(ctx, a.client, a.config.BaseURL+"/api/chat", nil, body, a.config.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("ollama adapter %s: %w", a.config.Name, err)
+	}
+
+	var chat ollamaResponse
+	if err := json.Unmarshal(respBody, &chat); err != nil {
+		return nil, fmt.Errorf("ollama adapter %s: decode response: %w", a.config.Name, err)
+	}
+	if !chat.Done {
+		return nil, fmt.Errorf("ollama adapter %s: incomplete response", a.config.Name)
+	}
+
+	calls, err := toolCalls(chat.Message.ToolCalls)
+	if err != nil {
+		return nil, fmt.Errorf("ollama adapter %s: %w", a.config.Name, err)
+	}
+
+	return &AdapterResult{
+		Status:      StatusSuccess,
+		Content:     chat.Message.Content,
+		ContentType: ContentTypeText,
+		ToolCalls:   calls,
+		Usage:       Usage{PromptTokens: chat.PromptEvalCount, CompletionTokens: chat.EvalCount},
+		Provenance:  Provenance{Source: chat.Model},
+		Exchange:    exchangeOf(body, respBody),
+		Details:     map[string]interface{}{"posture": currentPosture},
+	}, nil
+}
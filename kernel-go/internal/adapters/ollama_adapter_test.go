@@ -0,0 +1,70 @@
+// WHY: These tests prove the local model adapter picks its model from the
+// verified posture and refuses postures it has no model for.
+package adapters
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// TestOllamaAdapterSelectsModelByPosture proves the registry's verified
+// posture, not a caller-supplied one, selects the model
+func TestOllamaAdapterSelectsModelByPosture(t *testing.T) {
+	var models []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request ollamaRequest
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &request)
+		models = append(models, request.Model)
+		json.NewEncoder(w).Encode(ollamaResponse{Model: request.Model, Message: openAIMessage{Role: "assistant", Content: "ok"}, Done: true})
+	}))
+	defer server.Close()
+
+	adapter, err := NewOllamaAdapter(OllamaConfig{
+		Name:    "local_model",
+		BaseURL: server.URL,
+		Models:  map[int]string{posture.P1: "llama3:8b", posture.P3: "phi3:mini"},
+	})
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+
+	registry := NewRegistry()
+	registry.Register(adapter)
+	token := mintModelToken(t, "local_model", 0)
+
+	// A spoofed posture parameter is overwritten by the verified one
+	params := map[string]interface{}{capabilities.ParamInput: "hello", ParamPosture: posture.P1}
+	if _, err := registry.Invoke("local_model", token, posture.P3, params); err != nil {
+		t.Fatalf("invoke at P3: %v", err)
+	}
+	if _, err := registry.Invoke("local_model", token, posture.P1, params); err != nil {
+		t.Fatalf("invoke at P1: %v", err)
+	}
+	if len(models) != 2 || models[0] != "phi3:mini" || models[1] != "llama3:8b" {
+		t.Fatalf("unexpected model selection: %v", models)
+	}
+
+	if _, err := registry.Invoke("local_model", token, posture.P2, params); err == nil {
+		t.Fatal("posture without a configured model must be refused")
+	}
+	if len(models) != 2 {
+		t.Fatal("refused posture must not reach the server")
+	}
+}
+
+// TestOllamaAdapterRejectsInvalidConfig proves misconfiguration fails at construction
+func TestOllamaAdapterRejectsInvalidConfig(t *testing.T) {
+	if _, err := NewOllamaAdapter(OllamaConfig{Name: "local_model"}); err == nil {
+		t.Fatal("adapter without models must be rejected")
+	}
+	if _, err := NewOllamaAdapter(OllamaConfig{Name: "local_model", Models: map[int]string{posture.P0: "m"}}); err == nil {
+		t.Fatal("model for undefined posture P0 must be rejected")
+	}
+}
@@ -0,0 +1,234 @@
+// WHY: The corridor needs a real model to govern. Most hosted and
+// self-hosted model servers speak the OpenAI chat completions API, so one
+// adapter covers them all. The adapter adds its own scope and posture
+// checks on top of the registry's, bounds every call with a timeout, and
+// reports hashes of the exact bytes exchanged so the ledger can commit to
+// them.
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/cdi"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// DefaultOpenAITimeout bounds a completion call when no timeout is configured
+const DefaultOpenAITimeout = 30 * time.Second
+
+// OpenAIConfig configures an OpenAI-compatible chat completions adapter
+type OpenAIConfig struct {
+	// Name is the adapter name and the scope tokens must carry
+	Name string
+
+	// BaseURL is the API root, e.g. https://api.openai.com/v1
+	BaseURL string
+
+	// APIKey is sent as a bearer token; empty for servers without auth
+	APIKey string
+
+	// Model is the model requested for every completion
+	Model string
+
+	// MaxPosture is the most constrained posture the adapter may run at
+	// (default posture.P3). WHY: At P4 (oracle/read-only) sending content
+	// to an external model is itself a widening the posture forbids.
+	MaxPosture int
+
+	// Timeout bounds each call (default DefaultOpenAITimeout)
+	Timeout time.Duration
+
+	// Client overrides the HTTP client, mainly for tests
+	Client *http.Client
+}
+
+// OpenAIAdapter calls an OpenAI-compatible /chat/completions endpoint
+type OpenAIAdapter struct {
+	config OpenAIConfig
+	client *http.Client
+}
+
+// NewOpenAIAdapter validates the configuration and creates the adapter
+func NewOpenAIAdapter(config OpenAIConfig) (*OpenAIAdapter, error) {
+	if config.Name == "" {
+		return nil, fmt.Errorf("openai adapter: empty name")
+	}
+	if config.BaseURL == "" {
+		return nil, fmt.Errorf("openai adapter %s: empty base URL", config.Name)
+	}
+	if config.Model == "" {
+		return nil, fmt.Errorf("openai adapter %s: empty model", config.Name)
+	}
+	if config.MaxPosture == 0 {
+		config.MaxPosture = posture.P3
+	}
+	if !posture.IsValid(config.MaxPosture) {
+		return nil, fmt.Errorf("openai adapter %s: invalid max posture %d", config.Name, config.MaxPosture)
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultOpenAITimeout
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+
+	client := config.Client
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &OpenAIAdapter{config: config, client: client}, nil
+}
+
+// Name returns the adapter identifier
+func (a *OpenAIAdapter) Name() string {
+	return a.config.Name
+}
+
+// Declare reports that prompts leave the host
+func (a *OpenAIAdapter) Declare() cdi.CapabilityDeclaration {
+	return cdi.CapabilityDeclaration{
+		Name:        a.config.Name,
+		Risk:        cdi.RiskMedium,
+		SideEffects: []string{cdi.SideEffectNetwork},
+	}
+}
+
+// VerifyToken checks token validity, scope, and the adapter's posture bound
+// WHY: Fail closed - an unknown posture or one beyond MaxPosture refuses the call
+func (a *OpenAIAdapter) VerifyToken(token *capabilities.Token, currentPosture int) error {
+	if token == nil {
+		return fmt.Errorf("nil token - tokenless invocation rejected")
+	}
+
+	valid, err := token.Verify(currentPosture)
+	if !valid {
+		return fmt.Errorf("token verification failed: %w", err)
+	}
+
+	if !token.HasScope(a.config.Name) {
+		return fmt.Errorf("token does not have scope for adapter %s", a.config.Name)
+	}
+
+	if !posture.IsValid(currentPosture) || currentPosture > a.config.MaxPosture {
+		return fmt.Errorf("adapter %s not permitted at posture %d (max %d)", a.config.Name, currentPosture, a.config.MaxPosture)
+	}
+
+	return nil
+}
+
+// chat completions wire format, limited to the fields the adapter uses
+type openAIMessage struct {
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	ToolCalls []openAIToolCall `json:"tool_calls,omitempty"`
+}
+
+// openAIToolCall is shared with Ollama, which sends arguments as an object
+// where OpenAI sends a JSON-encoded string
+type openAIToolCall struct {
+	ID       string `json:"id"`
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"function"`
+}
+
+// toolCalls converts wire tool calls, decoding string-encoded arguments
+func toolCalls(calls []openAIToolCall) ([]ToolCall, error) {
+	converted := make([]ToolCall, 0, len(calls))
+	for _, call := range calls {
+		arguments := call.Function.Arguments
+		var encoded string
+		if err := json.Unmarshal(arguments, &encoded); err == nil {
+			arguments = json.RawMessage(encoded)
+		}
+		if len(arguments) == 0 {
+			arguments = json.RawMessage("{}")
+		}
+		var object map[string]interface{}
+		if err := json.Unmarshal(arguments, &object); err != nil {
+			return nil, fmt.Errorf("tool call %s: arguments are not a JSON object", call.Function.Name)
+		}
+		converted = append(converted, ToolCall{ID: call.ID, Name: call.Function.Name, Arguments: arguments})
+	}
+	return converted, nil
+}
+
+type openAIRequest struct {
+	Model     string          `json:"model"`
+	Messages  []openAIMessage `json:"messages"`
+	MaxTokens int             `json:"max_tokens,omitempty"`
+}
+
+type openAIResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message      openAIMessage `json:"message"`
+		FinishReason string        `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// Invoke sends the request input as a single user message.
+// WHY: The token's MaxBudget, when set, caps completion tokens so a grant
+// cannot buy more model output than CDI approved.
+func (a *OpenAIAdapter) Invoke(ctx context.Context, token *capabilities.Token, params map[string]interface{}) (*AdapterResult, error) {
+	if token == nil {
+		return nil, fmt.Errorf("nil token - invoke rejected")
+	}
+
+	input, ok := params[capabilities.ParamInput].(string)
+	if !ok {
+		return nil, fmt.Errorf("openai adapter %s: missing %q parameter", a.config.Name, capabilities.ParamInput)
+	}
+
+	body, err := json.Marshal(openAIRequest{
+		Model:     a.config.Model,
+		Messages:  []openAIMessage{{Role: "user", Content: input}},
+		MaxTokens: token.Limits.MaxBudget,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("openai adapter %s: encode request: %w", a.config.Name, err)
+	}
+
+	headers := map[string]string{}
+	if a.config.APIKey != "" {
+		headers["Authorization"] = "Bearer " + a.config.APIKey
+	}
+	respBody, err := postExchange(ctx, a.client, a.config.BaseURL+"/chat/completions", headers, body, a.config.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("openai adapter %s: %w", a.config.Name, err)
+	}
+
+	var completion openAIResponse
+	if err := json.Unmarshal(respBody, &completion); err != nil {
+		return nil, fmt.Errorf("openai adapter %s: decode response: %w", a.config.Name, err)
+	}
+	if len(completion.Choices) == 0 {
+		return nil, fmt.Errorf("openai adapter %s: response has no choices", a.config.Name)
+	}
+	choice := completion.Choices[0]
+
+	calls, err := toolCalls(choice.Message.ToolCalls)
+	if err != nil {
+		return nil, fmt.Errorf("openai adapter %s: %w", a.config.Name, err)
+	}
+
+	return &AdapterResult{
+		Status:      StatusSuccess,
+		Content:     choice.Message.Content,
+		ContentType: ContentTypeText,
+		ToolCalls:   calls,
+		Usage:       Usage{PromptTokens: completion.Usage.PromptTokens, CompletionTokens: completion.Usage.CompletionTokens},
+		Provenance:  Provenance{Source: completion.Model},
+		Exchange:    exchangeOf(body, respBody),
+		Details:     map[string]interface{}{"finish_reason": choice.FinishReason},
+	}, nil
+}
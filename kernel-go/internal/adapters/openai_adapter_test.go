@@ -0,0 +1,123 @@
+// WHY: These tests prove the OpenAI-compatible adapter enforces scope and
+// posture, bounds calls in time, and commits to the exact bytes exchanged.
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// mintModelToken mints a token scoped to one adapter
+func mintModelToken(t *testing.T, scope string, budget int) *capabilities.Token {
+	t.Helper()
+	token, err := capabilities.Mint("kernel", "test_subject", "adapters", []string{scope},
+		capabilities.Limits{MaxBudget: budget}, time.Minute,
+		capabilities.PostureBounds{MinPosture: posture.P1, MaxPosture: posture.P4},
+		"test_namespace", "test_principal")
+	if err != nil {
+		t.Fatalf("mint: %v", err)
+	}
+	return token
+}
+
+// TestOpenAIAdapterHashesExchange proves the adapter sends the input and
+// budget, authenticates, and reports hashes of the raw bodies
+func TestOpenAIAdapterHashesExchange(t *testing.T) {
+	var requestBody []byte
+	responseBody := `{"model":"test-model","choices":[{"message":{"role":"assistant","content":"hi there"},"finish_reason":"stop"}],"usage":{"prompt_tokens":3,"completion_tokens":2}}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" || r.Header.Get("Authorization") != "Bearer sk-test" {
+			http.Error(w, "bad request", http.StatusUnauthorized)
+			return
+		}
+		requestBody, _ = io.ReadAll(r.Body)
+		io.WriteString(w, responseBody)
+	}))
+	defer server.Close()
+
+	adapter, err := NewOpenAIAdapter(OpenAIConfig{Name: "openai", BaseURL: server.URL + "/v1/", APIKey: "sk-test", Model: "test-model"})
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+
+	token := mintModelToken(t, "openai", 64)
+	result, err := adapter.Invoke(context.Background(), token, map[string]interface{}{capabilities.ParamInput: "hello"})
+	if err != nil {
+		t.Fatalf("invoke: %v", err)
+	}
+
+	var sent openAIRequest
+	if err := json.Unmarshal(requestBody, &sent); err != nil {
+		t.Fatalf("decode sent request: %v", err)
+	}
+	if sent.Model != "test-model" || sent.MaxTokens != 64 || sent.Messages[0].Content != "hello" {
+		t.Fatalf("unexpected request: %+v", sent)
+	}
+
+	if result.Content != "hi there" || result.Usage.CompletionTokens == 0 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if result.Exchange == nil || result.Exchange.RequestHash != HashExchange(requestBody) || result.Exchange.ResponseHash != HashExchange([]byte(responseBody)) {
+		t.Fatal("exchange hashes must commit to the raw bodies")
+	}
+}
+
+// TestOpenAIAdapterEnforcesScopeAndPosture proves a wrong scope or a
+// posture beyond the adapter's bound refuses the call
+func TestOpenAIAdapterEnforcesScopeAndPosture(t *testing.T) {
+	adapter, err := NewOpenAIAdapter(OpenAIConfig{Name: "openai", BaseURL: "http://127.0.0.1:1", Model: "m", MaxPosture: posture.P2})
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+
+	if err := adapter.VerifyToken(mintModelToken(t, "openai", 0), posture.P2); err != nil {
+		t.Fatalf("token within bounds rejected: %v", err)
+	}
+	if err := adapter.VerifyToken(mintModelToken(t, "other_adapter", 0), posture.P1); err == nil {
+		t.Fatal("token without the adapter scope must be rejected")
+	}
+	if err := adapter.VerifyToken(mintModelToken(t, "openai", 0), posture.P3); err == nil {
+		t.Fatal("posture above MaxPosture must be rejected")
+	}
+	if err := adapter.VerifyToken(nil, posture.P1); err == nil {
+		t.Fatal("nil token must be rejected")
+	}
+}
+
+// TestOpenAIAdapterTimesOutAndHidesErrorBodies proves stalled servers are
+// cut off and error bodies never reach the caller
+func TestOpenAIAdapterTimesOutAndHidesErrorBodies(t *testing.T) {
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer slow.Close()
+	defer close(release)
+
+	adapter, _ := NewOpenAIAdapter(OpenAIConfig{Name: "openai", BaseURL: slow.URL, Model: "m", Timeout: 50 * time.Millisecond})
+	_, err := adapter.Invoke(context.Background(), mintModelToken(t, "openai", 0), map[string]interface{}{capabilities.ParamInput: "hello"})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "prompt was: hello", http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	adapter, _ = NewOpenAIAdapter(OpenAIConfig{Name: "openai", BaseURL: failing.URL, Model: "m"})
+	_, err = adapter.Invoke(context.Background(), mintModelToken(t, "openai", 0), map[string]interface{}{capabilities.ParamInput: "hello"})
+	if err == nil || strings.Contains(err.Error(), "prompt") {
+		t.Fatalf("error must report status only, got %v", err)
+	}
+}
@@ -0,0 +1,533 @@
+// WHY: An adapter linked into the kernel shares its memory: a crash takes
+// the corridor down and a malicious adapter can rewrite kernel state. A
+// plugin adapter runs as a separate process under OS resource limits and
+// sees only the token and parameters of each call. The contract mirrors
+// Adapter (Name, VerifyToken, Invoke) and is carried as net/rpc with the
+// JSON codec over the child's stdin/stdout, which keeps the module free of
+// external dependencies; a gRPC transport would carry the same three calls.
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/cdi"
+)
+
+// Defaults for plugin adapters
+const (
+	DefaultPluginTimeout   = 30 * time.Second
+	DefaultPluginMemory    = 4 << 30
+	DefaultPluginOpenFiles = 256
+	pluginServiceName      = "Plugin"
+	pluginHandshakeTimeout = 10 * time.Second
+	pluginShutdownGrace    = time.Second
+)
+
+// PluginVerifyArgs is the wire form of a VerifyToken call
+type PluginVerifyArgs struct {
+	Token   *capabilities.Token
+	Posture int
+}
+
+// PluginInvokeArgs is the wire form of an Invoke call. Posture travels on
+// its own because JSON would turn the ParamPosture int into a float.
+type PluginInvokeArgs struct {
+	Token   *capabilities.Token
+	Params  map[string]interface{}
+	Posture int
+}
+
+// PluginInvokeReply is the wire form of an Invoke result
+type PluginInvokeReply struct {
+	Result *AdapterResult
+}
+
+// PluginAttestArgs is the wire form of the attestation handshake; Kernel
+// is nil when the host has no attestation key
+type PluginAttestArgs struct {
+	Kernel *KernelAttestation
+	Nonce  string
+}
+
+// PluginAttestReply reports the plugin's own build and echoes the nonce
+type PluginAttestReply struct {
+	BuildHash string
+	Nonce     string
+}
+
+// pluginService exposes an in-process adapter to a plugin host
+type pluginService struct {
+	adapter Adapter
+
+	// trust, if set, holds every token call until the kernel attests
+	trust *PluginTrust
+
+	mu       sync.Mutex
+	attested bool
+}
+
+func (s *pluginService) Attest(args PluginAttestArgs, reply *PluginAttestReply) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locate own build: %w", err)
+	}
+	buildHash, err := HashBuild(executable)
+	if err != nil {
+		return fmt.Errorf("hash own build: %w", err)
+	}
+	if s.trust != nil {
+		if err := s.trust.verify(args.Kernel, s.adapter.Name(), buildHash, time.Now()); err != nil {
+			return err
+		}
+		if args.Kernel.Nonce != args.Nonce {
+			return fmt.Errorf("kernel attestation nonce does not match the handshake")
+		}
+	}
+	s.mu.Lock()
+	s.attested = true
+	s.mu.Unlock()
+	reply.BuildHash = buildHash
+	reply.Nonce = args.Nonce
+	return nil
+}
+
+// checkAttested refuses token calls until a trusted kernel has attested
+func (s *pluginService) checkAttested() error {
+	if s.trust == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.attested {
+		return fmt.Errorf("kernel has not attested; call refused")
+	}
+	return nil
+}
+
+func (s *pluginService) Name(_ struct{}, reply *string) error {
+	*reply = s.adapter.Name()
+	return nil
+}
+
+func (s *pluginService) VerifyToken(args PluginVerifyArgs, _ *struct{}) error {
+	if err := s.checkAttested(); err != nil {
+		return err
+	}
+	return s.adapter.VerifyToken(args.Token, args.Posture)
+}
+
+func (s *pluginService) Invoke(args PluginInvokeArgs, reply *PluginInvokeReply) error {
+	if err := s.checkAttested(); err != nil {
+		return err
+	}
+	params := args.Params
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	params[ParamPosture] = args.Posture
+	// WHY: The host enforces deadlines by killing the process, so the
+	// plugin side needs no context of its own.
+	result, err := s.adapter.Invoke(context.Background(), args.Token, params)
+	if err != nil {
+		return err
+	}
+	reply.Result = result
+	return nil
+}
+
+// ServePlugin serves adapter on stdin/stdout until the host closes the
+// pipe. A plugin binary's main calls it and does nothing else.
+func ServePlugin(adapter Adapter) error {
+	return servePlugin(&pluginService{adapter: adapter}, stdioConn{Reader: os.Stdin, Writer: os.Stdout})
+}
+
+// ServeAttestedPlugin is ServePlugin for a plugin that refuses every call
+// until the kernel presents an attestation trust accepts
+func ServeAttestedPlugin(adapter Adapter, trust PluginTrust) error {
+	return servePlugin(&pluginService{adapter: adapter, trust: &trust}, stdioConn{Reader: os.Stdin, Writer: os.Stdout})
+}
+
+// servePlugin serves one connection
+func servePlugin(service *pluginService, conn io.ReadWriteCloser) error {
+	server := rpc.NewServer()
+	if err := server.RegisterName(pluginServiceName, service); err != nil {
+		return err
+	}
+	server.ServeCodec(jsonrpc.NewServerCodec(conn))
+	return nil
+}
+
+// stdioConn joins a reader and writer into one connection
+type stdioConn struct {
+	io.Reader
+	io.Writer
+}
+
+func (stdioConn) Close() error { return nil }
+
+// PluginConfig configures an out-of-process adapter
+type PluginConfig struct {
+	// Name is the adapter name; the plugin must report the same name
+	Name string
+
+	// Path is the plugin executable; Args are passed to it
+	Path string
+	Args []string
+
+	// Env is the plugin's entire environment. WHY: The kernel's own
+	// environment (credentials included) is never inherited.
+	Env []string
+
+	// Timeout bounds each call; a plugin that overruns it is killed
+	// (default DefaultPluginTimeout)
+	Timeout time.Duration
+
+	// Resource limits applied to the plugin process. CPUSeconds is
+	// cumulative over the process lifetime; zero leaves it unlimited.
+	CPUSeconds  int
+	MemoryBytes int64 // default DefaultPluginMemory
+	OpenFiles   int   // default DefaultPluginOpenFiles
+
+	// Risk and SideEffects are what the host declares for the plugin.
+	// WHY: The plugin cannot vouch for itself, so an undeclared plugin is
+	// treated as high risk with every side effect.
+	Risk        string
+	SideEffects []string
+
+	// Isolation, if set, runs the plugin under an isolation profile
+	// instead of the ulimit wrapper
+	Isolation *Isolation
+
+	// Attestor, if set, refuses builds not pinned for the plugin and
+	// attests the kernel to each plugin process it starts
+	Attestor *Attestor
+}
+
+// PluginAdapter is the host side of an out-of-process adapter
+type PluginAdapter struct {
+	config PluginConfig
+
+	mu      sync.Mutex
+	process *pluginProcess
+}
+
+// pluginProcess is one running plugin and its RPC client
+type pluginProcess struct {
+	cmd    *exec.Cmd
+	client *rpc.Client
+	exited chan struct{}
+}
+
+// NewPluginAdapter validates the configuration and starts the plugin
+func NewPluginAdapter(config PluginConfig) (*PluginAdapter, error) {
+	if config.Name == "" {
+		return nil, fmt.Errorf("plugin adapter: empty name")
+	}
+	if config.Path == "" {
+		return nil, fmt.Errorf("plugin adapter %s: empty path", config.Name)
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultPluginTimeout
+	}
+	if config.MemoryBytes <= 0 {
+		config.MemoryBytes = DefaultPluginMemory
+	}
+	if config.OpenFiles <= 0 {
+		config.OpenFiles = DefaultPluginOpenFiles
+	}
+	if config.Isolation != nil {
+		if err := config.Isolation.validate(); err != nil {
+			return nil, fmt.Errorf("plugin adapter %s: %w", config.Name, err)
+		}
+	}
+
+	adapter := &PluginAdapter{config: config}
+	adapter.mu.Lock()
+	defer adapter.mu.Unlock()
+	if _, err := adapter.startLocked(); err != nil {
+		return nil, err
+	}
+	return adapter, nil
+}
+
+// Name returns the adapter identifier
+func (a *PluginAdapter) Name() string {
+	return a.config.Name
+}
+
+// Declare reports the risk and side effects configured on the host
+func (a *PluginAdapter) Declare() cdi.CapabilityDeclaration {
+	declaration := cdi.CapabilityDeclaration{
+		Name:        a.config.Name,
+		Risk:        a.config.Risk,
+		SideEffects: a.config.SideEffects,
+	}
+	if declaration.Risk == "" {
+		declaration.Risk = cdi.RiskHigh
+	}
+	if len(declaration.SideEffects) == 0 {
+		declaration.SideEffects = []string{cdi.SideEffectRead, cdi.SideEffectWrite, cdi.SideEffectNetwork, cdi.SideEffectExec}
+	}
+	return declaration
+}
+
+// VerifyToken runs the kernel's own checks, then lets the plugin refuse.
+// WHY: The plugin is untrusted, so its answer can only narrow: a plugin
+// that approves everything still gets no call the kernel would refuse.
+func (a *PluginAdapter) VerifyToken(token *capabilities.Token, currentPosture int) error {
+	if token == nil {
+		return fmt.Errorf("nil token - tokenless invocation rejected")
+	}
+
+	valid, err := token.Verify(currentPosture)
+	if !valid {
+		return fmt.Errorf("token verification failed: %w", err)
+	}
+
+	if !token.HasScope(a.config.Name) {
+		return fmt.Errorf("token does not have scope for adapter %s", a.config.Name)
+	}
+
+	var reply struct{}
+	return a.call(context.Background(), "VerifyToken", PluginVerifyArgs{Token: token, Posture: currentPosture}, &reply)
+}
+
+// Invoke forwards the call to the plugin process
+func (a *PluginAdapter) Invoke(ctx context.Context, token *capabilities.Token, params map[string]interface{}) (*AdapterResult, error) {
+	if token == nil {
+		return nil, fmt.Errorf("nil token - invoke rejected")
+	}
+
+	forwarded := make(map[string]interface{}, len(params))
+	for key, value := range params {
+		if key != ParamPosture {
+			forwarded[key] = value
+		}
+	}
+	currentPosture, _ := params[ParamPosture].(int)
+
+	var reply PluginInvokeReply
+	if err := a.call(ctx, "Invoke", PluginInvokeArgs{Token: token, Params: forwarded, Posture: currentPosture}, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Result, nil
+}
+
+// Close stops the plugin process
+func (a *PluginAdapter) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.process != nil {
+		a.process.kill()
+		a.process = nil
+	}
+	return nil
+}
+
+// call runs one RPC with the call timeout, or until ctx is done.
+// WHY: A plugin that hangs, crashes, or breaks the protocol is killed and
+// restarted on the next call, so one bad invocation cannot wedge the
+// adapter. Errors the plugin returns on purpose leave it running.
+func (a *PluginAdapter) call(ctx context.Context, method string, args interface{}, reply interface{}) error {
+	a.mu.Lock()
+	process, err := a.startLocked()
+	a.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	pending := process.client.Go(pluginServiceName+"."+method, args, reply, make(chan *rpc.Call, 1))
+	select {
+	case <-pending.Done:
+		var serverErr rpc.ServerError
+		if errors.As(pending.Error, &serverErr) {
+			return fmt.Errorf("plugin adapter %s: %s", a.config.Name, string(serverErr))
+		}
+		if pending.Error != nil {
+			a.discard(process)
+			return fmt.Errorf("plugin adapter %s: plugin failed: %w", a.config.Name, pending.Error)
+		}
+		return nil
+	case <-time.After(a.config.Timeout):
+		a.discard(process)
+		return fmt.Errorf("plugin adapter %s: %s timed out after %s; plugin killed", a.config.Name, method, a.config.Timeout)
+	case <-ctx.Done():
+		a.discard(process)
+		return fmt.Errorf("plugin adapter %s: %s abandoned: %w; plugin killed", a.config.Name, method, ctx.Err())
+	}
+}
+
+// discard kills process if it is still the current one
+func (a *PluginAdapter) discard(process *pluginProcess) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.process == process {
+		a.process = nil
+	}
+	process.kill()
+}
+
+// startLocked returns the running plugin, starting one if needed; a.mu is held
+func (a *PluginAdapter) startLocked() (*pluginProcess, error) {
+	if a.process != nil {
+		select {
+		case <-a.process.exited:
+			a.process.kill()
+			a.process = nil
+		default:
+			return a.process, nil
+		}
+	}
+
+	var buildHash string
+	if a.config.Attestor != nil {
+		path, err := exec.LookPath(a.config.Path)
+		if err == nil {
+			buildHash, err = a.config.Attestor.checkBuild(a.config.Name, path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("plugin adapter %s: %w", a.config.Name, err)
+		}
+	}
+
+	limits := rlimits{
+		CPUSeconds:  a.config.CPUSeconds,
+		MemoryBytes: a.config.MemoryBytes,
+		OpenFiles:   a.config.OpenFiles,
+	}
+	var cmd *exec.Cmd
+	cleanup := func() {}
+	if a.config.Isolation != nil {
+		var err error
+		cmd, cleanup, err = a.config.Isolation.command(context.Background(), a.config.Name, a.config.Path, a.config.Args, limits)
+		if err != nil {
+			return nil, fmt.Errorf("plugin adapter %s: %w", a.config.Name, err)
+		}
+	} else {
+		argv := limits.argv(a.config.Path, a.config.Args)
+		cmd = exec.Command(argv[0], argv[1:]...)
+	}
+	cmd.Env = append([]string{}, a.config.Env...)
+
+	// WHY: Explicit pipes rather than StdinPipe/StdoutPipe, so reaping the
+	// process never closes the host's ends under an in-flight read
+	childIn, hostOut, err := os.Pipe()
+	if err != nil {
+		cleanup()
+		return nil, fmt.Errorf("plugin adapter %s: %w", a.config.Name, err)
+	}
+	hostIn, childOut, err := os.Pipe()
+	if err != nil {
+		childIn.Close()
+		hostOut.Close()
+		cleanup()
+		return nil, fmt.Errorf("plugin adapter %s: %w", a.config.Name, err)
+	}
+	cmd.Stdin = childIn
+	cmd.Stdout = childOut
+	err = cmd.Start()
+	childIn.Close()
+	childOut.Close()
+	if err != nil {
+		hostIn.Close()
+		hostOut.Close()
+		cleanup()
+		return nil, fmt.Errorf("plugin adapter %s: start: %w", a.config.Name, err)
+	}
+
+	process := &pluginProcess{
+		cmd:    cmd,
+		client: jsonrpc.NewClient(stdioPipe{ReadCloser: hostIn, WriteCloser: hostOut}),
+		exited: make(chan struct{}),
+	}
+	go func() {
+		cmd.Wait()
+		cleanup()
+		close(process.exited)
+	}()
+
+	// WHY: A plugin that answers to another adapter's name could receive
+	// calls meant for it, so the name is checked before first use
+	var name string
+	pending := process.client.Go(pluginServiceName+".Name", struct{}{}, &name, make(chan *rpc.Call, 1))
+	select {
+	case <-pending.Done:
+	case <-time.After(pluginHandshakeTimeout):
+		pending.Error = fmt.Errorf("no handshake within %s", pluginHandshakeTimeout)
+	}
+	if pending.Error == nil && name != a.config.Name {
+		pending.Error = fmt.Errorf("plugin reports name %q", name)
+	}
+	if pending.Error == nil && a.config.Attestor != nil {
+		pending.Error = a.attest(process, buildHash)
+	}
+	if pending.Error != nil {
+		process.kill()
+		return nil, fmt.Errorf("plugin adapter %s: handshake: %w", a.config.Name, pending.Error)
+	}
+
+	a.process = process
+	return process, nil
+}
+
+// attest runs the attestation handshake with a freshly started plugin.
+// WHY: The executable was hashed before it started; the plugin's own
+// report catches a binary swapped between the hash and the exec.
+func (a *PluginAdapter) attest(process *pluginProcess, buildHash string) error {
+	nonce, err := attestationNonce()
+	if err != nil {
+		return err
+	}
+	kernel, err := a.config.Attestor.attest(a.config.Name, buildHash, nonce)
+	if err != nil {
+		return err
+	}
+
+	var reply PluginAttestReply
+	pending := process.client.Go(pluginServiceName+".Attest", PluginAttestArgs{Kernel: kernel, Nonce: nonce}, &reply, make(chan *rpc.Call, 1))
+	select {
+	case <-pending.Done:
+	case <-time.After(pluginHandshakeTimeout):
+		return fmt.Errorf("no attestation within %s", pluginHandshakeTimeout)
+	}
+	if pending.Error != nil {
+		return fmt.Errorf("attestation: %w", pending.Error)
+	}
+	if reply.Nonce != nonce || reply.BuildHash != buildHash {
+		return fmt.Errorf("plugin reports build %s, not the pinned build", reply.BuildHash)
+	}
+	return nil
+}
+
+// kill closes the pipes and terminates the process
+func (p *pluginProcess) kill() {
+	p.client.Close()
+	select {
+	case <-p.exited:
+		return
+	case <-time.After(pluginShutdownGrace):
+	}
+	p.cmd.Process.Kill()
+	<-p.exited
+}
+
+// stdioPipe joins the child's stdout and stdin into one connection
+type stdioPipe struct {
+	io.ReadCloser
+	io.WriteCloser
+}
+
+func (p stdioPipe) Close() error {
+	p.WriteCloser.Close()
+	return p.ReadCloser.Close()
+}
@@ -0,0 +1,160 @@
+// WHY: These tests prove plugin adapters run in their own process, cannot
+// widen the kernel's token checks, and survive crashing or hanging plugins.
+// The test binary doubles as the plugin (see TestPluginHelperProcess).
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/cdi"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// pluginHelperEnv selects the helper process behaviour
+const pluginHelperEnv = "OI_PLUGIN_HELPER"
+
+// helperAdapter approves every token and misbehaves on request
+type helperAdapter struct{ name string }
+
+func (h helperAdapter) Name() string { return h.name }
+
+func (h helperAdapter) Declare() cdi.CapabilityDeclaration {
+	return cdi.CapabilityDeclaration{Name: h.name, Risk: cdi.RiskLow}
+}
+
+func (h helperAdapter) VerifyToken(*capabilities.Token, int) error { return nil }
+
+func (h helperAdapter) Invoke(_ context.Context, _ *capabilities.Token, params map[string]interface{}) (*AdapterResult, error) {
+	switch params["mode"] {
+	case "crash":
+		os.Exit(3)
+	case "hang":
+		select {}
+	case "fail":
+		return nil, fmt.Errorf("refused by plugin")
+	}
+	return &AdapterResult{
+		Status:  StatusSuccess,
+		Content: "pong",
+		Details: map[string]interface{}{
+			"pid":     os.Getpid(),
+			"secret":  os.Getenv("OI_PLUGIN_SECRET"),
+			"posture": params[ParamPosture],
+		},
+	}, nil
+}
+
+// TestPluginHelperProcess is the plugin side; it only runs when started
+// by a plugin host
+func TestPluginHelperProcess(t *testing.T) {
+	name := os.Getenv(pluginHelperEnv)
+	if name == "" {
+		return
+	}
+	ServePlugin(helperAdapter{name: name})
+	os.Exit(0)
+}
+
+// newHelperPlugin starts the test binary as a plugin reporting reportName
+func newHelperPlugin(t *testing.T, name, reportName string, timeout time.Duration) (*PluginAdapter, error) {
+	t.Helper()
+	adapter, err := NewPluginAdapter(PluginConfig{
+		Name:    name,
+		Path:    os.Args[0],
+		Args:    []string{"-test.run=^TestPluginHelperProcess$"},
+		Env:     []string{pluginHelperEnv + "=" + reportName},
+		Timeout: timeout,
+	})
+	if err == nil {
+		t.Cleanup(func() { adapter.Close() })
+	}
+	return adapter, err
+}
+
+// mintPluginToken mints a token with the given scopes
+func mintPluginToken(t *testing.T, scopes ...string) *capabilities.Token {
+	t.Helper()
+	token, err := capabilities.Mint("kernel", "test_subject", "adapters", scopes,
+		capabilities.Limits{}, time.Minute,
+		capabilities.PostureBounds{MinPosture: posture.P1, MaxPosture: posture.P4},
+		"test_namespace", "test_principal")
+	if err != nil {
+		t.Fatalf("mint: %v", err)
+	}
+	return token
+}
+
+// TestPluginAdapterRunsOutOfProcess proves calls run in a separate process
+// with a scrubbed environment, and a plugin that approves everything
+// cannot override the kernel's scope check
+func TestPluginAdapterRunsOutOfProcess(t *testing.T) {
+	t.Setenv("OI_PLUGIN_SECRET", "kernel-credential")
+	adapter, err := newHelperPlugin(t, "echo", "echo", 0)
+	if err != nil {
+		t.Fatalf("new plugin: %v", err)
+	}
+	registry := NewRegistry()
+	registry.Register(adapter)
+
+	if _, err := registry.Invoke("echo", mintPluginToken(t, "other"), posture.P2, map[string]interface{}{}); err == nil {
+		t.Fatal("kernel scope check must apply even when the plugin approves")
+	}
+
+	result, err := registry.Invoke("echo", mintPluginToken(t, "echo"), posture.P2, map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("invoke: %v", err)
+	}
+	resultMap := result.Details
+	if result.Content != "pong" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if int(resultMap["pid"].(float64)) == os.Getpid() {
+		t.Fatal("plugin must run in a separate process")
+	}
+	if resultMap["secret"] != "" {
+		t.Fatal("plugin must not inherit the kernel's environment")
+	}
+	if int(resultMap["posture"].(float64)) != posture.P2 {
+		t.Fatalf("plugin must receive the verified posture, got %v", resultMap["posture"])
+	}
+
+	if _, err := newHelperPlugin(t, "echo", "mock_adapter", 0); err == nil {
+		t.Fatal("plugin reporting a different name must be refused")
+	}
+}
+
+// TestPluginAdapterSurvivesCrashAndHang proves a crashing or hanging
+// plugin fails only its own call and is restarted for the next one
+func TestPluginAdapterSurvivesCrashAndHang(t *testing.T) {
+	adapter, err := newHelperPlugin(t, "flaky", "flaky", 500*time.Millisecond)
+	if err != nil {
+		t.Fatalf("new plugin: %v", err)
+	}
+	token := mintPluginToken(t, "flaky")
+	invoke := func(mode string) error {
+		_, err := adapter.Invoke(context.Background(), token, map[string]interface{}{"mode": mode, ParamPosture: posture.P1})
+		return err
+	}
+
+	if err := invoke("fail"); err == nil || !strings.Contains(err.Error(), "refused by plugin") {
+		t.Fatalf("plugin errors must be returned: %v", err)
+	}
+	if err := invoke("crash"); err == nil {
+		t.Fatal("crashed plugin must fail the call")
+	}
+	if err := invoke("ok"); err != nil {
+		t.Fatalf("plugin must restart after a crash: %v", err)
+	}
+	if err := invoke("hang"); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("hung plugin must time out: %v", err)
+	}
+	if err := invoke("ok"); err != nil {
+		t.Fatalf("plugin must restart after a hang: %v", err)
+	}
+}
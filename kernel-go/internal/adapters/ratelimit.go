@@ -0,0 +1,95 @@
+// WHY: Token limits bound what one grant may do, but a runaway agent loop
+// can mint grant after grant and hammer an external API with each of them.
+// Per-adapter limits bound the total load on the thing behind the adapter,
+// whoever holds the tokens.
+package adapters
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Throttle reasons reported in ThrottleError
+const (
+	ThrottleRate        = "rate"
+	ThrottleConcurrency = "concurrency"
+)
+
+// RateLimit bounds calls to one adapter; zero fields are unlimited
+type RateLimit struct {
+	// QPS is the sustained calls per second
+	QPS float64
+
+	// Burst is how many calls may arrive at once before QPS applies
+	// (default QPS rounded up, at least 1)
+	Burst int
+
+	// MaxConcurrent caps in-flight calls across all tokens
+	MaxConcurrent int
+}
+
+// ThrottleError reports a call refused by an adapter's rate limit.
+// WHY: A distinct type lets the kernel record throttling separately from
+// authorization failures.
+type ThrottleError struct {
+	Adapter string
+	Reason  string // ThrottleRate or ThrottleConcurrency
+}
+
+func (e *ThrottleError) Error() string {
+	return fmt.Sprintf("adapter %s throttled: %s limit reached", e.Adapter, e.Reason)
+}
+
+// adapterLimiter enforces one adapter's RateLimit with a token bucket and
+// an in-flight counter
+type adapterLimiter struct {
+	mu       sync.Mutex
+	limit    RateLimit
+	tokens   float64
+	last     time.Time
+	inFlight int
+	now      func() time.Time
+}
+
+// newAdapterLimiter validates limit and returns a limiter with a full bucket
+func newAdapterLimiter(limit RateLimit) (*adapterLimiter, error) {
+	if limit.QPS < 0 || math.IsNaN(limit.QPS) || math.IsInf(limit.QPS, 0) || limit.Burst < 0 || limit.MaxConcurrent < 0 {
+		return nil, fmt.Errorf("invalid rate limit %+v", limit)
+	}
+	if limit.QPS > 0 && limit.Burst == 0 {
+		limit.Burst = int(math.Max(1, math.Ceil(limit.QPS)))
+	}
+	limiter := &adapterLimiter{limit: limit, tokens: float64(limit.Burst), now: time.Now}
+	limiter.last = limiter.now()
+	return limiter, nil
+}
+
+// acquire admits one call or reports which limit refused it
+func (l *adapterLimiter) acquire(adapterName string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limit.MaxConcurrent > 0 && l.inFlight >= l.limit.MaxConcurrent {
+		return &ThrottleError{Adapter: adapterName, Reason: ThrottleConcurrency}
+	}
+	if l.limit.QPS > 0 {
+		now := l.now()
+		l.tokens = math.Min(float64(l.limit.Burst), l.tokens+now.Sub(l.last).Seconds()*l.limit.QPS)
+		l.last = now
+		if l.tokens < 1 {
+			return &ThrottleError{Adapter: adapterName, Reason: ThrottleRate}
+		}
+		l.tokens--
+	}
+	l.inFlight++
+	return nil
+}
+
+// release ends one admitted call
+func (l *adapterLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inFlight--
+}
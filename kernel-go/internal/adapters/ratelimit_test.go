@@ -0,0 +1,104 @@
+// WHY: These tests prove adapter rate limits hold across tokens, so fresh
+// grants cannot be used to outrun them.
+package adapters
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+)
+
+// mintLimitToken mints a fresh token for adapterName
+func mintLimitToken(t *testing.T, adapterName string) *capabilities.Token {
+	t.Helper()
+	token, err := capabilities.Mint("test_issuer", "test_subject", "test_audience", []string{adapterName},
+		capabilities.Limits{}, 5*time.Minute,
+		capabilities.PostureBounds{MinPosture: 1, MaxPosture: 4},
+		"test_namespace", "test_principal")
+	if err != nil {
+		t.Fatalf("failed to mint token: %v", err)
+	}
+	return token
+}
+
+// TestRegistryRateLimitsAcrossTokens proves QPS is enforced per adapter
+// with a burst allowance, refills over time, and reports a throttle error
+func TestRegistryRateLimitsAcrossTokens(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(NewMockAdapter("api"))
+	if err := registry.SetRateLimit("api", RateLimit{QPS: 1, Burst: 2}); err != nil {
+		t.Fatalf("set rate limit: %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	limiter := registry.limiter("api")
+	limiter.now = func() time.Time { return now }
+	limiter.last = now
+
+	invoke := func() error {
+		_, err := registry.Invoke("api", mintLimitToken(t, "api"), 1, map[string]interface{}{})
+		return err
+	}
+	for i := 0; i < 2; i++ {
+		if err := invoke(); err != nil {
+			t.Fatalf("burst call %d: %v", i, err)
+		}
+	}
+
+	var throttled *ThrottleError
+	if err := invoke(); !errors.As(err, &throttled) || throttled.Reason != ThrottleRate || throttled.Adapter != "api" {
+		t.Fatalf("expected rate throttle, got %v", err)
+	}
+
+	now = now.Add(time.Second)
+	if err := invoke(); err != nil {
+		t.Fatalf("call after refill should succeed: %v", err)
+	}
+
+	if err := registry.SetRateLimit("missing", RateLimit{QPS: 1}); err == nil {
+		t.Fatal("limits for unregistered adapters must be refused")
+	}
+	if err := registry.SetRateLimit("api", RateLimit{QPS: -1}); err == nil {
+		t.Fatal("negative limits must be refused")
+	}
+	registry.SetRateLimit("api", RateLimit{})
+	if err := invoke(); err != nil {
+		t.Fatalf("zero limit should remove throttling: %v", err)
+	}
+}
+
+// TestRegistryAdapterConcurrencyLimit proves in-flight calls are capped per
+// adapter even when every call carries a different token
+func TestRegistryAdapterConcurrencyLimit(t *testing.T) {
+	registry := NewRegistry()
+	adapter := &blockingAdapter{
+		MockAdapter: NewMockAdapter("slow_api"),
+		started:     make(chan struct{}, 1),
+		release:     make(chan struct{}),
+	}
+	registry.Register(adapter)
+	registry.SetRateLimit("slow_api", RateLimit{MaxConcurrent: 1})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := registry.Invoke("slow_api", mintLimitToken(t, "slow_api"), 1, map[string]interface{}{})
+		done <- err
+	}()
+	<-adapter.started
+
+	var throttled *ThrottleError
+	_, err := registry.Invoke("slow_api", mintLimitToken(t, "slow_api"), 1, map[string]interface{}{})
+	if !errors.As(err, &throttled) || throttled.Reason != ThrottleConcurrency {
+		t.Fatalf("expected concurrency throttle, got %v", err)
+	}
+
+	close(adapter.release)
+	if err := <-done; err != nil {
+		t.Fatalf("first call failed: %v", err)
+	}
+	adapter.started = make(chan struct{}, 1)
+	if _, err := registry.Invoke("slow_api", mintLimitToken(t, "slow_api"), 1, map[string]interface{}{}); err != nil {
+		t.Fatalf("call after release should succeed: %v", err)
+	}
+}
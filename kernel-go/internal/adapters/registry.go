@@ -3,10 +3,15 @@
 package adapters
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/cdi"
+	"github.com/user/oi/kernel-go/internal/signing"
 )
 
 // Adapter is the interface all model/tool adapters must implement.
@@ -17,22 +22,67 @@ type Adapter interface {
 
 	// Invoke executes the adapter's operation with a valid capability token
 	// WHY: No tokenless calls - fail closed
-	Invoke(token *capabilities.Token, params map[string]interface{}) (interface{}, error)
+	Invoke(ctx context.Context, token *capabilities.Token, params map[string]interface{}) (*AdapterResult, error)
 
 	// VerifyToken checks if the token is valid for this adapter
 	VerifyToken(token *capabilities.Token, currentPosture int) error
+
+	// Declare reports the adapter's risk class, side effects, and scopes
+	// WHY: CDI narrows DEGRADE decisions using these declarations
+	Declare() cdi.CapabilityDeclaration
 }
 
+// ParamPosture is the invocation parameter carrying the posture the call was
+// verified at. WHY: The registry sets it after token verification, so an
+// adapter that varies behaviour by posture sees the verified value, never
+// one supplied by the caller.
+const ParamPosture = "posture"
+
 // Registry manages all registered adapters.
 type Registry struct {
 	mu       sync.RWMutex
 	adapters map[string]Adapter
+	calls    map[string]*adapterCalls
+	limiters map[string]*adapterLimiter
+	breakers map[string]*circuitBreaker
+	timeouts map[string]time.Duration
+	stats    *statsBook
+
+	// preInvoke and postInvoke are the interceptors, in order added
+	preInvoke  []PreInvoke
+	postInvoke []PostInvoke
+
+	// onBreakerChange, if set, is told of every breaker state change
+	onBreakerChange func(BreakerTransition)
+
+	// onAdapterChange, if set, is told of every deregistration and swap
+	onAdapterChange func(AdapterChange)
+
+	// allowlist, if set, bounds the adapters each posture may reach
+	allowlist PostureAllowlist
+
+	// tokenKeys, if set, are the keys every token must be signed by
+	tokenKeys *signing.KeyRing
+
+	// inFlight counts active invocations per token digest
+	inFlightMu sync.Mutex
+	inFlight   map[string]int
+
+	// stops holds the cancellation of in-flight calls (see Stop)
+	stops *stopBook
 }
 
 // NewRegistry creates a new adapter registry
 func NewRegistry() *Registry {
 	return &Registry{
 		adapters: make(map[string]Adapter),
+		calls:    make(map[string]*adapterCalls),
+		limiters: make(map[string]*adapterLimiter),
+		breakers: make(map[string]*circuitBreaker),
+		timeouts: make(map[string]time.Duration),
+		stats:    newStatsBook(),
+		inFlight: make(map[string]int),
+		stops:    newStopBook(),
 	}
 }
 
@@ -46,11 +96,25 @@ func (r *Registry) Register(adapter Adapter) error {
 	if _, exists := r.adapters[name]; exists {
 		return fmt.Errorf("adapter %s already registered", name)
 	}
+	if err := checkDeclaration(adapter); err != nil {
+		return err
+	}
 
 	r.adapters[name] = adapter
+	r.calls[name] = newAdapterCalls()
 	return nil
 }
 
+// checkDeclaration refuses an adapter whose declaration is invalid or
+// names a different adapter
+func checkDeclaration(adapter Adapter) error {
+	declaration := adapter.Declare()
+	if declaration.Name != adapter.Name() {
+		return fmt.Errorf("adapter %s declares itself as %q", adapter.Name(), declaration.Name)
+	}
+	return declaration.Validate()
+}
+
 // Get retrieves an adapter by name
 func (r *Registry) Get(name string) (Adapter, error) {
 	r.mu.RLock()
@@ -64,6 +128,156 @@ func (r *Registry) Get(name string) (Adapter, error) {
 	return adapter, nil
 }
 
+// begin looks up an adapter and records a call to it, so a concurrent
+// Deregister or Replace waits for the call to return
+func (r *Registry) begin(name string) (Adapter, *adapterCalls, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	adapter, exists := r.adapters[name]
+	if !exists {
+		return nil, nil, fmt.Errorf("adapter %s not found", name)
+	}
+	calls := r.calls[name]
+	calls.begin()
+	return adapter, calls, nil
+}
+
+// SetRateLimit applies limit to a registered adapter, replacing any
+// previous limit; a zero RateLimit removes it
+func (r *Registry) SetRateLimit(name string, limit RateLimit) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.adapters[name]; !exists {
+		return fmt.Errorf("adapter %s not found", name)
+	}
+	if limit == (RateLimit{}) {
+		delete(r.limiters, name)
+		return nil
+	}
+	limiter, err := newAdapterLimiter(limit)
+	if err != nil {
+		return fmt.Errorf("adapter %s: %w", name, err)
+	}
+	r.limiters[name] = limiter
+	return nil
+}
+
+// limiter returns the adapter's rate limiter, or nil if it has none
+func (r *Registry) limiter(name string) *adapterLimiter {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.limiters[name]
+}
+
+// SetCircuitBreaker puts a registered adapter behind a circuit breaker,
+// replacing any previous one; a zero BreakerConfig removes it
+func (r *Registry) SetCircuitBreaker(name string, config BreakerConfig) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.adapters[name]; !exists {
+		return fmt.Errorf("adapter %s not found", name)
+	}
+	if config == (BreakerConfig{}) {
+		delete(r.breakers, name)
+		return nil
+	}
+	breaker, err := newCircuitBreaker(name, config)
+	if err != nil {
+		return fmt.Errorf("adapter %s: %w", name, err)
+	}
+	r.breakers[name] = breaker
+	return nil
+}
+
+// OnBreakerChange registers fn to receive every breaker state change.
+// WHY: The registry has no ledger; the kernel records the changes.
+func (r *Registry) OnBreakerChange(fn func(BreakerTransition)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onBreakerChange = fn
+}
+
+// BreakerState returns the adapter's breaker state, or "" if it has none
+func (r *Registry) BreakerState(name string) string {
+	if breaker := r.breaker(name); breaker != nil {
+		return breaker.currentState()
+	}
+	return ""
+}
+
+// breaker returns the adapter's circuit breaker, or nil if it has none
+func (r *Registry) breaker(name string) *circuitBreaker {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.breakers[name]
+}
+
+// reportBreaker passes a state change to the observer, if any
+func (r *Registry) reportBreaker(change *BreakerTransition) {
+	if change == nil {
+		return
+	}
+	r.mu.RLock()
+	fn := r.onBreakerChange
+	r.mu.RUnlock()
+	if fn != nil {
+		fn(*change)
+	}
+}
+
+// SetTimeout bounds each call to a registered adapter, replacing any
+// previous timeout; zero restores DefaultAdapterTimeout. A token's own
+// timeout still applies when it is tighter.
+func (r *Registry) SetTimeout(name string, timeout time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.adapters[name]; !exists {
+		return fmt.Errorf("adapter %s not found", name)
+	}
+	if timeout < 0 {
+		return fmt.Errorf("adapter %s: negative timeout %s", name, timeout)
+	}
+	if timeout == 0 {
+		delete(r.timeouts, name)
+		return nil
+	}
+	r.timeouts[name] = timeout
+	return nil
+}
+
+// SetTokenKeys requires every token a call carries to be signed by one of
+// keys; nil lifts the requirement.
+// WHY: A token's digest is only as good as the record it is looked up in;
+// a signature proves the kernel's mint key issued the token as it stands.
+func (r *Registry) SetTokenKeys(keys *signing.KeyRing) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokenKeys = keys
+}
+
+// checkTokenSignature refuses a token not signed by the trusted keys, if
+// the registry has any
+func (r *Registry) checkTokenSignature(token *capabilities.Token) error {
+	r.mu.RLock()
+	keys := r.tokenKeys
+	r.mu.RUnlock()
+	if keys == nil {
+		return nil
+	}
+	return token.VerifySignature(keys)
+}
+
+// timeout returns the adapter's configured timeout, or 0 if it has none
+func (r *Registry) timeout(name string) time.Duration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.timeouts[name]
+}
+
 // ListAdapters returns all registered adapter names
 func (r *Registry) ListAdapters() []string {
 	r.mu.RLock()
@@ -76,20 +290,199 @@ func (r *Registry) ListAdapters() []string {
 	return names
 }
 
+// Declarations returns every registered adapter's capability declaration,
+// sorted by name
+func (r *Registry) Declarations() []cdi.CapabilityDeclaration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	declarations := make([]cdi.CapabilityDeclaration, 0, len(r.adapters))
+	for _, adapter := range r.adapters {
+		declarations = append(declarations, adapter.Declare())
+	}
+	sort.Slice(declarations, func(i, j int) bool { return declarations[i].Name < declarations[j].Name })
+	return declarations
+}
+
 // Invoke executes an adapter with capability verification.
 // WHY: Central chokepoint - all adapter calls go through here.
-func (r *Registry) Invoke(adapterName string, token *capabilities.Token, currentPosture int, params map[string]interface{}) (interface{}, error) {
-	adapter, err := r.Get(adapterName)
+func (r *Registry) Invoke(adapterName string, token *capabilities.Token, currentPosture int, params map[string]interface{}) (*AdapterResult, error) {
+	return r.InvokeContext(context.Background(), adapterName, token, currentPosture, params)
+}
+
+// InvokeContext is Invoke with cancellation: the call is abandoned when ctx
+// is done or the adapter's timeout passes, and the adapter's context is
+// cancelled with it
+func (r *Registry) InvokeContext(ctx context.Context, adapterName string, token *capabilities.Token, currentPosture int, params map[string]interface{}) (*AdapterResult, error) {
+	// Slots are released when the adapter returns, which may be after a
+	// timed-out call has been abandoned
+	var releases []func()
+	done := func() {
+		for i := len(releases) - 1; i >= 0; i-- {
+			releases[i]()
+		}
+	}
+	handedOff := false
+	defer func() {
+		if !handedOff {
+			done()
+		}
+	}()
+
+	adapter, calls, err := r.begin(adapterName)
 	if err != nil {
 		return nil, err
 	}
+	releases = append(releases, calls.end)
+
+	// Track the call so STOP can cancel it, whatever stage it is at
+	stopCtx, cancelStop := context.WithCancelCause(ctx)
+	defer cancelStop(nil)
+	releases = append(releases, r.track(token, cancelStop))
+	refuse := func(err error) (*AdapterResult, error) {
+		r.stats.refused(adapterName)
+		return nil, err
+	}
+
+	// Refuse adapters the posture puts out of reach, whatever the token says
+	if err := r.checkAllowlist(adapter, currentPosture); err != nil {
+		return refuse(err)
+	}
 
 	// Verify token before invocation
 	if err := adapter.VerifyToken(token, currentPosture); err != nil {
-		return nil, fmt.Errorf("token verification failed: %w", err)
+		return refuse(fmt.Errorf("token verification failed: %w", err))
+	}
+	if err := r.checkTokenSignature(token); err != nil {
+		return refuse(fmt.Errorf("token verification failed: %w", err))
+	}
+
+	// Verify the payload is the request the token was minted for
+	if err := token.VerifyRequestBinding(params); err != nil {
+		return refuse(fmt.Errorf("token request binding failed: %w", err))
+	}
+
+	// Let interceptors verify or scrub the call
+	pre, post := r.interceptors()
+	call := &Call{Adapter: adapterName, Token: token, Posture: currentPosture, Params: withPosture(params, currentPosture)}
+	if err := runPreInvoke(pre, call); err != nil {
+		return refuse(err)
 	}
 
-	// Invoke the adapter
-	result, err := adapter.Invoke(token, params)
-	return result, err
+	// Enforce the adapter's own limits, whoever holds the token
+	if limiter := r.limiter(adapterName); limiter != nil {
+		if err := limiter.acquire(adapterName); err != nil {
+			return refuse(err)
+		}
+		releases = append(releases, limiter.release)
+	}
+
+	// Enforce the token's concurrency limit
+	if err := r.acquire(token); err != nil {
+		return refuse(err)
+	}
+	releases = append(releases, func() { r.release(token) })
+
+	// Fail fast while the backend is known to be failing
+	breaker := r.breaker(adapterName)
+	if breaker != nil {
+		change, err := breaker.allow()
+		r.reportBreaker(change)
+		if err != nil {
+			return refuse(err)
+		}
+	}
+
+	// Invoke the adapter under its deadline
+	timeout := callTimeout(r.timeout(adapterName), token)
+	callCtx, cancel := context.WithTimeout(stopCtx, timeout)
+	defer cancel()
+
+	started := time.Now()
+	handedOff = true
+	result, err := invokeWithin(callCtx, adapter, token, withPosture(call.Params, currentPosture), done)
+	stopped := err != nil && stoppedCall(stopCtx)
+	if stopped {
+		err = &StoppedError{Adapter: adapterName}
+	} else if err != nil && ctx.Err() == nil && callCtx.Err() == context.DeadlineExceeded {
+		err = &TimeoutError{Adapter: adapterName, Timeout: timeout}
+	}
+	if err == nil {
+		err = checkResult(adapterName, result)
+	}
+	elapsed := time.Since(started)
+	if breaker != nil {
+		r.reportBreaker(breaker.record(err != nil && !stopped, elapsed))
+	}
+
+	// Let interceptors observe the outcome and label or refuse the result
+	outcome := &Outcome{Result: result, Err: err, Elapsed: elapsed}
+	if err != nil {
+		outcome.Result = nil
+	}
+	runPostInvoke(post, call, outcome)
+	if outcome.Err == nil {
+		outcome.Err = checkResult(adapterName, outcome.Result)
+	}
+	r.stats.record(adapterName, outcome)
+	if outcome.Err != nil {
+		return nil, outcome.Err
+	}
+	return outcome.Result, nil
+}
+
+// checkResult refuses a missing or malformed result.
+// WHY: The kernel records commitments straight from the result, so a
+// result it cannot trust is a failed call.
+func checkResult(adapterName string, result *AdapterResult) error {
+	if result == nil {
+		return fmt.Errorf("adapter %s returned no result", adapterName)
+	}
+	if err := result.Validate(); err != nil {
+		return fmt.Errorf("adapter %s returned an invalid result: %w", adapterName, err)
+	}
+	return nil
+}
+
+// withPosture copies params with ParamPosture set to the verified posture
+func withPosture(params map[string]interface{}, currentPosture int) map[string]interface{} {
+	copied := make(map[string]interface{}, len(params)+1)
+	for key, value := range params {
+		copied[key] = value
+	}
+	copied[ParamPosture] = currentPosture
+	return copied
+}
+
+// acquire reserves an in-flight slot for the token.
+// WHY: A token authorizes a bounded amount of parallel work; fanning one
+// grant out across many concurrent calls exceeds what CDI approved.
+func (r *Registry) acquire(token *capabilities.Token) error {
+	r.inFlightMu.Lock()
+	defer r.inFlightMu.Unlock()
+
+	limit := token.Limits.MaxConcurrent
+	if limit > 0 && r.inFlight[token.Digest] >= limit {
+		return fmt.Errorf("token concurrency limit reached: %d in-flight calls allowed", limit)
+	}
+	r.inFlight[token.Digest]++
+	return nil
+}
+
+// release frees an in-flight slot for the token
+func (r *Registry) release(token *capabilities.Token) {
+	r.inFlightMu.Lock()
+	defer r.inFlightMu.Unlock()
+
+	r.inFlight[token.Digest]--
+	if r.inFlight[token.Digest] <= 0 {
+		delete(r.inFlight, token.Digest)
+	}
+}
+
+// InFlight returns the number of active invocations for a token digest
+func (r *Registry) InFlight(tokenDigest string) int {
+	r.inFlightMu.Lock()
+	defer r.inFlightMu.Unlock()
+	return r.inFlight[tokenDigest]
 }
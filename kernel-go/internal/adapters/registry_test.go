@@ -3,10 +3,14 @@
 package adapters
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"testing"
 	"time"
 
 	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/cdi"
 )
 
 // TestAdapterRefusesTokenlessInvocation proves CI-1: no tokenless calls
@@ -58,7 +62,7 @@ func TestEveryAdapterInvocationHasTokenDigest(t *testing.T) {
 	}
 
 	// Invoke adapter
-	_, err = adapter.Invoke(token, map[string]interface{}{"test": "data"})
+	_, err = adapter.Invoke(context.Background(), token, map[string]interface{}{"test": "data"})
 	if err != nil {
 		t.Fatalf("adapter invocation failed: %v", err)
 	}
@@ -186,3 +190,150 @@ func TestExpiredTokenRejected(t *testing.T) {
 		t.Fatal("expected error for expired token, got nil")
 	}
 }
+
+// blockingAdapter holds each invocation open until released
+type blockingAdapter struct {
+	*MockAdapter
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingAdapter) Invoke(ctx context.Context, token *capabilities.Token, params map[string]interface{}) (*AdapterResult, error) {
+	b.started <- struct{}{}
+	<-b.release
+	return b.MockAdapter.Invoke(context.Background(), token, params)
+}
+
+// TestTokenConcurrencyLimitEnforced proves one token cannot fan out beyond its limit
+func TestTokenConcurrencyLimitEnforced(t *testing.T) {
+	registry := NewRegistry()
+	adapter := &blockingAdapter{
+		MockAdapter: NewMockAdapter("slow_adapter"),
+		started:     make(chan struct{}, 1),
+		release:     make(chan struct{}),
+	}
+	registry.Register(adapter)
+
+	token, err := capabilities.Mint(
+		"test_issuer",
+		"test_subject",
+		"test_audience",
+		[]string{"slow_adapter"},
+		capabilities.Limits{MaxDepth: 10, MaxBudget: 100, MaxConcurrent: 1},
+		5*time.Minute,
+		capabilities.PostureBounds{MinPosture: 1, MaxPosture: 4},
+		"test_namespace",
+		"test_principal",
+	)
+	if err != nil {
+		t.Fatalf("failed to mint token: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := registry.Invoke("slow_adapter", token, 1, map[string]interface{}{})
+		done <- err
+	}()
+	<-adapter.started
+
+	if registry.InFlight(token.Digest) != 1 {
+		t.Fatalf("expected 1 in-flight call, got %d", registry.InFlight(token.Digest))
+	}
+
+	// Second concurrent call with the same token must be refused
+	if _, err := registry.Invoke("slow_adapter", token, 1, map[string]interface{}{}); err == nil {
+		t.Fatal("expected concurrency limit error for second in-flight call")
+	}
+
+	close(adapter.release)
+	if err := <-done; err != nil {
+		t.Fatalf("first call failed: %v", err)
+	}
+
+	// Once the slot is released the token may be used again
+	adapter.started = make(chan struct{}, 1)
+	if _, err := registry.Invoke("slow_adapter", token, 1, map[string]interface{}{}); err != nil {
+		t.Fatalf("call after release should succeed: %v", err)
+	}
+	if registry.InFlight(token.Digest) != 0 {
+		t.Fatal("in-flight count should return to zero")
+	}
+}
+
+// TestRequestBoundTokenRejectsDifferentPayload proves tokens cannot be replayed
+// against a payload other than the request they were minted for
+func TestRequestBoundTokenRejectsDifferentPayload(t *testing.T) {
+	registry := NewRegistry()
+	adapter := NewMockAdapter("test_adapter")
+	registry.Register(adapter)
+
+	requestHash := sha256.Sum256([]byte("summarize my notes"))
+	token, err := capabilities.MintWithProvenance(
+		"test_issuer",
+		"test_subject",
+		"test_audience",
+		[]string{"test_adapter"},
+		capabilities.Limits{MaxDepth: 10, MaxBudget: 100},
+		5*time.Minute,
+		capabilities.PostureBounds{MinPosture: 1, MaxPosture: 4},
+		"test_namespace",
+		"test_principal",
+		capabilities.Provenance{RequestHash: hex.EncodeToString(requestHash[:])},
+	)
+	if err != nil {
+		t.Fatalf("failed to mint token: %v", err)
+	}
+
+	// Different payload within the TTL must be refused
+	_, err = registry.Invoke("test_adapter", token, 1, map[string]interface{}{"input": "delete all files"})
+	if err == nil {
+		t.Fatal("expected request binding error for different payload")
+	}
+
+	// Missing payload must be refused
+	_, err = registry.Invoke("test_adapter", token, 1, map[string]interface{}{})
+	if err == nil {
+		t.Fatal("expected request binding error for missing payload")
+	}
+
+	if len(adapter.GetInvocations()) != 0 {
+		t.Fatal("no side effect should occur for mismatched payloads")
+	}
+
+	// The original payload is accepted
+	_, err = registry.Invoke("test_adapter", token, 1, map[string]interface{}{"input": "summarize my notes"})
+	if err != nil {
+		t.Fatalf("bound payload should be accepted: %v", err)
+	}
+}
+
+// misdeclaredAdapter overrides the mock's declaration
+type misdeclaredAdapter struct {
+	*MockAdapter
+	declaration cdi.CapabilityDeclaration
+}
+
+func (m *misdeclaredAdapter) Declare() cdi.CapabilityDeclaration { return m.declaration }
+
+// TestRegistryRequiresValidDeclaration proves an adapter that misnames
+// itself or declares nonsense is not registered, and declarations are
+// reported for the rest
+func TestRegistryRequiresValidDeclaration(t *testing.T) {
+	registry := NewRegistry()
+	misnamed := &misdeclaredAdapter{MockAdapter: NewMockAdapter("a"), declaration: cdi.CapabilityDeclaration{Name: "b", Risk: cdi.RiskLow}}
+	if err := registry.Register(misnamed); err == nil {
+		t.Fatal("declaration naming another adapter must be refused")
+	}
+	unknownRisk := &misdeclaredAdapter{MockAdapter: NewMockAdapter("a"), declaration: cdi.CapabilityDeclaration{Name: "a", Risk: "none"}}
+	if err := registry.Register(unknownRisk); err == nil {
+		t.Fatal("declaration with unknown risk must be refused")
+	}
+
+	if err := registry.Register(NewMockAdapter("mock")); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	declarations := registry.Declarations()
+	if len(declarations) != 1 || declarations[0].Name != "mock" || declarations[0].CanWrite() {
+		t.Fatalf("unexpected declarations: %+v", declarations)
+	}
+}
@@ -0,0 +1,125 @@
+// WHY: The kernel used to learn what an adapter did by sniffing string keys
+// out of a map; a misspelt key silently dropped a ledger commitment. A
+// typed result makes every commitment a field the compiler checks, and
+// gives the corridor one shape for content, tool calls, usage, and
+// provenance whatever the adapter.
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Result statuses
+const (
+	StatusSuccess = "success"
+	StatusFailed  = "failed" // the operation ran but reported failure, e.g. a nonzero exit
+)
+
+// Content types adapters report
+const (
+	ContentTypeText = "text/plain; charset=utf-8"
+	ContentTypeJSON = "application/json"
+)
+
+// AdapterResult is what every adapter invocation returns
+type AdapterResult struct {
+	Status string // StatusSuccess or StatusFailed
+
+	// Content is the text handed back through the corridor
+	Content     string
+	ContentType string
+
+	// ToolCalls are structured calls a model asked for; the kernel treats
+	// them as proposals, never as instructions
+	ToolCalls []ToolCall `json:",omitempty"`
+
+	Usage      Usage
+	Provenance Provenance
+
+	// Commitments the kernel records in the ledger; nil when not applicable
+	Exchange *ExchangeCommitment `json:",omitempty"`
+	Write    *WriteCommitment    `json:",omitempty"`
+	Query    *QueryCommitment    `json:",omitempty"`
+
+	// Details holds adapter-specific fields (exit codes, rows, entries)
+	Details map[string]interface{} `json:",omitempty"`
+}
+
+// ToolCall is one structured tool call requested by a model
+type ToolCall struct {
+	ID        string
+	Name      string
+	Arguments json.RawMessage // a JSON object
+}
+
+// Usage is what the call consumed; zero fields were not reported
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+	Cost             float64 // provider-reported cost, in the provider's currency
+}
+
+// Provenance records where the content came from and how CIF labeled it
+type Provenance struct {
+	// Source names the origin: a model, URL, path, tool, or command
+	Source string
+
+	// TaintLabels are the CIF labels of external content; empty means the
+	// adapter produced no external content
+	TaintLabels []string `json:",omitempty"`
+
+	// DecisionID links to a CDI decision the adapter made, e.g. a proposal check
+	DecisionID string `json:",omitempty"`
+}
+
+// ExchangeCommitment hashes the raw bytes exchanged with an external service
+type ExchangeCommitment struct {
+	RequestHash  string
+	ResponseHash string
+}
+
+// WriteCommitment records where content was written and its hash
+type WriteCommitment struct {
+	Target      string
+	ContentHash string
+}
+
+// QueryCommitment records a query's literal-free fingerprint, its hash,
+// and the tables read
+type QueryCommitment struct {
+	Fingerprint string
+	Hash        string
+	Tables      []string
+}
+
+// exchangeOf returns the commitment for a request and response body
+func exchangeOf(request, response []byte) *ExchangeCommitment {
+	return &ExchangeCommitment{RequestHash: HashExchange(request), ResponseHash: HashExchange(response)}
+}
+
+// Validate checks the result is well formed before the kernel relies on it.
+// WHY: Plugins return results over the wire; an empty commitment would be
+// recorded as evidence of nothing.
+func (r *AdapterResult) Validate() error {
+	switch r.Status {
+	case StatusSuccess, StatusFailed:
+	default:
+		return fmt.Errorf("unknown result status %q", r.Status)
+	}
+	if r.Exchange != nil && (r.Exchange.RequestHash == "" || r.Exchange.ResponseHash == "") {
+		return fmt.Errorf("exchange commitment missing a hash")
+	}
+	if r.Write != nil && (r.Write.Target == "" || r.Write.ContentHash == "") {
+		return fmt.Errorf("write commitment missing target or hash")
+	}
+	if r.Query != nil && (r.Query.Fingerprint == "" || r.Query.Hash == "") {
+		return fmt.Errorf("query commitment missing fingerprint or hash")
+	}
+	for _, call := range r.ToolCalls {
+		if call.Name == "" {
+			return fmt.Errorf("tool call without a name")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+// WHY: These tests prove the registry refuses results the kernel could not
+// trust, and tool calls decode to one shape whatever the provider.
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+)
+
+// resultAdapter returns a fixed result
+type resultAdapter struct {
+	*MockAdapter
+	result *AdapterResult
+}
+
+func (r *resultAdapter) Invoke(context.Context, *capabilities.Token, map[string]interface{}) (*AdapterResult, error) {
+	return r.result, nil
+}
+
+// TestRegistryRefusesMalformedResults proves a missing result, an unknown
+// status, or an empty commitment fails the call
+func TestRegistryRefusesMalformedResults(t *testing.T) {
+	malformed := map[string]*AdapterResult{
+		"nil result":       nil,
+		"unknown status":   {Status: "ok"},
+		"empty exchange":   {Status: StatusSuccess, Exchange: &ExchangeCommitment{RequestHash: "abc"}},
+		"empty write":      {Status: StatusSuccess, Write: &WriteCommitment{Target: "/tmp/x"}},
+		"empty query":      {Status: StatusSuccess, Query: &QueryCommitment{Fingerprint: "SELECT ?"}},
+		"unnamed toolcall": {Status: StatusSuccess, ToolCalls: []ToolCall{{ID: "1"}}},
+	}
+	for name, result := range malformed {
+		registry := NewRegistry()
+		registry.Register(&resultAdapter{MockAdapter: NewMockAdapter("typed"), result: result})
+		if got, err := registry.Invoke("typed", mintLimitToken(t, "typed"), 1, map[string]interface{}{}); err == nil || got != nil {
+			t.Fatalf("%s: result must be refused", name)
+		}
+	}
+
+	registry := NewRegistry()
+	registry.Register(&resultAdapter{MockAdapter: NewMockAdapter("typed"), result: &AdapterResult{Status: StatusSuccess, Content: "ok", Exchange: exchangeOf([]byte("a"), []byte("b"))}})
+	if result, err := registry.Invoke("typed", mintLimitToken(t, "typed"), 1, map[string]interface{}{}); err != nil || result.Content != "ok" {
+		t.Fatalf("well-formed result refused: %v", err)
+	}
+}
+
+// TestToolCallsDecodeBothWireShapes proves OpenAI's string-encoded and
+// Ollama's object arguments decode identically, and non-objects are refused
+func TestToolCallsDecodeBothWireShapes(t *testing.T) {
+	var wire []openAIToolCall
+	if err := json.Unmarshal([]byte(`[
+		{"id": "call_1", "function": {"name": "search", "arguments": "{\"q\":\"kernel\"}"}},
+		{"function": {"name": "search", "arguments": {"q": "kernel"}}}
+	]`), &wire); err != nil {
+		t.Fatalf("decode wire: %v", err)
+	}
+	calls, err := toolCalls(wire)
+	if err != nil {
+		t.Fatalf("tool calls: %v", err)
+	}
+	if len(calls) != 2 || calls[0].ID != "call_1" || calls[0].Name != "search" {
+		t.Fatalf("unexpected calls: %+v", calls)
+	}
+	if string(calls[0].Arguments) != `{"q":"kernel"}` || string(calls[1].Arguments) != `{"q": "kernel"}` {
+		t.Fatalf("arguments should be raw JSON objects: %s %s", calls[0].Arguments, calls[1].Arguments)
+	}
+
+	json.Unmarshal([]byte(`[{"function": {"name": "search", "arguments": "[1,2]"}}]`), &wire)
+	if _, err := toolCalls(wire); err == nil {
+		t.Fatal("non-object arguments must be refused")
+	}
+}
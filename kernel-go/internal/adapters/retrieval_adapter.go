@@ -0,0 +1,290 @@
+// WHY: Retrieved passages are other people's text chosen by similarity,
+// which makes them an easy carrier for injected instructions. Every passage
+// the index returns is written to the quarantine partition as it arrived,
+// then re-ingested through CIF; only the sanitized text of passages CIF
+// finds clean is handed on, as data with its labels and quarantine ID. A
+// passage CIF flags is withheld and reachable only through the quarantine
+// promotion ritual, so retrieval never turns content into authority.
+package adapters
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/cdi"
+	"github.com/user/oi/kernel-go/internal/cif"
+	"github.com/user/oi/kernel-go/internal/memory"
+)
+
+// Retrieval parameters; the query text is ParamQuery
+const (
+	ParamCollection = "collection"
+	ParamTopK       = "top_k"
+)
+
+// Defaults for retrieval adapters
+const (
+	DefaultRetrievalName    = "retrieval"
+	DefaultRetrievalTimeout = 15 * time.Second
+	DefaultRetrievalTopK    = 5
+	MaxRetrievalTopK        = 50
+)
+
+// LabelRetrieved marks content that arrived from a retrieval index
+const LabelRetrieved = "retrieved"
+
+// collectionPattern limits collection names to characters that cannot
+// change the meaning of a scope string
+var collectionPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,128}$`)
+
+// RetrievalConfig configures a retrieval adapter over a vector index API:
+// POST {Endpoint}/query with {"collection", "query", "top_k"} returns
+// {"results": [{"id", "text", "score", "source"}]}
+type RetrievalConfig struct {
+	// Name is the adapter name and the scope tokens must carry (default DefaultRetrievalName)
+	Name string
+
+	// Endpoint is the index API base URL
+	Endpoint string
+
+	// Headers are sent with every request, e.g. API credentials
+	Headers map[string]string
+
+	// Memory receives every passage in its quarantine partition
+	Memory *memory.Manager
+
+	// Timeout bounds each query (default DefaultRetrievalTimeout)
+	Timeout time.Duration
+
+	// Client is the HTTP client; nil uses a default client
+	Client *http.Client
+}
+
+// RetrievedPassage is one passage as handed on to the caller. Text is
+// empty when the passage was withheld.
+type RetrievedPassage struct {
+	QuarantineID string   `json:"quarantine_id"`
+	Source       string   `json:"source,omitempty"`
+	Score        float64  `json:"score"`
+	Labels       []string `json:"labels"`
+	Withheld     bool     `json:"withheld,omitempty"`
+	Text         string   `json:"text,omitempty"`
+}
+
+// RetrievalAdapter queries a vector index and quarantines what it returns
+type RetrievalAdapter struct {
+	config RetrievalConfig
+	client *http.Client
+}
+
+// NewRetrievalAdapter validates the configuration and creates the adapter
+func NewRetrievalAdapter(config RetrievalConfig) (*RetrievalAdapter, error) {
+	if config.Name == "" {
+		config.Name = DefaultRetrievalName
+	}
+	if config.Memory == nil {
+		return nil, fmt.Errorf("retrieval adapter %s: no memory manager for quarantine", config.Name)
+	}
+	if config.Endpoint == "" {
+		return nil, fmt.Errorf("retrieval adapter %s: empty endpoint", config.Name)
+	}
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultRetrievalTimeout
+	}
+
+	client := config.Client
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &RetrievalAdapter{config: config, client: client}, nil
+}
+
+// Name returns the adapter identifier
+func (a *RetrievalAdapter) Name() string {
+	return a.config.Name
+}
+
+// Declare reports network reads; what is read is quarantined, not trusted
+func (a *RetrievalAdapter) Declare() cdi.CapabilityDeclaration {
+	return cdi.CapabilityDeclaration{
+		Name:        a.config.Name,
+		Risk:        cdi.RiskLow,
+		SideEffects: []string{cdi.SideEffectNetwork, cdi.SideEffectRead},
+		Scopes:      []string{"retrieval:query:*"},
+	}
+}
+
+// VerifyToken checks token validity and adapter scope. The collection
+// scope needs the request, so it is checked in Invoke.
+func (a *RetrievalAdapter) VerifyToken(token *capabilities.Token, currentPosture int) error {
+	if token == nil {
+		return fmt.Errorf("nil token - tokenless invocation rejected")
+	}
+
+	valid, err := token.Verify(currentPosture)
+	if !valid {
+		return fmt.Errorf("token verification failed: %w", err)
+	}
+
+	if !token.HasScope(a.config.Name) {
+		return fmt.Errorf("token does not have scope for adapter %s", a.config.Name)
+	}
+
+	return nil
+}
+
+// Invoke queries one collection and returns the passages CIF lets through
+func (a *RetrievalAdapter) Invoke(ctx context.Context, token *capabilities.Token, params map[string]interface{}) (*AdapterResult, error) {
+	if token == nil {
+		return nil, fmt.Errorf("nil token - invoke rejected")
+	}
+
+	collection, _ := params[ParamCollection].(string)
+	if !collectionPattern.MatchString(collection) {
+		return nil, fmt.Errorf("retrieval adapter %s: collection %q must match %s", a.config.Name, collection, collectionPattern)
+	}
+	scope := "retrieval:query:" + collection
+	if !token.HasScope(scope) {
+		return nil, fmt.Errorf("retrieval adapter %s: token does not grant %s", a.config.Name, scope)
+	}
+	query, _ := params[ParamQuery].(string)
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("retrieval adapter %s: missing %q parameter", a.config.Name, ParamQuery)
+	}
+	topK := topKParam(params, DefaultRetrievalTopK)
+	if topK < 1 || topK > MaxRetrievalTopK {
+		return nil, fmt.Errorf("retrieval adapter %s: %s must be between 1 and %d", a.config.Name, ParamTopK, MaxRetrievalTopK)
+	}
+
+	requestBody, err := json.Marshal(map[string]interface{}{"collection": collection, "query": query, "top_k": topK})
+	if err != nil {
+		return nil, fmt.Errorf("retrieval adapter %s: encode query: %w", a.config.Name, err)
+	}
+	responseBody, err := postExchange(ctx, a.client, a.config.Endpoint+"/query", a.config.Headers, requestBody, a.config.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("retrieval adapter %s: %w", a.config.Name, err)
+	}
+	var response struct {
+		Results []struct {
+			ID     string  `json:"id"`
+			Text   string  `json:"text"`
+			Score  float64 `json:"score"`
+			Source string  `json:"source"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return nil, fmt.Errorf("retrieval adapter %s: decode results: %w", a.config.Name, err)
+	}
+	// WHY: The index does not get to hand back more than was asked for
+	if len(response.Results) > topK {
+		response.Results = response.Results[:topK]
+	}
+
+	passages := make([]RetrievedPassage, 0, len(response.Results))
+	labels := []string{LabelRetrieved}
+	withheld := 0
+	for _, found := range response.Results {
+		passage, err := a.quarantine(token, collection, found.ID, found.Source, found.Score, found.Text)
+		if err != nil {
+			return nil, fmt.Errorf("retrieval adapter %s: %w", a.config.Name, err)
+		}
+		if passage.Withheld {
+			withheld++
+		}
+		labels = mergeLabels(labels, passage.Labels)
+		passages = append(passages, *passage)
+	}
+
+	content, err := json.Marshal(passages)
+	if err != nil {
+		return nil, fmt.Errorf("retrieval adapter %s: encode passages: %w", a.config.Name, err)
+	}
+	return &AdapterResult{
+		Status:      StatusSuccess,
+		Content:     string(content),
+		ContentType: ContentTypeJSON,
+		Provenance:  Provenance{Source: a.config.Endpoint + "/query#" + collection, TaintLabels: labels},
+		Exchange:    exchangeOf(requestBody, responseBody),
+		Details:     map[string]interface{}{"collection": collection, "passages": len(passages), "withheld": withheld},
+	}, nil
+}
+
+// topKParam reads ParamTopK, returning fallback when it is absent and 0
+// when it is not a whole number
+func topKParam(params map[string]interface{}, fallback int) int {
+	switch value := params[ParamTopK].(type) {
+	case int:
+		return value
+	case float64:
+		// JSON numbers arrive as float64
+		if float64(int(value)) != value {
+			return 0
+		}
+		return int(value)
+	}
+	return fallback
+}
+
+// quarantine writes one passage to the quarantine partition as it arrived
+// and re-ingests it through CIF, withholding its text if CIF flags it
+func (a *RetrievalAdapter) quarantine(token *capabilities.Token, collection string, id string, source string, score float64, text string) (*RetrievedPassage, error) {
+	entry := make([]byte, 16)
+	if _, err := rand.Read(entry); err != nil {
+		return nil, fmt.Errorf("quarantine entry ID: %w", err)
+	}
+	passage := &RetrievedPassage{QuarantineID: a.config.Name + ":" + hex.EncodeToString(entry), Source: source, Score: score}
+
+	metadata := map[string]interface{}{
+		"source":       a.config.Name,
+		"collection":   collection,
+		"document_id":  id,
+		"document":     source,
+		"score":        score,
+		"token_digest": token.Digest,
+	}
+	labeled, err := cif.Ingress(text, map[string]interface{}{"source": a.config.Name, "collection": collection})
+	if err != nil {
+		// Empty and oversized passages cannot be labeled, so nothing of them is handed on
+		passage.Labels = []string{LabelRetrieved, "unlabeled"}
+		passage.Withheld = true
+	} else {
+		passage.Labels = append(append([]string(nil), labeled.TaintLabels...), LabelRetrieved)
+		passage.Withheld = labeled.IsTainted()
+		metadata["input_hash"] = labeled.InputHash
+	}
+	metadata["taint_labels"] = passage.Labels
+
+	if err := a.config.Memory.Namespace(token.NamespaceID).WriteAs(token.PrincipalID, memory.PartitionQuarantine, passage.QuarantineID, text, metadata); err != nil {
+		return nil, fmt.Errorf("quarantine passage: %w", err)
+	}
+	if !passage.Withheld {
+		passage.Text = labeled.SanitizedInput
+	}
+	return passage, nil
+}
+
+// mergeLabels appends the labels not already present
+func mergeLabels(labels []string, more []string) []string {
+	for _, label := range more {
+		present := false
+		for _, existing := range labels {
+			if existing == label {
+				present = true
+				break
+			}
+		}
+		if !present {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
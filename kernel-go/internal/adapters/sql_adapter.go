@@ -0,0 +1,212 @@
+// WHY: Databases hold the most sensitive data an agent can reach. This
+// adapter runs only parameterized SELECTs (see sql_statement.go), checks
+// every referenced table against "sql:select:<schema>.<table>" scopes, and
+// executes inside a read-only transaction as a second line of defense. The
+// ledger records the query fingerprint, never the bound values.
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/cdi"
+)
+
+// Defaults for SQL adapters
+const (
+	DefaultSQLName    = "sql"
+	DefaultSQLSchema  = "public"
+	DefaultSQLMaxRows = 1000
+	DefaultSQLTimeout = 30 * time.Second
+)
+
+// ParamQuery is the invocation parameter carrying the SQL text
+const ParamQuery = "query"
+
+// SQLConfig configures a read-only SQL adapter
+type SQLConfig struct {
+	// Name is the adapter name and the scope tokens must carry (default DefaultSQLName)
+	Name string
+
+	// DB is the database handle. It should connect with a read-only role;
+	// the adapter's checks are a second layer, not a substitute.
+	DB *sql.DB
+
+	// DefaultSchema qualifies unqualified table names (default DefaultSQLSchema)
+	DefaultSchema string
+
+	// MaxRows caps returned rows (default DefaultSQLMaxRows)
+	MaxRows int
+
+	// Timeout bounds each query (default DefaultSQLTimeout)
+	Timeout time.Duration
+}
+
+// SQLAdapter runs read-only, scope-checked SELECT statements
+type SQLAdapter struct {
+	config SQLConfig
+}
+
+// NewSQLAdapter validates the configuration and creates the adapter
+func NewSQLAdapter(config SQLConfig) (*SQLAdapter, error) {
+	if config.Name == "" {
+		config.Name = DefaultSQLName
+	}
+	if config.DB == nil {
+		return nil, fmt.Errorf("sql adapter %s: no database", config.Name)
+	}
+	if config.DefaultSchema == "" {
+		config.DefaultSchema = DefaultSQLSchema
+	}
+	if config.MaxRows <= 0 {
+		config.MaxRows = DefaultSQLMaxRows
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultSQLTimeout
+	}
+	return &SQLAdapter{config: config}, nil
+}
+
+// Name returns the adapter identifier
+func (a *SQLAdapter) Name() string {
+	return a.config.Name
+}
+
+// Declare reports database reads only; statements are SELECTs in a
+// read-only transaction
+func (a *SQLAdapter) Declare() cdi.CapabilityDeclaration {
+	return cdi.CapabilityDeclaration{
+		Name:        a.config.Name,
+		Risk:        cdi.RiskMedium,
+		SideEffects: []string{cdi.SideEffectRead},
+		Scopes:      []string{"sql:select:**"},
+	}
+}
+
+// VerifyToken checks token validity and adapter scope. Table scopes need
+// the statement, so they are checked in Invoke.
+func (a *SQLAdapter) VerifyToken(token *capabilities.Token, currentPosture int) error {
+	if token == nil {
+		return fmt.Errorf("nil token - tokenless invocation rejected")
+	}
+
+	valid, err := token.Verify(currentPosture)
+	if !valid {
+		return fmt.Errorf("token verification failed: %w", err)
+	}
+
+	if !token.HasScope(a.config.Name) {
+		return fmt.Errorf("token does not have scope for adapter %s", a.config.Name)
+	}
+
+	return nil
+}
+
+// Invoke classifies, authorizes, and runs one SELECT
+func (a *SQLAdapter) Invoke(ctx context.Context, token *capabilities.Token, params map[string]interface{}) (*AdapterResult, error) {
+	if token == nil {
+		return nil, fmt.Errorf("nil token - invoke rejected")
+	}
+
+	query, _ := params[ParamQuery].(string)
+	if query == "" {
+		return nil, fmt.Errorf("sql adapter %s: missing %q parameter", a.config.Name, ParamQuery)
+	}
+	var args []interface{}
+	switch v := params[ParamArgs].(type) {
+	case nil:
+	case []interface{}:
+		args = v
+	default:
+		return nil, fmt.Errorf("sql adapter %s: %q must be a list", a.config.Name, ParamArgs)
+	}
+
+	statement, err := ClassifySelect(query, a.config.DefaultSchema)
+	if err != nil {
+		return nil, fmt.Errorf("sql adapter %s: %w", a.config.Name, err)
+	}
+	for _, table := range statement.Tables {
+		scope := "sql:select:" + table
+		if !token.HasScope(scope) {
+			return nil, fmt.Errorf("sql adapter %s: token does not grant %s", a.config.Name, scope)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, a.config.Timeout)
+	defer cancel()
+
+	tx, err := a.config.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("sql adapter %s: read-only transaction: %w", a.config.Name, err)
+	}
+	// WHY: Nothing a read is allowed to do needs committing
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("sql adapter %s: %w", a.config.Name, err)
+	}
+	defer rows.Close()
+
+	columns, records, truncated, err := scanRows(rows, a.config.MaxRows)
+	if err != nil {
+		return nil, fmt.Errorf("sql adapter %s: %w", a.config.Name, err)
+	}
+
+	encoded, err := json.Marshal(records)
+	if err != nil {
+		return nil, fmt.Errorf("sql adapter %s: encode rows: %w", a.config.Name, err)
+	}
+
+	return &AdapterResult{
+		Status:      StatusSuccess,
+		Content:     string(encoded),
+		ContentType: ContentTypeJSON,
+		Provenance:  Provenance{Source: a.config.Name},
+		Query:       &QueryCommitment{Fingerprint: statement.Fingerprint, Hash: statement.FingerprintHash, Tables: statement.Tables},
+		Details: map[string]interface{}{
+			"columns":   columns,
+			"rows":      records,
+			"row_count": len(records),
+			"truncated": truncated,
+		},
+	}, nil
+}
+
+// scanRows reads up to maxRows rows as column-name maps
+func scanRows(rows *sql.Rows, maxRows int) ([]string, []map[string]interface{}, bool, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	records := []map[string]interface{}{}
+	for rows.Next() {
+		if len(records) == maxRows {
+			return columns, records, true, nil
+		}
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, nil, false, err
+		}
+
+		record := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			if raw, ok := values[i].([]byte); ok {
+				record[column] = string(raw)
+			} else {
+				record[column] = values[i]
+			}
+		}
+		records = append(records, record)
+	}
+	return columns, records, false, rows.Err()
+}
@@ -0,0 +1,135 @@
+// WHY: These tests prove the SQL adapter enforces table scopes, runs in a
+// read-only transaction, and reports a fingerprint instead of values.
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// readOnlyDriver serves one fixed row and records transaction options
+type readOnlyDriver struct {
+	mu       sync.Mutex
+	readOnly []bool
+	queries  []string
+}
+
+func (d *readOnlyDriver) Open(string) (driver.Conn, error) { return &readOnlyConn{driver: d}, nil }
+
+type readOnlyConn struct{ driver *readOnlyDriver }
+
+func (c *readOnlyConn) Prepare(query string) (driver.Stmt, error) {
+	c.driver.mu.Lock()
+	c.driver.queries = append(c.driver.queries, query)
+	c.driver.mu.Unlock()
+	return readOnlyStmt{}, nil
+}
+func (c *readOnlyConn) Close() error              { return nil }
+func (c *readOnlyConn) Begin() (driver.Tx, error) { return nil, fmt.Errorf("use BeginTx") }
+func (c *readOnlyConn) BeginTx(_ context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.driver.mu.Lock()
+	c.driver.readOnly = append(c.driver.readOnly, opts.ReadOnly)
+	c.driver.mu.Unlock()
+	return readOnlyTx{}, nil
+}
+
+type readOnlyTx struct{}
+
+func (readOnlyTx) Commit() error   { return fmt.Errorf("read-only transactions never commit") }
+func (readOnlyTx) Rollback() error { return nil }
+
+type readOnlyStmt struct{}
+
+func (readOnlyStmt) Close() error  { return nil }
+func (readOnlyStmt) NumInput() int { return -1 }
+func (readOnlyStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, fmt.Errorf("exec not supported")
+}
+func (readOnlyStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &readOnlyRows{values: [][]driver.Value{{int64(7), []byte("ada")}}}, nil
+}
+
+type readOnlyRows struct{ values [][]driver.Value }
+
+func (r *readOnlyRows) Columns() []string { return []string{"id", "name"} }
+func (r *readOnlyRows) Close() error      { return nil }
+func (r *readOnlyRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+var registerReadOnlyDriver sync.Once
+var testReadOnlyDriver = &readOnlyDriver{}
+
+// mintSQLToken mints a token for the sql adapter with extra scopes
+func mintSQLToken(t *testing.T, scopes ...string) *capabilities.Token {
+	t.Helper()
+	token, err := capabilities.Mint("kernel", "test_subject", "adapters", append([]string{DefaultSQLName}, scopes...),
+		capabilities.Limits{}, time.Minute,
+		capabilities.PostureBounds{MinPosture: posture.P1, MaxPosture: posture.P4},
+		"test_namespace", "test_principal")
+	if err != nil {
+		t.Fatalf("mint: %v", err)
+	}
+	return token
+}
+
+// TestSQLAdapterEnforcesTableScopesReadOnly proves table scopes gate the
+// query, execution is read-only, and only the fingerprint is reported
+func TestSQLAdapterEnforcesTableScopesReadOnly(t *testing.T) {
+	registerReadOnlyDriver.Do(func() { sql.Register("oi_readonly_test", testReadOnlyDriver) })
+	db, err := sql.Open("oi_readonly_test", "")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+
+	adapter, err := NewSQLAdapter(SQLConfig{DB: db})
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	params := map[string]interface{}{
+		ParamQuery: "SELECT id, name FROM users u JOIN crm.accounts a ON a.owner = u.id WHERE u.email = ?",
+		ParamArgs:  []interface{}{"ada@example.com"},
+	}
+
+	if _, err := adapter.Invoke(context.Background(), mintSQLToken(t, "sql:select:public.users"), params); err == nil {
+		t.Fatal("query touching an unscoped table must be refused")
+	}
+	if len(testReadOnlyDriver.queries) != 0 {
+		t.Fatal("refused query reached the database")
+	}
+
+	result, err := adapter.Invoke(context.Background(), mintSQLToken(t, "sql:select:public.users", "sql:select:crm.*"), params)
+	if err != nil {
+		t.Fatalf("invoke: %v", err)
+	}
+	if len(testReadOnlyDriver.readOnly) != 1 || !testReadOnlyDriver.readOnly[0] {
+		t.Fatal("query must run in a read-only transaction")
+	}
+
+	if result.Details["row_count"] != 1 || !strings.Contains(result.Content, "ada") {
+		t.Fatalf("unexpected rows: %v", result.Content)
+	}
+	query := result.Query
+	if query == nil || query.Hash == "" || len(query.Tables) != 2 {
+		t.Fatalf("result must carry a fingerprint: %+v", query)
+	}
+	if strings.Contains(query.Fingerprint, "ada@example.com") {
+		t.Fatal("fingerprint must not contain bound values")
+	}
+}
@@ -0,0 +1,390 @@
+// WHY: A read-only SQL adapter is only as strong as its idea of what a
+// statement does. This small lexer classifies a statement without a full
+// parser: one statement, SELECT or WITH ... SELECT, no writing keyword
+// anywhere, no string literals (values must be bound parameters), and every
+// table named after FROM or JOIN extracted so scopes can be checked. When
+// in doubt it refuses. The same token stream yields a fingerprint with all
+// literals replaced, which is what the ledger records.
+package adapters
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// sqlTokenKind classifies lexer tokens
+type sqlTokenKind int
+
+const (
+	sqlWord sqlTokenKind = iota
+	sqlQuotedIdent
+	sqlNumber
+	sqlString
+	sqlPlaceholder
+	sqlPunct
+)
+
+type sqlToken struct {
+	kind sqlTokenKind
+	text string
+}
+
+// sqlWriteKeywords may not appear anywhere in a read-only statement.
+// WHY: Matching anywhere, not just at the start, catches data-modifying
+// CTEs, SELECT ... INTO, and FOR UPDATE locks.
+var sqlWriteKeywords = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "MERGE": true, "UPSERT": true,
+	"REPLACE": true, "CREATE": true, "ALTER": true, "DROP": true, "TRUNCATE": true,
+	"GRANT": true, "REVOKE": true, "COPY": true, "CALL": true, "EXEC": true,
+	"EXECUTE": true, "DO": true, "SET": true, "LOCK": true, "VACUUM": true,
+	"ANALYZE": true, "ATTACH": true, "DETACH": true, "PRAGMA": true, "INTO": true,
+	"LOAD": true, "HANDLER": true, "REFRESH": true, "REINDEX": true, "CLUSTER": true,
+}
+
+// sqlKeywords are words that are never table names, aliases, or functions
+var sqlKeywords = map[string]bool{
+	"SELECT": true, "WITH": true, "RECURSIVE": true, "AS": true, "FROM": true,
+	"JOIN": true, "LEFT": true, "RIGHT": true, "INNER": true, "OUTER": true,
+	"FULL": true, "CROSS": true, "NATURAL": true, "LATERAL": true, "ONLY": true,
+	"ON": true, "USING": true, "WHERE": true, "GROUP": true, "BY": true,
+	"ORDER": true, "HAVING": true, "LIMIT": true, "OFFSET": true, "FETCH": true,
+	"UNION": true, "EXCEPT": true, "INTERSECT": true, "ALL": true, "DISTINCT": true,
+	"WINDOW": true, "AND": true, "OR": true, "NOT": true, "IN": true,
+	"EXISTS": true, "ANY": true, "SOME": true, "BETWEEN": true, "LIKE": true,
+	"IS": true, "NULL": true, "CASE": true, "WHEN": true, "THEN": true,
+	"ELSE": true, "END": true, "ASC": true, "DESC": true, "OVER": true,
+	"PARTITION": true, "FILTER": true, "WITHIN": true, "VALUES": true,
+}
+
+// sqlValueSyntax are the functions whose arguments may themselves use FROM,
+// as in EXTRACT(YEAR FROM x) or TRIM(LEADING FROM x), without naming a table
+var sqlValueSyntax = map[string]bool{
+	"EXTRACT": true, "SUBSTRING": true, "TRIM": true, "POSITION": true, "OVERLAY": true,
+}
+
+// SQLStatement is a classified read-only statement
+type SQLStatement struct {
+	// Tables are the referenced tables as "schema.table"
+	Tables []string
+
+	// Fingerprint is the statement with every literal and placeholder
+	// replaced by "?", keywords upper-cased, and whitespace collapsed
+	Fingerprint string
+
+	// FingerprintHash is the hex SHA-256 of Fingerprint
+	FingerprintHash string
+}
+
+// ClassifySelect accepts only a single parameterized read-only SELECT.
+// Unqualified tables are placed in defaultSchema.
+func ClassifySelect(query string, defaultSchema string) (*SQLStatement, error) {
+	tokens, err := lexSQL(query)
+	if err != nil {
+		return nil, err
+	}
+	// A single trailing semicolon is harmless; any other ends a statement early
+	if n := len(tokens); n > 0 && tokens[n-1].text == ";" {
+		tokens = tokens[:n-1]
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty statement")
+	}
+
+	first := strings.ToUpper(tokens[0].text)
+	if tokens[0].kind != sqlWord || (first != "SELECT" && first != "WITH") {
+		return nil, fmt.Errorf("only SELECT statements are permitted, got %s", first)
+	}
+
+	cteNames := map[string]bool{}
+	for i, token := range tokens {
+		switch token.kind {
+		case sqlString:
+			return nil, fmt.Errorf("string literals are not permitted; bind values as parameters")
+		case sqlPunct:
+			if token.text == ";" {
+				return nil, fmt.Errorf("multiple statements are not permitted")
+			}
+		case sqlWord:
+			word := strings.ToUpper(token.text)
+			if sqlWriteKeywords[word] {
+				return nil, fmt.Errorf("%s is not permitted in a read-only statement", word)
+			}
+			// CTE names ("name AS (") are not tables
+			if i+2 < len(tokens) && strings.EqualFold(tokens[i+1].text, "AS") && tokens[i+2].text == "(" && !sqlKeywords[word] {
+				cteNames[strings.ToLower(token.text)] = true
+			}
+		}
+	}
+
+	tables, err := sqlTables(tokens, defaultSchema, cteNames)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprint := sqlFingerprint(tokens)
+	sum := sha256.Sum256([]byte(fingerprint))
+	return &SQLStatement{
+		Tables:          tables,
+		Fingerprint:     fingerprint,
+		FingerprintHash: hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// sqlTables extracts the tables named after FROM and JOIN everywhere but
+// the value arguments of sqlValueSyntax functions (so EXTRACT(YEAR FROM x)
+// is not a table).
+// WHY: Any other parentheses may hold a subquery, as ARRAY(SELECT ...) or
+// COALESCE((SELECT ...)) do, and a subquery's tables need scopes like any
+// other; a SELECT or WITH inside even a value-syntax function is one.
+func sqlTables(tokens []sqlToken, defaultSchema string, cteNames map[string]bool) ([]string, error) {
+	var tables []string
+	seen := map[string]bool{}
+	var parens []bool // true for value-syntax function parentheses
+
+	insideValueSyntax := func() bool {
+		return len(parens) > 0 && parens[len(parens)-1]
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		switch {
+		case token.text == "(":
+			isValueSyntax := i > 0 && tokens[i-1].kind == sqlWord && sqlValueSyntax[strings.ToUpper(tokens[i-1].text)]
+			parens = append(parens, isValueSyntax)
+			continue
+		case token.text == ")":
+			if len(parens) == 0 {
+				return nil, fmt.Errorf("unbalanced parentheses")
+			}
+			parens = parens[:len(parens)-1]
+			continue
+		case token.kind != sqlWord:
+			continue
+		}
+
+		word := strings.ToUpper(token.text)
+		if (word == "SELECT" || word == "WITH") && insideValueSyntax() {
+			parens[len(parens)-1] = false
+		}
+		if insideValueSyntax() || (word != "FROM" && word != "JOIN") {
+			continue
+		}
+		// "a IS DISTINCT FROM b" compares values; b is not a table
+		if word == "FROM" && i > 0 && strings.EqualFold(tokens[i-1].text, "DISTINCT") {
+			continue
+		}
+
+		// Parse "name [alias] {, name [alias]}" for FROM, one name for JOIN
+		for j := i + 1; j < len(tokens); {
+			for j < len(tokens) && tokens[j].kind == sqlWord && (strings.EqualFold(tokens[j].text, "ONLY") || strings.EqualFold(tokens[j].text, "LATERAL")) {
+				j++
+			}
+			if j >= len(tokens) || tokens[j].text == "(" {
+				// Subquery: its own FROM clauses are scanned in turn
+				break
+			}
+
+			name, next, err := sqlQualifiedName(tokens, j)
+			if err != nil {
+				return nil, err
+			}
+			if next < len(tokens) && tokens[next].text == "(" {
+				return nil, fmt.Errorf("table function %s is not permitted", strings.Join(name, "."))
+			}
+
+			table := ""
+			switch {
+			case len(name) == 1 && cteNames[name[0]]:
+				// Reference to a CTE defined in this statement
+			case len(name) == 1:
+				table = defaultSchema + "." + name[0]
+			case len(name) == 2:
+				table = name[0] + "." + name[1]
+			default:
+				return nil, fmt.Errorf("table name %s has too many parts", strings.Join(name, "."))
+			}
+			if table != "" && !seen[table] {
+				seen[table] = true
+				tables = append(tables, table)
+			}
+
+			// Skip an optional alias
+			j = next
+			if j < len(tokens) && strings.EqualFold(tokens[j].text, "AS") {
+				j++
+			}
+			if j < len(tokens) && (tokens[j].kind == sqlQuotedIdent || (tokens[j].kind == sqlWord && !sqlKeywords[strings.ToUpper(tokens[j].text)])) {
+				j++
+			}
+
+			if word == "FROM" && j < len(tokens) && tokens[j].text == "," {
+				j++
+				continue
+			}
+			break
+		}
+	}
+
+	if len(parens) != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses")
+	}
+	return tables, nil
+}
+
+// sqlQualifiedName reads ident {"." ident} starting at i, lower-casing
+// unquoted parts, and returns the parts and the index after the name
+func sqlQualifiedName(tokens []sqlToken, i int) ([]string, int, error) {
+	var parts []string
+	for {
+		if i >= len(tokens) || (tokens[i].kind != sqlWord && tokens[i].kind != sqlQuotedIdent) {
+			return nil, i, fmt.Errorf("expected table name")
+		}
+		if tokens[i].kind == sqlWord && sqlKeywords[strings.ToUpper(tokens[i].text)] {
+			return nil, i, fmt.Errorf("expected table name, got %s", tokens[i].text)
+		}
+		part := tokens[i].text
+		if tokens[i].kind == sqlWord {
+			part = strings.ToLower(part)
+		}
+		if part == "" || strings.ContainsAny(part, ".:*/") {
+			return nil, i, fmt.Errorf("table name part %q is not permitted", part)
+		}
+		parts = append(parts, part)
+		i++
+		if i < len(tokens) && tokens[i].text == "." {
+			i++
+			continue
+		}
+		return parts, i, nil
+	}
+}
+
+// sqlFingerprint renders tokens with literals and placeholders as "?"
+func sqlFingerprint(tokens []sqlToken) string {
+	parts := make([]string, len(tokens))
+	for i, token := range tokens {
+		switch token.kind {
+		case sqlNumber, sqlString, sqlPlaceholder:
+			parts[i] = "?"
+		case sqlWord:
+			if sqlKeywords[strings.ToUpper(token.text)] {
+				parts[i] = strings.ToUpper(token.text)
+			} else {
+				parts[i] = strings.ToLower(token.text)
+			}
+		case sqlQuotedIdent:
+			parts[i] = `"` + token.text + `"`
+		default:
+			parts[i] = token.text
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// lexSQL splits a statement into tokens, dropping comments
+func lexSQL(query string) ([]sqlToken, error) {
+	var tokens []sqlToken
+	runes := []rune(query)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			j := i + 2
+			for j+1 < len(runes) && !(runes[j] == '*' && runes[j+1] == '/') {
+				j++
+			}
+			if j+1 >= len(runes) {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			i = j + 2
+
+		case r == '\'':
+			j := i + 1
+			for {
+				if j >= len(runes) {
+					return nil, fmt.Errorf("unterminated string literal")
+				}
+				if runes[j] == '\'' {
+					if j+1 < len(runes) && runes[j+1] == '\'' {
+						j += 2
+						continue
+					}
+					break
+				}
+				j++
+			}
+			tokens = append(tokens, sqlToken{sqlString, string(runes[i : j+1])})
+			i = j + 1
+
+		case r == '"' || r == '`' || r == '[':
+			closing := map[rune]rune{'"': '"', '`': '`', '[': ']'}[r]
+			j := i + 1
+			for j < len(runes) && runes[j] != closing {
+				j++
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated quoted identifier")
+			}
+			tokens = append(tokens, sqlToken{sqlQuotedIdent, string(runes[i+1 : j])})
+			i = j + 1
+
+		case r == '?':
+			tokens = append(tokens, sqlToken{sqlPlaceholder, "?"})
+			i++
+
+		case r == '$' || r == ':' || r == '@':
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			if j == i+1 {
+				if r == ':' {
+					// Postgres casts ("x::int") are punctuation, not placeholders
+					tokens = append(tokens, sqlToken{sqlPunct, ":"})
+					i++
+					continue
+				}
+				// WHY: "$$" opens a Postgres dollar-quoted string literal
+				return nil, fmt.Errorf("unexpected %q", string(r))
+			}
+			tokens = append(tokens, sqlToken{sqlPlaceholder, string(runes[i:j])})
+			i = j
+
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.' || runes[j] == 'e' || runes[j] == 'E') {
+				j++
+			}
+			tokens = append(tokens, sqlToken{sqlNumber, string(runes[i:j])})
+			i = j
+
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, sqlToken{sqlWord, string(runes[i:j])})
+			i = j
+
+		case strings.ContainsRune("(),.;*=<>!+-/%|&~^", r):
+			tokens = append(tokens, sqlToken{sqlPunct, string(r)})
+			i++
+
+		default:
+			return nil, fmt.Errorf("unexpected character %q", string(r))
+		}
+	}
+	return tokens, nil
+}
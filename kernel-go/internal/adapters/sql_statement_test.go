@@ -0,0 +1,85 @@
+// WHY: These tests prove statement classification refuses anything that
+// could write or smuggle literals, and finds every table a SELECT reads.
+package adapters
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestClassifySelectRejectsNonReads proves writes, multiple statements,
+// literals, and table functions are refused
+func TestClassifySelectRejectsNonReads(t *testing.T) {
+	rejected := []string{
+		"DELETE FROM users",
+		"SELECT * FROM users; DROP TABLE users",
+		"WITH gone AS (DELETE FROM users RETURNING *) SELECT * FROM gone",
+		"SELECT * INTO copy FROM users",
+		"SELECT * FROM users FOR UPDATE",
+		"SELECT * FROM users WHERE name = 'admin'",
+		"SELECT * FROM users WHERE name = $$admin$$",
+		"SELECT * FROM pg_read_file(?)",
+		"SELECT * FROM users /* unterminated",
+		"",
+	}
+	for _, query := range rejected {
+		if _, err := ClassifySelect(query, "public"); err == nil {
+			t.Fatalf("statement must be rejected: %q", query)
+		}
+	}
+}
+
+// TestClassifySelectFindsTablesAndFingerprints proves tables are extracted
+// from joins, lists, and subqueries, and fingerprints drop literals
+func TestClassifySelectFindsTablesAndFingerprints(t *testing.T) {
+	statement, err := ClassifySelect(`
+		WITH recent AS (SELECT id FROM audit.events WHERE ts > $1)
+		SELECT u.name, EXTRACT(YEAR FROM u.created) FROM users u, billing.invoices AS i
+		JOIN recent r ON r.id = i.id
+		WHERE u.id IN (SELECT user_id FROM "Teams" WHERE size > 10) LIMIT 5;`, "public")
+	if err != nil {
+		t.Fatalf("classify: %v", err)
+	}
+
+	want := []string{"audit.events", "public.users", "billing.invoices", "public.Teams"}
+	if !reflect.DeepEqual(statement.Tables, want) {
+		t.Fatalf("tables: got %v, want %v", statement.Tables, want)
+	}
+	if strings.Contains(statement.Fingerprint, "10") || strings.Contains(statement.Fingerprint, "$1") {
+		t.Fatalf("fingerprint must not contain literals: %s", statement.Fingerprint)
+	}
+
+	a, _ := ClassifySelect("SELECT name FROM users WHERE id = 1", "public")
+	b, _ := ClassifySelect("select name\n from USERS where id = 42", "public")
+	if a.FingerprintHash != b.FingerprintHash {
+		t.Fatalf("same query shape must share a fingerprint: %q vs %q", a.Fingerprint, b.Fingerprint)
+	}
+}
+
+// TestClassifySelectFindsTablesInsideFunctions proves a subquery inside
+// any function's parentheses names its tables, and only the value syntax
+// of functions like EXTRACT and TRIM is passed over
+func TestClassifySelectFindsTablesInsideFunctions(t *testing.T) {
+	cases := map[string][]string{
+		"SELECT array(SELECT ssn FROM hr.employees)":                              {"hr.employees"},
+		"SELECT coalesce((SELECT ssn FROM hr.employees), ?)":                      {"hr.employees"},
+		"SELECT max(x) FROM (SELECT lower((SELECT ssn FROM hr.employees)) x) s":   {"hr.employees"},
+		"SELECT EXTRACT(YEAR FROM (SELECT max(hired) FROM hr.employees))":         {"hr.employees"},
+		"SELECT TRIM(LEADING FROM name), SUBSTRING(name FROM 2 FOR 3) FROM users": {"public.users"},
+	}
+	for query, want := range cases {
+		statement, err := ClassifySelect(query, "public")
+		if err != nil {
+			t.Fatalf("classify %q: %v", query, err)
+		}
+		if !reflect.DeepEqual(statement.Tables, want) {
+			t.Fatalf("%q: got tables %v, want %v", query, statement.Tables, want)
+		}
+	}
+
+	statement, err := ClassifySelect("SELECT some_func(x FROM y) FROM users", "public")
+	if err == nil && !reflect.DeepEqual(statement.Tables, []string{"public.y", "public.users"}) {
+		t.Fatalf("FROM inside any other function must be read as a table or refused, got %v", statement.Tables)
+	}
+}
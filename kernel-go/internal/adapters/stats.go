@@ -0,0 +1,144 @@
+// WHY: A governed tool that is slow or failing is invisible in the ledger
+// until someone reads every receipt. The registry sees every call, so it
+// keeps running totals per adapter: outcomes, latency, and the usage the
+// adapter reported. Operators query them directly or scrape them as
+// metrics; neither carries any content.
+package adapters
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// AdapterStats are cumulative totals for one adapter
+type AdapterStats struct {
+	Adapter string
+
+	// Outcomes of calls that reached the adapter; Timeouts are also Errors
+	Successes int64
+	Errors    int64
+	Timeouts  int64
+
+	// Refused counts calls stopped before reaching the adapter: token
+	// checks, interceptors, rate limits, and an open breaker
+	Refused int64
+
+	// Latency of calls that reached the adapter
+	TotalLatency time.Duration
+	MaxLatency   time.Duration
+
+	// Budget consumed, as reported in result usage
+	PromptTokens     int64
+	CompletionTokens int64
+	Cost             float64
+}
+
+// Calls returns how many calls reached the adapter
+func (s AdapterStats) Calls() int64 {
+	return s.Successes + s.Errors
+}
+
+// MeanLatency returns the average latency of calls that reached the adapter
+func (s AdapterStats) MeanLatency() time.Duration {
+	if s.Calls() == 0 {
+		return 0
+	}
+	return s.TotalLatency / time.Duration(s.Calls())
+}
+
+// ErrorRate returns the fraction of calls that reached the adapter and failed
+func (s AdapterStats) ErrorRate() float64 {
+	if s.Calls() == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Calls())
+}
+
+// statsBook holds every adapter's totals
+type statsBook struct {
+	mu    sync.Mutex
+	stats map[string]*AdapterStats
+}
+
+func newStatsBook() *statsBook {
+	return &statsBook{stats: make(map[string]*AdapterStats)}
+}
+
+// entry returns the adapter's totals, creating them; b.mu is held
+func (b *statsBook) entry(adapter string) *AdapterStats {
+	stats, ok := b.stats[adapter]
+	if !ok {
+		stats = &AdapterStats{Adapter: adapter}
+		b.stats[adapter] = stats
+	}
+	return stats
+}
+
+// refused records a call stopped before it reached the adapter
+func (b *statsBook) refused(adapter string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.entry(adapter).Refused++
+}
+
+// record records a call that reached the adapter
+func (b *statsBook) record(adapter string, outcome *Outcome) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	stats := b.entry(adapter)
+	stats.TotalLatency += outcome.Elapsed
+	if outcome.Elapsed > stats.MaxLatency {
+		stats.MaxLatency = outcome.Elapsed
+	}
+	if outcome.Err != nil {
+		stats.Errors++
+		var timedOut *TimeoutError
+		if errors.As(outcome.Err, &timedOut) {
+			stats.Timeouts++
+		}
+		return
+	}
+	stats.Successes++
+	stats.PromptTokens += int64(outcome.Result.Usage.PromptTokens)
+	stats.CompletionTokens += int64(outcome.Result.Usage.CompletionTokens)
+	stats.Cost += outcome.Result.Usage.Cost
+}
+
+// forget drops an adapter's totals. WHY: A name registered again later
+// is a different adapter and starts from zero.
+func (b *statsBook) forget(adapter string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.stats, adapter)
+}
+
+// Stats returns a snapshot of every registered adapter's totals, sorted
+// by name; adapters not yet called have zero totals
+func (r *Registry) Stats() []AdapterStats {
+	names := r.ListAdapters()
+
+	r.stats.mu.Lock()
+	defer r.stats.mu.Unlock()
+	snapshot := make([]AdapterStats, 0, len(names))
+	for _, name := range names {
+		snapshot = append(snapshot, *r.stats.entry(name))
+	}
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].Adapter < snapshot[j].Adapter })
+	return snapshot
+}
+
+// StatsFor returns a registered adapter's totals
+func (r *Registry) StatsFor(name string) (AdapterStats, bool) {
+	r.mu.RLock()
+	_, exists := r.adapters[name]
+	r.mu.RUnlock()
+	if !exists {
+		return AdapterStats{}, false
+	}
+
+	r.stats.mu.Lock()
+	defer r.stats.mu.Unlock()
+	return *r.stats.entry(name), true
+}
@@ -0,0 +1,91 @@
+// WHY: These tests prove the registry counts every call once, by outcome,
+// with its latency and reported usage, and that stats follow the adapter's
+// lifecycle.
+package adapters
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestRegistryStatsCountOutcomesAndUsage proves successes, errors,
+// timeouts, refusals, latency, and usage are all recorded per adapter
+func TestRegistryStatsCountOutcomesAndUsage(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(&resultAdapter{MockAdapter: NewMockAdapter("model"), result: &AdapterResult{
+		Status: StatusSuccess,
+		Usage:  Usage{PromptTokens: 10, CompletionTokens: 4, Cost: 0.25},
+	}})
+	registry.Register(&resultAdapter{MockAdapter: NewMockAdapter("broken")})
+	stuck := &stuckAdapter{MockAdapter: NewMockAdapter("stuck"), release: make(chan struct{})}
+	registry.Register(stuck)
+	registry.Register(NewMockAdapter("idle"))
+	registry.SetTimeout("stuck", 10*time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		if _, err := registry.Invoke("model", mintLimitToken(t, "model"), 1, map[string]interface{}{}); err != nil {
+			t.Fatalf("invoke: %v", err)
+		}
+	}
+	registry.Invoke("model", mintLimitToken(t, "other"), 1, map[string]interface{}{})
+	registry.Invoke("broken", mintLimitToken(t, "broken"), 1, map[string]interface{}{})
+	registry.Invoke("stuck", mintLimitToken(t, "stuck"), 1, map[string]interface{}{})
+	close(stuck.release)
+	registry.Invoke("missing", mintLimitToken(t, "missing"), 1, map[string]interface{}{})
+
+	model, ok := registry.StatsFor("model")
+	if !ok || model.Successes != 2 || model.Errors != 0 || model.Refused != 1 || model.Calls() != 2 {
+		t.Fatalf("model stats wrong: %+v", model)
+	}
+	if model.PromptTokens != 20 || model.CompletionTokens != 8 || model.Cost != 0.5 {
+		t.Fatalf("usage must accumulate: %+v", model)
+	}
+	if model.TotalLatency <= 0 || model.MaxLatency <= 0 || model.MeanLatency() > model.MaxLatency {
+		t.Fatalf("latency not recorded: %+v", model)
+	}
+
+	broken, _ := registry.StatsFor("broken")
+	if broken.Errors != 1 || broken.Timeouts != 0 || broken.ErrorRate() != 1 {
+		t.Fatalf("a refused result must count as an error: %+v", broken)
+	}
+	timedOut, _ := registry.StatsFor("stuck")
+	if timedOut.Errors != 1 || timedOut.Timeouts != 1 || timedOut.MaxLatency < 10*time.Millisecond {
+		t.Fatalf("a timeout must count as an error and a timeout: %+v", timedOut)
+	}
+	if _, ok := registry.StatsFor("missing"); ok {
+		t.Fatal("unregistered names must have no stats")
+	}
+
+	all := registry.Stats()
+	names := make([]string, len(all))
+	for i, stats := range all {
+		names[i] = stats.Adapter
+	}
+	if len(all) != 4 || names[0] != "broken" || names[1] != "idle" || all[1].Calls() != 0 {
+		t.Fatalf("stats must list every registered adapter by name: %v", names)
+	}
+}
+
+// TestStatsFollowTheAdapterLifecycle proves Replace keeps an adapter's
+// stats and Deregister drops them
+func TestStatsFollowTheAdapterLifecycle(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(NewMockAdapter("model"))
+	registry.Invoke("model", mintLimitToken(t, "model"), 1, map[string]interface{}{})
+
+	if err := registry.Replace(context.Background(), NewMockAdapter("model")); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	if stats, _ := registry.StatsFor("model"); stats.Successes != 1 {
+		t.Fatalf("replace must keep stats: %+v", stats)
+	}
+
+	if err := registry.Deregister(context.Background(), "model"); err != nil {
+		t.Fatalf("deregister: %v", err)
+	}
+	registry.Register(NewMockAdapter("model"))
+	if stats, _ := registry.StatsFor("model"); stats.Calls() != 0 {
+		t.Fatalf("a re-registered adapter must start from zero: %+v", stats)
+	}
+}
@@ -0,0 +1,130 @@
+// WHY: Revoking a token only marked it revoked; a call already inside an
+// adapter kept running until it finished on its own, so STOP stopped the
+// next side effect but not the one in progress. The registry now tracks
+// every in-flight call by its token, and Stop cancels the context of each
+// call holding a stopped token: the registry returns at once with a
+// StoppedError and the adapter's context is cancelled with it.
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+)
+
+// errStopCause is the cancellation cause of a call cancelled by Stop
+var errStopCause = errors.New("capability revoked by STOP")
+
+// StoppedError reports a call cancelled because its token was stopped.
+// WHY: A distinct type lets the kernel tell a STOP preemption from an
+// adapter failure or a timeout.
+type StoppedError struct {
+	Adapter string
+}
+
+func (e *StoppedError) Error() string {
+	return fmt.Sprintf("adapter %s stopped: %v", e.Adapter, errStopCause)
+}
+
+// Unwrap lets errors.Is match context.Canceled
+func (e *StoppedError) Unwrap() error {
+	return context.Canceled
+}
+
+// stopBook holds every in-flight call by token digest
+type stopBook struct {
+	mu      sync.Mutex
+	next    uint64
+	running map[string]map[uint64]trackedCall
+}
+
+// trackedCall is how to cancel an in-flight call, and closes released
+// once its adapter returns
+type trackedCall struct {
+	cancel   context.CancelCauseFunc
+	released chan struct{}
+}
+
+func newStopBook() *stopBook {
+	return &stopBook{running: make(map[string]map[uint64]trackedCall)}
+}
+
+// track registers cancel as the way to stop a call with token, and
+// returns the function that stops tracking it. WHY: A call is tracked
+// before its token is verified, so a STOP either lands before
+// verification, which then sees the revocation, or after tracking, which
+// it cancels. The returned function also marks the call released, which
+// is when its adapter has really returned.
+func (r *Registry) track(token *capabilities.Token, cancel context.CancelCauseFunc) func() {
+	if token == nil {
+		return func() {}
+	}
+
+	book := r.stops
+	book.mu.Lock()
+	id := book.next
+	book.next++
+	if book.running[token.Digest] == nil {
+		book.running[token.Digest] = make(map[uint64]trackedCall)
+	}
+	released := make(chan struct{})
+	book.running[token.Digest][id] = trackedCall{cancel: cancel, released: released}
+	book.mu.Unlock()
+
+	return func() {
+		close(released)
+		book.mu.Lock()
+		delete(book.running[token.Digest], id)
+		if len(book.running[token.Digest]) == 0 {
+			delete(book.running, token.Digest)
+		}
+		book.mu.Unlock()
+	}
+}
+
+// Stop cancels every in-flight call holding a token with one of digests
+// and returns how many it cancelled
+func (r *Registry) Stop(digests ...string) int {
+	return len(r.StopCalls(digests...))
+}
+
+// StopAll cancels every in-flight call and returns how many it cancelled
+func (r *Registry) StopAll() int {
+	return len(r.StopAllCalls())
+}
+
+// StopCalls is Stop, returning for each cancelled call a channel closed
+// once its adapter has returned. WHY: The caller is released at once, but
+// an adapter may finish its side effect after; the channels let STOP's
+// latency be measured to the last one.
+func (r *Registry) StopCalls(digests ...string) []<-chan struct{} {
+	r.stops.mu.Lock()
+	defer r.stops.mu.Unlock()
+	var released []<-chan struct{}
+	for _, digest := range digests {
+		for _, call := range r.stops.running[digest] {
+			call.cancel(errStopCause)
+			released = append(released, call.released)
+		}
+	}
+	return released
+}
+
+// StopAllCalls is StopAll, returning the channels StopCalls does
+func (r *Registry) StopAllCalls() []<-chan struct{} {
+	r.stops.mu.Lock()
+	digests := make([]string, 0, len(r.stops.running))
+	for digest := range r.stops.running {
+		digests = append(digests, digest)
+	}
+	r.stops.mu.Unlock()
+	return r.StopCalls(digests...)
+}
+
+// stoppedCall reports whether a call's context was cancelled by Stop
+func stoppedCall(stopCtx context.Context) bool {
+	return errors.Is(context.Cause(stopCtx), errStopCause)
+}
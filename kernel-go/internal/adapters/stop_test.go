@@ -0,0 +1,102 @@
+// WHY: These tests prove STOP reaches calls already inside an adapter:
+// the caller is released at once with a StoppedError, the adapter's
+// context is cancelled, and only calls holding a stopped token are hit.
+package adapters
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// awaitInFlight waits until a call holding digest is under way
+func awaitInFlight(t *testing.T, registry *Registry, digest string) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for registry.InFlight(digest) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("call never started")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestStopCancelsOnlyStoppedTokens proves Stop preempts the calls of the
+// tokens it names and leaves the rest running until StopAll
+func TestStopCancelsOnlyStoppedTokens(t *testing.T) {
+	registry := NewRegistry()
+	adapter := &stuckAdapter{MockAdapter: NewMockAdapter("patient"), honourCancel: true, cancelled: make(chan error, 2)}
+	registry.Register(adapter)
+	registry.SetTimeout("patient", time.Hour)
+
+	stopped, spared := mintLimitToken(t, "patient"), mintLimitToken(t, "patient")
+	results := map[string]chan error{stopped.Digest: make(chan error, 1), spared.Digest: make(chan error, 1)}
+	go func() {
+		_, err := registry.Invoke("patient", stopped, 1, map[string]interface{}{})
+		results[stopped.Digest] <- err
+	}()
+	go func() {
+		_, err := registry.Invoke("patient", spared, 1, map[string]interface{}{})
+		results[spared.Digest] <- err
+	}()
+	awaitInFlight(t, registry, stopped.Digest)
+	awaitInFlight(t, registry, spared.Digest)
+
+	if n := registry.Stop(stopped.Digest); n != 1 {
+		t.Fatalf("Stop should cancel one call, cancelled %d", n)
+	}
+	var stop *StoppedError
+	select {
+	case err := <-results[stopped.Digest]:
+		if !errors.As(err, &stop) || !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected StoppedError, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("stopped call was not released")
+	}
+	if err := <-adapter.cancelled; err != context.Canceled {
+		t.Fatalf("adapter should see the cancellation, got %v", err)
+	}
+	select {
+	case err := <-results[spared.Digest]:
+		t.Fatalf("a call with another token must keep running, got %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if n := registry.StopAll(); n != 1 {
+		t.Fatalf("StopAll should cancel the remaining call, cancelled %d", n)
+	}
+	if err := <-results[spared.Digest]; !errors.As(err, &stop) {
+		t.Fatalf("expected StoppedError, got %v", err)
+	}
+}
+
+// TestStopReleasesCallerOfUncooperativeAdapter proves the caller is
+// released even when the adapter ignores its context
+func TestStopReleasesCallerOfUncooperativeAdapter(t *testing.T) {
+	registry := NewRegistry()
+	adapter := &stuckAdapter{MockAdapter: NewMockAdapter("stuck"), release: make(chan struct{})}
+	defer close(adapter.release)
+	registry.Register(adapter)
+	registry.SetTimeout("stuck", time.Hour)
+
+	token := mintLimitToken(t, "stuck")
+	result := make(chan error, 1)
+	go func() {
+		_, err := registry.Invoke("stuck", token, 1, map[string]interface{}{})
+		result <- err
+	}()
+	awaitInFlight(t, registry, token.Digest)
+
+	registry.StopAll()
+	var stop *StoppedError
+	select {
+	case err := <-result:
+		if !errors.As(err, &stop) || stop.Adapter != "stuck" {
+			t.Fatalf("expected StoppedError, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("caller was not released")
+	}
+}
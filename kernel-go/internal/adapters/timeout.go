@@ -0,0 +1,79 @@
+// WHY: An adapter that never returns would hold the corridor, its token's
+// concurrency slot, and the caller forever. Every call runs under a
+// deadline; when it passes, the registry stops waiting and cancels the
+// adapter's context so well-behaved adapters abandon their work too.
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+)
+
+// DefaultAdapterTimeout bounds a call when neither the registry nor the
+// token sets a timeout
+const DefaultAdapterTimeout = 2 * time.Minute
+
+// TimeoutError reports a call abandoned at its deadline.
+// WHY: A distinct type lets the kernel record timeouts separately from
+// errors the adapter returned.
+type TimeoutError struct {
+	Adapter string
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("adapter %s timed out after %s", e.Adapter, e.Timeout)
+}
+
+// Unwrap lets errors.Is match context.DeadlineExceeded
+func (e *TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// callTimeout returns the tighter of the configured and token timeouts,
+// or DefaultAdapterTimeout if neither is set
+func callTimeout(configured time.Duration, token *capabilities.Token) time.Duration {
+	timeout := configured
+	if limit := token.Limits.Timeout; limit > 0 && (timeout <= 0 || limit < timeout) {
+		timeout = limit
+	}
+	if timeout <= 0 {
+		timeout = DefaultAdapterTimeout
+	}
+	return timeout
+}
+
+// invokeWithin runs adapter.Invoke until it returns or ctx is done, and
+// calls done once the adapter has actually returned.
+// WHY: An adapter that ignores cancellation keeps running after the caller
+// is released, so the resources it holds are freed only when it finishes.
+// The call runs on its own goroutine, where a panic could not be recovered
+// by the caller, so it is returned as an error instead.
+func invokeWithin(ctx context.Context, adapter Adapter, token *capabilities.Token, params map[string]interface{}, done func()) (*AdapterResult, error) {
+	type outcome struct {
+		result *AdapterResult
+		err    error
+	}
+	finished := make(chan outcome, 1)
+	go func() {
+		var out outcome
+		defer func() {
+			if p := recover(); p != nil {
+				out = outcome{err: fmt.Errorf("adapter %s panicked: %v", adapter.Name(), p)}
+			}
+			done()
+			finished <- out
+		}()
+		out.result, out.err = adapter.Invoke(ctx, token, params)
+	}()
+
+	select {
+	case out := <-finished:
+		return out.result, out.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
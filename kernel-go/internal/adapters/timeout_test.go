@@ -0,0 +1,105 @@
+// WHY: These tests prove a stuck adapter cannot hold a caller past its
+// deadline, and cancellation reaches adapters that honour it.
+package adapters
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+)
+
+// stuckAdapter blocks until released, ignoring cancellation, or until its
+// context is done when honourCancel is set
+type stuckAdapter struct {
+	*MockAdapter
+	honourCancel bool
+	release      chan struct{}
+	cancelled    chan error
+}
+
+func (s *stuckAdapter) Invoke(ctx context.Context, token *capabilities.Token, params map[string]interface{}) (*AdapterResult, error) {
+	if s.honourCancel {
+		<-ctx.Done()
+		s.cancelled <- ctx.Err()
+		return nil, ctx.Err()
+	}
+	<-s.release
+	return s.MockAdapter.Invoke(ctx, token, params)
+}
+
+// TestRegistryTimesOutStuckAdapter proves a call is abandoned at the
+// adapter's timeout with a TimeoutError, and the token's concurrency slot
+// stays held until the adapter really returns
+func TestRegistryTimesOutStuckAdapter(t *testing.T) {
+	registry := NewRegistry()
+	adapter := &stuckAdapter{MockAdapter: NewMockAdapter("stuck"), release: make(chan struct{})}
+	registry.Register(adapter)
+	if err := registry.SetTimeout("stuck", 20*time.Millisecond); err != nil {
+		t.Fatalf("set timeout: %v", err)
+	}
+
+	token := mintLimitToken(t, "stuck")
+	_, err := registry.Invoke("stuck", token, 1, map[string]interface{}{})
+	var timedOut *TimeoutError
+	if !errors.As(err, &timedOut) || timedOut.Timeout != 20*time.Millisecond {
+		t.Fatalf("expected TimeoutError after 20ms, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("TimeoutError should match context.DeadlineExceeded")
+	}
+	if registry.InFlight(token.Digest) != 1 {
+		t.Fatal("abandoned call still holds its slot until the adapter returns")
+	}
+
+	close(adapter.release)
+	deadline := time.Now().Add(time.Second)
+	for registry.InFlight(token.Digest) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("slot not released after the adapter returned")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := registry.SetTimeout("stuck", -time.Second); err == nil {
+		t.Fatal("negative timeout must be refused")
+	}
+}
+
+// TestRegistryPassesCancellationDown proves the tighter token timeout
+// applies and a caller's cancellation reaches the adapter's context
+func TestRegistryPassesCancellationDown(t *testing.T) {
+	registry := NewRegistry()
+	adapter := &stuckAdapter{MockAdapter: NewMockAdapter("patient"), honourCancel: true, cancelled: make(chan error, 1)}
+	registry.Register(adapter)
+	registry.SetTimeout("patient", time.Hour)
+
+	token, err := capabilities.Mint("test_issuer", "test_subject", "test_audience", []string{"patient"},
+		capabilities.Limits{Timeout: 10 * time.Millisecond}, 5*time.Minute,
+		capabilities.PostureBounds{MinPosture: 1, MaxPosture: 4}, "test_namespace", "test_principal")
+	if err != nil {
+		t.Fatalf("mint: %v", err)
+	}
+	var timedOut *TimeoutError
+	if _, err := registry.Invoke("patient", token, 1, map[string]interface{}{}); !errors.As(err, &timedOut) || timedOut.Timeout != 10*time.Millisecond {
+		t.Fatalf("token timeout should apply: %v", err)
+	}
+	if err := <-adapter.cancelled; err != context.DeadlineExceeded {
+		t.Fatalf("adapter should see the deadline, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	_, err = registry.InvokeContext(ctx, "patient", mintLimitToken(t, "patient"), 1, map[string]interface{}{})
+	if !errors.Is(err, context.Canceled) || errors.As(err, &timedOut) {
+		t.Fatalf("caller cancellation should surface as context.Canceled, got %v", err)
+	}
+	if err := <-adapter.cancelled; err != context.Canceled {
+		t.Fatalf("adapter should see the cancellation, got %v", err)
+	}
+}
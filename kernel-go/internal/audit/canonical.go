@@ -0,0 +1,138 @@
+// WHY: The original receipt hash formatted EventData with fmt %v, which
+// depends on Go's in-memory types: a []string and the []interface{} that
+// JSON decoding produces can print the same today, but ints, floats, and
+// json.Numbers need not. A re-verification in another process or language
+// must not spuriously fail, so receipts now hash a canonical JSON encoding.
+// Legacy receipts keep their original hash so existing ledgers still verify.
+package audit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// Receipt hash versions
+const (
+	// HashVersionLegacy hashes "seq|ts|type|%v(data)|prev" (receipts without hash_version)
+	HashVersionLegacy = 0
+	// HashVersionCanonical hashes canonical JSON (see canonicalReceiptBytes)
+	HashVersionCanonical = 1
+	// HashVersionClassified adds severity and category to the canonical JSON
+	HashVersionClassified = 2
+	// HashVersionSchema adds the receipt schema version
+	HashVersionSchema = 3
+
+	// CurrentHashVersion is used for every new receipt
+	CurrentHashVersion = HashVersionSchema
+)
+
+// computeHash generates a cryptographic hash for a receipt using the
+// receipt's own hash version. It returns "" if the receipt cannot be
+// encoded, which never matches a stored hash.
+func computeHash(r Receipt) string {
+	switch r.HashVersion {
+	case HashVersionLegacy:
+		return legacyHash(r)
+	case HashVersionCanonical, HashVersionClassified, HashVersionSchema:
+		encoded, err := canonicalReceiptBytes(r)
+		if err != nil {
+			return ""
+		}
+		sum := sha256.Sum256(encoded)
+		return hex.EncodeToString(sum[:])
+	default:
+		return ""
+	}
+}
+
+// legacyHash is the original fmt-based receipt hash
+func legacyHash(r Receipt) string {
+	h := sha256.New()
+	h.Write([]byte(fmt.Sprintf("%d|%d|%s|%v|%s",
+		r.Sequence, r.Timestamp, r.EventType, r.EventData, r.PrevHash)))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// canonicalReceiptBytes encodes the hashed fields of a receipt as JSON
+// with sorted object keys, no insignificant whitespace, no HTML escaping,
+// and integers written without exponent or fraction. The hash version is
+// part of the encoding so a receipt cannot be re-read under another scheme.
+func canonicalReceiptBytes(r Receipt) ([]byte, error) {
+	data, err := normalizeValue(r.EventData)
+	if err != nil {
+		return nil, fmt.Errorf("receipt %d: %w", r.Sequence, err)
+	}
+
+	// Map keys are sorted by encoding/json
+	document := map[string]interface{}{
+		"event_data":   data,
+		"event_type":   r.EventType,
+		"hash_version": r.HashVersion,
+		"prev_hash":    r.PrevHash,
+		"sequence":     r.Sequence,
+		"timestamp":    r.Timestamp,
+	}
+	if r.HashVersion >= HashVersionClassified {
+		document["category"] = r.Category
+		document["severity"] = r.Severity
+	}
+	if r.HashVersion >= HashVersionSchema {
+		document["schema_version"] = r.SchemaVersion
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(document); err != nil {
+		return nil, fmt.Errorf("receipt %d: %w", r.Sequence, err)
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// normalizeValue round-trips a value through JSON so that equivalent Go
+// representations (int vs json.Number, []string vs []interface{}) encode
+// identically, and rejects non-integral numbers, which have no single
+// canonical text form.
+func normalizeValue(value interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var normalized interface{}
+	if err := decoder.Decode(&normalized); err != nil {
+		return nil, err
+	}
+	if err := checkIntegers(normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// checkIntegers walks decoded JSON and rejects numbers that are not integers
+func checkIntegers(value interface{}) error {
+	switch v := value.(type) {
+	case json.Number:
+		if _, err := v.Int64(); err != nil {
+			return fmt.Errorf("non-integer number %s in event data", v)
+		}
+	case map[string]interface{}:
+		for _, child := range v {
+			if err := checkIntegers(child); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			if err := checkIntegers(child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
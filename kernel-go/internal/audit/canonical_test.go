@@ -0,0 +1,131 @@
+// WHY: These tests pin the canonical receipt encoding and prove legacy
+// ledgers keep verifying alongside canonical receipts.
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func canonicalFixture() Receipt {
+	return Receipt{
+		Sequence:  7,
+		Timestamp: 1700000000,
+		EventType: "cdi_decision",
+		EventData: map[string]interface{}{
+			"decision":       "ALLOW",
+			"scope":          []string{"a", "b<c>"},
+			"tokens_revoked": 3,
+		},
+		PrevHash:    "abc",
+		HashVersion: HashVersionCanonical,
+	}
+}
+
+// TestCanonicalHashGoldenVector proves the encoding is stable and matches an
+// independent implementation (sorted-key compact JSON, no HTML escaping).
+// If this test fails the encoding changed: add a hash version instead.
+func TestCanonicalHashGoldenVector(t *testing.T) {
+	const expectedJSON = `{"event_data":{"decision":"ALLOW","scope":["a","b<c>"],"tokens_revoked":3},"event_type":"cdi_decision","hash_version":1,"prev_hash":"abc","sequence":7,"timestamp":1700000000}`
+	const expectedHash = "f47b217863472ff6e4b84a6d63ff38df77e27a0f783b037bfaec0739a05ddde2"
+
+	encoded, err := canonicalReceiptBytes(canonicalFixture())
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if string(encoded) != expectedJSON {
+		t.Fatalf("canonical encoding changed:\n%s", encoded)
+	}
+	if got := computeHash(canonicalFixture()); got != expectedHash {
+		t.Fatalf("canonical hash changed: %s", got)
+	}
+}
+
+// TestClassifiedHashGoldenVector proves hash version 2 binds severity and category
+func TestClassifiedHashGoldenVector(t *testing.T) {
+	const expectedHash = "8305c2f8fcadcde9c9adf69a0801fa3b8bc8bcad6dc3638c42ce4ab170e5b1c2"
+
+	receipt := canonicalFixture()
+	receipt.HashVersion = HashVersionClassified
+	receipt.Severity = SeverityInfo
+	receipt.Category = CategoryDecision
+	if got := computeHash(receipt); got != expectedHash {
+		t.Fatalf("classified hash changed: %s", got)
+	}
+
+	receipt.Severity = SeverityCritical
+	if computeHash(receipt) == expectedHash {
+		t.Fatal("severity must be part of the hash")
+	}
+}
+
+// TestCanonicalHashIgnoresGoRepresentation proves decoded and in-memory
+// event data hash identically
+func TestCanonicalHashIgnoresGoRepresentation(t *testing.T) {
+	decoded := canonicalFixture()
+	decoded.EventData = map[string]interface{}{
+		"tokens_revoked": json.Number("3"),
+		"scope":          []interface{}{"a", "b<c>"},
+		"decision":       "ALLOW",
+	}
+	if computeHash(decoded) != computeHash(canonicalFixture()) {
+		t.Fatal("equivalent event data must hash identically")
+	}
+
+	floating := canonicalFixture()
+	floating.EventData = map[string]interface{}{"ratio": 0.5}
+	if computeHash(floating) != "" {
+		t.Fatal("non-integer numbers have no canonical form and must not hash")
+	}
+}
+
+// TestLegacyLedgerStillVerifies proves pre-canonical files load and extend
+func TestLegacyLedgerStillVerifies(t *testing.T) {
+	genesis := Receipt{Sequence: 0, Timestamp: 1, EventType: "genesis",
+		EventData: map[string]interface{}{"message": "audit ledger initialized"}, PrevHash: "0000000000000000"}
+	genesis.CurrentHash = legacyHash(genesis)
+	stop := Receipt{Sequence: 1, Timestamp: 2, EventType: "stop_event",
+		EventData: map[string]interface{}{"tokens_revoked": 2}, PrevHash: genesis.CurrentHash}
+	stop.CurrentHash = legacyHash(stop)
+
+	path := filepath.Join(t.TempDir(), "legacy.jsonl")
+	var lines []byte
+	for _, receipt := range []Receipt{genesis, stop} {
+		line, _ := json.Marshal(receipt)
+		lines = append(append(lines, line...), '\n')
+	}
+	if err := os.WriteFile(path, lines, 0o600); err != nil {
+		t.Fatalf("write legacy file: %v", err)
+	}
+
+	store, _ := OpenFileStore(path)
+	ledger, err := OpenLedger(store)
+	if err != nil {
+		t.Fatalf("legacy ledger should load: %v", err)
+	}
+	defer ledger.Close()
+
+	ledger.AppendStopEvent(testActor, 3)
+	receipts := ledger.GetReceipts()
+	if receipts[1].HashVersion != HashVersionLegacy || receipts[2].HashVersion != CurrentHashVersion {
+		t.Fatal("legacy receipts keep their version; new receipts are canonical")
+	}
+	if valid, err := ledger.Verify(); !valid {
+		t.Fatalf("mixed legacy/canonical chain should verify: %v", err)
+	}
+
+	// Re-hashing a canonical receipt under the legacy scheme is a downgrade
+	if err := verifyChain([]Receipt{receipts[0], receipts[1], receipts[2], downgradeSuccessor(receipts[2])}); err == nil {
+		t.Fatal("a legacy receipt after a canonical one must be rejected")
+	}
+}
+
+// downgradeSuccessor builds a legacy-hashed receipt linked after prev
+func downgradeSuccessor(prev Receipt) Receipt {
+	next := Receipt{Sequence: prev.Sequence + 1, Timestamp: prev.Timestamp, EventType: "stop_event",
+		EventData: map[string]interface{}{"tokens_revoked": 0}, PrevHash: prev.CurrentHash}
+	next.CurrentHash = legacyHash(next)
+	return next
+}
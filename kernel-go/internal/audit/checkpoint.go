@@ -0,0 +1,239 @@
+// WHY: Checkpoints anchor batches of receipts to Merkle roots that are
+// written both into the chain and to an external evidence store, so
+// tampering with old history stays detectable after rotation or compaction.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Checkpoint commits to receipts StartSequence..EndSequence (inclusive).
+// HashVersion is the lowest hash version in the range; no receipt from
+// StartSequence on may be hashed below it.
+type Checkpoint struct {
+	StartSequence int64  `json:"start_sequence"`
+	EndSequence   int64  `json:"end_sequence"`
+	MerkleRoot    string `json:"merkle_root"`
+	HashVersion   int    `json:"hash_version,omitempty"`
+}
+
+// CheckpointSink receives each new checkpoint, e.g. to store it in the
+// evidence memory partition. It is called without the ledger lock held.
+type CheckpointSink func(Checkpoint) error
+
+// SetCheckpointPolicy enables automatic checkpoints every interval receipts
+// and registers where they are exported. An interval of 0 disables them.
+func (l *Ledger) SetCheckpointPolicy(interval int, sink CheckpointSink) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.checkpointInterval = interval
+	l.checkpointSink = sink
+}
+
+// Checkpoint forces a checkpoint over every receipt since the last one
+func (l *Ledger) Checkpoint() (Checkpoint, error) {
+	l.mu.Lock()
+	checkpoint, err := l.checkpointLocked()
+	sink := l.checkpointSink
+	l.mu.Unlock()
+
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	return checkpoint, l.exportCheckpoint(sink, checkpoint)
+}
+
+// maybeCheckpointLocked cuts a checkpoint when the interval is reached.
+// Callers must hold l.mu.
+func (l *Ledger) maybeCheckpointLocked() (*Checkpoint, error) {
+	if l.checkpointInterval <= 0 {
+		return nil, nil
+	}
+	if l.sequence-l.lastCheckpointEnd < int64(l.checkpointInterval) {
+		return nil, nil
+	}
+	checkpoint, err := l.checkpointLocked()
+	if err != nil {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+// checkpointLocked computes a checkpoint and appends its receipt.
+// Callers must hold l.mu.
+func (l *Ledger) checkpointLocked() (Checkpoint, error) {
+	start := l.lastCheckpointEnd + 1
+	batch := l.receiptRangeLocked(start, l.sequence)
+	if len(batch) == 0 {
+		return Checkpoint{}, fmt.Errorf("no receipts since last checkpoint")
+	}
+
+	root, err := ReceiptsMerkleRoot(batch)
+	if err != nil {
+		return Checkpoint{}, err
+	}
+
+	checkpoint := Checkpoint{
+		StartSequence: start,
+		EndSequence:   l.sequence,
+		MerkleRoot:    root,
+		HashVersion:   batch[0].HashVersion,
+	}
+	for _, receipt := range batch {
+		checkpoint.HashVersion = min(checkpoint.HashVersion, receipt.HashVersion)
+	}
+	l.appendLocked("checkpoint", map[string]interface{}{
+		"start_sequence": checkpoint.StartSequence,
+		"end_sequence":   checkpoint.EndSequence,
+		"merkle_root":    checkpoint.MerkleRoot,
+		"hash_version":   checkpoint.HashVersion,
+	})
+	l.lastCheckpointEnd = checkpoint.EndSequence
+
+	return checkpoint, nil
+}
+
+// exportCheckpoint hands a checkpoint to the sink.
+// WHY: An unexported checkpoint protects nothing, so export failure
+// poisons verification like any other lost audit sink.
+func (l *Ledger) exportCheckpoint(sink CheckpointSink, checkpoint Checkpoint) error {
+	if sink == nil {
+		return nil
+	}
+	if err := sink(checkpoint); err != nil {
+		l.mu.Lock()
+		if l.persistErr == nil {
+			l.persistErr = fmt.Errorf("checkpoint %d-%d not exported: %w",
+				checkpoint.StartSequence, checkpoint.EndSequence, err)
+		}
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Checkpoints returns every checkpoint recorded in the chain
+func (l *Ledger) Checkpoints() []Checkpoint {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return checkpointsIn(l.receipts)
+}
+
+// VerifyCheckpoints checks externally held checkpoints (for example from
+// the evidence partition) against the receipts still in the ledger and the
+// checkpoint receipts in the chain. Each must be recorded in the chain,
+// unless its range was rotated out of memory.
+// WHY: If someone rewrites history and recomputes every hash, the roots
+// stored outside the chain no longer match; if they drop the checkpoint
+// receipts as well, the chain no longer records the roots held outside it.
+func (l *Ledger) VerifyCheckpoints(external []Checkpoint) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	recorded := map[int64]Checkpoint{}
+	for _, checkpoint := range checkpointsIn(l.receipts) {
+		recorded[checkpoint.EndSequence] = checkpoint
+	}
+
+	for _, checkpoint := range external {
+		inChain, ok := recorded[checkpoint.EndSequence]
+		if !ok && (len(l.receipts) == 0 || checkpoint.EndSequence >= l.receipts[0].Sequence) {
+			return fmt.Errorf("checkpoint %d-%d is not recorded in the chain",
+				checkpoint.StartSequence, checkpoint.EndSequence)
+		}
+		if ok && inChain != checkpoint {
+			return fmt.Errorf("checkpoint %d-%d does not match chain record",
+				checkpoint.StartSequence, checkpoint.EndSequence)
+		}
+		if err := l.verifyCheckpointLocked(checkpoint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyCheckpointLocked checks no held receipt from the checkpoint on is
+// hashed below its hash version, and recomputes its root when its receipts
+// are still held. Callers must hold l.mu.
+// WHY: The hash version a checkpoint records outlives its range, so a
+// chain rewritten at the legacy hash fails even once the receipts the
+// checkpoint covers have rotated away
+func (l *Ledger) verifyCheckpointLocked(checkpoint Checkpoint) error {
+	for _, receipt := range l.receipts {
+		if receipt.Sequence >= checkpoint.StartSequence && receipt.HashVersion < checkpoint.HashVersion {
+			return fmt.Errorf("receipt %d hash version %d is below the %d checkpoint %d-%d records",
+				receipt.Sequence, receipt.HashVersion, checkpoint.HashVersion,
+				checkpoint.StartSequence, checkpoint.EndSequence)
+		}
+	}
+
+	batch := l.receiptRangeLocked(checkpoint.StartSequence, checkpoint.EndSequence)
+	if int64(len(batch)) != checkpoint.EndSequence-checkpoint.StartSequence+1 {
+		// Range rotated away or compacted; the chain record is the anchor
+		return nil
+	}
+
+	root, err := ReceiptsMerkleRoot(batch)
+	if err != nil {
+		return err
+	}
+	if root != checkpoint.MerkleRoot {
+		return fmt.Errorf("checkpoint %d-%d merkle root mismatch: expected %s, got %s",
+			checkpoint.StartSequence, checkpoint.EndSequence, checkpoint.MerkleRoot, root)
+	}
+	return nil
+}
+
+// receiptRangeLocked returns held receipts with sequence in [start, end].
+// Callers must hold l.mu.
+func (l *Ledger) receiptRangeLocked(start, end int64) []Receipt {
+	batch := []Receipt{}
+	for _, receipt := range l.receipts {
+		if receipt.Sequence >= start && receipt.Sequence <= end {
+			batch = append(batch, receipt)
+		}
+	}
+	return batch
+}
+
+// checkpointsIn extracts checkpoints from checkpoint receipts
+func checkpointsIn(receipts []Receipt) []Checkpoint {
+	checkpoints := []Checkpoint{}
+	for _, receipt := range receipts {
+		if receipt.EventType != "checkpoint" {
+			continue
+		}
+		root, _ := receipt.EventData["merkle_root"].(string)
+		checkpoints = append(checkpoints, Checkpoint{
+			StartSequence: toInt64(receipt.EventData["start_sequence"]),
+			EndSequence:   toInt64(receipt.EventData["end_sequence"]),
+			MerkleRoot:    root,
+			HashVersion:   int(toInt64(receipt.EventData["hash_version"])),
+		})
+	}
+	return checkpoints
+}
+
+// toInt64 reads an integer from in-memory or JSON-decoded event data
+func toInt64(value interface{}) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	case json.Number:
+		n, _ := v.Int64()
+		return n
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,232 @@
+// WHY: High-throughput kernels write far more routine receipts (accepted
+// adapter calls, memory writes, approved egress) than anyone investigates.
+// Compaction replaces a run of them with a summary receipt that commits to
+// the run's Merkle root and bridges the hash chain across the gap, so memory
+// stays bounded while Verify still proves nothing was altered or removed
+// silently. Compacted receipts can be archived and later checked against
+// the summary with VerifyCompaction.
+package audit
+
+import "fmt"
+
+// routineEvents are the event types compaction may remove, and only at
+// info severity. Decisions and mints stay held because Lineage needs them.
+var routineEvents = map[string]bool{
+	"adapter_attempt": true,
+	"memory_write":    true,
+	"memory_read":     true,
+	"egress_decision": true,
+}
+
+// Compaction summarizes a compacted run of receipts
+type Compaction struct {
+	StartSequence int64
+	EndSequence   int64
+	MerkleRoot    string
+
+	// FirstPrevHash and LastHash are the chain hashes on either side of
+	// the run; verification uses them to bridge the gap
+	FirstPrevHash string
+	LastHash      string
+
+	// Receipts are populated when returned from Compact, for archiving;
+	// Compactions() leaves them empty
+	Receipts []Receipt
+}
+
+// isRoutine reports whether a receipt may be compacted
+func isRoutine(receipt Receipt) bool {
+	return routineEvents[receipt.EventType] && receiptSeverity(receipt) == SeverityInfo
+}
+
+// Compact replaces receipts start..end (inclusive) with a summary receipt
+// appended at the head of the chain.
+// WHY: Only sealed segments are compacted: their checkpoints and seals are
+// already cut, so no later root has to be computed over a missing receipt.
+func (l *Ledger) Compact(start, end int64) (Compaction, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.compactLocked(start, end)
+}
+
+// CompactRoutine compacts every run of at least minRun routine receipts in
+// the sealed segments still held in memory
+func (l *Ledger) CompactRoutine(minRun int) ([]Compaction, error) {
+	if minRun < 1 {
+		return nil, fmt.Errorf("minimum compaction run must be positive, got %d", minRun)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Find runs first: compacting rewrites l.receipts
+	type run struct{ start, end int64 }
+	runs := []run{}
+	length := 0
+	for i, receipt := range l.receipts {
+		contiguous := i > 0 && receipt.Sequence == l.receipts[i-1].Sequence+1
+		if receipt.Sequence >= l.segmentStart || !isRoutine(receipt) {
+			length = 0
+			continue
+		}
+		if length > 0 && contiguous {
+			length++
+			runs[len(runs)-1].end = receipt.Sequence
+		} else {
+			length = 1
+			runs = append(runs, run{receipt.Sequence, receipt.Sequence})
+		}
+	}
+
+	compactions := []Compaction{}
+	for _, r := range runs {
+		if r.end-r.start+1 < int64(minRun) {
+			continue
+		}
+		compaction, err := l.compactLocked(r.start, r.end)
+		if err != nil {
+			return compactions, err
+		}
+		compactions = append(compactions, compaction)
+	}
+	return compactions, nil
+}
+
+// compactLocked validates and compacts one run. Callers must hold l.mu.
+func (l *Ledger) compactLocked(start, end int64) (Compaction, error) {
+	if start < 1 || end < start {
+		return Compaction{}, fmt.Errorf("invalid compaction range %d-%d", start, end)
+	}
+	if end >= l.segmentStart {
+		return Compaction{}, fmt.Errorf("receipts %d-%d are in the open segment; rotate before compacting", start, end)
+	}
+
+	batch := l.receiptRangeLocked(start, end)
+	if int64(len(batch)) != end-start+1 {
+		return Compaction{}, fmt.Errorf("receipts %d-%d are not all held (already compacted or rotated away)", start, end)
+	}
+	for _, receipt := range batch {
+		if !isRoutine(receipt) {
+			return Compaction{}, fmt.Errorf("receipt %d (%s) is not routine and cannot be compacted", receipt.Sequence, receipt.EventType)
+		}
+	}
+
+	root, err := ReceiptsMerkleRoot(batch)
+	if err != nil {
+		return Compaction{}, err
+	}
+	compaction := Compaction{
+		StartSequence: start,
+		EndSequence:   end,
+		MerkleRoot:    root,
+		FirstPrevHash: batch[0].PrevHash,
+		LastHash:      batch[len(batch)-1].CurrentHash,
+	}
+
+	l.appendLocked("compaction_summary", map[string]interface{}{
+		"compacted_start":     compaction.StartSequence,
+		"compacted_end":       compaction.EndSequence,
+		"compacted_count":     len(batch),
+		"compacted_root":      compaction.MerkleRoot,
+		"compacted_prev_hash": compaction.FirstPrevHash,
+		"compacted_last_hash": compaction.LastHash,
+	})
+	l.receipts = withoutRange(l.receipts, start, end)
+
+	compaction.Receipts = batch
+	return compaction, nil
+}
+
+// Compactions returns every compaction recorded in the held chain
+func (l *Ledger) Compactions() []Compaction {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return compactionsIn(l.receipts)
+}
+
+// VerifyCompaction checks archived receipts against a compaction summary.
+// WHY: Auditors restoring a compacted run must be able to prove it is the
+// exact run the ledger removed.
+func VerifyCompaction(compaction Compaction, receipts []Receipt) error {
+	if int64(len(receipts)) != compaction.EndSequence-compaction.StartSequence+1 {
+		return fmt.Errorf("compaction %d-%d covers %d receipts, got %d", compaction.StartSequence,
+			compaction.EndSequence, compaction.EndSequence-compaction.StartSequence+1, len(receipts))
+	}
+	if err := verifyChain(receipts); err != nil {
+		return err
+	}
+
+	first, last := receipts[0], receipts[len(receipts)-1]
+	if first.Sequence != compaction.StartSequence || first.PrevHash != compaction.FirstPrevHash {
+		return fmt.Errorf("compaction %d-%d does not start at receipt %d", compaction.StartSequence, compaction.EndSequence, first.Sequence)
+	}
+	if last.CurrentHash != compaction.LastHash {
+		return fmt.Errorf("compaction %d-%d does not end at receipt %d", compaction.StartSequence, compaction.EndSequence, last.Sequence)
+	}
+
+	root, err := ReceiptsMerkleRoot(receipts)
+	if err != nil {
+		return err
+	}
+	if root != compaction.MerkleRoot {
+		return fmt.Errorf("compaction %d-%d merkle root mismatch: expected %s, got %s",
+			compaction.StartSequence, compaction.EndSequence, compaction.MerkleRoot, root)
+	}
+	return nil
+}
+
+// compactionsIn extracts compactions from summary receipts
+func compactionsIn(receipts []Receipt) []Compaction {
+	compactions := []Compaction{}
+	for _, receipt := range receipts {
+		if receipt.EventType != "compaction_summary" {
+			continue
+		}
+		root, _ := receipt.EventData["compacted_root"].(string)
+		prevHash, _ := receipt.EventData["compacted_prev_hash"].(string)
+		lastHash, _ := receipt.EventData["compacted_last_hash"].(string)
+		compactions = append(compactions, Compaction{
+			StartSequence: toInt64(receipt.EventData["compacted_start"]),
+			EndSequence:   toInt64(receipt.EventData["compacted_end"]),
+			MerkleRoot:    root,
+			FirstPrevHash: prevHash,
+			LastHash:      lastHash,
+		})
+	}
+	return compactions
+}
+
+// bridgesCompaction reports whether a recorded compaction accounts for the
+// gap between two held receipts
+func bridgesCompaction(compactions []Compaction, before, after Receipt) bool {
+	for _, compaction := range compactions {
+		if compaction.StartSequence == before.Sequence+1 &&
+			compaction.EndSequence == after.Sequence-1 &&
+			compaction.FirstPrevHash == before.CurrentHash &&
+			compaction.LastHash == after.PrevHash {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutCompacted drops every recorded compacted run from a loaded chain
+func withoutCompacted(receipts []Receipt) []Receipt {
+	for _, compaction := range compactionsIn(receipts) {
+		receipts = withoutRange(receipts, compaction.StartSequence, compaction.EndSequence)
+	}
+	return receipts
+}
+
+// withoutRange copies receipts outside [start, end]
+func withoutRange(receipts []Receipt, start, end int64) []Receipt {
+	kept := make([]Receipt, 0, len(receipts))
+	for _, receipt := range receipts {
+		if receipt.Sequence < start || receipt.Sequence > end {
+			kept = append(kept, receipt)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,142 @@
+// WHY: These tests prove compaction bounds memory without weakening
+// verification: summaries bridge the chain, commit to the removed run, and
+// a forged gap is still detected.
+package audit
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// appendRoutine appends n routine receipts
+func appendRoutine(ledger *Ledger, n int) {
+	for i := 0; i < n; i++ {
+		ledger.AppendAdapterAttempt(testActor, "mock_adapter", true, "digest")
+	}
+}
+
+// TestCompactRoutineBridgesChain proves routine runs are replaced by a
+// summary that Verify accepts and archived receipts can be checked against
+func TestCompactRoutineBridgesChain(t *testing.T) {
+	ledger := NewLedger()
+	appendRoutine(ledger, 5)
+	ledger.AppendCDIDecision(testActor, "DENY", "h", "", "d1")
+	appendRoutine(ledger, 2)
+	if _, err := ledger.Rotate(); err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	appendRoutine(ledger, 3) // open segment: never compacted
+
+	before := len(ledger.GetReceipts())
+	compactions, err := ledger.CompactRoutine(3)
+	if err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+	if len(compactions) != 1 || compactions[0].StartSequence != 1 || compactions[0].EndSequence != 5 {
+		t.Fatalf("expected one compaction of receipts 1-5, got %+v", compactions)
+	}
+	if held := len(ledger.GetReceipts()); held != before-5+1 {
+		t.Fatalf("compaction should drop 5 receipts and add a summary, held %d of %d", held, before)
+	}
+	if valid, err := ledger.Verify(); !valid {
+		t.Fatalf("compacted ledger should verify: %v", err)
+	}
+
+	if err := VerifyCompaction(compactions[0], compactions[0].Receipts); err != nil {
+		t.Fatalf("archived run should match its summary: %v", err)
+	}
+	if recorded := ledger.Compactions(); len(recorded) != 1 || recorded[0].MerkleRoot != compactions[0].MerkleRoot {
+		t.Fatal("summary receipt should record the compaction")
+	}
+
+	altered := append([]Receipt(nil), compactions[0].Receipts...)
+	altered = altered[:len(altered)-1]
+	if err := VerifyCompaction(compactions[0], altered); err == nil {
+		t.Fatal("a truncated archive should not match the summary")
+	}
+}
+
+// TestCompactRejectsNonRoutineAndOpenRanges proves decisions, the open
+// segment, and missing receipts are never compacted
+func TestCompactRejectsNonRoutineAndOpenRanges(t *testing.T) {
+	ledger := NewLedger()
+	appendRoutine(ledger, 2)
+	ledger.AppendCDIDecision(testActor, "ALLOW", "h", "", "d1")
+
+	if _, err := ledger.Compact(1, 2); err == nil {
+		t.Fatal("open segment should not be compacted")
+	}
+	ledger.Rotate()
+
+	if _, err := ledger.Compact(1, 3); err == nil {
+		t.Fatal("cdi_decision should not be compacted")
+	}
+	if _, err := ledger.Compact(0, 1); err == nil {
+		t.Fatal("genesis should not be compacted")
+	}
+	if _, err := ledger.Compact(1, 2); err != nil {
+		t.Fatalf("routine run should compact: %v", err)
+	}
+	if _, err := ledger.Compact(1, 2); err == nil {
+		t.Fatal("a run cannot be compacted twice")
+	}
+	if _, err := ledger.CompactRoutine(0); err == nil {
+		t.Fatal("non-positive run length should be rejected")
+	}
+}
+
+// TestUnbridgedGapIsDetected proves deleting receipts without a matching
+// summary still breaks the chain
+func TestUnbridgedGapIsDetected(t *testing.T) {
+	ledger := NewLedger()
+	appendRoutine(ledger, 4)
+	ledger.Rotate()
+	if _, err := ledger.Compact(1, 2); err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+
+	// Silently drop another receipt next to the compacted run
+	ledger.receipts = withoutRange(ledger.receipts, 3, 3)
+	if valid, _ := ledger.Verify(); valid {
+		t.Fatal("a gap without a summary should fail verification")
+	}
+}
+
+// TestCompactedFileLedgerReloads proves compaction survives restart while
+// the store keeps full history
+func TestCompactedFileLedgerReloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	store, _ := OpenFileStore(path)
+	ledger, err := OpenLedger(store)
+	if err != nil {
+		t.Fatalf("open ledger: %v", err)
+	}
+	appendRoutine(ledger, 4)
+	ledger.Rotate()
+	if _, err := ledger.Compact(1, 4); err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+	held := len(ledger.GetReceipts())
+	ledger.Close()
+
+	store, _ = OpenFileStore(path)
+	stored, _ := store.Load()
+	store.Close()
+	if len(stored) != held+4 {
+		t.Fatalf("store should keep compacted receipts: %d stored, %d held", len(stored), held)
+	}
+
+	store, _ = OpenFileStore(path)
+	reopened, err := OpenLedger(store)
+	if err != nil {
+		t.Fatalf("reopen ledger: %v", err)
+	}
+	defer reopened.Close()
+
+	if len(reopened.GetReceipts()) != held {
+		t.Fatalf("reload should re-apply compaction, held %d want %d", len(reopened.GetReceipts()), held)
+	}
+	if valid, err := reopened.Verify(); !valid {
+		t.Fatalf("reloaded compacted ledger should verify: %v", err)
+	}
+}
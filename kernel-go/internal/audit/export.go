@@ -0,0 +1,325 @@
+// WHY: SOC teams already run SIEMs and log pipelines. Exporting receipts in
+// the formats those tools ingest (JSONL, CSV, CEF, OTLP) lets governance
+// events flow into existing tooling instead of being scraped from
+// GetReceipts by hand. Exports are read-only snapshots of the chain.
+package audit
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ExportFormat names a receipt export encoding
+type ExportFormat string
+
+const (
+	// FormatJSONL writes one JSON receipt per line, identical to FileStore lines
+	FormatJSONL ExportFormat = "jsonl"
+	// FormatCSV writes a header row and one row per receipt
+	FormatCSV ExportFormat = "csv"
+	// FormatCEF writes ArcSight Common Event Format lines
+	FormatCEF ExportFormat = "cef"
+	// FormatOTLP writes an OTLP/JSON ExportLogsServiceRequest document
+	FormatOTLP ExportFormat = "otlp"
+)
+
+// ParseExportFormat validates a format name
+func ParseExportFormat(name string) (ExportFormat, error) {
+	switch format := ExportFormat(strings.ToLower(name)); format {
+	case FormatJSONL, FormatCSV, FormatCEF, FormatOTLP:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown export format %q (want jsonl, csv, cef, or otlp)", name)
+	}
+}
+
+// Export writes a snapshot of the receipt chain in the given format
+func (l *Ledger) Export(w io.Writer, format ExportFormat) error {
+	return ExportReceipts(w, l.GetReceipts(), format)
+}
+
+// ExportReceipts writes receipts in the given format
+func ExportReceipts(w io.Writer, receipts []Receipt, format ExportFormat) error {
+	switch format {
+	case FormatJSONL:
+		return exportJSONL(w, receipts)
+	case FormatCSV:
+		return exportCSV(w, receipts)
+	case FormatCEF:
+		return exportCEF(w, receipts)
+	case FormatOTLP:
+		return exportOTLP(w, receipts)
+	default:
+		return fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+func exportJSONL(w io.Writer, receipts []Receipt) error {
+	encoder := json.NewEncoder(w)
+	for _, receipt := range receipts {
+		if err := encoder.Encode(receipt); err != nil {
+			return fmt.Errorf("export receipt %d: %w", receipt.Sequence, err)
+		}
+	}
+	return nil
+}
+
+var csvHeader = []string{
+	"sequence", "timestamp", "event_type", "event_data",
+	"prev_hash", "current_hash", "signer_key_id", "signature",
+	"severity", "category",
+}
+
+func exportCSV(w io.Writer, receipts []Receipt) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, receipt := range receipts {
+		eventData, err := json.Marshal(receipt.EventData)
+		if err != nil {
+			return fmt.Errorf("export receipt %d: %w", receipt.Sequence, err)
+		}
+		row := []string{
+			strconv.FormatInt(receipt.Sequence, 10),
+			strconv.FormatInt(receipt.Timestamp, 10),
+			receipt.EventType,
+			string(eventData),
+			receipt.PrevHash,
+			receipt.CurrentHash,
+			receipt.SignerKeyID,
+			hex.EncodeToString(receipt.Signature),
+			string(receipt.Severity),
+			string(receipt.Category),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// cefSeverity maps receipt severity onto the CEF 0-10 scale
+var cefSeverity = map[Severity]int{
+	SeverityInfo:     3,
+	SeverityWarn:     6,
+	SeverityCritical: 9,
+}
+
+// otlpSeverity maps receipt severity onto OTLP severity numbers and text
+var otlpSeverity = map[Severity]struct {
+	number int
+	text   string
+}{
+	SeverityInfo:     {9, "INFO"},
+	SeverityWarn:     {13, "WARN"},
+	SeverityCritical: {21, "FATAL"},
+}
+
+// receiptSeverity returns a receipt's severity, classifying receipts
+// written before severity was recorded
+func receiptSeverity(receipt Receipt) Severity {
+	if receipt.Severity != "" {
+		return receipt.Severity
+	}
+	return classify(receipt.EventType, receipt.EventData).severity
+}
+
+// exportCEF writes one CEF line per receipt. Receipt hashes travel in
+// custom string fields so a SIEM can correlate events back to the chain.
+func exportCEF(w io.Writer, receipts []Receipt) error {
+	for _, receipt := range receipts {
+		eventData, err := json.Marshal(receipt.EventData)
+		if err != nil {
+			return fmt.Errorf("export receipt %d: %w", receipt.Sequence, err)
+		}
+
+		severity := cefSeverity[receiptSeverity(receipt)]
+
+		extensions := []string{
+			"rt=" + strconv.FormatInt(receipt.Timestamp*1000, 10),
+			"cn1=" + strconv.FormatInt(receipt.Sequence, 10),
+			"cn1Label=sequence",
+			"cs1=" + cefExtension(receipt.CurrentHash),
+			"cs1Label=currentHash",
+			"cs2=" + cefExtension(receipt.PrevHash),
+			"cs2Label=prevHash",
+			"cs3=" + cefExtension(receipt.SignerKeyID),
+			"cs3Label=signerKeyId",
+			"cs4=" + cefExtension(string(eventData)),
+			"cs4Label=eventData",
+			"cs5=" + cefExtension(string(receipt.Category)),
+			"cs5Label=category",
+		}
+
+		line := fmt.Sprintf("CEF:0|OI|oi-kernel|1|%s|%s|%d|%s\n",
+			cefHeader(receipt.EventType),
+			cefHeader(strings.ReplaceAll(receipt.EventType, "_", " ")),
+			severity,
+			strings.Join(extensions, " "))
+		if _, err := io.WriteString(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cefHeader escapes a CEF header field
+func cefHeader(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return strings.ReplaceAll(value, "|", `\|`)
+}
+
+// cefExtension escapes a CEF extension value
+func cefExtension(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, "=", `\=`)
+	value = strings.ReplaceAll(value, "\r", `\r`)
+	return strings.ReplaceAll(value, "\n", `\n`)
+}
+
+// OTLP/JSON log data model, limited to the fields receipts use
+type otlpLogsRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano   string          `json:"timeUnixNano"`
+	SeverityNumber int             `json:"severityNumber"`
+	SeverityText   string          `json:"severityText"`
+	Body           otlpValue       `json:"body"`
+	Attributes     []otlpAttribute `json:"attributes"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+	BoolValue   *bool   `json:"boolValue,omitempty"`
+}
+
+func otlpString(value string) otlpValue {
+	return otlpValue{StringValue: &value}
+}
+
+func otlpInt(value int64) otlpValue {
+	text := strconv.FormatInt(value, 10)
+	return otlpValue{IntValue: &text}
+}
+
+// otlpEventValue maps an event data value onto an OTLP attribute value.
+// Lists and other structures are carried as JSON strings.
+func otlpEventValue(value interface{}) otlpValue {
+	switch v := value.(type) {
+	case string:
+		return otlpString(v)
+	case bool:
+		return otlpValue{BoolValue: &v}
+	case int:
+		return otlpInt(int64(v))
+	case int64:
+		return otlpInt(v)
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return otlpInt(n)
+		}
+		return otlpString(v.String())
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return otlpString(fmt.Sprintf("%v", v))
+		}
+		return otlpString(string(encoded))
+	}
+}
+
+// exportOTLP writes all receipts as a single OTLP/JSON logs request,
+// ready to POST to a collector's /v1/logs endpoint.
+func exportOTLP(w io.Writer, receipts []Receipt) error {
+	records := make([]otlpLogRecord, 0, len(receipts))
+	for _, receipt := range receipts {
+		attributes := []otlpAttribute{
+			{Key: "oi.sequence", Value: otlpInt(receipt.Sequence)},
+			{Key: "oi.event_type", Value: otlpString(receipt.EventType)},
+			{Key: "oi.prev_hash", Value: otlpString(receipt.PrevHash)},
+			{Key: "oi.current_hash", Value: otlpString(receipt.CurrentHash)},
+		}
+		if receipt.Category != "" {
+			attributes = append(attributes, otlpAttribute{Key: "oi.category", Value: otlpString(string(receipt.Category))})
+		}
+		if receipt.SignerKeyID != "" {
+			attributes = append(attributes,
+				otlpAttribute{Key: "oi.signer_key_id", Value: otlpString(receipt.SignerKeyID)},
+				otlpAttribute{Key: "oi.signature", Value: otlpString(hex.EncodeToString(receipt.Signature))})
+		}
+
+		// Sorted keys keep the export byte-for-byte reproducible
+		keys := make([]string, 0, len(receipt.EventData))
+		for key := range receipt.EventData {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			attributes = append(attributes, otlpAttribute{
+				Key:   "oi.event." + key,
+				Value: otlpEventValue(receipt.EventData[key]),
+			})
+		}
+
+		severity := otlpSeverity[receiptSeverity(receipt)]
+
+		records = append(records, otlpLogRecord{
+			TimeUnixNano:   strconv.FormatInt(receipt.Timestamp*1e9, 10),
+			SeverityNumber: severity.number,
+			SeverityText:   severity.text,
+			Body:           otlpString(receipt.EventType),
+			Attributes:     attributes,
+		})
+	}
+
+	request := otlpLogsRequest{
+		ResourceLogs: []otlpResourceLogs{{
+			Resource: otlpResource{Attributes: []otlpAttribute{
+				{Key: "service.name", Value: otlpString("oi-kernel")},
+			}},
+			ScopeLogs: []otlpScopeLogs{{
+				Scope:      otlpScope{Name: "oi.audit"},
+				LogRecords: records,
+			}},
+		}},
+	}
+
+	encoder := json.NewEncoder(w)
+	return encoder.Encode(request)
+}
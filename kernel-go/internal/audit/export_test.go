@@ -0,0 +1,115 @@
+// WHY: These tests prove exports carry every receipt and the hashes needed
+// to correlate exported events back to the chain.
+package audit
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func exportFixture() *Ledger {
+	ledger := NewLedger()
+	ledger.AppendCDIDecision(testActor, "ALLOW", "hash1", "", "decision_1")
+	ledger.AppendTokenMint(testActor, TokenMint{TokenDigest: "token1", Scope: []string{"fs:read:/a"}, DecisionID: "decision_1"})
+	ledger.AppendPostureChange(testActor, 1, 2, "operator=ops|team\\x")
+	return ledger
+}
+
+// TestExportJSONLRoundTrips proves JSONL exports reload into a verifiable chain
+func TestExportJSONLRoundTrips(t *testing.T) {
+	ledger := exportFixture()
+	var buf bytes.Buffer
+	if err := ledger.Export(&buf, FormatJSONL); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	reloaded := []Receipt{}
+	for _, line := range lines {
+		receipt, err := decodeReceipt([]byte(line))
+		if err != nil {
+			t.Fatalf("decode exported line: %v", err)
+		}
+		reloaded = append(reloaded, receipt)
+	}
+	if err := verifyChain(reloaded); err != nil {
+		t.Fatalf("exported chain should verify: %v", err)
+	}
+}
+
+// TestExportCSVHasRowPerReceipt proves CSV keeps hashes and event data
+func TestExportCSVHasRowPerReceipt(t *testing.T) {
+	ledger := exportFixture()
+	var buf bytes.Buffer
+	if err := ledger.Export(&buf, FormatCSV); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+	receipts := ledger.GetReceipts()
+	if len(rows) != len(receipts)+1 {
+		t.Fatalf("expected header plus %d rows, got %d", len(receipts), len(rows))
+	}
+	if rows[3][2] != "token_mint" || rows[3][5] != receipts[2].CurrentHash {
+		t.Fatalf("unexpected csv row: %v", rows[3])
+	}
+}
+
+// TestExportCEFEscapesFields proves CEF delimiters in values cannot forge fields
+func TestExportCEFEscapesFields(t *testing.T) {
+	ledger := exportFixture()
+	var buf bytes.Buffer
+	if err := ledger.Export(&buf, FormatCEF); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(ledger.GetReceipts()) {
+		t.Fatalf("expected one CEF line per receipt, got %d", len(lines))
+	}
+	last := lines[len(lines)-1]
+	if !strings.HasPrefix(last, "CEF:0|OI|oi-kernel|1|posture_change|posture change|6|") {
+		t.Fatalf("unexpected CEF header: %s", last)
+	}
+	if !strings.Contains(last, `operator\=ops|team\\\\x`) {
+		t.Fatalf("extension value not escaped: %s", last)
+	}
+}
+
+// TestExportOTLPDocument proves the OTLP export is one logs request with a record per receipt
+func TestExportOTLPDocument(t *testing.T) {
+	ledger := exportFixture()
+	var buf bytes.Buffer
+	if err := ledger.Export(&buf, FormatOTLP); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	var request otlpLogsRequest
+	if err := json.Unmarshal(buf.Bytes(), &request); err != nil {
+		t.Fatalf("parse otlp: %v", err)
+	}
+	records := request.ResourceLogs[0].ScopeLogs[0].LogRecords
+	if len(records) != len(ledger.GetReceipts()) {
+		t.Fatalf("expected %d log records, got %d", len(ledger.GetReceipts()), len(records))
+	}
+
+	found := false
+	for _, attribute := range records[1].Attributes {
+		if attribute.Key == "oi.event.decision" && *attribute.Value.StringValue == "ALLOW" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("event data should be exported as attributes")
+	}
+
+	if _, err := ParseExportFormat("xml"); err == nil {
+		t.Fatal("unknown formats must be rejected")
+	}
+}
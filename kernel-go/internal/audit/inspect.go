@@ -0,0 +1,123 @@
+// WHY: Verify answers for the whole chain and stops at the first broken
+// receipt, which is what a kernel needs before it extends a record. An
+// auditor reading a damaged record needs more: which receipts still hold,
+// where the chain stops holding, and whether each one carries a signature
+// from a trusted key. CheckReceipts applies the same rules as Verify to
+// every receipt in turn and reports each on its own.
+package audit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+// Signature states of a checked receipt
+const (
+	// SignatureVerified is a signature from a trusted key
+	SignatureVerified = "verified"
+
+	// SignatureInvalid is a signature that does not verify, or is from an
+	// untrusted key
+	SignatureInvalid = "invalid"
+
+	// SignatureMissing is an unsigned receipt after signing began, or past
+	// genesis once a key is given, which is a forgery rather than an
+	// omission
+	SignatureMissing = "missing"
+
+	// SignatureUnchecked is a signature checked against no key
+	SignatureUnchecked = "unchecked"
+
+	// SignatureNone is an unsigned receipt before signing began, checked
+	// against no key
+	SignatureNone = "none"
+)
+
+// ReceiptCheck is what verification found for one receipt
+type ReceiptCheck struct {
+	Sequence int64 `json:"sequence"`
+
+	// Hashed is whether the receipt's hash recomputes and its schema and
+	// hash versions agree
+	Hashed bool `json:"hashed"`
+
+	// Linked is whether the receipt names its predecessor's hash and does
+	// not downgrade its versions
+	Linked bool `json:"linked"`
+
+	// Signature is one of the Signature states
+	Signature string `json:"signature"`
+
+	// Error says why the receipt does not hold, if it does not
+	Error string `json:"error,omitempty"`
+}
+
+// OK reports whether the receipt holds
+func (c ReceiptCheck) OK() bool {
+	return c.Hashed && c.Linked && c.Signature != SignatureInvalid && c.Signature != SignatureMissing
+}
+
+// CheckReceipts checks each receipt's hash, its link to the receipt
+// before it, its hash version against the minimum genesis records, and,
+// against keys if given, its signature. WHY: Signatures
+// are not hashed, so with keys every receipt past genesis must be signed;
+// stripping them all must not pass for a ledger never signed.
+func CheckReceipts(receipts []Receipt, keys *signing.KeyRing) []ReceiptCheck {
+	checks := make([]ReceiptCheck, len(receipts))
+	compactions := compactionsIn(receipts)
+	minimum := minHashVersion(receipts)
+	signed := false
+	for i, receipt := range receipts {
+		check := ReceiptCheck{Sequence: receipt.Sequence, Hashed: true, Linked: true}
+		var problems []string
+
+		if err := checkSchema(receipt); err != nil {
+			check.Hashed = false
+			problems = append(problems, err.Error())
+		} else if expected := computeHash(receipt); expected == "" || receipt.CurrentHash != expected {
+			check.Hashed = false
+			problems = append(problems, "hash mismatch")
+		}
+		if receipt.HashVersion < minimum {
+			check.Hashed = false
+			problems = append(problems, fmt.Sprintf("hash version %d below the minimum %d genesis records", receipt.HashVersion, minimum))
+		}
+
+		if i > 0 {
+			prev := receipts[i-1]
+			switch {
+			case receipt.PrevHash != prev.CurrentHash && !bridgesCompaction(compactions, prev, receipt):
+				check.Linked = false
+				problems = append(problems, fmt.Sprintf("prev_hash does not match receipt %d", prev.Sequence))
+			case receipt.HashVersion < prev.HashVersion:
+				check.Linked = false
+				problems = append(problems, fmt.Sprintf("hash version downgrade from %d to %d", prev.HashVersion, receipt.HashVersion))
+			case schemaOf(receipt) < schemaOf(prev):
+				check.Linked = false
+				problems = append(problems, fmt.Sprintf("schema version downgrade from %d to %d", schemaOf(prev), schemaOf(receipt)))
+			}
+		}
+
+		signed = signed || len(receipt.Signature) > 0
+		switch {
+		case len(receipt.Signature) == 0 && (signed || keys != nil) && receipt.EventType != "genesis":
+			check.Signature = SignatureMissing
+			problems = append(problems, "unsigned after signing began")
+		case len(receipt.Signature) == 0:
+			check.Signature = SignatureNone
+		case keys == nil:
+			check.Signature = SignatureUnchecked
+		case verifyReceiptSignature(receipt, keys) != nil:
+			check.Signature = SignatureInvalid
+			problems = append(problems, "signature does not verify")
+		default:
+			check.Signature = SignatureVerified
+		}
+
+		check.Error = strings.Join(problems, "; ")
+		checks[i] = check
+	}
+	return checks
+}
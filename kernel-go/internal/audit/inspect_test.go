@@ -0,0 +1,94 @@
+// WHY: These tests prove a damaged record is reported receipt by receipt:
+// the receipts that still hold are shown as holding, and each broken one
+// says how it is broken.
+package audit
+
+import (
+	"testing"
+
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+// TestCheckReceiptsLocatesDamage proves an edited receipt fails its hash
+// alone, a forged signature and a stripped one are told apart, and the
+// receipts around them still hold
+func TestCheckReceiptsLocatesDamage(t *testing.T) {
+	ledger := NewLedger()
+	signer, _ := signing.GenerateLocalSigner("kernel_audit_1")
+	ledger.SetSigner(signer)
+	ledger.AppendCDIDecision(testActor, "ALLOW", "h", "", "decision_1")
+	for i := 0; i < 4; i++ {
+		ledger.AppendStopEvent(testActor, i)
+	}
+	keys := signing.NewKeyRing()
+	keys.AddSigner(signer)
+
+	receipts := ledger.GetReceipts()
+	for _, check := range CheckReceipts(receipts, keys) {
+		if !check.OK() || check.Error != "" {
+			t.Fatalf("an untouched ledger must hold throughout, got %+v", check)
+		}
+	}
+	checks := CheckReceipts(receipts, nil)
+	if checks[0].Signature != SignatureNone || checks[2].Signature != SignatureUnchecked || !checks[2].OK() {
+		t.Fatalf("without keys, signatures are unchecked and genesis unsigned: %+v", checks[:3])
+	}
+
+	stripped := ledger.GetReceipts()
+	for i := range stripped {
+		stripped[i].Signature = nil
+	}
+	checks = CheckReceipts(stripped, keys)
+	if checks[0].Signature != SignatureNone || checks[1].Signature != SignatureMissing || checks[1].OK() {
+		t.Fatalf("with a key, every stripped signature must be missing, got %+v", checks[:2])
+	}
+	if checks = CheckReceipts(stripped, nil); !checks[1].OK() {
+		t.Fatalf("without a key, an unsigned ledger holds, got %+v", checks[1])
+	}
+
+	receipts[2].EventData = map[string]interface{}{"tokens_revoked": 99}
+	receipts[3].Signature = append([]byte{}, receipts[4].Signature...)
+	receipts[4].Signature = nil
+	checks = CheckReceipts(receipts, keys)
+	if checks[2].Hashed || !checks[2].Linked || checks[2].OK() {
+		t.Fatalf("an edited receipt must fail its hash, got %+v", checks[2])
+	}
+	if checks[3].Signature != SignatureInvalid || !checks[3].Hashed || !checks[3].Linked {
+		t.Fatalf("a signature moved to another receipt must be invalid, got %+v", checks[3])
+	}
+	if checks[4].Signature != SignatureMissing || checks[4].OK() {
+		t.Fatalf("a stripped signature must be missing, got %+v", checks[4])
+	}
+	if !checks[0].OK() || !checks[1].OK() {
+		t.Fatalf("receipts before the damage must still hold, got %+v", checks[:2])
+	}
+
+	receipts = ledger.GetReceipts()
+	receipts = append(receipts[:2], receipts[3:]...)
+	checks = CheckReceipts(receipts, keys)
+	if checks[2].Linked || !checks[2].Hashed || !checks[3].OK() {
+		t.Fatalf("a dropped receipt must break only the next link, got %+v", checks[2:])
+	}
+}
+
+// TestQueryReceiptsMatchesQuery proves receipts read without a ledger are
+// filtered and paged as the ledger would
+func TestQueryReceiptsMatchesQuery(t *testing.T) {
+	ledger := NewLedger()
+	ledger.AppendCDIDecision(testActor, "ALLOW", "h1", "", "decision_1")
+	ledger.AppendCDIDecision(testActor, "DENY", "h2", "", "decision_2")
+	ledger.AppendCDIDecision(testActor, "DENY", "h3", "", "decision_3")
+
+	filter := ReceiptFilter{Decision: "DENY", Limit: 1}
+	want, _ := ledger.Query(filter)
+	got, err := QueryReceipts(ledger.GetReceipts(), filter)
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(got.Receipts) != 1 || got.Receipts[0].Sequence != want.Receipts[0].Sequence || got.NextSequence != want.NextSequence || !got.HasMore {
+		t.Fatalf("want %+v, got %+v", want, got)
+	}
+	if _, err := QueryReceipts(nil, ReceiptFilter{Limit: MaxQueryLimit + 1}); err == nil {
+		t.Fatal("an out-of-range limit must be refused")
+	}
+}
@@ -0,0 +1,152 @@
+// WHY: These tests prove the producer writes a Produce request a broker can
+// check, record batch CRC included, and reports a broker's refusal.
+package audit
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"net"
+	"testing"
+)
+
+// fakeBroker accepts one connection, decodes each Produce request's record
+// value onto values, and answers with errorCode for partition 0
+func fakeBroker(t *testing.T, errorCode int16, values chan<- []byte) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var size int32
+			if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+				return
+			}
+			request := make([]byte, size)
+			if _, err := io.ReadFull(conn, request); err != nil {
+				return
+			}
+			correlation, topic, value := decodeProduce(t, request)
+			values <- value
+
+			var response bytes.Buffer
+			writeInt32(&response, correlation)
+			writeInt32(&response, 1)
+			writeString(&response, topic)
+			writeInt32(&response, 1)
+			writeInt32(&response, 0)
+			writeInt16(&response, errorCode)
+			writeInt64(&response, 0)
+			writeInt64(&response, -1)
+			writeInt32(&response, 0) // throttle time
+			framed := binary.BigEndian.AppendUint32(nil, uint32(response.Len()))
+			conn.Write(append(framed, response.Bytes()...))
+		}
+	}()
+	return listener.Addr().String()
+}
+
+// decodeProduce checks a Produce v3 request as a broker would and returns
+// its correlation ID, topic, and record value
+func decodeProduce(t *testing.T, request []byte) (int32, string, []byte) {
+	r := bytes.NewReader(request)
+	var header struct {
+		Key, Version int16
+		Correlation  int32
+	}
+	binary.Read(r, binary.BigEndian, &header)
+	if header.Key != kafkaProduceKey || header.Version != kafkaProduceVersion {
+		t.Errorf("unexpected api %d v%d", header.Key, header.Version)
+	}
+	readString(r) // client id
+	var transactional, acks int16
+	var timeout, topics int32
+	binary.Read(r, binary.BigEndian, &transactional)
+	binary.Read(r, binary.BigEndian, &acks)
+	binary.Read(r, binary.BigEndian, &timeout)
+	binary.Read(r, binary.BigEndian, &topics)
+	topic, _ := readString(r)
+	var partitions, partition, size int32
+	binary.Read(r, binary.BigEndian, &partitions)
+	binary.Read(r, binary.BigEndian, &partition)
+	binary.Read(r, binary.BigEndian, &size)
+	if acks != kafkaAcksAll || topics != 1 || partitions != 1 || partition != 0 {
+		t.Errorf("unexpected produce acks=%d topics=%d partitions=%d partition=%d", acks, topics, partitions, partition)
+	}
+
+	batch := make([]byte, size)
+	io.ReadFull(r, batch)
+	if batch[16] != kafkaRecordMagic {
+		t.Errorf("record batch magic %d", batch[16])
+	}
+	if length := int(binary.BigEndian.Uint32(batch[8:12])); length != len(batch)-12 {
+		t.Errorf("batch length %d, want %d", length, len(batch)-12)
+	}
+	if crc := binary.BigEndian.Uint32(batch[17:21]); crc != crc32.Checksum(batch[21:], kafkaCastagnoli) {
+		t.Error("record batch CRC does not match")
+	}
+
+	// The record follows the 61-byte batch header: its length, then
+	// attributes, timestamp and offset deltas, key, and value
+	record := bytes.NewReader(batch[61:])
+	binary.ReadVarint(record)
+	record.ReadByte()
+	binary.ReadVarint(record)
+	binary.ReadVarint(record)
+	keyLength, _ := binary.ReadVarint(record)
+	record.Seek(keyLength, io.SeekCurrent)
+	valueLength, _ := binary.ReadVarint(record)
+	value := make([]byte, valueLength)
+	io.ReadFull(record, value)
+	return header.Correlation, topic, value
+}
+
+// TestKafkaBrokerProducerWritesRecords proves a forwarded receipt reaches a
+// broker as a checksummed record of its JSON
+func TestKafkaBrokerProducerWritesRecords(t *testing.T) {
+	values := make(chan []byte, 4)
+	producer, err := NewKafkaBrokerProducer(fakeBroker(t, 0, values))
+	if err != nil {
+		t.Fatalf("producer: %v", err)
+	}
+	sink, _ := NewKafkaSink(producer, "oi.receipts")
+
+	ledger := NewLedger()
+	ledger.AppendStopEvent(testActor, 1)
+	receipts := ledger.GetReceipts()
+	if err := sink.Deliver(receipts); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	for _, want := range receipts {
+		got, err := decodeReceipt(<-values)
+		if err != nil {
+			t.Fatalf("broker got an undecodable record: %v", err)
+		}
+		if got.CurrentHash != want.CurrentHash {
+			t.Fatalf("broker got receipt %d, want %d", got.Sequence, want.Sequence)
+		}
+	}
+}
+
+// TestKafkaBrokerProducerReportsRefusal proves a broker error code fails
+// the delivery so it is retried
+func TestKafkaBrokerProducerReportsRefusal(t *testing.T) {
+	values := make(chan []byte, 1)
+	producer, _ := NewKafkaBrokerProducer(fakeBroker(t, 6, values))
+	if err := producer.Produce("oi.receipts", []byte("1"), []byte("{}")); err == nil {
+		t.Fatal("a broker that does not lead the partition should fail the produce")
+	}
+	if _, err := NewKafkaBrokerProducer("no-port"); err == nil {
+		t.Fatal("an address without a port should be rejected")
+	}
+}
@@ -0,0 +1,55 @@
+// WHY: Kafka gives receipts durable, replicated, replayable storage off the
+// host. The kernel stays dependency-free: operators plug in their Kafka
+// client behind KafkaProducer.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// KafkaProducer is the subset of a Kafka client the sink needs
+type KafkaProducer interface {
+	// Produce synchronously writes one message and returns once the broker
+	// has acknowledged it (acks=all is recommended)
+	Produce(topic string, key, value []byte) error
+}
+
+// KafkaSink publishes each receipt as a JSON message keyed by sequence
+type KafkaSink struct {
+	producer KafkaProducer
+	topic    string
+}
+
+// NewKafkaSink creates a sink publishing to topic through producer
+func NewKafkaSink(producer KafkaProducer, topic string) (*KafkaSink, error) {
+	if producer == nil {
+		return nil, fmt.Errorf("nil kafka producer")
+	}
+	if topic == "" {
+		return nil, fmt.Errorf("empty kafka topic")
+	}
+	return &KafkaSink{producer: producer, topic: topic}, nil
+}
+
+// Name identifies the sink
+func (k *KafkaSink) Name() string {
+	return "kafka:" + k.topic
+}
+
+// Deliver produces the batch in order, stopping at the first failure.
+// WHY: A single-partition topic then holds the chain in sequence order.
+func (k *KafkaSink) Deliver(receipts []Receipt) error {
+	for _, receipt := range receipts {
+		value, err := json.Marshal(receipt)
+		if err != nil {
+			return fmt.Errorf("encode receipt %d: %w", receipt.Sequence, err)
+		}
+		key := []byte(strconv.FormatInt(receipt.Sequence, 10))
+		if err := k.producer.Produce(k.topic, key, value); err != nil {
+			return fmt.Errorf("kafka produce receipt %d: %w", receipt.Sequence, err)
+		}
+	}
+	return nil
+}
@@ -4,22 +4,39 @@
 package audit
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/user/oi/kernel-go/internal/clock"
+	"github.com/user/oi/kernel-go/internal/signing"
 )
 
 // Receipt represents a single audit log entry in the hash chain.
 // WHY: Mechanics-only logging - no raw user content by default.
 type Receipt struct {
-	Sequence     int64
-	Timestamp    int64
-	EventType    string
-	EventData    map[string]interface{} // structured data, not raw content
-	PrevHash     string
-	CurrentHash  string
+	Sequence     int64                  `json:"sequence"`
+	Timestamp    int64                  `json:"timestamp"`
+	EventType    string                 `json:"event_type"`
+	EventData    map[string]interface{} `json:"event_data"` // structured data, not raw content
+	PrevHash     string                 `json:"prev_hash"`
+	CurrentHash  string                 `json:"current_hash"`
+
+	// HashVersion selects the hash encoding; absent (0) on legacy receipts
+	HashVersion int `json:"hash_version,omitempty"`
+
+	// Severity and category for alerting; hashed from hash version 2 on
+	Severity Severity `json:"severity,omitempty"`
+	Category Category `json:"category,omitempty"`
+
+	// SchemaVersion is the receipt layout version (see schema.go); hashed
+	// from hash version 3 on
+	SchemaVersion int `json:"schema_version,omitempty"`
+
+	// Signature over CurrentHash and the ID of the signing key; empty when
+	// the ledger has no signer
+	Signature   []byte `json:"signature,omitempty"`
+	SignerKeyID string `json:"signer_key_id,omitempty"`
 }
 
 // Ledger is an append-only, hash-chained audit log.
@@ -27,37 +44,233 @@ type Ledger struct {
 	mu       sync.Mutex
 	receipts []Receipt
 	sequence int64
+
+	// store persists receipts; nil keeps the ledger in memory only
+	store Store
+	// persistErr is the first store failure; once set the ledger no longer verifies
+	persistErr error
+
+	// Checkpoint policy and the last sequence covered by a checkpoint (-1 if none)
+	checkpointInterval int
+	checkpointSink     CheckpointSink
+	lastCheckpointEnd  int64
+
+	// Receipt signing: the current signer, every trusted key, and the first
+	// sequence that must carry a signature (-1 if signing never started)
+	signer     signing.Signer
+	keys       *signing.KeyRing
+	signedFrom int64
+
+	// loaded is set when the chain was read from a store. WHY: Signatures
+	// are not hashed, so where signing started cannot be read back from
+	// the chain; loaded history must be signed throughout once a key is
+	// trusted.
+	loaded bool
+
+	// Rotation policy, sealed segments still held in memory, and the
+	// index and first sequence of the open segment
+	rotation     RotationPolicy
+	segments     []Segment
+	segmentIndex int
+	segmentStart int64
+
+	// Live receipt subscribers, keyed by subscription ID
+	subscribers      map[uint64]*Subscription
+	nextSubscriberID uint64
+
+	// pseudonymizer HMACs identifiers before hashing; nil stores them raw
+	pseudonymizer *Pseudonymizer
+
+	// clock stamps receipts; nil reads the system clock
+	clock clock.Clock
 }
 
 // NewLedger creates a new audit ledger with genesis receipt
 func NewLedger() *Ledger {
 	ledger := &Ledger{
-		receipts: []Receipt{},
-		sequence: 0,
+		receipts:          []Receipt{},
+		sequence:          0,
+		lastCheckpointEnd: -1,
+		signedFrom:        -1,
 	}
+	ledger.receipts = append(ledger.receipts, newGenesis())
+
+	return ledger
+}
+
+// OpenLedger loads and verifies the receipt chain held by a store, or
+// initializes the store with a genesis receipt if it is empty.
+// WHY: A restart must resume the existing governance record, and a
+// record that fails verification must never be silently extended.
+func OpenLedger(store Store) (*Ledger, error) {
+	receipts, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load ledger: %w", err)
+	}
+
+	ledger := &Ledger{
+		receipts:          receipts,
+		store:             store,
+		lastCheckpointEnd: -1,
+		signedFrom:        -1,
+	}
+
+	if len(receipts) == 0 {
+		genesis := newGenesis()
+		if err := store.Append(genesis); err != nil {
+			return nil, fmt.Errorf("persist genesis receipt: %w", err)
+		}
+		ledger.receipts = []Receipt{genesis}
+		return ledger, nil
+	}
+
+	if err := verifyChain(receipts); err != nil {
+		return nil, fmt.Errorf("stored ledger failed verification: %w", err)
+	}
+	ledger.sequence = receipts[len(receipts)-1].Sequence
+	if checkpoints := checkpointsIn(receipts); len(checkpoints) > 0 {
+		ledger.lastCheckpointEnd = checkpoints[len(checkpoints)-1].EndSequence
+	}
+	// Loaded segments are already durable in the store
+	for _, sealed := range sealsIn(receipts) {
+		sealed.archived = true
+		ledger.segments = append(ledger.segments, sealed)
+		ledger.segmentIndex = sealed.Index + 1
+		ledger.segmentStart = sealed.EndSequence + 1
+	}
+	// Compacted runs stay in the store but not in memory
+	ledger.receipts = withoutCompacted(receipts)
+	ledger.loaded = true
+	for _, receipt := range receipts {
+		if len(receipt.Signature) > 0 {
+			// Signed history stays signed: its keys must be trusted before Verify
+			ledger.signedFrom = 1
+			break
+		}
+	}
+
+	return ledger, nil
+}
 
-	// Genesis receipt
+// newGenesis builds the first receipt of a chain
+func newGenesis() Receipt {
 	genesis := Receipt{
 		Sequence:    0,
 		Timestamp:   time.Now().Unix(),
 		EventType:   "genesis",
-		EventData:   map[string]interface{}{"message": "audit ledger initialized"},
+		EventData:   map[string]interface{}{"message": "audit ledger initialized", "min_hash_version": CurrentHashVersion},
 		PrevHash:    "0000000000000000",
 		CurrentHash: "",
+		HashVersion: CurrentHashVersion,
+		Severity:    SeverityInfo,
+		Category:    CategoryIntegrity,
+
+		SchemaVersion: CurrentSchemaVersion,
 	}
 	genesis.CurrentHash = computeHash(genesis)
-	ledger.receipts = append(ledger.receipts, genesis)
+	return genesis
+}
 
-	return ledger
+// minHashVersion is the lowest hash version the genesis receipt at the
+// head of receipts allows; a chain without one, or from before genesis
+// recorded it, allows any.
+// WHY: Downgrades are otherwise only caught between neighbours, so a chain
+// rewritten entirely at the legacy hash would verify
+func minHashVersion(receipts []Receipt) int {
+	if len(receipts) == 0 || receipts[0].EventType != "genesis" {
+		return HashVersionLegacy
+	}
+	return int(toInt64(receipts[0].EventData["min_hash_version"]))
+}
+
+// Close releases the backing store, if any
+func (l *Ledger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.store == nil {
+		return nil
+	}
+	return l.store.Close()
 }
 
-// append adds a new receipt to the chain
+// append adds a new receipt to the chain and cuts a checkpoint or rotates
+// the segment when due
 func (l *Ledger) append(eventType string, eventData map[string]interface{}) {
+	l.appendAs(eventType, classify(eventType, eventData), eventData)
+}
+
+// appendAs is append with an explicit severity and category
+func (l *Ledger) appendAs(eventType string, class classification, eventData map[string]interface{}) {
+	l.mu.Lock()
+	l.appendAsLocked(eventType, class, eventData)
+	checkpoint, err := l.maybeCheckpointLocked()
+	if err != nil && l.persistErr == nil {
+		l.persistErr = fmt.Errorf("checkpoint failed: %w", err)
+	}
+
+	var sealed *Segment
+	var sealCheckpoint Checkpoint
+	if l.dueForRotationLocked() {
+		segment, cp, err := l.rotateLocked()
+		if err != nil && l.persistErr == nil {
+			l.persistErr = fmt.Errorf("rotation failed: %w", err)
+		}
+		if err == nil {
+			sealed, sealCheckpoint = &segment, cp
+		}
+	}
+	sink := l.checkpointSink
+	archive := l.rotation.Archive
+	l.mu.Unlock()
+
+	if checkpoint != nil {
+		l.exportCheckpoint(sink, *checkpoint)
+	}
+	if sealed != nil {
+		l.exportCheckpoint(sink, sealCheckpoint)
+		l.archiveSegment(archive, *sealed)
+	}
+}
+
+// SetClock sets the clock receipts are stamped with; nil restores the
+// system clock
+func (l *Ledger) SetClock(c clock.Clock) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
+	l.clock = c
+}
+
+// timestampLocked stamps the next receipt. Callers must hold l.mu.
+// WHY: Receipts are read in sequence order, so a wall clock stepping
+// backwards is held at the previous receipt's time rather than making a
+// later receipt look older.
+func (l *Ledger) timestampLocked() int64 {
+	c := l.clock
+	if c == nil {
+		c = clock.Real{}
+	}
+	now := c.Now().Unix()
+	if len(l.receipts) > 0 && now < l.receipts[len(l.receipts)-1].Timestamp {
+		return l.receipts[len(l.receipts)-1].Timestamp
+	}
+	return now
+}
+
+// appendLocked adds a new receipt to the chain. Callers must hold l.mu.
+func (l *Ledger) appendLocked(eventType string, eventData map[string]interface{}) {
+	l.appendAsLocked(eventType, classify(eventType, eventData), eventData)
+}
+
+// appendAsLocked adds a classified receipt to the chain. Callers must hold l.mu.
+func (l *Ledger) appendAsLocked(eventType string, class classification, eventData map[string]interface{}) {
 	l.sequence++
 
+	if l.pseudonymizer != nil {
+		l.pseudonymizer.apply(eventData)
+	}
+
 	var prevHash string
 	if len(l.receipts) > 0 {
 		prevHash = l.receipts[len(l.receipts)-1].CurrentHash
@@ -66,73 +279,591 @@ func (l *Ledger) append(eventType string, eventData map[string]interface{}) {
 	}
 
 	receipt := Receipt{
-		Sequence:  l.sequence,
-		Timestamp: time.Now().Unix(),
-		EventType: eventType,
-		EventData: eventData,
-		PrevHash:  prevHash,
+		Sequence:    l.sequence,
+		Timestamp:   l.timestampLocked(),
+		EventType:   eventType,
+		EventData:   eventData,
+		PrevHash:    prevHash,
+		HashVersion: CurrentHashVersion,
+		Severity:    class.severity,
+		Category:    class.category,
+
+		SchemaVersion: CurrentSchemaVersion,
 	}
 	receipt.CurrentHash = computeHash(receipt)
+	if receipt.CurrentHash == "" && l.persistErr == nil {
+		// Event data that cannot be canonically encoded cannot be audited
+		l.persistErr = fmt.Errorf("receipt %d event data cannot be hashed", receipt.Sequence)
+	}
+
+	if err := l.signLocked(&receipt); err != nil && l.persistErr == nil {
+		// An unsigned receipt in a signed ledger can never verify
+		l.persistErr = fmt.Errorf("receipt %d not signed: %w", receipt.Sequence, err)
+	}
+
+	if l.store != nil && l.persistErr == nil {
+		if err := l.store.Append(receipt); err != nil {
+			// Keep the receipt in memory but poison verification:
+			// a governance record that cannot be persisted is a missing sink.
+			l.persistErr = fmt.Errorf("receipt %d not persisted: %w", receipt.Sequence, err)
+		}
+	}
 
 	l.receipts = append(l.receipts, receipt)
+	l.publishLocked(receipt)
+}
+
+// Attribution identifies who a receipt is about and which request it
+// belongs to.
+// WHY: Per-tenant audit slices need every receipt attributable to a
+// principal and namespace, and correlated to the request that caused it.
+type Attribution struct {
+	PrincipalID string
+	NamespaceID string
+	RequestID   string
+}
+
+// annotate adds attribution fields to event data
+func (a Attribution) annotate(eventData map[string]interface{}) map[string]interface{} {
+	eventData["principal_id"] = a.PrincipalID
+	eventData["namespace_id"] = a.NamespaceID
+	eventData["request_id"] = a.RequestID
+	return eventData
 }
 
 // AppendCDIDecision logs a CDI decision (ALLOW/DENY/DEGRADE)
-func (l *Ledger) AppendCDIDecision(decision string, inputHash string, outputHash string) {
-	l.append("cdi_decision", map[string]interface{}{
+func (l *Ledger) AppendCDIDecision(actor Attribution, decision string, inputHash string, outputHash string, decisionID string) {
+	l.AppendGovernedDecision(actor, decision, inputHash, outputHash, decisionID, Governance{})
+}
+
+// Governance identifies the governance a decision was made under
+type Governance struct {
+	CapsuleHash   string
+	PolicyVersion string
+}
+
+// AppendGovernedDecision logs a CDI decision with the governance it was
+// made under
+func (l *Ledger) AppendGovernedDecision(actor Attribution, decision string, inputHash string, outputHash string, decisionID string, governance Governance) {
+	eventData := map[string]interface{}{
 		"decision":    decision,
 		"input_hash":  inputHash,
 		"output_hash": outputHash,
-	})
+		"decision_id": decisionID,
+	}
+	if governance.CapsuleHash != "" {
+		eventData["capsule_hash"] = governance.CapsuleHash
+		eventData["policy_version"] = governance.PolicyVersion
+	}
+	l.append("cdi_decision", actor.annotate(eventData))
 }
 
-// AppendTokenMint logs a capability token mint event
-func (l *Ledger) AppendTokenMint(tokenDigest string, scope []string) {
-	l.append("token_mint", map[string]interface{}{
-		"token_digest": tokenDigest,
-		"scope":        scope,
-	})
+// AppendGovernanceCommitted logs the governance capsule committed as the
+// one decisions are held to, and what committed it
+func (l *Ledger) AppendGovernanceCommitted(actor Attribution, governance Governance, source string) {
+	l.append("governance_committed", actor.annotate(map[string]interface{}{
+		"capsule_hash":   governance.CapsuleHash,
+		"policy_version": governance.PolicyVersion,
+		"source":         source,
+	}))
+}
+
+// TokenMint describes a capability token mint for the audit ledger
+type TokenMint struct {
+	TokenDigest  string
+	Scope        []string
+	ParentDigest string
+	RequestHash  string
+	DecisionID   string
+	TemplateID   string
+}
+
+// AppendTokenMint logs a capability token mint event with its provenance
+func (l *Ledger) AppendTokenMint(actor Attribution, mint TokenMint) {
+	l.append("token_mint", actor.annotate(map[string]interface{}{
+		"token_digest":  mint.TokenDigest,
+		"scope":         mint.Scope,
+		"parent_digest": mint.ParentDigest,
+		"request_hash":  mint.RequestHash,
+		"decision_id":   mint.DecisionID,
+		"template_id":   mint.TemplateID,
+	}))
 }
 
 // AppendAdapterAttempt logs an adapter invocation attempt
-func (l *Ledger) AppendAdapterAttempt(adapterName string, accepted bool, tokenDigest string) {
-	l.append("adapter_attempt", map[string]interface{}{
+func (l *Ledger) AppendAdapterAttempt(actor Attribution, adapterName string, accepted bool, tokenDigest string) {
+	l.append("adapter_attempt", actor.annotate(map[string]interface{}{
 		"adapter":      adapterName,
 		"accepted":     accepted,
 		"token_digest": tokenDigest,
-	})
+	}))
+}
+
+// AppendAdapterTimeout logs a failed adapter attempt abandoned at its
+// deadline; it is an adapter_attempt receipt so timeouts count as failures
+func (l *Ledger) AppendAdapterTimeout(actor Attribution, adapterName string, tokenDigest string, timeout time.Duration) {
+	l.append("adapter_attempt", actor.annotate(map[string]interface{}{
+		"adapter":      adapterName,
+		"accepted":     false,
+		"token_digest": tokenDigest,
+		"outcome":      "timeout",
+		"timeout_ms":   timeout.Milliseconds(),
+	}))
+}
+
+// AppendAdapterExchange logs hashes of the bytes an adapter exchanged with
+// an external service.
+// WHY: The ledger commits to what crossed the boundary without holding it.
+func (l *Ledger) AppendAdapterExchange(actor Attribution, adapterName string, tokenDigest string, requestHash string, responseHash string) {
+	l.append("adapter_exchange", actor.annotate(map[string]interface{}{
+		"adapter":       adapterName,
+		"token_digest":  tokenDigest,
+		"request_hash":  requestHash,
+		"response_hash": responseHash,
+	}))
+}
+
+// AppendAdapterWrite logs the content hash of a write an adapter performed
+func (l *Ledger) AppendAdapterWrite(actor Attribution, adapterName string, tokenDigest string, target string, contentHash string) {
+	l.append("adapter_write", actor.annotate(map[string]interface{}{
+		"adapter":      adapterName,
+		"token_digest": tokenDigest,
+		"target":       target,
+		"content_hash": contentHash,
+	}))
+}
+
+// AppendAdapterQuery logs the fingerprint of a query an adapter ran.
+// WHY: The fingerprint shows what was asked of which tables without
+// recording the bound values, which are often the sensitive part.
+func (l *Ledger) AppendAdapterQuery(actor Attribution, adapterName string, tokenDigest string, fingerprintHash string, fingerprint string, tables []string) {
+	l.append("adapter_query", actor.annotate(map[string]interface{}{
+		"adapter":          adapterName,
+		"token_digest":     tokenDigest,
+		"fingerprint_hash": fingerprintHash,
+		"fingerprint":      fingerprint,
+		"tables":           tables,
+	}))
+}
+
+// AppendAdapterThrottle logs a call refused by an adapter's rate limit
+func (l *Ledger) AppendAdapterThrottle(actor Attribution, adapterName string, tokenDigest string, reason string) {
+	l.append("adapter_throttle", actor.annotate(map[string]interface{}{
+		"adapter":      adapterName,
+		"token_digest": tokenDigest,
+		"reason":       reason,
+	}))
+}
+
+// AppendBreakerStateChange logs an adapter circuit breaker state change
+func (l *Ledger) AppendBreakerStateChange(actor Attribution, adapterName string, fromState string, toState string, reason string) {
+	l.append("breaker_state_change", actor.annotate(map[string]interface{}{
+		"adapter":    adapterName,
+		"from_state": fromState,
+		"to_state":   toState,
+		"reason":     reason,
+	}))
+}
+
+// AppendAdapterLifecycle logs an adapter deregistration, replacement, or
+// the completed drain of the instance it retired
+func (l *Ledger) AppendAdapterLifecycle(actor Attribution, adapterName string, action string, inFlight int) {
+	l.append("adapter_lifecycle", actor.annotate(map[string]interface{}{
+		"adapter":   adapterName,
+		"action":    action,
+		"in_flight": inFlight,
+	}))
 }
 
 // AppendMemoryWrite logs a memory partition write
-func (l *Ledger) AppendMemoryWrite(partition string, scope string, contentHash string) {
-	l.append("memory_write", map[string]interface{}{
+func (l *Ledger) AppendMemoryWrite(actor Attribution, partition string, entryID string, contentHash string) {
+	l.append("memory_write", actor.annotate(map[string]interface{}{
 		"partition":    partition,
-		"scope":        scope,
+		"entry_id":     entryID,
+		"content_hash": contentHash,
+	}))
+}
+
+// AppendMemoryRead logs a read of a protected memory partition
+func (l *Ledger) AppendMemoryRead(actor Attribution, partition string, entryID string, contentHash string) {
+	l.append("memory_read", actor.annotate(map[string]interface{}{
+		"partition":    partition,
+		"entry_id":     entryID,
+		"content_hash": contentHash,
+	}))
+}
+
+// AppendMemoryPromotion logs an entry promoted out of quarantine, with
+// who verified it and how
+func (l *Ledger) AppendMemoryPromotion(actor Attribution, partition string, entryID string, contentHash string, verifier string, method string) {
+	l.append("memory_promotion", actor.annotate(map[string]interface{}{
+		"partition":    partition,
+		"entry_id":     entryID,
+		"content_hash": contentHash,
+		"verifier":     verifier,
+		"method":       method,
+	}))
+}
+
+// AppendMemoryQuotaExceeded logs a memory write refused because it would
+// take a principal over its quota in a partition
+func (l *Ledger) AppendMemoryQuotaExceeded(actor Attribution, principal string, partition string, entryID string, reason string) {
+	l.append("memory_quota_exceeded", actor.annotate(map[string]interface{}{
+		"quota_principal": principal,
+		"partition":       partition,
+		"entry_id":        entryID,
+		"reason":          reason,
+	}))
+}
+
+// AppendMemoryCollection logs what one garbage collection removed from a
+// partition: counts, bytes, and a digest over the removed entries, never
+// their IDs
+func (l *Ledger) AppendMemoryCollection(actor Attribution, partition string, removed int, bytes int64, digest string) {
+	l.append("memory_collection", actor.annotate(map[string]interface{}{
+		"partition":      partition,
+		"removed":        removed,
+		"bytes":          bytes,
+		"removed_digest": digest,
+	}))
+}
+
+// AppendQuarantineClosed logs a quarantined entry rejected by a verifier
+// or expired unverified; it can no longer be promoted
+func (l *Ledger) AppendQuarantineClosed(actor Attribution, entryID string, contentHash string, outcome string, verifier string, method string) {
+	l.append("quarantine_closed", actor.annotate(map[string]interface{}{
+		"entry_id":     entryID,
 		"content_hash": contentHash,
-	})
+		"outcome":      outcome,
+		"verifier":     verifier,
+		"method":       method,
+	}))
+}
+
+// AppendMemoryDeletion logs a memory entry deletion.
+// WHY: The hash of what was forgotten stays in the chain, so a deletion
+// can later be matched to the write it undid.
+func (l *Ledger) AppendMemoryDeletion(actor Attribution, partition string, entryID string, contentHash string) {
+	l.append("memory_deletion", actor.annotate(map[string]interface{}{
+		"partition":    partition,
+		"entry_id":     entryID,
+		"content_hash": contentHash,
+	}))
+}
+
+// AppendEgressDecision logs the output decision made before egress
+func (l *Ledger) AppendEgressDecision(actor Attribution, decision string, outputHash string, reason string) {
+	l.append("egress_decision", actor.annotate(map[string]interface{}{
+		"decision":    decision,
+		"output_hash": outputHash,
+		"reason":      reason,
+	}))
+}
+
+// AppendDeclassification logs a request to declassify content with the
+// given hash, approved with the token with tokenDigest, and whether it was
+// accepted
+func (l *Ledger) AppendDeclassification(actor Attribution, contentHash string, reason string, tokenDigest string, accepted bool) {
+	l.append("declassification", actor.annotate(map[string]interface{}{
+		"content_hash": contentHash,
+		"reason":       reason,
+		"token_digest": tokenDigest,
+		"accepted":     accepted,
+	}))
+}
+
+// AppendNamespaceViolation logs a token minted in tokenNamespace used
+// against target for the actor's namespace, and refused
+func (l *Ledger) AppendNamespaceViolation(actor Attribution, target string, tokenNamespace string, tokenDigest string) {
+	l.append("namespace_violation", actor.annotate(map[string]interface{}{
+		"target":          target,
+		"token_namespace": tokenNamespace,
+		"token_digest":    tokenDigest,
+	}))
+}
+
+// AppendAuthentication logs a bearer token checked for a request: the
+// issuer and subject it named, and why it was refused, if it was
+func (l *Ledger) AppendAuthentication(actor Attribution, issuer string, subject string, accepted bool, reason string) {
+	l.append("authentication", actor.annotate(map[string]interface{}{
+		"issuer":   issuer,
+		"subject":  subject,
+		"accepted": accepted,
+		"reason":   reason,
+	}))
+}
+
+// AppendProfileAccess logs an attempt on the profile of principalID for
+// scope, and whether it was accepted
+func (l *Ledger) AppendProfileAccess(actor Attribution, principalID string, scope string, accepted bool) {
+	l.append("profile_access", actor.annotate(map[string]interface{}{
+		"profile":  principalID,
+		"scope":    scope,
+		"accepted": accepted,
+	}))
 }
 
 // AppendIntegrityStateChange logs an integrity state transition
-func (l *Ledger) AppendIntegrityStateChange(newState string) {
-	l.append("integrity_state_change", map[string]interface{}{
+func (l *Ledger) AppendIntegrityStateChange(actor Attribution, newState string) {
+	l.append("integrity_state_change", actor.annotate(map[string]interface{}{
 		"new_state": newState,
-	})
+	}))
 }
 
-// AppendStopEvent logs a STOP/revocation event
-func (l *Ledger) AppendStopEvent(tokensRevoked int) {
-	l.append("stop_event", map[string]interface{}{
+// Scopes of a STOP
+const (
+	StopScopeGlobal    = "global"
+	StopScopePrincipal = "principal"
+	StopScopeToken     = "token"
+)
+
+// Reasons a STOP is invoked for
+const (
+	StopReasonUserPanic        = "user_panic"
+	StopReasonIntegrityFailure = "integrity_failure"
+	StopReasonOperatorAction   = "operator_action"
+	StopReasonHeartbeatLost    = "heartbeat_lost"
+	StopReasonQuiesce          = "quiesce"
+)
+
+// AppendStopEvent logs a global STOP/revocation event
+func (l *Ledger) AppendStopEvent(actor Attribution, tokensRevoked int) {
+	l.AppendScopedStopEvent(actor, StopScopeGlobal, "", "", tokensRevoked)
+}
+
+// AppendScopedStopEvent logs a STOP of the given scope for reason; target
+// names the principal a principal STOP revoked for, or the token a token
+// STOP revoked
+func (l *Ledger) AppendScopedStopEvent(actor Attribution, scope string, target string, reason string, tokensRevoked int) {
+	eventData := map[string]interface{}{
+		"stop_scope":     scope,
 		"tokens_revoked": tokensRevoked,
-	})
+	}
+	if target != "" {
+		eventData["stop_target"] = target
+	}
+	if reason != "" {
+		eventData["stop_reason"] = reason
+	}
+	l.append("stop_event", actor.annotate(eventData))
+}
+
+// AppendStopRequest logs an invocation of STOP through the admin surface:
+// refused with refusal, or carried out revoking tokenDigests and
+// cancelling callsCancelled calls in flight
+func (l *Ledger) AppendStopRequest(actor Attribution, scope string, target string, reason string, refusal string, tokenDigests []string, callsCancelled int) {
+	eventData := map[string]interface{}{
+		"stop_scope": scope,
+		"accepted":   refusal == "",
+	}
+	if target != "" {
+		eventData["stop_target"] = target
+	}
+	if reason != "" {
+		eventData["stop_reason"] = reason
+	}
+	if refusal != "" {
+		eventData["refusal"] = refusal
+	} else {
+		eventData["tokens_revoked"] = len(tokenDigests)
+		eventData["token_digests"] = append([]string{}, tokenDigests...)
+		eventData["calls_cancelled"] = callsCancelled
+	}
+	l.append("stop_request", actor.annotate(eventData))
+}
+
+// AppendDeadmanTrip logs a deadman switch tripping after missed
+// heartbeats; lastFrom sent the last one, at lastBeat
+func (l *Ledger) AppendDeadmanTrip(actor Attribution, lastFrom string, lastBeat int64, missed int) {
+	l.append("deadman_trip", actor.annotate(map[string]interface{}{
+		"last_heartbeat_from": lastFrom,
+		"last_heartbeat":      lastBeat,
+		"missed_heartbeats":   missed,
+	}))
+}
+
+// AppendStopLatency logs how long the STOP of scope on target took to
+// settle: revocation is when its tokens were revoked, latency when its
+// last cancelled call returned, and outstanding counts the calls that had
+// not returned when measuring gave up
+func (l *Ledger) AppendStopLatency(actor Attribution, scope string, target string, reason string, revocation time.Duration, latency time.Duration, slo time.Duration, cancelled int, outstanding int) {
+	eventData := map[string]interface{}{
+		"stop_scope":        scope,
+		"stop_reason":       reason,
+		"revocation_us":     revocation.Microseconds(),
+		"latency_us":        latency.Microseconds(),
+		"slo_us":            slo.Microseconds(),
+		"within_slo":        outstanding == 0 && latency <= slo,
+		"calls_cancelled":   cancelled,
+		"calls_outstanding": outstanding,
+	}
+	if target != "" {
+		eventData["stop_target"] = target
+	}
+	l.append("stop_latency", actor.annotate(eventData))
+}
+
+// AppendHostStop logs a host trigger, a signal or a trigger file, pulling
+// STOP
+func (l *Ledger) AppendHostStop(actor Attribution, trigger string) {
+	l.append("host_stop", actor.annotate(map[string]interface{}{
+		"trigger": trigger,
+	}))
+}
+
+// AppendAdminAction logs an action on the admin surface by an admin
+// holding role: refused with refusal, or carried out on target
+func (l *Ledger) AppendAdminAction(actor Attribution, action string, role string, target string, refusal string, detail string) {
+	eventData := map[string]interface{}{
+		"admin_action": action,
+		"accepted":     refusal == "",
+	}
+	if role != "" {
+		eventData["admin_role"] = role
+	}
+	if target != "" {
+		eventData["target"] = target
+	}
+	if refusal != "" {
+		eventData["refusal"] = refusal
+	}
+	if detail != "" {
+		eventData["detail"] = detail
+	}
+	l.append("admin_action", actor.annotate(eventData))
+}
+
+// AppendQuiesce logs a quiesce: whether every corridor drained before the
+// deadline, how many were still in flight if not, and the tokens then
+// revoked
+func (l *Ledger) AppendQuiesce(actor Attribution, drained bool, inFlight int, revoked int) {
+	l.append("quiesce", actor.annotate(map[string]interface{}{
+		"drained":        drained,
+		"in_flight":      inFlight,
+		"tokens_revoked": revoked,
+	}))
+}
+
+// AppendRevocationNotice logs the outcome of pushing a revocation notice
+// to endpoint: delivered on attempt attempts, or abandoned after them
+// with lastError
+func (l *Ledger) AppendRevocationNotice(actor Attribution, endpoint string, noticeID string, scope string, tokens int, attempts int, lastError string) {
+	eventData := map[string]interface{}{
+		"endpoint":  endpoint,
+		"notice_id": noticeID,
+		"scope":     scope,
+		"tokens":    tokens,
+		"attempts":  attempts,
+		"delivered": lastError == "",
+	}
+	if lastError != "" {
+		eventData["last_error"] = lastError
+	}
+	l.append("revocation_notice", actor.annotate(eventData))
+}
+
+// AppendStopResume logs a Resume of the STOP of scope on target: refused
+// with refusal, or carried out under the governance committed, with the
+// scopes consented to afresh when it withdrew every standing consent.
+// WHY: The justification is hashed, so the ledger shows it was given
+// without holding its text.
+func (l *Ledger) AppendStopResume(actor Attribution, scope string, target string, justificationHash string, refusal string, governance Governance, reconsented []string) {
+	eventData := map[string]interface{}{
+		"stop_scope":         scope,
+		"justification_hash": justificationHash,
+		"accepted":           refusal == "",
+	}
+	if target != "" {
+		eventData["stop_target"] = target
+	}
+	if refusal != "" {
+		eventData["refusal"] = refusal
+	} else {
+		eventData["capsule_hash"] = governance.CapsuleHash
+		eventData["policy_version"] = governance.PolicyVersion
+	}
+	if reconsented != nil {
+		eventData["reconsented"] = append([]string{}, reconsented...)
+	}
+	l.append("stop_resume", actor.annotate(eventData))
 }
 
 // AppendPostureChange logs a posture level change
-func (l *Ledger) AppendPostureChange(fromLevel int, toLevel int, reason string) {
-	l.append("posture_change", map[string]interface{}{
+func (l *Ledger) AppendPostureChange(actor Attribution, fromLevel int, toLevel int, reason string) {
+	l.append("posture_change", actor.annotate(map[string]interface{}{
 		"from_level": fromLevel,
 		"to_level":   toLevel,
 		"reason":     reason,
-	})
+	}))
+}
+
+// AppendPostureDowngrade logs a posture level lowered by a governed
+// downgrade, with who asked for it and who approved it
+func (l *Ledger) AppendPostureDowngrade(actor Attribution, fromLevel int, toLevel int, reason string, requestedBy string, approvedBy string) {
+	l.append("posture_change", actor.annotate(map[string]interface{}{
+		"from_level":   fromLevel,
+		"to_level":     toLevel,
+		"reason":       reason,
+		"requested_by": requestedBy,
+		"approved_by":  approvedBy,
+	}))
+}
+
+// AppendSessionOpened logs a session opened for the actor's principal
+func (l *Ledger) AppendSessionOpened(actor Attribution, sessionID string) {
+	l.append("session_opened", actor.annotate(map[string]interface{}{
+		"session_id": sessionID,
+	}))
+}
+
+// AppendSessionClosed logs a session closed, why, and how many tokens
+// closing it revoked
+func (l *Ledger) AppendSessionClosed(actor Attribution, sessionID string, reason string, tokensRevoked int) {
+	l.append("session_closed", actor.annotate(map[string]interface{}{
+		"session_id":     sessionID,
+		"reason":         reason,
+		"tokens_revoked": tokensRevoked,
+	}))
+}
+
+// AppendConsentGrant logs a consent to scope granted until expiresAt (0
+// for until revoked), on evidence with the given hash
+func (l *Ledger) AppendConsentGrant(actor Attribution, scope string, expiresAt int64, evidenceHash string) {
+	l.append("consent_change", actor.annotate(map[string]interface{}{
+		"scope":         scope,
+		"granted":       true,
+		"expires_at":    expiresAt,
+		"evidence_hash": evidenceHash,
+	}))
+}
+
+// AppendConsentRevoke logs a consent to scope revoked
+func (l *Ledger) AppendConsentRevoke(actor Attribution, scope string) {
+	l.append("consent_change", actor.annotate(map[string]interface{}{
+		"scope":   scope,
+		"granted": false,
+	}))
+}
+
+// AppendWorldUpdate logs a world pack update: the hash of the world pack
+// it left, and the providers that updated and failed
+func (l *Ledger) AppendWorldUpdate(actor Attribution, worldHash string, updated []string, failed []string) {
+	l.append("world_update", actor.annotate(map[string]interface{}{
+		"world_hash": worldHash,
+		"updated":    updated,
+		"failed":     failed,
+	}))
+}
+
+// AppendStateRestored logs the kernel's state restored from the snapshot
+// with digest, taken at createdAt, less the consents to the scopes dropped
+// for want of their grant receipts
+func (l *Ledger) AppendStateRestored(actor Attribution, digest string, createdAt int64, dropped []string) {
+	l.append("state_restored", actor.annotate(map[string]interface{}{
+		"snapshot_digest":  digest,
+		"snapshot_time":    createdAt,
+		"consents_dropped": dropped,
+	}))
 }
 
 // Verify checks the integrity of the entire receipt chain.
@@ -141,27 +872,84 @@ func (l *Ledger) Verify() (bool, error) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	if len(l.receipts) == 0 {
-		return false, fmt.Errorf("empty ledger")
+	if l.persistErr != nil {
+		return false, fmt.Errorf("ledger persistence failed: %w", l.persistErr)
+	}
+
+	if err := verifyChain(l.receipts); err != nil {
+		return false, err
+	}
+
+	if err := l.verifySignaturesLocked(); err != nil {
+		return false, err
+	}
+
+	for _, checkpoint := range checkpointsIn(l.receipts) {
+		if err := l.verifyCheckpointLocked(checkpoint); err != nil {
+			return false, err
+		}
+	}
+
+	for _, sealed := range sealsIn(l.receipts) {
+		if err := l.verifyCheckpointLocked(sealed.Seal); err != nil {
+			return false, fmt.Errorf("segment %d seal: %w", sealed.Index, err)
+		}
+	}
+
+	return true, nil
+}
+
+// verifyChain checks hashes and linkage for a sequence of receipts
+func verifyChain(receipts []Receipt) error {
+	if len(receipts) == 0 {
+		return fmt.Errorf("empty ledger")
 	}
 
-	for i, receipt := range l.receipts {
+	compactions := compactionsIn(receipts)
+	minimum := minHashVersion(receipts)
+
+	for i, receipt := range receipts {
 		// Verify hash
 		expectedHash := computeHash(receipt)
+		if expectedHash == "" {
+			return fmt.Errorf("receipt %d cannot be hashed (hash version %d)", i, receipt.HashVersion)
+		}
+		if receipt.HashVersion < minimum {
+			return fmt.Errorf("receipt %d hash version %d is below the minimum %d genesis records", i, receipt.HashVersion, minimum)
+		}
+		if err := checkSchema(receipt); err != nil {
+			return fmt.Errorf("receipt %d: %w", i, err)
+		}
 		if receipt.CurrentHash != expectedHash {
-			return false, fmt.Errorf("receipt %d hash mismatch: expected %s, got %s", i, expectedHash, receipt.CurrentHash)
+			return fmt.Errorf("receipt %d hash mismatch: expected %s, got %s", i, expectedHash, receipt.CurrentHash)
 		}
 
 		// Verify chain linkage (except genesis)
 		if i > 0 {
-			prevReceipt := l.receipts[i-1]
-			if receipt.PrevHash != prevReceipt.CurrentHash {
-				return false, fmt.Errorf("receipt %d chain break: prev_hash %s != previous current_hash %s", i, receipt.PrevHash, prevReceipt.CurrentHash)
+			prevReceipt := receipts[i-1]
+			if receipt.PrevHash != prevReceipt.CurrentHash && !bridgesCompaction(compactions, prevReceipt, receipt) {
+				return fmt.Errorf("receipt %d chain break: prev_hash %s != previous current_hash %s", i, receipt.PrevHash, prevReceipt.CurrentHash)
+			}
+			// Hash versions only move forward; a legacy receipt after a
+			// canonical one is a downgrade, not history
+			if receipt.HashVersion < prevReceipt.HashVersion {
+				return fmt.Errorf("receipt %d hash version downgrade from %d to %d", i, prevReceipt.HashVersion, receipt.HashVersion)
+			}
+			if schemaOf(receipt) < schemaOf(prevReceipt) {
+				return fmt.Errorf("receipt %d schema version downgrade from %d to %d", i, schemaOf(prevReceipt), schemaOf(receipt))
 			}
 		}
 	}
 
-	return true, nil
+	return nil
+}
+
+// HeadSequence returns the sequence of the last receipt appended
+func (l *Ledger) HeadSequence() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.sequence
 }
 
 // GetReceipts returns a copy of all receipts (read-only)
@@ -175,10 +963,57 @@ func (l *Ledger) GetReceipts() []Receipt {
 	return receipts
 }
 
-// computeHash generates a cryptographic hash for a receipt
-func computeHash(r Receipt) string {
-	h := sha256.New()
-	h.Write([]byte(fmt.Sprintf("%d|%d|%s|%v|%s",
-		r.Sequence, r.Timestamp, r.EventType, r.EventData, r.PrevHash)))
-	return hex.EncodeToString(h.Sum(nil))
+// Lineage traces a token back to the CDI decision that authorized it.
+// It returns the token's token_mint receipt, the mint receipts of every
+// ancestor token, and finally the cdi_decision receipt, in that order.
+// WHY: Any side effect's token digest must lead back to the user request
+// hash and decision that allowed it; a broken trail is an error.
+func (l *Ledger) Lineage(tokenDigest string) ([]Receipt, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lineage := []Receipt{}
+	visited := map[string]bool{}
+	digest := tokenDigest
+	decisionID := ""
+
+	for digest != "" {
+		if visited[digest] {
+			return nil, fmt.Errorf("lineage cycle at token %s", digest)
+		}
+		visited[digest] = true
+
+		mint, found := l.findReceipt("token_mint", "token_digest", digest)
+		if !found {
+			return nil, fmt.Errorf("no token_mint receipt for token %s", digest)
+		}
+		lineage = append(lineage, mint)
+
+		decisionID, _ = mint.EventData["decision_id"].(string)
+		digest, _ = mint.EventData["parent_digest"].(string)
+	}
+
+	if decisionID == "" {
+		return nil, fmt.Errorf("token %s has no authorizing decision", tokenDigest)
+	}
+	decision, found := l.findReceipt("cdi_decision", "decision_id", decisionID)
+	if !found {
+		return nil, fmt.Errorf("no cdi_decision receipt for decision %s", decisionID)
+	}
+
+	return append(lineage, decision), nil
+}
+
+// findReceipt returns the first receipt of a type whose field equals value.
+// Callers must hold l.mu.
+func (l *Ledger) findReceipt(eventType string, field string, value string) (Receipt, bool) {
+	for _, receipt := range l.receipts {
+		if receipt.EventType != eventType {
+			continue
+		}
+		if v, ok := receipt.EventData[field].(string); ok && v == value {
+			return receipt, true
+		}
+	}
+	return Receipt{}, false
 }
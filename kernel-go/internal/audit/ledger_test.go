@@ -3,17 +3,31 @@
 package audit
 
 import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/clock"
+	"github.com/user/oi/kernel-go/internal/signing"
 )
 
+// testActor attributes receipts appended by tests
+var testActor = Attribution{PrincipalID: "principal_1", NamespaceID: "namespace_1", RequestID: "request_1"}
+
 // TestReceiptChainDetectsModification proves AU-2: tamper detection
 func TestReceiptChainDetectsModification(t *testing.T) {
 	ledger := NewLedger()
 
 	// Add some receipts
-	ledger.AppendCDIDecision("ALLOW", "input_hash_1", "output_hash_1")
-	ledger.AppendTokenMint("token_digest_1", []string{"scope1", "scope2"})
-	ledger.AppendAdapterAttempt("test_adapter", true, "token_digest_1")
+	ledger.AppendCDIDecision(testActor, "ALLOW", "input_hash_1", "output_hash_1", "decision_1")
+	ledger.AppendTokenMint(testActor, TokenMint{TokenDigest: "token_digest_1", Scope: []string{"scope1", "scope2"}, RequestHash: "input_hash_1", DecisionID: "decision_1"})
+	ledger.AppendAdapterAttempt(testActor, "test_adapter", true, "token_digest_1")
 
 	// Verify initial chain
 	valid, err := ledger.Verify()
@@ -50,7 +64,7 @@ func TestAuditReceiptsContainNoRawUserContentByDefault(t *testing.T) {
 	ledger := NewLedger()
 
 	// Log a CDI decision with only hashes
-	ledger.AppendCDIDecision("ALLOW", "hash_of_input", "hash_of_output")
+	ledger.AppendCDIDecision(testActor, "ALLOW", "hash_of_input", "hash_of_output", "decision_1")
 
 	receipts := ledger.GetReceipts()
 	if len(receipts) < 2 {
@@ -86,9 +100,9 @@ func TestReceiptChainLinkage(t *testing.T) {
 	ledger := NewLedger()
 
 	// Add multiple receipts
-	ledger.AppendCDIDecision("ALLOW", "hash1", "hash2")
-	ledger.AppendTokenMint("token1", []string{"scope"})
-	ledger.AppendAdapterAttempt("adapter1", true, "token1")
+	ledger.AppendCDIDecision(testActor, "ALLOW", "hash1", "hash2", "decision_1")
+	ledger.AppendTokenMint(testActor, TokenMint{TokenDigest: "token1", Scope: []string{"scope"}, RequestHash: "hash1", DecisionID: "decision_1"})
+	ledger.AppendAdapterAttempt(testActor, "adapter1", true, "token1")
 
 	receipts := ledger.GetReceipts()
 	if len(receipts) < 4 {
@@ -114,8 +128,8 @@ func TestAppendOnlyLedger(t *testing.T) {
 	initialCount := len(ledger.GetReceipts())
 
 	// Add receipts
-	ledger.AppendCDIDecision("ALLOW", "hash1", "hash2")
-	ledger.AppendTokenMint("token1", []string{"scope"})
+	ledger.AppendCDIDecision(testActor, "ALLOW", "hash1", "hash2", "decision_1")
+	ledger.AppendTokenMint(testActor, TokenMint{TokenDigest: "token1", Scope: []string{"scope"}, RequestHash: "hash1", DecisionID: "decision_1"})
 
 	newCount := len(ledger.GetReceipts())
 	if newCount != initialCount+2 {
@@ -132,11 +146,36 @@ func TestAppendOnlyLedger(t *testing.T) {
 	}
 }
 
+// TestReceiptsAreStampedInOrder proves receipts take the ledger clock's
+// time and never precede the receipt before them
+func TestReceiptsAreStampedInOrder(t *testing.T) {
+	ledger := NewLedger()
+	start := time.Now().Add(time.Hour)
+	now := clock.NewFake(start)
+	ledger.SetClock(now)
+
+	ledger.AppendStopEvent(testActor, 1)
+	now.Set(start.Add(-2 * time.Hour))
+	ledger.AppendStopEvent(testActor, 2)
+
+	receipts := ledger.GetReceipts()
+	first, second := receipts[len(receipts)-2], receipts[len(receipts)-1]
+	if first.Timestamp != start.Unix() {
+		t.Fatalf("expected the clock's time %d, got %d", start.Unix(), first.Timestamp)
+	}
+	if second.Timestamp != first.Timestamp {
+		t.Fatalf("a clock stepping backwards must be held at %d, got %d", first.Timestamp, second.Timestamp)
+	}
+	if _, err := ledger.Verify(); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+}
+
 // TestStopEventLogging proves STOP events are audited
 func TestStopEventLogging(t *testing.T) {
 	ledger := NewLedger()
 
-	ledger.AppendStopEvent(5) // 5 tokens revoked
+	ledger.AppendStopEvent(testActor, 5) // 5 tokens revoked
 
 	receipts := ledger.GetReceipts()
 	found := false
@@ -158,9 +197,9 @@ func TestStopEventLogging(t *testing.T) {
 func TestSequentialOrdering(t *testing.T) {
 	ledger := NewLedger()
 
-	ledger.AppendCDIDecision("ALLOW", "hash1", "hash2")
-	ledger.AppendTokenMint("token1", []string{"scope"})
-	ledger.AppendAdapterAttempt("adapter1", true, "token1")
+	ledger.AppendCDIDecision(testActor, "ALLOW", "hash1", "hash2", "decision_1")
+	ledger.AppendTokenMint(testActor, TokenMint{TokenDigest: "token1", Scope: []string{"scope"}, RequestHash: "hash1", DecisionID: "decision_1"})
+	ledger.AppendAdapterAttempt(testActor, "adapter1", true, "token1")
 
 	receipts := ledger.GetReceipts()
 
@@ -172,3 +211,429 @@ func TestSequentialOrdering(t *testing.T) {
 		}
 	}
 }
+
+// TestLineageTracesDerivedTokenToDecision proves every token leads back to a decision
+func TestLineageTracesDerivedTokenToDecision(t *testing.T) {
+	ledger := NewLedger()
+
+	ledger.AppendCDIDecision(testActor, "ALLOW", "input_hash", "", "decision_1")
+	ledger.AppendTokenMint(testActor, TokenMint{TokenDigest: "root", Scope: []string{"fs:*"}, RequestHash: "input_hash", DecisionID: "decision_1"})
+	ledger.AppendTokenMint(testActor, TokenMint{TokenDigest: "child", Scope: []string{"fs:read"}, ParentDigest: "root", RequestHash: "input_hash", DecisionID: "decision_1"})
+	ledger.AppendAdapterAttempt(testActor, "fs", true, "child")
+
+	lineage, err := ledger.Lineage("child")
+	if err != nil {
+		t.Fatalf("lineage failed: %v", err)
+	}
+	if len(lineage) != 3 {
+		t.Fatalf("expected 3 lineage receipts, got %d", len(lineage))
+	}
+	if lineage[0].EventData["token_digest"] != "child" || lineage[1].EventData["token_digest"] != "root" {
+		t.Fatal("lineage should walk from the token up through its parents")
+	}
+	if lineage[2].EventType != "cdi_decision" || lineage[2].EventData["input_hash"] != "input_hash" {
+		t.Fatal("lineage should end at the authorizing cdi_decision")
+	}
+}
+
+// TestLineageFailsWithoutDecision proves an orphaned token is reported
+func TestLineageFailsWithoutDecision(t *testing.T) {
+	ledger := NewLedger()
+
+	ledger.AppendTokenMint(testActor, TokenMint{TokenDigest: "orphan", Scope: []string{"*"}, RequestHash: "input_hash", DecisionID: "missing_decision"})
+
+	if _, err := ledger.Lineage("orphan"); err == nil {
+		t.Fatal("expected error for token without decision receipt")
+	}
+	if _, err := ledger.Lineage("unknown"); err == nil {
+		t.Fatal("expected error for unknown token")
+	}
+}
+
+// TestFileLedgerSurvivesRestart proves receipts persist and reload verifiably
+func TestFileLedgerSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	ledger, err := OpenLedger(store)
+	if err != nil {
+		t.Fatalf("open ledger: %v", err)
+	}
+
+	ledger.AppendCDIDecision(testActor, "ALLOW", "hash1", "hash2", "decision_1")
+	ledger.AppendTokenMint(testActor, TokenMint{TokenDigest: "token1", Scope: []string{"a", "b"}, RequestHash: "hash1", DecisionID: "decision_1"})
+	ledger.AppendAdapterAttempt(testActor, "adapter1", true, "token1")
+	ledger.AppendStopEvent(testActor, 1234567)
+	if err := ledger.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	// Simulate restart
+	store, err = OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("reopen store: %v", err)
+	}
+	reopened, err := OpenLedger(store)
+	if err != nil {
+		t.Fatalf("reopen ledger: %v", err)
+	}
+	defer reopened.Close()
+
+	if len(reopened.GetReceipts()) != 5 {
+		t.Fatalf("expected 5 receipts after reload, got %d", len(reopened.GetReceipts()))
+	}
+	if valid, err := reopened.Verify(); !valid {
+		t.Fatalf("reloaded chain should verify: %v", err)
+	}
+
+	// Appends continue the same chain
+	reopened.AppendStopEvent(testActor, 0)
+	receipts := reopened.GetReceipts()
+	last := receipts[len(receipts)-1]
+	if last.Sequence != 5 || last.PrevHash != receipts[len(receipts)-2].CurrentHash {
+		t.Fatal("appends after reload should extend the existing chain")
+	}
+	if valid, err := reopened.Verify(); !valid {
+		t.Fatalf("extended chain should verify: %v", err)
+	}
+}
+
+// TestFileLedgerRefusesTamperedFile proves on-disk tampering is detected at startup
+func TestFileLedgerRefusesTamperedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+
+	store, _ := OpenFileStore(path)
+	ledger, err := OpenLedger(store)
+	if err != nil {
+		t.Fatalf("open ledger: %v", err)
+	}
+	ledger.AppendCDIDecision(testActor, "DENY", "hash1", "", "decision_1")
+	ledger.Close()
+
+	data, _ := os.ReadFile(path)
+	tampered := strings.Replace(string(data), `"DENY"`, `"ALLOW"`, 1)
+	os.WriteFile(path, []byte(tampered), 0o600)
+
+	store, _ = OpenFileStore(path)
+	if _, err := OpenLedger(store); err == nil {
+		t.Fatal("tampered ledger file must fail verification on open")
+	}
+	store.Close()
+
+	// A truncated final line is damage, not an empty tail
+	os.WriteFile(path, data[:len(data)-10], 0o600)
+	store, _ = OpenFileStore(path)
+	if _, err := OpenLedger(store); err == nil {
+		t.Fatal("truncated ledger file must be refused")
+	}
+	store.Close()
+}
+
+// failingStore accepts genesis and then refuses every write
+type failingStore struct {
+	receipts []Receipt
+}
+
+func (f *failingStore) Append(receipt Receipt) error {
+	if len(f.receipts) > 0 {
+		return errors.New("disk full")
+	}
+	f.receipts = append(f.receipts, receipt)
+	return nil
+}
+
+func (f *failingStore) Load() ([]Receipt, error) { return f.receipts, nil }
+func (f *failingStore) Close() error             { return nil }
+
+// TestPersistenceFailurePoisonsVerification proves a lost sink is never silent
+func TestPersistenceFailurePoisonsVerification(t *testing.T) {
+	ledger, err := OpenLedger(&failingStore{})
+	if err != nil {
+		t.Fatalf("open ledger: %v", err)
+	}
+
+	ledger.AppendCDIDecision(testActor, "ALLOW", "hash1", "", "decision_1")
+
+	valid, err := ledger.Verify()
+	if valid || err == nil {
+		t.Fatal("ledger with unpersisted receipts must fail verification")
+	}
+}
+
+// rehashChain recomputes every hash after tampering, as an attacker with
+// full write access to the chain would.
+func rehashChain(ledger *Ledger) {
+	for i := range ledger.receipts {
+		if i > 0 {
+			ledger.receipts[i].PrevHash = ledger.receipts[i-1].CurrentHash
+		}
+		ledger.receipts[i].CurrentHash = computeHash(ledger.receipts[i])
+	}
+}
+
+// TestCheckpointsDetectRewrittenHistory proves Merkle checkpoints catch
+// tampering even when the attacker recomputes the hash chain
+func TestCheckpointsDetectRewrittenHistory(t *testing.T) {
+	ledger := NewLedger()
+	exported := []Checkpoint{}
+	ledger.SetCheckpointPolicy(4, func(c Checkpoint) error {
+		exported = append(exported, c)
+		return nil
+	})
+
+	for i := 0; i < 8; i++ {
+		ledger.AppendCDIDecision(testActor, "DENY", "hash", "", "decision")
+	}
+
+	if len(exported) != 2 || len(ledger.Checkpoints()) != 2 {
+		t.Fatalf("expected 2 checkpoints, got %d exported, %d in chain", len(exported), len(ledger.Checkpoints()))
+	}
+	if exported[0].StartSequence != 0 || exported[1].StartSequence != exported[0].EndSequence+1 {
+		t.Fatal("checkpoints should cover contiguous ranges")
+	}
+	if valid, err := ledger.Verify(); !valid {
+		t.Fatalf("untampered ledger should verify: %v", err)
+	}
+	if err := ledger.VerifyCheckpoints(exported); err != nil {
+		t.Fatalf("exported checkpoints should verify: %v", err)
+	}
+
+	// Rewrite an old decision and recompute the whole hash chain
+	ledger.receipts[2].EventData["decision"] = "ALLOW"
+	rehashChain(ledger)
+	if valid, _ := ledger.Verify(); valid {
+		t.Fatal("checkpoint root should expose rewritten history")
+	}
+
+	// Also forge every in-chain checkpoint root; the exported copies still catch it
+	for i := range ledger.receipts {
+		if ledger.receipts[i].EventType != "checkpoint" {
+			continue
+		}
+		cp := checkpointsIn(ledger.receipts[i : i+1])[0]
+		root, _ := ReceiptsMerkleRoot(ledger.receiptRangeLocked(cp.StartSequence, cp.EndSequence))
+		ledger.receipts[i].EventData["merkle_root"] = root
+		rehashChain(ledger)
+	}
+	if valid, err := ledger.Verify(); !valid {
+		t.Fatalf("fully forged chain is internally consistent: %v", err)
+	}
+	if err := ledger.VerifyCheckpoints(exported); err == nil {
+		t.Fatal("exported checkpoints must expose a fully forged chain")
+	}
+}
+
+// TestCheckpointsMustBeRecordedInChain proves a checkpoint held outside
+// the chain fails once the chain no longer records it, even if its root
+// still matches the receipts
+func TestCheckpointsMustBeRecordedInChain(t *testing.T) {
+	ledger := NewLedger()
+	for i := 0; i < 8; i++ {
+		ledger.AppendCDIDecision(testActor, "DENY", "hash", "", "decision")
+	}
+	root, err := ReceiptsMerkleRoot(ledger.receiptRangeLocked(1, 4))
+	if err != nil {
+		t.Fatalf("root: %v", err)
+	}
+	exported := []Checkpoint{{StartSequence: 1, EndSequence: 4, MerkleRoot: root}}
+	if err := ledger.VerifyCheckpoints(exported); err == nil || !strings.Contains(err.Error(), "not recorded") {
+		t.Fatalf("a checkpoint the chain does not record must fail, got %v", err)
+	}
+}
+
+// rehashLegacy rewrites every receipt at the legacy hash, as an attacker
+// downgrading the whole chain would
+func rehashLegacy(ledger *Ledger) {
+	for i := range ledger.receipts {
+		ledger.receipts[i].HashVersion = HashVersionLegacy
+		ledger.receipts[i].SchemaVersion = 0
+	}
+	rehashChain(ledger)
+}
+
+// TestChainRewrittenAtLegacyHashFails proves a chain downgraded whole to
+// the legacy hash fails on the minimum its genesis records, and, with that
+// record stripped, on the hash version its exported checkpoints record
+// even after their ranges have rotated away
+func TestChainRewrittenAtLegacyHashFails(t *testing.T) {
+	ledger := NewLedger()
+	exported := []Checkpoint{}
+	ledger.SetCheckpointPolicy(4, func(c Checkpoint) error {
+		exported = append(exported, c)
+		return nil
+	})
+	for i := 0; i < 8; i++ {
+		ledger.AppendCDIDecision(testActor, "DENY", "hash", "", "decision")
+	}
+	if exported[0].HashVersion != CurrentHashVersion {
+		t.Fatalf("checkpoints must record the hash version they cover, got %d", exported[0].HashVersion)
+	}
+
+	rehashLegacy(ledger)
+	if valid, err := ledger.Verify(); valid || !strings.Contains(err.Error(), "minimum") {
+		t.Fatalf("a chain below the minimum genesis records must fail, got %v", err)
+	}
+	if checks := CheckReceipts(ledger.receipts, nil); checks[1].Hashed {
+		t.Fatal("inspection must flag receipts below the minimum genesis records")
+	}
+
+	delete(ledger.receipts[0].EventData, "min_hash_version")
+	rehashChain(ledger)
+	ledger.receipts = ledger.receipts[exported[0].EndSequence+1:]
+	if err := ledger.VerifyCheckpoints(exported[:1]); err == nil || !strings.Contains(err.Error(), "hash version") {
+		t.Fatalf("a rotated checkpoint must still hold the chain to its hash version, got %v", err)
+	}
+}
+
+// TestMerkleRootKnownAnswer pins the RFC 6962 tree construction
+func TestMerkleRootKnownAnswer(t *testing.T) {
+	leaf := func(b byte) []byte { return merkleLeaf([]byte{b}) }
+
+	three := merkleRoot([][]byte{leaf(1), leaf(2), leaf(3)})
+	expected := merkleNode(merkleNode(leaf(1), leaf(2)), leaf(3))
+	if hex.EncodeToString(three) != hex.EncodeToString(expected) {
+		t.Fatal("three-leaf tree should split 2+1")
+	}
+
+	if hex.EncodeToString(merkleRoot([][]byte{leaf(1)})) != hex.EncodeToString(leaf(1)) {
+		t.Fatal("single-leaf root should be the leaf")
+	}
+}
+
+// TestSignedReceiptsResistFullChainRewrite proves a rehashed forgery fails
+// signature verification, and stripping signatures does not help
+func TestSignedReceiptsResistFullChainRewrite(t *testing.T) {
+	signer, err := signing.GenerateLocalSigner("kernel_audit_1")
+	if err != nil {
+		t.Fatalf("generate signer: %v", err)
+	}
+	ledger := NewLedger()
+	if err := ledger.SetSigner(signer); err != nil {
+		t.Fatalf("set signer: %v", err)
+	}
+
+	ledger.AppendCDIDecision(testActor, "DENY", "hash1", "", "decision_1")
+	ledger.AppendStopEvent(testActor, 2)
+
+	for _, receipt := range ledger.GetReceipts()[1:] {
+		if len(receipt.Signature) == 0 || receipt.SignerKeyID != "kernel_audit_1" {
+			t.Fatalf("receipt %d should be signed", receipt.Sequence)
+		}
+	}
+	if valid, err := ledger.Verify(); !valid {
+		t.Fatalf("signed ledger should verify: %v", err)
+	}
+
+	keys := signing.NewKeyRing()
+	keys.AddSigner(signer)
+	if err := VerifyReceiptSignatures(ledger.GetReceipts(), keys); err != nil {
+		t.Fatalf("external verification should pass: %v", err)
+	}
+
+	ledger.receipts[1].EventData["decision"] = "ALLOW"
+	rehashChain(ledger)
+	if valid, _ := ledger.Verify(); valid {
+		t.Fatal("rehashed forgery must fail signature verification")
+	}
+	if err := VerifyReceiptSignatures(ledger.GetReceipts(), keys); err == nil {
+		t.Fatal("rehashed forgery must fail external verification")
+	}
+
+	for i := range ledger.receipts {
+		ledger.receipts[i].Signature = nil
+		ledger.receipts[i].SignerKeyID = ""
+	}
+	if valid, _ := ledger.Verify(); valid {
+		t.Fatal("stripping signatures must not pass verification")
+	}
+}
+
+// TestSignedFileLedgerReloadsWithTrustedKey proves signatures survive a
+// restart and require the earlier key to be trusted
+func TestSignedFileLedgerReloadsWithTrustedKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	signer, err := signing.GenerateLocalSigner("kernel_audit_1")
+	if err != nil {
+		t.Fatalf("generate signer: %v", err)
+	}
+
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	ledger, err := OpenLedger(store)
+	if err != nil {
+		t.Fatalf("open ledger: %v", err)
+	}
+	ledger.SetSigner(signer)
+	ledger.AppendIntegrityStateChange(testActor, "INTEGRITY_OK")
+	ledger.Close()
+
+	store, _ = OpenFileStore(path)
+	reopened, err := OpenLedger(store)
+	if err != nil {
+		t.Fatalf("reopen ledger: %v", err)
+	}
+	defer reopened.Close()
+
+	if valid, _ := reopened.Verify(); valid {
+		t.Fatal("signed history must not verify without its key")
+	}
+	if err := reopened.TrustKey(signer.KeyID(), signer.Public()); err != nil {
+		t.Fatalf("trust key: %v", err)
+	}
+	if valid, err := reopened.Verify(); !valid {
+		t.Fatalf("reloaded signed ledger should verify: %v", err)
+	}
+}
+
+// TestStrippedFileLedgerFailsOnReload proves a stored chain with every
+// signature stripped does not pass for one never signed once a key is
+// trusted or a signer set
+func TestStrippedFileLedgerFailsOnReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	signer, _ := signing.GenerateLocalSigner("kernel_audit_1")
+	store, _ := OpenFileStore(path)
+	ledger, err := OpenLedger(store)
+	if err != nil {
+		t.Fatalf("open ledger: %v", err)
+	}
+	ledger.SetSigner(signer)
+	ledger.AppendCDIDecision(testActor, "DENY", "hash1", "", "decision_1")
+	ledger.Close()
+
+	file, _ := os.Open(path)
+	receipts, err := ReadReceipts(file)
+	file.Close()
+	if err != nil {
+		t.Fatalf("read receipts: %v", err)
+	}
+	var stripped bytes.Buffer
+	for _, receipt := range receipts {
+		receipt.Signature, receipt.SignerKeyID = nil, ""
+		line, _ := json.Marshal(receipt)
+		stripped.Write(append(line, '\n'))
+	}
+	os.WriteFile(path, stripped.Bytes(), 0o600)
+
+	for name, configure := range map[string]func(*Ledger) error{
+		"trusted key": func(l *Ledger) error { return l.TrustKey(signer.KeyID(), signer.Public()) },
+		"signer":      func(l *Ledger) error { return l.SetSigner(signer) },
+	} {
+		store, _ := OpenFileStore(path)
+		reopened, err := OpenLedger(store)
+		if err != nil {
+			t.Fatalf("%s: reopen: %v", name, err)
+		}
+		if err := configure(reopened); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if valid, _ := reopened.Verify(); valid {
+			t.Fatalf("%s: a stripped chain must not verify", name)
+		}
+		reopened.Close()
+	}
+}
@@ -0,0 +1,110 @@
+// WHY: A Merkle root commits to a whole batch of receipts in 32 bytes.
+// Storing roots outside the chain lets old history be checked even after
+// the receipts themselves are rotated away or compacted.
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Domain-separation prefixes (RFC 6962) so a leaf can never pass as a node
+const (
+	merkleLeafPrefix = 0x00
+	merkleNodePrefix = 0x01
+)
+
+// merkleLeaf hashes a receipt hash into a tree leaf
+func merkleLeaf(receiptHash []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{merkleLeafPrefix})
+	h.Write(receiptHash)
+	return h.Sum(nil)
+}
+
+// merkleNode hashes two children into their parent
+func merkleNode(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{merkleNodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// merkleRoot computes the RFC 6962 tree hash over leaf hashes
+func merkleRoot(leaves [][]byte) []byte {
+	switch len(leaves) {
+	case 0:
+		empty := sha256.Sum256(nil)
+		return empty[:]
+	case 1:
+		return leaves[0]
+	}
+
+	split := largestPowerOfTwoBelow(len(leaves))
+	return merkleNode(merkleRoot(leaves[:split]), merkleRoot(leaves[split:]))
+}
+
+// largestPowerOfTwoBelow returns the largest power of two strictly less than n (n > 1)
+func largestPowerOfTwoBelow(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+// ReceiptsMerkleRoot computes the hex Merkle root over a run of receipts
+func ReceiptsMerkleRoot(receipts []Receipt) (string, error) {
+	leaves := make([][]byte, len(receipts))
+	for i, receipt := range receipts {
+		hash, err := hex.DecodeString(receipt.CurrentHash)
+		if err != nil {
+			return "", fmt.Errorf("receipt %d hash is not hex: %w", receipt.Sequence, err)
+		}
+		leaves[i] = merkleLeaf(hash)
+	}
+	return hex.EncodeToString(merkleRoot(leaves)), nil
+}
+
+// merklePath returns the RFC 6962 audit path for leaf m, leaf to root
+func merklePath(m int, leaves [][]byte) [][]byte {
+	if len(leaves) <= 1 {
+		return [][]byte{}
+	}
+
+	k := largestPowerOfTwoBelow(len(leaves))
+	if m < k {
+		return append(merklePath(m, leaves[:k]), merkleRoot(leaves[k:]))
+	}
+	return append(merklePath(m-k, leaves[k:]), merkleRoot(leaves[:k]))
+}
+
+// rootFromPath recomputes a tree root from a leaf and its audit path
+// (RFC 9162 section 2.1.3.2); ok is false if the path does not fit the tree
+func rootFromPath(leaf []byte, index, size int, path [][]byte) (root []byte, ok bool) {
+	if index < 0 || index >= size {
+		return nil, false
+	}
+
+	fn, sn := index, size-1
+	r := leaf
+	for _, p := range path {
+		if sn == 0 {
+			return nil, false
+		}
+		if fn&1 == 1 || fn == sn {
+			r = merkleNode(p, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = merkleNode(r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	return r, sn == 0
+}
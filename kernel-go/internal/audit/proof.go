@@ -0,0 +1,176 @@
+// WHY: A user who wants to know that their decision was recorded should
+// not need the whole ledger, or trust whoever hands it over. An inclusion
+// proof carries the receipt's Merkle path to the checkpoint that covers it
+// plus the signed checkpoint receipt, so anyone holding the kernel's public
+// key can check it offline.
+package audit
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+// InclusionProof shows that a receipt is committed to by a checkpoint
+type InclusionProof struct {
+	Sequence    int64  `json:"sequence"`
+	ReceiptHash string `json:"receipt_hash"`
+
+	// Checkpoint covers the receipt; CheckpointReceipt is the chain receipt
+	// that records it, signed when the ledger is signed
+	Checkpoint        Checkpoint `json:"checkpoint"`
+	CheckpointReceipt Receipt    `json:"checkpoint_receipt"`
+
+	// LeafIndex and TreeSize place the receipt in the checkpoint's tree;
+	// Path holds hex sibling hashes from the leaf up to the root
+	LeafIndex int      `json:"leaf_index"`
+	TreeSize  int      `json:"tree_size"`
+	Path      []string `json:"path"`
+}
+
+// Prove returns an inclusion proof for the receipt at sequence. A receipt
+// not yet covered by a checkpoint gets one cut (and exported) first.
+func (l *Ledger) Prove(sequence int64) (InclusionProof, error) {
+	l.mu.Lock()
+	checkpoint, found := l.coveringCheckpointLocked(sequence)
+	var cut *Checkpoint
+	if !found && sequence > l.lastCheckpointEnd && sequence <= l.sequence {
+		fresh, err := l.checkpointLocked()
+		if err != nil {
+			l.mu.Unlock()
+			return InclusionProof{}, err
+		}
+		checkpoint, found, cut = fresh, true, &fresh
+	}
+	proof, err := l.proveLocked(sequence, checkpoint, found)
+	sink := l.checkpointSink
+	l.mu.Unlock()
+
+	if cut != nil {
+		if exportErr := l.exportCheckpoint(sink, *cut); exportErr != nil && err == nil {
+			err = exportErr
+		}
+	}
+	return proof, err
+}
+
+// coveringCheckpointLocked finds the checkpoint whose range holds sequence.
+// Callers must hold l.mu.
+func (l *Ledger) coveringCheckpointLocked(sequence int64) (Checkpoint, bool) {
+	for _, checkpoint := range checkpointsIn(l.receipts) {
+		if checkpoint.StartSequence <= sequence && sequence <= checkpoint.EndSequence {
+			return checkpoint, true
+		}
+	}
+	return Checkpoint{}, false
+}
+
+// proveLocked builds the proof against checkpoint. Callers must hold l.mu.
+func (l *Ledger) proveLocked(sequence int64, checkpoint Checkpoint, found bool) (InclusionProof, error) {
+	if !found {
+		return InclusionProof{}, fmt.Errorf("no held checkpoint covers receipt %d", sequence)
+	}
+
+	batch := l.receiptRangeLocked(checkpoint.StartSequence, checkpoint.EndSequence)
+	if int64(len(batch)) != checkpoint.EndSequence-checkpoint.StartSequence+1 {
+		return InclusionProof{}, fmt.Errorf("receipts of checkpoint %d-%d are no longer held",
+			checkpoint.StartSequence, checkpoint.EndSequence)
+	}
+
+	var recorded Receipt
+	recordedFound := false
+	for _, receipt := range l.receipts {
+		if receipt.EventType == "checkpoint" && toInt64(receipt.EventData["end_sequence"]) == checkpoint.EndSequence {
+			recorded, recordedFound = receipt, true
+			break
+		}
+	}
+	if !recordedFound {
+		return InclusionProof{}, fmt.Errorf("checkpoint %d-%d receipt is no longer held", checkpoint.StartSequence, checkpoint.EndSequence)
+	}
+
+	leaves := make([][]byte, len(batch))
+	index := -1
+	for i, receipt := range batch {
+		hash, err := hex.DecodeString(receipt.CurrentHash)
+		if err != nil {
+			return InclusionProof{}, fmt.Errorf("receipt %d hash is not hex: %w", receipt.Sequence, err)
+		}
+		leaves[i] = merkleLeaf(hash)
+		if receipt.Sequence == sequence {
+			index = i
+		}
+	}
+
+	path := []string{}
+	for _, node := range merklePath(index, leaves) {
+		path = append(path, hex.EncodeToString(node))
+	}
+	return InclusionProof{
+		Sequence:          sequence,
+		ReceiptHash:       batch[index].CurrentHash,
+		Checkpoint:        checkpoint,
+		CheckpointReceipt: recorded,
+		LeafIndex:         index,
+		TreeSize:          len(batch),
+		Path:              path,
+	}, nil
+}
+
+// VerifyInclusionProof checks that receipt is committed to by the proof's
+// checkpoint, and that the checkpoint receipt is intact. With keys, the
+// checkpoint receipt's signature must also verify; pass nil only for
+// unsigned ledgers.
+func VerifyInclusionProof(proof InclusionProof, receipt Receipt, keys *signing.KeyRing) error {
+	if receipt.Sequence != proof.Sequence || receipt.CurrentHash != proof.ReceiptHash {
+		return fmt.Errorf("proof is for receipt %d, not %d", proof.Sequence, receipt.Sequence)
+	}
+	if computeHash(receipt) != receipt.CurrentHash {
+		return fmt.Errorf("receipt %d hash mismatch", receipt.Sequence)
+	}
+	if proof.Sequence < proof.Checkpoint.StartSequence || proof.Sequence > proof.Checkpoint.EndSequence ||
+		int64(proof.LeafIndex) != proof.Sequence-proof.Checkpoint.StartSequence ||
+		int64(proof.TreeSize) != proof.Checkpoint.EndSequence-proof.Checkpoint.StartSequence+1 {
+		return fmt.Errorf("proof position does not match checkpoint %d-%d",
+			proof.Checkpoint.StartSequence, proof.Checkpoint.EndSequence)
+	}
+
+	// The checkpoint must be the one its chain receipt records
+	recorded := checkpointsIn([]Receipt{proof.CheckpointReceipt})
+	if len(recorded) != 1 || recorded[0] != proof.Checkpoint {
+		return fmt.Errorf("checkpoint receipt does not record checkpoint %d-%d",
+			proof.Checkpoint.StartSequence, proof.Checkpoint.EndSequence)
+	}
+	if computeHash(proof.CheckpointReceipt) != proof.CheckpointReceipt.CurrentHash {
+		return fmt.Errorf("checkpoint receipt %d hash mismatch", proof.CheckpointReceipt.Sequence)
+	}
+	if keys != nil {
+		if err := verifyReceiptSignature(proof.CheckpointReceipt, keys); err != nil {
+			return err
+		}
+	}
+
+	receiptHash, err := hex.DecodeString(receipt.CurrentHash)
+	if err != nil {
+		return fmt.Errorf("receipt %d hash is not hex: %w", receipt.Sequence, err)
+	}
+	path := make([][]byte, len(proof.Path))
+	for i, node := range proof.Path {
+		if path[i], err = hex.DecodeString(node); err != nil {
+			return fmt.Errorf("proof path node %d is not hex: %w", i, err)
+		}
+	}
+	expectedRoot, err := hex.DecodeString(proof.Checkpoint.MerkleRoot)
+	if err != nil {
+		return fmt.Errorf("checkpoint root is not hex: %w", err)
+	}
+
+	root, ok := rootFromPath(merkleLeaf(receiptHash), proof.LeafIndex, proof.TreeSize, path)
+	if !ok || !bytes.Equal(root, expectedRoot) {
+		return fmt.Errorf("receipt %d is not included in checkpoint %d-%d",
+			receipt.Sequence, proof.Checkpoint.StartSequence, proof.Checkpoint.EndSequence)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// WHY: These tests prove inclusion proofs verify for every leaf position and
+// tree size, and fail for any other receipt, path, or checkpoint.
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+// TestInclusionProofsForEveryTreeShape proves every receipt of checkpoints
+// of sizes 1-17 can be proven and verified
+func TestInclusionProofsForEveryTreeShape(t *testing.T) {
+	for size := 1; size <= 17; size++ {
+		ledger := NewLedger()
+		for i := 0; i < size-1; i++ {
+			ledger.AppendStopEvent(testActor, i)
+		}
+		checkpoint, err := ledger.Checkpoint() // covers genesis plus size-1 receipts
+		if err != nil {
+			t.Fatalf("checkpoint: %v", err)
+		}
+
+		receipts := ledger.GetReceipts()
+		for _, receipt := range receipts[:size] {
+			proof, err := ledger.Prove(receipt.Sequence)
+			if err != nil {
+				t.Fatalf("size %d: prove %d: %v", size, receipt.Sequence, err)
+			}
+			if proof.Checkpoint != checkpoint || proof.TreeSize != size {
+				t.Fatalf("size %d: proof uses the wrong checkpoint: %+v", size, proof.Checkpoint)
+			}
+			if err := VerifyInclusionProof(proof, receipt, nil); err != nil {
+				t.Fatalf("size %d: verify %d: %v", size, receipt.Sequence, err)
+			}
+			if size > 1 {
+				other := receipts[(int(receipt.Sequence)+1)%size]
+				if err := VerifyInclusionProof(proof, other, nil); err == nil {
+					t.Fatalf("size %d: proof for %d must not verify receipt %d", size, receipt.Sequence, other.Sequence)
+				}
+			}
+		}
+	}
+}
+
+// TestInclusionProofSurvivesTransport proves a JSON-encoded signed proof
+// verifies with only the kernel's public key, and tampering is caught
+func TestInclusionProofSurvivesTransport(t *testing.T) {
+	ledger := NewLedger()
+	signer, _ := signing.GenerateLocalSigner("kernel_audit_1")
+	ledger.SetSigner(signer)
+	ledger.AppendCDIDecision(testActor, "ALLOW", "h", "", "decision_1")
+	ledger.AppendStopEvent(testActor, 0)
+
+	// Not yet checkpointed: Prove cuts one
+	decision := ledger.GetReceipts()[1]
+	proof, err := ledger.Prove(decision.Sequence)
+	if err != nil {
+		t.Fatalf("prove: %v", err)
+	}
+	if len(ledger.Checkpoints()) != 1 {
+		t.Fatal("prove should checkpoint an uncovered receipt")
+	}
+
+	encoded, _ := json.Marshal(proof)
+	var received InclusionProof
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	if err := decoder.Decode(&received); err != nil {
+		t.Fatalf("decode proof: %v", err)
+	}
+	encodedReceipt, _ := json.Marshal(decision)
+	receivedReceipt, err := decodeReceipt(encodedReceipt)
+	if err != nil {
+		t.Fatalf("decode receipt: %v", err)
+	}
+
+	keys := signing.NewKeyRing()
+	keys.AddSigner(signer)
+	if err := VerifyInclusionProof(received, receivedReceipt, keys); err != nil {
+		t.Fatalf("transported proof should verify: %v", err)
+	}
+
+	if err := VerifyInclusionProof(received, receivedReceipt, signing.NewKeyRing()); err == nil {
+		t.Fatal("an untrusted checkpoint signer must be rejected")
+	}
+
+	forgedRoot := received
+	forgedRoot.Checkpoint.MerkleRoot = received.Path[0]
+	if err := VerifyInclusionProof(forgedRoot, receivedReceipt, keys); err == nil {
+		t.Fatal("a checkpoint that differs from its receipt must be rejected")
+	}
+
+	forgedPath := received
+	forgedPath.Path = append([]string(nil), received.Path...)
+	forgedPath.Path[0] = received.ReceiptHash
+	if err := VerifyInclusionProof(forgedPath, receivedReceipt, keys); err == nil {
+		t.Fatal("a tampered path must be rejected")
+	}
+
+	edited := receivedReceipt
+	edited.EventData = map[string]interface{}{"decision": "DENY"}
+	if err := VerifyInclusionProof(received, edited, keys); err == nil {
+		t.Fatal("an edited receipt must be rejected")
+	}
+}
+
+// TestProveUnknownReceipt proves receipts beyond the chain head have no proof
+func TestProveUnknownReceipt(t *testing.T) {
+	ledger := NewLedger()
+	if _, err := ledger.Prove(5); err == nil {
+		t.Fatal("a receipt that does not exist cannot be proven")
+	}
+}
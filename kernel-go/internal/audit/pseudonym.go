@@ -0,0 +1,76 @@
+// WHY: Receipts are exported to SIEMs and sinks that many people can read.
+// Raw principal and namespace IDs, and plain SHA-256 hashes of short inputs
+// (which a dictionary reverses), would turn the audit log into a
+// user-tracking dataset. In pseudonymous mode those fields are replaced by
+// HMACs under a per-deployment secret salt before the receipt is hashed:
+// receipts about the same user still correlate, but only the salt holder
+// can tell who that user is.
+package audit
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// MinPseudonymSaltBytes is the minimum per-deployment salt length
+const MinPseudonymSaltBytes = 32
+
+// pseudonymFields are the event data fields replaced in pseudonymous mode
+var pseudonymFields = []string{
+	"principal_id",
+	"namespace_id",
+	"input_hash",
+	"output_hash",
+	"request_hash",
+	"response_hash",
+	"content_hash",
+}
+
+// Pseudonymizer HMACs identifiers with a secret per-deployment salt
+type Pseudonymizer struct {
+	keyID string
+	salt  []byte
+}
+
+// NewPseudonymizer creates a pseudonymizer; keyID names the salt so
+// investigators know which one to use after a salt rotation
+func NewPseudonymizer(keyID string, salt []byte) (*Pseudonymizer, error) {
+	if keyID == "" {
+		return nil, fmt.Errorf("empty pseudonym key ID")
+	}
+	if len(salt) < MinPseudonymSaltBytes {
+		return nil, fmt.Errorf("pseudonym salt must be at least %d bytes, got %d", MinPseudonymSaltBytes, len(salt))
+	}
+	return &Pseudonymizer{keyID: keyID, salt: append([]byte(nil), salt...)}, nil
+}
+
+// Pseudonym returns the receipt value for a field, e.g. to look up a
+// principal's receipts: "hmac:<key ID>:<hex HMAC-SHA256>". The field name
+// is mixed in so a principal ID and a namespace ID never collide.
+func (p *Pseudonymizer) Pseudonym(field, value string) string {
+	mac := hmac.New(sha256.New, p.salt)
+	mac.Write([]byte(field))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return "hmac:" + p.keyID + ":" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// apply replaces identifying fields in event data; empty values stay empty
+func (p *Pseudonymizer) apply(eventData map[string]interface{}) {
+	for _, field := range pseudonymFields {
+		if value, ok := eventData[field].(string); ok && value != "" {
+			eventData[field] = p.Pseudonym(field, value)
+		}
+	}
+}
+
+// SetPseudonymizer enables pseudonymous mode for receipts appended from now
+// on; nil disables it. Earlier receipts are unchanged.
+func (l *Ledger) SetPseudonymizer(p *Pseudonymizer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.pseudonymizer = p
+}
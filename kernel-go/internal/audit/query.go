@@ -0,0 +1,165 @@
+// WHY: Investigations ask narrow questions ("every DENY for this principal
+// last Tuesday"). Filtering inside the ledger avoids copying the whole
+// chain for each question and gives every caller the same matching rules.
+package audit
+
+import "fmt"
+
+// Query page sizes
+const (
+	DefaultQueryLimit = 100
+	MaxQueryLimit     = 1000
+)
+
+// ReceiptFilter selects receipts. Zero-valued fields match everything.
+type ReceiptFilter struct {
+	// EventTypes matches any of the listed event types
+	EventTypes []string
+
+	// Since and Until bound the receipt timestamp (Unix seconds); Since is
+	// inclusive and Until exclusive
+	Since int64
+	Until int64
+
+	// Identity, token, and request fields, matched against the receipt's
+	// event data
+	PrincipalID string
+	NamespaceID string
+	TokenDigest string
+	RequestID   string
+
+	// Decision matches the outcome of cdi_decision receipts (ALLOW, DENY, DEGRADE)
+	Decision string
+
+	// MinSeverity keeps receipts at or above a severity; Categories matches any listed
+	MinSeverity Severity
+	Categories  []Category
+
+	// FromSequence is the pagination cursor: the first sequence to consider
+	FromSequence int64
+
+	// Limit caps the page size (DefaultQueryLimit if zero, at most MaxQueryLimit)
+	Limit int
+}
+
+// ReceiptPage is one page of query results
+type ReceiptPage struct {
+	Receipts []Receipt
+
+	// NextSequence is the FromSequence for the next page; valid when HasMore
+	NextSequence int64
+	HasMore      bool
+}
+
+// Query returns receipts matching the filter in sequence order, one page at a time
+func (l *Ledger) Query(filter ReceiptFilter) (ReceiptPage, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Callers filter by real identifiers; receipts may hold pseudonyms
+	if p := l.pseudonymizer; p != nil {
+		if filter.PrincipalID != "" {
+			filter.PrincipalID = p.Pseudonym("principal_id", filter.PrincipalID)
+		}
+		if filter.NamespaceID != "" {
+			filter.NamespaceID = p.Pseudonym("namespace_id", filter.NamespaceID)
+		}
+	}
+
+	return QueryReceipts(l.receipts, filter)
+}
+
+// QueryReceipts applies the filter to receipts read without a ledger, such
+// as a file that no longer verifies, with the same matching rules as Query
+func QueryReceipts(receipts []Receipt, filter ReceiptFilter) (ReceiptPage, error) {
+	limit := filter.Limit
+	if limit == 0 {
+		limit = DefaultQueryLimit
+	}
+	if limit < 0 || limit > MaxQueryLimit {
+		return ReceiptPage{}, fmt.Errorf("query limit %d out of range 1-%d", filter.Limit, MaxQueryLimit)
+	}
+	if filter.Until != 0 && filter.Until <= filter.Since {
+		return ReceiptPage{}, fmt.Errorf("empty time range [%d, %d)", filter.Since, filter.Until)
+	}
+
+	page := ReceiptPage{Receipts: []Receipt{}}
+	for _, receipt := range receipts {
+		if receipt.Sequence < filter.FromSequence || !filter.matches(receipt) {
+			continue
+		}
+		if len(page.Receipts) == limit {
+			page.HasMore = true
+			page.NextSequence = receipt.Sequence
+			break
+		}
+		page.Receipts = append(page.Receipts, receipt)
+	}
+
+	return page, nil
+}
+
+// matches reports whether a receipt passes every set field of the filter
+func (f ReceiptFilter) matches(receipt Receipt) bool {
+	if len(f.EventTypes) > 0 {
+		found := false
+		for _, eventType := range f.EventTypes {
+			if receipt.EventType == eventType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if f.Since != 0 && receipt.Timestamp < f.Since {
+		return false
+	}
+	if f.Until != 0 && receipt.Timestamp >= f.Until {
+		return false
+	}
+
+	fields := map[string]string{
+		"principal_id": f.PrincipalID,
+		"namespace_id": f.NamespaceID,
+		"token_digest": f.TokenDigest,
+		"request_id":   f.RequestID,
+	}
+	for field, want := range fields {
+		if want == "" {
+			continue
+		}
+		if got, _ := receipt.EventData[field].(string); got != want {
+			return false
+		}
+	}
+
+	if f.MinSeverity != "" && !receiptSeverity(receipt).AtLeast(f.MinSeverity) {
+		return false
+	}
+	if len(f.Categories) > 0 {
+		found := false
+		for _, category := range f.Categories {
+			if receipt.Category == category {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if f.Decision != "" {
+		if receipt.EventType != "cdi_decision" {
+			return false
+		}
+		if got, _ := receipt.EventData["decision"].(string); got != f.Decision {
+			return false
+		}
+	}
+
+	return true
+}
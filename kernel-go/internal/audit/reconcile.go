@@ -0,0 +1,178 @@
+// WHY: After suspected tampering, investigators hold two copies of the
+// chain: the host's ledger and the copy a remote sink received. Comparing
+// them receipt by receipt shows where they diverge, which side still hashes
+// correctly, and what one side is missing, without trusting either copy.
+package audit
+
+import "sort"
+
+// SequenceRange is an inclusive run of receipt sequences
+type SequenceRange struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
+
+// Divergence is a sequence both copies hold with different hashes
+type Divergence struct {
+	Sequence   int64  `json:"sequence"`
+	LocalHash  string `json:"local_hash"`
+	RemoteHash string `json:"remote_hash"`
+
+	// LocalIntact and RemoteIntact report whether each copy's stored hash
+	// still matches its contents; the side that does not was edited in place
+	LocalIntact  bool `json:"local_intact"`
+	RemoteIntact bool `json:"remote_intact"`
+}
+
+// ReconciliationReport compares a local and a remote copy of a ledger
+type ReconciliationReport struct {
+	LocalReceipts  int `json:"local_receipts"`
+	RemoteReceipts int `json:"remote_receipts"`
+	Matched        int `json:"matched"`
+
+	// FirstDivergence is the lowest divergent sequence, or -1
+	FirstDivergence int64        `json:"first_divergence"`
+	Divergences     []Divergence `json:"divergences"`
+
+	// Receipts held by only one copy
+	MissingLocal  []SequenceRange `json:"missing_local"`
+	MissingRemote []SequenceRange `json:"missing_remote"`
+
+	// Duplicates counts repeated sequences (sinks may redeliver);
+	// Conflicts lists sequences a copy holds twice with different hashes
+	LocalDuplicates  int     `json:"local_duplicates"`
+	RemoteDuplicates int     `json:"remote_duplicates"`
+	LocalConflicts   []int64 `json:"local_conflicts"`
+	RemoteConflicts  []int64 `json:"remote_conflicts"`
+
+	// Chain errors from verifying each copy on its own ("" if it verifies)
+	LocalChainError  string `json:"local_chain_error,omitempty"`
+	RemoteChainError string `json:"remote_chain_error,omitempty"`
+}
+
+// Consistent reports whether the copies agree on every shared receipt,
+// hold the same receipts, and each verify on their own
+func (r ReconciliationReport) Consistent() bool {
+	return len(r.Divergences) == 0 &&
+		len(r.MissingLocal) == 0 &&
+		len(r.MissingRemote) == 0 &&
+		len(r.LocalConflicts) == 0 &&
+		len(r.RemoteConflicts) == 0 &&
+		r.LocalChainError == "" &&
+		r.RemoteChainError == ""
+}
+
+// Reconcile compares two copies of a receipt chain by sequence and hash
+func Reconcile(local, remote []Receipt) ReconciliationReport {
+	report := ReconciliationReport{
+		LocalReceipts:   len(local),
+		RemoteReceipts:  len(remote),
+		FirstDivergence: -1,
+		Divergences:     []Divergence{},
+		MissingLocal:    []SequenceRange{},
+		MissingRemote:   []SequenceRange{},
+	}
+
+	localBySeq := indexReceipts(local, &report.LocalDuplicates, &report.LocalConflicts)
+	remoteBySeq := indexReceipts(remote, &report.RemoteDuplicates, &report.RemoteConflicts)
+
+	sequences := map[int64]bool{}
+	for seq := range localBySeq {
+		sequences[seq] = true
+	}
+	for seq := range remoteBySeq {
+		sequences[seq] = true
+	}
+	ordered := make([]int64, 0, len(sequences))
+	for seq := range sequences {
+		ordered = append(ordered, seq)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i] < ordered[j] })
+
+	missingLocal, missingRemote := []int64{}, []int64{}
+	for _, seq := range ordered {
+		l, inLocal := localBySeq[seq]
+		r, inRemote := remoteBySeq[seq]
+		switch {
+		case !inLocal:
+			missingLocal = append(missingLocal, seq)
+		case !inRemote:
+			missingRemote = append(missingRemote, seq)
+		default:
+			// Equal stored hashes are not enough: content edited without
+			// rehashing keeps the old hash
+			divergence := Divergence{
+				Sequence:     seq,
+				LocalHash:    l.CurrentHash,
+				RemoteHash:   r.CurrentHash,
+				LocalIntact:  computeHash(l) == l.CurrentHash,
+				RemoteIntact: computeHash(r) == r.CurrentHash,
+			}
+			if l.CurrentHash == r.CurrentHash && divergence.LocalIntact && divergence.RemoteIntact {
+				report.Matched++
+				continue
+			}
+			report.Divergences = append(report.Divergences, divergence)
+		}
+	}
+	report.MissingLocal = toRanges(missingLocal)
+	report.MissingRemote = toRanges(missingRemote)
+
+	if len(report.Divergences) > 0 {
+		report.FirstDivergence = report.Divergences[0].Sequence
+	}
+
+	report.LocalChainError = chainError(local)
+	report.RemoteChainError = chainError(remote)
+	return report
+}
+
+// indexReceipts maps receipts by sequence, counting duplicates and
+// recording sequences held twice with different hashes
+func indexReceipts(receipts []Receipt, duplicates *int, conflicts *[]int64) map[int64]Receipt {
+	bySeq := make(map[int64]Receipt, len(receipts))
+	*conflicts = []int64{}
+	for _, receipt := range receipts {
+		existing, seen := bySeq[receipt.Sequence]
+		if !seen {
+			bySeq[receipt.Sequence] = receipt
+			continue
+		}
+		*duplicates++
+		if existing.CurrentHash != receipt.CurrentHash {
+			*conflicts = append(*conflicts, receipt.Sequence)
+		}
+	}
+	return bySeq
+}
+
+// chainError verifies one copy in sequence order, ignoring redelivered duplicates
+func chainError(receipts []Receipt) string {
+	ordered := make([]Receipt, 0, len(receipts))
+	seen := map[int64]bool{}
+	for _, receipt := range receipts {
+		if !seen[receipt.Sequence] {
+			seen[receipt.Sequence] = true
+			ordered = append(ordered, receipt)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Sequence < ordered[j].Sequence })
+
+	if err := verifyChain(ordered); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// toRanges collapses sorted sequences into inclusive runs
+func toRanges(sequences []int64) []SequenceRange {
+	ranges := []SequenceRange{}
+	for _, seq := range sequences {
+		if n := len(ranges); n > 0 && ranges[n-1].End == seq-1 {
+			ranges[n-1].End = seq
+			continue
+		}
+		ranges = append(ranges, SequenceRange{Start: seq, End: seq})
+	}
+	return ranges
+}
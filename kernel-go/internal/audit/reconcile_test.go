@@ -0,0 +1,78 @@
+// WHY: These tests prove reconciliation finds edits, gaps, and redelivered
+// duplicates between two copies of a chain without trusting either.
+package audit
+
+import "testing"
+
+// TestReconcileIdenticalCopies proves a faithful copy, even with redelivered
+// receipts, is consistent
+func TestReconcileIdenticalCopies(t *testing.T) {
+	ledger := NewLedger()
+	ledger.AppendCDIDecision(testActor, "ALLOW", "h", "", "d1")
+	ledger.AppendStopEvent(testActor, 1)
+	local := ledger.GetReceipts()
+
+	remote := append(append([]Receipt(nil), local...), local[1])
+	report := Reconcile(local, remote)
+	if !report.Consistent() || report.Matched != len(local) || report.RemoteDuplicates != 1 {
+		t.Fatalf("faithful copy should reconcile: %+v", report)
+	}
+	if report.FirstDivergence != -1 {
+		t.Fatal("no divergence expected")
+	}
+}
+
+// TestReconcileLocatesEditAndGaps proves an in-place edit is located and
+// attributed to the copy whose hash no longer matches, and gaps are ranged
+func TestReconcileLocatesEditAndGaps(t *testing.T) {
+	ledger := NewLedger()
+	for i := 0; i < 5; i++ {
+		ledger.AppendStopEvent(testActor, i)
+	}
+	local := ledger.GetReceipts()
+
+	remote := make([]Receipt, len(local))
+	copy(remote, local)
+	edited := remote[2]
+	edited.EventData = map[string]interface{}{"tokens_revoked": 99}
+	remote[2] = edited
+	remote = append(remote[:4], remote[5:]...) // drop sequence 4
+
+	report := Reconcile(local, remote)
+	if report.Consistent() {
+		t.Fatal("edited copy must not reconcile")
+	}
+	// The edit kept its stored hash, but no longer hashes to it
+	if report.FirstDivergence != 2 || report.Divergences[0].RemoteIntact || !report.Divergences[0].LocalIntact {
+		t.Fatalf("edit without rehash should diverge at 2 on the remote side: %+v", report.Divergences)
+	}
+	if report.RemoteChainError == "" {
+		t.Fatal("edited remote chain should fail verification")
+	}
+	if len(report.MissingRemote) != 1 || report.MissingRemote[0] != (SequenceRange{Start: 4, End: 4}) {
+		t.Fatalf("expected receipt 4 missing remotely, got %v", report.MissingRemote)
+	}
+
+	// Rehashed edit: hashes now differ, and the remote copy is self-consistent
+	edited.CurrentHash = computeHash(edited)
+	remote[2] = edited
+	report = Reconcile(local, remote)
+	if report.FirstDivergence != 2 || !report.Divergences[0].LocalIntact || !report.Divergences[0].RemoteIntact {
+		t.Fatalf("rehashed edit should diverge at 2: %+v", report.Divergences)
+	}
+}
+
+// TestReconcileFlagsConflictingDuplicates proves a copy holding two
+// different receipts for one sequence is reported
+func TestReconcileFlagsConflictingDuplicates(t *testing.T) {
+	ledger := NewLedger()
+	ledger.AppendStopEvent(testActor, 1)
+	local := ledger.GetReceipts()
+
+	forged := local[1]
+	forged.CurrentHash = "forged"
+	report := Reconcile(local, append(append([]Receipt(nil), local...), forged))
+	if report.Consistent() || len(report.RemoteConflicts) != 1 || report.RemoteConflicts[0] != 1 {
+		t.Fatalf("conflicting duplicate should be reported: %+v", report)
+	}
+}
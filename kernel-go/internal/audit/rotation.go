@@ -0,0 +1,202 @@
+// WHY: A long-lived kernel cannot hold its entire governance history in
+// memory. Rotation seals the chain into segments: each segment ends with a
+// checkpoint, and the next segment opens with an anchor receipt that
+// commits to the sealed segment's Merkle root and links to its last hash.
+// Sealed segments can then be archived and dropped from memory without
+// breaking verification of what remains.
+package audit
+
+import "fmt"
+
+// Segment is a sealed, contiguous run of receipts
+type Segment struct {
+	Index         int
+	StartSequence int64
+	EndSequence   int64
+
+	// Seal commits to every receipt in the segment
+	Seal Checkpoint
+
+	// Receipts are populated when a segment is handed to an ArchiveFunc;
+	// Segments() leaves them empty
+	Receipts []Receipt
+
+	archived bool
+}
+
+// ArchiveFunc stores a sealed segment outside the ledger, e.g. in cold
+// storage. It is called without the ledger lock held.
+type ArchiveFunc func(Segment) error
+
+// RotationPolicy controls automatic rotation and in-memory retention
+type RotationPolicy struct {
+	// SegmentReceipts rotates once the current segment holds this many
+	// receipts; 0 disables automatic rotation
+	SegmentReceipts int
+
+	// RetainSegments is how many sealed segments stay in memory; older
+	// segments are dropped once archived. 0 retains every segment.
+	RetainSegments int
+
+	// Archive receives each sealed segment; nil means the store is the
+	// archive, and a ledger without a healthy store retains every segment
+	Archive ArchiveFunc
+}
+
+// SetRotationPolicy configures segment rotation and retention
+func (l *Ledger) SetRotationPolicy(policy RotationPolicy) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rotation = policy
+	l.enforceRetentionLocked()
+}
+
+// Rotate seals the current segment and opens a new one
+func (l *Ledger) Rotate() (Segment, error) {
+	l.mu.Lock()
+	segment, checkpoint, err := l.rotateLocked()
+	sink := l.checkpointSink
+	archive := l.rotation.Archive
+	l.mu.Unlock()
+
+	if err != nil {
+		return Segment{}, err
+	}
+	if err := l.exportCheckpoint(sink, checkpoint); err != nil {
+		return segment, err
+	}
+	return segment, l.archiveSegment(archive, segment)
+}
+
+// Segments returns the sealed segments still held in memory, without receipts
+func (l *Ledger) Segments() []Segment {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	segments := make([]Segment, len(l.segments))
+	copy(segments, l.segments)
+	return segments
+}
+
+// dueForRotationLocked reports whether the current segment is full.
+// Callers must hold l.mu.
+func (l *Ledger) dueForRotationLocked() bool {
+	return l.rotation.SegmentReceipts > 0 &&
+		l.sequence-l.segmentStart+1 >= int64(l.rotation.SegmentReceipts)
+}
+
+// rotateLocked checkpoints and seals the current segment, then appends the
+// anchor receipt that opens the next one. Callers must hold l.mu.
+func (l *Ledger) rotateLocked() (Segment, Checkpoint, error) {
+	checkpoint, err := l.checkpointLocked()
+	if err != nil {
+		return Segment{}, Checkpoint{}, fmt.Errorf("seal segment %d: %w", l.segmentIndex, err)
+	}
+
+	receipts := l.receiptRangeLocked(l.segmentStart, l.sequence)
+	root, err := ReceiptsMerkleRoot(receipts)
+	if err != nil {
+		return Segment{}, Checkpoint{}, fmt.Errorf("seal segment %d: %w", l.segmentIndex, err)
+	}
+
+	segment := Segment{
+		Index:         l.segmentIndex,
+		StartSequence: l.segmentStart,
+		EndSequence:   l.sequence,
+		Seal: Checkpoint{
+			StartSequence: l.segmentStart,
+			EndSequence:   l.sequence,
+			MerkleRoot:    root,
+		},
+	}
+
+	l.appendLocked("segment_anchor", map[string]interface{}{
+		"sealed_start":   segment.Seal.StartSequence,
+		"sealed_end":     segment.Seal.EndSequence,
+		"sealed_root":    segment.Seal.MerkleRoot,
+		"sealed_segment": segment.Index,
+	})
+	l.segmentIndex++
+	l.segmentStart = l.sequence
+	l.segments = append(l.segments, segment)
+
+	segment.Receipts = receipts
+	return segment, checkpoint, nil
+}
+
+// archiveSegment hands a sealed segment to the archive hook and then
+// applies retention.
+// WHY: A segment is only dropped from memory after it is safely archived,
+// by the hook or, without one, by a store that has persisted every
+// receipt; archive failure poisons verification like any other lost
+// audit sink.
+func (l *Ledger) archiveSegment(archive ArchiveFunc, segment Segment) error {
+	if archive != nil {
+		if err := archive(segment); err != nil {
+			l.mu.Lock()
+			if l.persistErr == nil {
+				l.persistErr = fmt.Errorf("segment %d not archived: %w", segment.Index, err)
+			}
+			l.mu.Unlock()
+			return err
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if archive == nil && (l.store == nil || l.persistErr != nil) {
+		return nil
+	}
+	for i := range l.segments {
+		if l.segments[i].Index == segment.Index {
+			l.segments[i].archived = true
+		}
+	}
+	l.enforceRetentionLocked()
+	return nil
+}
+
+// enforceRetentionLocked drops the oldest archived segments beyond the
+// retention limit. Callers must hold l.mu.
+func (l *Ledger) enforceRetentionLocked() {
+	retain := l.rotation.RetainSegments
+	if retain <= 0 {
+		return
+	}
+
+	for len(l.segments) > retain && l.segments[0].archived {
+		evicted := l.segments[0]
+		cut := 0
+		for cut < len(l.receipts) && l.receipts[cut].Sequence <= evicted.EndSequence {
+			cut++
+		}
+		// Copy so the evicted receipts can be garbage collected
+		l.receipts = append([]Receipt(nil), l.receipts[cut:]...)
+		l.segments = l.segments[1:]
+	}
+}
+
+// sealsIn extracts segment seals from anchor receipts
+func sealsIn(receipts []Receipt) []Segment {
+	segments := []Segment{}
+	for _, receipt := range receipts {
+		if receipt.EventType != "segment_anchor" {
+			continue
+		}
+		root, _ := receipt.EventData["sealed_root"].(string)
+		seal := Checkpoint{
+			StartSequence: toInt64(receipt.EventData["sealed_start"]),
+			EndSequence:   toInt64(receipt.EventData["sealed_end"]),
+			MerkleRoot:    root,
+		}
+		segments = append(segments, Segment{
+			Index:         int(toInt64(receipt.EventData["sealed_segment"])),
+			StartSequence: seal.StartSequence,
+			EndSequence:   seal.EndSequence,
+			Seal:          seal,
+		})
+	}
+	return segments
+}
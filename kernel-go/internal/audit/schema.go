@@ -0,0 +1,116 @@
+// WHY: Receipts are evidence that must outlive the code that wrote them.
+// Each receipt names its layout version, readers keep a decoder for every
+// version ever written, and an unknown version is refused rather than
+// half-decoded, so the format can evolve without invalidating history.
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Receipt schema versions
+const (
+	// SchemaVersionUnversioned covers receipts written before schema
+	// versioning (hash versions 0-2); they carry no schema_version field
+	SchemaVersionUnversioned = 1
+	// SchemaVersionTagged receipts carry schema_version and are hashed
+	// with HashVersionSchema or later
+	SchemaVersionTagged = 2
+
+	// CurrentSchemaVersion is used for every new receipt
+	CurrentSchemaVersion = SchemaVersionTagged
+)
+
+// receiptDecoders decode one stored receipt per schema version
+var receiptDecoders = map[int]func(data []byte) (Receipt, error){
+	SchemaVersionUnversioned: decodeUnversionedReceipt,
+	SchemaVersionTagged:      decodeTaggedReceipt,
+}
+
+// decodeReceipt parses one JSON receipt with the decoder for its schema
+// version.
+// WHY: Numbers are kept as json.Number so they format exactly as the
+// integers that were hashed, keeping reloaded receipts verifiable.
+func decodeReceipt(data []byte) (Receipt, error) {
+	var header struct {
+		SchemaVersion *int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return Receipt{}, fmt.Errorf("decode receipt: %w", err)
+	}
+
+	version := SchemaVersionUnversioned
+	if header.SchemaVersion != nil {
+		version = *header.SchemaVersion
+	}
+	decode, ok := receiptDecoders[version]
+	if !ok {
+		return Receipt{}, fmt.Errorf("decode receipt: unsupported schema version %d", version)
+	}
+	return decode(data)
+}
+
+// decodeUnversionedReceipt reads a receipt written before schema versioning
+func decodeUnversionedReceipt(data []byte) (Receipt, error) {
+	receipt, err := decodeReceiptFields(data, false)
+	if err != nil {
+		return Receipt{}, err
+	}
+	receipt.SchemaVersion = SchemaVersionUnversioned
+	return receipt, checkSchema(receipt)
+}
+
+// decodeTaggedReceipt reads a schema 2 receipt. Unknown fields are refused:
+// a new field means a new schema version.
+func decodeTaggedReceipt(data []byte) (Receipt, error) {
+	receipt, err := decodeReceiptFields(data, true)
+	if err != nil {
+		return Receipt{}, err
+	}
+	return receipt, checkSchema(receipt)
+}
+
+// decodeReceiptFields decodes the JSON receipt layout
+func decodeReceiptFields(data []byte, strict bool) (Receipt, error) {
+	var receipt Receipt
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if strict {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(&receipt); err != nil {
+		return Receipt{}, fmt.Errorf("decode receipt: %w", err)
+	}
+	return receipt, nil
+}
+
+// schemaOf returns a receipt's schema version; receipts built in memory
+// before versioning report as unversioned
+func schemaOf(receipt Receipt) int {
+	if receipt.SchemaVersion == 0 {
+		return SchemaVersionUnversioned
+	}
+	return receipt.SchemaVersion
+}
+
+// checkSchema pairs schema and hash versions.
+// WHY: The schema version is only hashed from HashVersionSchema on; an
+// older receipt claiming a newer schema could otherwise be relabeled freely.
+func checkSchema(receipt Receipt) error {
+	switch schemaOf(receipt) {
+	case SchemaVersionUnversioned:
+		if receipt.HashVersion >= HashVersionSchema {
+			return fmt.Errorf("schema %d receipt cannot use hash version %d", SchemaVersionUnversioned, receipt.HashVersion)
+		}
+	case SchemaVersionTagged:
+		if receipt.HashVersion < HashVersionSchema {
+			return fmt.Errorf("schema %d receipt requires hash version %d or later, got %d",
+				SchemaVersionTagged, HashVersionSchema, receipt.HashVersion)
+		}
+	default:
+		return fmt.Errorf("unsupported schema version %d", receipt.SchemaVersion)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+// WHY: These tests prove every receipt layout ever written still decodes
+// and verifies, and that unknown or relabeled layouts are refused.
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSchemaHashGoldenVector proves hash version 3 binds the schema version
+func TestSchemaHashGoldenVector(t *testing.T) {
+	const expectedHash = "88fcc0c8d120bdb5da28ecc2c5596876e1dbbb03df45ec16c440403790a874cd"
+
+	receipt := canonicalFixture()
+	receipt.HashVersion = HashVersionSchema
+	receipt.Severity = SeverityInfo
+	receipt.Category = CategoryDecision
+	receipt.SchemaVersion = SchemaVersionTagged
+	if got := computeHash(receipt); got != expectedHash {
+		t.Fatalf("schema hash changed: %s", got)
+	}
+
+	receipt.SchemaVersion = 3
+	if computeHash(receipt) == expectedHash {
+		t.Fatal("schema version must be part of the hash")
+	}
+}
+
+// TestMixedSchemaChainLoads proves unversioned and tagged receipts decode
+// through their own decoders and verify as one chain
+func TestMixedSchemaChainLoads(t *testing.T) {
+	genesis := Receipt{Sequence: 0, Timestamp: 1, EventType: "genesis",
+		EventData: map[string]interface{}{"message": "audit ledger initialized"}, PrevHash: "0000000000000000",
+		HashVersion: HashVersionClassified, Severity: SeverityInfo, Category: CategoryIntegrity}
+	genesis.CurrentHash = computeHash(genesis)
+
+	path := filepath.Join(t.TempDir(), "mixed.jsonl")
+	line, _ := json.Marshal(genesis)
+	if strings.Contains(string(line), "schema_version") {
+		t.Fatal("fixture should be unversioned")
+	}
+	os.WriteFile(path, append(line, '\n'), 0o600)
+
+	store, _ := OpenFileStore(path)
+	ledger, err := OpenLedger(store)
+	if err != nil {
+		t.Fatalf("unversioned ledger should load: %v", err)
+	}
+	ledger.AppendStopEvent(testActor, 1)
+	ledger.Close()
+
+	store, _ = OpenFileStore(path)
+	reopened, err := OpenLedger(store)
+	if err != nil {
+		t.Fatalf("mixed-schema ledger should load: %v", err)
+	}
+	defer reopened.Close()
+
+	receipts := reopened.GetReceipts()
+	if receipts[0].SchemaVersion != SchemaVersionUnversioned || receipts[1].SchemaVersion != CurrentSchemaVersion {
+		t.Fatalf("expected schemas 1 and %d, got %d and %d", CurrentSchemaVersion, receipts[0].SchemaVersion, receipts[1].SchemaVersion)
+	}
+	if valid, err := reopened.Verify(); !valid {
+		t.Fatalf("mixed-schema chain should verify: %v", err)
+	}
+
+	if err := verifyChain([]Receipt{receipts[1], downgradeSuccessor(receipts[1])}); err == nil {
+		t.Fatal("an unversioned receipt after a tagged one must be rejected")
+	}
+}
+
+// TestDecodeRefusesUnknownLayouts proves future schemas, unknown fields,
+// and relabeled old receipts are refused
+func TestDecodeRefusesUnknownLayouts(t *testing.T) {
+	ledger := NewLedger()
+	ledger.AppendStopEvent(testActor, 1)
+	current, _ := json.Marshal(ledger.GetReceipts()[1])
+
+	if _, err := decodeReceipt(current); err != nil {
+		t.Fatalf("current receipt should decode: %v", err)
+	}
+
+	future := strings.Replace(string(current), `"schema_version":2`, `"schema_version":99`, 1)
+	if _, err := decodeReceipt([]byte(future)); err == nil || !strings.Contains(err.Error(), "unsupported schema version 99") {
+		t.Fatalf("future schema should be refused, got %v", err)
+	}
+
+	extra := strings.Replace(string(current), `{`, `{"tenant":"x",`, 1)
+	if _, err := decodeReceipt([]byte(extra)); err == nil {
+		t.Fatal("a tagged receipt with an unknown field should be refused")
+	}
+
+	legacy := canonicalFixture()
+	legacy.CurrentHash = computeHash(legacy)
+	legacy.SchemaVersion = SchemaVersionTagged
+	relabeled, _ := json.Marshal(legacy)
+	if _, err := decodeReceipt(relabeled); err == nil {
+		t.Fatal("an old receipt relabeled with a newer schema should be refused")
+	}
+}
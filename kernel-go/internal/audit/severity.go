@@ -0,0 +1,195 @@
+// WHY: Alerting should not depend on string-matching event types, which
+// grow with every release. Each receipt carries a severity and category
+// assigned at append time from a single table, so "page on critical" keeps
+// working as new events are added.
+package audit
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Severity ranks how urgently a receipt needs attention
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarn     Severity = "warn"
+	SeverityCritical Severity = "critical"
+)
+
+// severityRank orders severities for threshold filtering
+var severityRank = map[Severity]int{
+	SeverityInfo:     1,
+	SeverityWarn:     2,
+	SeverityCritical: 3,
+}
+
+// AtLeast reports whether s is at or above the threshold
+func (s Severity) AtLeast(threshold Severity) bool {
+	return severityRank[s] >= severityRank[threshold]
+}
+
+// Category groups receipts by the corridor stage they describe
+type Category string
+
+const (
+	CategoryDecision   Category = "decision"
+	CategoryCapability Category = "capability"
+	CategoryIntegrity  Category = "integrity"
+	CategoryEgress     Category = "egress"
+)
+
+// classification is the severity and category assigned to a receipt
+type classification struct {
+	severity Severity
+	category Category
+}
+
+// eventCategories assigns each event type its category; unknown types
+// default to integrity so they are never silently uncategorized
+var eventCategories = map[string]Category{
+	"genesis":                CategoryIntegrity,
+	"checkpoint":             CategoryIntegrity,
+	"segment_anchor":         CategoryIntegrity,
+	"compaction_summary":     CategoryIntegrity,
+	"integrity_state_change": CategoryIntegrity,
+	"state_restored":         CategoryIntegrity,
+	"governance_committed":   CategoryIntegrity,
+	"world_update":           CategoryIntegrity,
+	"tamper_detected":        CategoryIntegrity,
+	"namespace_violation":    CategoryIntegrity,
+	"admin_action":           CategoryIntegrity,
+	"sink_delivery":          CategoryIntegrity,
+	"sink_failure":           CategoryIntegrity,
+	"cdi_decision":           CategoryDecision,
+	"authentication":         CategoryDecision,
+	"posture_change":         CategoryDecision,
+	"token_mint":             CategoryCapability,
+	"adapter_attempt":        CategoryCapability,
+	"adapter_exchange":       CategoryCapability,
+	"adapter_write":          CategoryCapability,
+	"adapter_query":          CategoryCapability,
+	"adapter_throttle":       CategoryCapability,
+	"breaker_state_change":   CategoryCapability,
+	"adapter_lifecycle":      CategoryCapability,
+	"memory_write":           CategoryCapability,
+	"memory_read":            CategoryCapability,
+	"memory_promotion":       CategoryCapability,
+	"memory_deletion":        CategoryCapability,
+	"quarantine_closed":      CategoryCapability,
+	"memory_quota_exceeded":  CategoryCapability,
+	"memory_collection":      CategoryCapability,
+	"stop_event":             CategoryCapability,
+	"stop_request":           CategoryCapability,
+	"stop_resume":            CategoryCapability,
+	"revocation_notice":      CategoryCapability,
+	"deadman_trip":           CategoryCapability,
+	"quiesce":                CategoryCapability,
+	"stop_latency":           CategoryCapability,
+	"host_stop":              CategoryCapability,
+	"session_opened":         CategoryCapability,
+	"session_closed":         CategoryCapability,
+	"consent_change":         CategoryCapability,
+	"profile_access":         CategoryCapability,
+	"egress_decision":        CategoryEgress,
+	"declassification":       CategoryEgress,
+}
+
+// classify assigns severity and category from the event type and data.
+// WHY: STOP, tamper, cross-namespace use, and integrity-void are always
+// critical; refusals are warnings; everything else is informational.
+func classify(eventType string, eventData map[string]interface{}) classification {
+	category, ok := eventCategories[eventType]
+	if !ok {
+		category = CategoryIntegrity
+	}
+
+	severity := SeverityInfo
+	switch eventType {
+	case "stop_event", "tamper_detected", "namespace_violation", "deadman_trip":
+		severity = SeverityCritical
+	case "integrity_state_change":
+		switch eventData["new_state"] {
+		case "INTEGRITY_VOID":
+			severity = SeverityCritical
+		case "INTEGRITY_DEGRADED":
+			severity = SeverityWarn
+		}
+	case "cdi_decision", "egress_decision":
+		if eventData["decision"] == "DENY" {
+			severity = SeverityWarn
+		}
+	case "adapter_attempt", "profile_access", "authentication", "stop_request", "admin_action":
+		if eventData["accepted"] == false {
+			severity = SeverityWarn
+		}
+	case "declassification", "sink_failure":
+		severity = SeverityWarn
+	case "posture_change", "state_restored", "stop_resume", "host_stop", "adapter_throttle", "memory_deletion", "memory_quota_exceeded":
+		severity = SeverityWarn
+	case "revocation_notice", "quiesce", "stop_latency":
+		if eventData["delivered"] == false || eventData["drained"] == false || eventData["within_slo"] == false {
+			severity = SeverityWarn
+		}
+	case "breaker_state_change":
+		if eventData["to_state"] == "open" {
+			severity = SeverityWarn
+		}
+	}
+
+	return classification{severity: severity, category: category}
+}
+
+// criticalEventTypePattern is the shape of a custom critical event type
+var criticalEventTypePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+// criticalIDPattern is the shape of an ID or hash a critical event may carry
+var criticalIDPattern = regexp.MustCompile(`^[A-Za-z0-9_.:-]{1,128}$`)
+
+// AppendCriticalEvent logs an event outside the standard event set at
+// critical severity. Its data may hold only IDs and hashes, under keys
+// ending in _id or _hash, and numbers or booleans.
+// WHY: Callers that detect an emergency outside the standard event set
+// must still reach the alerting path, but never by writing a type the
+// chain's own structure is parsed from, nor raw content into the ledger.
+func (l *Ledger) AppendCriticalEvent(actor Attribution, category Category, eventType string, eventData map[string]interface{}) error {
+	if _, ok := map[Category]bool{
+		CategoryDecision: true, CategoryCapability: true, CategoryIntegrity: true, CategoryEgress: true,
+	}[category]; !ok {
+		return fmt.Errorf("unknown receipt category %q", category)
+	}
+	if !criticalEventTypePattern.MatchString(eventType) {
+		return fmt.Errorf("invalid critical event type %q", eventType)
+	}
+	if _, known := eventCategories[eventType]; known {
+		return fmt.Errorf("event type %s is reserved", eventType)
+	}
+
+	data := map[string]interface{}{}
+	for key, value := range eventData {
+		switch v := value.(type) {
+		case bool, int, int64, float64:
+		case string:
+			if !strings.HasSuffix(key, "_id") && !strings.HasSuffix(key, "_hash") {
+				return fmt.Errorf("critical event field %s: only IDs and hashes may be strings", key)
+			}
+			if !criticalIDPattern.MatchString(v) {
+				return fmt.Errorf("critical event field %s is not an ID or hash", key)
+			}
+		default:
+			return fmt.Errorf("critical event field %s: %T is not permitted", key, value)
+		}
+		data[key] = value
+	}
+	l.appendAs(eventType, classification{severity: SeverityCritical, category: category}, actor.annotate(data))
+	return nil
+}
+
+// AppendTamperDetected logs a critical integrity event when verification fails
+func (l *Ledger) AppendTamperDetected(actor Attribution, reason string) {
+	l.append("tamper_detected", actor.annotate(map[string]interface{}{
+		"reason": reason,
+	}))
+}
@@ -0,0 +1,79 @@
+// WHY: These tests prove alert-worthy events are classified critical
+// without consumers matching on event type strings.
+package audit
+
+import "testing"
+
+// TestReceiptsAreClassified proves STOP, tamper, and integrity-void are critical
+func TestReceiptsAreClassified(t *testing.T) {
+	ledger := NewLedger()
+	ledger.AppendCDIDecision(testActor, "ALLOW", "h", "", "d1")
+	ledger.AppendCDIDecision(testActor, "DENY", "h", "", "d2")
+	ledger.AppendStopEvent(testActor, 1)
+	ledger.AppendIntegrityStateChange(testActor, "INTEGRITY_VOID")
+	ledger.AppendTamperDetected(testActor, "receipt 3 hash mismatch")
+	ledger.AppendEgressDecision(testActor, "ALLOW", "out", "output_approved")
+
+	expected := []struct {
+		severity Severity
+		category Category
+	}{
+		{SeverityInfo, CategoryIntegrity}, // genesis
+		{SeverityInfo, CategoryDecision},
+		{SeverityWarn, CategoryDecision},
+		{SeverityCritical, CategoryCapability},
+		{SeverityCritical, CategoryIntegrity},
+		{SeverityCritical, CategoryIntegrity},
+		{SeverityInfo, CategoryEgress},
+	}
+	receipts := ledger.GetReceipts()
+	for i, want := range expected {
+		if receipts[i].Severity != want.severity || receipts[i].Category != want.category {
+			t.Fatalf("receipt %d (%s): got %s/%s, want %s/%s", i, receipts[i].EventType,
+				receipts[i].Severity, receipts[i].Category, want.severity, want.category)
+		}
+	}
+
+	page, _ := ledger.Query(ReceiptFilter{MinSeverity: SeverityCritical})
+	if len(page.Receipts) != 3 {
+		t.Fatalf("expected 3 critical receipts, got %d", len(page.Receipts))
+	}
+	if valid, err := ledger.Verify(); !valid {
+		t.Fatalf("classified ledger should verify: %v", err)
+	}
+}
+
+// TestAppendCriticalEvent proves custom emergencies reach the critical
+// path, and can neither use a known event type nor carry raw content
+func TestAppendCriticalEvent(t *testing.T) {
+	ledger := NewLedger()
+	if err := ledger.AppendCriticalEvent(testActor, CategoryEgress, "leak_budget_exhausted", map[string]interface{}{"used": 10001}); err != nil {
+		t.Fatalf("append critical: %v", err)
+	}
+	receipts := ledger.GetReceipts()
+	last := receipts[len(receipts)-1]
+	if last.Severity != SeverityCritical || last.Category != CategoryEgress || last.EventData["principal_id"] != testActor.PrincipalID {
+		t.Fatalf("unexpected critical receipt: %+v", last)
+	}
+
+	if err := ledger.AppendCriticalEvent(testActor, "billing", "x", nil); err == nil {
+		t.Fatal("unknown categories must be rejected")
+	}
+	for _, eventType := range []string{"checkpoint", "segment_anchor", "compaction_summary", "genesis", "stop_event", "Leak Budget"} {
+		if err := ledger.AppendCriticalEvent(testActor, CategoryIntegrity, eventType, nil); err == nil {
+			t.Fatalf("event type %q must be rejected", eventType)
+		}
+	}
+	for _, data := range []map[string]interface{}{
+		{"message": "raw user text"},
+		{"adapter_id": "not an id at all"},
+		{"detail": map[string]interface{}{"nested": true}},
+	} {
+		if err := ledger.AppendCriticalEvent(testActor, CategoryEgress, "leak_budget_exhausted", data); err == nil {
+			t.Fatalf("data %v must be rejected", data)
+		}
+	}
+	if len(ledger.GetReceipts()) != len(receipts) || len(ledger.Checkpoints()) != 0 {
+		t.Fatal("a rejected critical event must append nothing")
+	}
+}
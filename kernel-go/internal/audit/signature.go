@@ -0,0 +1,142 @@
+// WHY: A hash chain only proves internal consistency; anyone who can
+// rewrite the whole chain can recompute every hash. Signing each receipt
+// hash with a kernel key means a consistent forgery also needs the private
+// key, which never leaves the signer.
+package audit
+
+import (
+	"crypto"
+	"fmt"
+
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+// receiptSignatureDomain separates receipt signatures from every other
+// message a kernel key might sign
+const receiptSignatureDomain = "oi.audit_receipt.v1|"
+
+// receiptSigningMessage is the message signed for a receipt
+func receiptSigningMessage(currentHash string) []byte {
+	return []byte(receiptSignatureDomain + currentHash)
+}
+
+// SetSigner signs every receipt appended from now on and trusts the
+// signer's public key when verifying.
+// WHY: Receipts signed before a key rotation stay verifiable because the
+// old key remains trusted.
+func (l *Ledger) SetSigner(signer signing.Signer) error {
+	if signer == nil {
+		return fmt.Errorf("nil receipt signer")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.trustKeyLocked(signer.KeyID(), signer.Public()); err != nil {
+		return err
+	}
+	l.signer = signer
+	if l.signedFrom < 0 {
+		l.signedFrom = l.sequence + 1
+	}
+	return nil
+}
+
+// TrustKey trusts a public key for receipts signed by an earlier process,
+// such as the previous run of a persisted ledger.
+func (l *Ledger) TrustKey(keyID string, public crypto.PublicKey) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.trustKeyLocked(keyID, public)
+}
+
+// trustKeyLocked adds a key to the ledger's key ring. Callers must hold l.mu.
+func (l *Ledger) trustKeyLocked(keyID string, public crypto.PublicKey) error {
+	if l.keys == nil {
+		l.keys = signing.NewKeyRing()
+	}
+	return l.keys.Add(keyID, public)
+}
+
+// signLocked signs a receipt with the current signer, if any.
+// Callers must hold l.mu.
+func (l *Ledger) signLocked(receipt *Receipt) error {
+	if l.signer == nil {
+		return nil
+	}
+
+	signature, err := l.signer.Sign(receiptSigningMessage(receipt.CurrentHash))
+	if err != nil {
+		return err
+	}
+	receipt.Signature = signature
+	receipt.SignerKeyID = l.signer.KeyID()
+	return nil
+}
+
+// verifySignaturesLocked checks every receipt from the first signed
+// sequence onward, and every receipt after genesis once a key is trusted
+// for loaded history or a ledger never signed in this process. Callers
+// must hold l.mu.
+// WHY: Once signing starts, an unsigned receipt is a forgery, not an
+// omission; stripping signatures must not downgrade verification. A
+// signature is not part of the hash, so a chain rewritten with every
+// signature stripped looks like one never signed; only receipts this
+// process appended before its signer was set may go unsigned.
+func (l *Ledger) verifySignaturesLocked() error {
+	signedFrom := l.signedFrom
+	if l.keys != nil && (l.loaded || signedFrom < 0) {
+		signedFrom = 1
+	}
+	if signedFrom < 0 {
+		return nil
+	}
+	if l.keys == nil {
+		return fmt.Errorf("ledger is signed but no receipt keys are trusted")
+	}
+
+	for _, receipt := range l.receipts {
+		if receipt.Sequence < signedFrom {
+			continue
+		}
+		if err := verifyReceiptSignature(receipt, l.keys); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyReceiptSignatures checks that every receipt after genesis carries a
+// valid signature from a trusted key.
+// WHY: External auditors pin the kernel's public keys and need no access
+// to the running ledger to reject a forged history.
+func VerifyReceiptSignatures(receipts []Receipt, keys *signing.KeyRing) error {
+	if keys == nil {
+		return fmt.Errorf("no trusted receipt keys")
+	}
+	if err := verifyChain(receipts); err != nil {
+		return err
+	}
+
+	for _, receipt := range receipts {
+		if receipt.EventType == "genesis" {
+			continue
+		}
+		if err := verifyReceiptSignature(receipt, keys); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyReceiptSignature checks one receipt's signature against trusted keys
+func verifyReceiptSignature(receipt Receipt, keys *signing.KeyRing) error {
+	if len(receipt.Signature) == 0 {
+		return fmt.Errorf("receipt %d is not signed", receipt.Sequence)
+	}
+	if err := keys.Verify(receipt.SignerKeyID, receiptSigningMessage(receipt.CurrentHash), receipt.Signature); err != nil {
+		return fmt.Errorf("receipt %d signature invalid: %w", receipt.Sequence, err)
+	}
+	return nil
+}
@@ -0,0 +1,325 @@
+// WHY: A tamper-evident log that only lives on the host can still be erased
+// by whoever compromises the host. Forwarders copy every receipt to remote
+// sinks (syslog, webhooks, Kafka) in order, retrying until each batch is
+// acknowledged, so an off-box copy of the chain exists. The ledger itself
+// is the buffer: a forwarder tracks a sequence cursor and re-reads receipts
+// with Query, so a slow or unreachable sink never loses or reorders them.
+// The kernel ledgers each delivery and each outage, so the chain itself
+// records how far its off-box copies reach.
+package audit
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// AuditSink delivers receipts to a remote destination
+type AuditSink interface {
+	// Name identifies the sink in delivery receipts and status
+	Name() string
+
+	// Deliver sends a batch of receipts in sequence order. It returns nil
+	// only once the destination has accepted the whole batch; a batch that
+	// fails is retried in full, so sinks may see duplicates but never gaps.
+	Deliver(receipts []Receipt) error
+}
+
+// DeliveryReceipt acknowledges one batch accepted by a sink
+type DeliveryReceipt struct {
+	Sink          string
+	StartSequence int64
+	EndSequence   int64
+
+	// LastHash is the CurrentHash of the last receipt delivered; the remote
+	// copy can be verified against it
+	LastHash string
+
+	// Attempts is how many deliveries the batch took
+	Attempts    int
+	DeliveredAt int64
+
+	// Bookkeeping reports the batch held only sink_delivery and
+	// sink_failure receipts
+	Bookkeeping bool
+}
+
+// DeliveryFailure reports one failed delivery attempt
+type DeliveryFailure struct {
+	Sink string
+
+	// StartSequence is the first receipt of the batch that failed
+	StartSequence int64
+
+	// Attempt is the failed attempt's number for the batch, 0 when the
+	// ledger could not be read and forwarding stopped
+	Attempt int
+	Error   string
+}
+
+// sinkEventTypes are the receipts ledgering deliveries and failures adds
+var sinkEventTypes = map[string]bool{
+	"sink_delivery": true,
+	"sink_failure":  true,
+}
+
+// ForwardPolicy tunes batching and retry for a forwarder
+type ForwardPolicy struct {
+	// FromSequence resumes delivery after a restart; 0 sends the held chain
+	FromSequence int64
+
+	// BatchSize caps receipts per delivery (default 100, at most MaxQueryLimit)
+	BatchSize int
+
+	// MinBackoff and MaxBackoff bound the exponential retry delay
+	// (defaults 100ms and 30s)
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// OnDelivery, if set, is called after each acknowledged batch
+	OnDelivery func(DeliveryReceipt)
+
+	// OnFailure, if set, is called after each failed delivery attempt
+	OnFailure func(DeliveryFailure)
+}
+
+// ForwarderStatus reports a forwarder's progress
+type ForwarderStatus struct {
+	Sink string
+
+	// NextSequence is the first receipt not yet acknowledged
+	NextSequence int64
+
+	LastDelivery DeliveryReceipt
+	Delivered    int64
+
+	// Failures counts failed delivery attempts; LastError is the most recent
+	Failures  int64
+	LastError string
+
+	// Missed counts receipts dropped from memory by rotation or compaction
+	// before they could be delivered; recover them from the archive
+	Missed int64
+}
+
+// Forwarder copies ledger receipts to one sink
+type Forwarder struct {
+	ledger *Ledger
+	sink   AuditSink
+	policy ForwardPolicy
+	sub    *Subscription
+
+	mu     sync.Mutex
+	status ForwarderStatus
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// forwardWakeups buffers new-receipt notifications; overflow is harmless
+// because the forwarder re-reads the ledger from its cursor
+const forwardWakeups = 64
+
+// Forward starts copying receipts to sink in the background
+func (l *Ledger) Forward(sink AuditSink, policy ForwardPolicy) (*Forwarder, error) {
+	if sink == nil {
+		return nil, fmt.Errorf("nil audit sink")
+	}
+	if policy.BatchSize == 0 {
+		policy.BatchSize = DefaultQueryLimit
+	}
+	if policy.BatchSize < 0 || policy.BatchSize > MaxQueryLimit {
+		return nil, fmt.Errorf("forward batch size %d out of range 1-%d", policy.BatchSize, MaxQueryLimit)
+	}
+	if policy.MinBackoff <= 0 {
+		policy.MinBackoff = 100 * time.Millisecond
+	}
+	if policy.MaxBackoff < policy.MinBackoff {
+		policy.MaxBackoff = 30 * time.Second
+	}
+
+	sub, err := l.Subscribe(forwardWakeups)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Forwarder{
+		ledger: l,
+		sink:   sink,
+		policy: policy,
+		sub:    sub,
+		status: ForwarderStatus{Sink: sink.Name(), NextSequence: policy.FromSequence},
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go f.run()
+	return f, nil
+}
+
+// Status returns a snapshot of the forwarder's progress
+func (f *Forwarder) Status() ForwarderStatus {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.status
+}
+
+// Stop halts forwarding; an in-flight delivery finishes or is abandoned
+// between retries
+func (f *Forwarder) Stop() {
+	f.stopOnce.Do(func() {
+		close(f.stop)
+		f.sub.Close()
+	})
+	<-f.done
+}
+
+func (f *Forwarder) run() {
+	defer close(f.done)
+
+	for {
+		f.mu.Lock()
+		next := f.status.NextSequence
+		f.mu.Unlock()
+
+		page, err := f.ledger.Query(ReceiptFilter{FromSequence: next, Limit: f.policy.BatchSize})
+		if err != nil {
+			f.recordFailure(next, 0, err)
+			return
+		}
+
+		if len(page.Receipts) == 0 {
+			// Caught up: wait for the next append
+			select {
+			case _, open := <-f.sub.Receipts():
+				if !open {
+					return
+				}
+			case <-f.stop:
+				return
+			}
+			continue
+		}
+
+		if first := page.Receipts[0].Sequence; first > next {
+			f.mu.Lock()
+			f.status.Missed += first - next
+			f.status.LastError = fmt.Sprintf("receipts %d-%d left memory before delivery", next, first-1)
+			f.mu.Unlock()
+		}
+
+		if !f.deliver(page.Receipts) {
+			return
+		}
+	}
+}
+
+// deliver retries a batch until the sink accepts it; it returns false if
+// the forwarder was stopped first
+func (f *Forwarder) deliver(batch []Receipt) bool {
+	backoff := f.policy.MinBackoff
+	for attempt := 1; ; attempt++ {
+		err := f.sink.Deliver(batch)
+		if err == nil {
+			f.recordDelivery(batch, attempt)
+			return true
+		}
+		f.recordFailure(batch[0].Sequence, attempt, err)
+
+		select {
+		case <-time.After(backoff):
+		case <-f.stop:
+			return false
+		}
+		backoff *= 2
+		if backoff > f.policy.MaxBackoff {
+			backoff = f.policy.MaxBackoff
+		}
+	}
+}
+
+func (f *Forwarder) recordDelivery(batch []Receipt, attempts int) {
+	last := batch[len(batch)-1]
+	delivery := DeliveryReceipt{
+		Sink:          f.sink.Name(),
+		StartSequence: batch[0].Sequence,
+		EndSequence:   last.Sequence,
+		LastHash:      last.CurrentHash,
+		Attempts:      attempts,
+		DeliveredAt:   time.Now().Unix(),
+		Bookkeeping:   true,
+	}
+	for _, receipt := range batch {
+		if !sinkEventTypes[receipt.EventType] {
+			delivery.Bookkeeping = false
+			break
+		}
+	}
+
+	f.mu.Lock()
+	f.status.NextSequence = last.Sequence + 1
+	f.status.LastDelivery = delivery
+	f.status.Delivered += int64(len(batch))
+	f.mu.Unlock()
+
+	if f.policy.OnDelivery != nil {
+		f.policy.OnDelivery(delivery)
+	}
+}
+
+func (f *Forwarder) recordFailure(from int64, attempt int, err error) {
+	f.mu.Lock()
+	f.status.Failures++
+	f.status.LastError = err.Error()
+	f.mu.Unlock()
+
+	if f.policy.OnFailure != nil {
+		f.policy.OnFailure(DeliveryFailure{Sink: f.sink.Name(), StartSequence: from, Attempt: attempt, Error: err.Error()})
+	}
+}
+
+// AppendSinkDelivery logs a batch acknowledged by a sink
+func (l *Ledger) AppendSinkDelivery(actor Attribution, delivery DeliveryReceipt) {
+	l.append("sink_delivery", actor.annotate(map[string]interface{}{
+		"sink":           delivery.Sink,
+		"start_sequence": delivery.StartSequence,
+		"end_sequence":   delivery.EndSequence,
+		"last_hash":      delivery.LastHash,
+		"attempts":       delivery.Attempts,
+	}))
+}
+
+// AppendSinkFailure logs a failed delivery attempt to a sink
+func (l *Ledger) AppendSinkFailure(actor Attribution, failure DeliveryFailure) {
+	l.append("sink_failure", actor.annotate(map[string]interface{}{
+		"sink":           failure.Sink,
+		"start_sequence": failure.StartSequence,
+		"attempt":        failure.Attempt,
+		"last_error":     failure.Error,
+	}))
+}
+
+// LastDelivered is the sequence after the last batch the ledger records
+// sink accepting, or 0 if it records none; it resumes a forwarder to sink
+func (l *Ledger) LastDelivered(sink string) (int64, error) {
+	var from int64
+	filter := ReceiptFilter{EventTypes: []string{"sink_delivery"}, Limit: MaxQueryLimit}
+	for {
+		page, err := l.Query(filter)
+		if err != nil {
+			return 0, err
+		}
+		for _, receipt := range page.Receipts {
+			if receipt.EventData["sink"] != sink {
+				continue
+			}
+			if end := toInt64(receipt.EventData["end_sequence"]); end >= from {
+				from = end + 1
+			}
+		}
+		if !page.HasMore {
+			return from, nil
+		}
+		filter.FromSequence = page.NextSequence
+	}
+}
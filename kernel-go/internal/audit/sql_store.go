@@ -0,0 +1,249 @@
+// WHY: Large deployments need to retain and query years of governance
+// history. A SQL store keeps receipts in SQLite or Postgres with indexed
+// columns while the hash chain stays the source of truth.
+package audit
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Dialect captures the SQL differences between supported databases
+type Dialect int
+
+const (
+	// DialectSQLite uses "?" placeholders
+	DialectSQLite Dialect = iota
+	// DialectPostgres uses "$n" placeholders
+	DialectPostgres
+)
+
+// placeholder returns the n-th (1-based) bind parameter marker
+func (d Dialect) placeholder(n int) string {
+	if d == DialectPostgres {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+var tableNamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+
+// SQLStore persists receipts in a relational table.
+// The caller owns the *sql.DB and imports the driver (e.g. modernc.org/sqlite
+// or github.com/jackc/pgx/v5/stdlib); the kernel itself stays driver-free.
+type SQLStore struct {
+	db      *sql.DB
+	dialect Dialect
+	table   string
+}
+
+// sqlMigration adds the columns one store version introduced, with the
+// value they take on rows written before it
+type sqlMigration struct {
+	version int
+	columns []sqlColumn
+}
+
+// sqlColumn is one column a migration adds
+type sqlColumn struct {
+	name       string
+	definition string
+}
+
+// sqlMigrations bring a table written by an older store up to date, in
+// version order. Version 1 is the original table.
+// WHY: CREATE TABLE IF NOT EXISTS leaves an existing table as it was, so
+// every column added since must be added to it, defaulting to what rows
+// written before it meant: unsigned, hashed under the legacy scheme,
+// unclassified, and schema 1.
+var sqlMigrations = []sqlMigration{
+	{version: 2, columns: []sqlColumn{
+		{"signature", "TEXT NOT NULL DEFAULT ''"},
+		{"signer_key", "TEXT NOT NULL DEFAULT ''"},
+	}},
+	{version: 3, columns: []sqlColumn{
+		{"hash_version", fmt.Sprintf("INTEGER NOT NULL DEFAULT %d", HashVersionLegacy)},
+	}},
+	{version: 4, columns: []sqlColumn{
+		{"severity", "TEXT NOT NULL DEFAULT ''"},
+		{"category", "TEXT NOT NULL DEFAULT ''"},
+	}},
+	{version: 5, columns: []sqlColumn{
+		{"schema_version", fmt.Sprintf("INTEGER NOT NULL DEFAULT %d", SchemaVersionUnversioned)},
+	}},
+}
+
+// OpenSQLStore creates the receipt table and indexes if they do not exist,
+// and migrates a table an older store created before it is used
+func OpenSQLStore(db *sql.DB, dialect Dialect, table string) (*SQLStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("nil database handle")
+	}
+	if !tableNamePattern.MatchString(table) {
+		return nil, fmt.Errorf("invalid ledger table name %q", table)
+	}
+
+	store := &SQLStore{db: db, dialect: dialect, table: table}
+
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS ` + table + ` (
+			sequence     BIGINT PRIMARY KEY,
+			timestamp    BIGINT NOT NULL,
+			event_type   TEXT NOT NULL,
+			principal    TEXT NOT NULL,
+			token_digest TEXT NOT NULL,
+			event_data   TEXT NOT NULL,
+			prev_hash    TEXT NOT NULL,
+			current_hash TEXT NOT NULL,
+			signature    TEXT NOT NULL,
+			signer_key   TEXT NOT NULL,
+			hash_version INTEGER NOT NULL,
+			severity     TEXT NOT NULL,
+			category     TEXT NOT NULL,
+			schema_version INTEGER NOT NULL
+		)`); err != nil {
+		return nil, fmt.Errorf("create ledger schema: %w", err)
+	}
+	if err := store.migrate(); err != nil {
+		return nil, err
+	}
+
+	statements := []string{
+		`CREATE INDEX IF NOT EXISTS ` + table + `_event_type_idx ON ` + table + ` (event_type)`,
+		`CREATE INDEX IF NOT EXISTS ` + table + `_principal_idx ON ` + table + ` (principal)`,
+		`CREATE INDEX IF NOT EXISTS ` + table + `_token_digest_idx ON ` + table + ` (token_digest)`,
+		`CREATE INDEX IF NOT EXISTS ` + table + `_severity_idx ON ` + table + ` (severity)`,
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			return nil, fmt.Errorf("create ledger schema: %w", err)
+		}
+	}
+
+	return store, nil
+}
+
+// migrate applies every migration whose columns the table lacks
+func (s *SQLStore) migrate() error {
+	for _, migration := range sqlMigrations {
+		for _, column := range migration.columns {
+			if s.hasColumn(column.name) {
+				continue
+			}
+			if _, err := s.db.Exec(`ALTER TABLE ` + s.table + ` ADD COLUMN ` + column.name + ` ` + column.definition); err != nil {
+				return fmt.Errorf("migrate ledger schema to version %d: %w", migration.version, err)
+			}
+		}
+	}
+	return nil
+}
+
+// hasColumn reports whether the table has a column, by selecting it.
+// WHY: SQLite and Postgres describe their tables differently; both fail a
+// query naming a column that does not exist.
+func (s *SQLStore) hasColumn(name string) bool {
+	rows, err := s.db.Query(`SELECT ` + name + ` FROM ` + s.table + ` WHERE 1 = 0`)
+	if err != nil {
+		return false
+	}
+	rows.Close()
+	return true
+}
+
+// Append inserts one receipt row.
+// WHY: The sequence primary key makes the table append-only in practice:
+// a second writer reusing a sequence number fails instead of forking.
+func (s *SQLStore) Append(receipt Receipt) error {
+	eventData, err := json.Marshal(receipt.EventData)
+	if err != nil {
+		return fmt.Errorf("encode receipt %d: %w", receipt.Sequence, err)
+	}
+
+	placeholders := make([]string, 14)
+	for i := range placeholders {
+		placeholders[i] = s.dialect.placeholder(i + 1)
+	}
+
+	query := `INSERT INTO ` + s.table +
+		` (sequence, timestamp, event_type, principal, token_digest, event_data, prev_hash, current_hash, signature, signer_key, hash_version, severity, category, schema_version) VALUES (` +
+		strings.Join(placeholders, ", ") + `)`
+
+	_, err = s.db.Exec(query,
+		receipt.Sequence,
+		receipt.Timestamp,
+		receipt.EventType,
+		indexedString(receipt, "principal_id"),
+		indexedString(receipt, "token_digest"),
+		string(eventData),
+		receipt.PrevHash,
+		receipt.CurrentHash,
+		hex.EncodeToString(receipt.Signature),
+		receipt.SignerKeyID,
+		receipt.HashVersion,
+		string(receipt.Severity),
+		string(receipt.Category),
+		schemaOf(receipt),
+	)
+	if err != nil {
+		return fmt.Errorf("insert receipt %d: %w", receipt.Sequence, err)
+	}
+	return nil
+}
+
+// Load reads every receipt ordered by sequence
+func (s *SQLStore) Load() ([]Receipt, error) {
+	rows, err := s.db.Query(`SELECT sequence, timestamp, event_type, event_data, prev_hash, current_hash, signature, signer_key, hash_version, severity, category, schema_version FROM ` +
+		s.table + ` ORDER BY sequence`)
+	if err != nil {
+		return nil, fmt.Errorf("query receipts: %w", err)
+	}
+	defer rows.Close()
+
+	receipts := []Receipt{}
+	for rows.Next() {
+		var receipt Receipt
+		var eventData, signature string
+		if err := rows.Scan(&receipt.Sequence, &receipt.Timestamp, &receipt.EventType,
+			&eventData, &receipt.PrevHash, &receipt.CurrentHash, &signature, &receipt.SignerKeyID, &receipt.HashVersion,
+			&receipt.Severity, &receipt.Category, &receipt.SchemaVersion); err != nil {
+			return nil, fmt.Errorf("scan receipt: %w", err)
+		}
+		if err := checkSchema(receipt); err != nil {
+			return nil, fmt.Errorf("receipt %d: %w", receipt.Sequence, err)
+		}
+		if signature != "" {
+			decoded, err := hex.DecodeString(signature)
+			if err != nil {
+				return nil, fmt.Errorf("receipt %d signature: %w", receipt.Sequence, err)
+			}
+			receipt.Signature = decoded
+		}
+
+		data, err := decodeEventData([]byte(eventData))
+		if err != nil {
+			return nil, fmt.Errorf("receipt %d: %w", receipt.Sequence, err)
+		}
+		receipt.EventData = data
+		receipts = append(receipts, receipt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate receipts: %w", err)
+	}
+
+	return receipts, nil
+}
+
+// Close is a no-op; the caller owns the database handle
+func (s *SQLStore) Close() error {
+	return nil
+}
+
+// indexedString extracts a string field for an indexed column
+func indexedString(receipt Receipt, field string) string {
+	value, _ := receipt.EventData[field].(string)
+	return value
+}
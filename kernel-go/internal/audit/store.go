@@ -0,0 +1,176 @@
+// WHY: An in-memory-only ledger means every restart silently destroys the
+// governance record. Stores persist receipts outside the process so the
+// hash chain survives restarts and can be re-verified on startup.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Store persists receipts for a Ledger.
+// WHY: Backends are append-only by contract; there is no update or delete.
+type Store interface {
+	// Append durably records a receipt before returning
+	Append(receipt Receipt) error
+
+	// Load returns every stored receipt in sequence order
+	Load() ([]Receipt, error)
+
+	// Close releases the backend
+	Close() error
+}
+
+// FileStore appends receipts to a JSONL file, one receipt per line, and
+// fsyncs after every write.
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// OpenFileStore opens (or creates) a JSONL receipt file for appending
+func OpenFileStore(path string) (*FileStore, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open ledger file: %w", err)
+	}
+	return &FileStore{path: path, file: file}, nil
+}
+
+// Append writes one receipt line and syncs it to stable storage
+func (f *FileStore) Append(receipt Receipt) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return fmt.Errorf("ledger file %s is closed", f.path)
+	}
+
+	line, err := json.Marshal(receipt)
+	if err != nil {
+		return fmt.Errorf("encode receipt %d: %w", receipt.Sequence, err)
+	}
+	line = append(line, '\n')
+
+	if _, err := f.file.Write(line); err != nil {
+		return fmt.Errorf("write receipt %d: %w", receipt.Sequence, err)
+	}
+	if err := f.file.Sync(); err != nil {
+		return fmt.Errorf("sync receipt %d: %w", receipt.Sequence, err)
+	}
+	return nil
+}
+
+// Load reads every receipt from the file
+func (f *FileStore) Load() ([]Receipt, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil, fmt.Errorf("ledger file %s is closed", f.path)
+	}
+	if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("seek ledger file: %w", err)
+	}
+
+	receipts, err := ReadReceipts(f.file)
+	if err != nil {
+		return nil, fmt.Errorf("ledger file %s: %w", f.path, err)
+	}
+	return receipts, nil
+}
+
+// ReadReceipts reads JSONL receipts, e.g. a ledger file or a sink's copy.
+// WHY: A truncated or malformed line means the record was damaged; it is
+// reported rather than skipped so the chain is never silently shortened.
+func ReadReceipts(r io.Reader) ([]Receipt, error) {
+	receipts := []Receipt{}
+	reader := bufio.NewReader(r)
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			if len(bytes.TrimSpace(line)) != 0 {
+				return nil, fmt.Errorf("line %d is truncated", lineNumber)
+			}
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read receipts: %w", err)
+		}
+
+		receipt, err := decodeReceipt(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		receipts = append(receipts, receipt)
+	}
+
+	return receipts, nil
+}
+
+// Close closes the underlying file
+func (f *FileStore) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// ReadOnlyStore holds receipts already read, for a ledger opened only to
+// verify or read them; nothing can be appended to it
+type ReadOnlyStore []Receipt
+
+// Append refuses every receipt
+func (s ReadOnlyStore) Append(Receipt) error {
+	return fmt.Errorf("ledger is open read-only")
+}
+
+// Load returns the receipts held
+func (s ReadOnlyStore) Load() ([]Receipt, error) { return s, nil }
+
+// Close is a no-op
+func (s ReadOnlyStore) Close() error { return nil }
+
+// ReadFileLedger opens the JSONL receipt file at path read-only and
+// verifies its chain. A missing or empty file is an error.
+// WHY: Inspecting a ledger must not change it: opening one for appending
+// would create a missing file and write a genesis receipt into it, and
+// then report that fresh file as verified.
+func ReadFileLedger(path string) (*Ledger, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open ledger file: %w", err)
+	}
+	defer file.Close()
+
+	receipts, err := ReadReceipts(file)
+	if err != nil {
+		return nil, fmt.Errorf("ledger file %s: %w", path, err)
+	}
+	if len(receipts) == 0 {
+		return nil, fmt.Errorf("ledger file %s holds no receipts", path)
+	}
+	return OpenLedger(ReadOnlyStore(receipts))
+}
+
+// decodeEventData parses a JSON event data object with json.Number values
+func decodeEventData(data []byte) (map[string]interface{}, error) {
+	var eventData map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&eventData); err != nil {
+		return nil, fmt.Errorf("decode event data: %w", err)
+	}
+	return eventData, nil
+}
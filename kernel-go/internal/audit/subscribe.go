@@ -0,0 +1,77 @@
+// WHY: Monitors and SIEM forwarders need receipts as they happen, not by
+// polling. Delivery never blocks the append path: a slow subscriber loses
+// receipts (and is told how many) instead of stalling the corridor. The
+// chain itself stays complete, so a lagging consumer can backfill via Query.
+package audit
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// MaxSubscriptionBuffer caps the per-subscriber buffer
+const MaxSubscriptionBuffer = 65536
+
+// Subscription receives receipts appended after it was created
+type Subscription struct {
+	ledger  *Ledger
+	id      uint64
+	ch      chan Receipt
+	dropped atomic.Int64
+}
+
+// Subscribe registers a subscriber with a bounded buffer of receipts
+func (l *Ledger) Subscribe(buffer int) (*Subscription, error) {
+	if buffer <= 0 || buffer > MaxSubscriptionBuffer {
+		return nil, fmt.Errorf("subscription buffer %d out of range 1-%d", buffer, MaxSubscriptionBuffer)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.subscribers == nil {
+		l.subscribers = make(map[uint64]*Subscription)
+	}
+	l.nextSubscriberID++
+	sub := &Subscription{
+		ledger: l,
+		id:     l.nextSubscriberID,
+		ch:     make(chan Receipt, buffer),
+	}
+	l.subscribers[sub.id] = sub
+	return sub, nil
+}
+
+// Receipts returns the delivery channel; it is closed by Close
+func (s *Subscription) Receipts() <-chan Receipt {
+	return s.ch
+}
+
+// Dropped returns how many receipts were discarded because the buffer was full
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// Close unregisters the subscription and closes its channel
+func (s *Subscription) Close() {
+	s.ledger.mu.Lock()
+	defer s.ledger.mu.Unlock()
+
+	if _, active := s.ledger.subscribers[s.id]; !active {
+		return
+	}
+	delete(s.ledger.subscribers, s.id)
+	close(s.ch)
+}
+
+// publishLocked offers a receipt to every subscriber without blocking.
+// Callers must hold l.mu, which also serializes against Close.
+func (l *Ledger) publishLocked(receipt Receipt) {
+	for _, sub := range l.subscribers {
+		select {
+		case sub.ch <- receipt:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// WHY: These tests prove subscribers see new receipts in order and that a
+// full buffer drops with accounting instead of blocking appends.
+package audit
+
+import "testing"
+
+// TestSubscribeDeliversNewReceipts proves live delivery in sequence order
+func TestSubscribeDeliversNewReceipts(t *testing.T) {
+	ledger := NewLedger()
+	ledger.AppendStopEvent(testActor, 0)
+
+	sub, err := ledger.Subscribe(8)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	ledger.AppendCDIDecision(testActor, "DENY", "h", "", "d1")
+	ledger.AppendStopEvent(testActor, 1)
+
+	first := <-sub.Receipts()
+	second := <-sub.Receipts()
+	if first.EventType != "cdi_decision" || second.Sequence != first.Sequence+1 {
+		t.Fatalf("unexpected delivery: %v then %v", first, second)
+	}
+
+	sub.Close()
+	sub.Close()
+	if _, open := <-sub.Receipts(); open {
+		t.Fatal("closed subscription channel should be closed")
+	}
+	ledger.AppendStopEvent(testActor, 2) // must not panic on a closed subscriber
+}
+
+// TestSlowSubscriberDropsInsteadOfBlocking proves appends never wait on subscribers
+func TestSlowSubscriberDropsInsteadOfBlocking(t *testing.T) {
+	ledger := NewLedger()
+	sub, err := ledger.Subscribe(2)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	defer sub.Close()
+
+	for i := 0; i < 5; i++ {
+		ledger.AppendStopEvent(testActor, i)
+	}
+
+	if sub.Dropped() != 3 {
+		t.Fatalf("expected 3 dropped receipts, got %d", sub.Dropped())
+	}
+	if len(ledger.GetReceipts()) != 6 {
+		t.Fatal("drops must not affect the chain")
+	}
+
+	if _, err := ledger.Subscribe(0); err == nil {
+		t.Fatal("unbuffered subscriptions must be rejected")
+	}
+}
@@ -0,0 +1,111 @@
+// WHY: Syslog collectors are the lowest common denominator of off-box
+// logging. Receipts go out as RFC 5424 messages with the JSON receipt as
+// the body, so the collector's copy can be re-verified hash by hash.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// syslogFacility is LOG_AUDIT (13)
+const syslogFacility = 13
+
+// syslogSeverity maps receipt severity onto syslog severities
+var syslogSeverity = map[Severity]int{
+	SeverityInfo:     6, // informational
+	SeverityWarn:     4, // warning
+	SeverityCritical: 2, // critical
+}
+
+// SyslogSink sends receipts to a syslog collector over UDP or TCP.
+// TCP uses RFC 6587 octet-counting framing.
+type SyslogSink struct {
+	network  string
+	address  string
+	hostname string
+	timeout  time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSyslogSink creates a sink for network ("udp" or "tcp") and address
+func NewSyslogSink(network, address string) (*SyslogSink, error) {
+	if network != "udp" && network != "tcp" {
+		return nil, fmt.Errorf("unsupported syslog network %q (want udp or tcp)", network)
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	return &SyslogSink{network: network, address: address, hostname: hostname, timeout: 10 * time.Second}, nil
+}
+
+// Name identifies the sink
+func (s *SyslogSink) Name() string {
+	return "syslog:" + s.network + "://" + s.address
+}
+
+// Deliver writes one syslog message per receipt
+func (s *SyslogSink) Deliver(receipts []Receipt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		conn, err := net.DialTimeout(s.network, s.address, s.timeout)
+		if err != nil {
+			return fmt.Errorf("syslog dial: %w", err)
+		}
+		s.conn = conn
+	}
+
+	for _, receipt := range receipts {
+		message, err := s.format(receipt)
+		if err != nil {
+			return err
+		}
+		if s.network == "tcp" {
+			message = []byte(fmt.Sprintf("%d %s", len(message), message))
+		}
+
+		s.conn.SetWriteDeadline(time.Now().Add(s.timeout))
+		if _, err := s.conn.Write(message); err != nil {
+			// Reconnect on the retry
+			s.conn.Close()
+			s.conn = nil
+			return fmt.Errorf("syslog write receipt %d: %w", receipt.Sequence, err)
+		}
+	}
+	return nil
+}
+
+// format renders an RFC 5424 message: PRI, version, timestamp, host,
+// app-name, procid, msgid (event type), no structured data, JSON body
+func (s *SyslogSink) format(receipt Receipt) ([]byte, error) {
+	body, err := json.Marshal(receipt)
+	if err != nil {
+		return nil, fmt.Errorf("encode receipt %d: %w", receipt.Sequence, err)
+	}
+	priority := syslogFacility*8 + syslogSeverity[receiptSeverity(receipt)]
+	timestamp := time.Unix(receipt.Timestamp, 0).UTC().Format(time.RFC3339)
+	return []byte(fmt.Sprintf("<%d>1 %s %s oi-kernel - %s - %s",
+		priority, timestamp, s.hostname, receipt.EventType, body)), nil
+}
+
+// Close closes the collector connection
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
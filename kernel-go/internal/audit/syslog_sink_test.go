@@ -0,0 +1,74 @@
+// WHY: Proves receipts reach a syslog collector as RFC 5424 messages.
+package audit
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestSyslogSinkTCPFraming proves TCP delivery uses octet counting and
+// carries priority, event type, and the JSON receipt
+func TestSyslogSinkTCPFraming(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+
+	messages := make(chan string, 2)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for {
+			length, err := reader.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(length))
+			buf := make([]byte, n)
+			if _, err := io.ReadFull(reader, buf); err != nil {
+				return
+			}
+			messages <- string(buf)
+		}
+	}()
+
+	sink, err := NewSyslogSink("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("syslog sink: %v", err)
+	}
+	defer sink.Close()
+
+	ledger := NewLedger()
+	ledger.AppendStopEvent(testActor, 1)
+	if err := sink.Deliver(ledger.GetReceipts()); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+
+	<-messages // genesis
+	stop := <-messages
+	// LOG_AUDIT (13) * 8 + critical (2)
+	if !strings.HasPrefix(stop, "<106>1 ") || !strings.Contains(stop, " oi-kernel - stop_event - {") {
+		t.Fatalf("unexpected syslog message %q", stop)
+	}
+	body := stop[strings.Index(stop, "{"):]
+	receipt, err := decodeReceipt([]byte(body))
+	if err != nil || receipt.CurrentHash != ledger.GetReceipts()[1].CurrentHash {
+		t.Fatalf("syslog body should carry the receipt: %v", err)
+	}
+}
+
+// TestSyslogSinkRejectsUnknownNetwork proves only udp and tcp are accepted
+func TestSyslogSinkRejectsUnknownNetwork(t *testing.T) {
+	if _, err := NewSyslogSink("unix", "/dev/log"); err == nil {
+		t.Fatal("unix network should be rejected")
+	}
+}
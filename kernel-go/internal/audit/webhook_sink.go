@@ -0,0 +1,67 @@
+// WHY: Many SOC pipelines ingest over HTTPS. The webhook sink POSTs each
+// batch as JSONL and treats only a 2xx response as acceptance, so a
+// collector outage is retried rather than silently skipped.
+package audit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WebhookSink POSTs receipt batches to an HTTP endpoint
+type WebhookSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewWebhookSink creates a sink for url; headers (e.g. Authorization) are
+// sent with every request
+func NewWebhookSink(url string, headers map[string]string) *WebhookSink {
+	copied := make(map[string]string, len(headers))
+	for key, value := range headers {
+		copied[key] = value
+	}
+	return &WebhookSink{
+		url:     url,
+		headers: copied,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name identifies the sink
+func (w *WebhookSink) Name() string {
+	return "webhook:" + w.url
+}
+
+// Deliver POSTs the batch as application/x-ndjson
+func (w *WebhookSink) Deliver(receipts []Receipt) error {
+	var body bytes.Buffer
+	if err := exportJSONL(&body, receipts); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.url, &body)
+	if err != nil {
+		return fmt.Errorf("webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	for key, value := range w.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook post: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook rejected batch: %s", resp.Status)
+	}
+	return nil
+}
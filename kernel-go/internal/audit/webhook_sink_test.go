@@ -0,0 +1,63 @@
+// WHY: Proves webhook delivery only counts when the collector accepts it.
+package audit
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestWebhookSinkPostsJSONL proves batches arrive as JSONL with headers,
+// and non-2xx responses are retried
+func TestWebhookSinkPostsJSONL(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	received := []Receipt{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer t0k" || r.Header.Get("Content-Type") != "application/x-ndjson" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			receipt, err := decodeReceipt(scanner.Bytes())
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			received = append(received, receipt)
+		}
+	}))
+	defer server.Close()
+
+	ledger := NewLedger()
+	ledger.AppendCDIDecision(testActor, "DENY", "h", "", "d1")
+
+	sink := NewWebhookSink(server.URL, map[string]string{"Authorization": "Bearer t0k"})
+	forwarder, err := ledger.Forward(sink, ForwardPolicy{MinBackoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("forward: %v", err)
+	}
+	defer forwarder.Stop()
+
+	waitFor(t, "webhook delivery", func() bool { return forwarder.Status().Delivered == 2 })
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 || received[1].EventType != "cdi_decision" {
+		t.Fatalf("unexpected webhook payload: %v", received)
+	}
+	if status := forwarder.Status(); status.Failures != 1 || status.LastDelivery.Attempts != 2 {
+		t.Fatalf("503 should be retried once, got %+v", status)
+	}
+}
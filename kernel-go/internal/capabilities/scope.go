@@ -0,0 +1,308 @@
+// WHY: Scope strings are the vocabulary of authority. A small, explicit
+// grammar with documented wildcard rules keeps grants reviewable and makes
+// "what does this token allow" a mechanical question instead of a guess.
+//
+// Grammar:
+//
+//	scope    = "*" | resource [ ":" action [ ":" target ] ]
+//	resource = segment
+//	action   = segment
+//	segment  = name | "*"
+//	target   = path | host
+//	path     = "/" element { "/" element }    (elements may be "*" or "**")
+//	host     = label { "." label }            (labels may be "*" or "**")
+//
+// Examples: "mock_adapter", "fs:read:/workspace/**", "net:get:example.com",
+// "net:get:*.example.com", "exec:*".
+//
+// Wildcard rules:
+//   - "*" on its own grants everything. It is the only global wildcard.
+//   - "*" as a resource or action segment matches exactly one segment value.
+//   - A trailing "*" resource or action segment also covers every deeper
+//     segment: "fs:*" covers "fs:read" and "fs:read:/workspace/a.txt", but
+//     not "fs" itself.
+//   - Without a trailing "*", segment counts must match exactly: "fs:read"
+//     does not cover "fs:read:/workspace/a.txt".
+//   - Inside a target, "*" matches exactly one path element or host label
+//     and "**" matches zero or more of them. Paths split on "/", hosts on ".".
+//   - Partial wildcards ("fo*", "*.txt") are not part of the grammar.
+//   - Requested scopes are literal: wildcards only have meaning in grants,
+//     and requested paths containing "." or ".." elements never match.
+package capabilities
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	scopeSeparator  = ":"
+	wildcardOne     = "*"
+	wildcardAny     = "**"
+	maxScopeSegment = 3
+)
+
+// Scope is a parsed scope string.
+type Scope struct {
+	Resource string
+	Action   string // empty when the scope names a resource only
+	Target   string // empty when the scope has no target
+}
+
+// String renders the scope back into its canonical text form
+func (s Scope) String() string {
+	parts := []string{s.Resource}
+	if s.Action != "" {
+		parts = append(parts, s.Action)
+	}
+	if s.Target != "" {
+		parts = append(parts, s.Target)
+	}
+	return strings.Join(parts, scopeSeparator)
+}
+
+// ParseScope validates a scope string against the grammar.
+// WHY: Malformed grants must be rejected at mint time, not silently
+// interpreted at match time.
+func ParseScope(raw string) (Scope, error) {
+	if raw == wildcardOne {
+		return Scope{Resource: wildcardOne}, nil
+	}
+	if raw == "" {
+		return Scope{}, fmt.Errorf("empty scope")
+	}
+
+	parts := strings.SplitN(raw, scopeSeparator, maxScopeSegment)
+	for i, part := range parts {
+		if part == "" {
+			return Scope{}, fmt.Errorf("scope %q has an empty segment", raw)
+		}
+		if i < 2 && part != wildcardOne && strings.ContainsAny(part, "*/") {
+			return Scope{}, fmt.Errorf("scope %q segment %q is not a name or \"*\"", raw, part)
+		}
+	}
+
+	scope := Scope{Resource: parts[0]}
+	if len(parts) > 1 {
+		scope.Action = parts[1]
+	}
+	if len(parts) > 2 {
+		if err := validateTarget(parts[2]); err != nil {
+			return Scope{}, fmt.Errorf("scope %q: %w", raw, err)
+		}
+		scope.Target = parts[2]
+	}
+	return scope, nil
+}
+
+// validateTarget checks that wildcards only appear as whole elements
+func validateTarget(target string) error {
+	for _, element := range targetElements(target) {
+		if isPath(target) && (element == "." || element == "..") {
+			return fmt.Errorf("target path %q is not canonical", target)
+		}
+		if element == wildcardOne || element == wildcardAny {
+			continue
+		}
+		if strings.Contains(element, wildcardOne) {
+			return fmt.Errorf("target element %q mixes wildcard and literal", element)
+		}
+	}
+	return nil
+}
+
+// MatchScope reports whether a granted scope covers a requested scope.
+// WHY: One matching engine for every adapter keeps wildcard semantics
+// identical across the corridor. Invalid grants match nothing.
+func MatchScope(granted, requested string) bool {
+	if granted == wildcardOne {
+		return true
+	}
+	if requested == "" {
+		return false
+	}
+	if _, err := ParseScope(granted); err != nil {
+		return false
+	}
+
+	grantParts := strings.SplitN(granted, scopeSeparator, maxScopeSegment)
+	requestParts := strings.SplitN(requested, scopeSeparator, maxScopeSegment)
+
+	for i, grant := range grantParts {
+		if i >= len(requestParts) {
+			// Grant is more specific than the request
+			return false
+		}
+		request := requestParts[i]
+
+		if i == 2 {
+			return matchTarget(grant, request)
+		}
+
+		if grant == wildcardOne {
+			if i == len(grantParts)-1 {
+				// Trailing wildcard covers every deeper segment
+				return true
+			}
+			continue
+		}
+		if grant != request {
+			return false
+		}
+	}
+
+	return len(grantParts) == len(requestParts)
+}
+
+// matchTarget applies element-wise glob matching to a target
+func matchTarget(pattern, target string) bool {
+	if isPath(pattern) != isPath(target) {
+		return false
+	}
+	targetParts := targetElements(target)
+	if isPath(target) {
+		for _, element := range targetParts {
+			if element == "." || element == ".." {
+				return false
+			}
+		}
+	}
+	return matchElements(targetElements(pattern), targetParts)
+}
+
+// matchElements matches glob elements where "*" is one element and "**" any number
+func matchElements(pattern, target []string) bool {
+	for len(pattern) > 0 {
+		head := pattern[0]
+		if head == wildcardAny {
+			for skip := 0; skip <= len(target); skip++ {
+				if matchElements(pattern[1:], target[skip:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(target) == 0 {
+			return false
+		}
+		if head != wildcardOne && head != target[0] {
+			return false
+		}
+		pattern, target = pattern[1:], target[1:]
+	}
+	return len(target) == 0
+}
+
+// targetElements splits a target into path elements or host labels
+func targetElements(target string) []string {
+	if isPath(target) {
+		return strings.Split(strings.TrimPrefix(target, "/"), "/")
+	}
+	return strings.Split(target, ".")
+}
+
+func isPath(target string) bool {
+	return strings.HasPrefix(target, "/")
+}
+
+// Covers reports whether a grant, which may itself contain wildcards, lies
+// entirely within the authority of the given scopes.
+// WHY: Delegation and policy narrowing compare grants with grants, where
+// a literal MatchScope would let "/a/*" appear to cover "/a/**".
+func Covers(scopes []string, grant string) bool {
+	for _, s := range scopes {
+		if scopeCovers(s, grant) {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeCovers reports whether every request matched by inner is also matched by outer
+func scopeCovers(outer, inner string) bool {
+	if outer == wildcardOne {
+		return true
+	}
+	if inner == wildcardOne {
+		return false
+	}
+	if _, err := ParseScope(outer); err != nil {
+		return false
+	}
+	if _, err := ParseScope(inner); err != nil {
+		return false
+	}
+
+	outerParts := strings.SplitN(outer, scopeSeparator, maxScopeSegment)
+	innerParts := strings.SplitN(inner, scopeSeparator, maxScopeSegment)
+
+	for i, segment := range outerParts {
+		if i >= len(innerParts) {
+			return false
+		}
+		if i == 2 {
+			if isPath(segment) != isPath(innerParts[i]) {
+				return false
+			}
+			return elementsCover(targetElements(segment), targetElements(innerParts[i]))
+		}
+		if segment == wildcardOne {
+			if i == len(outerParts)-1 {
+				return true
+			}
+			continue
+		}
+		if segment != innerParts[i] {
+			return false
+		}
+	}
+	return len(outerParts) == len(innerParts)
+}
+
+// elementsCover reports whether the outer glob matches everything the inner glob matches
+func elementsCover(outer, inner []string) bool {
+	if len(outer) == 0 {
+		return len(inner) == 0
+	}
+	if outer[0] == wildcardAny {
+		for skip := 0; skip <= len(inner); skip++ {
+			if elementsCover(outer[1:], inner[skip:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(inner) == 0 || inner[0] == wildcardAny {
+		return false
+	}
+	if outer[0] != wildcardOne && outer[0] != inner[0] {
+		return false
+	}
+	return elementsCover(outer[1:], inner[1:])
+}
+
+// IntersectScopes returns the grants that both scope sets authorize.
+// WHY: When policy and a CDI decision both constrain a token, the token
+// gets the narrower of the two, never the union.
+func IntersectScopes(a, b []string) []string {
+	result := []string{}
+	seen := map[string]bool{}
+	add := func(grant string) {
+		if !seen[grant] {
+			seen[grant] = true
+			result = append(result, grant)
+		}
+	}
+
+	for _, grant := range b {
+		if Covers(a, grant) {
+			add(grant)
+		}
+	}
+	for _, grant := range a {
+		if Covers(b, grant) {
+			add(grant)
+		}
+	}
+	return result
+}
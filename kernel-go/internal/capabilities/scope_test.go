@@ -0,0 +1,146 @@
+// WHY: These tests pin the scope grammar and its wildcard semantics.
+package capabilities
+
+import (
+	"testing"
+	"time"
+)
+
+// TestMatchScopeTable covers every documented wildcard rule
+func TestMatchScopeTable(t *testing.T) {
+	cases := []struct {
+		granted   string
+		requested string
+		want      bool
+	}{
+		// Global wildcard and plain names
+		{"*", "anything", true},
+		{"*", "fs:write:/etc/passwd", true},
+		{"mock_adapter", "mock_adapter", true},
+		{"mock_adapter", "other_adapter", false},
+		{"query", "query:extra", false},
+
+		// Segment wildcards
+		{"fs:*", "fs:read", true},
+		{"fs:*", "fs:read:/workspace/a.txt", true},
+		{"fs:*", "fs", false},
+		{"*:read", "db:read", true},
+		{"*:read", "db:write", false},
+		{"fs:read", "fs:read:/workspace/a.txt", false},
+		{"fs:read", "fs:read", true},
+
+		// Path targets
+		{"fs:read:/workspace/**", "fs:read:/workspace/a/b/c.txt", true},
+		{"fs:read:/workspace/**", "fs:read:/workspace", true},
+		{"fs:read:/workspace/**", "fs:read:/workspaces/a", false},
+		{"fs:read:/workspace/*", "fs:read:/workspace/a.txt", true},
+		{"fs:read:/workspace/*", "fs:read:/workspace/a/b.txt", false},
+		{"fs:read:/workspace/**/notes", "fs:read:/workspace/x/y/notes", true},
+		{"fs:read:/workspace/**", "fs:read:/workspace/../etc/passwd", false},
+		{"fs:read:/workspace/**", "fs:read:/workspace/./a", false},
+		{"fs:read:/workspace/**", "fs:write:/workspace/a", false},
+
+		// Host targets
+		{"net:get:example.com", "net:get:example.com", true},
+		{"net:get:example.com", "net:get:api.example.com", false},
+		{"net:get:*.example.com", "net:get:api.example.com", true},
+		{"net:get:*.example.com", "net:get:a.b.example.com", false},
+		{"net:get:**.example.com", "net:get:a.b.example.com", true},
+		{"net:get:*.example.com", "net:get:example.com", false},
+		{"net:get:example.com", "net:get:/example.com", false},
+
+		// Malformed grants match nothing
+		{"fs:re*d", "fs:read", false},
+		{"fs::read", "fs::read", false},
+		{"fs:read:/workspace/*.txt", "fs:read:/workspace/*.txt", false},
+
+		// Requests are literal
+		{"fs:read", "fs:*", false},
+		{"fs:read", "", false},
+	}
+
+	for _, tc := range cases {
+		if got := MatchScope(tc.granted, tc.requested); got != tc.want {
+			t.Errorf("MatchScope(%q, %q) = %v, want %v", tc.granted, tc.requested, got, tc.want)
+		}
+	}
+}
+
+// TestParseScopeRejectsMalformedGrants proves grammar violations are refused
+func TestParseScopeRejectsMalformedGrants(t *testing.T) {
+	invalid := []string{
+		"",
+		":read",
+		"fs:",
+		"fs:re*d",
+		"f/s:read",
+		"fs:read:/workspace/../etc",
+		"net:get:*example.com",
+	}
+	for _, raw := range invalid {
+		if _, err := ParseScope(raw); err == nil {
+			t.Errorf("ParseScope(%q) should fail", raw)
+		}
+	}
+
+	scope, err := ParseScope("fs:read:/workspace/**")
+	if err != nil {
+		t.Fatalf("valid scope rejected: %v", err)
+	}
+	if scope.Resource != "fs" || scope.Action != "read" || scope.Target != "/workspace/**" {
+		t.Fatalf("unexpected parse result: %+v", scope)
+	}
+	if scope.String() != "fs:read:/workspace/**" {
+		t.Fatalf("round trip mismatch: %s", scope.String())
+	}
+}
+
+// TestMintRejectsMalformedScope proves invalid grants never become tokens
+func TestMintRejectsMalformedScope(t *testing.T) {
+	_, err := Mint("kernel", "p", "adapters", []string{"fs:re*d"},
+		Limits{}, time.Minute, PostureBounds{MinPosture: 1, MaxPosture: 4}, "ns", "p")
+	if err == nil {
+		t.Fatal("mint should reject a malformed scope grant")
+	}
+}
+
+// TestHasScopeUsesGrammar proves HasScope applies wildcard matching
+func TestHasScopeUsesGrammar(t *testing.T) {
+	token := fixtureToken()
+	token.Scope = []string{"fs:read:/workspace/**"}
+
+	if !token.HasScope("fs:read:/workspace/docs/a.md") {
+		t.Fatal("expected hierarchical grant to cover nested path")
+	}
+	if token.HasScope("fs:write:/workspace/docs/a.md") {
+		t.Fatal("read grant must not cover write")
+	}
+}
+
+// TestIntersectScopesTakesNarrowerGrant proves policy and decisions only narrow
+func TestIntersectScopesTakesNarrowerGrant(t *testing.T) {
+	cases := []struct {
+		a, b []string
+		want []string
+	}{
+		{[]string{"*"}, []string{"query", "read"}, []string{"query", "read"}},
+		{[]string{"mock_adapter", "fs:*"}, []string{"*"}, []string{"mock_adapter", "fs:*"}},
+		{[]string{"mock_adapter", "fs:*"}, []string{"fs:read:/workspace/**"}, []string{"fs:read:/workspace/**"}},
+		{[]string{"fs:read:/workspace/*"}, []string{"fs:read:/workspace/**"}, []string{"fs:read:/workspace/*"}},
+		{[]string{"query"}, []string{"read"}, []string{}},
+	}
+
+	for _, tc := range cases {
+		got := IntersectScopes(tc.a, tc.b)
+		if len(got) != len(tc.want) {
+			t.Errorf("IntersectScopes(%v, %v) = %v, want %v", tc.a, tc.b, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("IntersectScopes(%v, %v) = %v, want %v", tc.a, tc.b, got, tc.want)
+				break
+			}
+		}
+	}
+}
@@ -5,11 +5,48 @@ package capabilities
 
 import (
 	"crypto/sha256"
+	"encoding/binary"
 	"encoding/hex"
 	"fmt"
+	"sort"
+	"sync"
 	"time"
+
+	"github.com/user/oi/kernel-go/internal/clock"
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+// digestDomain separates token digests from every other hash in the system
+// and pins the canonical encoding version. Changing the encoding requires a
+// new domain string so old and new digests can never collide.
+const digestDomain = "oi.capability_token.v5"
+
+// tokenClock is the clock tokens are minted, verified, and revoked by
+var (
+	clockMu    sync.RWMutex
+	tokenClock clock.Clock = clock.Real{}
 )
 
+// SetClock sets the clock tokens are minted, verified, and revoked by;
+// nil restores the system clock.
+// WHY: Tokens are verified by every adapter with no shared state to carry
+// a clock, so the kernel sets it once for the process.
+func SetClock(c clock.Clock) {
+	if c == nil {
+		c = clock.Real{}
+	}
+	clockMu.Lock()
+	defer clockMu.Unlock()
+	tokenClock = c
+}
+
+// currentTime reads the token clock
+func currentTime() time.Time {
+	clockMu.RLock()
+	defer clockMu.RUnlock()
+	return tokenClock.Now()
+}
+
 // Token represents a scoped capability grant for a specific operation.
 // Tokens are minted by the kernel after CDI ALLOW/DEGRADE decision
 // and verified by adapters before any side-effect.
@@ -29,9 +66,16 @@ type Token struct {
 	NamespaceID string
 	PrincipalID string
 
+	// Provenance links this token to whatever authorized it
+	Provenance Provenance
+
 	// Digest is the cryptographic hash of this token's contents
 	Digest string
 
+	// Signature over the canonical token bytes, and the ID of the signing key
+	Signature   []byte
+	SignerKeyID string
+
 	// RevokedAt is set when STOP is invoked
 	RevokedAt *time.Time
 }
@@ -41,6 +85,18 @@ type Limits struct {
 	MaxDepth         int      // call depth limit
 	MaxBudget        int      // resource budget (e.g., tokens, API calls)
 	WorkspaceBounds  []string // allowed file paths or workspace roots
+	MaxConcurrent    int      // in-flight adapter calls allowed at once; 0 means unlimited
+	Timeout          time.Duration // deadline for each adapter call; 0 leaves it to the registry
+}
+
+// Provenance records where a token's authority came from.
+// WHY: Every side effect must be traceable back to the user request and
+// CDI decision that authorized it, through any chain of derived tokens.
+type Provenance struct {
+	ParentDigest string // digest of the token this one was derived from, empty for root tokens
+	RequestHash  string // CIF input hash of the originating request
+	DecisionID   string // ID of the CDI decision that authorized minting
+	TemplateID   string // governance token template ("name@version") used to mint
 }
 
 // PostureBounds define the posture range this token is valid for
@@ -53,7 +109,24 @@ type PostureBounds struct {
 // WHY: Centralized minting ensures all tokens have required fields
 // and proper initialization.
 func Mint(issuer, subject, audience string, scope []string, limits Limits, ttl time.Duration, postureBounds PostureBounds, namespaceID, principalID string) (*Token, error) {
-	now := time.Now()
+	return MintWithProvenance(issuer, subject, audience, scope, limits, ttl, postureBounds, namespaceID, principalID, Provenance{})
+}
+
+// MintWithProvenance creates a new capability token bound to the request
+// and decision that authorized it.
+// WHY: Provenance is part of the digest, so it cannot be rewritten after
+// minting without invalidating the token.
+func MintWithProvenance(issuer, subject, audience string, scope []string, limits Limits, ttl time.Duration, postureBounds PostureBounds, namespaceID, principalID string, provenance Provenance) (*Token, error) {
+	return mintAt(currentTime(), issuer, subject, audience, scope, limits, ttl, postureBounds, namespaceID, principalID, provenance)
+}
+
+// mintAt builds a token issued at the given instant
+func mintAt(now time.Time, issuer, subject, audience string, scope []string, limits Limits, ttl time.Duration, postureBounds PostureBounds, namespaceID, principalID string, provenance Provenance) (*Token, error) {
+	for _, s := range scope {
+		if _, err := ParseScope(s); err != nil {
+			return nil, fmt.Errorf("invalid scope grant: %w", err)
+		}
+	}
 
 	token := &Token{
 		Issuer:        issuer,
@@ -67,6 +140,7 @@ func Mint(issuer, subject, audience string, scope []string, limits Limits, ttl t
 		PostureBounds: postureBounds,
 		NamespaceID:   namespaceID,
 		PrincipalID:   principalID,
+		Provenance:    provenance,
 		RevokedAt:     nil,
 	}
 
@@ -78,18 +152,67 @@ func Mint(issuer, subject, audience string, scope []string, limits Limits, ttl t
 
 // computeDigest generates a cryptographic hash of the token's contents
 func (t *Token) computeDigest() string {
-	h := sha256.New()
-	h.Write([]byte(fmt.Sprintf("%s|%s|%s|%v|%v|%v|%v|%s|%s",
-		t.Issuer, t.Subject, t.Audience,
-		t.Scope, t.Limits, t.IssuedAt.Unix(), t.ExpiresAt.Unix(),
-		t.NamespaceID, t.PrincipalID)))
-	return hex.EncodeToString(h.Sum(nil))
+	h := sha256.Sum256(t.canonicalBytes())
+	return hex.EncodeToString(h[:])
+}
+
+// canonicalBytes encodes the authority-bearing token fields into a stable
+// byte sequence.
+// WHY: fmt formatting of structs and slices is not a wire format; digests
+// must be reproducible across processes, architectures, and Go releases.
+//
+// Layout: every string is a big-endian uint32 length followed by its bytes,
+// every integer is a big-endian int64, and every list is a uint32 count
+// followed by its elements. Scope and workspace bounds are sorted so that
+// grant order does not change the digest. Times are Unix nanoseconds (UTC).
+func (t *Token) canonicalBytes() []byte {
+	var b []byte
+	b = appendString(b, digestDomain)
+	b = appendString(b, t.Issuer)
+	b = appendString(b, t.Subject)
+	b = appendString(b, t.Audience)
+	b = appendStrings(b, t.Scope)
+	b = appendInt(b, int64(t.Limits.MaxDepth))
+	b = appendInt(b, int64(t.Limits.MaxBudget))
+	b = appendStrings(b, t.Limits.WorkspaceBounds)
+	b = appendInt(b, int64(t.Limits.MaxConcurrent))
+	b = appendInt(b, int64(t.Limits.Timeout))
+	b = appendInt(b, t.IssuedAt.UnixNano())
+	b = appendInt(b, t.ExpiresAt.UnixNano())
+	b = appendInt(b, int64(t.PostureBounds.MinPosture))
+	b = appendInt(b, int64(t.PostureBounds.MaxPosture))
+	b = appendString(b, t.NamespaceID)
+	b = appendString(b, t.PrincipalID)
+	b = appendString(b, t.Provenance.ParentDigest)
+	b = appendString(b, t.Provenance.RequestHash)
+	b = appendString(b, t.Provenance.DecisionID)
+	b = appendString(b, t.Provenance.TemplateID)
+	return b
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+func appendStrings(b []byte, values []string) []byte {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(sorted)))
+	for _, v := range sorted {
+		b = appendString(b, v)
+	}
+	return b
+}
+
+func appendInt(b []byte, v int64) []byte {
+	return binary.BigEndian.AppendUint64(b, uint64(v))
 }
 
 // Verify checks if a token is valid for use.
 // WHY: Fail-closed verification - any problem returns false.
 func (t *Token) Verify(currentPosture int) (bool, error) {
-	now := time.Now()
+	now := currentTime()
 
 	// Check revocation
 	if t.RevokedAt != nil {
@@ -112,19 +235,118 @@ func (t *Token) Verify(currentPosture int) (bool, error) {
 	return true, nil
 }
 
+// Sign attaches a signature over the token's canonical encoding.
+// WHY: A signed token proves it came from the kernel's mint key, which
+// may live in a keychain, KMS, or HSM rather than in process memory.
+func (t *Token) Sign(signer signing.Signer) error {
+	signature, err := signer.Sign(t.canonicalBytes())
+	if err != nil {
+		return fmt.Errorf("sign token: %w", err)
+	}
+	t.Signature = signature
+	t.SignerKeyID = signer.KeyID()
+	return nil
+}
+
+// VerifySignature checks the token signature against trusted keys.
+// WHY: Fail-closed - unsigned tokens and unknown keys are rejected.
+func (t *Token) VerifySignature(keys *signing.KeyRing) error {
+	if len(t.Signature) == 0 {
+		return fmt.Errorf("token is not signed")
+	}
+	if err := keys.Verify(t.SignerKeyID, t.canonicalBytes(), t.Signature); err != nil {
+		return fmt.Errorf("token signature: %w", err)
+	}
+	return nil
+}
+
+// ParamInput is the invocation parameter carrying the request payload that
+// a request-bound token authorizes.
+const ParamInput = "input"
+
+// VerifyRequestBinding checks that an invocation's payload is the request
+// this token was minted for: the SHA-256 of params[ParamInput] must equal
+// Provenance.RequestHash (the CIF input hash).
+// WHY: Without the binding, a token minted for one request could authorize
+// a completely different payload for the rest of its TTL. Tokens minted
+// outside the corridor carry no request hash and are not request-bound.
+func (t *Token) VerifyRequestBinding(params map[string]interface{}) error {
+	if t.Provenance.RequestHash == "" {
+		return nil
+	}
+
+	input, ok := params[ParamInput].(string)
+	if !ok {
+		return fmt.Errorf("request-bound token requires %q parameter", ParamInput)
+	}
+
+	h := sha256.Sum256([]byte(input))
+	if hex.EncodeToString(h[:]) != t.Provenance.RequestHash {
+		return fmt.Errorf("invocation payload does not match token request hash")
+	}
+	return nil
+}
+
 // Revoke marks this token as revoked.
 // WHY: STOP dominance - revocation is immediate and irreversible.
 func (t *Token) Revoke() {
-	now := time.Now()
+	now := currentTime()
 	t.RevokedAt = &now
 }
 
 // HasScope checks if this token grants a specific operation scope.
+// Grants are matched with the scope grammar in scope.go, so "*" and
+// hierarchical wildcards such as "fs:read:/workspace/**" apply here.
 func (t *Token) HasScope(operation string) bool {
 	for _, s := range t.Scope {
-		if s == operation {
+		if MatchScope(s, operation) {
 			return true
 		}
 	}
 	return false
 }
+
+// Attenuate derives a child token from a parent with equal or narrower
+// authority. The child inherits identity, limits, posture bounds, and
+// provenance from the parent and records the parent's digest.
+// WHY: Delegation may only shrink authority, and the lineage of every
+// derived token must lead back to a root minted after a CDI decision.
+func Attenuate(parent *Token, scope []string, ttl time.Duration) (*Token, error) {
+	if parent == nil {
+		return nil, fmt.Errorf("nil parent token")
+	}
+	if parent.RevokedAt != nil {
+		return nil, fmt.Errorf("parent token revoked")
+	}
+	for _, s := range scope {
+		if !Covers(parent.Scope, s) {
+			return nil, fmt.Errorf("scope %q exceeds parent token authority", s)
+		}
+	}
+
+	now := currentTime()
+	remaining := parent.ExpiresAt.Sub(now)
+	if remaining <= 0 {
+		return nil, fmt.Errorf("parent token expired at %v", parent.ExpiresAt)
+	}
+	if ttl > remaining {
+		ttl = remaining
+	}
+
+	provenance := parent.Provenance
+	provenance.ParentDigest = parent.Digest
+
+	return mintAt(
+		now,
+		parent.Issuer,
+		parent.Subject,
+		parent.Audience,
+		scope,
+		parent.Limits,
+		ttl,
+		parent.PostureBounds,
+		parent.NamespaceID,
+		parent.PrincipalID,
+		provenance,
+	)
+}
@@ -0,0 +1,270 @@
+// WHY: These tests pin the canonical token digest encoding so digests stay
+// reproducible across processes and Go releases.
+package capabilities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/clock"
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+// fixtureToken builds a token with fixed timestamps so its digest is stable.
+func fixtureToken() *Token {
+	issued := time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC)
+	return &Token{
+		Issuer:   "kernel",
+		Subject:  "principal_1",
+		Audience: "adapters",
+		Scope:    []string{"query", "read"},
+		Limits: Limits{
+			MaxDepth:        10,
+			MaxBudget:       1000,
+			WorkspaceBounds: []string{"/workspace"},
+			MaxConcurrent:   2,
+			Timeout:         30 * time.Second,
+		},
+		TTL:           5 * time.Minute,
+		IssuedAt:      issued,
+		ExpiresAt:     issued.Add(5 * time.Minute),
+		PostureBounds: PostureBounds{MinPosture: 1, MaxPosture: 4},
+		NamespaceID:   "namespace_1",
+		PrincipalID:   "principal_1",
+		Provenance: Provenance{
+			RequestHash: "request_hash_1",
+			DecisionID:  "decision_1",
+			TemplateID:  "standard@v1",
+		},
+	}
+}
+
+// TestTokenDigestGoldenVector proves the canonical encoding is stable.
+// If this test fails the digest format changed: bump digestDomain and
+// update the expected value together, never the value alone, so a digest
+// in the old format can never be taken for one in the new.
+func TestTokenDigestGoldenVector(t *testing.T) {
+	const expected = "5875eb49f4d2dc5e529bf8a95454ad4ade056ad0ffad9c5883b30fa2c4cd0c4f"
+
+	got := fixtureToken().computeDigest()
+	if got != expected {
+		t.Fatalf("token digest changed: expected %s, got %s", expected, got)
+	}
+}
+
+// TestTokenDigestIndependentOfScopeOrder proves grant order is not significant
+func TestTokenDigestIndependentOfScopeOrder(t *testing.T) {
+	a := fixtureToken()
+	b := fixtureToken()
+	b.Scope = []string{"read", "query"}
+
+	if a.computeDigest() != b.computeDigest() {
+		t.Fatal("scope order should not change the token digest")
+	}
+	if b.Scope[0] != "read" {
+		t.Fatal("computing the digest must not reorder the token's scope")
+	}
+}
+
+// TestTokenDigestIgnoresTimeZone proves the digest depends on the instant only
+func TestTokenDigestIgnoresTimeZone(t *testing.T) {
+	a := fixtureToken()
+	b := fixtureToken()
+	zone := time.FixedZone("UTC+9", 9*60*60)
+	b.IssuedAt = b.IssuedAt.In(zone)
+	b.ExpiresAt = b.ExpiresAt.In(zone)
+
+	if a.computeDigest() != b.computeDigest() {
+		t.Fatal("time zone should not change the token digest")
+	}
+}
+
+// TestTokenDigestCoversAuthorityFields proves every authority-bearing field
+// contributes to the digest.
+func TestTokenDigestCoversAuthorityFields(t *testing.T) {
+	base := fixtureToken().computeDigest()
+
+	mutations := map[string]func(*Token){
+		"issuer":           func(tk *Token) { tk.Issuer = "other" },
+		"subject":          func(tk *Token) { tk.Subject = "other" },
+		"audience":         func(tk *Token) { tk.Audience = "other" },
+		"scope":            func(tk *Token) { tk.Scope = []string{"query"} },
+		"max_depth":        func(tk *Token) { tk.Limits.MaxDepth = 11 },
+		"max_budget":       func(tk *Token) { tk.Limits.MaxBudget = 1001 },
+		"workspace_bounds": func(tk *Token) { tk.Limits.WorkspaceBounds = nil },
+		"max_concurrent":   func(tk *Token) { tk.Limits.MaxConcurrent = 1 },
+		"timeout":          func(tk *Token) { tk.Limits.Timeout = time.Minute },
+		"issued_at":        func(tk *Token) { tk.IssuedAt = tk.IssuedAt.Add(time.Nanosecond) },
+		"expires_at":       func(tk *Token) { tk.ExpiresAt = tk.ExpiresAt.Add(time.Second) },
+		"min_posture":      func(tk *Token) { tk.PostureBounds.MinPosture = 2 },
+		"max_posture":      func(tk *Token) { tk.PostureBounds.MaxPosture = 3 },
+		"namespace":        func(tk *Token) { tk.NamespaceID = "other" },
+		"principal":        func(tk *Token) { tk.PrincipalID = "other" },
+		"parent_digest":    func(tk *Token) { tk.Provenance.ParentDigest = "other" },
+		"request_hash":     func(tk *Token) { tk.Provenance.RequestHash = "other" },
+		"decision_id":      func(tk *Token) { tk.Provenance.DecisionID = "other" },
+		"template_id":      func(tk *Token) { tk.Provenance.TemplateID = "other" },
+	}
+
+	for field, mutate := range mutations {
+		token := fixtureToken()
+		mutate(token)
+		if token.computeDigest() == base {
+			t.Fatalf("changing %s did not change the token digest", field)
+		}
+	}
+}
+
+// TestTokenDigestFieldBoundaries proves length prefixes prevent field shifting
+func TestTokenDigestFieldBoundaries(t *testing.T) {
+	a := fixtureToken()
+	a.Issuer, a.Subject = "ab", "c"
+	b := fixtureToken()
+	b.Issuer, b.Subject = "a", "bc"
+
+	if a.computeDigest() == b.computeDigest() {
+		t.Fatal("shifting bytes between fields must change the token digest")
+	}
+
+	c := fixtureToken()
+	c.Scope = []string{"a,b"}
+	d := fixtureToken()
+	d.Scope = []string{"a", "b"}
+
+	if c.computeDigest() == d.computeDigest() {
+		t.Fatal("scope element boundaries must be part of the token digest")
+	}
+}
+
+// TestMintProducesDistinctDigests proves same-second mints do not collide
+func TestMintProducesDistinctDigests(t *testing.T) {
+	mint := func() *Token {
+		token, err := Mint("kernel", "p", "adapters", []string{"*"},
+			Limits{MaxDepth: 10, MaxBudget: 100}, time.Minute,
+			PostureBounds{MinPosture: 1, MaxPosture: 4}, "ns", "p")
+		if err != nil {
+			t.Fatalf("mint failed: %v", err)
+		}
+		return token
+	}
+
+	first := mint()
+	time.Sleep(time.Microsecond)
+	second := mint()
+
+	if first.Digest == second.Digest {
+		t.Fatal("tokens minted at different instants must have distinct digests")
+	}
+}
+
+// TestTokensFollowTheClock proves minting, expiry, and revocation read
+// the token clock rather than the system clock
+func TestTokensFollowTheClock(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	now := clock.NewFake(start)
+	SetClock(now)
+	t.Cleanup(func() { SetClock(nil) })
+
+	token, err := Mint("kernel", "p", "adapters", []string{"*"}, Limits{}, time.Minute,
+		PostureBounds{MinPosture: 1, MaxPosture: 4}, "ns", "p")
+	if err != nil {
+		t.Fatalf("mint failed: %v", err)
+	}
+	if !token.IssuedAt.Equal(start) {
+		t.Fatalf("expected issue time %v, got %v", start, token.IssuedAt)
+	}
+	if valid, err := token.Verify(1); !valid {
+		t.Fatalf("a fresh token must verify: %v", err)
+	}
+	now.Advance(2 * time.Minute)
+	if valid, _ := token.Verify(1); valid {
+		t.Fatal("a token must expire when the clock passes its TTL")
+	}
+	token.Revoke()
+	if !token.RevokedAt.Equal(start.Add(2 * time.Minute)) {
+		t.Fatalf("revocation must be stamped by the clock, got %v", token.RevokedAt)
+	}
+}
+
+// TestAttenuateRecordsParentAndNarrowsScope proves derived tokens keep lineage
+func TestAttenuateRecordsParentAndNarrowsScope(t *testing.T) {
+	parent, err := MintWithProvenance("kernel", "p", "adapters", []string{"fs:*"},
+		Limits{MaxDepth: 10, MaxBudget: 100}, time.Minute,
+		PostureBounds{MinPosture: 1, MaxPosture: 4}, "ns", "p",
+		Provenance{RequestHash: "req_hash", DecisionID: "dec_1"})
+	if err != nil {
+		t.Fatalf("mint failed: %v", err)
+	}
+
+	child, err := Attenuate(parent, []string{"fs:read:/workspace/**"}, time.Hour)
+	if err != nil {
+		t.Fatalf("attenuate failed: %v", err)
+	}
+
+	if child.Provenance.ParentDigest != parent.Digest {
+		t.Fatal("child must record parent digest")
+	}
+	if child.Provenance.RequestHash != "req_hash" || child.Provenance.DecisionID != "dec_1" {
+		t.Fatal("child must inherit request hash and decision ID")
+	}
+	if child.ExpiresAt.After(parent.ExpiresAt) {
+		t.Fatal("child must not outlive parent")
+	}
+}
+
+// TestAttenuateRejectsWidening proves delegation can only shrink authority
+func TestAttenuateRejectsWidening(t *testing.T) {
+	parent, err := Mint("kernel", "p", "adapters", []string{"fs:read:/workspace/*"},
+		Limits{}, time.Minute, PostureBounds{MinPosture: 1, MaxPosture: 4}, "ns", "p")
+	if err != nil {
+		t.Fatalf("mint failed: %v", err)
+	}
+
+	widening := [][]string{
+		{"*"},
+		{"fs:write:/workspace/a"},
+		{"fs:read:/workspace/**"},
+		{"fs:*"},
+	}
+	for _, scope := range widening {
+		if _, err := Attenuate(parent, scope, time.Minute); err == nil {
+			t.Errorf("attenuate to %v should be rejected", scope)
+		}
+	}
+
+	if _, err := Attenuate(parent, []string{"fs:read:/workspace/a.txt"}, time.Minute); err != nil {
+		t.Fatalf("narrowing should be allowed: %v", err)
+	}
+
+	parent.Revoke()
+	if _, err := Attenuate(parent, []string{"fs:read:/workspace/a.txt"}, time.Minute); err == nil {
+		t.Fatal("revoked parent must not delegate")
+	}
+}
+
+// TestTokenSignatureBindsContents proves signed tokens detect field edits
+func TestTokenSignatureBindsContents(t *testing.T) {
+	signer, err := signing.GenerateLocalSigner("mint_key_1")
+	if err != nil {
+		t.Fatalf("generate signer: %v", err)
+	}
+	keys := signing.NewKeyRing()
+	keys.AddSigner(signer)
+
+	token := fixtureToken()
+	if err := token.VerifySignature(keys); err == nil {
+		t.Fatal("unsigned token must not verify")
+	}
+
+	if err := token.Sign(signer); err != nil {
+		t.Fatalf("sign failed: %v", err)
+	}
+	if err := token.VerifySignature(keys); err != nil {
+		t.Fatalf("signed token should verify: %v", err)
+	}
+
+	token.Scope = []string{"*"}
+	if err := token.VerifySignature(keys); err == nil {
+		t.Fatal("widened token must fail signature verification")
+	}
+}
@@ -3,9 +3,12 @@
 package cdi
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 
 	"github.com/user/oi/kernel-go/internal/cif"
+	"github.com/user/oi/kernel-go/internal/posture"
 )
 
 // Decision represents the result of a CDI evaluation
@@ -19,6 +22,7 @@ const (
 
 // DecisionResult contains the decision and associated metadata
 type DecisionResult struct {
+	DecisionID     string // unique ID linking receipts and tokens to this decision
 	Decision       Decision
 	Reason         string
 	DegradedScope  []string // If DEGRADE, what operations are allowed
@@ -33,11 +37,34 @@ type DecisionContext struct {
 	GovernanceRules  map[string]interface{}
 	IntegrityState   string
 	ActiveConsents   map[string]bool
+
+	// ConsentExpiry holds the Unix time each expiring consent lapses at;
+	// a consent past it at Now counts as absent
+	ConsentExpiry map[string]int64
+	Now           int64
+
+	// CapsuleHash is the hash of the governance capsule in force, and
+	// CommittedCapsuleHash the one committed to the ledger; they must match
+	CapsuleHash          string
+	CommittedCapsuleHash string
+
+	// World is the world pack context the request is judged in
+	World map[string]interface{}
+
+	// Adapters are the registered adapters' declarations; DEGRADE
+	// decisions keep only the ones that cannot write
+	Adapters []CapabilityDeclaration
 }
 
 // Decide evaluates a request and returns ALLOW, DENY, or DEGRADE.
 // WHY: Fail-closed decision logic - unknowns become DENY.
 func Decide(ctx *DecisionContext) (*DecisionResult, error) {
+	result, err := decide(ctx)
+	return withDecisionID(result), err
+}
+
+// decide holds the decision logic; Decide stamps the result with an ID
+func decide(ctx *DecisionContext) (*DecisionResult, error) {
 	if ctx == nil {
 		return &DecisionResult{
 			Decision: DENY,
@@ -53,6 +80,14 @@ func Decide(ctx *DecisionContext) (*DecisionResult, error) {
 		}, nil
 	}
 
+	// Governance changed since it was committed is not governance
+	if ctx.CapsuleHash != ctx.CommittedCapsuleHash {
+		return &DecisionResult{
+			Decision: DENY,
+			Reason:   "governance_capsule_mismatch",
+		}, nil
+	}
+
 	// Check if request is tainted
 	if ctx.Request.IsTainted() {
 		return &DecisionResult{
@@ -90,7 +125,7 @@ func evaluateRequest(ctx *DecisionContext) *DecisionResult {
 
 	// High sensitivity requires explicit consent
 	if sensitivity == "high" {
-		if !hasConsent(ctx.ActiveConsents, "high_risk_operations") {
+		if !ctx.consentActive("high_risk_operations") {
 			return &DecisionResult{
 				Decision: DENY,
 				Reason:   "high_risk_requires_consent",
@@ -103,7 +138,7 @@ func evaluateRequest(ctx *DecisionContext) *DecisionResult {
 		return &DecisionResult{
 			Decision:       DEGRADE,
 			Reason:         "integrity_degraded",
-			DegradedScope:  []string{"read_only", "query"},
+			DegradedScope:  append([]string{"read_only", "query"}, readOnlyScopes(ctx.Adapters)...),
 			RequiredPosture: ctx.PostureLevel,
 		}
 	}
@@ -123,7 +158,7 @@ func evaluateRequest(ctx *DecisionContext) *DecisionResult {
 		return &DecisionResult{
 			Decision:       DEGRADE,
 			Reason:         "medium_sensitivity",
-			DegradedScope:  []string{"query", "search", "read"},
+			DegradedScope:  append([]string{"query", "search", "read"}, readOnlyScopes(ctx.Adapters)...),
 			RequiredPosture: ctx.PostureLevel,
 		}
 	}
@@ -143,11 +178,34 @@ func hasConsent(consents map[string]bool, required string) bool {
 	return consents[required]
 }
 
+// consentActive checks if a consent is held and has not expired
+func (ctx *DecisionContext) consentActive(required string) bool {
+	if !hasConsent(ctx.ActiveConsents, required) {
+		return false
+	}
+	expiresAt, expiring := ctx.ConsentExpiry[required]
+	return !expiring || ctx.Now < expiresAt
+}
+
 // DecideOutput evaluates output artifacts before egress.
 // WHY: Output CDI prevents information leakage through results.
 func DecideOutput(content string, sensitivity string, postureLevel int) (*DecisionResult, error) {
-	// Check if output should be allowed based on posture
-	if sensitivity == "high" && postureLevel >= 2 {
+	return DecideOutputWithPolicy(content, sensitivity, posture.DefaultPolicy().Level(postureLevel))
+}
+
+// DecideOutputWithPolicy evaluates output artifacts before egress under
+// the policy of the current posture level
+func DecideOutputWithPolicy(content string, sensitivity string, policy posture.LevelPolicy) (*DecisionResult, error) {
+	result, err := decideOutput(content, sensitivity, policy)
+	return withDecisionID(result), err
+}
+
+// decideOutput holds the output decision logic
+func decideOutput(content string, sensitivity string, policy posture.LevelPolicy) (*DecisionResult, error) {
+	// Check if output should be allowed based on posture. WHY: Only high
+	// sensitivity is blocked outright; what else the ceiling excludes is
+	// redacted at egress.
+	if sensitivity == posture.SensitivityHigh && !policy.Permits(sensitivity) {
 		return &DecisionResult{
 			Decision: DENY,
 			Reason:   "high_sensitivity_blocked_by_posture",
@@ -174,3 +232,22 @@ func containsBypassPatterns(content string) bool {
 	// Simplified check - production would be more sophisticated
 	return false
 }
+
+// withDecisionID assigns a fresh decision ID to a result.
+// WHY: Decision IDs let every token and side effect be traced back to
+// the exact judgment that authorized it.
+func withDecisionID(result *DecisionResult) *DecisionResult {
+	if result != nil {
+		result.DecisionID = newDecisionID()
+	}
+	return result
+}
+
+// newDecisionID returns a random 128-bit identifier
+func newDecisionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("cdi: decision ID entropy unavailable: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
@@ -249,3 +249,34 @@ func TestHighSensitivityRequiresConsent(t *testing.T) {
 		t.Fatal("expected ALLOW/DEGRADE with consent, but got DENY for consent")
 	}
 }
+
+// TestExpiredConsentCountsAsAbsent proves a consent past its expiry no
+// longer lets a high-risk request through
+func TestExpiredConsentCountsAsAbsent(t *testing.T) {
+	ctx := &DecisionContext{
+		Request: &cif.LabeledRequest{
+			SanitizedInput:   "test input",
+			TaintLabels:      []string{"clean"},
+			SensitivityLevel: "high",
+		},
+		PostureLevel:    1,
+		GovernanceRules: map[string]interface{}{"exists": true},
+		IntegrityState:  "INTEGRITY_OK",
+		ActiveConsents:  map[string]bool{"high_risk_operations": true},
+		ConsentExpiry:   map[string]int64{"high_risk_operations": 1000},
+		Now:             999,
+	}
+	result, err := Decide(ctx)
+	if err != nil || result.Reason == "high_risk_requires_consent" {
+		t.Fatalf("an unexpired consent must hold, got %v (%v)", result.Reason, err)
+	}
+
+	ctx.Now = 1000
+	result, err = Decide(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Decision != DENY || result.Reason != "high_risk_requires_consent" {
+		t.Fatalf("an expired consent must count as absent, got %s: %s", result.Decision, result.Reason)
+	}
+}
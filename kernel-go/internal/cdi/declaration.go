@@ -0,0 +1,85 @@
+// WHY: CDI cannot narrow what it cannot see. Every adapter declares up
+// front how risky it is, what kinds of side effect it can cause, and which
+// scopes it needs, so a DEGRADE decision can name exactly the adapters
+// that cannot change anything instead of guessing from their names.
+package cdi
+
+import (
+	"fmt"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+)
+
+// Side-effect categories an adapter may declare
+const (
+	SideEffectRead    = "read"    // reads data the kernel did not supply
+	SideEffectWrite   = "write"   // changes state outside the kernel
+	SideEffectNetwork = "network" // sends data off the host
+	SideEffectExec    = "exec"    // runs code
+)
+
+// CapabilityDeclaration is what an adapter says about itself at registration
+type CapabilityDeclaration struct {
+	// Name is the adapter name
+	Name string
+
+	// Risk is RiskLow, RiskMedium, or RiskHigh
+	Risk string
+
+	// SideEffects lists the categories of effect the adapter can cause;
+	// empty means none
+	SideEffects []string
+
+	// Scopes are the grants a token needs to use the adapter fully
+	Scopes []string
+}
+
+// Validate checks the declaration uses known risks, side effects, and
+// well-formed scopes
+func (d CapabilityDeclaration) Validate() error {
+	switch d.Risk {
+	case RiskLow, RiskMedium, RiskHigh:
+	default:
+		return fmt.Errorf("adapter %s declares unknown risk %q", d.Name, d.Risk)
+	}
+	for _, effect := range d.SideEffects {
+		switch effect {
+		case SideEffectRead, SideEffectWrite, SideEffectNetwork, SideEffectExec:
+		default:
+			return fmt.Errorf("adapter %s declares unknown side effect %q", d.Name, effect)
+		}
+	}
+	for _, scope := range d.Scopes {
+		if scope == "*" {
+			return fmt.Errorf("adapter %s may not declare the global wildcard scope", d.Name)
+		}
+		if _, err := capabilities.ParseScope(scope); err != nil {
+			return fmt.Errorf("adapter %s: %w", d.Name, err)
+		}
+	}
+	return nil
+}
+
+// CanWrite reports whether the adapter can change state or run code
+func (d CapabilityDeclaration) CanWrite() bool {
+	for _, effect := range d.SideEffects {
+		if effect == SideEffectWrite || effect == SideEffectExec {
+			return true
+		}
+	}
+	return false
+}
+
+// readOnlyScopes returns the name and scopes of every adapter a degraded
+// token may still use: no write or exec side effects and not high risk
+func readOnlyScopes(declarations []CapabilityDeclaration) []string {
+	scopes := []string{}
+	for _, declaration := range declarations {
+		if declaration.CanWrite() || declaration.Risk == RiskHigh {
+			continue
+		}
+		scopes = append(scopes, declaration.Name)
+		scopes = append(scopes, declaration.Scopes...)
+	}
+	return scopes
+}
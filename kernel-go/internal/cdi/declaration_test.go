@@ -0,0 +1,68 @@
+// WHY: These tests prove a DEGRADE decision keeps only adapters that
+// declared they cannot write, and malformed declarations are refused.
+package cdi
+
+import (
+	"testing"
+
+	"github.com/user/oi/kernel-go/internal/cif"
+)
+
+// TestDegradeExcludesWriteCapableAdapters proves degraded scopes name
+// read-only adapters and omit write, exec, and high-risk ones
+func TestDegradeExcludesWriteCapableAdapters(t *testing.T) {
+	ctx := &DecisionContext{
+		Request:         &cif.LabeledRequest{TaintLabels: []string{"clean"}, SensitivityLevel: "low"},
+		PostureLevel:    1,
+		GovernanceRules: map[string]interface{}{},
+		IntegrityState:  "INTEGRITY_DEGRADED",
+		Adapters: []CapabilityDeclaration{
+			{Name: "search", Risk: RiskLow, SideEffects: []string{SideEffectNetwork}},
+			{Name: "db", Risk: RiskMedium, SideEffects: []string{SideEffectRead}, Scopes: []string{"sql:select:**"}},
+			{Name: "files", Risk: RiskMedium, SideEffects: []string{SideEffectRead, SideEffectWrite}},
+			{Name: "shell", Risk: RiskMedium, SideEffects: []string{SideEffectExec}},
+			{Name: "oracle", Risk: RiskHigh},
+		},
+	}
+	result, err := Decide(ctx)
+	if err != nil {
+		t.Fatalf("decide: %v", err)
+	}
+	if result.Decision != DEGRADE {
+		t.Fatalf("expected DEGRADE, got %s", result.Decision)
+	}
+
+	scopes := map[string]bool{}
+	for _, scope := range result.DegradedScope {
+		scopes[scope] = true
+	}
+	for _, kept := range []string{"search", "db", "sql:select:**"} {
+		if !scopes[kept] {
+			t.Fatalf("read-only scope %s missing from %v", kept, result.DegradedScope)
+		}
+	}
+	for _, dropped := range []string{"files", "shell", "oracle"} {
+		if scopes[dropped] {
+			t.Fatalf("write-capable or high-risk adapter %s kept in %v", dropped, result.DegradedScope)
+		}
+	}
+}
+
+// TestDeclarationValidate proves unknown risks and side effects, malformed
+// scopes, and the global wildcard are refused
+func TestDeclarationValidate(t *testing.T) {
+	if err := (CapabilityDeclaration{Name: "ok", Risk: RiskLow, Scopes: []string{"fs:read:*"}}).Validate(); err != nil {
+		t.Fatalf("valid declaration refused: %v", err)
+	}
+	rejected := map[string]CapabilityDeclaration{
+		"missing risk":        {Name: "a"},
+		"unknown side effect": {Name: "a", Risk: RiskLow, SideEffects: []string{"teleport"}},
+		"malformed scope":     {Name: "a", Risk: RiskLow, Scopes: []string{"fs:re*d"}},
+		"global wildcard":     {Name: "a", Risk: RiskLow, Scopes: []string{"*"}},
+	}
+	for name, declaration := range rejected {
+		if err := declaration.Validate(); err == nil {
+			t.Fatalf("%s: declaration must be refused", name)
+		}
+	}
+}
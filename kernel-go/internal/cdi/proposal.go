@@ -0,0 +1,116 @@
+// WHY: A request-level ALLOW approves intent, not every action taken on its
+// behalf. When an adapter proposes a concrete action (a tool call with
+// arguments), CDI judges that proposal too, so arguments shaped by external
+// content cannot ride an earlier approval.
+package cdi
+
+import (
+	"fmt"
+
+	"github.com/user/oi/kernel-go/internal/cif"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// ObligationConfirm marks a denial the user can lift by confirming the
+// exact action; it is set in the decision's Metadata["obligation"]
+const ObligationConfirm = "confirm"
+
+// Proposal risk levels, ordered from least to most dangerous
+const (
+	RiskLow    = "low"
+	RiskMedium = "medium"
+	RiskHigh   = "high"
+)
+
+// ProposalContext describes one concrete action an adapter wants to take
+type ProposalContext struct {
+	// Action names what would run, e.g. "mcp:call:github.create_issue"
+	Action string
+
+	// Arguments is the CIF-labeled action payload
+	Arguments *cif.LabeledRequest
+
+	// Risk is the declared risk of the action (RiskLow, RiskMedium, RiskHigh)
+	Risk string
+
+	PostureLevel   int
+	IntegrityState string
+
+	// PosturePolicy says which risks need confirmation at each level;
+	// nil means posture.DefaultPolicy
+	PosturePolicy posture.Policy
+
+	// Consent, if set, names a consent that must be active in
+	// ActiveConsents for the action to be considered at all
+	Consent        string
+	ActiveConsents map[string]bool
+
+	// Confirmed reports that the user confirmed this exact action through
+	// the adapter's confirmation channel
+	Confirmed bool
+}
+
+// DecideProposal evaluates a proposed action and returns ALLOW or DENY.
+// WHY: A proposal the posture says needs confirmation is refused unless
+// the user has confirmed it; the refusal carries ObligationConfirm so an
+// adapter with a confirmation channel knows asking could lift it. Nothing
+// is ever degraded into running unconfirmed.
+func DecideProposal(ctx *ProposalContext) (*DecisionResult, error) {
+	result, err := decideProposal(ctx)
+	return withDecisionID(result), err
+}
+
+// decideProposal holds the proposal decision logic
+func decideProposal(ctx *ProposalContext) (*DecisionResult, error) {
+	if ctx == nil || ctx.Arguments == nil {
+		return &DecisionResult{
+			Decision: DENY,
+			Reason:   "nil proposal",
+		}, fmt.Errorf("nil proposal context")
+	}
+
+	if ctx.IntegrityState == "INTEGRITY_VOID" {
+		return &DecisionResult{Decision: DENY, Reason: "integrity_void"}, nil
+	}
+	if !posture.IsValid(ctx.PostureLevel) {
+		return &DecisionResult{Decision: DENY, Reason: "undefined_posture"}, nil
+	}
+	if ctx.Arguments.IsTainted() {
+		return &DecisionResult{Decision: DENY, Reason: "tainted_proposal"}, nil
+	}
+	if ctx.Consent != "" && !hasConsent(ctx.ActiveConsents, ctx.Consent) {
+		return &DecisionResult{Decision: DENY, Reason: "proposal_requires_consent", Metadata: map[string]interface{}{"consent": ctx.Consent}}, nil
+	}
+
+	risk := ctx.Risk
+	if ctx.Arguments.SensitivityLevel == RiskHigh {
+		risk = RiskHigh
+	}
+	if ctx.IntegrityState == "INTEGRITY_DEGRADED" && risk != RiskLow {
+		return &DecisionResult{Decision: DENY, Reason: "integrity_degraded"}, nil
+	}
+	// Unknown risk fails closed inside RequiresConfirmation. WHY: P4 is
+	// read-only and an unknown risk cannot be weighed, so no confirmation
+	// can lift either.
+	policy := ctx.PosturePolicy
+	if policy == nil {
+		policy = posture.DefaultPolicy()
+	}
+	confirmed := false
+	if policy.RequiresConfirmation(ctx.PostureLevel, risk) {
+		if ctx.PostureLevel >= posture.P4 || (risk != RiskLow && risk != RiskMedium && risk != RiskHigh) {
+			return &DecisionResult{Decision: DENY, Reason: "proposal_requires_confirmation"}, nil
+		}
+		if !ctx.Confirmed {
+			return &DecisionResult{Decision: DENY, Reason: "proposal_requires_confirmation", Metadata: map[string]interface{}{"obligation": ObligationConfirm}}, nil
+		}
+		confirmed = true
+	}
+
+	return &DecisionResult{
+		Decision:        ALLOW,
+		Reason:          "proposal_approved",
+		RequiredPosture: ctx.PostureLevel,
+		Metadata:        map[string]interface{}{"action": ctx.Action, "risk": risk, "confirmed": confirmed},
+	}, nil
+}
@@ -0,0 +1,82 @@
+// WHY: These tests prove adapter proposals are judged on their own:
+// tainted arguments, missing consent, and risk beyond the posture are
+// refused unless the user confirms.
+package cdi
+
+import (
+	"testing"
+
+	"github.com/user/oi/kernel-go/internal/cif"
+)
+
+// TestDecideProposalGatesRiskByPosture proves tainted arguments, unknown
+// risk, and risk needing confirmation are denied, and clean low risk passes
+func TestDecideProposalGatesRiskByPosture(t *testing.T) {
+	clean := &cif.LabeledRequest{TaintLabels: []string{"clean"}, SensitivityLevel: "low"}
+	tainted := &cif.LabeledRequest{TaintLabels: []string{"pressure_tactic"}, SensitivityLevel: "low"}
+
+	cases := []struct {
+		name    string
+		ctx     ProposalContext
+		want    Decision
+		because string
+	}{
+		{"low risk at P1", ProposalContext{Arguments: clean, Risk: RiskLow, PostureLevel: 1}, ALLOW, "proposal_approved"},
+		{"high risk at P1", ProposalContext{Arguments: clean, Risk: RiskHigh, PostureLevel: 1}, ALLOW, "proposal_approved"},
+		{"high risk at P2", ProposalContext{Arguments: clean, Risk: RiskHigh, PostureLevel: 2}, DENY, "proposal_requires_confirmation"},
+		{"low risk at P4", ProposalContext{Arguments: clean, Risk: RiskLow, PostureLevel: 4}, DENY, "proposal_requires_confirmation"},
+		{"unknown risk", ProposalContext{Arguments: clean, Risk: "", PostureLevel: 1}, DENY, "proposal_requires_confirmation"},
+		{"tainted arguments", ProposalContext{Arguments: tainted, Risk: RiskLow, PostureLevel: 1}, DENY, "tainted_proposal"},
+		{"undefined posture", ProposalContext{Arguments: clean, Risk: RiskLow, PostureLevel: 0}, DENY, "undefined_posture"},
+		{"degraded integrity", ProposalContext{Arguments: clean, Risk: RiskMedium, PostureLevel: 1, IntegrityState: "INTEGRITY_DEGRADED"}, DENY, "integrity_degraded"},
+	}
+	for _, tc := range cases {
+		result, err := DecideProposal(&tc.ctx)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if result.Decision != tc.want || result.Reason != tc.because {
+			t.Fatalf("%s: got %s (%s), want %s (%s)", tc.name, result.Decision, result.Reason, tc.want, tc.because)
+		}
+		if result.DecisionID == "" {
+			t.Fatalf("%s: proposal decisions must carry an ID", tc.name)
+		}
+	}
+
+	if result, err := DecideProposal(nil); err == nil || result.Decision != DENY {
+		t.Fatal("nil proposal must be denied with an error")
+	}
+}
+
+// TestProposalConsentAndConfirmation proves a required consent gates the
+// action first, a confirmation lifts only the obligation it was asked for,
+// and nothing lifts P4 or an unknown risk
+func TestProposalConsentAndConfirmation(t *testing.T) {
+	clean := &cif.LabeledRequest{TaintLabels: []string{"clean"}, SensitivityLevel: "low"}
+	consents := map[string]bool{"outbound_email": true}
+
+	cases := []struct {
+		name       string
+		ctx        ProposalContext
+		want       Decision
+		because    string
+		obligation bool
+	}{
+		{"missing consent", ProposalContext{Arguments: clean, Risk: RiskHigh, PostureLevel: 1, Consent: "outbound_email"}, DENY, "proposal_requires_consent", false},
+		{"missing consent confirmed", ProposalContext{Arguments: clean, Risk: RiskHigh, PostureLevel: 2, Consent: "outbound_email", Confirmed: true}, DENY, "proposal_requires_consent", false},
+		{"consent at P1", ProposalContext{Arguments: clean, Risk: RiskHigh, PostureLevel: 1, Consent: "outbound_email", ActiveConsents: consents}, ALLOW, "proposal_approved", false},
+		{"unconfirmed at P2", ProposalContext{Arguments: clean, Risk: RiskHigh, PostureLevel: 2, Consent: "outbound_email", ActiveConsents: consents}, DENY, "proposal_requires_confirmation", true},
+		{"confirmed at P2", ProposalContext{Arguments: clean, Risk: RiskHigh, PostureLevel: 2, Consent: "outbound_email", ActiveConsents: consents, Confirmed: true}, ALLOW, "proposal_approved", false},
+		{"confirmed at P4", ProposalContext{Arguments: clean, Risk: RiskLow, PostureLevel: 4, Confirmed: true}, DENY, "proposal_requires_confirmation", false},
+		{"confirmed unknown risk", ProposalContext{Arguments: clean, Risk: "", PostureLevel: 1, Confirmed: true}, DENY, "proposal_requires_confirmation", false},
+	}
+	for _, tc := range cases {
+		result, _ := DecideProposal(&tc.ctx)
+		if result.Decision != tc.want || result.Reason != tc.because {
+			t.Fatalf("%s: got %s (%s), want %s (%s)", tc.name, result.Decision, result.Reason, tc.want, tc.because)
+		}
+		if obligation := result.Metadata["obligation"] == ObligationConfirm; obligation != tc.obligation {
+			t.Fatalf("%s: confirm obligation %v, want %v", tc.name, obligation, tc.obligation)
+		}
+	}
+}
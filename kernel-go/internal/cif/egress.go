@@ -6,6 +6,8 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"strings"
+
+	"github.com/user/oi/kernel-go/internal/posture"
 )
 
 // OutputArtifact represents processed output ready for egress control
@@ -24,14 +26,40 @@ type UserResponse struct {
 	Redacted     bool
 	RedactionReason string
 	OutputHash   string
+
+	// Declassified is set when a declassification lifted the posture
+	// redaction
+	Declassified bool
 }
 
-// Egress processes output artifacts and applies leak control.
+// Declassifier reports whether content with the given hash has been
+// declassified, lifting its posture redaction
+type Declassifier func(contentHash string) bool
+
+// Egress processes output artifacts and applies leak control under the
+// default posture policy, with leakBudget in place of the policy's.
 // WHY: Output shaping prevents disallowed emissions.
 func Egress(artifact *OutputArtifact, postureLevel int, leakBudget int) (*UserResponse, error) {
+	policy := posture.DefaultPolicy().Level(postureLevel)
+	policy.LeakBudget = leakBudget
+	return EgressWithPolicy(artifact, policy)
+}
+
+// EgressWithPolicy processes output artifacts and applies leak control
+// under the policy of the current posture level
+func EgressWithPolicy(artifact *OutputArtifact, policy posture.LevelPolicy) (*UserResponse, error) {
+	return EgressDeclassified(artifact, policy, nil)
+}
+
+// EgressDeclassified is EgressWithPolicy, except that content declassified
+// reports as declassified is not redacted for the posture.
+// WHY: Only the posture redaction is a classification; the leak budget and
+// bypass checks hold whatever was declassified.
+func EgressDeclassified(artifact *OutputArtifact, policy posture.LevelPolicy, declassified Declassifier) (*UserResponse, error) {
 	content := artifact.Content
 	redacted := false
 	redactionReason := ""
+	lifted := false
 
 	// Compute hash of original content
 	h := sha256.New()
@@ -39,17 +67,21 @@ func Egress(artifact *OutputArtifact, postureLevel int, leakBudget int) (*UserRe
 	outputHash := hex.EncodeToString(h.Sum(nil))
 
 	// Apply leak budget constraints
-	if artifact.LeakBudgetUsed > leakBudget {
-		content = redactOverBudget(content, leakBudget)
+	if artifact.LeakBudgetUsed > policy.LeakBudget {
+		content = redactOverBudget(content, policy.LeakBudget)
 		redacted = true
 		redactionReason = "leak_budget_exceeded"
 	}
 
 	// Apply posture-based redaction
-	if shouldRedactByPosture(artifact.SensitivityLevel, postureLevel) {
-		content = redactSensitive(content)
-		redacted = true
-		redactionReason = "posture_constraint"
+	if !policy.Permits(artifact.SensitivityLevel) {
+		if declassified != nil && declassified(outputHash) {
+			lifted = true
+		} else {
+			content = redactSensitive(content)
+			redacted = true
+			redactionReason = "posture_constraint"
+		}
 	}
 
 	// Check for instruction smuggling in output
@@ -64,6 +96,7 @@ func Egress(artifact *OutputArtifact, postureLevel int, leakBudget int) (*UserRe
 		Redacted:        redacted,
 		RedactionReason: redactionReason,
 		OutputHash:      outputHash,
+		Declassified:    lifted,
 	}, nil
 }
 
@@ -75,21 +108,6 @@ func redactOverBudget(content string, budget int) string {
 	return content[:budget] + "\n[REDACTED: leak budget exceeded]"
 }
 
-// shouldRedactByPosture checks if content should be redacted based on posture
-func shouldRedactByPosture(sensitivity string, posture int) bool {
-	// Higher postures have stricter redaction
-	switch sensitivity {
-	case "high":
-		return posture >= 2 // Redact high sensitivity at P2+
-	case "medium":
-		return posture >= 3 // Redact medium sensitivity at P3+
-	case "low":
-		return false // Never redact low sensitivity
-	default:
-		return true // Unknown sensitivity - fail closed
-	}
-}
-
 // redactSensitive applies redaction patterns
 func redactSensitive(content string) string {
 	// Simple redaction - in production this would be more sophisticated
@@ -0,0 +1,178 @@
+// WHY: oi-server and `oi-kernel serve` are one server under two names, so
+// they share this code rather than a copy that drifts. Serve refuses to
+// start without an identity issuer, so every request runs as the
+// principal its bearer token names. SIGUSR1, SIGTERM, and the stop file
+// pull STOP from the host; SIGTERM then shuts the server down, while an
+// interrupt quiesces it first. The admin surface is served only on its own
+// address, apart from the corridor, and only to the admin issuer's tokens.
+// Receipts are copied to every configured sink while the server runs.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/config"
+	"github.com/user/oi/kernel-go/internal/server"
+)
+
+// shutdownTimeout bounds how long open connections are waited on
+const shutdownTimeout = 5 * time.Second
+
+// Serve configures a kernel from args, serves it until shut down, and
+// returns the process exit code; name prefixes its messages
+func Serve(name string, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var cfg config.Config
+	cfg.Bind(flags, config.Identity|config.Admin|config.Serve)
+	if err := cfg.Parse(flags, args); err != nil {
+		return 2
+	}
+	if cfg.Identity.JWKS == "" {
+		fmt.Fprintf(stderr, "%s: -identity-jwks is required\n", name)
+		return 2
+	}
+
+	k, err := cfg.Build("server_principal", "server_namespace")
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", name, err)
+		return config.ExitCode(err)
+	}
+	defer k.Close()
+	state := k.State
+
+	// WHY: /readyz reports the last ledger verification, so one runs
+	// before the first probe and then on a schedule; a failure voids
+	// integrity and takes the server out of rotation
+	if err := state.VerifyAndEnforce(); err != nil {
+		fmt.Fprintf(stderr, "%s: ledger does not verify: %v\n", name, err)
+	}
+	verifier, err := state.StartLedgerVerifier(time.Duration(cfg.Serve.VerifyInterval))
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", name, err)
+		return 2
+	}
+	defer verifier.Stop()
+
+	// WHY: Receipts copied off the box survive a host that is taken over;
+	// each forwarder resumes where the ledger records its sink leaving off
+	sinks, err := cfg.Serve.Forward.Sinks()
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", name, err)
+		return config.ExitCode(err)
+	}
+	for _, sink := range sinks {
+		forwarder, err := state.ForwardAudit(sink, audit.ForwardPolicy{})
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", name, err)
+			return 1
+		}
+		defer forwarder.Stop()
+		fmt.Fprintf(stdout, "%s: forwarding receipts to %s\n", name, sink.Name())
+	}
+
+	listener, err := net.Listen("tcp", cfg.Serve.Addr)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", name, err)
+		return 1
+	}
+	options := server.Options{MaxBodyBytes: cfg.Serve.MaxBodyBytes}
+	httpServer := &http.Server{
+		Handler:           server.New(state, options),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	servers := []*http.Server{httpServer}
+	listeners := []net.Listener{listener}
+	extra := []struct {
+		name string
+		addr string
+		srv  *http.Server
+	}{
+		{"gRPC", cfg.Serve.GRPCAddr, &http.Server{Handler: server.NewGRPC(state, options), Protocols: new(http.Protocols)}},
+		{"admin", cfg.Serve.AdminAddr, &http.Server{Handler: state.AdminHandler()}},
+	}
+	extra[0].srv.Protocols.SetUnencryptedHTTP2(true)
+	for _, e := range extra {
+		if e.addr == "" {
+			continue
+		}
+		l, err := net.Listen("tcp", e.addr)
+		if err != nil {
+			for _, open := range listeners {
+				open.Close()
+			}
+			fmt.Fprintf(stderr, "%s: %v\n", name, err)
+			return 1
+		}
+		e.srv.ReadHeaderTimeout = 10 * time.Second
+		servers = append(servers, e.srv)
+		listeners = append(listeners, l)
+		fmt.Fprintf(stdout, "%s: serving %s on %s\n", name, e.name, l.Addr())
+	}
+
+	// WHY: SIGTERM has already pulled STOP when it is reported, so the
+	// server only has to stop serving
+	terminated := make(chan struct{}, 1)
+	disarm, err := state.ArmHostStop(cfg.StopFile, func(trigger string) {
+		fmt.Fprintf(stderr, "%s: STOP pulled by %s\n", name, trigger)
+		if trigger == "signal:"+syscall.SIGTERM.String() {
+			select {
+			case terminated <- struct{}{}:
+			default:
+			}
+		}
+	})
+	if err != nil {
+		for _, l := range listeners {
+			l.Close()
+		}
+		fmt.Fprintf(stderr, "%s: %v\n", name, err)
+		return 2
+	}
+	defer disarm()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	served := make(chan error, len(servers))
+	for i, srv := range servers {
+		go func() { served <- srv.Serve(listeners[i]) }()
+	}
+	fmt.Fprintf(stdout, "%s: serving on %s\n", name, listener.Addr())
+
+	select {
+	case err := <-served:
+		fmt.Fprintf(stderr, "%s: %v\n", name, err)
+		return 1
+	case <-terminated:
+	case <-interrupt:
+		summary, err := state.Quiesce(time.Duration(cfg.Serve.Quiesce))
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: quiesce: %v\n", name, err)
+		}
+		fmt.Fprintf(stdout, "%s: quiesced (drained: %t, %d tokens revoked)\n", name, summary.Drained, summary.TokensRevoked)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	code := 0
+	for _, srv := range servers {
+		if err := srv.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(stderr, "%s: shutdown: %v\n", name, err)
+			code = 1
+		}
+	}
+	return code
+}
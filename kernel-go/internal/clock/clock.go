@@ -0,0 +1,85 @@
+// WHY: Timestamps on memory entries, posture transitions, tokens, and
+// receipts are evidence, so they must be real, and tests that check
+// expiry or ordering must not sleep. Every component that stamps time
+// reads it from a Clock: Real in production, Fake in tests, and Monotonic
+// where a wall clock stepping backwards would reorder a record.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock reports the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// Real reads the system clock
+type Real struct{}
+
+// Now returns time.Now()
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// Fake is a clock that moves only when told to
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFake returns a fake clock stopped at start
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start}
+}
+
+// Now returns the fake clock's current time
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Advance moves the fake clock forward by d
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+// Set moves the fake clock to t, which may be in its past
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = t
+}
+
+// monotonic never reports a time before one it already reported
+type monotonic struct {
+	mu   sync.Mutex
+	base Clock
+	last time.Time
+}
+
+// Monotonic wraps base so its readings never go backwards. WHY: Unix
+// timestamps drop Go's monotonic reading, so an NTP step would otherwise
+// stamp a later record earlier than the one before it.
+func Monotonic(base Clock) Clock {
+	if base == nil {
+		base = Real{}
+	}
+	return &monotonic{base: base}
+}
+
+// Now returns the later of base's time and the last reading
+func (m *monotonic) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.base.Now()
+	if now.Before(m.last) {
+		return m.last
+	}
+	m.last = now
+	return now
+}
@@ -0,0 +1,47 @@
+// WHY: These tests prove a fake clock moves only when told to and a
+// monotonic clock never reports a step backwards.
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+// TestFakeMovesOnlyWhenTold proves Advance and Set are the only way a
+// fake clock changes
+func TestFakeMovesOnlyWhenTold(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	fake := NewFake(start)
+	if !fake.Now().Equal(start) || !fake.Now().Equal(start) {
+		t.Fatal("a fake clock must stand still")
+	}
+	fake.Advance(time.Minute)
+	if got := fake.Now(); !got.Equal(start.Add(time.Minute)) {
+		t.Fatalf("expected one minute later, got %v", got)
+	}
+	fake.Set(start)
+	if !fake.Now().Equal(start) {
+		t.Fatal("Set must move the clock, even backwards")
+	}
+}
+
+// TestMonotonicNeverGoesBackwards proves a step back in the base clock is
+// held at the last reading until the base catches up
+func TestMonotonicNeverGoesBackwards(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	fake := NewFake(start)
+	clock := Monotonic(fake)
+
+	clock.Now()
+	fake.Set(start.Add(-time.Hour))
+	if got := clock.Now(); !got.Equal(start) {
+		t.Fatalf("a step backwards must be held at %v, got %v", start, got)
+	}
+	fake.Set(start.Add(time.Second))
+	if got := clock.Now(); !got.Equal(start.Add(time.Second)) {
+		t.Fatalf("the clock must follow its base forwards, got %v", got)
+	}
+	if Monotonic(nil).Now().IsZero() {
+		t.Fatal("a monotonic clock without a base must read the system clock")
+	}
+}
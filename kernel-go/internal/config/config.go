@@ -0,0 +1,342 @@
+// WHY: Every binary and subcommand stands a kernel up the same way, from
+// the same ledger, key, adapter, governance, and identity settings. Each
+// used to declare its own flags and repeat the setup, so the binaries
+// drifted apart: one had -memory, the others did not. Config declares
+// those settings once, as flags, as a JSON file (-config), or both, with
+// flags overriding the file. It builds the kernel in one order for every
+// caller and checks the same rules everywhere: a governance bundle only
+// under its pin, memory only beside a ledger, an issuer only with its keys.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/kernel"
+	"github.com/user/oi/kernel-go/internal/server"
+)
+
+// Section is a group of settings a command takes beyond the kernel's own
+type Section int
+
+const (
+	// Identity is the issuer bearer tokens are verified against
+	Identity Section = 1 << iota
+
+	// Admin is the issuer admin tokens are verified against
+	Admin
+
+	// Serve is where and how a server listens
+	Serve
+)
+
+// Config is how a kernel is stood up. Paths in a -config file are relative
+// to the file.
+type Config struct {
+	Ledger        string `json:"ledger,omitempty"`
+	Key           string `json:"key,omitempty"`
+	Signer        string `json:"signer,omitempty"`
+	Memory        string `json:"memory,omitempty"`
+	Adapters      string `json:"adapters,omitempty"`
+	OpenAIURL     string `json:"openai_url,omitempty"`
+	Model         string `json:"model,omitempty"`
+	Governance    string `json:"governance,omitempty"`
+	GovernancePin string `json:"governance_pin,omitempty"`
+	StopFile      string `json:"stop_file,omitempty"`
+
+	Identity Issuer   `json:"identity,omitempty"`
+	Admin    Issuer   `json:"admin,omitempty"`
+	Serve    Listener `json:"serve,omitempty"`
+
+	// file is the -config file and sections those bound to flags
+	file     string
+	sections Section
+}
+
+// Issuer is an identity issuer: its key set and the issuer and audience
+// its tokens must name
+type Issuer struct {
+	JWKS     string `json:"jwks,omitempty"`
+	Issuer   string `json:"issuer,omitempty"`
+	Audience string `json:"audience,omitempty"`
+}
+
+// Listener is where a server listens and how it winds down
+type Listener struct {
+	Addr         string   `json:"addr,omitempty"`
+	GRPCAddr     string   `json:"grpc_addr,omitempty"`
+	AdminAddr    string   `json:"admin_addr,omitempty"`
+	MaxBodyBytes int64    `json:"max_body_bytes,omitempty"`
+	Quiesce      Duration `json:"quiesce,omitempty"`
+
+	// VerifyInterval is how often the ledger is verified while serving
+	VerifyInterval Duration `json:"verify_interval,omitempty"`
+
+	// Forward names the sinks receipts are copied to while serving
+	Forward Forward `json:"forward,omitempty"`
+}
+
+// Forward names the off-box sinks a server copies its receipts to. A
+// webhook's bearer token is read from OI_FORWARD_WEBHOOK_TOKEN.
+type Forward struct {
+	// Syslog is a collector as udp://host:port or tcp://host:port
+	Syslog string `json:"syslog,omitempty"`
+
+	// Webhook is an http or https URL batches are POSTed to
+	Webhook string `json:"webhook,omitempty"`
+
+	// Kafka is a broker's host:port, leading partition 0 of KafkaTopic
+	Kafka      string `json:"kafka,omitempty"`
+	KafkaTopic string `json:"kafka_topic,omitempty"`
+}
+
+// Duration is a time.Duration written as "10s" in flags and files
+type Duration time.Duration
+
+// Set parses a flag value
+func (d *Duration) Set(value string) error {
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d *Duration) String() string {
+	return time.Duration(*d).String()
+}
+
+// UnmarshalJSON reads a duration string such as "10s"
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("duration must be a string such as \"10s\": %w", err)
+	}
+	return d.Set(value)
+}
+
+// MarshalJSON writes the duration as a string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Bind declares -config, the kernel's settings, and those of sections as
+// flags that set c
+func (c *Config) Bind(flags *flag.FlagSet, sections Section) {
+	c.sections = sections
+	flags.StringVar(&c.file, "config", "", "JSON config file; flags given as well override it")
+	flags.StringVar(&c.Ledger, "ledger", "", "JSONL file to persist audit receipts (default: in memory)")
+	flags.StringVar(&c.Key, "key", "", "PEM Ed25519 key for signing receipts (default: <ledger>.key, generated on first use, or a fresh key per run without -ledger)")
+	flags.StringVar(&c.Signer, "signer", "", "keep the signing key out of process instead of -key: keychain:SERVICE/ACCOUNT, or remote:URL of a signing service (bearer token from OI_SIGNER_TOKEN)")
+	flags.StringVar(&c.Memory, "memory", "", "directory persisting durable, commitments, provenance, and evidence memory (default: in memory)")
+	flags.StringVar(&c.Adapters, "adapters", "", "JSON adapter manifest to register at startup")
+	flags.StringVar(&c.OpenAIURL, "openai-url", "", "OpenAI-compatible API root to route requests to (API key from OPENAI_API_KEY)")
+	flags.StringVar(&c.Model, "model", "", "model name for -openai-url")
+	flags.StringVar(&c.Governance, "governance", "", "signed governance bundle to load at startup")
+	flags.StringVar(&c.GovernancePin, "governance-pin", "", "fingerprint of the key -governance must be signed with")
+	flags.StringVar(&c.StopFile, "stop-file", "", "trigger file (e.g. a GPIO value file) that pulls a global STOP when set to anything but 0")
+	if sections&Identity != 0 {
+		flags.StringVar(&c.Identity.JWKS, "identity-jwks", "", "JSON Web Key Set of the identity issuer")
+		flags.StringVar(&c.Identity.Issuer, "identity-issuer", "", "issuer bearer tokens must name, for -identity-jwks")
+		flags.StringVar(&c.Identity.Audience, "identity-audience", "", "audience bearer tokens must name, for -identity-jwks")
+	}
+	if sections&Admin != 0 {
+		flags.StringVar(&c.Admin.JWKS, "admin-jwks", "", "JSON Web Key Set of the admin token issuer")
+		flags.StringVar(&c.Admin.Issuer, "admin-issuer", "", "issuer admin tokens must name")
+		flags.StringVar(&c.Admin.Audience, "admin-audience", "", "audience admin tokens must name, apart from -identity-audience")
+	}
+	if sections&Serve != 0 {
+		c.Serve.Quiesce = Duration(10 * time.Second)
+		c.Serve.VerifyInterval = Duration(kernel.DefaultVerifyInterval)
+		flags.StringVar(&c.Serve.Addr, "addr", ":8080", "address to listen on")
+		flags.StringVar(&c.Serve.GRPCAddr, "grpc-addr", "", "address to serve the gRPC contract on over unencrypted HTTP/2 (default: off)")
+		flags.StringVar(&c.Serve.AdminAddr, "admin-addr", "", "address to serve the admin surface on (default: off)")
+		flags.Int64Var(&c.Serve.MaxBodyBytes, "max-body", server.DefaultMaxBodyBytes, "largest request body accepted, in bytes")
+		flags.Var(&c.Serve.Quiesce, "quiesce", "how long in-flight requests may drain on interrupt before a STOP")
+		flags.Var(&c.Serve.VerifyInterval, "verify-interval", "how often the ledger is verified while serving; a failure voids integrity and fails /readyz")
+		flags.StringVar(&c.Serve.Forward.Syslog, "forward-syslog", "", "syslog collector to copy receipts to, as udp://host:port or tcp://host:port")
+		flags.StringVar(&c.Serve.Forward.Webhook, "forward-webhook", "", "URL to POST receipt batches to (bearer token from OI_FORWARD_WEBHOOK_TOKEN)")
+		flags.StringVar(&c.Serve.Forward.Kafka, "forward-kafka", "", "Kafka broker (host:port) leading partition 0 of -forward-kafka-topic")
+		flags.StringVar(&c.Serve.Forward.KafkaTopic, "forward-kafka-topic", "", "Kafka topic to copy receipts to, for -forward-kafka")
+	}
+}
+
+// Parse parses args into c, reading the -config file if one is named, and
+// checks the result. Every error is a usage error and is reported on the
+// flags' output.
+func (c *Config) Parse(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		return invalid(err)
+	}
+	err := c.parse(flags)
+	if err != nil {
+		fmt.Fprintf(flags.Output(), "%s: %v\n", flags.Name(), err)
+	}
+	return err
+}
+
+// parse applies the -config file under the flags given and checks c
+func (c *Config) parse(flags *flag.FlagSet) error {
+	if c.file != "" {
+		// WHY: Decoding over c leaves what the file omits as the flags
+		// set it, so only the flags given must be set again over the file
+		given := map[string]string{}
+		flags.Visit(func(f *flag.Flag) { given[f.Name] = f.Value.String() })
+		if err := c.load(c.file); err != nil {
+			return err
+		}
+		for name, value := range given {
+			flags.Set(name, value)
+		}
+	}
+	return c.Check()
+}
+
+// load reads the JSON file at path over c
+func (c *Config) load(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return invalid(fmt.Errorf("config: %w", err))
+	}
+	defer file.Close()
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(c); err != nil {
+		return invalid(fmt.Errorf("config %s: %w", path, err))
+	}
+	// WHY: A section the command does not take is not applied, so one
+	// file can serve every subcommand
+	if c.sections&Identity == 0 {
+		c.Identity = Issuer{}
+	}
+	if c.sections&Admin == 0 {
+		c.Admin = Issuer{}
+	}
+	if c.sections&Serve == 0 {
+		c.Serve = Listener{}
+	}
+	dir := filepath.Dir(path)
+	for _, p := range []*string{&c.Ledger, &c.Key, &c.Memory, &c.Adapters, &c.Governance, &c.StopFile, &c.Identity.JWKS, &c.Admin.JWKS} {
+		if *p != "" && !filepath.IsAbs(*p) {
+			*p = filepath.Join(dir, *p)
+		}
+	}
+	return nil
+}
+
+// Check reports settings that are wrong in themselves
+func (c *Config) Check() error {
+	switch {
+	case (c.Governance == "") != (c.GovernancePin == ""):
+		return invalid(errors.New("-governance and -governance-pin go together"))
+	case c.Memory != "" && c.Ledger == "":
+		// WHY: Persistent evidence holds the ledger's checkpoints, so it
+		// must persist alongside the ledger it checkpoints
+		return invalid(errors.New("-memory requires -ledger"))
+	case c.Identity.JWKS == "" && (c.Identity.Issuer != "" || c.Identity.Audience != ""):
+		return invalid(errors.New("-identity-issuer and -identity-audience require -identity-jwks"))
+	case c.Admin.JWKS == "" && (c.Admin.Issuer != "" || c.Admin.Audience != ""):
+		return invalid(errors.New("-admin-issuer and -admin-audience require -admin-jwks"))
+	case c.sections&Serve != 0 && (c.Serve.AdminAddr == "") != (c.Admin.JWKS == ""):
+		return invalid(errors.New("-admin-addr and -admin-jwks go together"))
+	case c.Model != "" && c.OpenAIURL == "":
+		return invalid(errors.New("-model requires -openai-url"))
+	case c.Signer != "" && c.Key != "":
+		return invalid(errors.New("-signer and -key name two keys; give one"))
+	case c.sections&Serve != 0 && c.Serve.VerifyInterval <= 0:
+		return invalid(errors.New("-verify-interval must be positive"))
+	}
+	if c.Signer != "" {
+		if _, _, err := c.signerBackend(); err != nil {
+			return invalid(err)
+		}
+	}
+	return c.Serve.Forward.check()
+}
+
+// signerBackend splits -signer into its backend and where the key is
+func (c *Config) signerBackend() (string, string, error) {
+	backend, location, _ := strings.Cut(c.Signer, ":")
+	switch {
+	case backend == "keychain" && strings.Count(location, "/") == 1 && !strings.HasPrefix(location, "/") && !strings.HasSuffix(location, "/"):
+		return backend, location, nil
+	case backend == "remote":
+		if u, err := url.Parse(location); err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" {
+			return backend, location, nil
+		}
+	}
+	return "", "", fmt.Errorf("-signer %q must be keychain:SERVICE/ACCOUNT or remote:URL", c.Signer)
+}
+
+// check reports sinks named wrongly
+func (f Forward) check() error {
+	if f.Syslog != "" {
+		if _, _, err := f.syslogAddress(); err != nil {
+			return invalid(err)
+		}
+	}
+	if f.Webhook != "" {
+		u, err := url.Parse(f.Webhook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return invalid(fmt.Errorf("-forward-webhook %q must be an http or https URL", f.Webhook))
+		}
+	}
+	if (f.Kafka == "") != (f.KafkaTopic == "") {
+		return invalid(errors.New("-forward-kafka and -forward-kafka-topic go together"))
+	}
+	if f.Kafka != "" {
+		if _, _, err := net.SplitHostPort(f.Kafka); err != nil {
+			return invalid(fmt.Errorf("-forward-kafka %q must be host:port", f.Kafka))
+		}
+	}
+	return nil
+}
+
+// syslogAddress splits -forward-syslog into its network and address
+func (f Forward) syslogAddress() (string, string, error) {
+	u, err := url.Parse(f.Syslog)
+	if err != nil || (u.Scheme != "udp" && u.Scheme != "tcp") || u.Host == "" || u.Port() == "" {
+		return "", "", fmt.Errorf("-forward-syslog %q must be udp://host:port or tcp://host:port", f.Syslog)
+	}
+	return u.Scheme, u.Host, nil
+}
+
+// ErrInvalid marks a configuration that is wrong in itself, rather than
+// one that failed to load
+var ErrInvalid = errors.New("invalid configuration")
+
+// invalidError is an error marked ErrInvalid
+type invalidError struct {
+	err error
+}
+
+func (e *invalidError) Error() string {
+	return e.err.Error()
+}
+
+func (e *invalidError) Unwrap() []error {
+	return []error{e.err, ErrInvalid}
+}
+
+// invalid marks err ErrInvalid
+func invalid(err error) error {
+	return &invalidError{err: err}
+}
+
+// ExitCode is the process exit code for err: 2 for an invalid
+// configuration, 1 otherwise
+func ExitCode(err error) int {
+	if errors.Is(err, ErrInvalid) {
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,288 @@
+// WHY: These tests prove every command reads the same settings the same
+// way: a file under the flags given, paths relative to the file, only the
+// sections a command takes, and the same refusals of settings that are
+// wrong in themselves.
+package config
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/kernel"
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+// parse binds sections to a fresh flag set and parses args
+func parse(t *testing.T, sections Section, args ...string) (*Config, error) {
+	t.Helper()
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(new(bytes.Buffer))
+	cfg := new(Config)
+	cfg.Bind(flags, sections)
+	return cfg, cfg.Parse(flags, args)
+}
+
+// writeConfig writes a config file and returns its path
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "oi.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+// TestFileUnderFlags proves a config file supplies what the flags do not,
+// flags given override it, and its paths are relative to it
+func TestFileUnderFlags(t *testing.T) {
+	path := writeConfig(t, `{
+		"ledger": "receipts.jsonl",
+		"key": "/etc/oi/audit_key.pem",
+		"identity": {"jwks": "keys.json", "issuer": "https://issuer.example", "audience": "oi-kernel"},
+		"serve": {"addr": ":9000", "quiesce": "3s"}
+	}`)
+	dir := filepath.Dir(path)
+
+	cfg, err := parse(t, Identity|Serve, "-config", path, "-identity-audience", "other", "-grpc-addr", ":9090")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if cfg.Ledger != filepath.Join(dir, "receipts.jsonl") || cfg.Identity.JWKS != filepath.Join(dir, "keys.json") {
+		t.Fatalf("relative paths must resolve against the file: %+v", cfg)
+	}
+	if cfg.Key != "/etc/oi/audit_key.pem" {
+		t.Fatalf("an absolute path must be kept, got %s", cfg.Key)
+	}
+	if cfg.Identity.Audience != "other" || cfg.Identity.Issuer != "https://issuer.example" {
+		t.Fatalf("a flag given must override the file and only its own setting: %+v", cfg.Identity)
+	}
+	if cfg.Serve.Addr != ":9000" || cfg.Serve.GRPCAddr != ":9090" || time.Duration(cfg.Serve.Quiesce) != 3*time.Second {
+		t.Fatalf("serve settings: %+v", cfg.Serve)
+	}
+
+	cfg, err = parse(t, Serve)
+	if err != nil || cfg.Serve.Addr != ":8080" || time.Duration(cfg.Serve.Quiesce) != 10*time.Second {
+		t.Fatalf("without a file the flag defaults hold: %+v (%v)", cfg.Serve, err)
+	}
+}
+
+// TestSectionsNotTakenAreIgnored proves one file serves every command: a
+// command that takes no identity issuer is not given one by the file
+func TestSectionsNotTakenAreIgnored(t *testing.T) {
+	path := writeConfig(t, `{"identity": {"jwks": "keys.json"}, "admin": {"jwks": "admin.json"}, "serve": {"addr": ":9000"}}`)
+	cfg, err := parse(t, 0, "-config", path)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if cfg.Identity != (Issuer{}) || cfg.Admin != (Issuer{}) || cfg.Serve != (Listener{}) {
+		t.Fatalf("sections not bound must be dropped: %+v", cfg)
+	}
+}
+
+// TestRefusesInvalidSettings proves settings wrong in themselves are
+// refused as invalid, from flags or a file
+func TestRefusesInvalidSettings(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"governance without pin", []string{"-governance", "bundle.json"}, "go together"},
+		{"memory without ledger", []string{"-memory", "dir"}, "-memory requires -ledger"},
+		{"issuer without keys", []string{"-identity-issuer", "https://issuer.example"}, "require -identity-jwks"},
+		{"admin address without issuer", []string{"-admin-addr", "127.0.0.1:0"}, "-admin-addr and -admin-jwks go together"},
+		{"model without url", []string{"-model", "gpt"}, "-model requires -openai-url"},
+		{"unknown file field", []string{"-config", writeConfig(t, `{"ledgr": "x"}`)}, "unknown field"},
+		{"malformed duration", []string{"-config", writeConfig(t, `{"serve": {"quiesce": 10}}`)}, "duration"},
+		{"no ledger verification", []string{"-verify-interval", "0s"}, "-verify-interval must be positive"},
+		{"syslog without a scheme", []string{"-forward-syslog", "collector:514"}, "-forward-syslog"},
+		{"webhook not over http", []string{"-forward-webhook", "ftp://collector/receipts"}, "-forward-webhook"},
+		{"kafka without topic", []string{"-forward-kafka", "broker:9092"}, "go together"},
+		{"signer without a backend", []string{"-signer", "vault:signing"}, "-signer"},
+		{"signer and key", []string{"-signer", "keychain:oi/kernel", "-key", "kernel.pem"}, "give one"},
+		{"missing file", []string{"-config", filepath.Join(t.TempDir(), "missing.json")}, "config"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parse(t, Identity|Admin|Serve, tc.args...)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("want an error containing %q, got %v", tc.want, err)
+			}
+			if !errors.Is(err, ErrInvalid) || ExitCode(err) != 2 {
+				t.Fatalf("a setting wrong in itself must be a usage error: %v", err)
+			}
+		})
+	}
+}
+
+// TestBuildAndValidate proves Build stands up the configured kernel and
+// refuses one it cannot load whole, and Validate finds the same problems
+// without writing the ledger
+func TestBuildAndValidate(t *testing.T) {
+	dir := t.TempDir()
+	ledgerPath := filepath.Join(dir, "receipts.jsonl")
+	cfg := &Config{Ledger: ledgerPath}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("a ledger yet to be written is valid: %v", err)
+	}
+	if _, err := os.Stat(ledgerPath); !errors.Is(err, os.ErrNotExist) {
+		t.Fatal("Validate must not create the ledger")
+	}
+
+	k, err := cfg.Build("test_principal", "test_namespace")
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	if len(k.State.AuditLedger.GetReceipts()) == 0 {
+		t.Fatal("the built kernel must write to the configured ledger")
+	}
+	if err := k.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("an untampered ledger is valid: %v", err)
+	}
+
+	bad := &Config{
+		Adapters:      filepath.Join(dir, "missing.json"),
+		Governance:    filepath.Join(dir, "bundle.json"),
+		GovernancePin: "0000",
+		Ledger:        filepath.Join(dir, "no", "such", "dir", "receipts.jsonl"),
+	}
+	err = bad.Validate()
+	for _, want := range []string{"missing.json", "bundle.json", "does not exist"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("Validate must report every problem, missing %q in: %v", want, err)
+		}
+	}
+	if _, err := bad.Build("test_principal", "test_namespace"); err == nil {
+		t.Fatal("Build must refuse a kernel it cannot load whole")
+	}
+	if _, err := (&Config{Adapters: bad.Adapters}).Build("test_principal", "test_namespace"); ExitCode(err) != 2 {
+		t.Fatalf("an unloadable manifest is a usage error, got %v", err)
+	}
+}
+
+// TestLedgerKeepsItsKey proves a kernel on a persistent ledger without
+// -key signs with the key kept beside the ledger, so a later run verifies
+// the receipts an earlier one signed, and one never starts on signed
+// receipts with a key that did not sign them
+func TestLedgerKeepsItsKey(t *testing.T) {
+	ledgerPath := filepath.Join(t.TempDir(), "receipts.jsonl")
+	cfg := &Config{Ledger: ledgerPath}
+	for run := 0; run < 2; run++ {
+		k, err := cfg.Build("test_principal", "test_namespace")
+		if err != nil {
+			t.Fatalf("run %d: build: %v", run, err)
+		}
+		if err := k.State.VerifyAuditLedger(); err != nil {
+			t.Fatalf("run %d: the ledger must verify under the kept key: %v", run, err)
+		}
+		if err := k.Close(); err != nil {
+			t.Fatalf("run %d: close: %v", run, err)
+		}
+	}
+	if info, err := os.Stat(LedgerKeyPath(ledgerPath)); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("the kept key must be readable by its owner only, got %v", err)
+	}
+	if _, err := os.Stat(LedgerPublicKeyPath(ledgerPath)); err != nil {
+		t.Fatalf("the kept key's public half must be written for audit verify: %v", err)
+	}
+
+	if err := os.Remove(LedgerKeyPath(ledgerPath)); err != nil {
+		t.Fatalf("remove key: %v", err)
+	}
+	if _, err := cfg.Build("test_principal", "test_namespace"); err == nil {
+		t.Fatal("a ledger with receipts and no key must not get a fresh one")
+	}
+}
+
+// TestForwardBuildsSinks proves the sinks a server forwards to are named
+// from flags or a file, and a command that does not serve forwards nowhere
+func TestForwardBuildsSinks(t *testing.T) {
+	path := writeConfig(t, `{"serve": {"forward": {"syslog": "tcp://collector:6514", "kafka": "broker:9092", "kafka_topic": "oi.receipts"}}}`)
+	cfg, err := parse(t, Serve, "-config", path, "-forward-webhook", "https://collector.example/receipts")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	sinks, err := cfg.Serve.Forward.Sinks()
+	if err != nil {
+		t.Fatalf("sinks: %v", err)
+	}
+	var names []string
+	for _, sink := range sinks {
+		names = append(names, sink.Name())
+	}
+	want := "syslog:tcp://collector:6514 webhook:https://collector.example/receipts kafka:oi.receipts"
+	if strings.Join(names, " ") != want {
+		t.Fatalf("sinks %v, want %s", names, want)
+	}
+
+	cfg, err = parse(t, 0, "-config", path)
+	if err != nil || cfg.Serve.Forward != (Forward{}) {
+		t.Fatalf("a command that does not serve takes no sinks: %+v (%v)", cfg.Serve.Forward, err)
+	}
+}
+
+// TestSignerKeepsTheKeyOutOfProcess proves -signer remote: signs receipts
+// and minted tokens through a signing service, writes its public half for
+// audit verify, and validates the ledger it signed
+func TestSignerKeepsTheKeyOutOfProcess(t *testing.T) {
+	private, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/public_key":
+			encoded, _ := signing.EncodePublicKey(&private.PublicKey)
+			w.Write(encoded)
+		case "/sign":
+			var request struct{ Digest []byte }
+			json.NewDecoder(r.Body).Decode(&request)
+			signature, _ := ecdsa.SignASN1(rand.Reader, private, request.Digest)
+			json.NewEncoder(w).Encode(map[string][]byte{"signature": signature})
+		}
+	}))
+	defer service.Close()
+
+	ledgerPath := filepath.Join(t.TempDir(), "receipts.jsonl")
+	cfg, err := parse(t, 0, "-ledger", ledgerPath, "-signer", "remote:"+service.URL)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	k, err := cfg.Build("test_principal", "test_namespace")
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	defer k.Close()
+	if resp, err := kernel.Execute(&kernel.Request{RawInput: "test request"}, k.State); err != nil || !resp.Success {
+		t.Fatalf("a request must run on a token the service signed: %v", err)
+	}
+	keys := signing.NewKeyRing()
+	keys.Add(kernel.AuditKeyID, &private.PublicKey)
+	for _, token := range k.State.ActiveCapabilityTokens {
+		if err := token.VerifySignature(keys); err != nil {
+			t.Fatalf("minted tokens must be signed by the service's key: %v", err)
+		}
+	}
+
+	public, err := signing.LoadPublicKey(LedgerPublicKeyPath(ledgerPath))
+	if err != nil || !private.PublicKey.Equal(public) {
+		t.Fatalf("the service's public key must be written beside the ledger: %v", err)
+	}
+	if _, err := os.Stat(LedgerKeyPath(ledgerPath)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatal("no key file is kept when the key is out of process")
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("the ledger the service signed must validate: %v", err)
+	}
+}
@@ -0,0 +1,399 @@
+// WHY: A kernel must come up whole or not at all: a governance bundle that
+// fails verification, an issuer whose keys cannot be read, or a ledger
+// that cannot be opened stops the build rather than leaving a kernel
+// running on less than was configured. Validate checks the same settings
+// without standing anything up, so a bad config is found before a deploy,
+// not by it.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/user/oi/kernel-go/internal/adapters"
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/identity"
+	"github.com/user/oi/kernel-go/internal/kernel"
+	"github.com/user/oi/kernel-go/internal/memory"
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+// Kernel is a kernel built from a Config and the files it holds open
+type Kernel struct {
+	State *kernel.SystemState
+
+	closers []func() error
+}
+
+// Close closes the ledger and memory store the kernel holds open
+func (k *Kernel) Close() error {
+	var errs []error
+	for i := len(k.closers) - 1; i >= 0; i-- {
+		errs = append(errs, k.closers[i]())
+	}
+	return errors.Join(errs...)
+}
+
+// Build stands up a kernel for principal in namespace as c configures it,
+// routing requests to the mock model adapter unless c names another
+func (c *Config) Build(principal, namespace string) (*Kernel, error) {
+	if err := c.Check(); err != nil {
+		return nil, err
+	}
+	state := kernel.NewSystemState(principal, namespace)
+	state.GovernanceCapsule.Rules = map[string]interface{}{"exists": true}
+	state.AdapterRegistry.Register(adapters.NewMockAdapter(kernel.DefaultModelAdapter))
+	k := &Kernel{State: state}
+	if err := c.build(k); err != nil {
+		k.Close()
+		return nil, err
+	}
+	return k, nil
+}
+
+// build configures k.State in the order every command shares
+func (c *Config) build(k *Kernel) error {
+	state := k.State
+
+	// WHY: A governance bundle that fails verification must not leave the
+	// kernel running on policy nobody signed
+	if c.Governance != "" {
+		state.GovernanceCapsule.Commitments[kernel.CommitmentGovernanceKey] = c.GovernancePin
+		if err := state.LoadGovernanceBundle(c.Governance); err != nil {
+			return err
+		}
+	}
+
+	// WHY: With an identity issuer configured, requests run as the
+	// principal their bearer token names, not the kernel's own
+	if c.Identity.JWKS != "" {
+		verifier, err := c.Identity.verifier()
+		if err == nil {
+			err = state.SetIdentityVerifier(verifier)
+		}
+		if err != nil {
+			return invalid(err)
+		}
+	}
+	if c.Admin.JWKS != "" {
+		verifier, err := c.Admin.verifier()
+		if err == nil {
+			err = state.SetAdminVerifier(verifier)
+		}
+		if err != nil {
+			return invalid(err)
+		}
+	}
+
+	if c.Memory != "" {
+		store, err := memory.OpenFileStore(c.Memory)
+		if err != nil {
+			return err
+		}
+		k.closers = append(k.closers, store.Close)
+		if err := state.AttachMemoryStore(store); err != nil {
+			return err
+		}
+	}
+
+	if c.Adapters != "" {
+		if err := state.LoadAdapterManifest(c.Adapters); err != nil {
+			return invalid(err)
+		}
+	}
+	if c.OpenAIURL != "" {
+		model, err := c.openAIAdapter()
+		if err != nil {
+			return invalid(err)
+		}
+		state.AdapterRegistry.Register(model)
+		state.ModelAdapter = model.Name()
+	}
+
+	// WHY: The key is found before the ledger is opened, since opening
+	// writes genesis to a new file. It signs the tokens the kernel mints
+	// as well as its receipts.
+	signer, err := c.loadSigner()
+	if err != nil {
+		return err
+	}
+	if err := state.SetTokenSigner(signer); err != nil {
+		return err
+	}
+	if c.Ledger != "" {
+		ledger, err := OpenLedger(c.Ledger)
+		if err != nil {
+			return err
+		}
+		k.closers = append(k.closers, ledger.Close)
+		if err := state.AttachLedger(ledger, signer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate checks everything Build would load, without opening anything
+// for writing, and reports every problem found
+func (c *Config) Validate() error {
+	if err := c.Check(); err != nil {
+		return err
+	}
+	var errs []error
+	scratch := kernel.NewSystemState("config_validate", "config_validate")
+	if c.Governance != "" {
+		scratch.GovernanceCapsule.Commitments[kernel.CommitmentGovernanceKey] = c.GovernancePin
+		errs = append(errs, scratch.LoadGovernanceBundle(c.Governance))
+	}
+	for _, issuer := range []struct {
+		name   string
+		issuer Issuer
+		set    func(*identity.Verifier) error
+	}{
+		{"identity", c.Identity, scratch.SetIdentityVerifier},
+		{"admin", c.Admin, scratch.SetAdminVerifier},
+	} {
+		if issuer.issuer.JWKS == "" {
+			continue
+		}
+		verifier, err := issuer.issuer.verifier()
+		if err == nil {
+			err = issuer.set(verifier)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s issuer: %w", issuer.name, err))
+		}
+	}
+	if c.Memory != "" {
+		if info, err := os.Stat(c.Memory); err == nil && !info.IsDir() {
+			errs = append(errs, fmt.Errorf("memory %s is not a directory", c.Memory))
+		}
+	}
+	if c.Adapters != "" {
+		_, err := adapters.LoadManifest(c.Adapters)
+		errs = append(errs, err)
+	}
+	if c.OpenAIURL != "" {
+		_, err := c.openAIAdapter()
+		errs = append(errs, err)
+	}
+	signer, err := c.outOfProcessSigner()
+	errs = append(errs, err)
+	key := c.Key
+	if key == "" && c.Ledger != "" {
+		if _, err := os.Stat(LedgerKeyPath(c.Ledger)); err == nil {
+			key = LedgerKeyPath(c.Ledger)
+		}
+	}
+	if c.Signer == "" && key != "" {
+		signer, err = LoadSigner(key, c.Ledger)
+		errs = append(errs, err)
+	}
+	if c.Ledger != "" {
+		errs = append(errs, validateLedger(c.Ledger, signer))
+	}
+	return errors.Join(errs...)
+}
+
+// validateLedger verifies the ledger at path, if there is one yet, under
+// signer's key if given, reading it only; a ledger yet to be written
+// needs its directory
+func validateLedger(path string, signer signing.Signer) error {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		if info, err := os.Stat(filepath.Dir(path)); err != nil || !info.IsDir() {
+			return fmt.Errorf("ledger %s: directory %s does not exist", path, filepath.Dir(path))
+		}
+		return nil
+	}
+	if err == nil && info.Size() == 0 {
+		return nil
+	}
+	ledger, err := audit.ReadFileLedger(path)
+	if err != nil {
+		return fmt.Errorf("ledger %s: %w", path, err)
+	}
+	if signer != nil {
+		if err := ledger.TrustKey(signer.KeyID(), signer.Public()); err != nil {
+			return fmt.Errorf("ledger %s: %w", path, err)
+		}
+	}
+	if _, err := ledger.Verify(); err != nil {
+		return fmt.Errorf("ledger %s: %w", path, err)
+	}
+	return nil
+}
+
+// verifier loads the issuer's key set into a token verifier
+func (i Issuer) verifier() (*identity.Verifier, error) {
+	keys, err := identity.LoadJWKS(i.JWKS)
+	if err != nil {
+		return nil, err
+	}
+	return &identity.Verifier{Issuer: i.Issuer, Audience: i.Audience, Keys: keys}, nil
+}
+
+// openAIAdapter is the model adapter -openai-url names
+func (c *Config) openAIAdapter() (*adapters.OpenAIAdapter, error) {
+	return adapters.NewOpenAIAdapter(adapters.OpenAIConfig{
+		Name:    "openai",
+		BaseURL: c.OpenAIURL,
+		APIKey:  os.Getenv("OPENAI_API_KEY"),
+		Model:   c.Model,
+	})
+}
+
+// Sinks builds the sinks f names
+func (f Forward) Sinks() ([]audit.AuditSink, error) {
+	var sinks []audit.AuditSink
+	if f.Syslog != "" {
+		network, address, err := f.syslogAddress()
+		if err != nil {
+			return nil, invalid(err)
+		}
+		sink, err := audit.NewSyslogSink(network, address)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	if f.Webhook != "" {
+		headers := map[string]string{}
+		if token := os.Getenv("OI_FORWARD_WEBHOOK_TOKEN"); token != "" {
+			headers["Authorization"] = "Bearer " + token
+		}
+		sinks = append(sinks, audit.NewWebhookSink(f.Webhook, headers))
+	}
+	if f.Kafka != "" {
+		producer, err := audit.NewKafkaBrokerProducer(f.Kafka)
+		if err != nil {
+			return nil, invalid(err)
+		}
+		sink, err := audit.NewKafkaSink(producer, f.KafkaTopic)
+		if err != nil {
+			return nil, invalid(err)
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+// loadSigner is the kernel's signing key: the one -signer names, its
+// public half written beside the ledger if not there yet, or LoadSigner's
+func (c *Config) loadSigner() (signing.Signer, error) {
+	if c.Signer == "" {
+		return LoadSigner(c.Key, c.Ledger)
+	}
+	signer, err := c.outOfProcessSigner()
+	if err != nil {
+		return nil, err
+	}
+	if c.Ledger != "" {
+		if _, err := os.Stat(LedgerPublicKeyPath(c.Ledger)); errors.Is(err, os.ErrNotExist) {
+			if err := writePublicKey(LedgerPublicKeyPath(c.Ledger), signer); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return signer, nil
+}
+
+// outOfProcessSigner is the signer -signer names, or nil without one
+func (c *Config) outOfProcessSigner() (signing.Signer, error) {
+	if c.Signer == "" {
+		return nil, nil
+	}
+	backend, location, err := c.signerBackend()
+	if err != nil {
+		return nil, invalid(err)
+	}
+	if backend == "keychain" {
+		service, account, _ := strings.Cut(location, "/")
+		signer, err := signing.NewKeychainSigner(kernel.AuditKeyID, service, account, nil)
+		if err != nil {
+			return nil, err
+		}
+		return signer, nil
+	}
+	headers := map[string]string{}
+	if token := os.Getenv("OI_SIGNER_TOKEN"); token != "" {
+		headers["Authorization"] = "Bearer " + token
+	}
+	signer, err := signing.NewRemoteSigner(kernel.AuditKeyID, signing.NewHTTPRemoteKey(location, headers), 0)
+	if err != nil {
+		return nil, err
+	}
+	return signer, nil
+}
+
+// writePublicKey writes the public half of signer to path as PEM
+func writePublicKey(path string, signer signing.Signer) error {
+	public, err := signing.EncodePublicKey(signer.Public())
+	if err == nil {
+		err = os.WriteFile(path, public, 0o644)
+	}
+	if err != nil {
+		return fmt.Errorf("ledger public key: %w", err)
+	}
+	return nil
+}
+
+// OpenLedger opens a file-backed ledger
+func OpenLedger(path string) (*audit.Ledger, error) {
+	store, err := audit.OpenFileStore(path)
+	if err != nil {
+		return nil, err
+	}
+	ledger, err := audit.OpenLedger(store)
+	if err != nil {
+		store.Close()
+		return nil, err
+	}
+	return ledger, nil
+}
+
+// LoadSigner reads the receipt signing key at path. With no path, a kernel
+// on a persistent ledger signs with the key kept beside it, generated on
+// first use, and one without a ledger with a fresh key.
+// WHY: A key generated per run would leave every later run, and audit
+// verify, unable to check the receipts the earlier runs signed
+func LoadSigner(path, ledger string) (signing.Signer, error) {
+	switch {
+	case path != "":
+		return signing.LoadLocalSigner(kernel.AuditKeyID, path)
+	case ledger == "":
+		return signing.GenerateLocalSigner(kernel.AuditKeyID)
+	}
+	path = LedgerKeyPath(ledger)
+	if _, err := os.Stat(path); err == nil {
+		return signing.LoadLocalSigner(kernel.AuditKeyID, path)
+	}
+	if info, err := os.Stat(ledger); err == nil && info.Size() > 0 {
+		return nil, fmt.Errorf("ledger %s has receipts but no key at %s; pass the key that signed them with -key", ledger, path)
+	}
+	signer, err := signing.GenerateLocalSigner(kernel.AuditKeyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := signer.WriteKeyFile(path); err != nil {
+		return nil, fmt.Errorf("ledger key: %w", err)
+	}
+	if err := writePublicKey(LedgerPublicKeyPath(ledger), signer); err != nil {
+		return nil, err
+	}
+	return signer, nil
+}
+
+// LedgerKeyPath is where the key for a ledger run without -key is kept
+func LedgerKeyPath(ledger string) string {
+	return ledger + ".key"
+}
+
+// LedgerPublicKeyPath is where the public half of LedgerKeyPath is
+// written, for audit verify -pubkey
+func LedgerPublicKeyPath(ledger string) string {
+	return ledger + ".pub.pem"
+}
@@ -0,0 +1,288 @@
+// WHY: The governance record is a JSONL hash chain, and reading it meant a
+// shell and the audit subcommands. Auditors who do not read Go or jq need
+// to see it: every receipt with whether it still holds, the decisions CDI
+// made, each token from mint to revocation, and the posture over time.
+// The dashboard serves that as a small web page over a ledger file it
+// only reads. It trusts nothing it shows: the file is read without
+// verification so a damaged record can still be inspected, and every
+// receipt is shown with what its own checks found, beside the verdict on
+// the chain as a whole. It adds no authority and can change nothing.
+package dashboard
+
+import (
+	"crypto"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+//go:embed static
+var static embed.FS
+
+// Options configures a dashboard
+type Options struct {
+	// Load reads the ledger's receipts afresh for each view
+	Load func() ([]audit.Receipt, error)
+
+	// KeyID and Key, if set, are the key receipts are checked against;
+	// without them signatures are shown unchecked and a signed ledger
+	// does not verify
+	KeyID string
+	Key   crypto.PublicKey
+}
+
+// Status is the verdict on the ledger as a whole
+type Status struct {
+	Receipts      int    `json:"receipts"`
+	FirstSequence int64  `json:"first_sequence"`
+	LastSequence  int64  `json:"last_sequence"`
+	Head          string `json:"head,omitempty"`
+
+	// Verified is whether the whole ledger verifies as the kernel would
+	// verify it: hashes, links, signatures, checkpoints, and seals
+	Verified bool   `json:"verified"`
+	Error    string `json:"error,omitempty"`
+
+	// Broken counts receipts that fail their own checks
+	Broken int `json:"broken"`
+
+	// TrustedKey is the ID of the key signatures are checked against
+	TrustedKey string `json:"trusted_key,omitempty"`
+}
+
+// CheckedReceipt is a receipt with what its checks found
+type CheckedReceipt struct {
+	audit.Receipt
+	Check audit.ReceiptCheck `json:"check"`
+}
+
+// ReceiptsResponse is one page of GET /api/receipts
+type ReceiptsResponse struct {
+	Receipts     []CheckedReceipt `json:"receipts"`
+	NextSequence int64            `json:"next_sequence,omitempty"`
+	HasMore      bool             `json:"has_more"`
+}
+
+// Dashboard serves the ledger's views
+type Dashboard struct {
+	options Options
+	keys    *signing.KeyRing
+	mux     *http.ServeMux
+}
+
+// New creates a dashboard over the ledger options.Load reads
+func New(options Options) (*Dashboard, error) {
+	if options.Load == nil {
+		return nil, errors.New("dashboard: no ledger to load")
+	}
+	d := &Dashboard{options: options, mux: http.NewServeMux()}
+	if options.Key != nil {
+		d.keys = signing.NewKeyRing()
+		if err := d.keys.Add(options.KeyID, options.Key); err != nil {
+			return nil, fmt.Errorf("dashboard: %w", err)
+		}
+	}
+	assets, err := fs.Sub(static, "static")
+	if err != nil {
+		return nil, err
+	}
+	d.mux.Handle("GET /", http.FileServerFS(assets))
+	d.mux.HandleFunc("GET /api/status", d.status)
+	d.mux.HandleFunc("GET /api/receipts", d.receipts)
+	d.mux.HandleFunc("GET /api/tokens", d.tokens)
+	return d, nil
+}
+
+// ServeHTTP sets headers that keep the page from loading anything but its
+// own assets or being framed, then routes the request
+func (d *Dashboard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "no-store")
+	d.mux.ServeHTTP(w, r)
+}
+
+// load reads the receipts and checks each one
+func (d *Dashboard) load() ([]audit.Receipt, []audit.ReceiptCheck, error) {
+	receipts, err := d.options.Load()
+	if err != nil {
+		return nil, nil, err
+	}
+	return receipts, audit.CheckReceipts(receipts, d.keys), nil
+}
+
+// status serves the verdict on the whole ledger
+func (d *Dashboard) status(w http.ResponseWriter, r *http.Request) {
+	receipts, checks, err := d.load()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	status := Status{Receipts: len(receipts)}
+	if d.keys != nil {
+		status.TrustedKey = d.options.KeyID
+	}
+	for _, check := range checks {
+		if !check.OK() {
+			status.Broken++
+		}
+	}
+	if len(receipts) > 0 {
+		status.FirstSequence = receipts[0].Sequence
+		status.LastSequence = receipts[len(receipts)-1].Sequence
+		status.Head = receipts[len(receipts)-1].CurrentHash
+	}
+	if err := d.verify(receipts); err != nil {
+		status.Error = err.Error()
+	} else {
+		status.Verified = true
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+// verify verifies receipts as the kernel verifies its own ledger
+func (d *Dashboard) verify(receipts []audit.Receipt) error {
+	if len(receipts) == 0 {
+		return errors.New("empty ledger")
+	}
+	ledger, err := audit.OpenLedger(audit.ReadOnlyStore(receipts))
+	if err != nil {
+		return err
+	}
+	if d.options.Key != nil {
+		if err := ledger.TrustKey(d.options.KeyID, d.options.Key); err != nil {
+			return err
+		}
+	}
+	_, err = ledger.Verify()
+	return err
+}
+
+// receipts serves a page of checked receipts. Query parameters: type
+// (comma-separated), principal, namespace, token, request, decision,
+// severity, category (comma-separated), since, until, from, limit, and
+// broken, which keeps only receipts that do not hold.
+func (d *Dashboard) receipts(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseReceiptFilter(r.URL.Query())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	receipts, checks, err := d.load()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	if r.URL.Query().Get("broken") != "" {
+		receipts, checks = brokenOnly(receipts, checks)
+	}
+	page, err := audit.QueryReceipts(receipts, filter)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	// WHY: A rewritten file can repeat a sequence; the worse check stands
+	bySequence := map[int64]audit.ReceiptCheck{}
+	for _, check := range checks {
+		if held, seen := bySequence[check.Sequence]; !seen || held.OK() {
+			bySequence[check.Sequence] = check
+		}
+	}
+	response := ReceiptsResponse{Receipts: []CheckedReceipt{}, NextSequence: page.NextSequence, HasMore: page.HasMore}
+	for _, receipt := range page.Receipts {
+		response.Receipts = append(response.Receipts, CheckedReceipt{Receipt: receipt, Check: bySequence[receipt.Sequence]})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// tokens serves token lifecycles, newest first. Query parameters:
+// principal, namespace, token, and broken.
+func (d *Dashboard) tokens(w http.ResponseWriter, r *http.Request) {
+	receipts, checks, err := d.load()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	query := r.URL.Query()
+	writeJSON(w, http.StatusOK, tokenLifecycles(receipts, checks, TokenFilter{
+		PrincipalID: query.Get("principal"),
+		NamespaceID: query.Get("namespace"),
+		TokenDigest: query.Get("token"),
+		Broken:      query.Get("broken") != "",
+	}))
+}
+
+// brokenOnly keeps the receipts that do not hold and their checks
+func brokenOnly(receipts []audit.Receipt, checks []audit.ReceiptCheck) ([]audit.Receipt, []audit.ReceiptCheck) {
+	var keptReceipts []audit.Receipt
+	var keptChecks []audit.ReceiptCheck
+	for i, check := range checks {
+		if !check.OK() {
+			keptReceipts = append(keptReceipts, receipts[i])
+			keptChecks = append(keptChecks, check)
+		}
+	}
+	return keptReceipts, keptChecks
+}
+
+// parseReceiptFilter reads a receipt filter from query parameters
+func parseReceiptFilter(query url.Values) (audit.ReceiptFilter, error) {
+	filter := audit.ReceiptFilter{
+		EventTypes:  splitList(query.Get("type")),
+		PrincipalID: query.Get("principal"),
+		NamespaceID: query.Get("namespace"),
+		TokenDigest: query.Get("token"),
+		RequestID:   query.Get("request"),
+		Decision:    strings.ToUpper(query.Get("decision")),
+		MinSeverity: audit.Severity(query.Get("severity")),
+	}
+	for _, category := range splitList(query.Get("category")) {
+		filter.Categories = append(filter.Categories, audit.Category(category))
+	}
+	integers := map[string]*int64{"since": &filter.Since, "until": &filter.Until, "from": &filter.FromSequence}
+	for name, field := range integers {
+		if value := query.Get(name); value != "" {
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return audit.ReceiptFilter{}, fmt.Errorf("%s: %v", name, err)
+			}
+			*field = parsed
+		}
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return audit.ReceiptFilter{}, fmt.Errorf("limit: %v", err)
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
+
+// splitList splits a comma-separated parameter, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// writeJSON writes body as JSON with status
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,195 @@
+// WHY: Proves the dashboard shows a ledger as it is: a sound chain as
+// verified, a damaged one with the receipts that no longer hold picked
+// out, and each token from its mint to the STOP that revoked it.
+package dashboard
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+var (
+	alice = audit.Attribution{PrincipalID: "alice", NamespaceID: "tenant_a", RequestID: "request_1"}
+	bob   = audit.Attribution{PrincipalID: "bob", NamespaceID: "tenant_b", RequestID: "request_2"}
+)
+
+// writeLedger writes a signed ledger file of two requests, a principal
+// STOP of alice, and a global STOP, and returns its path and signer
+func writeLedger(t *testing.T) (string, *signing.LocalSigner) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "receipts.jsonl")
+	store, err := audit.OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	ledger, err := audit.OpenLedger(store)
+	if err != nil {
+		t.Fatalf("open ledger: %v", err)
+	}
+	signer, _ := signing.GenerateLocalSigner("kernel_audit_1")
+	ledger.SetSigner(signer)
+
+	ledger.AppendCDIDecision(alice, "ALLOW", "h1", "", "decision_1")
+	ledger.AppendTokenMint(alice, audit.TokenMint{TokenDigest: "token_a", Scope: []string{"read"}, DecisionID: "decision_1"})
+	ledger.AppendAdapterAttempt(alice, "mock_adapter", true, "token_a")
+	ledger.AppendCDIDecision(bob, "DENY", "h2", "", "decision_2")
+	ledger.AppendTokenMint(bob, audit.TokenMint{TokenDigest: "token_b", Scope: []string{"read"}, DecisionID: "decision_2"})
+	ledger.AppendScopedStopEvent(alice, audit.StopScopePrincipal, "alice", audit.StopReasonOperatorAction, 1)
+	ledger.AppendPostureChange(bob, 1, 3, "threat")
+	ledger.AppendStopEvent(bob, 1)
+	if err := ledger.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	return path, signer
+}
+
+// fileLoader reads the receipts of the file at path
+func fileLoader(path string) func() ([]audit.Receipt, error) {
+	return func() ([]audit.Receipt, error) {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return audit.ReadReceipts(file)
+	}
+}
+
+// get sends GET path to the dashboard and decodes the JSON response
+func get(t *testing.T, d *Dashboard, path string, out interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	d.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("decode %s: %v (%s)", path, err, rec.Body.String())
+		}
+	}
+	return rec
+}
+
+// TestDashboardShowsASoundLedger proves the page and its assets are served
+// locked down, the chain verifies against the key, and receipts filter
+func TestDashboardShowsASoundLedger(t *testing.T) {
+	path, signer := writeLedger(t)
+	d, err := New(Options{Load: fileLoader(path), KeyID: signer.KeyID(), Key: signer.Public()})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+
+	for _, asset := range []string{"/", "/dashboard.js", "/dashboard.css"} {
+		rec := get(t, d, asset, nil)
+		if rec.Code != http.StatusOK || rec.Body.Len() == 0 {
+			t.Fatalf("%s must be served, got %d", asset, rec.Code)
+		}
+		if !strings.Contains(rec.Header().Get("Content-Security-Policy"), "default-src 'self'") {
+			t.Fatalf("%s must load only the dashboard's own assets", asset)
+		}
+	}
+	if rec := get(t, d, "/api/receipts", nil); rec.Code != http.StatusOK {
+		t.Fatalf("receipts: %d", rec.Code)
+	}
+	rec := httptest.NewRecorder()
+	d.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/receipts", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("the dashboard only reads, got %d for a POST", rec.Code)
+	}
+
+	var status Status
+	get(t, d, "/api/status", &status)
+	if !status.Verified || status.Broken != 0 || status.Receipts != 9 || status.TrustedKey != signer.KeyID() {
+		t.Fatalf("a sound ledger must verify, got %+v", status)
+	}
+
+	var page ReceiptsResponse
+	get(t, d, "/api/receipts?type=cdi_decision&decision=deny", &page)
+	if len(page.Receipts) != 1 || page.Receipts[0].EventData["principal_id"] != "bob" {
+		t.Fatalf("want bob's DENY, got %+v", page.Receipts)
+	}
+	if check := page.Receipts[0].Check; !check.OK() || check.Signature != audit.SignatureVerified {
+		t.Fatalf("each receipt carries its check, got %+v", check)
+	}
+	get(t, d, "/api/receipts?limit=2&from=3", &page)
+	if len(page.Receipts) != 2 || page.Receipts[0].Sequence != 3 || !page.HasMore || page.NextSequence != 5 {
+		t.Fatalf("receipts must page from the cursor, got %+v", page)
+	}
+	if rec := get(t, d, "/api/receipts?since=x", nil); rec.Code != http.StatusBadRequest {
+		t.Fatalf("a malformed filter must be refused, got %d", rec.Code)
+	}
+
+	unkeyed, _ := New(Options{Load: fileLoader(path)})
+	get(t, unkeyed, "/api/status", &status)
+	if status.Verified || !strings.Contains(status.Error, "no receipt keys are trusted") {
+		t.Fatalf("a signed ledger must not verify without its key, got %+v", status)
+	}
+	get(t, unkeyed, "/api/receipts?type=token_mint", &page)
+	if page.Receipts[0].Check.Signature != audit.SignatureUnchecked || !page.Receipts[0].Check.OK() {
+		t.Fatalf("without a key, signatures are shown unchecked, got %+v", page.Receipts[0].Check)
+	}
+}
+
+// TestDashboardTracesTokenLifecycles proves each token runs from its mint
+// through its uses to the STOP that revoked it
+func TestDashboardTracesTokenLifecycles(t *testing.T) {
+	path, _ := writeLedger(t)
+	d, _ := New(Options{Load: fileLoader(path)})
+
+	var tokens TokensResponse
+	get(t, d, "/api/tokens", &tokens)
+	if len(tokens.Tokens) != 2 || tokens.Tokens[0].Digest != "token_b" {
+		t.Fatalf("want both tokens, newest first, got %+v", tokens.Tokens)
+	}
+	b, a := tokens.Tokens[0], tokens.Tokens[1]
+	if a.Uses != 1 || a.LastSequence != 3 || a.RevokedSequence != 6 || a.RevokedScope != audit.StopScopePrincipal || a.RevokedReason != audit.StopReasonOperatorAction {
+		t.Fatalf("alice's token must be used once and revoked by her STOP, got %+v", a)
+	}
+	if b.RevokedSequence != 8 || b.RevokedScope != audit.StopScopeGlobal || len(b.Scope) != 1 || b.DecisionID != "decision_2" {
+		t.Fatalf("bob's token must outlive alice's STOP and fall to the global one, got %+v", b)
+	}
+	get(t, d, "/api/tokens?principal=alice", &tokens)
+	if len(tokens.Tokens) != 1 || tokens.Tokens[0].Digest != "token_a" || !tokens.Tokens[0].Intact {
+		t.Fatalf("tokens must filter by principal, got %+v", tokens.Tokens)
+	}
+}
+
+// TestDashboardLocatesTampering proves a tampered file is still shown,
+// fails verification, and picks out the receipts that no longer hold
+func TestDashboardLocatesTampering(t *testing.T) {
+	path, signer := writeLedger(t)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	tampered := strings.Replace(string(data), `"decision":"DENY"`, `"decision":"ALLOW"`, 1)
+	if tampered == string(data) {
+		t.Fatal("the test must tamper with the DENY")
+	}
+	if err := os.WriteFile(path, []byte(tampered), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	d, _ := New(Options{Load: fileLoader(path), KeyID: signer.KeyID(), Key: signer.Public()})
+
+	var status Status
+	get(t, d, "/api/status", &status)
+	if status.Verified || status.Broken != 1 || !strings.Contains(status.Error, "hash mismatch") {
+		t.Fatalf("a tampered ledger must fail at the edited receipt, got %+v", status)
+	}
+	var page ReceiptsResponse
+	get(t, d, "/api/receipts?broken=1", &page)
+	if len(page.Receipts) != 1 || page.Receipts[0].Sequence != 4 || page.Receipts[0].Check.Hashed {
+		t.Fatalf("only the edited receipt must be picked out, got %+v", page.Receipts)
+	}
+	var tokens TokensResponse
+	get(t, d, "/api/tokens?broken=1", &tokens)
+	if len(tokens.Tokens) != 0 {
+		t.Fatalf("no token's own receipts were edited, got %+v", tokens.Tokens)
+	}
+}
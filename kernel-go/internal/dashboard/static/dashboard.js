@@ -0,0 +1,237 @@
+// The dashboard's views, drawn from its read-only JSON API. Everything
+// shown comes from the ledger and is written as text, never as markup.
+"use strict";
+
+const views = {
+  receipts: {
+    endpoint: "/api/receipts",
+    columns: ["Holds", "Seq", "Time", "Event", "Severity", "Principal", "Namespace", "Request", "Details", "Hash"],
+    row: (r) => [holds(r.check), r.sequence, time(r.timestamp), r.event_type, badge(r.severity), who(r, "principal_id"),
+      who(r, "namespace_id"), requestLink(r), details(r.event_data), mono(short(r.current_hash))],
+    broken: (r) => !ok(r.check),
+  },
+  decisions: {
+    endpoint: "/api/receipts",
+    fixed: { type: "cdi_decision" },
+    columns: ["Holds", "Seq", "Time", "Decision", "Principal", "Namespace", "Request", "Decision ID", "Policy", "Input hash"],
+    row: (r) => [holds(r.check), r.sequence, time(r.timestamp), badge(r.event_data.decision), who(r, "principal_id"),
+      who(r, "namespace_id"), requestLink(r), mono(r.event_data.decision_id), text(r.event_data.policy_version),
+      mono(short(r.event_data.input_hash))],
+    broken: (r) => !ok(r.check),
+  },
+  tokens: {
+    endpoint: "/api/tokens",
+    columns: ["Holds", "Token", "Principal", "Namespace", "Scope", "Minted", "Uses", "Revoked"],
+    row: (t) => [holds({ ok: t.intact, error: t.intact ? "" : "a receipt of this token does not hold" }),
+      tokenLink(t.digest), text(t.principal_id), text(t.namespace_id), text((t.scope || []).join(", ")),
+      text("#" + t.mint_sequence + " " + time(t.minted_at)), text(t.uses ? t.uses + " (last #" + t.last_sequence + ")" : "0"),
+      t.revoked_sequence ? text("#" + t.revoked_sequence + " " + time(t.revoked_at) + ", " + t.revoked_scope + " STOP" +
+        (t.revoked_reason ? " (" + t.revoked_reason + ")" : "")) : muted("no revocation recorded")],
+    broken: (t) => !t.intact,
+  },
+  posture: {
+    endpoint: "/api/receipts",
+    fixed: { type: "posture_change,integrity_state_change,stop_event,stop_resume", limit: "1000" },
+    columns: ["Holds", "Seq", "Time", "Change", "Reason", "By"],
+    row: (r) => [holds(r.check), r.sequence, time(r.timestamp), text(change(r)), text(reason(r.event_data)),
+      text(by(r.event_data))],
+    broken: (r) => !ok(r.check),
+  },
+};
+
+let current = "receipts";
+let cursor = 0;
+
+// ok mirrors audit.ReceiptCheck.OK
+function ok(check) {
+  if (check.ok !== undefined) return check.ok;
+  return check.hashed && check.linked && check.signature !== "invalid" && check.signature !== "missing";
+}
+
+function el(tag, className, content) {
+  const node = document.createElement(tag);
+  if (className) node.className = className;
+  if (content !== undefined && content !== null) node.textContent = String(content);
+  return node;
+}
+
+function text(value) { return el("span", "", value === undefined ? "" : value); }
+function muted(value) { return el("span", "muted", value); }
+function mono(value) { return el("span", "mono", value || ""); }
+function badge(value) { return value ? el("span", "badge " + value, value) : text(""); }
+function short(hash) { return hash ? hash.slice(0, 16) + "…" : ""; }
+function time(seconds) { return seconds ? new Date(seconds * 1000).toISOString().replace(".000", "") : ""; }
+function who(r, field) { return text(r.event_data ? r.event_data[field] : ""); }
+
+function holds(check) {
+  const good = ok(check);
+  const node = el("span", good ? "ok" : "bad", good ? "✓" : "✗");
+  const detail = [];
+  if (check.signature) detail.push("signature " + check.signature);
+  if (check.error) detail.push(check.error);
+  node.title = good ? (detail.join("; ") || "holds") : detail.join("; ");
+  return node;
+}
+
+function link(label, apply) {
+  const node = el("a", "mono", label);
+  node.href = "#";
+  node.addEventListener("click", (event) => {
+    event.preventDefault();
+    apply();
+  });
+  return node;
+}
+
+function tokenLink(digest) {
+  return link(short(digest), () => show("receipts", { token: digest }));
+}
+
+function requestLink(r) {
+  const id = r.event_data ? r.event_data.request_id : "";
+  return id ? link(id, () => show("receipts", { request: id })) : text("");
+}
+
+const attribution = ["principal_id", "namespace_id", "request_id"];
+
+function details(data) {
+  const parts = [];
+  for (const [key, value] of Object.entries(data || {})) {
+    if (attribution.includes(key)) continue;
+    parts.push(key + "=" + (typeof value === "object" ? JSON.stringify(value) : value));
+  }
+  return el("span", "mono", parts.join(" "));
+}
+
+function change(r) {
+  const d = r.event_data || {};
+  switch (r.event_type) {
+    case "posture_change": return "posture P" + d.from_level + " → P" + d.to_level;
+    case "integrity_state_change": return "integrity → " + d.new_state;
+    case "stop_event": return d.stop_scope + " STOP" + (d.stop_target ? " of " + d.stop_target : "") + ", " + d.tokens_revoked + " tokens revoked";
+    case "stop_resume": return (d.accepted ? "resumed " : "resume refused: ") + d.stop_scope + (d.stop_target ? " " + d.stop_target : "");
+    default: return r.event_type;
+  }
+}
+
+function reason(d) { return d.reason || d.stop_reason || d.refusal || ""; }
+
+function by(d) {
+  if (d.requested_by) return "requested by " + d.requested_by + (d.approved_by ? ", approved by " + d.approved_by : "");
+  return d.principal_id || "";
+}
+
+async function getJSON(path, params) {
+  const query = new URLSearchParams(params).toString();
+  const response = await fetch(path + (query ? "?" + query : ""));
+  const body = await response.json();
+  if (!response.ok) throw new Error(body.error || response.statusText);
+  return body;
+}
+
+function filters() {
+  const form = document.getElementById("filters");
+  const params = {};
+  for (const field of form.elements) {
+    if (!field.name) continue;
+    if (field.type === "checkbox") {
+      if (field.checked) params[field.name] = "1";
+    } else if (field.value && field.type === "datetime-local") {
+      params[field.name] = String(Math.floor(new Date(field.value).getTime() / 1000));
+    } else if (field.value) {
+      params[field.name] = field.value.trim();
+    }
+  }
+  return params;
+}
+
+async function refreshStatus() {
+  const node = document.getElementById("status");
+  try {
+    const s = await getJSON("/api/status", {});
+    const key = s.trusted_key ? "signatures checked against " + s.trusted_key : "no key given: signatures unchecked";
+    if (s.verified) {
+      node.className = "status verified";
+      node.textContent = "✓ Chain verified: " + s.receipts + " receipts, #" + s.first_sequence + "–#" + s.last_sequence + "; " + key;
+    } else {
+      node.className = "status failed";
+      node.textContent = "✗ Chain does not verify: " + s.error + " (" + s.broken + " of " + s.receipts + " receipts do not hold; " + key + ")";
+    }
+    node.title = s.head ? "head " + s.head : "";
+  } catch (err) {
+    node.className = "status failed";
+    node.textContent = "Cannot read the ledger: " + err.message;
+  }
+}
+
+async function load(append) {
+  const view = views[current];
+  const params = Object.assign(filters(), view.fixed || {});
+  if (append) params.from = String(cursor);
+  const message = document.getElementById("message");
+  const body = document.querySelector("#table tbody");
+  const more = document.getElementById("more");
+  message.hidden = true;
+  try {
+    const page = await getJSON(view.endpoint, params);
+    const items = page.receipts || page.tokens || [];
+    if (!append) body.replaceChildren();
+    for (const item of items) {
+      const row = el("tr", view.broken(item) ? "broken" : "");
+      for (const cell of view.row(item)) {
+        const td = el("td");
+        td.append(cell instanceof Node ? cell : text(cell));
+        row.append(td);
+      }
+      body.append(row);
+    }
+    if (!append && items.length === 0) {
+      const row = el("tr");
+      const td = el("td", "muted", "Nothing matches.");
+      td.colSpan = view.columns.length;
+      row.append(td);
+      body.append(row);
+    }
+    cursor = page.next_sequence || 0;
+    more.hidden = !page.has_more;
+    if (page.more) {
+      message.hidden = false;
+      message.textContent = "Only the newest tokens are shown; narrow the filter to see older ones.";
+    }
+  } catch (err) {
+    message.hidden = false;
+    message.textContent = err.message;
+  }
+}
+
+function show(name, preset) {
+  current = name;
+  for (const button of document.querySelectorAll("nav button")) {
+    button.classList.toggle("active", button.dataset.view === name);
+  }
+  if (preset) {
+    const form = document.getElementById("filters");
+    form.reset();
+    for (const [key, value] of Object.entries(preset)) form.elements[key].value = value;
+  }
+  const head = document.querySelector("#table thead");
+  const row = el("tr");
+  for (const column of views[name].columns) row.append(el("th", "", column));
+  head.replaceChildren(row);
+  refreshStatus();
+  load(false);
+}
+
+document.addEventListener("DOMContentLoaded", () => {
+  for (const button of document.querySelectorAll("nav button")) {
+    button.addEventListener("click", () => show(button.dataset.view));
+  }
+  const form = document.getElementById("filters");
+  form.addEventListener("submit", (event) => {
+    event.preventDefault();
+    show(current);
+  });
+  form.addEventListener("reset", () => setTimeout(() => show(current), 0));
+  document.getElementById("more").addEventListener("click", () => load(true));
+  show("receipts");
+});
@@ -0,0 +1,174 @@
+// WHY: A token's life is spread across the chain: its mint, every adapter
+// call and refusal that names it, and the STOP that ended it, which names
+// a principal or nothing at all rather than the token. An auditor asking
+// "what could this token do, and when did it stop" should not have to
+// join those by hand, so the dashboard assembles each token's lifecycle
+// from the receipts, and marks one whose receipts do not all hold.
+package dashboard
+
+import "github.com/user/oi/kernel-go/internal/audit"
+
+// maxTokens bounds one tokens response
+const maxTokens = audit.MaxQueryLimit
+
+// TokenFilter selects token lifecycles. Zero-valued fields match everything.
+type TokenFilter struct {
+	PrincipalID string
+	NamespaceID string
+	TokenDigest string
+
+	// Broken keeps only lifecycles with a receipt that does not hold
+	Broken bool
+}
+
+// TokenLifecycle is one token from mint to revocation
+type TokenLifecycle struct {
+	Digest       string   `json:"digest"`
+	PrincipalID  string   `json:"principal_id,omitempty"`
+	NamespaceID  string   `json:"namespace_id,omitempty"`
+	RequestID    string   `json:"request_id,omitempty"`
+	Scope        []string `json:"scope,omitempty"`
+	ParentDigest string   `json:"parent_digest,omitempty"`
+	DecisionID   string   `json:"decision_id,omitempty"`
+
+	// MintSequence and MintedAt are the token_mint receipt's
+	MintSequence int64 `json:"mint_sequence"`
+	MintedAt     int64 `json:"minted_at"`
+
+	// Uses counts the later receipts naming the token, the last of them at
+	// LastSequence
+	Uses         int   `json:"uses"`
+	LastSequence int64 `json:"last_sequence,omitempty"`
+
+	// RevokedSequence and RevokedAt are the STOP that revoked the token,
+	// of scope RevokedScope for RevokedReason; zero while the ledger
+	// records no revocation
+	RevokedSequence int64  `json:"revoked_sequence,omitempty"`
+	RevokedAt       int64  `json:"revoked_at,omitempty"`
+	RevokedScope    string `json:"revoked_scope,omitempty"`
+	RevokedReason   string `json:"revoked_reason,omitempty"`
+
+	// Intact is whether every receipt of the lifecycle holds
+	Intact bool `json:"intact"`
+}
+
+// TokensResponse is GET /api/tokens
+type TokensResponse struct {
+	Tokens []TokenLifecycle `json:"tokens"`
+
+	// More is whether older tokens matched than were returned
+	More bool `json:"more"`
+}
+
+// tokenLifecycles assembles the lifecycle of every token minted in
+// receipts that passes the filter, newest first
+func tokenLifecycles(receipts []audit.Receipt, checks []audit.ReceiptCheck, filter TokenFilter) TokensResponse {
+	var lifecycles, open []*TokenLifecycle
+	byDigest := map[string]*TokenLifecycle{}
+	for i, receipt := range receipts {
+		data := receipt.EventData
+		digest := stringField(data, "token_digest")
+		switch {
+		case receipt.EventType == "token_mint" && digest != "":
+			lifecycle := &TokenLifecycle{
+				Digest:       digest,
+				PrincipalID:  stringField(data, "principal_id"),
+				NamespaceID:  stringField(data, "namespace_id"),
+				RequestID:    stringField(data, "request_id"),
+				Scope:        stringsField(data, "scope"),
+				ParentDigest: stringField(data, "parent_digest"),
+				DecisionID:   stringField(data, "decision_id"),
+				MintSequence: receipt.Sequence,
+				MintedAt:     receipt.Timestamp,
+				Intact:       checks[i].OK(),
+			}
+			lifecycles = append(lifecycles, lifecycle)
+			open = append(open, lifecycle)
+			byDigest[digest] = lifecycle
+		case receipt.EventType == "stop_event":
+			live := open[:0]
+			for _, lifecycle := range open {
+				if !revokes(data, lifecycle) {
+					live = append(live, lifecycle)
+					continue
+				}
+				lifecycle.RevokedSequence = receipt.Sequence
+				lifecycle.RevokedAt = receipt.Timestamp
+				lifecycle.RevokedScope = stringField(data, "stop_scope")
+				lifecycle.RevokedReason = stringField(data, "stop_reason")
+				lifecycle.Intact = lifecycle.Intact && checks[i].OK()
+			}
+			open = live
+		case digest != "":
+			if lifecycle, ok := byDigest[digest]; ok {
+				lifecycle.Uses++
+				lifecycle.LastSequence = receipt.Sequence
+				lifecycle.Intact = lifecycle.Intact && checks[i].OK()
+			}
+		}
+	}
+
+	response := TokensResponse{Tokens: []TokenLifecycle{}}
+	for i := len(lifecycles) - 1; i >= 0; i-- {
+		lifecycle := lifecycles[i]
+		if !filter.matches(lifecycle) {
+			continue
+		}
+		if len(response.Tokens) == maxTokens {
+			response.More = true
+			break
+		}
+		response.Tokens = append(response.Tokens, *lifecycle)
+	}
+	return response
+}
+
+// revokes reports whether a stop_event revoked the token: a global STOP
+// revokes every token, a principal STOP its principal's, a token STOP the
+// one it names
+func revokes(stop map[string]interface{}, lifecycle *TokenLifecycle) bool {
+	target := stringField(stop, "stop_target")
+	switch stringField(stop, "stop_scope") {
+	case audit.StopScopeGlobal:
+		return true
+	case audit.StopScopePrincipal:
+		return target == lifecycle.PrincipalID
+	case audit.StopScopeToken:
+		return target == lifecycle.Digest
+	default:
+		return false
+	}
+}
+
+// matches reports whether a lifecycle passes every set field of the filter
+func (f TokenFilter) matches(lifecycle *TokenLifecycle) bool {
+	return (f.PrincipalID == "" || f.PrincipalID == lifecycle.PrincipalID) &&
+		(f.NamespaceID == "" || f.NamespaceID == lifecycle.NamespaceID) &&
+		(f.TokenDigest == "" || f.TokenDigest == lifecycle.Digest) &&
+		(!f.Broken || !lifecycle.Intact)
+}
+
+// stringField returns a string field of event data, or ""
+func stringField(data map[string]interface{}, field string) string {
+	value, _ := data[field].(string)
+	return value
+}
+
+// stringsField returns a list field of event data, as held in memory or
+// as decoded from a file
+func stringsField(data map[string]interface{}, field string) []string {
+	switch value := data[field].(type) {
+	case []string:
+		return value
+	case []interface{}:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				items = append(items, s)
+			}
+		}
+		return items
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,132 @@
+// WHY: OIDC issuers publish their signing keys as a JSON Web Key Set, and
+// rotate them by adding and retiring entries there. ParseJWKS turns that
+// document into the KeySet a Verifier checks against, keeping only
+// signing keys of the types it can verify, so a deployment pins the
+// issuer's published keys rather than copying each one by hand.
+package identity
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// jsonWebKey is the part of a JWK a KeySet is built from
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	Curve   string `json:"crv"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+// LoadJWKS reads the JSON Web Key Set in the file at path
+func LoadJWKS(path string) (KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("key set: %w", err)
+	}
+	return ParseJWKS(data)
+}
+
+// ParseJWKS returns the signing keys of a JSON Web Key Set by key ID.
+// Keys for encryption, and of types a Verifier cannot check, are left
+// out; a set with no usable key, a key without an ID, or an ID used twice
+// is an error.
+func ParseJWKS(data []byte) (KeySet, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("key set unreadable: %w", err)
+	}
+	keys := make(KeySet, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", jwk.KeyID, err)
+		}
+		if key == nil {
+			continue
+		}
+		if jwk.KeyID == "" {
+			return nil, fmt.Errorf("key set has a key without an ID")
+		}
+		if _, seen := keys[jwk.KeyID]; seen {
+			return nil, fmt.Errorf("key set has key %q twice", jwk.KeyID)
+		}
+		keys[jwk.KeyID] = key
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("key set has no signing key")
+	}
+	return keys, nil
+}
+
+// publicKey returns the key a JWK describes, or nil for a type a Verifier
+// cannot check
+func (jwk jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch {
+	case jwk.KeyType == "RSA":
+		n, err := decodeInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("rsa exponent out of range")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case jwk.KeyType == "EC" && jwk.Curve == "P-256":
+		x, err := decodeInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !elliptic.P256().IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point is not on P-256")
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	case jwk.KeyType == "OKP" && jwk.Curve == "Ed25519":
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("ed25519 key size %d", len(x))
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, nil
+}
+
+// decodeInt decodes a base64url big-endian unsigned integer
+func decodeInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty integer")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,79 @@
+// WHY: Proves a published key set yields the issuer's signing keys by ID
+// and that a key set that cannot be trusted whole is refused.
+package identity
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/clock"
+)
+
+// encodeInt base64url-encodes a big-endian unsigned integer
+func encodeInt(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
+
+// TestParseJWKSVerifiesPublishedKeys proves tokens verify against the
+// keys of a parsed set and encryption keys are left out
+func TestParseJWKSVerifiesPublishedKeys(t *testing.T) {
+	edPublic, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	document, _ := json.Marshal(map[string]interface{}{"keys": []map[string]string{
+		{"kty": "OKP", "crv": "Ed25519", "kid": "ed", "x": base64.RawURLEncoding.EncodeToString(edPublic)},
+		{"kty": "RSA", "kid": "rsa", "use": "sig", "n": encodeInt(rsaKey.N), "e": encodeInt(big.NewInt(int64(rsaKey.E)))},
+		{"kty": "EC", "crv": "P-256", "kid": "ec", "x": encodeInt(ecKey.X), "y": encodeInt(ecKey.Y)},
+		{"kty": "RSA", "kid": "enc", "use": "enc", "n": encodeInt(rsaKey.N), "e": encodeInt(big.NewInt(int64(rsaKey.E)))},
+		{"kty": "oct", "kid": "secret", "k": "c2VjcmV0"},
+	}})
+
+	keys, err := ParseJWKS(document)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(keys) != 3 {
+		t.Fatalf("want the three signing keys, got %d", len(keys))
+	}
+
+	fake := clock.NewFake(time.Unix(1_700_000_000, 0))
+	verifier := &Verifier{Issuer: "https://issuer.example", Audience: "oi-kernel", Keys: keys, Clock: fake}
+	for _, token := range []string{
+		signJWT(t, AlgorithmEdDSA, "ed", edKey, claimsAt(fake.Now())),
+		signJWT(t, AlgorithmRS256, "rsa", rsaKey, claimsAt(fake.Now())),
+		signJWT(t, AlgorithmES256, "ec", ecKey, claimsAt(fake.Now())),
+	} {
+		if _, err := verifier.Verify(token); err != nil {
+			t.Fatalf("verify: %v", err)
+		}
+	}
+}
+
+// TestParseJWKSRefusesUntrustworthySets proves a set that is unreadable,
+// has no signing key, or has a damaged, unnamed, or repeated key is
+// refused
+func TestParseJWKSRefusesUntrustworthySets(t *testing.T) {
+	edPublic, _, _ := ed25519.GenerateKey(rand.Reader)
+	x := base64.RawURLEncoding.EncodeToString(edPublic)
+	for name, document := range map[string]string{
+		"unreadable":     `{"keys":`,
+		"empty":          `{"keys":[]}`,
+		"only symmetric": `{"keys":[{"kty":"oct","kid":"a","k":"c2VjcmV0"}]}`,
+		"short key":      `{"keys":[{"kty":"OKP","crv":"Ed25519","kid":"a","x":"AAAA"}]}`,
+		"off curve":      `{"keys":[{"kty":"EC","crv":"P-256","kid":"a","x":"AQ","y":"AQ"}]}`,
+		"no key id":      `{"keys":[{"kty":"OKP","crv":"Ed25519","x":"` + x + `"}]}`,
+		"repeated id":    `{"keys":[{"kty":"OKP","crv":"Ed25519","kid":"a","x":"` + x + `"},{"kty":"OKP","crv":"Ed25519","kid":"a","x":"` + x + `"}]}`,
+	} {
+		if _, err := ParseJWKS([]byte(document)); err == nil {
+			t.Fatalf("%s: key set must be refused", name)
+		}
+	}
+}
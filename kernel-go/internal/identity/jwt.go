@@ -0,0 +1,262 @@
+// WHY: The principal a request ran as was whatever the caller put in the
+// identity capsule or session, so any caller could claim to be anyone. A
+// Verifier checks an OIDC/JWT bearer token against the issuer's keys and
+// the expected issuer and audience, and only then yields the principal,
+// namespace, and attributes the token states. Anything it cannot verify
+// (an unknown key, an algorithm the key does not use, a lapsed or
+// not-yet-valid token, a missing subject or namespace) is an error, never
+// a partial identity.
+package identity
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/clock"
+)
+
+// DefaultNamespaceClaim is the claim a namespace is read from when the
+// verifier names none
+const DefaultNamespaceClaim = "namespace"
+
+// DefaultLeeway is the clock skew allowed on a token's validity window
+// when the verifier sets none
+const DefaultLeeway = 30 * time.Second
+
+// JWT signature algorithms a Verifier accepts
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// registeredClaims are the claims a Verifier checks itself and does not
+// pass on as attributes
+var registeredClaims = map[string]bool{
+	"iss": true, "sub": true, "aud": true, "exp": true, "nbf": true, "iat": true, "jti": true,
+}
+
+// KeySet holds an issuer's verification keys by key ID
+type KeySet map[string]crypto.PublicKey
+
+// Verifier checks bearer tokens from one issuer
+type Verifier struct {
+	// Issuer is the iss a token must carry
+	Issuer string
+
+	// Audience is a value the token's aud must hold
+	Audience string
+
+	// Keys are the issuer's keys; a token must name one by kid
+	Keys KeySet
+
+	// NamespaceClaim is the claim holding the namespace; empty means
+	// DefaultNamespaceClaim
+	NamespaceClaim string
+
+	// Leeway is the clock skew allowed on exp and nbf; zero means
+	// DefaultLeeway
+	Leeway time.Duration
+
+	// Clock is what exp and nbf are checked against; nil means the system
+	// clock
+	Clock clock.Clock
+}
+
+// Identity is what a verified token says about its holder
+type Identity struct {
+	PrincipalID string
+	NamespaceID string
+	Issuer      string
+	ExpiresAt   int64
+
+	// Attributes are the token's other string claims
+	Attributes map[string]string
+}
+
+// header is the part of a JWT header a Verifier reads
+type header struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+// Validate reports why the verifier cannot check tokens, if it cannot
+func (v *Verifier) Validate() error {
+	if v.Issuer == "" {
+		return fmt.Errorf("identity verifier needs an issuer")
+	}
+	if v.Audience == "" {
+		return fmt.Errorf("identity verifier needs an audience")
+	}
+	if len(v.Keys) == 0 {
+		return fmt.Errorf("identity verifier needs a key")
+	}
+	return nil
+}
+
+// Verify checks a compact-serialized JWT and returns the identity it
+// states
+func (v *Verifier) Verify(token string) (*Identity, error) {
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("bearer token is not a JWT")
+	}
+
+	var head header
+	if err := decodeSegment(parts[0], &head); err != nil {
+		return nil, fmt.Errorf("token header: %w", err)
+	}
+	key, ok := v.Keys[head.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("token key %q is not one of the issuer's", head.KeyID)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("token signature: %w", err)
+	}
+	if err := verifySignature(head.Algorithm, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("token claims: %w", err)
+	}
+	return v.identity(claims)
+}
+
+// identity checks the claims of a token whose signature holds and
+// returns the identity they state
+func (v *Verifier) identity(claims map[string]interface{}) (*Identity, error) {
+	if issuer, _ := claims["iss"].(string); issuer != v.Issuer {
+		return nil, fmt.Errorf("token issuer %q is not %q", issuer, v.Issuer)
+	}
+	if !audienceHolds(claims["aud"], v.Audience) {
+		return nil, fmt.Errorf("token is not for audience %q", v.Audience)
+	}
+
+	now := time.Now()
+	if v.Clock != nil {
+		now = v.Clock.Now()
+	}
+	leeway := v.Leeway
+	if leeway == 0 {
+		leeway = DefaultLeeway
+	}
+	expires, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("token has no expiry")
+	}
+	if !now.Before(time.Unix(int64(expires), 0).Add(leeway)) {
+		return nil, fmt.Errorf("token expired")
+	}
+	if notBefore, ok := claims["nbf"].(float64); ok && now.Add(leeway).Before(time.Unix(int64(notBefore), 0)) {
+		return nil, fmt.Errorf("token is not yet valid")
+	}
+
+	namespaceClaim := v.NamespaceClaim
+	if namespaceClaim == "" {
+		namespaceClaim = DefaultNamespaceClaim
+	}
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, fmt.Errorf("token has no subject")
+	}
+	namespace, _ := claims[namespaceClaim].(string)
+	if namespace == "" {
+		return nil, fmt.Errorf("token has no %s claim", namespaceClaim)
+	}
+
+	identity := &Identity{
+		PrincipalID: subject,
+		NamespaceID: namespace,
+		Issuer:      v.Issuer,
+		ExpiresAt:   int64(expires),
+		Attributes:  make(map[string]string),
+	}
+	for name, value := range claims {
+		if text, ok := value.(string); ok && !registeredClaims[name] && name != namespaceClaim {
+			identity.Attributes[name] = text
+		}
+	}
+	return identity, nil
+}
+
+// audienceHolds reports whether an aud claim, a string or an array of
+// strings, holds audience
+func audienceHolds(claim interface{}, audience string) bool {
+	switch aud := claim.(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, value := range aud {
+			if value == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// verifySignature checks a JWS signature over signed with key under
+// algorithm. WHY: The algorithm must be the one the key's type signs
+// with, so a token cannot pick a weaker check than its key implies.
+func verifySignature(algorithm string, key crypto.PublicKey, signed []byte, signature []byte) error {
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if algorithm != AlgorithmRS256 {
+			break
+		}
+		digest := sha256.Sum256(signed)
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+			return fmt.Errorf("token signature invalid")
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		if algorithm != AlgorithmES256 || key.Curve != elliptic.P256() {
+			break
+		}
+		if len(signature) != 64 {
+			return fmt.Errorf("token signature invalid")
+		}
+		digest := sha256.Sum256(signed)
+		r, s := new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(key, digest[:], r, s) {
+			return fmt.Errorf("token signature invalid")
+		}
+		return nil
+	case ed25519.PublicKey:
+		if algorithm != AlgorithmEdDSA {
+			break
+		}
+		if !ed25519.Verify(key, signed, signature) {
+			return fmt.Errorf("token signature invalid")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported key type %T", key)
+	}
+	return fmt.Errorf("token algorithm %q does not match its key", algorithm)
+}
+
+// decodeSegment decodes one base64url JSON segment of a JWT into value
+func decodeSegment(segment string, value interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
@@ -0,0 +1,148 @@
+// WHY: Proves a Verifier yields an identity only from a token signed by
+// one of the issuer's keys, under that key's algorithm, for the expected
+// issuer and audience, inside its validity window, and naming a subject
+// and a namespace.
+package identity
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/clock"
+)
+
+// signJWT returns a compact JWT of claims signed with key under algorithm
+func signJWT(t *testing.T, algorithm string, keyID string, key crypto.Signer, claims map[string]interface{}) string {
+	t.Helper()
+	head, _ := json.Marshal(map[string]string{"alg": algorithm, "kid": keyID, "typ": "JWT"})
+	body, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("encode claims: %v", err)
+	}
+	signed := base64.RawURLEncoding.EncodeToString(head) + "." + base64.RawURLEncoding.EncodeToString(body)
+	digest := sha256.Sum256([]byte(signed))
+
+	var signature []byte
+	switch key := key.(type) {
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(key, []byte(signed))
+	case *rsa.PrivateKey:
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	case *ecdsa.PrivateKey:
+		r, s, signErr := ecdsa.Sign(rand.Reader, key, digest[:])
+		signature, err = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...), signErr
+	}
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// claimsAt returns valid claims for alice in tenant_a, issued at now
+func claimsAt(now time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"iss":       "https://issuer.example",
+		"aud":       []string{"other", "oi-kernel"},
+		"sub":       "alice",
+		"namespace": "tenant_a",
+		"email":     "alice@example.com",
+		"exp":       now.Add(time.Hour).Unix(),
+		"nbf":       now.Unix(),
+	}
+}
+
+// TestVerifierAcceptsEachAlgorithm proves RS256, ES256, and EdDSA tokens
+// verify and yield the identity they state
+func TestVerifierAcceptsEachAlgorithm(t *testing.T) {
+	fake := clock.NewFake(time.Unix(1_700_000_000, 0))
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	verifier := &Verifier{
+		Issuer:   "https://issuer.example",
+		Audience: "oi-kernel",
+		Keys:     KeySet{"ed": edKey.Public(), "rsa": rsaKey.Public(), "ec": ecKey.Public()},
+		Clock:    fake,
+	}
+
+	for _, tc := range []struct {
+		algorithm string
+		keyID     string
+		key       crypto.Signer
+	}{
+		{AlgorithmEdDSA, "ed", edKey},
+		{AlgorithmRS256, "rsa", rsaKey},
+		{AlgorithmES256, "ec", ecKey},
+	} {
+		identity, err := verifier.Verify(signJWT(t, tc.algorithm, tc.keyID, tc.key, claimsAt(fake.Now())))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.algorithm, err)
+		}
+		if identity.PrincipalID != "alice" || identity.NamespaceID != "tenant_a" || identity.Attributes["email"] != "alice@example.com" {
+			t.Fatalf("%s: identity %+v", tc.algorithm, identity)
+		}
+		if _, registered := identity.Attributes["iss"]; registered {
+			t.Fatalf("%s: registered claims must not be attributes", tc.algorithm)
+		}
+	}
+}
+
+// TestVerifierRefusesUnverifiableTokens proves every way a token can fail
+// to establish an identity is an error
+func TestVerifierRefusesUnverifiableTokens(t *testing.T) {
+	fake := clock.NewFake(time.Unix(1_700_000_000, 0))
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+	_, otherKey, _ := ed25519.GenerateKey(rand.Reader)
+	verifier := &Verifier{
+		Issuer:   "https://issuer.example",
+		Audience: "oi-kernel",
+		Keys:     KeySet{"ed": key.Public()},
+		Clock:    fake,
+	}
+	with := func(name string, value interface{}) map[string]interface{} {
+		claims := claimsAt(fake.Now())
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+
+	for name, token := range map[string]string{
+		"not a jwt":       "abc.def",
+		"unknown key":     signJWT(t, AlgorithmEdDSA, "missing", key, claimsAt(fake.Now())),
+		"wrong signer":    signJWT(t, AlgorithmEdDSA, "ed", otherKey, claimsAt(fake.Now())),
+		"wrong algorithm": signJWT(t, AlgorithmRS256, "ed", key, claimsAt(fake.Now())),
+		"wrong issuer":    signJWT(t, AlgorithmEdDSA, "ed", key, with("iss", "https://evil.example")),
+		"wrong audience":  signJWT(t, AlgorithmEdDSA, "ed", key, with("aud", "other")),
+		"no expiry":       signJWT(t, AlgorithmEdDSA, "ed", key, with("exp", nil)),
+		"expired":         signJWT(t, AlgorithmEdDSA, "ed", key, with("exp", fake.Now().Add(-time.Minute).Unix())),
+		"not yet valid":   signJWT(t, AlgorithmEdDSA, "ed", key, with("nbf", fake.Now().Add(time.Minute).Unix())),
+		"no subject":      signJWT(t, AlgorithmEdDSA, "ed", key, with("sub", nil)),
+		"no namespace":    signJWT(t, AlgorithmEdDSA, "ed", key, with("namespace", nil)),
+		"numeric subject": signJWT(t, AlgorithmEdDSA, "ed", key, with("sub", 42)),
+	} {
+		if _, err := verifier.Verify(token); err == nil {
+			t.Fatalf("%s: token must be refused", name)
+		}
+	}
+
+	token := signJWT(t, AlgorithmEdDSA, "ed", key, claimsAt(fake.Now()))
+	fake.Advance(time.Hour + DefaultLeeway)
+	if _, err := verifier.Verify(token); err == nil {
+		t.Fatal("a token past its expiry and leeway must be refused")
+	}
+	if _, err := (&Verifier{Audience: "oi-kernel", Keys: verifier.Keys}).Verify(token); err == nil {
+		t.Fatal("a verifier without an issuer must refuse everything")
+	}
+}
@@ -0,0 +1,401 @@
+// WHY: Operating a running kernel meant linking it: listing and revoking
+// tokens, moving the posture, loading a new capsule, and checking the
+// ledger were all calls only the embedding program could make. The admin
+// surface offers them to operators, behind authentication of its own: an
+// admin token must verify under the admin verifier, an issuer and audience
+// apart from the one requests are checked with, so no user token is ever
+// an admin token. The role it names decides which actions it may take.
+// Every admin action is ledgered, refused or not.
+package kernel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/identity"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// AdminRoleClaim is the claim of an admin token naming its role
+const AdminRoleClaim = "oi_admin_role"
+
+// Admin roles, each allowed what the one before it is and more
+const (
+	AdminRoleAuditor  = "auditor"
+	AdminRoleOperator = "operator"
+	AdminRoleGovernor = "governor"
+)
+
+// Admin actions, as ledgered in admin_action receipts
+const (
+	AdminListTokens    = "list_tokens"
+	AdminViewIntegrity = "view_integrity"
+	AdminVerifyLedger  = "verify_ledger"
+	AdminRevokeToken   = "revoke_token"
+	AdminSetPosture    = "set_posture"
+	AdminReloadCapsule = "reload_capsule"
+)
+
+// adminRoleActions are the actions each role may take. WHY: Reading is
+// the auditor's; taking capability away or tightening is the operator's;
+// replacing the policy itself is the governor's alone.
+var adminRoleActions = map[string][]string{
+	AdminRoleAuditor:  {AdminListTokens, AdminViewIntegrity, AdminVerifyLedger},
+	AdminRoleOperator: {AdminListTokens, AdminViewIntegrity, AdminVerifyLedger, AdminRevokeToken, AdminSetPosture},
+	AdminRoleGovernor: {AdminListTokens, AdminViewIntegrity, AdminVerifyLedger, AdminRevokeToken, AdminSetPosture, AdminReloadCapsule},
+}
+
+// maxAdminRequestBytes bounds an admin request body, a governance bundle
+// included
+const maxAdminRequestBytes = 1 << 20
+
+// PostureChange asks the admin surface to move a posture
+type PostureChange struct {
+	// Level is the posture to move to
+	Level int `json:"level"`
+
+	// Namespace names the namespace posture to move; empty means the
+	// kernel's
+	Namespace string `json:"namespace,omitempty"`
+
+	Reason string `json:"reason"`
+
+	// ApproverToken is a second admin's token, for a lowering the
+	// governance downgrade rules want approved
+	ApproverToken string `json:"approver_token,omitempty"`
+}
+
+// IntegrityReport is the state the kernel's integrity rests on
+type IntegrityReport struct {
+	Integrity    IntegrityState `json:"integrity"`
+	PostureLevel int            `json:"posture_level"`
+	Halts        []Halt         `json:"halts"`
+
+	// CapsuleHash and PolicyVersion are the governance committed;
+	// CapsuleInForce is false once the capsule in memory has moved from it
+	CapsuleHash    string `json:"capsule_hash,omitempty"`
+	PolicyVersion  string `json:"policy_version,omitempty"`
+	CapsuleInForce bool   `json:"capsule_in_force"`
+
+	LedgerCheck  LedgerCheck `json:"ledger_check"`
+	ActiveTokens int         `json:"active_tokens"`
+}
+
+// SetAdminVerifier sets what admin tokens are checked with; nil refuses
+// every admin action. WHY: An admin verifier accepting the tokens
+// requests carry would make every user an admin, so one sharing the
+// request verifier's issuer and audience is refused.
+func (s *SystemState) SetAdminVerifier(verifier *identity.Verifier) error {
+	if verifier != nil {
+		if err := verifier.Validate(); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if verifier != nil && s.identityVerifier != nil && verifier.Issuer == s.identityVerifier.Issuer && verifier.Audience == s.identityVerifier.Audience {
+		return fmt.Errorf("admin tokens need an issuer or audience of their own, not %s for %s", verifier.Issuer, verifier.Audience)
+	}
+	s.adminVerifier = verifier
+	return nil
+}
+
+// authorizeAdmin verifies bearer as an admin token whose role may take
+// action, and returns the admin as actor with the role. A refusal is
+// ledgered here; the caller ledgers the outcome otherwise.
+func (s *SystemState) authorizeAdmin(bearer string, action string, target string) (audit.Attribution, string, error) {
+	actor := s.attribution("")
+	s.mu.RLock()
+	var verifier identity.Verifier
+	configured := s.adminVerifier != nil
+	if configured {
+		verifier = *s.adminVerifier
+		if verifier.Clock == nil {
+			verifier.Clock = s.clock
+		}
+	}
+	s.mu.RUnlock()
+
+	var verified *identity.Identity
+	var err error
+	switch {
+	case !configured:
+		err = fmt.Errorf("no admin verifier is set")
+	case bearer == "":
+		err = fmt.Errorf("no admin token")
+	default:
+		verified, err = verifier.Verify(bearer)
+	}
+	if err != nil {
+		s.AuditLedger.AppendAdminAction(actor, action, "", target, StopRefusedUnauthenticated, "")
+		return actor, "", &AccessRefusedError{Reason: StopRefusedUnauthenticated, Err: fmt.Errorf("admin identity unverifiable: %w", err)}
+	}
+
+	actor.PrincipalID, actor.NamespaceID = verified.PrincipalID, verified.NamespaceID
+	role := verified.Attributes[AdminRoleClaim]
+	for _, allowed := range adminRoleActions[role] {
+		if allowed == action {
+			return actor, role, nil
+		}
+	}
+	s.AuditLedger.AppendAdminAction(actor, action, role, target, StopRefusedForbidden, "")
+	return actor, role, &AccessRefusedError{Reason: StopRefusedForbidden, Err: fmt.Errorf("admin %s with role %q may not %s", verified.PrincipalID, role, action)}
+}
+
+// adminOutcome ledgers the outcome of an authorized admin action and
+// returns err as a refusal, if there was one
+func (s *SystemState) adminOutcome(actor audit.Attribution, action string, role string, target string, detail string, err error) error {
+	if err == nil {
+		s.AuditLedger.AppendAdminAction(actor, action, role, target, "", detail)
+		return nil
+	}
+	s.AuditLedger.AppendAdminAction(actor, action, role, target, StopRefusedInvalid, err.Error())
+	return &AccessRefusedError{Reason: StopRefusedInvalid, Err: err}
+}
+
+// AdminListTokens describes every token the kernel holds, revoked ones
+// included, oldest first
+func (s *SystemState) AdminListTokens(bearer string) ([]TokenInfo, error) {
+	actor, role, err := s.authorizeAdmin(bearer, AdminListTokens, "")
+	if err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	tokens := make([]TokenInfo, 0, len(s.ActiveCapabilityTokens))
+	for _, token := range s.ActiveCapabilityTokens {
+		tokens = append(tokens, s.tokenInfoLocked(token))
+	}
+	s.mu.RUnlock()
+	sort.Slice(tokens, func(i, j int) bool {
+		if !tokens[i].IssuedAt.Equal(tokens[j].IssuedAt) {
+			return tokens[i].IssuedAt.Before(tokens[j].IssuedAt)
+		}
+		return tokens[i].Digest < tokens[j].Digest
+	})
+	s.adminOutcome(actor, AdminListTokens, role, "", fmt.Sprintf("%d tokens", len(tokens)), nil)
+	return tokens, nil
+}
+
+// AdminRevokeToken pulls a token STOP on digest as the admin
+func (s *SystemState) AdminRevokeToken(bearer string, digest string) error {
+	actor, role, err := s.authorizeAdmin(bearer, AdminRevokeToken, digest)
+	if err != nil {
+		return err
+	}
+	s.mu.RLock()
+	token, held := s.ActiveCapabilityTokens[digest]
+	active := held && token.RevokedAt == nil
+	s.mu.RUnlock()
+	if !active {
+		return s.adminOutcome(actor, AdminRevokeToken, role, digest, "", fmt.Errorf("no active token %s", digest))
+	}
+	s.stopWhere(actor, audit.StopScopeToken, digest, audit.StopReasonOperatorAction, func(token *capabilities.Token) bool {
+		return token.Digest == digest && token.RevokedAt == nil
+	})
+	return s.adminOutcome(actor, AdminRevokeToken, role, digest, "", nil)
+}
+
+// AdminSetPosture moves a posture as change asks. Raising is the admin's
+// to do; lowering is a governed downgrade, requested by the admin,
+// approved by the admin change.ApproverToken names if any, and held to the
+// posture_downgrade consent and the governance downgrade rules.
+func (s *SystemState) AdminSetPosture(bearer string, change PostureChange) error {
+	actor, role, err := s.authorizeAdmin(bearer, AdminSetPosture, change.Namespace)
+	if err != nil {
+		return err
+	}
+	detail := fmt.Sprintf("posture %d", change.Level)
+	return s.adminOutcome(actor, AdminSetPosture, role, change.Namespace, detail, s.setPosture(actor, change))
+}
+
+// setPosture moves the posture change names for actor
+func (s *SystemState) setPosture(actor audit.Attribution, change PostureChange) error {
+	if !posture.IsValid(change.Level) {
+		return fmt.Errorf("posture %d is not defined", change.Level)
+	}
+	if change.Reason == "" {
+		return fmt.Errorf("a posture change needs a reason")
+	}
+	state := s.Posture
+	if change.Namespace != "" {
+		state = s.NamespacePosture(change.Namespace)
+	}
+
+	current := state.Level()
+	switch {
+	case change.Level == current:
+		return fmt.Errorf("posture is already %d", current)
+	case change.Level > current:
+		if _, raised := state.Raise(change.Level, change.Reason); !raised {
+			return fmt.Errorf("posture did not rise to %d", change.Level)
+		}
+		return nil
+	}
+
+	approver := ""
+	if change.ApproverToken != "" {
+		approving, _, err := s.authorizeAdmin(change.ApproverToken, AdminSetPosture, change.Namespace)
+		if err != nil {
+			return fmt.Errorf("approver: %w", err)
+		}
+		approver = approving.PrincipalID
+	}
+	return s.LowerPosture(change.Namespace, posture.Downgrade{
+		Level:       change.Level,
+		Reason:      change.Reason,
+		RequestedBy: actor.PrincipalID,
+		ApprovedBy:  approver,
+	})
+}
+
+// AdminReloadCapsule applies an encoded governance bundle as the admin
+// (see ApplyGovernanceBundle). WHY: The bundle must still be signed with
+// the pinned key; the governor role lets an admin deliver a policy, not
+// author one.
+func (s *SystemState) AdminReloadCapsule(bearer string, bundle []byte) error {
+	actor, role, err := s.authorizeAdmin(bearer, AdminReloadCapsule, "")
+	if err != nil {
+		return err
+	}
+	if err := s.ApplyGovernanceBundle(bundle); err != nil {
+		return s.adminOutcome(actor, AdminReloadCapsule, role, "", "", err)
+	}
+	s.mu.RLock()
+	committed := s.governance.CapsuleHash
+	s.mu.RUnlock()
+	return s.adminOutcome(actor, AdminReloadCapsule, role, committed, "", nil)
+}
+
+// AdminVerifyLedger runs VerifyAndEnforce as the admin and returns its
+// outcome; a ledger that fails to verify is an outcome, not a refusal
+func (s *SystemState) AdminVerifyLedger(bearer string) (LedgerCheck, error) {
+	actor, role, err := s.authorizeAdmin(bearer, AdminVerifyLedger, "")
+	if err != nil {
+		return LedgerCheck{}, err
+	}
+	s.VerifyAndEnforce()
+	check := s.LastLedgerCheck()
+	detail := "verified"
+	if check.Error != "" {
+		detail = "failed: " + check.Error
+	}
+	s.adminOutcome(actor, AdminVerifyLedger, role, "", detail, nil)
+	return check, nil
+}
+
+// AdminIntegrity reports the state the kernel's integrity rests on
+func (s *SystemState) AdminIntegrity(bearer string) (IntegrityReport, error) {
+	actor, role, err := s.authorizeAdmin(bearer, AdminViewIntegrity, "")
+	if err != nil {
+		return IntegrityReport{}, err
+	}
+	report := IntegrityReport{
+		Integrity:    s.GetIntegrityState(),
+		PostureLevel: s.PostureLevel(),
+		Halts:        s.Halts(),
+		LedgerCheck:  s.LastLedgerCheck(),
+	}
+	s.mu.RLock()
+	live, hashErr := s.GovernanceCapsule.Hash()
+	report.CapsuleHash, report.PolicyVersion = s.governance.CapsuleHash, s.governance.PolicyVersion
+	report.CapsuleInForce = hashErr == nil && live == s.governance.CapsuleHash
+	for _, token := range s.ActiveCapabilityTokens {
+		if token.RevokedAt == nil {
+			report.ActiveTokens++
+		}
+	}
+	s.mu.RUnlock()
+	s.adminOutcome(actor, AdminViewIntegrity, role, "", string(report.Integrity), nil)
+	return report, nil
+}
+
+// AdminHandler serves the admin surface. Every request carries an admin
+// token in Authorization; responses are JSON, or {"error": ...} with 400,
+// 401, 403, or 413 when refused.
+//
+//	GET  /admin/tokens                  AdminListTokens
+//	POST /admin/tokens/{digest}/revoke  AdminRevokeToken
+//	POST /admin/posture                 AdminSetPosture (a JSON PostureChange)
+//	POST /admin/capsule                 AdminReloadCapsule (an encoded GovernanceBundle)
+//	POST /admin/ledger/verify           AdminVerifyLedger
+//	GET  /admin/integrity               AdminIntegrity
+func (s *SystemState) AdminHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /admin/tokens", func(w http.ResponseWriter, r *http.Request) {
+		tokens, err := s.AdminListTokens(adminBearer(r))
+		writeAdminResponse(w, tokens, err)
+	})
+	mux.HandleFunc("POST /admin/tokens/{digest}/revoke", func(w http.ResponseWriter, r *http.Request) {
+		err := s.AdminRevokeToken(adminBearer(r), r.PathValue("digest"))
+		writeAdminResponse(w, map[string]bool{"revoked": true}, err)
+	})
+	mux.HandleFunc("POST /admin/posture", func(w http.ResponseWriter, r *http.Request) {
+		var change PostureChange
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAdminRequestBytes))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&change); err != nil {
+			writeAdminBodyError(w, err)
+			return
+		}
+		err := s.AdminSetPosture(adminBearer(r), change)
+		writeAdminResponse(w, map[string]int{"posture_level": s.PostureLevelFor(change.Namespace)}, err)
+	})
+	mux.HandleFunc("POST /admin/capsule", func(w http.ResponseWriter, r *http.Request) {
+		// WHY: A bundle cut short would fail its signature and degrade
+		// integrity, so one too large is refused before it is applied
+		bundle, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxAdminRequestBytes))
+		if err != nil {
+			writeAdminBodyError(w, err)
+			return
+		}
+		err = s.AdminReloadCapsule(adminBearer(r), bundle)
+		writeAdminResponse(w, map[string]bool{"reloaded": true}, err)
+	})
+	mux.HandleFunc("POST /admin/ledger/verify", func(w http.ResponseWriter, r *http.Request) {
+		check, err := s.AdminVerifyLedger(adminBearer(r))
+		writeAdminResponse(w, check, err)
+	})
+	mux.HandleFunc("GET /admin/integrity", func(w http.ResponseWriter, r *http.Request) {
+		report, err := s.AdminIntegrity(adminBearer(r))
+		writeAdminResponse(w, report, err)
+	})
+	return mux
+}
+
+// adminBearer returns the admin token in the Authorization header
+func adminBearer(r *http.Request) string {
+	bearer, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return bearer
+}
+
+// writeAdminResponse writes body, or the refusal err
+func writeAdminResponse(w http.ResponseWriter, body interface{}, err error) {
+	if err == nil {
+		writeStopResponse(w, http.StatusOK, body)
+		return
+	}
+	reason := StopRefusedInvalid
+	var refused *AccessRefusedError
+	if errors.As(err, &refused) {
+		reason = refused.Reason
+	}
+	writeStopResponse(w, refusalStatus(reason), map[string]string{"error": err.Error()})
+}
+
+// writeAdminBodyError reports a body that could not be read: 413 if it
+// was too large, 400 otherwise
+func writeAdminBodyError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeStopResponse(w, http.StatusRequestEntityTooLarge, map[string]string{"error": fmt.Sprintf("admin request exceeds %d bytes", tooLarge.Limit)})
+		return
+	}
+	writeStopResponse(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("admin request: %v", err)})
+}
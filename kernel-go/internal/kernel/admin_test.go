@@ -0,0 +1,225 @@
+// WHY: Proves the admin surface takes only admin tokens, that each role
+// reaches only its own actions, that a posture lowering stays a governed
+// downgrade, and that every admin action leaves a receipt.
+package kernel
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/identity"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// adminKernel returns a kernel verifying request tokens signed with the
+// first key and admin tokens signed with the second
+func adminKernel(t *testing.T) (*SystemState, ed25519.PrivateKey, ed25519.PrivateKey) {
+	t.Helper()
+	state, key := stopKernel(t)
+	public, adminKey, _ := ed25519.GenerateKey(rand.Reader)
+	if err := state.SetAdminVerifier(&identity.Verifier{
+		Issuer:   "https://issuer.example",
+		Audience: "oi-kernel-admin",
+		Keys:     identity.KeySet{"admin_key": public},
+	}); err != nil {
+		t.Fatalf("set admin verifier: %v", err)
+	}
+	return state, key, adminKey
+}
+
+// adminTokenFor signs an admin token for subject holding role
+func adminTokenFor(key ed25519.PrivateKey, subject string, role string) string {
+	head, _ := json.Marshal(map[string]string{"alg": identity.AlgorithmEdDSA, "kid": "admin_key"})
+	body, _ := json.Marshal(map[string]interface{}{
+		"iss": "https://issuer.example", "aud": "oi-kernel-admin", "sub": subject, "namespace": "ops",
+		"exp": time.Now().Add(time.Hour).Unix(), AdminRoleClaim: role,
+	})
+	signed := base64.RawURLEncoding.EncodeToString(head) + "." + base64.RawURLEncoding.EncodeToString(body)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(ed25519.Sign(key, []byte(signed)))
+}
+
+// adminReceipts returns the admin_action receipts in the ledger
+func adminReceipts(t *testing.T, state *SystemState) []audit.Receipt {
+	t.Helper()
+	page, err := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"admin_action"}})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	return page.Receipts
+}
+
+// TestAdminTakesOnlyAdminTokens proves no request token, and no admin
+// verifier sharing the request audience, opens the admin surface
+func TestAdminTakesOnlyAdminTokens(t *testing.T) {
+	state, key := stopKernel(t)
+	user := bearerFor(t, key, "alice", "test_namespace", time.Now().Add(time.Hour))
+
+	if _, err := state.AdminListTokens(user); err == nil {
+		t.Fatal("no admin action may be taken while no admin verifier is set")
+	}
+	public := key.Public().(ed25519.PublicKey)
+	if err := state.SetAdminVerifier(&identity.Verifier{Issuer: "https://issuer.example", Audience: "oi-kernel", Keys: identity.KeySet{"issuer_key": public}}); err == nil {
+		t.Fatal("an admin verifier accepting request tokens must be refused")
+	}
+
+	state, key, _ = adminKernel(t)
+	user = bearerFor(t, key, "alice", "test_namespace", time.Now().Add(time.Hour))
+	_, err := state.AdminListTokens(user)
+	var refused *AccessRefusedError
+	if !errors.As(err, &refused) || refused.Reason != StopRefusedUnauthenticated {
+		t.Fatalf("a request token must not be an admin token, got %v", err)
+	}
+	if err := state.SetIdentityVerifier(&identity.Verifier{Issuer: "https://issuer.example", Audience: "oi-kernel-admin", Keys: identity.KeySet{"issuer_key": public}}); err == nil {
+		t.Fatal("a request verifier accepting admin tokens must be refused")
+	}
+
+	receipts := adminReceipts(t, state)
+	if len(receipts) != 1 || receipts[0].EventData["accepted"] != false || receipts[0].EventData["refusal"] != StopRefusedUnauthenticated || receipts[0].Severity != audit.SeverityWarn {
+		t.Fatalf("the refusal must be ledgered as a warning, got %+v", receipts)
+	}
+}
+
+// TestAdminRolesBoundTheirActions proves an auditor only reads, an
+// operator revokes as themself, and a bundle still needs the pinned key
+func TestAdminRolesBoundTheirActions(t *testing.T) {
+	state, _, adminKey := adminKernel(t)
+	auditor := adminTokenFor(adminKey, "ada", AdminRoleAuditor)
+	operator := adminTokenFor(adminKey, "otto", AdminRoleOperator)
+	governor := adminTokenFor(adminKey, "greta", AdminRoleGovernor)
+	token := mintApprover(t, state, "alice", "scope1")
+
+	tokens, err := state.AdminListTokens(auditor)
+	if err != nil || len(tokens) != 1 || tokens[0].Digest != token.Digest || !tokens[0].Active {
+		t.Fatalf("an auditor must list the tokens, got %+v %v", tokens, err)
+	}
+	var refused *AccessRefusedError
+	if err := state.AdminRevokeToken(auditor, token.Digest); !errors.As(err, &refused) || refused.Reason != StopRefusedForbidden {
+		t.Fatalf("an auditor must not revoke, got %v", err)
+	}
+	if err := state.AdminRevokeToken(adminTokenFor(adminKey, "nobody", ""), token.Digest); !errors.As(err, &refused) || refused.Reason != StopRefusedForbidden {
+		t.Fatalf("an admin token naming no role must take no action, got %v", err)
+	}
+	if err := state.AdminRevokeToken(operator, token.Digest); err != nil || token.RevokedAt == nil {
+		t.Fatalf("an operator must revoke, got %v", err)
+	}
+	if err := state.AdminRevokeToken(operator, token.Digest); !errors.As(err, &refused) || refused.Reason != StopRefusedInvalid {
+		t.Fatalf("a revoked token must not be revoked again, got %v", err)
+	}
+	page, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"stop_event"}})
+	if len(page.Receipts) != 1 || page.Receipts[0].EventData["principal_id"] != "otto" {
+		t.Fatalf("the STOP must be ledgered as the operator's, got %+v", page.Receipts)
+	}
+
+	capsule := DefaultGovernanceCapsule()
+	capsule.PolicyVersion = "v2"
+	bundle, fingerprint := signedGovernance(t, capsule)
+	if err := state.AdminReloadCapsule(operator, bundle); !errors.As(err, &refused) || refused.Reason != StopRefusedForbidden {
+		t.Fatalf("an operator must not reload the capsule, got %v", err)
+	}
+	if err := state.AdminReloadCapsule(governor, bundle); err == nil {
+		t.Fatal("a bundle must not load without a pinned key")
+	}
+	state.SetIntegrityState(IntegrityOK)
+	state.GovernanceCapsule.Commitments[CommitmentGovernanceKey] = fingerprint
+	if err := state.AdminReloadCapsule(governor, bundle); err != nil || state.GovernanceCapsule.PolicyVersion != "v2" {
+		t.Fatalf("a governor must reload a bundle signed by the pinned key, got %v", err)
+	}
+
+	accepted, refusals := 0, 0
+	for _, receipt := range adminReceipts(t, state) {
+		if receipt.EventData["accepted"] == true {
+			accepted++
+		} else {
+			refusals++
+		}
+	}
+	if accepted != 3 || refusals != 5 {
+		t.Fatalf("every admin action must be ledgered, got %d accepted and %d refused", accepted, refusals)
+	}
+}
+
+// TestAdminPostureLoweringIsGoverned proves an operator raises the
+// posture at will but lowers it only with consent and a second admin
+func TestAdminPostureLoweringIsGoverned(t *testing.T) {
+	state, _, adminKey := adminKernel(t)
+	state.GovernanceCapsule.PostureDowngrade = posture.DowngradeRules{RequireApprover: true}
+	operator := adminTokenFor(adminKey, "otto", AdminRoleOperator)
+
+	if err := state.AdminSetPosture(operator, PostureChange{Level: posture.P3, Reason: "incident"}); err != nil || state.PostureLevel() != posture.P3 {
+		t.Fatalf("an operator must raise the posture, got %v", err)
+	}
+	lower := PostureChange{Level: posture.P1, Reason: "incident closed"}
+	if err := state.AdminSetPosture(operator, lower); err == nil {
+		t.Fatal("a lowering without consent must be refused")
+	}
+	state.GrantConsent(ConsentPostureDowngrade, 0, "operator")
+	if err := state.AdminSetPosture(operator, lower); err == nil {
+		t.Fatal("a lowering without an approver must be refused")
+	}
+	lower.ApproverToken = adminTokenFor(adminKey, "otto", AdminRoleOperator)
+	if err := state.AdminSetPosture(operator, lower); err == nil {
+		t.Fatal("an admin must not approve their own lowering")
+	}
+	lower.ApproverToken = adminTokenFor(adminKey, "pia", AdminRoleOperator)
+	if err := state.AdminSetPosture(operator, lower); err != nil || state.PostureLevel() != posture.P1 {
+		t.Fatalf("a lowering with consent and approver must hold, got %v", err)
+	}
+
+	page, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"posture_change"}})
+	receipt := page.Receipts[len(page.Receipts)-1]
+	if receipt.EventData["requested_by"] != "otto" || receipt.EventData["approved_by"] != "pia" {
+		t.Fatalf("the downgrade must name both admins, got %+v", receipt.EventData)
+	}
+}
+
+// TestAdminHandlerReportsIntegrity proves the HTTP surface verifies the
+// ledger, reports integrity, and refuses tokens and bodies it must
+func TestAdminHandlerReportsIntegrity(t *testing.T) {
+	state, key, adminKey := adminKernel(t)
+	handler := state.AdminHandler()
+	auditor := adminTokenFor(adminKey, "ada", AdminRoleAuditor)
+	governor := adminTokenFor(adminKey, "greta", AdminRoleGovernor)
+	send := func(method string, path string, bearer string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+bearer)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send(http.MethodPost, "/admin/ledger/verify", auditor, "")
+	var check LedgerCheck
+	json.NewDecoder(rec.Body).Decode(&check)
+	if rec.Code != http.StatusOK || check.CheckedAt == 0 || check.Error != "" {
+		t.Fatalf("verify: %d %+v", rec.Code, check)
+	}
+
+	rec = send(http.MethodGet, "/admin/integrity", auditor, "")
+	var report IntegrityReport
+	json.NewDecoder(rec.Body).Decode(&report)
+	if rec.Code != http.StatusOK || report.Integrity != IntegrityOK || report.LedgerCheck != check || report.PostureLevel != state.PostureLevel() {
+		t.Fatalf("integrity: %d %+v", rec.Code, report)
+	}
+
+	if rec := send(http.MethodGet, "/admin/integrity", bearerFor(t, key, "alice", "test_namespace", time.Now().Add(time.Hour)), ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("a request token must be refused, got %d", rec.Code)
+	}
+	if rec := send(http.MethodPost, "/admin/posture", auditor, `{"level":3,"reason":"incident"}`); rec.Code != http.StatusForbidden {
+		t.Fatalf("an auditor must not move the posture, got %d", rec.Code)
+	}
+	if rec := send(http.MethodPost, "/admin/capsule", governor, strings.Repeat("x", maxAdminRequestBytes+1)); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("an oversized bundle must be refused, got %d", rec.Code)
+	}
+	if state.GetIntegrityState() != IntegrityOK {
+		t.Fatal("an oversized bundle must not reach the governance check")
+	}
+}
@@ -0,0 +1,46 @@
+// WHY: A forwarder's progress lived only in its status and a callback, so
+// nothing on the chain showed whether an off-box copy existed or when a
+// sink went dark. ForwardAudit ledgers every acknowledged batch as
+// sink_delivery and the first failure of each batch as sink_failure, and
+// resumes a sink after the last batch the ledger records it accepting.
+// A batch of nothing but those receipts is not ledgered again, or two
+// sinks would acknowledge each other's deliveries forever.
+package kernel
+
+import (
+	"github.com/user/oi/kernel-go/internal/audit"
+)
+
+// ForwardAudit starts copying the ledger to sink. Unless policy sets
+// FromSequence, delivery resumes after the last batch the ledger records
+// sink accepting.
+func (s *SystemState) ForwardAudit(sink audit.AuditSink, policy audit.ForwardPolicy) (*audit.Forwarder, error) {
+	if sink != nil && policy.FromSequence == 0 {
+		from, err := s.AuditLedger.LastDelivered(sink.Name())
+		if err != nil {
+			return nil, err
+		}
+		policy.FromSequence = from
+	}
+
+	onDelivery, onFailure := policy.OnDelivery, policy.OnFailure
+	policy.OnDelivery = func(delivery audit.DeliveryReceipt) {
+		if !delivery.Bookkeeping {
+			s.AuditLedger.AppendSinkDelivery(s.attribution(""), delivery)
+		}
+		if onDelivery != nil {
+			onDelivery(delivery)
+		}
+	}
+	policy.OnFailure = func(failure audit.DeliveryFailure) {
+		// WHY: A sink that is down fails every retry; its first failure
+		// marks the outage and the delivery that follows marks its end
+		if failure.Attempt <= 1 {
+			s.AuditLedger.AppendSinkFailure(s.attribution(""), failure)
+		}
+		if onFailure != nil {
+			onFailure(failure)
+		}
+	}
+	return s.AuditLedger.Forward(sink, policy)
+}
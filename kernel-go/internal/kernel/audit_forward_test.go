@@ -0,0 +1,148 @@
+// WHY: Proves a forwarder's deliveries and outages are ledgered, that
+// sinks do not acknowledge each other's delivery receipts forever, and
+// that a restarted forwarder resumes where the ledger says its sink left off.
+package kernel
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+)
+
+// recordingSink refuses the first failures batches, then records every
+// receipt it accepts
+type recordingSink struct {
+	name string
+
+	mu       sync.Mutex
+	failures int
+	accepted []audit.Receipt
+}
+
+func (r *recordingSink) Name() string {
+	return r.name
+}
+
+func (r *recordingSink) Deliver(receipts []audit.Receipt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failures > 0 {
+		r.failures--
+		return fmt.Errorf("collector unreachable")
+	}
+	r.accepted = append(r.accepted, receipts...)
+	return nil
+}
+
+func (r *recordingSink) received() []audit.Receipt {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]audit.Receipt(nil), r.accepted...)
+}
+
+// receiptsOf returns the ledger's receipts of eventType
+func receiptsOf(state *SystemState, eventType string) []audit.Receipt {
+	var found []audit.Receipt
+	for _, receipt := range state.AuditLedger.GetReceipts() {
+		if receipt.EventType == eventType {
+			found = append(found, receipt)
+		}
+	}
+	return found
+}
+
+// awaitForwarded waits until sink has the ledger's whole chain and the
+// ledger has stopped growing
+func awaitForwarded(t *testing.T, state *SystemState, sink *recordingSink) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		held := len(state.AuditLedger.GetReceipts())
+		if len(sink.received()) >= held {
+			time.Sleep(20 * time.Millisecond)
+			if len(state.AuditLedger.GetReceipts()) == held {
+				return
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("sink %s has %d of %d receipts", sink.name, len(sink.received()), held)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestForwardingIsLedgered proves an outage is ledgered once, the delivery
+// that ends it is ledgered, and two sinks settle instead of acknowledging
+// each other's delivery receipts without end
+func TestForwardingIsLedgered(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	first := &recordingSink{name: "first", failures: 3}
+	second := &recordingSink{name: "second"}
+	policy := audit.ForwardPolicy{MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+	for _, sink := range []*recordingSink{first, second} {
+		forwarder, err := state.ForwardAudit(sink, policy)
+		if err != nil {
+			t.Fatalf("forward: %v", err)
+		}
+		defer forwarder.Stop()
+	}
+
+	awaitForwarded(t, state, first)
+	awaitForwarded(t, state, second)
+
+	failures := receiptsOf(state, "sink_failure")
+	if len(failures) != 1 || failures[0].EventData["sink"] != "first" || failures[0].Severity != audit.SeverityWarn {
+		t.Fatalf("an outage is ledgered once, as a warning: %+v", failures)
+	}
+	recovered := false
+	for _, delivery := range receiptsOf(state, "sink_delivery") {
+		if delivery.EventData["sink"] == "first" && delivery.EventData["attempts"] == 4 {
+			recovered = true
+		}
+	}
+	if !recovered {
+		t.Fatal("the delivery ending the outage should be ledgered with its attempts")
+	}
+	if _, err := state.AuditLedger.Verify(); err != nil {
+		t.Fatalf("ledger should verify: %v", err)
+	}
+}
+
+// TestForwardingResumesFromTheLedger proves a forwarder restarted for the
+// same sink sends only what the ledger does not record it accepting
+func TestForwardingResumesFromTheLedger(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	before := &recordingSink{name: "collector"}
+	forwarder, err := state.ForwardAudit(before, audit.ForwardPolicy{})
+	if err != nil {
+		t.Fatalf("forward: %v", err)
+	}
+	awaitForwarded(t, state, before)
+	forwarder.Stop()
+
+	delivered := receiptsOf(state, "sink_delivery")
+	if len(delivered) == 0 {
+		t.Fatal("the first delivery should be ledgered")
+	}
+	state.RevokeAllTokens()
+
+	after := &recordingSink{name: "collector"}
+	forwarder, err = state.ForwardAudit(after, audit.ForwardPolicy{})
+	if err != nil {
+		t.Fatalf("forward: %v", err)
+	}
+	defer forwarder.Stop()
+	awaitFirst := time.Now().Add(2 * time.Second)
+	for len(after.received()) == 0 && time.Now().Before(awaitFirst) {
+		time.Sleep(time.Millisecond)
+	}
+
+	received := after.received()
+	last := delivered[len(delivered)-1].EventData["end_sequence"].(int64)
+	if len(received) == 0 || received[0].Sequence != last+1 {
+		t.Fatalf("delivery should resume after sequence %d, got %+v", last, received)
+	}
+}
@@ -0,0 +1,164 @@
+// WHY: Consents and revocations lived only in memory, so a restart came
+// back with whatever the process was started with: a consent the user had
+// withdrawn, or a token they had revoked, was live again. An authority
+// store keeps the authority capsule and the revoked-token set on disk,
+// written before a change is reported done, and a store that cannot be
+// read keeps the kernel from attaching it rather than starting without
+// the revocations.
+package kernel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// AuthorityVersion is the authority file format this kernel writes and
+// reads
+const AuthorityVersion = 1
+
+// authorityRecord is what the authority file holds
+type authorityRecord struct {
+	Version       int
+	Authority     AuthorityCapsule
+	RevokedTokens []string
+
+	// Hash is the hex SHA-256 of the encoded authority and revoked tokens
+	Hash string
+}
+
+// hash returns the hash of the record's authority and revoked tokens
+func (r *authorityRecord) hash() (string, error) {
+	data, err := json.Marshal(struct {
+		Version       int
+		Authority     AuthorityCapsule
+		RevokedTokens []string
+	}{r.Version, r.Authority, r.RevokedTokens})
+	if err != nil {
+		return "", err
+	}
+	return hashHex(data), nil
+}
+
+// AttachAuthorityStore keeps the authority capsule and the revoked-token
+// set in the file at path. What the file already holds replaces the
+// kernel's consents and revocations and adds to its revoked tokens; a
+// missing file is created. A file that is unreadable or fails its hash is
+// refused and nothing is attached.
+func (s *SystemState) AttachAuthorityStore(path string) error {
+	record, err := readAuthority(path)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	if record != nil {
+		s.AuthorityCapsule = record.Authority
+		for _, digest := range record.RevokedTokens {
+			s.revokedTokens[digest] = true
+		}
+	}
+	s.authorityPath = path
+	s.mu.Unlock()
+
+	return s.persistAuthority()
+}
+
+// holdRevoked adds digests to the revoked-token set; s.mu is held
+func (s *SystemState) holdRevoked(digests []string) {
+	for _, digest := range digests {
+		s.revokedTokens[digest] = true
+	}
+}
+
+// persistRevocation writes a revocation to the authority store, degrading
+// integrity if it cannot
+func (s *SystemState) persistRevocation() error {
+	if err := s.persistAuthority(); err != nil {
+		s.SetIntegrityState(IntegrityDegraded)
+		return fmt.Errorf("revocation not persisted: %w", err)
+	}
+	return nil
+}
+
+// persistAuthority writes the authority capsule and the revoked-token set
+// to the authority store, if one is attached
+func (s *SystemState) persistAuthority() error {
+	s.authorityMu.Lock()
+	defer s.authorityMu.Unlock()
+
+	s.mu.RLock()
+	path := s.authorityPath
+	record := authorityRecord{
+		Version:       AuthorityVersion,
+		Authority:     copyAuthority(s.AuthorityCapsule),
+		RevokedTokens: make([]string, 0, len(s.revokedTokens)),
+	}
+	for digest := range s.revokedTokens {
+		record.RevokedTokens = append(record.RevokedTokens, digest)
+	}
+	s.mu.RUnlock()
+	if path == "" {
+		return nil
+	}
+
+	sort.Strings(record.RevokedTokens)
+	hash, err := record.hash()
+	if err != nil {
+		return err
+	}
+	record.Hash = hash
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// readAuthority reads the authority file at path, or nil if there is none
+func readAuthority(path string) (*authorityRecord, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("authority store: %w", err)
+	}
+	var record authorityRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("authority store %s unreadable: %w", path, err)
+	}
+	if record.Version != AuthorityVersion {
+		return nil, fmt.Errorf("authority store %s version %d is not supported", path, record.Version)
+	}
+	hash, err := record.hash()
+	if err != nil || hash != record.Hash {
+		return nil, fmt.Errorf("authority store %s hash mismatch", path)
+	}
+	if record.Authority.ActiveConsents == nil {
+		record.Authority.ActiveConsents = make(map[string]Consent)
+	}
+	return &record, nil
+}
+
+// copyAuthority returns a copy of authority that shares nothing with it
+func copyAuthority(authority AuthorityCapsule) AuthorityCapsule {
+	consents := make(map[string]Consent, len(authority.ActiveConsents))
+	for scope, consent := range authority.ActiveConsents {
+		consents[scope] = consent
+	}
+	var halts map[string]Halt
+	if len(authority.Halts) > 0 {
+		halts = make(map[string]Halt, len(authority.Halts))
+		for key, halt := range authority.Halts {
+			halts[key] = halt
+		}
+	}
+	return AuthorityCapsule{
+		ActiveConsents: consents,
+		Revocations:    append([]Revocation{}, authority.Revocations...),
+		Halts:          halts,
+	}
+}
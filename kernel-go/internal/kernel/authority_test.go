@@ -0,0 +1,110 @@
+// WHY: Proves a restart comes back with the consents and token
+// revocations the user left, never with authority they withdrew, and that
+// a damaged authority store is refused rather than ignored.
+package kernel
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+)
+
+// TestAuthoritySurvivesRestart proves a revoked consent and a revoked
+// token stay revoked in a kernel restarted on the same store
+func TestAuthoritySurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "authority.json")
+	state := NewSystemState("user_123", "namespace_abc")
+	if err := state.AttachAuthorityStore(path); err != nil {
+		t.Fatalf("attach: %v", err)
+	}
+	if err := state.GrantConsent("high_risk_operations", 0, "settings page"); err != nil {
+		t.Fatalf("grant: %v", err)
+	}
+	if err := state.GrantConsent("email", 0, "settings page"); err != nil {
+		t.Fatalf("grant: %v", err)
+	}
+	if err := state.RevokeConsent("email"); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	token, err := capabilities.Mint("kernel", "user_123", "mock_adapter", []string{"adapter:mock_adapter"}, capabilities.Limits{}, time.Minute, capabilities.PostureBounds{}, "namespace_abc", "user_123")
+	if err != nil {
+		t.Fatalf("mint: %v", err)
+	}
+	state.AddToken(token)
+	state.RevokeAllTokens()
+
+	restarted := NewSystemState("user_123", "namespace_abc")
+	restarted.GrantConsent("email", 0, "stale default")
+	if err := restarted.AttachAuthorityStore(path); err != nil {
+		t.Fatalf("reattach: %v", err)
+	}
+	consents := restarted.activeConsents()
+	if !consents["high_risk_operations"] || consents["email"] {
+		t.Fatalf("consents must be as the user left them, got %v", consents)
+	}
+	if revocations := restarted.AuthorityCapsule.Revocations; len(revocations) != 1 || revocations[0].Scope != "email" {
+		t.Fatalf("the revocation must be kept, got %+v", revocations)
+	}
+	if !restarted.TokenRevoked(token.Digest) {
+		t.Fatal("a revoked token must stay revoked across a restart")
+	}
+}
+
+// TestAuthorityStoreRefusesDamage proves a tampered authority store is not
+// attached
+func TestAuthorityStoreRefusesDamage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "authority.json")
+	state := NewSystemState("user_123", "namespace_abc")
+	if err := state.AttachAuthorityStore(path); err != nil {
+		t.Fatalf("attach: %v", err)
+	}
+	if err := state.RevokeConsent("email"); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	for name, damaged := range map[string][]byte{
+		"truncated": data[:len(data)/2],
+		"tampered":  []byte(strings.Replace(string(data), `"email"`, `"phone"`, 1)),
+	} {
+		if err := os.WriteFile(path, damaged, 0o600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if err := NewSystemState("user_123", "namespace_abc").AttachAuthorityStore(path); err == nil {
+			t.Fatalf("%s: a damaged authority store must be refused", name)
+		}
+	}
+}
+
+// TestRevocationOutlivesAFailedWrite proves a revocation that cannot be
+// written still takes effect and degrades integrity
+func TestRevocationOutlivesAFailedWrite(t *testing.T) {
+	dir := t.TempDir()
+	state := NewSystemState("user_123", "namespace_abc")
+	if err := state.AttachAuthorityStore(filepath.Join(dir, "authority.json")); err != nil {
+		t.Fatalf("attach: %v", err)
+	}
+	if err := state.GrantConsent("email", 0, "settings page"); err != nil {
+		t.Fatalf("grant: %v", err)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+
+	if err := state.RevokeConsent("email"); err == nil {
+		t.Fatal("an unwritten revocation must be reported")
+	}
+	if state.activeConsents()["email"] {
+		t.Fatal("the revocation must take effect even if it is not written")
+	}
+	if state.GetIntegrityState() != IntegrityDegraded {
+		t.Fatalf("an unwritten revocation must degrade integrity, got %s", state.GetIntegrityState())
+	}
+	if err := state.GrantConsent("high_risk_operations", 0, "settings page"); err == nil || state.activeConsents()["high_risk_operations"] {
+		t.Fatal("a consent that cannot be written must not be granted")
+	}
+}
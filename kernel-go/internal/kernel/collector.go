@@ -0,0 +1,77 @@
+// WHY: Memory garbage collection removes content without a caller asking,
+// so it must leave the same kind of trail a deletion does. The collector
+// runs CollectGarbage on a schedule and ledgers one memory_collection
+// receipt per partition it removed from, carrying counts, bytes, and the
+// digest of what went, never the entries themselves.
+package kernel
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/memory"
+)
+
+// DefaultCollectInterval is how often the background collector runs
+const DefaultCollectInterval = 10 * time.Minute
+
+// MemoryCollector runs CollectMemory on a fixed interval until stopped
+type MemoryCollector struct {
+	state    *SystemState
+	interval time.Duration
+	policy   memory.GCPolicy
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// StartMemoryCollector starts a background collector for the kernel's memory
+func (s *SystemState) StartMemoryCollector(interval time.Duration, policy memory.GCPolicy) (*MemoryCollector, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("collect interval must be positive, got %s", interval)
+	}
+
+	c := &MemoryCollector{
+		state:    s,
+		interval: interval,
+		policy:   policy,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go c.run()
+	return c, nil
+}
+
+func (c *MemoryCollector) run() {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.state.CollectMemory(c.policy)
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// Stop halts the collector and waits for an in-flight collection to finish
+func (c *MemoryCollector) Stop() {
+	c.stopOnce.Do(func() { close(c.stop) })
+	<-c.done
+}
+
+// CollectMemory runs one garbage collection over every namespace and
+// ledgers a summary receipt for each partition it removed from
+func (s *SystemState) CollectMemory(policy memory.GCPolicy) []memory.GCReport {
+	reports := s.MemoryManager.CollectGarbage(policy)
+	for _, report := range reports {
+		s.AuditLedger.AppendMemoryCollection(s.attribution(""), report.Partition, report.Removed, report.Bytes, report.Digest)
+	}
+	return reports
+}
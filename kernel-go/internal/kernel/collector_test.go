@@ -0,0 +1,77 @@
+// WHY: Proves memory garbage collection is ledgered as summaries, and the
+// scheduled collector runs without a caller.
+package kernel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/clock"
+	"github.com/user/oi/kernel-go/internal/memory"
+)
+
+// TestMemoryCollectionIsLedgeredAsSummaries proves one receipt per
+// partition records what was removed, without naming the entries
+func TestMemoryCollectionIsLedgeredAsSummaries(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	now := clock.NewFake(time.Now())
+	state.MemoryManager.SetClock(now)
+	state.MemoryManager.Write(memory.PartitionEphemeral, "draft_1", "one", nil)
+	state.MemoryManager.Write(memory.PartitionEphemeral, "draft_2", "two", nil)
+	state.MemoryManager.Write(memory.PartitionDurable, "note", "forget me", nil)
+	state.MemoryManager.Delete(memory.PartitionDurable, "note")
+	now.Advance(2 * time.Hour)
+
+	reports := state.CollectMemory(memory.GCPolicy{EphemeralMaxAge: time.Hour, TombstoneMaxAge: time.Hour})
+	if len(reports) != 2 {
+		t.Fatalf("expected ephemeral and durable summaries, got %+v", reports)
+	}
+	collected, err := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"memory_collection"}})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(collected.Receipts) != 2 {
+		t.Fatalf("expected one receipt per partition, got %d", len(collected.Receipts))
+	}
+	ephemeral := collected.Receipts[0].EventData
+	if ephemeral["partition"] != memory.PartitionEphemeral || ephemeral["removed"] != 2 || ephemeral["removed_digest"] != reports[0].Digest {
+		t.Fatalf("the summary must carry the count and digest, got %v", ephemeral)
+	}
+	if _, named := ephemeral["entry_id"]; named {
+		t.Fatal("a collection summary must not name the entries it removed")
+	}
+}
+
+// TestMemoryCollectorRunsInBackground proves the scheduled collector
+// removes aged entries without a caller
+func TestMemoryCollectorRunsInBackground(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	if _, err := state.StartMemoryCollector(0, memory.GCPolicy{}); err == nil {
+		t.Fatal("non-positive interval should be rejected")
+	}
+
+	now := clock.NewFake(time.Now())
+	state.MemoryManager.SetClock(now)
+	state.MemoryManager.Write(memory.PartitionEphemeral, "draft", "scratch", nil)
+	now.Advance(2 * time.Hour)
+	collector, err := state.StartMemoryCollector(5*time.Millisecond, memory.GCPolicy{EphemeralMaxAge: time.Hour})
+	if err != nil {
+		t.Fatalf("start collector: %v", err)
+	}
+	defer collector.Stop()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := state.MemoryManager.Read(memory.PartitionEphemeral, "draft"); err != nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("background collector never removed the aged entry")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	collector.Stop()
+	collector.Stop() // idempotent
+}
@@ -0,0 +1,117 @@
+// WHY: Consents were a bare map callers wrote into, so nothing recorded
+// who consented, on what evidence, or for how long, and a consent given
+// once held forever. GrantConsent and RevokeConsent are now the only way
+// to change them: each grant carries its evidence and an optional expiry,
+// each change is ledgered, and CDI treats a consent past its expiry as
+// never given.
+package kernel
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Consent is the kernel principal's consent to one scope
+type Consent struct {
+	Scope     string
+	GrantedAt int64
+
+	// ExpiresAt is the Unix time the consent lapses at; zero means it
+	// holds until revoked
+	ExpiresAt int64
+
+	// Evidence records how the consent was given
+	Evidence string
+}
+
+// activeAt reports whether the consent holds at the Unix time now
+func (c Consent) activeAt(now int64) bool {
+	return c.ExpiresAt == 0 || now < c.ExpiresAt
+}
+
+// GrantConsent records the kernel principal's consent to scope for ttl,
+// or until revoked if ttl is zero, once it is on disk. Evidence says how
+// the consent was given and is required.
+func (s *SystemState) GrantConsent(scope string, ttl time.Duration, evidence string) error {
+	if scope == "" || evidence == "" {
+		return fmt.Errorf("a consent needs a scope and evidence")
+	}
+	if ttl < 0 {
+		return fmt.Errorf("consent %s: ttl must not be negative", scope)
+	}
+
+	s.mu.Lock()
+	now := s.nowLocked()
+	consent := Consent{Scope: scope, GrantedAt: now.Unix(), Evidence: evidence}
+	if ttl > 0 {
+		consent.ExpiresAt = now.Add(ttl).Unix()
+	}
+	previous, granted := s.AuthorityCapsule.ActiveConsents[scope]
+	s.AuthorityCapsule.ActiveConsents[scope] = consent
+	s.mu.Unlock()
+
+	if err := s.persistAuthority(); err != nil {
+		s.mu.Lock()
+		if granted {
+			s.AuthorityCapsule.ActiveConsents[scope] = previous
+		} else {
+			delete(s.AuthorityCapsule.ActiveConsents, scope)
+		}
+		s.mu.Unlock()
+		return fmt.Errorf("consent %s not granted: %w", scope, err)
+	}
+	evidenceHash := sha256.Sum256([]byte(evidence))
+	s.AuditLedger.AppendConsentGrant(s.attribution(""), scope, consent.ExpiresAt, hex.EncodeToString(evidenceHash[:]))
+	return nil
+}
+
+// RevokeConsent withdraws the kernel principal's consent to scope and
+// records the revocation. WHY: A revocation takes effect at once even if
+// it cannot be written; the error says it will not survive a restart, and
+// integrity is degraded until it can be.
+func (s *SystemState) RevokeConsent(scope string) error {
+	s.mu.Lock()
+	delete(s.AuthorityCapsule.ActiveConsents, scope)
+	s.AuthorityCapsule.Revocations = append(s.AuthorityCapsule.Revocations, Revocation{
+		Timestamp: s.nowLocked().Unix(),
+		Scope:     scope,
+	})
+	s.mu.Unlock()
+
+	s.AuditLedger.AppendConsentRevoke(s.attribution(""), scope)
+	return s.persistRevocation()
+}
+
+// ListConsents returns the unexpired consents, sorted by scope
+func (s *SystemState) ListConsents() []Consent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	now := s.nowLocked().Unix()
+	consents := make([]Consent, 0, len(s.AuthorityCapsule.ActiveConsents))
+	for _, consent := range s.AuthorityCapsule.ActiveConsents {
+		if consent.activeAt(now) {
+			consents = append(consents, consent)
+		}
+	}
+	sort.Slice(consents, func(i, j int) bool { return consents[i].Scope < consents[j].Scope })
+	return consents
+}
+
+// grantedConsents returns every consent granted and the expiry of those
+// that lapse, for CDI to judge at the request's time
+func (s *SystemState) grantedConsents() (map[string]bool, map[string]int64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	consents := make(map[string]bool, len(s.AuthorityCapsule.ActiveConsents))
+	expiry := make(map[string]int64)
+	for scope, consent := range s.AuthorityCapsule.ActiveConsents {
+		consents[scope] = true
+		if consent.ExpiresAt != 0 {
+			expiry[scope] = consent.ExpiresAt
+		}
+	}
+	return consents, expiry
+}
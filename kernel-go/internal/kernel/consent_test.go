@@ -0,0 +1,76 @@
+// WHY: Proves consents change only through the API, that each change is
+// ledgered with its evidence hashed, and that a consent lapses at its
+// expiry without anyone revoking it.
+package kernel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/clock"
+)
+
+// TestConsentsExpire proves a consent granted with a ttl is listed until
+// it lapses and not after
+func TestConsentsExpire(t *testing.T) {
+	state := NewSystemState("user_123", "namespace_abc")
+	now := clock.NewFake(time.Unix(1_700_000_000, 0))
+	state.SetClock(now)
+
+	if err := state.GrantConsent("email", time.Hour, ""); err == nil {
+		t.Fatal("a consent without evidence must be refused")
+	}
+	if err := state.GrantConsent("email", -time.Hour, "settings page"); err == nil {
+		t.Fatal("a consent with a negative ttl must be refused")
+	}
+	if err := state.GrantConsent("email", time.Hour, "settings page"); err != nil {
+		t.Fatalf("grant: %v", err)
+	}
+	if err := state.GrantConsent("high_risk_operations", 0, "settings page"); err != nil {
+		t.Fatalf("grant: %v", err)
+	}
+	if consents := state.ListConsents(); len(consents) != 2 || consents[0].Scope != "email" {
+		t.Fatalf("both consents must be listed, got %+v", consents)
+	}
+
+	now.Advance(time.Hour)
+	consents := state.ListConsents()
+	if len(consents) != 1 || consents[0].Scope != "high_risk_operations" {
+		t.Fatalf("an expired consent must not be listed, got %+v", consents)
+	}
+	if state.activeConsents()["email"] {
+		t.Fatal("an expired consent must not be active")
+	}
+	granted, expiry := state.grantedConsents()
+	if !granted["email"] || expiry["email"] != now.Now().Unix() {
+		t.Fatalf("CDI must see the consent with its expiry, got %v %v", granted, expiry)
+	}
+}
+
+// TestConsentChangesAreLedgered proves grants and revocations leave
+// receipts, with the evidence only as a hash
+func TestConsentChangesAreLedgered(t *testing.T) {
+	state := NewSystemState("user_123", "namespace_abc")
+	if err := state.GrantConsent("email", 0, "clicked allow"); err != nil {
+		t.Fatalf("grant: %v", err)
+	}
+	if err := state.RevokeConsent("email"); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+
+	page, err := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"consent_change"}})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(page.Receipts) != 2 {
+		t.Fatalf("expected a receipt per change, got %d", len(page.Receipts))
+	}
+	grant, revoke := page.Receipts[0].EventData, page.Receipts[1].EventData
+	if grant["granted"] != true || grant["evidence_hash"] == "clicked allow" || grant["evidence_hash"] == "" {
+		t.Fatalf("the grant receipt must hash its evidence, got %v", grant)
+	}
+	if revoke["granted"] != false || revoke["scope"] != "email" {
+		t.Fatalf("the revocation must be ledgered, got %v", revoke)
+	}
+}
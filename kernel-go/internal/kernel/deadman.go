@@ -0,0 +1,138 @@
+// WHY: STOP depends on someone being there to pull it. An autonomous run
+// left unattended keeps its capability however long nobody watches it. A
+// deadman switch requires a heartbeat from an operator or user at least
+// once an interval; when the set number of beats is missed it pulls a
+// global STOP on its own and raises the posture, so capability does not
+// outlast the attention it was granted under. Like any global STOP, only
+// Resume lifts it.
+package kernel
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// Defaults for DeadmanPolicy
+const (
+	DefaultDeadmanMissedBeats = 3
+	DefaultDeadmanPosture     = posture.P4
+)
+
+// DeadmanPolicy sets how often heartbeats are owed and what follows when
+// they stop
+type DeadmanPolicy struct {
+	// Interval is how often a heartbeat is owed
+	Interval time.Duration
+
+	// MissedBeats is how many intervals may pass without a heartbeat
+	// before the switch trips (default DefaultDeadmanMissedBeats)
+	MissedBeats int
+
+	// Posture is the level the switch raises the posture to when it
+	// trips (default DefaultDeadmanPosture)
+	Posture int
+}
+
+// DeadmanSwitch pulls STOP when heartbeats stop arriving
+type DeadmanSwitch struct {
+	state  *SystemState
+	policy DeadmanPolicy
+
+	mu       sync.Mutex
+	lastBeat time.Time
+	from     string
+	tripped  bool
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// StartDeadmanSwitch arms a deadman switch; the first heartbeat is owed
+// one interval from now
+func (s *SystemState) StartDeadmanSwitch(policy DeadmanPolicy) (*DeadmanSwitch, error) {
+	if policy.Interval <= 0 {
+		return nil, fmt.Errorf("heartbeat interval must be positive, got %s", policy.Interval)
+	}
+	if policy.MissedBeats < 0 {
+		return nil, fmt.Errorf("missed heartbeats must not be negative")
+	}
+	if policy.MissedBeats == 0 {
+		policy.MissedBeats = DefaultDeadmanMissedBeats
+	}
+	if policy.Posture == 0 {
+		policy.Posture = DefaultDeadmanPosture
+	}
+	if !posture.IsValid(policy.Posture) {
+		return nil, fmt.Errorf("posture %d is not defined", policy.Posture)
+	}
+
+	d := &DeadmanSwitch{
+		state:    s,
+		policy:   policy,
+		lastBeat: s.now(),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go d.run()
+	return d, nil
+}
+
+// Heartbeat records that from is still attending; it re-arms a switch
+// that has tripped but lifts nothing
+func (d *DeadmanSwitch) Heartbeat(from string) {
+	now := d.state.now()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.lastBeat = now
+	d.from = from
+	d.tripped = false
+}
+
+// Check trips the switch if the heartbeats owed have been missed, and
+// reports whether it did
+func (d *DeadmanSwitch) Check() bool {
+	now := d.state.now()
+	d.mu.Lock()
+	silence := now.Sub(d.lastBeat)
+	if d.tripped || silence < time.Duration(d.policy.MissedBeats)*d.policy.Interval {
+		d.mu.Unlock()
+		return false
+	}
+	d.tripped = true
+	lastBeat, from := d.lastBeat, d.from
+	d.mu.Unlock()
+
+	missed := int(silence / d.policy.Interval)
+	s := d.state
+	s.AuditLedger.AppendDeadmanTrip(s.attribution(""), from, lastBeat.Unix(), missed)
+	s.Posture.Raise(d.policy.Posture, fmt.Sprintf("deadman: %d heartbeats missed", missed))
+	s.revokeAllTokens(audit.StopReasonHeartbeatLost)
+	return true
+}
+
+// Stop disarms the switch
+func (d *DeadmanSwitch) Stop() {
+	d.stopOnce.Do(func() { close(d.stop) })
+	<-d.done
+}
+
+func (d *DeadmanSwitch) run() {
+	defer close(d.done)
+
+	ticker := time.NewTicker(d.policy.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			d.Check()
+		case <-d.stop:
+			return
+		}
+	}
+}
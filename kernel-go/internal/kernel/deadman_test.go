@@ -0,0 +1,72 @@
+// WHY: Proves the deadman switch lets an attended run be and STOPs an
+// unattended one once the heartbeats owed are missed, raising the posture
+// and halting minting until a Resume.
+package kernel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/clock"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// TestDeadmanSwitchStopsOnMissedHeartbeats proves the switch trips only
+// after the set number of heartbeats is missed, and trips once
+func TestDeadmanSwitchStopsOnMissedHeartbeats(t *testing.T) {
+	state := newSessionKernel(t)
+	now := clock.NewFake(time.Now())
+	state.SetClock(now)
+
+	if _, err := state.StartDeadmanSwitch(DeadmanPolicy{}); err == nil {
+		t.Fatal("a deadman switch without an interval must be refused")
+	}
+	deadman, err := state.StartDeadmanSwitch(DeadmanPolicy{Interval: time.Hour})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	defer deadman.Stop()
+
+	if resp, err := Execute(&Request{RawInput: "test request"}, state); err != nil || !resp.Success {
+		t.Fatalf("request before the trip: %+v (%v)", resp, err)
+	}
+	now.Advance(2 * time.Hour)
+	deadman.Heartbeat("operator")
+	now.Advance(2 * time.Hour)
+	if deadman.Check() {
+		t.Fatal("the switch must not trip while heartbeats arrive")
+	}
+
+	now.Advance(time.Hour)
+	if !deadman.Check() {
+		t.Fatal("the switch must trip once three heartbeats are missed")
+	}
+	if deadman.Check() {
+		t.Fatal("a tripped switch must not trip again until re-armed")
+	}
+	if state.Posture.Level() != posture.P4 {
+		t.Fatalf("the trip must raise the posture, got P%d", state.Posture.Level())
+	}
+	if resp, err := Execute(&Request{RawInput: "test request"}, state); err == nil || resp.Success {
+		t.Fatal("no token may be minted after the switch trips")
+	}
+	halts := state.Halts()
+	if len(halts) != 1 || halts[0].Reason != audit.StopReasonHeartbeatLost {
+		t.Fatalf("the trip must be held as a global STOP, got %+v", halts)
+	}
+
+	trips, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"deadman_trip"}})
+	if len(trips.Receipts) != 1 || trips.Receipts[0].EventData["last_heartbeat_from"] != "operator" || trips.Receipts[0].EventData["missed_heartbeats"] != 3 {
+		t.Fatalf("the trip must be ledgered with the last heartbeat, got %+v", trips.Receipts)
+	}
+	stops, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"stop_event"}})
+	if len(stops.Receipts) != 1 || stops.Receipts[0].EventData["stop_reason"] != audit.StopReasonHeartbeatLost {
+		t.Fatalf("the STOP must record the lost heartbeat, got %+v", stops.Receipts)
+	}
+
+	deadman.Heartbeat("operator")
+	if len(state.Halts()) != 1 {
+		t.Fatal("a heartbeat must not lift the STOP")
+	}
+}
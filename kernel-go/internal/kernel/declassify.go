@@ -0,0 +1,120 @@
+// WHY: The declassification ledger was declared but nothing wrote to it,
+// so posture redaction could not be lifted at all except by lowering the
+// posture for everything. Declassify lifts it for one piece of content,
+// named by hash, on the word of an approver holding a live token scoped to
+// declassify; every attempt is ledgered, and egress lifts nothing the
+// ledger does not hold.
+package kernel
+
+import (
+	"fmt"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/capabilities"
+)
+
+// ScopeDeclassify is the token scope an approver declassifies with
+const ScopeDeclassify = "kernel:declassify"
+
+// ReasonDeclassified is the egress decision reason for content let out
+// unredacted because it was declassified
+const ReasonDeclassified = "declassified"
+
+// Declassify lifts the posture redaction of content with contentHash, for
+// reason. approverToken must be a live token the kernel minted, scoped to
+// ScopeDeclassify; the principal the kernel minted it to is recorded as
+// the approver.
+func (s *SystemState) Declassify(contentHash string, reason string, approverToken *capabilities.Token) error {
+	actor := s.tokenActor(approverToken)
+	digest := ""
+	if approverToken != nil {
+		digest = approverToken.Digest
+	}
+	approver, err := s.checkDeclassification(contentHash, reason, approverToken)
+	if err != nil {
+		s.AuditLedger.AppendDeclassification(actor, contentHash, reason, digest, false)
+		return fmt.Errorf("declassification refused: %w", err)
+	}
+
+	s.mu.Lock()
+	s.DeclassificationLedger.Entries = append(s.DeclassificationLedger.Entries, DeclassificationEntry{
+		Timestamp:   s.nowLocked().Unix(),
+		ContentHash: contentHash,
+		Reason:      reason,
+		Approver:    approver.PrincipalID,
+		TokenDigest: approver.Digest,
+	})
+	s.mu.Unlock()
+
+	s.AuditLedger.AppendDeclassification(actor, contentHash, reason, approver.Digest, true)
+	return nil
+}
+
+// checkDeclassification returns the kernel's record of approverToken if
+// it may declassify contentHash
+func (s *SystemState) checkDeclassification(contentHash string, reason string, approverToken *capabilities.Token) (*capabilities.Token, error) {
+	if contentHash == "" || reason == "" {
+		return nil, fmt.Errorf("a declassification needs a content hash and a reason")
+	}
+	if approverToken == nil {
+		return nil, fmt.Errorf("a declassification needs an approver token")
+	}
+	return s.heldToken(approverToken, ScopeDeclassify)
+}
+
+// tokenActor attributes an attempt made with token to the principal and
+// namespace of the kernel's record of it, or to the kernel itself if it
+// holds no such token. WHY: The caller's copy is unverified, so
+// attributing to it would let anyone ledger an attempt, or an approval,
+// in another principal's name.
+func (s *SystemState) tokenActor(token *capabilities.Token) audit.Attribution {
+	actor := s.attribution("")
+	if token == nil {
+		return actor
+	}
+	s.mu.RLock()
+	held, minted := s.ActiveCapabilityTokens[token.Digest]
+	s.mu.RUnlock()
+	if minted {
+		actor.PrincipalID, actor.NamespaceID = held.PrincipalID, held.NamespaceID
+	}
+	return actor
+}
+
+// heldToken returns the kernel's own record of token if it is live and
+// grants scope. WHY: The kernel's record is what is checked, so a token
+// it did not mint, or one altered since, has no authority.
+func (s *SystemState) heldToken(token *capabilities.Token, scope string) (*capabilities.Token, error) {
+	if token == nil {
+		return nil, fmt.Errorf("a capability token is required")
+	}
+	s.mu.RLock()
+	held, minted := s.ActiveCapabilityTokens[token.Digest]
+	s.mu.RUnlock()
+	if !minted {
+		return nil, fmt.Errorf("token %s is not one the kernel holds", token.Digest)
+	}
+	if s.TokenRevoked(held.Digest) {
+		return nil, fmt.Errorf("token %s is revoked", held.Digest)
+	}
+	if _, err := held.Verify(s.PostureLevelFor(held.NamespaceID)); err != nil {
+		return nil, fmt.Errorf("token %s: %w", held.Digest, err)
+	}
+	if !held.HasScope(scope) {
+		return nil, fmt.Errorf("token %s is not scoped to %s", held.Digest, scope)
+	}
+	return held, nil
+}
+
+// declassified reports whether content with contentHash has been
+// declassified
+func (s *SystemState) declassified(contentHash string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, entry := range s.DeclassificationLedger.Entries {
+		if entry.ContentHash == contentHash {
+			return true
+		}
+	}
+	return false
+}
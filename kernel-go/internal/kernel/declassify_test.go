@@ -0,0 +1,94 @@
+// WHY: Proves a declassification lifts the posture redaction of exactly
+// the content it names, only on a live approver token the kernel minted,
+// and that every attempt leaves a receipt.
+package kernel
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/adapters"
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// mintApprover mints and registers a token for approver with scope
+func mintApprover(t *testing.T, state *SystemState, approver string, scope string) *capabilities.Token {
+	t.Helper()
+	token, err := capabilities.Mint("kernel", approver, "kernel", []string{scope}, capabilities.Limits{}, time.Minute,
+		capabilities.PostureBounds{MinPosture: posture.P1, MaxPosture: posture.P4}, "test_namespace", approver)
+	if err != nil {
+		t.Fatalf("mint: %v", err)
+	}
+	state.AddToken(token)
+	return token
+}
+
+// TestDeclassificationLiftsPostureRedaction proves declassified content
+// passes egress unredacted and is ledgered as such, under the approver the
+// kernel minted the token to whatever the caller's copy says
+func TestDeclassificationLiftsPostureRedaction(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	state.AdapterRegistry.Register(adapters.NewMockAdapter("mock_adapter"))
+	state.GovernanceCapsule.Rules = map[string]interface{}{"exists": true}
+	state.Posture.Raise(posture.P3, "incident")
+
+	request := func() *Response {
+		metadata := map[string]interface{}{"sensitivity": posture.SensitivityMedium}
+		resp, err := Execute(&Request{RawInput: "test request", Metadata: metadata}, state)
+		if err != nil || !resp.Success {
+			t.Fatalf("request: %+v (%v)", resp, err)
+		}
+		return resp
+	}
+	if resp := request(); !strings.Contains(resp.Content, "REDACTED") {
+		t.Fatalf("medium output at P3 must be redacted, got %q", resp.Content)
+	}
+	page, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"egress_decision"}})
+	outputHash, _ := page.Receipts[len(page.Receipts)-1].EventData["output_hash"].(string)
+
+	unscoped := mintApprover(t, state, "approver", "mock_adapter")
+	if err := state.Declassify(outputHash, "user asked", unscoped); err == nil {
+		t.Fatal("a token not scoped to declassify must be refused")
+	}
+	forged := *unscoped
+	forged.Scope = []string{ScopeDeclassify}
+	forged.Digest = "forged"
+	if err := state.Declassify(outputHash, "user asked", &forged); err == nil {
+		t.Fatal("a token the kernel did not mint must be refused")
+	}
+	approver := mintApprover(t, state, "approver", ScopeDeclassify)
+	if err := state.Declassify(outputHash, "", approver); err == nil {
+		t.Fatal("a declassification without a reason must be refused")
+	}
+	altered := *approver
+	altered.PrincipalID, altered.NamespaceID = "someone_else", "tenant_b"
+	if err := state.Declassify(outputHash, "user asked", &altered); err != nil {
+		t.Fatalf("declassify: %v", err)
+	}
+
+	if resp := request(); strings.Contains(resp.Content, "REDACTED") {
+		t.Fatalf("declassified output must not be redacted, got %q", resp.Content)
+	}
+	if entries := state.DeclassificationLedger.Entries; len(entries) != 1 || entries[0].Approver != "approver" {
+		t.Fatalf("the declassification must be recorded with its approver, got %+v", entries)
+	}
+	page, _ = state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"declassification"}})
+	if len(page.Receipts) != 4 || page.Receipts[3].EventData["accepted"] != true {
+		t.Fatalf("every declassification attempt must be ledgered, got %d", len(page.Receipts))
+	}
+	if data := page.Receipts[3].EventData; data["principal_id"] != "approver" || data["namespace_id"] != "test_namespace" {
+		t.Fatalf("the approval must be ledgered as the kernel's record of the token, got %v", data)
+	}
+	page, _ = state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"egress_decision"}})
+	if last := page.Receipts[len(page.Receipts)-1].EventData; last["reason"] != ReasonDeclassified {
+		t.Fatalf("egress must record the declassification it applied, got %v", last)
+	}
+
+	state.RevokeAllTokens()
+	if err := state.Declassify("other", "user asked", approver); err == nil {
+		t.Fatal("a revoked approver token must be refused")
+	}
+}
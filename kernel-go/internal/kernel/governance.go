@@ -0,0 +1,202 @@
+// WHY: Governance was whatever the embedding program assigned to the
+// capsule, so anyone able to change that program could change policy
+// unseen. A governance bundle carries the policy with a signature and the
+// public key that made it; the kernel accepts it only if that key's
+// fingerprint matches the one pinned in its commitments and the signature
+// holds, and a bundle that fails degrades integrity rather than being
+// applied in part. Whatever governance is in force is committed to the
+// ledger by hash, every decision records the hash it was made under, and
+// CDI denies once the capsule in memory no longer matches the commitment.
+package kernel
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+// CommitmentGovernanceKey is the commitment that pins the governance
+// signing key by its fingerprint (signing.Fingerprint)
+const CommitmentGovernanceKey = "governance_key"
+
+// What committed the governance in force
+const (
+	GovernanceFromFirstUse = "first_use"
+	GovernanceFromBundle   = "bundle"
+	GovernanceFromCommit   = "commit"
+)
+
+// boundGovernance is the governance committed, with the hash of the
+// capsule in force
+type boundGovernance struct {
+	audit.Governance
+	live string
+}
+
+// GovernanceBundle is a signed governance policy
+type GovernanceBundle struct {
+	KeyID string
+
+	// PublicKey is the PEM PKIX key the policy was signed with
+	PublicKey string
+
+	// Policy is the governance capsule, encoded; fields it leaves out keep
+	// their defaults
+	Policy json.RawMessage
+
+	// Signature is the signature over Policy
+	Signature []byte
+}
+
+// SignGovernance encodes capsule, less its commitments, as a governance
+// bundle signed by signer
+func SignGovernance(capsule GovernanceCapsule, signer signing.Signer) (*GovernanceBundle, error) {
+	capsule.Commitments = nil
+	policy, err := json.Marshal(capsule)
+	if err != nil {
+		return nil, fmt.Errorf("encode governance policy: %w", err)
+	}
+	public, err := signing.EncodePublicKey(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	signature, err := signer.Sign(policy)
+	if err != nil {
+		return nil, fmt.Errorf("sign governance policy: %w", err)
+	}
+	return &GovernanceBundle{KeyID: signer.KeyID(), PublicKey: string(public), Policy: policy, Signature: signature}, nil
+}
+
+// LoadGovernanceBundle applies the governance bundle in the file at path
+// (see ApplyGovernanceBundle)
+func (s *SystemState) LoadGovernanceBundle(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		s.SetIntegrityState(IntegrityDegraded)
+		return fmt.Errorf("governance bundle: %w", err)
+	}
+	return s.ApplyGovernanceBundle(data)
+}
+
+// ApplyGovernanceBundle replaces the governance capsule with the policy of
+// an encoded GovernanceBundle, once its key matches the pinned fingerprint
+// and its signature and policy check out. The commitments are kept: a
+// bundle cannot move its own pin. A bundle that fails degrades integrity
+// and changes nothing else.
+func (s *SystemState) ApplyGovernanceBundle(data []byte) error {
+	capsule, err := s.verifyGovernanceBundle(data)
+	if err != nil {
+		s.SetIntegrityState(IntegrityDegraded)
+		return fmt.Errorf("governance bundle refused: %w", err)
+	}
+
+	s.mu.Lock()
+	capsule.Commitments = s.GovernanceCapsule.Commitments
+	s.GovernanceCapsule = capsule
+	s.mu.Unlock()
+	return s.commitGovernance(GovernanceFromBundle)
+}
+
+// verifyGovernanceBundle returns the governance capsule an encoded bundle
+// carries, if its key is the pinned one and its signature and policy hold
+func (s *SystemState) verifyGovernanceBundle(data []byte) (GovernanceCapsule, error) {
+	var bundle GovernanceBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return GovernanceCapsule{}, fmt.Errorf("unreadable: %w", err)
+	}
+	public, err := signing.ParsePublicKey([]byte(bundle.PublicKey))
+	if err != nil {
+		return GovernanceCapsule{}, err
+	}
+	fingerprint, err := signing.Fingerprint(public)
+	if err != nil {
+		return GovernanceCapsule{}, err
+	}
+	s.mu.RLock()
+	pinned := s.GovernanceCapsule.Commitments[CommitmentGovernanceKey]
+	s.mu.RUnlock()
+	if pinned == "" {
+		return GovernanceCapsule{}, fmt.Errorf("no governance key is pinned in commitment %s", CommitmentGovernanceKey)
+	}
+	if fingerprint != pinned {
+		return GovernanceCapsule{}, fmt.Errorf("key %s is not the pinned governance key", bundle.KeyID)
+	}
+	if err := signing.Verify(public, bundle.Policy, bundle.Signature); err != nil {
+		return GovernanceCapsule{}, err
+	}
+
+	capsule := DefaultGovernanceCapsule()
+	if err := json.Unmarshal(bundle.Policy, &capsule); err != nil {
+		return GovernanceCapsule{}, fmt.Errorf("policy unreadable: %w", err)
+	}
+	if len(capsule.Commitments) > 0 {
+		return GovernanceCapsule{}, fmt.Errorf("a governance bundle may not set commitments")
+	}
+	if err := capsule.PosturePolicy.Validate(); err != nil {
+		return GovernanceCapsule{}, err
+	}
+	if err := capsule.PostureSchedule.Validate(); err != nil {
+		return GovernanceCapsule{}, err
+	}
+	if err := capsule.PostureDowngrade.Validate(); err != nil {
+		return GovernanceCapsule{}, err
+	}
+	return capsule, nil
+}
+
+// Hash returns the hex SHA-256 of the capsule's encoding
+func (g *GovernanceCapsule) Hash() (string, error) {
+	data, err := json.Marshal(g)
+	if err != nil {
+		return "", fmt.Errorf("encode governance capsule: %w", err)
+	}
+	return hashHex(data), nil
+}
+
+// CommitGovernance commits the governance capsule in force as the one
+// decisions are held to. WHY: A program that changes the capsule in
+// process says so here; a change it does not commit reads as tampering.
+func (s *SystemState) CommitGovernance() error {
+	return s.commitGovernance(GovernanceFromCommit)
+}
+
+// commitGovernance commits the capsule in force and ledgers what did
+func (s *SystemState) commitGovernance(source string) error {
+	s.mu.Lock()
+	hash, err := s.GovernanceCapsule.Hash()
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	s.governance = audit.Governance{CapsuleHash: hash, PolicyVersion: s.GovernanceCapsule.PolicyVersion}
+	governance := s.governance
+	s.mu.Unlock()
+
+	s.AuditLedger.AppendGovernanceCommitted(s.attribution(""), governance, source)
+	return nil
+}
+
+// governanceBinding returns the governance committed and the hash of the
+// capsule in force, committing it for actor first if nothing has been
+func (s *SystemState) governanceBinding(actor audit.Attribution) (boundGovernance, error) {
+	s.mu.Lock()
+	live, err := s.GovernanceCapsule.Hash()
+	if err != nil {
+		s.mu.Unlock()
+		return boundGovernance{}, err
+	}
+	firstUse := s.governance.CapsuleHash == ""
+	if firstUse {
+		s.governance = audit.Governance{CapsuleHash: live, PolicyVersion: s.GovernanceCapsule.PolicyVersion}
+	}
+	bound := boundGovernance{Governance: s.governance, live: live}
+	s.mu.Unlock()
+
+	if firstUse {
+		s.AuditLedger.AppendGovernanceCommitted(actor, bound.Governance, GovernanceFromFirstUse)
+	}
+	return bound, nil
+}
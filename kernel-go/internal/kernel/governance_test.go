@@ -0,0 +1,144 @@
+// WHY: Proves governance loads only from a bundle signed by the pinned
+// key, that any bundle failing that check degrades integrity without
+// touching the policy in force, and that decisions are bound to the
+// governance committed to the ledger.
+package kernel
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/user/oi/kernel-go/internal/adapters"
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+// signedGovernance returns an encoded bundle of capsule signed by a fresh
+// key, and that key's fingerprint
+func signedGovernance(t *testing.T, capsule GovernanceCapsule) ([]byte, string) {
+	t.Helper()
+	signer, err := signing.GenerateLocalSigner("governance")
+	if err != nil {
+		t.Fatalf("signer: %v", err)
+	}
+	bundle, err := SignGovernance(capsule, signer)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	data, _ := json.Marshal(bundle)
+	fingerprint, _ := signing.Fingerprint(signer.Public())
+	return data, fingerprint
+}
+
+// TestGovernanceBundleLoadsUnderThePinnedKey proves a bundle signed by the
+// pinned key replaces the policy and keeps the pin
+func TestGovernanceBundleLoadsUnderThePinnedKey(t *testing.T) {
+	capsule := DefaultGovernanceCapsule()
+	capsule.PolicyVersion = "v9"
+	capsule.Rules = map[string]interface{}{"exists": true}
+	data, fingerprint := signedGovernance(t, capsule)
+
+	state := NewSystemState("user_123", "namespace_abc")
+	state.GovernanceCapsule.Commitments[CommitmentGovernanceKey] = fingerprint
+	if err := state.ApplyGovernanceBundle(data); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if state.GovernanceCapsule.PolicyVersion != "v9" || state.GovernanceCapsule.Rules["exists"] != true {
+		t.Fatalf("the bundle's policy must be in force, got %+v", state.GovernanceCapsule)
+	}
+	if state.GovernanceCapsule.Commitments[CommitmentGovernanceKey] != fingerprint {
+		t.Fatal("the pin must survive the bundle")
+	}
+	if len(state.GovernanceCapsule.TokenTemplates) == 0 {
+		t.Fatal("fields the bundle leaves out must keep their defaults")
+	}
+	if state.GetIntegrityState() != IntegrityOK {
+		t.Fatalf("a good bundle must not degrade integrity, got %s", state.GetIntegrityState())
+	}
+}
+
+// TestGovernanceBundleRefusals proves an unpinned key, a tampered policy,
+// or a bundle setting commitments is refused and changes nothing
+func TestGovernanceBundleRefusals(t *testing.T) {
+	capsule := DefaultGovernanceCapsule()
+	capsule.PolicyVersion = "v9"
+	data, fingerprint := signedGovernance(t, capsule)
+	_, other := signedGovernance(t, capsule)
+
+	var bundle GovernanceBundle
+	json.Unmarshal(data, &bundle)
+	bundle.Policy = json.RawMessage(`{"PolicyVersion":"v10"}`)
+	tampered, _ := json.Marshal(bundle)
+
+	signer, _ := signing.GenerateLocalSigner("governance")
+	pinningBundle, _ := SignGovernance(capsule, signer)
+	pinningBundle.Policy = json.RawMessage(`{"Commitments":{"governance_key":"attacker"}}`)
+	pinningBundle.Signature, _ = signer.Sign(pinningBundle.Policy)
+	pinningData, _ := json.Marshal(pinningBundle)
+	pinningFingerprint, _ := signing.Fingerprint(signer.Public())
+
+	cases := []struct {
+		name   string
+		pin    string
+		bundle []byte
+	}{
+		{"no pin", "", data},
+		{"other key", other, data},
+		{"tampered", fingerprint, tampered},
+		{"sets commitments", pinningFingerprint, pinningData},
+		{"unreadable", fingerprint, []byte("{")},
+	}
+	for _, c := range cases {
+		state := NewSystemState("user_123", "namespace_abc")
+		if c.pin != "" {
+			state.GovernanceCapsule.Commitments[CommitmentGovernanceKey] = c.pin
+		}
+		if err := state.ApplyGovernanceBundle(c.bundle); err == nil {
+			t.Fatalf("%s: the bundle must be refused", c.name)
+		}
+		if state.GovernanceCapsule.PolicyVersion != "v1" {
+			t.Fatalf("%s: a refused bundle must not change the policy", c.name)
+		}
+		if state.GetIntegrityState() != IntegrityDegraded {
+			t.Fatalf("%s: a refused bundle must degrade integrity, got %s", c.name, state.GetIntegrityState())
+		}
+	}
+}
+
+// TestDecisionsAreBoundToCommittedGovernance proves each decision records
+// the committed capsule hash, and that an uncommitted change to the
+// capsule is denied until it is committed
+func TestDecisionsAreBoundToCommittedGovernance(t *testing.T) {
+	state := NewSystemState("user_123", "namespace_abc")
+	state.AdapterRegistry.Register(adapters.NewMockAdapter("mock_adapter"))
+	state.GovernanceCapsule.Rules = map[string]interface{}{"exists": true}
+	execute := func() *Response {
+		resp, _ := Execute(&Request{RawInput: "test request", Metadata: map[string]interface{}{}}, state)
+		return resp
+	}
+	if resp := execute(); !resp.Success {
+		t.Fatalf("request: %s", resp.Error)
+	}
+
+	committed, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"governance_committed"}})
+	decisions, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"cdi_decision"}})
+	if len(committed.Receipts) != 1 || committed.Receipts[0].EventData["source"] != GovernanceFromFirstUse {
+		t.Fatalf("the first request must commit the governance in force, got %d", len(committed.Receipts))
+	}
+	hash := committed.Receipts[0].EventData["capsule_hash"]
+	if decisions.Receipts[0].EventData["capsule_hash"] != hash || decisions.Receipts[0].EventData["policy_version"] != "v1" {
+		t.Fatalf("the decision must record the governance it was made under, got %v", decisions.Receipts[0].EventData)
+	}
+
+	state.GovernanceCapsule.Rules["exists"] = false
+	if resp := execute(); resp.Success || !strings.Contains(resp.Error, "governance_capsule_mismatch") {
+		t.Fatalf("an uncommitted change to governance must be denied, got %+v", resp)
+	}
+	if err := state.CommitGovernance(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	if resp := execute(); !resp.Success {
+		t.Fatalf("committed governance must be honoured: %s", resp.Error)
+	}
+}
@@ -0,0 +1,144 @@
+// WHY: An orchestrator routes traffic by probes, and a kernel that has
+// gone INTEGRITY_VOID, failed ledger verification, lost its committed
+// governance, or is held by a global STOP answers every request with a
+// refusal. Readiness reports each condition the corridor rests on so
+// traffic goes elsewhere. Liveness stays true through all of them: a
+// restart would not mend a tampered ledger, and a kernel restarted out of
+// VOID would start clean with nothing left to show why it failed, so the
+// condition is shown to operators rather than cleared away. Neither probe
+// writes to the ledger or needs a credential, and neither names a token,
+// a principal, or a receipt.
+package kernel
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/adapters"
+	"github.com/user/oi/kernel-go/internal/audit"
+)
+
+// HealthCheck is one condition readiness rests on
+type HealthCheck struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Health is whether the kernel should be sent traffic, and why
+type Health struct {
+	Ready     bool           `json:"ready"`
+	Integrity IntegrityState `json:"integrity"`
+	Checks    []HealthCheck  `json:"checks"`
+}
+
+// Readiness checks integrity, the last ledger verification, global STOP,
+// quiescing, the governance capsule, and the model adapter
+func (s *SystemState) Readiness() Health {
+	integrity := s.GetIntegrityState()
+	health := Health{Ready: true, Integrity: integrity}
+	check := func(name string, ok bool, detail string) {
+		health.Checks = append(health.Checks, HealthCheck{Name: name, OK: ok, Detail: detail})
+		health.Ready = health.Ready && ok
+	}
+
+	check("integrity", integrity != IntegrityVoid, string(integrity))
+
+	ledger := s.LastLedgerCheck()
+	switch {
+	case ledger.CheckedAt == 0:
+		check("ledger", true, "not yet verified")
+	case ledger.Error != "":
+		check("ledger", false, "failed verification at "+time.Unix(ledger.CheckedAt, 0).UTC().Format(time.RFC3339))
+	default:
+		check("ledger", true, "verified at "+time.Unix(ledger.CheckedAt, 0).UTC().Format(time.RFC3339))
+	}
+
+	stop := HealthCheck{Name: "stop", OK: true}
+	for _, halt := range s.Halts() {
+		if halt.Scope == audit.StopScopeGlobal {
+			stop.OK, stop.Detail = false, fmt.Sprintf("global STOP in force (%s)", halt.Reason)
+		}
+	}
+	check(stop.Name, stop.OK, stop.Detail)
+
+	if s.corridors.isClosed() {
+		check("corridor", false, "quiescing")
+	} else {
+		check("corridor", true, "")
+	}
+
+	check(s.capsuleHealth())
+	check(s.adapterHealth())
+	return health
+}
+
+// capsuleHealth checks that governance rules are loaded and, once
+// governance is committed, that the capsule in force is the one committed
+func (s *SystemState) capsuleHealth() (string, bool, string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.GovernanceCapsule.Rules == nil {
+		return "capsule", false, "no governance rules loaded"
+	}
+	live, err := s.GovernanceCapsule.Hash()
+	if err != nil {
+		return "capsule", false, "governance capsule cannot be hashed"
+	}
+	if s.governance.CapsuleHash != "" && s.governance.CapsuleHash != live {
+		return "capsule", false, "governance capsule in force is not the one committed"
+	}
+	return "capsule", true, "policy " + s.GovernanceCapsule.PolicyVersion
+}
+
+// adapterHealth checks that the model adapter is registered and its
+// breaker is not open, and names any other adapter whose breaker is
+func (s *SystemState) adapterHealth() (string, bool, string) {
+	s.mu.RLock()
+	model := s.ModelAdapter
+	s.mu.RUnlock()
+	if _, err := s.AdapterRegistry.Get(model); err != nil {
+		return "adapters", false, fmt.Sprintf("model adapter %s is not registered", model)
+	}
+	var open []string
+	for _, name := range s.AdapterRegistry.ListAdapters() {
+		if s.AdapterRegistry.BreakerState(name) == adapters.BreakerOpen {
+			open = append(open, name)
+		}
+	}
+	sort.Strings(open)
+	detail := ""
+	if len(open) > 0 {
+		detail = "breaker open: " + strings.Join(open, ", ")
+	}
+	for _, name := range open {
+		if name == model {
+			return "adapters", false, detail
+		}
+	}
+	return "adapters", true, detail
+}
+
+// HealthHandler serves GET /healthz, which answers 200 while the kernel
+// answers at all, and GET /readyz, which answers 503 unless Readiness is
+// ready; both report the kernel's integrity
+func (s *SystemState) HealthHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		writeStopResponse(w, http.StatusOK, map[string]interface{}{"alive": true, "integrity": s.GetIntegrityState()})
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		health := s.Readiness()
+		status := http.StatusOK
+		if !health.Ready {
+			status = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		writeStopResponse(w, status, health)
+	})
+	return mux
+}
@@ -0,0 +1,129 @@
+// WHY: Proves readiness falls to 503 on each condition that makes the
+// kernel refuse its traffic, while liveness keeps answering so the
+// condition is not restarted away.
+package kernel
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/adapters"
+)
+
+// probe GETs path from state's health handler and decodes the response
+func probe(t *testing.T, state *SystemState, path string, out interface{}) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	state.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	if rec.Header().Get("Cache-Control") != "no-store" {
+		t.Fatalf("%s must not be cached", path)
+	}
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("decode %s: %v", path, err)
+		}
+	}
+	return rec.Code
+}
+
+// failedCheck returns the name of the first failed check, if any
+func failedCheck(health Health) string {
+	for _, check := range health.Checks {
+		if !check.OK {
+			return check.Name
+		}
+	}
+	return ""
+}
+
+// TestReadinessFollowsGovernanceState proves each condition that refuses
+// traffic takes the kernel out of rotation and leaves it alive
+func TestReadinessFollowsGovernanceState(t *testing.T) {
+	var health Health
+	state := newSessionKernel(t)
+	if code := probe(t, state, "/readyz", &health); code != http.StatusOK || !health.Ready || health.Integrity != IntegrityOK {
+		t.Fatalf("a fresh kernel must be ready, got %d %+v", code, health)
+	}
+	if err := state.VerifyAndEnforce(); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	probe(t, state, "/readyz", &health)
+	if !health.Ready || len(health.Checks) != 6 {
+		t.Fatalf("a verified kernel must be ready on every check, got %+v", health)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		check  string
+		breaks func(*SystemState)
+	}{
+		{"integrity void", "integrity", func(s *SystemState) { s.SetIntegrityState(IntegrityVoid) }},
+		{"global stop", "stop", func(s *SystemState) { s.RevokeAllTokens() }},
+		{"quiescing", "corridor", func(s *SystemState) { s.Quiesce(time.Second) }},
+		{"no governance", "capsule", func(s *SystemState) { s.GovernanceCapsule.Rules = nil }},
+		{"model adapter missing", "adapters", func(s *SystemState) { s.ModelAdapter = "absent_adapter" }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			state := newSessionKernel(t)
+			tc.breaks(state)
+			var health Health
+			if code := probe(t, state, "/readyz", &health); code != http.StatusServiceUnavailable || health.Ready || failedCheck(health) != tc.check {
+				t.Fatalf("want 503 failing %s, got %d %+v", tc.check, code, health)
+			}
+			var live map[string]interface{}
+			if code := probe(t, state, "/healthz", &live); code != http.StatusOK || live["alive"] != true {
+				t.Fatalf("an unready kernel must stay alive, got %d %+v", code, live)
+			}
+		})
+	}
+}
+
+// TestReadinessReportsFailedVerification proves a ledger that fails
+// verification fails readiness after the kernel voids itself
+func TestReadinessReportsFailedVerification(t *testing.T) {
+	store := &failingStore{}
+	state := newVerifiedState(t, store)
+	store.fail = true
+	state.AuditLedger.AppendPostureChange(state.attribution(""), 1, 2, "test")
+	store.fail = false
+	if err := state.VerifyAndEnforce(); err == nil {
+		t.Fatal("a ledger with a lost receipt must fail verification")
+	}
+
+	var health Health
+	if code := probe(t, state, "/readyz", &health); code != http.StatusServiceUnavailable || health.Integrity != IntegrityVoid {
+		t.Fatalf("a voided kernel must not be ready, got %d %+v", code, health)
+	}
+	for _, check := range health.Checks {
+		if check.Name == "ledger" && check.OK {
+			t.Fatalf("the ledger check must report the failed verification, got %+v", check)
+		}
+	}
+	var live map[string]interface{}
+	if code := probe(t, state, "/healthz", &live); code != http.StatusOK || live["integrity"] != string(IntegrityVoid) {
+		t.Fatalf("liveness must report VOID without failing, got %d %+v", code, live)
+	}
+}
+
+// TestReadinessFollowsTheModelBreaker proves an open breaker on the model
+// adapter fails readiness and is named
+func TestReadinessFollowsTheModelBreaker(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	state.GovernanceCapsule.Rules = map[string]interface{}{"exists": true}
+	state.AdapterRegistry.Register(failingAdapter{adapters.NewMockAdapter(state.ModelAdapter)})
+	if err := state.AdapterRegistry.SetCircuitBreaker(state.ModelAdapter, adapters.BreakerConfig{Window: 1, MinCalls: 1, ErrorRate: 1, OpenFor: time.Minute}); err != nil {
+		t.Fatalf("set breaker: %v", err)
+	}
+	if _, ok, _ := state.adapterHealth(); !ok {
+		t.Fatal("a closed breaker must be ready")
+	}
+
+	Execute(&Request{RawInput: "test request", Metadata: map[string]interface{}{}}, state)
+	name, ok, detail := state.adapterHealth()
+	if name != "adapters" || ok || detail != "breaker open: "+state.ModelAdapter {
+		t.Fatalf("an open model breaker must fail readiness and be named, got %v %q", ok, detail)
+	}
+}
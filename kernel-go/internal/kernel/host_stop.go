@@ -0,0 +1,188 @@
+// WHY: STOP over the admin endpoint needs the API surface to be
+// responsive, and a wedged server is exactly when an operator most needs
+// STOP. A host trigger lets whoever controls the host pull a global STOP
+// without it: a signal sent to the process, or a trigger file that a
+// script, a watchdog, or a hardware switch wired to a GPIO line sets.
+// Each firing is ledgered with its trigger before the STOP it pulls.
+// ArmHostStop arms the set every kernel binary runs with.
+package kernel
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+)
+
+// HostStop pulls a global STOP whenever its trigger fires
+type HostStop struct {
+	state *SystemState
+	fired chan string
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// HostStopFileInterval is how often ArmHostStop checks its trigger file
+const HostStopFileInterval = 100 * time.Millisecond
+
+// StopOnSignal pulls a global STOP whenever the process receives one of
+// signals
+func (s *SystemState) StopOnSignal(signals ...os.Signal) (*HostStop, error) {
+	if len(signals) == 0 {
+		return nil, fmt.Errorf("no signals to STOP on")
+	}
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, signals...)
+
+	h := s.newHostStop()
+	go func() {
+		defer close(h.done)
+		defer signal.Stop(received)
+		for {
+			select {
+			case sig := <-received:
+				h.pull("signal:" + sig.String())
+			case <-h.stop:
+				return
+			}
+		}
+	}()
+	return h, nil
+}
+
+// StopOnFile pulls a global STOP when the file at path appears holding
+// anything but "0", checked every interval. A sysfs GPIO value file
+// therefore triggers on its line going high. It fires once per setting
+// and re-arms when the file is removed or reads "0" again.
+func (s *SystemState) StopOnFile(path string, interval time.Duration) (*HostStop, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no trigger file to STOP on")
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("trigger check interval must be positive, got %s", interval)
+	}
+
+	h := s.newHostStop()
+	go func() {
+		defer close(h.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		armed := true
+		for {
+			set := fileTriggerSet(path)
+			if set && armed {
+				h.pull("file:" + path)
+			}
+			armed = !set
+
+			select {
+			case <-ticker.C:
+			case <-h.stop:
+				return
+			}
+		}
+	}()
+	return h, nil
+}
+
+// Fired delivers the trigger each time it pulls STOP; a firing is dropped
+// if the last is still unread
+func (h *HostStop) Fired() <-chan string {
+	return h.fired
+}
+
+// Stop disarms the trigger
+func (h *HostStop) Stop() {
+	h.stopOnce.Do(func() { close(h.stop) })
+	<-h.done
+}
+
+func (s *SystemState) newHostStop() *HostStop {
+	return &HostStop{
+		state: s,
+		fired: make(chan string, 1),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+}
+
+// pull ledgers the firing of trigger and pulls a global STOP
+func (h *HostStop) pull(trigger string) {
+	s := h.state
+	s.AuditLedger.AppendHostStop(s.attribution(""), trigger)
+	s.revokeAllTokens(audit.StopReasonOperatorAction)
+	select {
+	case h.fired <- trigger:
+	default:
+	}
+}
+
+// ArmHostStop arms STOP on SIGUSR1 and SIGTERM (SIGTERM alone outside
+// Unix) and, if stopFile is set, on that trigger file, passing each
+// firing to report; the returned function disarms them. A terminating
+// signal STOPs first; a second one terminates the process as usual, so a
+// wedged kernel can still be killed.
+func (s *SystemState) ArmHostStop(stopFile string, report func(trigger string)) (func(), error) {
+	hosts := []*HostStop{}
+	disarm := func() {
+		for _, host := range hosts {
+			host.Stop()
+		}
+	}
+
+	host, err := s.StopOnSignal(stopSignals...)
+	if err != nil {
+		return nil, err
+	}
+	hosts = append(hosts, host)
+	if stopFile != "" {
+		host, err := s.StopOnFile(stopFile, HostStopFileInterval)
+		if err != nil {
+			disarm()
+			return nil, err
+		}
+		hosts = append(hosts, host)
+	}
+
+	done := make(chan struct{})
+	var reporters sync.WaitGroup
+	for _, host := range hosts {
+		reporters.Add(1)
+		go func(host *HostStop) {
+			defer reporters.Done()
+			for {
+				select {
+				case trigger := <-host.Fired():
+					if trigger == "signal:"+terminateSignal.String() {
+						signal.Reset(terminateSignal)
+					}
+					report(trigger)
+				case <-done:
+					return
+				}
+			}
+		}(host)
+	}
+	return func() {
+		close(done)
+		reporters.Wait()
+		disarm()
+	}, nil
+}
+
+// fileTriggerSet reports whether the trigger file exists and does not
+// read "0"
+func fileTriggerSet(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return !bytes.Equal(bytes.TrimSpace(data), []byte("0"))
+}
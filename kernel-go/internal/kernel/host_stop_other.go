@@ -0,0 +1,15 @@
+//go:build !unix
+
+package kernel
+
+import (
+	"os"
+	"syscall"
+)
+
+// stopSignals pull a global STOP under ArmHostStop; there is no SIGUSR1
+// outside Unix
+var (
+	stopSignals               = []os.Signal{syscall.SIGTERM}
+	terminateSignal os.Signal = syscall.SIGTERM
+)
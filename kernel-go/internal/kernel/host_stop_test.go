@@ -0,0 +1,99 @@
+// WHY: Proves a trigger file pulls a global STOP when set, once per
+// setting, and that each firing is ledgered with its trigger.
+package kernel
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+)
+
+// awaitFired waits for host to fire
+func awaitFired(t *testing.T, host *HostStop) string {
+	t.Helper()
+	select {
+	case trigger := <-host.Fired():
+		return trigger
+	case <-time.After(2 * time.Second):
+		t.Fatal("the host trigger never fired")
+		return ""
+	}
+}
+
+// TestStopOnFileFiresOncePerSetting proves the trigger file STOPs when it
+// goes high and re-arms only once it is cleared
+func TestStopOnFileFiresOncePerSetting(t *testing.T) {
+	state := newSessionKernel(t)
+	path := filepath.Join(t.TempDir(), "value")
+	if _, err := state.StopOnFile(path, 0); err == nil {
+		t.Fatal("a trigger file without a check interval must be refused")
+	}
+	if err := os.WriteFile(path, []byte("0\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	host, err := state.StopOnFile(path, time.Millisecond)
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	defer host.Stop()
+
+	time.Sleep(10 * time.Millisecond)
+	if len(state.Halts()) != 0 {
+		t.Fatal("a trigger file reading 0 must not STOP")
+	}
+
+	os.WriteFile(path, []byte("1\n"), 0o600)
+	if trigger := awaitFired(t, host); trigger != "file:"+path {
+		t.Fatalf("the firing must name its trigger, got %q", trigger)
+	}
+	if halts := state.Halts(); len(halts) != 1 || halts[0].Reason != audit.StopReasonOperatorAction {
+		t.Fatalf("the trigger must pull a global STOP, got %+v", halts)
+	}
+	time.Sleep(10 * time.Millisecond)
+	select {
+	case <-host.Fired():
+		t.Fatal("a trigger file left set must fire only once")
+	default:
+	}
+
+	os.Remove(path)
+	time.Sleep(10 * time.Millisecond)
+	os.WriteFile(path, []byte("pressed"), 0o600)
+	awaitFired(t, host)
+
+	page, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"host_stop"}})
+	if len(page.Receipts) != 2 || page.Receipts[0].EventData["trigger"] != "file:"+path {
+		t.Fatalf("every firing must be ledgered, got %+v", page.Receipts)
+	}
+}
+
+// TestArmHostStopReportsFirings proves the armed trigger file STOPs the
+// kernel and each firing reaches the binary's report
+func TestArmHostStopReportsFirings(t *testing.T) {
+	state := newSessionKernel(t)
+	path := filepath.Join(t.TempDir(), "stop")
+	if err := os.WriteFile(path, []byte("1"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	reported := make(chan string, 1)
+	disarm, err := state.ArmHostStop(path, func(trigger string) { reported <- trigger })
+	if err != nil {
+		t.Fatalf("arm: %v", err)
+	}
+	defer disarm()
+
+	select {
+	case trigger := <-reported:
+		if trigger != "file:"+path {
+			t.Fatalf("the report must name the trigger, got %q", trigger)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("the armed trigger file never fired")
+	}
+	if len(state.Halts()) != 1 {
+		t.Fatal("the trigger file must pull a global STOP")
+	}
+}
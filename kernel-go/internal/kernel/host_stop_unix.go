@@ -0,0 +1,15 @@
+//go:build unix
+
+package kernel
+
+import (
+	"os"
+	"syscall"
+)
+
+// stopSignals pull a global STOP under ArmHostStop; terminateSignal is the one of them
+// whose second delivery terminates the process
+var (
+	stopSignals               = []os.Signal{syscall.SIGUSR1, syscall.SIGTERM}
+	terminateSignal os.Signal = syscall.SIGTERM
+)
@@ -0,0 +1,32 @@
+//go:build unix
+
+package kernel
+
+import (
+	"os"
+	"syscall"
+	"testing"
+)
+
+// TestStopOnSignal proves a signal to the process pulls a global STOP
+func TestStopOnSignal(t *testing.T) {
+	state := newSessionKernel(t)
+	if _, err := state.StopOnSignal(); err == nil {
+		t.Fatal("a signal trigger without signals must be refused")
+	}
+	host, err := state.StopOnSignal(syscall.SIGUSR1)
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	defer host.Stop()
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("kill: %v", err)
+	}
+	if trigger := awaitFired(t, host); trigger != "signal:user defined signal 1" {
+		t.Fatalf("the firing must name its signal, got %q", trigger)
+	}
+	if len(state.Halts()) != 1 {
+		t.Fatal("the signal must pull a global STOP")
+	}
+}
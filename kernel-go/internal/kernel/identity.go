@@ -0,0 +1,84 @@
+// WHY: A request ran as the kernel's principal, or as whatever principal
+// its session was opened with, on the caller's word. Once an identity
+// verifier is set, every request must carry a bearer token it verifies,
+// the request runs as the principal and namespace that token states, and
+// one whose identity cannot be verified is refused before anything is
+// judged. Every check is ledgered, accepted or not.
+package kernel
+
+import (
+	"fmt"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/identity"
+)
+
+// SetIdentityVerifier requires every request to carry a bearer token
+// verifier accepts; nil lifts the requirement. A verifier without its own
+// clock is checked against the kernel's. One that would accept admin
+// tokens is refused (see SetAdminVerifier).
+func (s *SystemState) SetIdentityVerifier(verifier *identity.Verifier) error {
+	if verifier != nil {
+		if err := verifier.Validate(); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if verifier != nil && s.adminVerifier != nil && verifier.Issuer == s.adminVerifier.Issuer && verifier.Audience == s.adminVerifier.Audience {
+		return fmt.Errorf("requests need an issuer or audience apart from admin tokens, not %s for %s", verifier.Issuer, verifier.Audience)
+	}
+	s.identityVerifier = verifier
+	return nil
+}
+
+// Authenticate verifies a bearer token and returns the identity it states
+func (s *SystemState) Authenticate(bearer string) (IdentityCapsule, error) {
+	return s.authenticate(bearer, s.attribution(""))
+}
+
+// authenticate verifies bearer for actor and ledgers the result. WHY: A
+// bearer token with no verifier set cannot be checked, so it is refused
+// rather than ignored.
+func (s *SystemState) authenticate(bearer string, actor audit.Attribution) (IdentityCapsule, error) {
+	s.mu.RLock()
+	var verifier identity.Verifier
+	configured := s.identityVerifier != nil
+	if configured {
+		verifier = *s.identityVerifier
+		if verifier.Clock == nil {
+			verifier.Clock = s.clock
+		}
+	}
+	s.mu.RUnlock()
+
+	var verified *identity.Identity
+	var err error
+	switch {
+	case !configured:
+		err = fmt.Errorf("no identity verifier is set")
+	case bearer == "":
+		err = fmt.Errorf("no bearer token")
+	default:
+		verified, err = verifier.Verify(bearer)
+	}
+	if err != nil {
+		s.AuditLedger.AppendAuthentication(actor, verifier.Issuer, "", false, err.Error())
+		return IdentityCapsule{}, fmt.Errorf("identity unverifiable: %w", err)
+	}
+
+	actor.PrincipalID, actor.NamespaceID = verified.PrincipalID, verified.NamespaceID
+	s.AuditLedger.AppendAuthentication(actor, verified.Issuer, verified.PrincipalID, true, "")
+	return IdentityCapsule{
+		PrincipalID: verified.PrincipalID,
+		NamespaceID: verified.NamespaceID,
+		Attributes:  verified.Attributes,
+	}, nil
+}
+
+// requiresIdentity reports whether requests must carry a bearer token
+func (s *SystemState) requiresIdentity() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.identityVerifier != nil
+}
@@ -0,0 +1,94 @@
+// WHY: Proves that once an identity verifier is set, a request runs as
+// the principal and namespace its bearer token states and nothing else,
+// and that one whose identity cannot be verified is refused and ledgered.
+package kernel
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/identity"
+)
+
+// bearerFor returns an EdDSA JWT for subject in namespace signed with key
+func bearerFor(t *testing.T, key ed25519.PrivateKey, subject string, namespace string, expires time.Time) string {
+	t.Helper()
+	head, _ := json.Marshal(map[string]string{"alg": identity.AlgorithmEdDSA, "kid": "issuer_key"})
+	body, _ := json.Marshal(map[string]interface{}{
+		"iss": "https://issuer.example", "aud": "oi-kernel", "sub": subject, "namespace": namespace, "exp": expires.Unix(),
+	})
+	signed := base64.RawURLEncoding.EncodeToString(head) + "." + base64.RawURLEncoding.EncodeToString(body)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(ed25519.Sign(key, []byte(signed)))
+}
+
+// TestRequestsRunAsTheVerifiedPrincipal proves the bearer token, not the
+// caller, names the principal, and unverifiable requests are refused
+func TestRequestsRunAsTheVerifiedPrincipal(t *testing.T) {
+	state := newSessionKernel(t)
+	public, key, _ := ed25519.GenerateKey(rand.Reader)
+	_, forger, _ := ed25519.GenerateKey(rand.Reader)
+	expires := time.Now().Add(time.Hour)
+	alice := bearerFor(t, key, "alice", "tenant_a", expires)
+
+	if _, err := Execute(&Request{RawInput: "test request", BearerToken: alice}, state); err == nil {
+		t.Fatal("a bearer token must be refused while no verifier can check it")
+	}
+	if err := state.SetIdentityVerifier(&identity.Verifier{Issuer: "https://issuer.example"}); err == nil {
+		t.Fatal("an incomplete verifier must be refused")
+	}
+	verifier := &identity.Verifier{
+		Issuer:   "https://issuer.example",
+		Audience: "oi-kernel",
+		Keys:     identity.KeySet{"issuer_key": public},
+	}
+	if err := state.SetIdentityVerifier(verifier); err != nil {
+		t.Fatalf("set verifier: %v", err)
+	}
+
+	for name, req := range map[string]*Request{
+		"no bearer":         {RawInput: "test request"},
+		"forged bearer":     {RawInput: "test request", BearerToken: bearerFor(t, forger, "alice", "tenant_a", expires)},
+		"expired bearer":    {RawInput: "test request", BearerToken: bearerFor(t, key, "alice", "tenant_a", time.Now().Add(-time.Hour))},
+		"another namespace": {RawInput: "test request", BearerToken: alice, NamespaceID: "tenant_b"},
+	} {
+		if resp, err := Execute(req, state); err == nil || resp.Success {
+			t.Fatalf("%s: request must be refused", name)
+		}
+	}
+	bob, _ := state.Sessions.Open("bob", "tenant_a")
+	if _, err := Execute(&Request{RawInput: "test request", BearerToken: alice, SessionID: bob.ID}, state); err == nil {
+		t.Fatal("a bearer token must not act in another principal's session")
+	}
+	if len(state.ActiveCapabilityTokens) != 0 {
+		t.Fatal("a refused request must mint nothing")
+	}
+
+	resp, err := Execute(&Request{RawInput: "test request", BearerToken: alice}, state)
+	if err != nil || !resp.Success {
+		t.Fatalf("verified request: %+v (%v)", resp, err)
+	}
+	for _, minted := range state.ActiveCapabilityTokens {
+		if minted.PrincipalID != "alice" || minted.NamespaceID != "tenant_a" {
+			t.Fatalf("the token must be minted to the verified principal, got %s in %s", minted.PrincipalID, minted.NamespaceID)
+		}
+	}
+
+	page, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"authentication"}})
+	accepted := 0
+	for _, receipt := range page.Receipts {
+		if receipt.EventData["accepted"] == true {
+			accepted++
+			if receipt.EventData["principal_id"] != "alice" || receipt.EventData["subject"] != "alice" {
+				t.Fatalf("an accepted check must be attributed to its subject, got %+v", receipt.EventData)
+			}
+		}
+	}
+	if len(page.Receipts) != 7 || accepted != 3 {
+		t.Fatalf("every check must be ledgered, got %d receipts, %d accepted", len(page.Receipts), accepted)
+	}
+}
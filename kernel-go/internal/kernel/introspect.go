@@ -0,0 +1,95 @@
+// WHY: A caller outside the process holds only a bearer token and the
+// digests and request IDs the corridor handed back. IntrospectToken and
+// QueryReceipts let that caller see the state of their own tokens and the
+// receipts about their own requests, and nothing of anyone else's: the
+// principal and namespace come from the verified bearer, never from the
+// request.
+package kernel
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/capabilities"
+)
+
+// AccessRefusedError reports a read that was not served; Reason is one of
+// the StopRefused reasons
+type AccessRefusedError struct {
+	Reason string
+	Err    error
+}
+
+func (e *AccessRefusedError) Error() string {
+	return fmt.Sprintf("access refused (%s): %v", e.Reason, e.Err)
+}
+
+func (e *AccessRefusedError) Unwrap() error {
+	return e.Err
+}
+
+// TokenInfo is what a holder may learn of a capability token
+type TokenInfo struct {
+	Digest      string    `json:"digest"`
+	Scope       []string  `json:"scope"`
+	PrincipalID string    `json:"principal_id"`
+	NamespaceID string    `json:"namespace_id"`
+	IssuedAt    time.Time `json:"issued_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+
+	// Active is false once the token is revoked or expired
+	Active    bool       `json:"active"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// IntrospectToken describes the token with digest, if the caller bearer
+// identifies holds it. WHY: An unknown token and another principal's are
+// refused alike, so a caller cannot probe for digests that exist.
+func (s *SystemState) IntrospectToken(bearer string, digest string) (TokenInfo, error) {
+	caller, err := s.authenticate(bearer, s.attribution(""))
+	if err != nil {
+		return TokenInfo{}, &AccessRefusedError{Reason: StopRefusedUnauthenticated, Err: err}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	token, ok := s.ActiveCapabilityTokens[digest]
+	if !ok || token.PrincipalID != caller.PrincipalID || token.NamespaceID != caller.NamespaceID {
+		return TokenInfo{}, &AccessRefusedError{Reason: StopRefusedForbidden, Err: fmt.Errorf("token %s is not one principal %s holds", digest, caller.PrincipalID)}
+	}
+	return s.tokenInfoLocked(token), nil
+}
+
+// tokenInfoLocked describes token; s.mu is held
+func (s *SystemState) tokenInfoLocked(token *capabilities.Token) TokenInfo {
+	return TokenInfo{
+		Digest:      token.Digest,
+		Scope:       append([]string{}, token.Scope...),
+		PrincipalID: token.PrincipalID,
+		NamespaceID: token.NamespaceID,
+		IssuedAt:    token.IssuedAt,
+		ExpiresAt:   token.ExpiresAt,
+		Active:      token.RevokedAt == nil && !s.revokedTokens[token.Digest] && s.nowLocked().Before(token.ExpiresAt),
+		RevokedAt:   token.RevokedAt,
+	}
+}
+
+// QueryReceipts runs filter over the receipts of the principal and
+// namespace bearer identifies; a filter naming anyone else is refused
+func (s *SystemState) QueryReceipts(bearer string, filter audit.ReceiptFilter) (audit.ReceiptPage, error) {
+	caller, err := s.authenticate(bearer, s.attribution(""))
+	if err != nil {
+		return audit.ReceiptPage{}, &AccessRefusedError{Reason: StopRefusedUnauthenticated, Err: err}
+	}
+	if (filter.PrincipalID != "" && filter.PrincipalID != caller.PrincipalID) || (filter.NamespaceID != "" && filter.NamespaceID != caller.NamespaceID) {
+		return audit.ReceiptPage{}, &AccessRefusedError{Reason: StopRefusedForbidden, Err: fmt.Errorf("principal %s may read only its own receipts", caller.PrincipalID)}
+	}
+	filter.PrincipalID, filter.NamespaceID = caller.PrincipalID, caller.NamespaceID
+
+	page, err := s.AuditLedger.Query(filter)
+	if err != nil {
+		return audit.ReceiptPage{}, &AccessRefusedError{Reason: StopRefusedInvalid, Err: err}
+	}
+	return page, nil
+}
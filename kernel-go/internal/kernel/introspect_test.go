@@ -0,0 +1,48 @@
+// WHY: Proves a caller can introspect only tokens they hold and read only
+// their own receipts, whatever the request asks for.
+package kernel
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+)
+
+// TestReadsAreConfinedToTheCaller proves token introspection and receipt
+// queries answer for the verified principal alone
+func TestReadsAreConfinedToTheCaller(t *testing.T) {
+	state, key := stopKernel(t)
+	expires := time.Now().Add(time.Hour)
+	alice := bearerFor(t, key, "alice", "test_namespace", expires)
+	aliceToken := mintApprover(t, state, "alice", "scope1")
+	bobToken := mintApprover(t, state, "bob", "scope1")
+
+	var refused *AccessRefusedError
+	if _, err := state.IntrospectToken("", aliceToken.Digest); !errors.As(err, &refused) || refused.Reason != StopRefusedUnauthenticated {
+		t.Fatalf("an unauthenticated introspection must be refused, got %v", err)
+	}
+	if _, err := state.IntrospectToken(alice, bobToken.Digest); !errors.As(err, &refused) || refused.Reason != StopRefusedForbidden {
+		t.Fatalf("another principal's token must be refused, got %v", err)
+	}
+	info, err := state.IntrospectToken(alice, aliceToken.Digest)
+	if err != nil || !info.Active || info.PrincipalID != "alice" {
+		t.Fatalf("alice must see her live token, got %+v (%v)", info, err)
+	}
+	state.RevokeToken(aliceToken.Digest)
+	if info, _ := state.IntrospectToken(alice, aliceToken.Digest); info.Active || info.RevokedAt == nil {
+		t.Fatalf("a revoked token must introspect as inactive, got %+v", info)
+	}
+
+	if _, err := state.QueryReceipts(alice, audit.ReceiptFilter{PrincipalID: "bob"}); !errors.As(err, &refused) || refused.Reason != StopRefusedForbidden {
+		t.Fatalf("a query for another principal must be refused, got %v", err)
+	}
+	page, err := state.QueryReceipts(alice, audit.ReceiptFilter{EventTypes: []string{"token_mint"}})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(page.Receipts) != 1 || page.Receipts[0].EventData["token_digest"] != aliceToken.Digest {
+		t.Fatalf("alice must see only her own mint, got %+v", page.Receipts)
+	}
+}
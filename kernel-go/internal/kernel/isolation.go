@@ -0,0 +1,71 @@
+// WHY: Memory was namespaced, but nothing tied the namespace an operation
+// acted for to the token that authorized it, so a token minted for one
+// tenant could read or write another's memory by naming it. Memory
+// operations made through the kernel take a token the kernel holds and
+// act only for the namespace that token was minted in; an attempt for any
+// other is refused and ledgered as a namespace violation, as the corridor
+// does for adapter calls.
+package kernel
+
+import (
+	"fmt"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/memory"
+)
+
+// Token scopes that grant memory access through the kernel
+const (
+	ScopeMemoryRead  = "memory:read"
+	ScopeMemoryWrite = "memory:write"
+)
+
+// ReadMemoryIn reads an entry from namespace's memory, redacted under
+// namespace's posture. token must be a live token the kernel minted in
+// namespace, scoped to ScopeMemoryRead.
+func (s *SystemState) ReadMemoryIn(namespace string, token *capabilities.Token, partition string, id string) (*memory.Entry, error) {
+	held, err := s.checkNamespaceToken(namespace, token, ScopeMemoryRead, "memory:"+partition)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := s.GovernanceCapsule.PostureLevelPolicy(s.PostureLevelFor(held.NamespaceID))
+	if err != nil {
+		return nil, err
+	}
+	return s.MemoryManager.Namespace(held.NamespaceID).ReadWithPolicy(partition, id, policy)
+}
+
+// WriteMemoryIn writes an entry to namespace's memory as the token's
+// principal in the kernel's record. token must be a live token the kernel
+// minted in namespace, scoped to ScopeMemoryWrite.
+func (s *SystemState) WriteMemoryIn(namespace string, token *capabilities.Token, partition string, id string, content string, metadata map[string]interface{}) error {
+	held, err := s.checkNamespaceToken(namespace, token, ScopeMemoryWrite, "memory:"+partition)
+	if err != nil {
+		return err
+	}
+	return s.MemoryManager.Namespace(held.NamespaceID).WriteAs(held.PrincipalID, partition, id, content, metadata)
+}
+
+// checkNamespaceToken returns the kernel's record of token if it may act
+// on target for namespace with scope, and ledgers a token minted in
+// another namespace as a violation whatever else is wrong with it. WHY:
+// The namespace compared, and the principal acted as, are the ones in the
+// kernel's record of the token, not the caller's copy.
+func (s *SystemState) checkNamespaceToken(namespace string, token *capabilities.Token, scope string, target string) (*capabilities.Token, error) {
+	if token != nil {
+		s.mu.RLock()
+		held, minted := s.ActiveCapabilityTokens[token.Digest]
+		s.mu.RUnlock()
+		if minted && held.NamespaceID != namespace {
+			actor := s.attribution("")
+			actor.PrincipalID, actor.NamespaceID = held.PrincipalID, namespace
+			s.AuditLedger.AppendNamespaceViolation(actor, target, held.NamespaceID, held.Digest)
+			return nil, fmt.Errorf("%s refused: token minted in namespace %q cannot act for namespace %q", target, held.NamespaceID, namespace)
+		}
+	}
+	held, err := s.heldToken(token, scope)
+	if err != nil {
+		return nil, fmt.Errorf("%s refused: %w", target, err)
+	}
+	return held, nil
+}
@@ -0,0 +1,102 @@
+// WHY: Proves a token minted in one namespace cannot drive an adapter or
+// reach memory for another, and that each attempt is ledgered as a
+// critical namespace violation.
+package kernel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/cif"
+	"github.com/user/oi/kernel-go/internal/memory"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// mintIn mints and registers a token for principal in namespace with scope
+func mintIn(t *testing.T, state *SystemState, namespace string, principal string, scope ...string) *capabilities.Token {
+	t.Helper()
+	token, err := capabilities.Mint("kernel", principal, "kernel", scope, capabilities.Limits{}, time.Minute,
+		capabilities.PostureBounds{MinPosture: posture.P1, MaxPosture: posture.P4}, namespace, principal)
+	if err != nil {
+		t.Fatalf("mint: %v", err)
+	}
+	state.AddToken(token)
+	return token
+}
+
+// TestMemoryActsOnlyForTheTokensNamespace proves memory reads and writes
+// through the kernel stay inside the token's namespace and act as the
+// principal the kernel minted the token to
+func TestMemoryActsOnlyForTheTokensNamespace(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	alice := mintIn(t, state, "tenant_a", "alice", ScopeMemoryRead, ScopeMemoryWrite)
+	bob := mintIn(t, state, "tenant_b", "bob", ScopeMemoryRead, ScopeMemoryWrite)
+
+	if err := state.WriteMemoryIn("tenant_a", alice, memory.PartitionDurable, "note", "alice's note", nil); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	entry, err := state.ReadMemoryIn("tenant_a", alice, memory.PartitionDurable, "note")
+	if err != nil || entry.Content != "alice's note" || entry.Principal != "alice" {
+		t.Fatalf("read back: %+v (%v)", entry, err)
+	}
+
+	if _, err := state.ReadMemoryIn("tenant_a", bob, memory.PartitionDurable, "note"); err == nil {
+		t.Fatal("a token from tenant_b must not read tenant_a's memory")
+	}
+	if err := state.WriteMemoryIn("tenant_a", bob, memory.PartitionDurable, "note", "overwritten", nil); err == nil {
+		t.Fatal("a token from tenant_b must not write tenant_a's memory")
+	}
+	if _, err := state.ReadMemoryIn("tenant_b", bob, memory.PartitionDurable, "note"); err == nil {
+		t.Fatal("tenant_b must not see tenant_a's entry in its own namespace")
+	}
+	forged := *alice
+	forged.PrincipalID = "carol"
+	if err := state.WriteMemoryIn("tenant_a", &forged, memory.PartitionDurable, "forged", "charged to carol", nil); err != nil {
+		t.Fatalf("write with an altered copy: %v", err)
+	}
+	if entry, _ := state.ReadMemoryIn("tenant_a", &forged, memory.PartitionDurable, "forged"); entry == nil || entry.Principal != "alice" {
+		t.Fatalf("a write must be charged to the principal the kernel minted the token to, got %+v", entry)
+	}
+	reader := mintIn(t, state, "tenant_a", "alice", ScopeMemoryRead)
+	if err := state.WriteMemoryIn("tenant_a", reader, memory.PartitionDurable, "note", "overwritten", nil); err == nil {
+		t.Fatal("a read token must not write memory")
+	}
+
+	page, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"namespace_violation"}})
+	if len(page.Receipts) != 2 {
+		t.Fatalf("each cross-namespace attempt must be ledgered, got %d", len(page.Receipts))
+	}
+	for _, receipt := range page.Receipts {
+		if receipt.Severity != audit.SeverityCritical || receipt.EventData["token_namespace"] != "tenant_b" || receipt.EventData["namespace_id"] != "tenant_a" {
+			t.Fatalf("violation receipt wrong: %+v", receipt)
+		}
+	}
+}
+
+// TestAdapterCallsActOnlyForTheTokensNamespace proves the corridor's
+// adapter call refuses a token minted in another namespace
+func TestAdapterCallsActOnlyForTheTokensNamespace(t *testing.T) {
+	state := newSessionKernel(t)
+	request, err := cif.Ingress("test request", map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("ingress: %v", err)
+	}
+	foreign := mintIn(t, state, "tenant_b", "bob", DefaultModelAdapter)
+	actor := state.attribution("request_1")
+	actor.NamespaceID = "tenant_a"
+
+	if _, err := kernelExecute(foreign, request, posture.P1, state, actor); err == nil {
+		t.Fatal("a token from tenant_b must not call an adapter for tenant_a")
+	}
+	page, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"namespace_violation"}})
+	if len(page.Receipts) != 1 || page.Receipts[0].EventData["target"] != "adapter:"+DefaultModelAdapter {
+		t.Fatalf("the attempt must be ledgered as a violation, got %+v", page.Receipts)
+	}
+
+	actor.NamespaceID = "tenant_b"
+	if _, err := kernelExecute(foreign, request, posture.P1, state, actor); err != nil {
+		t.Fatalf("a token must call an adapter for its own namespace: %v", err)
+	}
+}
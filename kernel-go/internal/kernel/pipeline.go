@@ -3,18 +3,54 @@
 package kernel
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/user/oi/kernel-go/internal/adapters"
+	"github.com/user/oi/kernel-go/internal/audit"
 	"github.com/user/oi/kernel-go/internal/capabilities"
 	"github.com/user/oi/kernel-go/internal/cdi"
 	"github.com/user/oi/kernel-go/internal/cif"
+	"github.com/user/oi/kernel-go/internal/posture"
 )
 
 // Request represents a user request entering the system
 type Request struct {
 	RawInput string
 	Metadata map[string]interface{}
+
+	// ID correlates every receipt the request produces; generated if empty
+	ID string
+
+	// NamespaceID is the namespace the request acts in; empty means the
+	// kernel's own
+	NamespaceID string
+
+	// SessionID, if set, is the session the request runs in: its
+	// principal, namespace, consents, and leak budget apply
+	SessionID string
+
+	// BearerToken is the OIDC/JWT token naming the principal the request
+	// runs as; required once an identity verifier is set
+	BearerToken string
+
+	// OnStage, if set, is called with each audit trail stage as the
+	// corridor reaches it, so a caller can show progress as it happens
+	OnStage func(stage string)
+}
+
+// stage appends stage to trail and reports it to OnStage
+func (r *Request) stage(trail []string, stage string) []string {
+	if r.OnStage != nil {
+		r.OnStage(stage)
+	}
+	return append(trail, stage)
 }
 
 // Response represents the final response to the user
@@ -23,15 +59,106 @@ type Response struct {
 	Success      bool
 	Error        string
 	AuditTrail   []string
+	RequestID    string
 }
 
 // Execute runs the complete corridor pipeline: CIF → CDI → kernel → CDI → CIF
 // WHY: This is THE single path to capability. No bypass allowed.
 func Execute(req *Request, state *SystemState) (*Response, error) {
+	requestID := req.ID
+	if requestID == "" {
+		requestID = newRequestID()
+	}
+
+	// A quiescing kernel takes no new requests
+	if err := state.corridors.enter(); err != nil {
+		return &Response{
+			Success: false,
+			Error:   fmt.Sprintf("quiesced: %v", err),
+			RequestID: requestID,
+		}, err
+	}
+	defer state.corridors.leave()
+
+	resp, err := execute(req, requestID, state)
+	if resp != nil {
+		resp.RequestID = requestID
+	}
+	return resp, err
+}
+
+// execute runs the corridor with every receipt attributed to requestID
+func execute(req *Request, requestID string, state *SystemState) (*Response, error) {
+	actor := state.attribution(requestID)
+	if req.NamespaceID != "" {
+		actor.NamespaceID = req.NamespaceID
+	}
+	consents, consentExpiry := state.grantedConsents()
 	auditTrail := []string{}
 
+	// A session's principal and consents replace the kernel's
+	var session *Session
+	if req.SessionID != "" {
+		var err error
+		session, err = state.Sessions.use(req.SessionID)
+		if err == nil && req.NamespaceID != "" && req.NamespaceID != session.NamespaceID {
+			err = fmt.Errorf("namespace %s is not the session's", req.NamespaceID)
+		}
+		if err != nil {
+			return &Response{
+				Success: false,
+				Error:   fmt.Sprintf("session_failed: %v", err),
+				AuditTrail: auditTrail,
+			}, err
+		}
+		actor.PrincipalID, actor.NamespaceID = session.PrincipalID, session.NamespaceID
+		consents, consentExpiry = session.Consents(), nil
+	}
+
+	// A verified bearer token, not the caller, names the principal
+	if req.BearerToken != "" || state.requiresIdentity() {
+		verified, err := state.authenticate(req.BearerToken, actor)
+		if err == nil && session != nil && (verified.PrincipalID != session.PrincipalID || verified.NamespaceID != session.NamespaceID) {
+			err = fmt.Errorf("principal %s in %s is not the session's", verified.PrincipalID, verified.NamespaceID)
+		}
+		if err == nil && req.NamespaceID != "" && req.NamespaceID != verified.NamespaceID {
+			err = fmt.Errorf("namespace %s is not the principal's", req.NamespaceID)
+		}
+		if err != nil {
+			return &Response{
+				Success: false,
+				Error:   fmt.Sprintf("authentication_failed: %v", err),
+				AuditTrail: auditTrail,
+			}, err
+		}
+		actor.PrincipalID, actor.NamespaceID = verified.PrincipalID, verified.NamespaceID
+	}
+	namespace := actor.NamespaceID
+
+	// Posture windows apply before anything is judged
+	if err := state.ApplyPostureSchedule(); err != nil {
+		return &Response{
+			Success: false,
+			Error:   fmt.Sprintf("posture_schedule_failed: %v", err),
+			AuditTrail: auditTrail,
+		}, err
+	}
+
+	// The namespace's posture holds for the whole request
+	postureLevel := state.PostureLevelFor(namespace)
+
+	// The governance in force is judged against the one committed
+	governance, err := state.governanceBinding(actor)
+	if err != nil {
+		return &Response{
+			Success: false,
+			Error:   fmt.Sprintf("governance_binding_failed: %v", err),
+			AuditTrail: auditTrail,
+		}, err
+	}
+
 	// STEP 1: CIF Ingress - sanitize and label input
-	auditTrail = append(auditTrail, "cif_ingress_start")
+	auditTrail = req.stage(auditTrail, "cif_ingress_start")
 	labeledRequest, err := cif.Ingress(req.RawInput, req.Metadata)
 	if err != nil {
 		return &Response{
@@ -40,16 +167,26 @@ func Execute(req *Request, state *SystemState) (*Response, error) {
 			AuditTrail: auditTrail,
 		}, err
 	}
-	auditTrail = append(auditTrail, "cif_ingress_complete")
+	auditTrail = req.stage(auditTrail, "cif_ingress_complete")
+	if len(labeledRequest.TaintLabels) > 0 {
+		state.observePosture(posture.SignalTaint, strings.Join(labeledRequest.TaintLabels, ","))
+	}
 
 	// STEP 2: CDI Decision - judge before power
-	auditTrail = append(auditTrail, "cdi_decision_start")
+	auditTrail = req.stage(auditTrail, "cdi_decision_start")
 	decisionCtx := &cdi.DecisionContext{
 		Request:         labeledRequest,
-		PostureLevel:    state.PostureLevel,
+		PostureLevel:    postureLevel,
 		GovernanceRules: state.GovernanceCapsule.Rules,
 		IntegrityState:  string(state.IntegrityState),
-		ActiveConsents:  state.AuthorityCapsule.ActiveConsents,
+		ActiveConsents:  consents,
+		ConsentExpiry:   consentExpiry,
+		Now:             state.now().Unix(),
+
+		CapsuleHash:          governance.live,
+		CommittedCapsuleHash: governance.CapsuleHash,
+		World:                state.worldContext(),
+		Adapters:        state.AdapterRegistry.Declarations(),
 	}
 
 	decision, err := cdi.Decide(decisionCtx)
@@ -62,12 +199,15 @@ func Execute(req *Request, state *SystemState) (*Response, error) {
 	}
 
 	// Log CDI decision
-	state.AuditLedger.AppendCDIDecision(string(decision.Decision), labeledRequest.InputHash, "")
-	auditTrail = append(auditTrail, fmt.Sprintf("cdi_decision: %s", decision.Decision))
+	state.AuditLedger.AppendGovernedDecision(actor, string(decision.Decision), labeledRequest.InputHash, "", decision.DecisionID, governance.Governance)
+	auditTrail = req.stage(auditTrail, fmt.Sprintf("cdi_decision: %s", decision.Decision))
+	state.Metrics.Decisions.Inc(string(decision.Decision))
 
 	// STEP 3: Handle DENY - no tokens, no calls
 	if decision.Decision == cdi.DENY {
-		auditTrail = append(auditTrail, "deny_terminal")
+		state.Metrics.Denials.Inc("input", decision.Reason)
+		state.observePosture(posture.SignalDeny, decision.Reason)
+		auditTrail = req.stage(auditTrail, "deny_terminal")
 		return &Response{
 			Success: false,
 			Error:   fmt.Sprintf("request denied: %s", decision.Reason),
@@ -75,9 +215,25 @@ func Execute(req *Request, state *SystemState) (*Response, error) {
 		}, nil
 	}
 
-	// STEP 4: Mint capability tokens (ALLOW or DEGRADE)
-	auditTrail = append(auditTrail, "token_mint_start")
-	token, err := mintToken(decision, labeledRequest, state)
+	// The posture policy at the current level bounds the token, the
+	// output decision, and egress for the rest of the request
+	posturePolicy, err := state.GovernanceCapsule.PostureLevelPolicy(postureLevel)
+	if err != nil {
+		return &Response{
+			Success: false,
+			Error:   fmt.Sprintf("posture_policy_failed: %v", err),
+			AuditTrail: auditTrail,
+		}, err
+	}
+
+	// STEP 4: Mint capability tokens (ALLOW or DEGRADE), unless a STOP
+	// is in force until resumed
+	auditTrail = req.stage(auditTrail, "token_mint_start")
+	err = state.mintHalted(actor.PrincipalID)
+	var token *capabilities.Token
+	if err == nil {
+		token, err = mintToken(decision, labeledRequest, posturePolicy, actor, state)
+	}
 	if err != nil {
 		return &Response{
 			Success: false,
@@ -85,12 +241,22 @@ func Execute(req *Request, state *SystemState) (*Response, error) {
 			AuditTrail: auditTrail,
 		}, err
 	}
-	state.AddToken(token)
-	auditTrail = append(auditTrail, "token_mint_complete")
+	state.addToken(token, requestID)
+	if session != nil {
+		if err := session.addToken(token.Digest); err != nil {
+			state.revokeTokens([]string{token.Digest})
+			return &Response{
+				Success: false,
+				Error:   fmt.Sprintf("token_mint_failed: %v", err),
+				AuditTrail: auditTrail,
+			}, err
+		}
+	}
+	auditTrail = req.stage(auditTrail, "token_mint_complete")
 
 	// STEP 5: Kernel execute - invoke adapters with token
-	auditTrail = append(auditTrail, "kernel_execute_start")
-	outputContent, err := kernelExecute(token, labeledRequest, state)
+	auditTrail = req.stage(auditTrail, "kernel_execute_start")
+	outputContent, err := kernelExecute(token, labeledRequest, postureLevel, state, actor)
 	if err != nil {
 		return &Response{
 			Success: false,
@@ -98,22 +264,32 @@ func Execute(req *Request, state *SystemState) (*Response, error) {
 			AuditTrail: auditTrail,
 		}, err
 	}
-	auditTrail = append(auditTrail, "kernel_execute_complete")
+	auditTrail = req.stage(auditTrail, "kernel_execute_complete")
 
 	// STEP 6: CDI output decision - check output before egress
-	auditTrail = append(auditTrail, "cdi_output_decision_start")
-	outputDecision, err := cdi.DecideOutput(outputContent, labeledRequest.SensitivityLevel, state.PostureLevel)
+	auditTrail = req.stage(auditTrail, "cdi_output_decision_start")
+	outputDecision, err := cdi.DecideOutputWithPolicy(outputContent, labeledRequest.SensitivityLevel, posturePolicy)
+	if err == nil {
+		outputHash := sha256.Sum256([]byte(outputContent))
+		state.AuditLedger.AppendEgressDecision(actor, string(outputDecision.Decision), hex.EncodeToString(outputHash[:]), outputDecision.Reason)
+	}
 	if err != nil || outputDecision.Decision == cdi.DENY {
+		reason := "output_decision_failed"
+		if err == nil {
+			reason = outputDecision.Reason
+		}
+		state.Metrics.Denials.Inc("output", reason)
+		state.observePosture(posture.SignalDeny, reason)
 		return &Response{
 			Success: false,
 			Error:   "output blocked by CDI",
 			AuditTrail: auditTrail,
 		}, nil
 	}
-	auditTrail = append(auditTrail, "cdi_output_decision_complete")
+	auditTrail = req.stage(auditTrail, "cdi_output_decision_complete")
 
 	// STEP 7: CIF Egress - apply leak control and redaction
-	auditTrail = append(auditTrail, "cif_egress_start")
+	auditTrail = req.stage(auditTrail, "cif_egress_start")
 	outputArtifact := &cif.OutputArtifact{
 		Content:          outputContent,
 		SensitivityLevel: labeledRequest.SensitivityLevel,
@@ -121,7 +297,10 @@ func Execute(req *Request, state *SystemState) (*Response, error) {
 		Metadata:         map[string]interface{}{},
 	}
 
-	finalResponse, err := cif.Egress(outputArtifact, state.PostureLevel, 10000) // 10KB leak budget
+	if session != nil {
+		posturePolicy.LeakBudget = session.charge(outputArtifact.LeakBudgetUsed, posturePolicy.LeakBudget)
+	}
+	finalResponse, err := cif.EgressDeclassified(outputArtifact, posturePolicy, state.declassified)
 	if err != nil {
 		return &Response{
 			Success: false,
@@ -129,7 +308,11 @@ func Execute(req *Request, state *SystemState) (*Response, error) {
 			AuditTrail: auditTrail,
 		}, err
 	}
-	auditTrail = append(auditTrail, "cif_egress_complete")
+	if finalResponse.Declassified {
+		state.AuditLedger.AppendEgressDecision(actor, string(cdi.ALLOW), finalResponse.OutputHash, ReasonDeclassified)
+	}
+	state.Metrics.LeakBudgetConsumed.Add(float64(outputArtifact.LeakBudgetUsed))
+	auditTrail = req.stage(auditTrail, "cif_egress_complete")
 
 	// STEP 8: Return user response
 	return &Response{
@@ -140,71 +323,122 @@ func Execute(req *Request, state *SystemState) (*Response, error) {
 	}, nil
 }
 
-// mintToken creates a capability token after CDI decision
-func mintToken(decision *cdi.DecisionResult, request *cif.LabeledRequest, state *SystemState) (*capabilities.Token, error) {
-	scope := decision.DegradedScope
+// mintToken creates a capability token after CDI decision.
+// WHY: Scope, TTL, limits, and posture bounds come from the governance
+// template selected by the decision reason; the decision and the posture
+// policy can only narrow it.
+func mintToken(decision *cdi.DecisionResult, request *cif.LabeledRequest, posturePolicy posture.LevelPolicy, actor audit.Attribution, state *SystemState) (*capabilities.Token, error) {
+	template, err := state.GovernanceCapsule.TemplateFor(decision.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := template.Scope
+	if len(decision.DegradedScope) > 0 {
+		scope = capabilities.IntersectScopes(template.Scope, decision.DegradedScope)
+	}
+	if len(posturePolicy.AllowedScopes) > 0 {
+		scope = capabilities.IntersectScopes(scope, posturePolicy.AllowedScopes)
+	}
 	if len(scope) == 0 {
-		scope = []string{"*"} // default full scope for ALLOW
+		return nil, fmt.Errorf("token template %s grants no scope for this decision", template.ID())
 	}
 
-	limits := capabilities.Limits{
-		MaxDepth:        10,
-		MaxBudget:       1000,
-		WorkspaceBounds: []string{},
+	postureBounds := template.PostureBounds
+	if decision.RequiredPosture > postureBounds.MinPosture {
+		postureBounds.MinPosture = decision.RequiredPosture
+	}
+	if postureBounds.MinPosture > postureBounds.MaxPosture {
+		return nil, fmt.Errorf("token template %s does not permit posture %d", template.ID(), decision.RequiredPosture)
 	}
 
-	postureBounds := capabilities.PostureBounds{
-		MinPosture: decision.RequiredPosture,
-		MaxPosture: 4, // P4 is maximum
+	provenance := capabilities.Provenance{
+		RequestHash: request.InputHash,
+		DecisionID:  decision.DecisionID,
+		TemplateID:  template.ID(),
 	}
 
-	token, err := capabilities.Mint(
+	token, err := capabilities.MintWithProvenance(
 		"kernel",
-		state.IdentityCapsule.PrincipalID,
+		actor.PrincipalID,
 		"adapters",
 		scope,
-		limits,
-		5*time.Minute, // 5 minute TTL
+		template.Limits,
+		template.TTL,
 		postureBounds,
-		state.IdentityCapsule.NamespaceID,
-		state.IdentityCapsule.PrincipalID,
+		actor.NamespaceID,
+		actor.PrincipalID,
+		provenance,
 	)
+	if err != nil {
+		return nil, err
+	}
+	if err := state.signToken(token); err != nil {
+		return nil, err
+	}
 
-	return token, err
+	return token, nil
 }
 
 // kernelExecute invokes adapters with the capability token.
 // WHY: Single chokepoint - all adapter calls go through here.
-func kernelExecute(token *capabilities.Token, request *cif.LabeledRequest, state *SystemState) (string, error) {
+func kernelExecute(token *capabilities.Token, request *cif.LabeledRequest, postureLevel int, state *SystemState, actor audit.Attribution) (string, error) {
 	// Check STOP before executing
-	if token.RevokedAt != nil {
+	if token.RevokedAt != nil || state.TokenRevoked(token.Digest) {
 		return "", fmt.Errorf("token revoked - STOP dominance")
 	}
 
-	// For now, use a mock adapter
-	// In production, this would route to real model/tool adapters
-	adapterName := "mock_adapter"
+	adapterName := state.ModelAdapter
 
 	params := map[string]interface{}{
-		"input": request.SanitizedInput,
+		capabilities.ParamInput: request.SanitizedInput,
 	}
 
-	result, err := state.AdapterRegistry.Invoke(adapterName, token, state.PostureLevel, params)
+	started := time.Now()
+	result, err := state.AdapterRegistry.InvokeInNamespace(context.Background(), actor.NamespaceID, adapterName, token, postureLevel, params)
+	state.Metrics.AdapterLatency.Observe(time.Since(started).Seconds(), adapterName, fmt.Sprint(err == nil))
 	if err != nil {
+		var foreign *adapters.NamespaceError
+		if errors.As(err, &foreign) {
+			state.AuditLedger.AppendNamespaceViolation(actor, "adapter:"+adapterName, foreign.TokenNamespace, token.Digest)
+			return "", err
+		}
+		var throttled *adapters.ThrottleError
+		if errors.As(err, &throttled) {
+			state.AuditLedger.AppendAdapterThrottle(actor, adapterName, token.Digest, throttled.Reason)
+			state.Metrics.AdapterThrottles.Inc(adapterName, throttled.Reason)
+		}
 		// Log failed attempt
-		state.AuditLedger.AppendAdapterAttempt(adapterName, false, token.Digest)
+		var timedOut *adapters.TimeoutError
+		if errors.As(err, &timedOut) {
+			state.AuditLedger.AppendAdapterTimeout(actor, adapterName, token.Digest, timedOut.Timeout)
+			state.Metrics.AdapterTimeouts.Inc(adapterName)
+		} else {
+			state.AuditLedger.AppendAdapterAttempt(actor, adapterName, false, token.Digest)
+		}
 		return "", err
 	}
 
 	// Log successful attempt
-	state.AuditLedger.AppendAdapterAttempt(adapterName, true, token.Digest)
-
-	// Extract content from result
-	if resultMap, ok := result.(map[string]interface{}); ok {
-		if message, ok := resultMap["message"].(string); ok {
-			return message, nil
-		}
+	state.AuditLedger.AppendAdapterAttempt(actor, adapterName, true, token.Digest)
+	if result.Exchange != nil {
+		state.AuditLedger.AppendAdapterExchange(actor, adapterName, token.Digest, result.Exchange.RequestHash, result.Exchange.ResponseHash)
+	}
+	if result.Write != nil {
+		state.AuditLedger.AppendAdapterWrite(actor, adapterName, token.Digest, result.Write.Target, result.Write.ContentHash)
 	}
+	if result.Query != nil {
+		state.AuditLedger.AppendAdapterQuery(actor, adapterName, token.Digest, result.Query.Hash, result.Query.Fingerprint, result.Query.Tables)
+	}
+
+	return result.Content, nil
+}
 
-	return fmt.Sprintf("result: %v", result), nil
+// newRequestID returns a random correlation ID for a request
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("kernel: request ID entropy unavailable: %v", err))
+	}
+	return hex.EncodeToString(b)
 }
@@ -2,9 +2,22 @@
 package kernel
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/user/oi/kernel-go/internal/adapters"
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/memory"
+	"github.com/user/oi/kernel-go/internal/posture"
+	"github.com/user/oi/kernel-go/internal/signing"
 )
 
 // TestPipelineOrder_CIF_CDI_kernel_CDI_CIF proves DI-1: judge before power
@@ -202,6 +215,41 @@ func TestStopRevokesAllTokens(t *testing.T) {
 	}
 }
 
+// TestPrincipalStopRevokesOnlyTheirCapability proves one principal's STOP
+// stops their requests' tokens and leaves another principal's working
+func TestPrincipalStopRevokesOnlyTheirCapability(t *testing.T) {
+	state := newSessionKernel(t)
+	alice, _ := state.Sessions.Open("alice", "tenant_a")
+	bob, _ := state.Sessions.Open("bob", "tenant_a")
+	for _, session := range []*Session{alice, bob} {
+		if resp, err := Execute(&Request{RawInput: "test request", SessionID: session.ID}, state); err != nil || !resp.Success {
+			t.Fatalf("request: %+v (%v)", resp, err)
+		}
+	}
+
+	if revoked := state.RevokeTokensFor("alice"); revoked != 1 {
+		t.Fatalf("expected alice's one token revoked, got %d", revoked)
+	}
+	for digest, token := range state.ActiveCapabilityTokens {
+		stopped := token.RevokedAt != nil && state.TokenRevoked(digest)
+		if stopped != (token.PrincipalID == "alice") {
+			t.Fatalf("only alice's token may be stopped, %s's is stopped=%v", token.PrincipalID, stopped)
+		}
+	}
+	if resp, err := Execute(&Request{RawInput: "test request", SessionID: bob.ID}, state); err != nil || !resp.Success {
+		t.Fatalf("bob must keep working after alice's STOP: %+v (%v)", resp, err)
+	}
+
+	page, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"stop_event"}})
+	if len(page.Receipts) != 1 {
+		t.Fatalf("expected one STOP receipt, got %d", len(page.Receipts))
+	}
+	stop := page.Receipts[0]
+	if stop.EventData["stop_scope"] != audit.StopScopePrincipal || stop.EventData["stop_target"] != "alice" || stop.EventData["principal_id"] != "alice" {
+		t.Fatalf("the STOP receipt must name its scope and principal, got %+v", stop.EventData)
+	}
+}
+
 // TestMissingGovernanceDenies proves fail-closed behavior
 func TestMissingGovernanceDenies(t *testing.T) {
 	state := NewSystemState("test_principal", "test_namespace")
@@ -292,3 +340,633 @@ func TestLeakBudgetEnforcement(t *testing.T) {
 		t.Fatal("response should have content")
 	}
 }
+
+// TestAdapterSideEffectTracesToRequest proves every side effect has a lineage
+func TestAdapterSideEffectTracesToRequest(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+
+	mockAdapter := adapters.NewMockAdapter("mock_adapter")
+	state.AdapterRegistry.Register(mockAdapter)
+	state.GovernanceCapsule.Rules = map[string]interface{}{"exists": true}
+
+	req := &Request{
+		RawInput: "test request",
+		Metadata: map[string]interface{}{},
+	}
+
+	resp, err := Execute(req, state)
+	if err != nil || !resp.Success {
+		t.Fatalf("pipeline should succeed: %v", err)
+	}
+
+	var tokenDigest string
+	for _, receipt := range state.AuditLedger.GetReceipts() {
+		if receipt.EventType == "adapter_attempt" {
+			tokenDigest = receipt.EventData["token_digest"].(string)
+		}
+	}
+	if tokenDigest == "" {
+		t.Fatal("adapter attempt not found in audit log")
+	}
+
+	lineage, err := state.AuditLedger.Lineage(tokenDigest)
+	if err != nil {
+		t.Fatalf("side effect has no lineage: %v", err)
+	}
+
+	decision := lineage[len(lineage)-1]
+	if decision.EventType != "cdi_decision" {
+		t.Fatalf("lineage should end at cdi_decision, got %s", decision.EventType)
+	}
+
+	token := state.ActiveCapabilityTokens[tokenDigest]
+	if decision.EventData["input_hash"] != token.Provenance.RequestHash {
+		t.Fatal("decision input hash should match the token's request hash")
+	}
+	if decision.EventData["decision_id"] != token.Provenance.DecisionID {
+		t.Fatal("decision ID should match the token's decision ID")
+	}
+}
+
+// TestTokenMintedFromGovernanceTemplate proves minting is policy-driven
+func TestTokenMintedFromGovernanceTemplate(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+
+	mockAdapter := adapters.NewMockAdapter("mock_adapter")
+	state.AdapterRegistry.Register(mockAdapter)
+	state.GovernanceCapsule.Rules = map[string]interface{}{"exists": true}
+	state.GovernanceCapsule.TokenTemplates["narrow"] = TokenTemplate{
+		Name:          "narrow",
+		Version:       "v7",
+		Scope:         []string{"mock_adapter"},
+		TTL:           30 * time.Second,
+		Limits:        capabilities.Limits{MaxDepth: 1, MaxBudget: 5},
+		PostureBounds: capabilities.PostureBounds{MinPosture: 1, MaxPosture: 2},
+	}
+	state.GovernanceCapsule.TemplateSelectors["clean_low_sensitivity"] = "narrow"
+
+	resp, err := Execute(&Request{RawInput: "test request", Metadata: map[string]interface{}{}}, state)
+	if err != nil || !resp.Success {
+		t.Fatalf("pipeline should succeed: %v %s", err, resp.Error)
+	}
+
+	if len(state.ActiveCapabilityTokens) != 1 {
+		t.Fatalf("expected 1 token, got %d", len(state.ActiveCapabilityTokens))
+	}
+	for _, token := range state.ActiveCapabilityTokens {
+		if len(token.Scope) != 1 || token.Scope[0] != "mock_adapter" {
+			t.Fatalf("token scope should come from template, got %v", token.Scope)
+		}
+		if token.TTL != 30*time.Second || token.Limits.MaxBudget != 5 {
+			t.Fatal("token TTL and limits should come from template")
+		}
+		if token.Provenance.TemplateID != "narrow@v7" {
+			t.Fatalf("token should record template ID, got %s", token.Provenance.TemplateID)
+		}
+	}
+
+	foundTemplate := false
+	for _, receipt := range state.AuditLedger.GetReceipts() {
+		if receipt.EventType == "token_mint" && receipt.EventData["template_id"] == "narrow@v7" {
+			foundTemplate = true
+		}
+	}
+	if !foundTemplate {
+		t.Fatal("token_mint receipt should record template ID")
+	}
+}
+
+// TestMissingTokenTemplateFailsClosed proves no template means no token
+func TestMissingTokenTemplateFailsClosed(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+
+	mockAdapter := adapters.NewMockAdapter("mock_adapter")
+	state.AdapterRegistry.Register(mockAdapter)
+	state.GovernanceCapsule.Rules = map[string]interface{}{"exists": true}
+	state.GovernanceCapsule.TemplateSelectors = map[string]string{}
+
+	resp, err := Execute(&Request{RawInput: "test request", Metadata: map[string]interface{}{}}, state)
+	if err == nil || resp.Success {
+		t.Fatal("execution without a token template should fail")
+	}
+	if len(state.ActiveCapabilityTokens) != 0 {
+		t.Fatal("no token should be minted without a template")
+	}
+	if len(mockAdapter.GetInvocations()) != 0 {
+		t.Fatal("no adapter invocation should occur without a token")
+	}
+}
+
+// TestTemplatePostureCeilingFailsClosed proves templates bound posture
+func TestTemplatePostureCeilingFailsClosed(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+
+	mockAdapter := adapters.NewMockAdapter("mock_adapter")
+	state.AdapterRegistry.Register(mockAdapter)
+	state.GovernanceCapsule.Rules = map[string]interface{}{"exists": true}
+	template := state.GovernanceCapsule.TokenTemplates["standard"]
+	template.PostureBounds.MaxPosture = 2
+	state.GovernanceCapsule.TokenTemplates["standard"] = template
+	state.Posture.SetLevel(posture.P3, "test")
+
+	resp, _ := Execute(&Request{RawInput: "test request", Metadata: map[string]interface{}{}}, state)
+	if resp.Success {
+		t.Fatal("template should refuse to mint above its posture ceiling")
+	}
+	if len(mockAdapter.GetInvocations()) != 0 {
+		t.Fatal("no adapter invocation should occur")
+	}
+}
+
+// TestLedgerCheckpointsStoredAsEvidence proves checkpoints land in the evidence partition
+func TestLedgerCheckpointsStoredAsEvidence(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	state.AuditLedger.SetCheckpointPolicy(5, state.storeCheckpoint)
+
+	mockAdapter := adapters.NewMockAdapter("mock_adapter")
+	state.AdapterRegistry.Register(mockAdapter)
+	state.GovernanceCapsule.Rules = map[string]interface{}{"exists": true}
+
+	for i := 0; i < 3; i++ {
+		resp, err := Execute(&Request{RawInput: "test request", Metadata: map[string]interface{}{}}, state)
+		if err != nil || !resp.Success {
+			t.Fatalf("pipeline should succeed: %v", err)
+		}
+	}
+
+	checkpoints := state.AuditLedger.Checkpoints()
+	if len(checkpoints) == 0 {
+		t.Fatal("expected ledger checkpoints")
+	}
+	if _, err := state.MemoryManager.Read(memory.PartitionEvidence, checkpointEntryID(checkpoints[0])); err != nil {
+		t.Fatalf("checkpoint should be stored as evidence: %v", err)
+	}
+	if err := state.VerifyAuditCheckpoints(); err != nil {
+		t.Fatalf("checkpoints should verify against evidence: %v", err)
+	}
+}
+
+// TestAuditCheckpointsWalkEvidence proves checkpoint evidence the chain
+// does not record fails verification
+func TestAuditCheckpointsWalkEvidence(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	if err := state.VerifyAuditCheckpoints(); err != nil {
+		t.Fatalf("a ledger without checkpoints must verify: %v", err)
+	}
+	state.storeCheckpoint(audit.Checkpoint{StartSequence: 0, EndSequence: 1000, MerkleRoot: "root"})
+	if err := state.VerifyAuditCheckpoints(); err == nil {
+		t.Fatal("evidence of a checkpoint missing from the chain must fail verification")
+	}
+}
+
+// TestPipelineReceiptsAreSigned proves every receipt after genesis carries a kernel signature
+func TestPipelineReceiptsAreSigned(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	mockAdapter := adapters.NewMockAdapter("mock_adapter")
+	state.AdapterRegistry.Register(mockAdapter)
+	state.GovernanceCapsule.Rules = map[string]interface{}{"exists": true}
+
+	resp, err := Execute(&Request{RawInput: "test request", Metadata: map[string]interface{}{}}, state)
+	if err != nil || !resp.Success {
+		t.Fatalf("pipeline should succeed: %v", err)
+	}
+
+	receipts := state.AuditLedger.GetReceipts()
+	for _, receipt := range receipts[1:] {
+		if receipt.SignerKeyID != AuditKeyID || len(receipt.Signature) == 0 {
+			t.Fatalf("receipt %d (%s) should be signed by the kernel key", receipt.Sequence, receipt.EventType)
+		}
+	}
+	if valid, err := state.AuditLedger.Verify(); !valid {
+		t.Fatalf("signed ledger should verify: %v", err)
+	}
+}
+
+// TestPipelineReceiptsCarryAttribution proves every corridor receipt names the
+// principal, namespace, and request that produced it
+func TestPipelineReceiptsCarryAttribution(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	state.AdapterRegistry.Register(adapters.NewMockAdapter("mock_adapter"))
+	state.GovernanceCapsule.Rules = map[string]interface{}{"exists": true}
+
+	resp, err := Execute(&Request{RawInput: "test request", Metadata: map[string]interface{}{}, ID: "req_42"}, state)
+	if err != nil || !resp.Success {
+		t.Fatalf("pipeline should succeed: %v", err)
+	}
+	if resp.RequestID != "req_42" {
+		t.Fatalf("response should echo the request ID, got %q", resp.RequestID)
+	}
+
+	page, err := state.AuditLedger.Query(audit.ReceiptFilter{PrincipalID: "test_principal", NamespaceID: "test_namespace"})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	types := map[string]bool{}
+	for _, receipt := range page.Receipts {
+		if receipt.EventData["request_id"] != "req_42" {
+			t.Fatalf("%s receipt not correlated to the request", receipt.EventType)
+		}
+		types[receipt.EventType] = true
+	}
+	for _, eventType := range []string{"cdi_decision", "token_mint", "adapter_attempt"} {
+		if !types[eventType] {
+			t.Fatalf("missing attributed %s receipt", eventType)
+		}
+	}
+
+	generated, _ := Execute(&Request{RawInput: "test request", Metadata: map[string]interface{}{}}, state)
+	if generated.RequestID == "" || generated.RequestID == "req_42" {
+		t.Fatal("requests without an ID should get a fresh one")
+	}
+}
+
+// TestPipelineRecordsMetrics proves decisions, denials, adapter calls, tokens,
+// and leak budget are counted as requests flow through the corridor
+func TestPipelineRecordsMetrics(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	state.AdapterRegistry.Register(adapters.NewMockAdapter("mock_adapter"))
+
+	// No governance rules: CDI denies
+	state.GovernanceCapsule.Rules = nil
+	if resp, _ := Execute(&Request{RawInput: "test request", Metadata: map[string]interface{}{}}, state); resp.Success {
+		t.Fatal("request without governance should be denied")
+	}
+
+	state.GovernanceCapsule.Rules = map[string]interface{}{"exists": true}
+	state.CommitGovernance()
+	resp, err := Execute(&Request{RawInput: "test request", Metadata: map[string]interface{}{}}, state)
+	if err != nil || !resp.Success {
+		t.Fatalf("pipeline should succeed: %v", err)
+	}
+	state.RevokeAllTokens()
+
+	m := state.Metrics
+	if m.Decisions.Value("DENY") != 1 || m.Decisions.Value("ALLOW") != 1 {
+		t.Fatalf("expected one DENY and one ALLOW decision, got %v/%v", m.Decisions.Value("DENY"), m.Decisions.Value("ALLOW"))
+	}
+	if m.Denials.Value("input", "missing_governance") != 1 {
+		t.Fatal("input denial should be counted by reason")
+	}
+	if m.AdapterLatency.Count("mock_adapter", "true") != 1 {
+		t.Fatal("adapter call latency should be observed")
+	}
+	var exposition strings.Builder
+	m.Registry.WriteText(&exposition)
+	if !strings.Contains(exposition.String(), `oi_adapter_calls_total{adapter="mock_adapter",outcome="success"} 1`) {
+		t.Fatalf("adapter stats should be exported:\n%s", exposition.String())
+	}
+	if m.TokensMinted.Value("standard@v1") != 1 || m.TokensRevoked.Value() != 1 {
+		t.Fatalf("expected one minted and one revoked token, got %v/%v", m.TokensMinted.Value("standard@v1"), m.TokensRevoked.Value())
+	}
+	if m.LeakBudgetConsumed.Value() == 0 {
+		t.Fatal("egress should consume leak budget")
+	}
+
+	if err := state.VerifyAuditLedger(); err != nil {
+		t.Fatalf("untampered ledger should verify: %v", err)
+	}
+	if m.LedgerVerifyFailures.Value() != 0 {
+		t.Fatal("successful verification should not count as a failure")
+	}
+}
+
+// TestPipelinePseudonymousAudit proves a pseudonymous kernel keeps the
+// principal out of its receipts, including after attaching a new ledger
+func TestPipelinePseudonymousAudit(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	state.AdapterRegistry.Register(adapters.NewMockAdapter("mock_adapter"))
+	state.GovernanceCapsule.Rules = map[string]interface{}{"exists": true}
+
+	salt := make([]byte, audit.MinPseudonymSaltBytes)
+	p, err := audit.NewPseudonymizer("test_deploy", salt)
+	if err != nil {
+		t.Fatalf("pseudonymizer: %v", err)
+	}
+	state.SetAuditPseudonymizer(p)
+
+	signer, _ := signing.GenerateLocalSigner(AuditKeyID)
+	if err := state.AttachLedger(audit.NewLedger(), signer); err != nil {
+		t.Fatalf("attach ledger: %v", err)
+	}
+
+	resp, err := Execute(&Request{RawInput: "test request", Metadata: map[string]interface{}{}}, state)
+	if err != nil || !resp.Success {
+		t.Fatalf("pipeline should succeed: %v", err)
+	}
+
+	for _, receipt := range state.AuditLedger.GetReceipts() {
+		if receipt.EventData["principal_id"] == "test_principal" {
+			t.Fatalf("%s receipt carries the raw principal", receipt.EventType)
+		}
+	}
+	page, _ := state.AuditLedger.Query(audit.ReceiptFilter{PrincipalID: "test_principal", EventTypes: []string{"token_mint"}})
+	if len(page.Receipts) != 1 {
+		t.Fatal("token mint should be found by real principal")
+	}
+}
+
+// TestModelAdapterExchangeIsLedgered proves requests route to the configured
+// model adapter and its wire exchange is committed to the ledger
+func TestModelAdapterExchangeIsLedgered(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"model":"m","choices":[{"message":{"role":"assistant","content":"model reply"}}]}`)
+	}))
+	defer server.Close()
+
+	state := NewSystemState("test_principal", "test_namespace")
+	state.GovernanceCapsule.Rules = map[string]interface{}{"exists": true}
+	model, err := adapters.NewOpenAIAdapter(adapters.OpenAIConfig{Name: "openai", BaseURL: server.URL, Model: "m"})
+	if err != nil {
+		t.Fatalf("new adapter: %v", err)
+	}
+	state.AdapterRegistry.Register(model)
+	state.ModelAdapter = "openai"
+
+	resp, err := Execute(&Request{RawInput: "test request", Metadata: map[string]interface{}{}}, state)
+	if err != nil || !resp.Success {
+		t.Fatalf("pipeline should succeed: %v", err)
+	}
+	if resp.Content != "model reply" {
+		t.Fatalf("unexpected content: %q", resp.Content)
+	}
+
+	exchanges, err := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"adapter_exchange"}})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(exchanges.Receipts) != 1 || exchanges.Receipts[0].EventData["adapter"] != "openai" {
+		t.Fatalf("expected one openai exchange receipt, got %d", len(exchanges.Receipts))
+	}
+	if exchanges.Receipts[0].EventData["response_hash"] == "" {
+		t.Fatal("exchange receipt missing response hash")
+	}
+}
+
+// TestAdapterThrottleIsLedgered proves a call refused by an adapter rate
+// limit fails the request and leaves a warning receipt with the reason
+func TestAdapterThrottleIsLedgered(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	state.GovernanceCapsule.Rules = map[string]interface{}{"exists": true}
+	state.AdapterRegistry.Register(adapters.NewMockAdapter(state.ModelAdapter))
+	if err := state.AdapterRegistry.SetRateLimit(state.ModelAdapter, adapters.RateLimit{QPS: 0.001, Burst: 1}); err != nil {
+		t.Fatalf("set rate limit: %v", err)
+	}
+
+	if resp, err := Execute(&Request{RawInput: "first request", Metadata: map[string]interface{}{}}, state); err != nil || !resp.Success {
+		t.Fatalf("first request should succeed: %v", err)
+	}
+	if resp, _ := Execute(&Request{RawInput: "second request", Metadata: map[string]interface{}{}}, state); resp.Success {
+		t.Fatal("second request should be throttled")
+	}
+
+	throttles, err := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"adapter_throttle"}})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(throttles.Receipts) != 1 || throttles.Receipts[0].EventData["reason"] != adapters.ThrottleRate {
+		t.Fatalf("expected one rate throttle receipt, got %d", len(throttles.Receipts))
+	}
+	if throttles.Receipts[0].Severity != audit.SeverityWarn {
+		t.Fatalf("throttle receipts should be warnings, got %s", throttles.Receipts[0].Severity)
+	}
+}
+
+// hangingAdapter never returns until its context is done
+type hangingAdapter struct{ *adapters.MockAdapter }
+
+func (h hangingAdapter) Invoke(ctx context.Context, _ *capabilities.Token, _ map[string]interface{}) (*adapters.AdapterResult, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// TestAdapterTimeoutIsLedgered proves a stuck adapter is abandoned at its
+// deadline and the timeout is recorded as a failed adapter_attempt
+func TestAdapterTimeoutIsLedgered(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	state.GovernanceCapsule.Rules = map[string]interface{}{"exists": true}
+	state.AdapterRegistry.Register(hangingAdapter{adapters.NewMockAdapter(state.ModelAdapter)})
+	if err := state.AdapterRegistry.SetTimeout(state.ModelAdapter, 20*time.Millisecond); err != nil {
+		t.Fatalf("set timeout: %v", err)
+	}
+
+	resp, err := Execute(&Request{RawInput: "stuck request", Metadata: map[string]interface{}{}}, state)
+	var timedOut *adapters.TimeoutError
+	if resp.Success || !errors.As(err, &timedOut) {
+		t.Fatalf("request should fail with a timeout, got %v", err)
+	}
+
+	attempts, err := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"adapter_attempt"}})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(attempts.Receipts) != 1 {
+		t.Fatalf("expected one adapter_attempt receipt, got %d", len(attempts.Receipts))
+	}
+	data := attempts.Receipts[0].EventData
+	if data["accepted"] != false || data["outcome"] != "timeout" {
+		t.Fatalf("timeout should be a failed attempt: %v", data)
+	}
+}
+
+// failingAdapter always fails, standing in for an unavailable backend
+type failingAdapter struct{ *adapters.MockAdapter }
+
+func (f failingAdapter) Invoke(context.Context, *capabilities.Token, map[string]interface{}) (*adapters.AdapterResult, error) {
+	return nil, fmt.Errorf("backend unavailable")
+}
+
+// TestBreakerStateChangeIsLedgered proves a tripped adapter breaker leaves
+// a warning receipt and later requests fail fast
+func TestBreakerStateChangeIsLedgered(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	state.GovernanceCapsule.Rules = map[string]interface{}{"exists": true}
+	state.AdapterRegistry.Register(failingAdapter{adapters.NewMockAdapter(state.ModelAdapter)})
+	if err := state.AdapterRegistry.SetCircuitBreaker(state.ModelAdapter, adapters.BreakerConfig{Window: 1, MinCalls: 1, ErrorRate: 1}); err != nil {
+		t.Fatalf("set breaker: %v", err)
+	}
+
+	Execute(&Request{RawInput: "first request", Metadata: map[string]interface{}{}}, state)
+	_, err := Execute(&Request{RawInput: "second request", Metadata: map[string]interface{}{}}, state)
+	var open *adapters.BreakerOpenError
+	if !errors.As(err, &open) {
+		t.Fatalf("second request should fail fast, got %v", err)
+	}
+
+	changes, err := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"breaker_state_change"}})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(changes.Receipts) != 1 || changes.Receipts[0].EventData["to_state"] != adapters.BreakerOpen {
+		t.Fatalf("expected one breaker open receipt, got %d", len(changes.Receipts))
+	}
+	if changes.Receipts[0].Severity != audit.SeverityWarn {
+		t.Fatalf("breaker open receipts should be warnings, got %s", changes.Receipts[0].Severity)
+	}
+}
+
+// TestAdapterLifecycleIsLedgered proves swapping an adapter leaves
+// receipts for the replacement and the drained old instance
+func TestAdapterLifecycleIsLedgered(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	state.GovernanceCapsule.Rules = map[string]interface{}{"exists": true}
+	state.AdapterRegistry.Register(adapters.NewMockAdapter(state.ModelAdapter))
+
+	if err := state.AdapterRegistry.Replace(context.Background(), adapters.NewMockAdapter(state.ModelAdapter)); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	if resp, err := Execute(&Request{RawInput: "after rotation", Metadata: map[string]interface{}{}}, state); err != nil || !resp.Success {
+		t.Fatalf("requests should reach the replacement: %v", err)
+	}
+
+	changes, err := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"adapter_lifecycle"}})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(changes.Receipts) != 2 ||
+		changes.Receipts[0].EventData["action"] != adapters.AdapterReplaced ||
+		changes.Receipts[1].EventData["action"] != adapters.AdapterDrained {
+		t.Fatalf("expected replaced and drained receipts, got %d", len(changes.Receipts))
+	}
+}
+
+// TestMemoryOperationsAreLedgered proves memory writes, protected reads,
+// promotions, and deletions each leave a receipt with the content hash
+func TestMemoryOperationsAreLedgered(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	state.MemoryManager.Write(memory.PartitionDurable, "note", "remember this", nil)
+	state.MemoryManager.Read(memory.PartitionDurable, "note")
+	state.MemoryManager.Write(memory.PartitionQuarantine, "fetched", "untrusted", nil)
+	if err := state.MemoryManager.PromoteFromQuarantine("fetched", reviewedRecord(t, state, "fetched", memory.VerdictPromote)); err != nil {
+		t.Fatalf("promote: %v", err)
+	}
+	state.MemoryManager.Delete(memory.PartitionDurable, "note")
+
+	result, err := state.AuditLedger.Query(audit.ReceiptFilter{
+		EventTypes: []string{"memory_write", "memory_read", "memory_promotion", "memory_deletion"}})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	want := []string{"memory_write", "memory_read", "memory_write", "memory_promotion", "memory_deletion"}
+	if len(result.Receipts) != len(want) {
+		t.Fatalf("expected %d memory receipts, got %d", len(want), len(result.Receipts))
+	}
+	for i, receipt := range result.Receipts {
+		if receipt.EventType != want[i] || receipt.EventData["content_hash"] == "" || receipt.EventData["entry_id"] == "" {
+			t.Fatalf("receipt %d: expected %s with hash and entry, got %s %v", i, want[i], receipt.EventType, receipt.EventData)
+		}
+	}
+	if result.Receipts[4].Severity != audit.SeverityWarn {
+		t.Fatalf("deletions should be warnings, got %s", result.Receipts[4].Severity)
+	}
+}
+
+// reviewedRecord trusts a "reviewer" principal in governance and returns
+// its signed verdict on a quarantined entry
+func reviewedRecord(t *testing.T, state *SystemState, id string, verdict string) memory.VerificationRecord {
+	t.Helper()
+	signer, _ := signing.GenerateLocalSigner("reviewer_key")
+	keys := signing.NewKeyRing()
+	keys.AddSigner(signer)
+	state.SetQuarantineVerifierKeys(keys)
+	state.GovernanceCapsule.QuarantineVerifiers["reviewer"] = "reviewer_key"
+
+	hash, err := state.MemoryManager.QuarantinedHash(id)
+	if err != nil {
+		t.Fatalf("quarantined hash: %v", err)
+	}
+	record := memory.VerificationRecord{EntryID: id, ContentHash: hash, Verdict: verdict,
+		Verifier: "reviewer", Method: memory.MethodHumanReview}
+	if err := record.Sign(signer); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return record
+}
+
+// TestQuarantineVerificationFollowsGovernance proves the governance
+// capsule decides who may close quarantined entries, and rejections are
+// ledgered with the verifier
+func TestQuarantineVerificationFollowsGovernance(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	state.MemoryManager.Write(memory.PartitionQuarantine, "fetched", "untrusted", nil)
+	state.MemoryManager.Write(memory.PartitionQuarantine, "spam", "buy now", nil)
+
+	record := reviewedRecord(t, state, "fetched", memory.VerdictPromote)
+	state.GovernanceCapsule.VerificationMethods = []string{memory.MethodSourceSignature}
+	if err := state.MemoryManager.PromoteFromQuarantine("fetched", record); err == nil {
+		t.Fatal("a method governance no longer accepts must be refused")
+	}
+	state.GovernanceCapsule.VerificationMethods = []string{memory.MethodHumanReview}
+	delete(state.GovernanceCapsule.QuarantineVerifiers, "reviewer")
+	if err := state.MemoryManager.PromoteFromQuarantine("fetched", record); err == nil {
+		t.Fatal("a verifier governance removed must be refused")
+	}
+
+	if err := state.MemoryManager.RejectQuarantined("spam", reviewedRecord(t, state, "spam", memory.VerdictReject)); err != nil {
+		t.Fatalf("reject: %v", err)
+	}
+	closed, err := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"quarantine_closed"}})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(closed.Receipts) != 1 || closed.Receipts[0].EventData["outcome"] != memory.QuarantineRejected ||
+		closed.Receipts[0].EventData["verifier"] != "reviewer" {
+		t.Fatalf("expected one rejection receipt naming the verifier, got %d", len(closed.Receipts))
+	}
+}
+
+// TestMemoryQuotaRefusalsAreLedgered proves a write over quota leaves a
+// warning receipt naming the principal
+func TestMemoryQuotaRefusalsAreLedgered(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	state.MemoryManager.SetQuota(memory.PartitionDurable, memory.Quota{MaxEntries: 1})
+	state.MemoryManager.WriteAs("tenant_a", memory.PartitionDurable, "first", "kept", nil)
+	if err := state.MemoryManager.WriteAs("tenant_a", memory.PartitionDurable, "second", "refused", nil); err == nil {
+		t.Fatal("the second entry must exceed the quota")
+	}
+
+	refused, err := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"memory_quota_exceeded"}})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(refused.Receipts) != 1 || refused.Receipts[0].EventData["quota_principal"] != "tenant_a" ||
+		refused.Receipts[0].Severity != audit.SeverityWarn {
+		t.Fatalf("expected one warning receipt for tenant_a, got %d", len(refused.Receipts))
+	}
+}
+
+// TestMemoryIsScopedToTheKernelNamespace proves the kernel's memory is its
+// namespace's view, and operations through another view are ledgered
+// under that namespace
+func TestMemoryIsScopedToTheKernelNamespace(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	other := state.MemoryManager.Namespace("other_namespace")
+	other.Write(memory.PartitionDurable, "note", "not yours", nil)
+
+	if _, err := state.MemoryManager.Read(memory.PartitionDurable, "note"); err == nil {
+		t.Fatal("the kernel must not read another namespace's memory")
+	}
+	writes, err := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"memory_write"}})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(writes.Receipts) != 1 || writes.Receipts[0].EventData["namespace_id"] != "other_namespace" {
+		t.Fatalf("expected one write ledgered under other_namespace, got %d", len(writes.Receipts))
+	}
+}
+
+// TestKernelMemoryReadsFollowPosture proves the kernel's memory reads
+// redact high-sensitivity entries once the posture tightens
+func TestKernelMemoryReadsFollowPosture(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	state.MemoryManager.Write(memory.PartitionDurable, "recovery", "recovery code 1234", map[string]interface{}{memory.MetadataSensitivity: "high"})
+
+	entry, err := state.ReadMemory(memory.PartitionDurable, "recovery")
+	if err != nil || entry.Content != "recovery code 1234" {
+		t.Fatalf("P1 may read the entry: %+v (%v)", entry, err)
+	}
+	state.Posture.SetLevel(posture.P4, "test")
+	entry, err = state.ReadMemory(memory.PartitionDurable, "recovery")
+	if err != nil || strings.Contains(entry.Content, "1234") || entry.Metadata[memory.MetadataRedaction] == nil {
+		t.Fatalf("P4 must not read the raw entry: %+v (%v)", entry, err)
+	}
+}
@@ -0,0 +1,130 @@
+// WHY: The posture controller decides when to tighten; the kernel feeds
+// it the signals the corridor sees. Every transition of the kernel's
+// posture, automatic or by hand, leaves a posture_change receipt, and a
+// namespace can be held at a stricter posture than the rest. Lowering a
+// posture is a governed downgrade, never a bare assignment.
+package kernel
+
+import (
+	"fmt"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// ConsentPostureDowngrade is the consent lowering the posture needs
+const ConsentPostureDowngrade = "posture_downgrade"
+
+// observePosture reports a signal to the posture controller, if any
+func (s *SystemState) observePosture(kind string, detail string) {
+	if s.PostureController != nil {
+		s.PostureController.Observe(posture.Signal{Kind: kind, Detail: detail})
+	}
+}
+
+// recordPostureChange writes a receipt for a posture transition. Like
+// breaker changes, transitions carry no request ID.
+func (s *SystemState) recordPostureChange(transition posture.Transition) {
+	s.recordPostureTransition(s.attribution(""), transition)
+}
+
+// recordPostureTransition writes the receipt of transition under actor; a
+// downgrade's receipt names who asked for it and who approved it
+func (s *SystemState) recordPostureTransition(actor audit.Attribution, transition posture.Transition) {
+	if transition.RequestedBy != "" {
+		s.AuditLedger.AppendPostureDowngrade(actor, transition.FromLevel, transition.ToLevel, transition.Reason,
+			transition.RequestedBy, transition.ApprovedBy)
+		return
+	}
+	s.AuditLedger.AppendPostureChange(actor, transition.FromLevel, transition.ToLevel, transition.Reason)
+}
+
+// LowerPosture relaxes the posture of namespace, or the kernel's if
+// namespace is empty, as downgrade asks.
+// WHY: Lowering relaxes constraint, so it takes the posture_downgrade
+// consent and must satisfy the governance downgrade rules.
+func (s *SystemState) LowerPosture(namespace string, downgrade posture.Downgrade) error {
+	state := s.Posture
+	if namespace != "" {
+		state = s.NamespacePosture(namespace)
+	}
+	return s.lowerPosture(state, ConsentPostureDowngrade, downgrade)
+}
+
+// lowerPosture lowers state as downgrade asks, if consent is active and
+// the governance downgrade rules allow it
+func (s *SystemState) lowerPosture(state *posture.State, consent string, downgrade posture.Downgrade) error {
+	if !s.activeConsents()[consent] {
+		return fmt.Errorf("posture downgrade requires consent %s", consent)
+	}
+	_, err := state.Lower(downgrade, s.GovernanceCapsule.PostureDowngrade)
+	return err
+}
+
+// NamespacePosture returns the posture state of namespace, creating it at
+// P1. Its transitions are ledgered under the namespace.
+func (s *SystemState) NamespacePosture(namespace string) *posture.State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state, ok := s.namespacePostures[namespace]; ok {
+		return state
+	}
+	state := posture.NewState()
+	if s.clock != nil {
+		state.SetClock(s.clock)
+	}
+	state.OnTransition(func(transition posture.Transition) {
+		actor := s.attribution("")
+		actor.NamespaceID = namespace
+		s.recordPostureTransition(actor, transition)
+	})
+	if s.namespacePostures == nil {
+		s.namespacePostures = make(map[string]*posture.State)
+	}
+	s.namespacePostures[namespace] = state
+	return state
+}
+
+// PostureLevelFor returns the posture requests in namespace run at.
+// WHY: A namespace's posture can only add constraint, so a tenant is held
+// at the stricter of its own level and the kernel's.
+func (s *SystemState) PostureLevelFor(namespace string) int {
+	level := s.Posture.Level()
+	s.mu.RLock()
+	state, ok := s.namespacePostures[namespace]
+	s.mu.RUnlock()
+	if ok && state.Level() > level {
+		level = state.Level()
+	}
+	return level
+}
+
+// PostureLevel returns the posture the corridor runs at in the kernel's
+// own namespace
+func (s *SystemState) PostureLevel() int {
+	return s.PostureLevelFor(s.IdentityCapsule.NamespaceID)
+}
+
+// adapterAllowlist returns the adapters the governance posture policy
+// lets calls at level reach
+func (s *SystemState) adapterAllowlist(level int) (posture.AdapterAllowlist, error) {
+	policy, err := s.GovernanceCapsule.PostureLevelPolicy(level)
+	return policy.Adapters, err
+}
+
+// PostureLevelPolicy returns what the governance posture policy permits
+// at level. WHY: Fail-closed - a missing or malformed policy, or an
+// undefined level, permits nothing rather than falling back to a default.
+func (g *GovernanceCapsule) PostureLevelPolicy(level int) (posture.LevelPolicy, error) {
+	if g.PosturePolicy == nil {
+		return posture.LevelPolicy{}, fmt.Errorf("no posture policy defined")
+	}
+	if err := g.PosturePolicy.Validate(); err != nil {
+		return posture.LevelPolicy{}, err
+	}
+	if !posture.IsValid(level) {
+		return posture.LevelPolicy{}, fmt.Errorf("posture %d is not defined", level)
+	}
+	return g.PosturePolicy[level], nil
+}
@@ -0,0 +1,190 @@
+// WHY: The profile store was an untyped map anything holding the state
+// could read or overwrite, so a user's history left the kernel at any
+// posture and nothing recorded who had touched it. Profiles are now typed
+// records read and written only through GetProfile and SetProfile, which
+// take a live token the kernel minted on a CDI allow, scoped to profile
+// access and held by the profile's own principal. Reads are redacted under
+// the posture policy in force, and every attempt is ledgered.
+package kernel
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// Token scopes that grant profile access
+const (
+	ScopeProfileRead  = "profile:read"
+	ScopeProfileWrite = "profile:write"
+)
+
+// Risk tiers a profile may carry
+const (
+	RiskTierStandard = "standard"
+	RiskTierElevated = "elevated"
+	RiskTierHigh     = "high"
+)
+
+// profileRiskTierSensitivity is the sensitivity a profile's risk tier is
+// read at; preferences are low
+const profileRiskTierSensitivity = posture.SensitivityMedium
+
+// Profile is what the kernel keeps about one principal
+type Profile struct {
+	PrincipalID string
+	Preferences map[string]string
+	RiskTier    string
+	History     []InteractionSummary
+	UpdatedAt   int64
+
+	// Redacted is set on a read that withheld part of the profile under
+	// the posture in force
+	Redacted bool
+}
+
+// InteractionSummary summarizes one past interaction
+type InteractionSummary struct {
+	Timestamp   int64
+	Summary     string
+	Sensitivity string
+}
+
+// ProfileStore holds a profile per principal
+type ProfileStore struct {
+	mu       sync.RWMutex
+	profiles map[string]Profile
+}
+
+// NewProfileStore returns an empty profile store
+func NewProfileStore() *ProfileStore {
+	return &ProfileStore{profiles: make(map[string]Profile)}
+}
+
+// Principals returns the principals with a profile, sorted
+func (p *ProfileStore) Principals() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	principals := make([]string, 0, len(p.profiles))
+	for principal := range p.profiles {
+		principals = append(principals, principal)
+	}
+	sort.Strings(principals)
+	return principals
+}
+
+// get returns a copy of the profile of principalID
+func (p *ProfileStore) get(principalID string) (Profile, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	profile, ok := p.profiles[principalID]
+	return copyProfile(profile), ok
+}
+
+// set stores a copy of profile
+func (p *ProfileStore) set(profile Profile) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.profiles[profile.PrincipalID] = copyProfile(profile)
+}
+
+// GetProfile returns the profile of principalID, redacted under the
+// posture policy in force. token must be a live token the kernel minted
+// to principalID, scoped to ScopeProfileRead.
+func (s *SystemState) GetProfile(principalID string, token *capabilities.Token) (Profile, error) {
+	held, err := s.checkProfileAccess(principalID, token, ScopeProfileRead)
+	if err != nil {
+		return Profile{}, err
+	}
+	profile, ok := s.ProfileStore.get(principalID)
+	if !ok {
+		return Profile{}, fmt.Errorf("no profile for principal %s", principalID)
+	}
+	policy, err := s.GovernanceCapsule.PostureLevelPolicy(s.PostureLevelFor(held.NamespaceID))
+	if err != nil {
+		return Profile{}, err
+	}
+	return redactProfile(profile, policy), nil
+}
+
+// SetProfile stores profile in place of the principal's last. token must
+// be a live token the kernel minted to the profile's principal, scoped to
+// ScopeProfileWrite.
+func (s *SystemState) SetProfile(profile Profile, token *capabilities.Token) error {
+	if err := validateProfile(profile); err != nil {
+		s.AuditLedger.AppendProfileAccess(s.tokenActor(token), profile.PrincipalID, ScopeProfileWrite, false)
+		return fmt.Errorf("profile refused: %w", err)
+	}
+	if _, err := s.checkProfileAccess(profile.PrincipalID, token, ScopeProfileWrite); err != nil {
+		return err
+	}
+	profile.UpdatedAt = s.now().Unix()
+	profile.Redacted = false
+	s.ProfileStore.set(profile)
+	return nil
+}
+
+// checkProfileAccess ledgers an attempt on the profile of principalID
+// with token for scope, and returns the kernel's record of token if the
+// attempt is allowed
+func (s *SystemState) checkProfileAccess(principalID string, token *capabilities.Token, scope string) (*capabilities.Token, error) {
+	held, err := s.heldToken(token, scope)
+	if err == nil && held.PrincipalID != principalID {
+		err = fmt.Errorf("token %s was not minted to principal %s", held.Digest, principalID)
+	}
+	s.AuditLedger.AppendProfileAccess(s.tokenActor(token), principalID, scope, err == nil)
+	if err != nil {
+		return nil, fmt.Errorf("profile access refused: %w", err)
+	}
+	return held, nil
+}
+
+// validateProfile reports why profile may not be stored, if it may not
+func validateProfile(profile Profile) error {
+	if profile.PrincipalID == "" {
+		return fmt.Errorf("a profile needs a principal")
+	}
+	switch profile.RiskTier {
+	case RiskTierStandard, RiskTierElevated, RiskTierHigh:
+	default:
+		return fmt.Errorf("risk tier %q is not defined", profile.RiskTier)
+	}
+	for i, summary := range profile.History {
+		if !posture.IsSensitivity(summary.Sensitivity) {
+			return fmt.Errorf("history %d: sensitivity %q is not defined", i, summary.Sensitivity)
+		}
+	}
+	return nil
+}
+
+// redactProfile withholds what policy does not permit: the risk tier, and
+// each history summary above its sensitivity ceiling
+func redactProfile(profile Profile, policy posture.LevelPolicy) Profile {
+	if !policy.Permits(profileRiskTierSensitivity) {
+		profile.RiskTier = ""
+		profile.Redacted = true
+	}
+	for i, summary := range profile.History {
+		if !policy.Permits(summary.Sensitivity) {
+			profile.History[i].Summary = ""
+			profile.Redacted = true
+		}
+	}
+	return profile
+}
+
+// copyProfile returns a copy of profile that shares nothing with it
+func copyProfile(profile Profile) Profile {
+	if profile.Preferences != nil {
+		preferences := make(map[string]string, len(profile.Preferences))
+		for key, value := range profile.Preferences {
+			preferences[key] = value
+		}
+		profile.Preferences = preferences
+	}
+	profile.History = append([]InteractionSummary(nil), profile.History...)
+	return profile
+}
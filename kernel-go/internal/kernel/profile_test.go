@@ -0,0 +1,119 @@
+// WHY: Proves profiles are read and written only with a live token scoped
+// to the access and minted to the profile's principal, that reads are
+// redacted under the posture in force, and that every attempt leaves a
+// receipt.
+package kernel
+
+import (
+	"testing"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// TestProfileAccessRequiresScopedPrincipalToken proves the token must
+// carry the scope, be the principal's own, and still be live
+func TestProfileAccessRequiresScopedPrincipalToken(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	profile := Profile{
+		PrincipalID: "alice",
+		Preferences: map[string]string{"language": "en"},
+		RiskTier:    RiskTierStandard,
+	}
+
+	reader := mintApprover(t, state, "alice", ScopeProfileRead)
+	if err := state.SetProfile(profile, reader); err == nil {
+		t.Fatal("a read token must not write a profile")
+	}
+	other := mintApprover(t, state, "bob", ScopeProfileWrite)
+	if err := state.SetProfile(profile, other); err == nil {
+		t.Fatal("another principal's token must not write a profile")
+	}
+	writer := mintApprover(t, state, "alice", ScopeProfileWrite)
+	if err := state.SetProfile(profile, writer); err != nil {
+		t.Fatalf("set profile: %v", err)
+	}
+	profile.Preferences["language"] = "fr"
+
+	got, err := state.GetProfile("alice", reader)
+	if err != nil {
+		t.Fatalf("get profile: %v", err)
+	}
+	if got.Preferences["language"] != "en" || got.UpdatedAt == 0 {
+		t.Fatalf("profile must be stored as set, got %+v", got)
+	}
+	if _, err := state.GetProfile("alice", writer); err == nil {
+		t.Fatal("a write token must not read a profile")
+	}
+	altered := *other
+	altered.PrincipalID = "alice"
+	if _, err := state.GetProfile("alice", &altered); err == nil {
+		t.Fatal("a token whose copy was altered to name the principal must be refused")
+	}
+
+	state.RevokeAllTokens()
+	if _, err := state.GetProfile("alice", reader); err == nil {
+		t.Fatal("a revoked token must not read a profile")
+	}
+
+	page, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"profile_access"}})
+	accepted := 0
+	for _, receipt := range page.Receipts {
+		if receipt.EventData["accepted"] == true {
+			accepted++
+		}
+	}
+	if len(page.Receipts) != 7 || accepted != 2 {
+		t.Fatalf("every attempt must be ledgered, got %d receipts, %d accepted", len(page.Receipts), accepted)
+	}
+	if actor := page.Receipts[5].EventData["principal_id"]; actor != "bob" {
+		t.Fatalf("an attempt must be ledgered as the kernel's record of the token, got %v", actor)
+	}
+}
+
+// TestProfileReadsAreRedactedByPosture proves what a posture's ceiling
+// does not permit is withheld on read and kept in the store
+func TestProfileReadsAreRedactedByPosture(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	writer := mintApprover(t, state, "alice", ScopeProfileWrite)
+	reader := mintApprover(t, state, "alice", ScopeProfileRead)
+
+	invalid := Profile{PrincipalID: "alice", RiskTier: "unknown"}
+	if err := state.SetProfile(invalid, writer); err == nil {
+		t.Fatal("an undefined risk tier must be refused")
+	}
+	profile := Profile{
+		PrincipalID: "alice",
+		RiskTier:    RiskTierElevated,
+		History: []InteractionSummary{
+			{Timestamp: 1, Summary: "asked about the weather", Sensitivity: posture.SensitivityLow},
+			{Timestamp: 2, Summary: "shared a diagnosis", Sensitivity: posture.SensitivityHigh},
+		},
+	}
+	if err := state.SetProfile(profile, writer); err != nil {
+		t.Fatalf("set profile: %v", err)
+	}
+
+	state.Posture.Raise(posture.P2, "test")
+	got, err := state.GetProfile("alice", reader)
+	if err != nil {
+		t.Fatalf("get profile: %v", err)
+	}
+	if !got.Redacted || got.RiskTier != RiskTierElevated || got.History[0].Summary == "" || got.History[1].Summary != "" {
+		t.Fatalf("P2 must withhold only the high-sensitivity summary, got %+v", got)
+	}
+
+	state.Posture.Raise(posture.P3, "test")
+	got, err = state.GetProfile("alice", reader)
+	if err != nil {
+		t.Fatalf("get profile: %v", err)
+	}
+	if got.RiskTier != "" || got.History[0].Summary == "" {
+		t.Fatalf("P3 must withhold the risk tier, got %+v", got)
+	}
+
+	stored, _ := state.ProfileStore.get("alice")
+	if stored.RiskTier != RiskTierElevated || stored.History[1].Summary == "" || stored.Redacted {
+		t.Fatalf("redaction must not reach the store, got %+v", stored)
+	}
+}
@@ -0,0 +1,131 @@
+// WHY: Shutting a kernel down for an upgrade with a STOP cuts off every
+// request mid-corridor; shutting it down without one leaves live tokens
+// behind. Quiesce closes the corridor to new requests, lets the ones in
+// flight finish within a deadline, then revokes whatever tokens remain
+// and seals the ledger segment, so the next kernel starts from a clean
+// chain. The deadline keeps STOP dominant: a corridor still running when
+// it passes is cut off by a global STOP, which only Resume lifts.
+package kernel
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/capabilities"
+)
+
+// QuiesceSummary reports how a quiesce ended
+type QuiesceSummary struct {
+	// Drained is true if every corridor finished before the deadline;
+	// otherwise InFlight of them were cut off by a global STOP
+	Drained  bool
+	InFlight int
+
+	TokensRevoked  int
+	CallsCancelled int
+
+	// Segment is the ledger segment sealed last
+	Segment audit.Segment
+}
+
+// corridorGate counts requests inside the corridor and closes it to new
+// ones once the kernel quiesces
+type corridorGate struct {
+	mu       sync.Mutex
+	closed   bool
+	inFlight int
+	idle     chan struct{}
+}
+
+// enter admits a request, unless the gate is closed
+func (g *corridorGate) enter() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return fmt.Errorf("kernel is quiescing and accepts no new requests")
+	}
+	g.inFlight++
+	return nil
+}
+
+// leave lets a request out, signalling once the last leaves a closed gate
+func (g *corridorGate) leave() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.inFlight--
+	if g.closed && g.inFlight == 0 {
+		close(g.idle)
+	}
+}
+
+// close shuts the gate and returns a channel closed once no request is
+// in flight
+func (g *corridorGate) close() (<-chan struct{}, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return nil, fmt.Errorf("kernel is already quiescing")
+	}
+	g.closed = true
+	g.idle = make(chan struct{})
+	if g.inFlight == 0 {
+		close(g.idle)
+	}
+	return g.idle, nil
+}
+
+// isClosed reports whether the gate has closed
+func (g *corridorGate) isClosed() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.closed
+}
+
+// count returns the requests in flight
+func (g *corridorGate) count() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.inFlight
+}
+
+// Quiesce stops accepting requests, waits up to deadline for those in
+// flight to finish, then revokes every remaining token and seals the
+// ledger segment; a kernel quiesces only once
+func (s *SystemState) Quiesce(deadline time.Duration) (QuiesceSummary, error) {
+	if deadline < 0 {
+		return QuiesceSummary{}, fmt.Errorf("quiesce deadline must not be negative, got %s", deadline)
+	}
+	idle, err := s.corridors.close()
+	if err != nil {
+		return QuiesceSummary{}, err
+	}
+
+	timer := time.NewTimer(deadline)
+	defer timer.Stop()
+	summary := QuiesceSummary{Drained: true}
+	select {
+	case <-idle:
+	case <-timer.C:
+		summary.Drained = false
+		summary.InFlight = s.corridors.count()
+	}
+
+	reason := audit.StopReasonQuiesce
+	if !summary.Drained {
+		reason = audit.StopReasonOperatorAction
+	}
+	digests, cancelled := s.stopWhere(s.attribution(""), audit.StopScopeGlobal, "", reason, func(*capabilities.Token) bool {
+		return true
+	})
+	summary.TokensRevoked, summary.CallsCancelled = len(digests), cancelled
+	s.AuditLedger.AppendQuiesce(s.attribution(""), summary.Drained, summary.InFlight, len(digests))
+
+	segment, err := s.AuditLedger.Rotate()
+	summary.Segment = segment
+	if err != nil {
+		return summary, fmt.Errorf("seal ledger segment: %w", err)
+	}
+	return summary, nil
+}
@@ -0,0 +1,78 @@
+// WHY: Proves a quiescing kernel turns away new requests, lets those in
+// flight drain, and ends with no live token and a sealed ledger segment,
+// falling back to a global STOP when the deadline passes.
+package kernel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+)
+
+// TestQuiesceDrainsThenRevokes proves a drained quiesce revokes the
+// remaining tokens and seals the segment without halting a restart
+func TestQuiesceDrainsThenRevokes(t *testing.T) {
+	state := newSessionKernel(t)
+	if resp, err := Execute(&Request{RawInput: "test request"}, state); err != nil || !resp.Success {
+		t.Fatalf("request before quiesce: %+v (%v)", resp, err)
+	}
+	live := len(state.ActiveCapabilityTokens)
+
+	summary, err := state.Quiesce(time.Second)
+	if err != nil {
+		t.Fatalf("quiesce: %v", err)
+	}
+	if !summary.Drained || summary.TokensRevoked != live {
+		t.Fatalf("an idle kernel must drain and revoke its %d tokens, got %+v", live, summary)
+	}
+	for _, token := range state.ActiveCapabilityTokens {
+		if token.RevokedAt == nil {
+			t.Fatal("no token may outlive a quiesce")
+		}
+	}
+	if len(state.Halts()) != 0 {
+		t.Fatalf("a drained quiesce must not hold a STOP, got %+v", state.Halts())
+	}
+	if resp, err := Execute(&Request{RawInput: "test request"}, state); err == nil || resp.Success {
+		t.Fatal("a quiesced kernel must take no new requests")
+	}
+	if _, err := state.Quiesce(time.Second); err == nil {
+		t.Fatal("a kernel quiesces only once")
+	}
+
+	segments := state.AuditLedger.Segments()
+	if len(segments) != 1 || segments[0].Index != summary.Segment.Index {
+		t.Fatalf("the quiesce must seal the ledger segment, got %+v", segments)
+	}
+	page, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"quiesce"}})
+	if len(page.Receipts) != 1 || page.Receipts[0].Sequence > summary.Segment.EndSequence {
+		t.Fatalf("the quiesce receipt must fall inside the sealed segment, got %+v", page.Receipts)
+	}
+}
+
+// TestQuiesceStopsAtDeadline proves a corridor still in flight at the
+// deadline is cut off by a global STOP
+func TestQuiesceStopsAtDeadline(t *testing.T) {
+	state := newSessionKernel(t)
+	if err := state.corridors.enter(); err != nil {
+		t.Fatalf("enter: %v", err)
+	}
+
+	summary, err := state.Quiesce(10 * time.Millisecond)
+	if err != nil {
+		t.Fatalf("quiesce: %v", err)
+	}
+	if summary.Drained || summary.InFlight != 1 {
+		t.Fatalf("a corridor in flight at the deadline must be reported, got %+v", summary)
+	}
+	halts := state.Halts()
+	if len(halts) != 1 || halts[0].Scope != audit.StopScopeGlobal || halts[0].Reason != audit.StopReasonOperatorAction {
+		t.Fatalf("a passed deadline must end in a global STOP, got %+v", halts)
+	}
+	page, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"quiesce"}})
+	if len(page.Receipts) != 1 || page.Receipts[0].Severity != audit.SeverityWarn {
+		t.Fatalf("an undrained quiesce must be a warning, got %+v", page.Receipts)
+	}
+	state.corridors.leave()
+}
@@ -0,0 +1,301 @@
+// WHY: A STOP revoked the tokens in force but nothing kept the next
+// request from minting new ones, so a STOP pulled in panic or on an
+// integrity failure lasted one request. A global or principal STOP now
+// halts minting for what it covers, and the halt is kept with the
+// authority record so a restart does not lift it. Only Resume lifts it,
+// and only after a fresh governance check: integrity is not void, the
+// ledger verifies, and the capsule in force is the one committed. A Resume
+// may also withdraw every standing consent and take new ones, so nothing
+// consented before the STOP survives it unrenewed. Every Resume is
+// ledgered, refused or not.
+package kernel
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+)
+
+// Halt records a STOP no token may be minted under until Resume
+type Halt struct {
+	Scope     string // audit.StopScopeGlobal or audit.StopScopePrincipal
+	Target    string // the principal of a principal STOP
+	Reason    string // audit.StopReasonUserPanic, StopReasonIntegrityFailure, or StopReasonOperatorAction
+	StoppedAt int64
+}
+
+// ResumeRequest names the STOP a Resume lifts and what it is lifted on
+type ResumeRequest struct {
+	// Scope and Target name the STOP, as in StopRequest; a principal
+	// Resume with no target is the caller's own
+	Scope  string `json:"scope"`
+	Target string `json:"target,omitempty"`
+
+	// Justification says why capability may return; it is required and
+	// ledgered as a hash
+	Justification string `json:"justification"`
+
+	// Reconsent withdraws every standing consent and grants Consents in
+	// their place; it applies to a global Resume only
+	Reconsent bool           `json:"reconsent,omitempty"`
+	Consents  []ConsentGrant `json:"consents,omitempty"`
+}
+
+// ConsentGrant is one consent a Resume grants afresh
+type ConsentGrant struct {
+	Scope    string        `json:"scope"`
+	TTL      time.Duration `json:"ttl,omitempty"`
+	Evidence string        `json:"evidence"`
+}
+
+// ResumeRefusedError reports a Resume that was not carried out; Reason is
+// one of the StopRefused reasons
+type ResumeRefusedError struct {
+	Reason string
+	Err    error
+}
+
+func (e *ResumeRefusedError) Error() string {
+	return fmt.Sprintf("resume refused (%s): %v", e.Reason, e.Err)
+}
+
+func (e *ResumeRefusedError) Unwrap() error {
+	return e.Err
+}
+
+// haltKey is the key of the halt of scope on target
+func haltKey(scope string, target string) string {
+	if scope == audit.StopScopeGlobal {
+		return scope
+	}
+	return scope + ":" + target
+}
+
+// holdHalt records halt in the authority capsule; s.mu is held
+func (s *SystemState) holdHalt(halt Halt) {
+	if s.AuthorityCapsule.Halts == nil {
+		s.AuthorityCapsule.Halts = make(map[string]Halt)
+	}
+	s.AuthorityCapsule.Halts[haltKey(halt.Scope, halt.Target)] = halt
+}
+
+// Halts returns the STOPs in force, global first, then by principal
+func (s *SystemState) Halts() []Halt {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	halts := make([]Halt, 0, len(s.AuthorityCapsule.Halts))
+	for _, halt := range s.AuthorityCapsule.Halts {
+		halts = append(halts, halt)
+	}
+	sort.Slice(halts, func(i, j int) bool {
+		return haltKey(halts[i].Scope, halts[i].Target) < haltKey(halts[j].Scope, halts[j].Target)
+	})
+	return halts
+}
+
+// mintHalted reports why no token may be minted to principalID, if none
+// may
+func (s *SystemState) mintHalted(principalID string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, key := range []string{haltKey(audit.StopScopeGlobal, ""), haltKey(audit.StopScopePrincipal, principalID)} {
+		if halt, halted := s.AuthorityCapsule.Halts[key]; halted {
+			return fmt.Errorf("%s STOP (%s) in force until resumed", halt.Scope, halt.Reason)
+		}
+	}
+	return nil
+}
+
+// Resume lifts the STOP resume names, as the kernel's principal. WHY: A
+// global STOP can be pulled by anyone, so it is lifted only here, by the
+// operator holding the kernel, never over the admin surface.
+func (s *SystemState) Resume(resume ResumeRequest) error {
+	return s.resume(s.attribution(""), resume)
+}
+
+// InvokeResume lifts the caller's own principal STOP for the caller
+// bearer identifies
+func (s *SystemState) InvokeResume(bearer string, resume ResumeRequest) error {
+	actor := s.attribution("")
+	caller, err := s.authenticate(bearer, actor)
+	if err != nil {
+		s.AuditLedger.AppendStopResume(actor, resume.Scope, resume.Target, justificationHash(resume.Justification), StopRefusedUnauthenticated, audit.Governance{}, nil)
+		return &ResumeRefusedError{Reason: StopRefusedUnauthenticated, Err: err}
+	}
+	actor.PrincipalID, actor.NamespaceID = caller.PrincipalID, caller.NamespaceID
+
+	if resume.Scope == audit.StopScopePrincipal && resume.Target == "" {
+		resume.Target = caller.PrincipalID
+	}
+	if resume.Scope != audit.StopScopePrincipal || resume.Target != caller.PrincipalID {
+		err := &ResumeRefusedError{Reason: StopRefusedForbidden, Err: fmt.Errorf("principal %s may resume only their own STOP", caller.PrincipalID)}
+		s.AuditLedger.AppendStopResume(actor, resume.Scope, resume.Target, justificationHash(resume.Justification), err.Reason, audit.Governance{}, nil)
+		return err
+	}
+	return s.resume(actor, resume)
+}
+
+// resume carries out resume for actor and ledgers the result
+func (s *SystemState) resume(actor audit.Attribution, resume ResumeRequest) error {
+	justification := justificationHash(resume.Justification)
+	refuse := func(reason string, err error) error {
+		s.AuditLedger.AppendStopResume(actor, resume.Scope, resume.Target, justification, reason, audit.Governance{}, nil)
+		return &ResumeRefusedError{Reason: reason, Err: err}
+	}
+
+	if err := validateResume(resume); err != nil {
+		return refuse(StopRefusedInvalid, err)
+	}
+	key := haltKey(resume.Scope, resume.Target)
+	s.mu.RLock()
+	halt, halted := s.AuthorityCapsule.Halts[key]
+	s.mu.RUnlock()
+	if !halted {
+		return refuse(StopRefusedInvalid, fmt.Errorf("no %s STOP is in force", key))
+	}
+	governance, err := s.resumeGovernance(actor)
+	if err != nil {
+		return refuse(StopRefusedForbidden, err)
+	}
+
+	var reconsented []string
+	if resume.Reconsent {
+		if reconsented, err = s.reconsent(resume.Consents); err != nil {
+			return refuse(StopRefusedInvalid, err)
+		}
+	}
+
+	// WHY: A lift that cannot be written would be undone by a restart, so
+	// it is not reported done
+	s.mu.Lock()
+	delete(s.AuthorityCapsule.Halts, key)
+	s.mu.Unlock()
+	if err := s.persistAuthority(); err != nil {
+		s.mu.Lock()
+		s.holdHalt(halt)
+		s.mu.Unlock()
+		return refuse(StopRefusedInvalid, fmt.Errorf("resume not persisted: %w", err))
+	}
+
+	s.AuditLedger.AppendStopResume(actor, resume.Scope, resume.Target, justification, "", governance, reconsented)
+	return nil
+}
+
+// validateResume reports why resume is malformed, if it is
+func validateResume(resume ResumeRequest) error {
+	switch resume.Scope {
+	case audit.StopScopeGlobal:
+		if resume.Target != "" {
+			return fmt.Errorf("a global Resume takes no target")
+		}
+	case audit.StopScopePrincipal:
+		if resume.Target == "" {
+			return fmt.Errorf("a principal Resume needs a principal")
+		}
+		if resume.Reconsent {
+			return fmt.Errorf("consents are the kernel's; only a global Resume renews them")
+		}
+	default:
+		return fmt.Errorf("no STOP of scope %q is resumable", resume.Scope)
+	}
+	if resume.Justification == "" {
+		return fmt.Errorf("a Resume needs a justification")
+	}
+	if !resume.Reconsent && len(resume.Consents) > 0 {
+		return fmt.Errorf("consents are granted on a Resume only with reconsent")
+	}
+	return nil
+}
+
+// resumeGovernance checks the kernel may hold capability again and
+// returns the governance the Resume is made under
+func (s *SystemState) resumeGovernance(actor audit.Attribution) (audit.Governance, error) {
+	if s.GetIntegrityState() == IntegrityVoid {
+		return audit.Governance{}, fmt.Errorf("integrity is void")
+	}
+	if err := s.VerifyAuditLedger(); err != nil {
+		return audit.Governance{}, fmt.Errorf("audit ledger does not verify: %w", err)
+	}
+	bound, err := s.governanceBinding(actor)
+	if err != nil {
+		return audit.Governance{}, err
+	}
+	if bound.live != bound.CapsuleHash {
+		return audit.Governance{}, fmt.Errorf("governance capsule in force is not the one committed")
+	}
+	return bound.Governance, nil
+}
+
+// reconsent withdraws every standing consent, grants consents in their
+// place, and returns the scopes granted, sorted
+func (s *SystemState) reconsent(consents []ConsentGrant) ([]string, error) {
+	for _, grant := range consents {
+		if grant.Scope == "" || grant.Evidence == "" || grant.TTL < 0 {
+			return nil, fmt.Errorf("consent %q needs a scope, evidence, and a ttl that is not negative", grant.Scope)
+		}
+	}
+	for _, consent := range s.ListConsents() {
+		if err := s.RevokeConsent(consent.Scope); err != nil {
+			return nil, err
+		}
+	}
+	granted := make([]string, 0, len(consents))
+	for _, grant := range consents {
+		if err := s.GrantConsent(grant.Scope, grant.TTL, grant.Evidence); err != nil {
+			return nil, err
+		}
+		granted = append(granted, grant.Scope)
+	}
+	sort.Strings(granted)
+	return granted, nil
+}
+
+// justificationHash returns the hex SHA-256 of justification
+func justificationHash(justification string) string {
+	sum := sha256.Sum256([]byte(justification))
+	return hex.EncodeToString(sum[:])
+}
+
+// ResumeHandler serves InvokeResume as an admin endpoint. A POST carries a
+// JSON ResumeRequest and the caller's bearer token in Authorization; the
+// response is {"resumed": true}, or {"error": ...} with 400, 401, or 403
+// when the Resume is refused.
+func (s *SystemState) ResumeHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeStopResponse(w, http.StatusMethodNotAllowed, map[string]string{"error": "Resume takes POST"})
+			return
+		}
+		bearer, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		var resume ResumeRequest
+		decoder := json.NewDecoder(io.LimitReader(r.Body, maxStopRequestBytes))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&resume); err != nil {
+			writeStopResponse(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("Resume request: %v", err)})
+			return
+		}
+
+		err := s.InvokeResume(bearer, resume)
+		var refused *ResumeRefusedError
+		if err == nil {
+			writeStopResponse(w, http.StatusOK, map[string]bool{"resumed": true})
+			return
+		}
+		reason := StopRefusedInvalid
+		if errors.As(err, &refused) {
+			reason = refused.Reason
+		}
+		writeStopResponse(w, refusalStatus(reason), map[string]string{"error": err.Error()})
+	})
+}
@@ -0,0 +1,149 @@
+// WHY: Proves a STOP keeps new tokens from being minted, across a
+// restart, until a Resume that passes a fresh governance check, and that
+// STOPs and Resumes are ledgered with their reasons.
+package kernel
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+)
+
+// TestStopHaltsMintingUntilResumed proves a global STOP halts every
+// request until a justified Resume under committed governance lifts it
+func TestStopHaltsMintingUntilResumed(t *testing.T) {
+	state := newSessionKernel(t)
+	if resp, err := Execute(&Request{RawInput: "test request"}, state); err != nil || !resp.Success {
+		t.Fatalf("request before STOP: %+v (%v)", resp, err)
+	}
+
+	state.RevokeAllTokens()
+	if resp, err := Execute(&Request{RawInput: "test request"}, state); err == nil || resp.Success {
+		t.Fatal("no token may be minted while a STOP is in force")
+	}
+	halts := state.Halts()
+	if len(halts) != 1 || halts[0].Scope != audit.StopScopeGlobal || halts[0].Reason != audit.StopReasonUserPanic {
+		t.Fatalf("the STOP must be held as a halt with its reason, got %+v", halts)
+	}
+
+	var refused *ResumeRefusedError
+	if err := state.Resume(ResumeRequest{Scope: audit.StopScopeGlobal}); !errors.As(err, &refused) || refused.Reason != StopRefusedInvalid {
+		t.Fatalf("a Resume without a justification must be refused, got %v", err)
+	}
+	state.GovernanceCapsule.PolicyVersion = "uncommitted"
+	if err := state.Resume(ResumeRequest{Scope: audit.StopScopeGlobal, Justification: "false alarm"}); !errors.As(err, &refused) || refused.Reason != StopRefusedForbidden {
+		t.Fatalf("a Resume under uncommitted governance must be refused, got %v", err)
+	}
+	if err := state.CommitGovernance(); err != nil {
+		t.Fatalf("commit governance: %v", err)
+	}
+	if err := state.Resume(ResumeRequest{Scope: audit.StopScopeGlobal, Justification: "false alarm"}); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if resp, err := Execute(&Request{RawInput: "test request"}, state); err != nil || !resp.Success {
+		t.Fatalf("request after Resume: %+v (%v)", resp, err)
+	}
+	if err := state.Resume(ResumeRequest{Scope: audit.StopScopeGlobal, Justification: "again"}); err == nil {
+		t.Fatal("a Resume with no STOP in force must be refused")
+	}
+
+	page, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"stop_resume"}})
+	accepted := 0
+	for _, receipt := range page.Receipts {
+		if receipt.EventData["accepted"] == true {
+			accepted++
+			if receipt.EventData["capsule_hash"] == "" || receipt.EventData["justification_hash"] != justificationHash("false alarm") {
+				t.Fatalf("a Resume must be bound to its governance and justification, got %+v", receipt.EventData)
+			}
+		}
+	}
+	if len(page.Receipts) != 4 || accepted != 1 {
+		t.Fatalf("every Resume must be ledgered, got %d receipts, %d accepted", len(page.Receipts), accepted)
+	}
+	stops, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"stop_event"}})
+	if stops.Receipts[0].EventData["stop_reason"] != audit.StopReasonUserPanic {
+		t.Fatalf("the STOP receipt must record its reason, got %+v", stops.Receipts[0].EventData)
+	}
+}
+
+// TestResumeCanRenewConsents proves a Resume with reconsent leaves only
+// the consents it grants
+func TestResumeCanRenewConsents(t *testing.T) {
+	state := newSessionKernel(t)
+	if err := state.GrantConsent("calendar", 0, "clicked allow"); err != nil {
+		t.Fatalf("grant: %v", err)
+	}
+	state.RevokeAllTokens()
+
+	renewal := ResumeRequest{
+		Scope:         audit.StopScopeGlobal,
+		Justification: "user confirmed it was safe",
+		Reconsent:     true,
+		Consents:      []ConsentGrant{{Scope: "email", TTL: time.Hour, Evidence: "re-confirmed after STOP"}},
+	}
+	if err := state.Resume(renewal); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	consents := state.ListConsents()
+	if len(consents) != 1 || consents[0].Scope != "email" {
+		t.Fatalf("only the renewed consent may stand, got %+v", consents)
+	}
+
+	if err := state.Resume(ResumeRequest{Scope: audit.StopScopePrincipal, Target: "alice", Justification: "x", Reconsent: true}); err == nil {
+		t.Fatal("a principal Resume must not renew the kernel's consents")
+	}
+}
+
+// TestHaltSurvivesRestart proves a STOP still halts minting after the
+// authority store is reattached
+func TestHaltSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "authority.json")
+	state := newSessionKernel(t)
+	if err := state.AttachAuthorityStore(path); err != nil {
+		t.Fatalf("attach: %v", err)
+	}
+	state.RevokeTokensFor("alice")
+
+	restarted := newSessionKernel(t)
+	if err := restarted.AttachAuthorityStore(path); err != nil {
+		t.Fatalf("reattach: %v", err)
+	}
+	if err := restarted.mintHalted("alice"); err == nil {
+		t.Fatal("alice's STOP must survive a restart")
+	}
+	if err := restarted.mintHalted("bob"); err != nil {
+		t.Fatalf("alice's STOP must not halt bob: %v", err)
+	}
+}
+
+// TestInvokeResumeLiftsOnlyTheCallersOwn proves a caller may lift their
+// own principal STOP over the admin surface, and never a global one
+func TestInvokeResumeLiftsOnlyTheCallersOwn(t *testing.T) {
+	state, key := stopKernel(t)
+	alice := bearerFor(t, key, "alice", "test_namespace", time.Now().Add(time.Hour))
+	if _, err := state.InvokeStop(alice, StopRequest{Scope: audit.StopScopePrincipal, Reason: "boredom"}); err == nil {
+		t.Fatal("an undefined STOP reason must be refused")
+	}
+	summary, err := state.InvokeStop(alice, StopRequest{Scope: audit.StopScopePrincipal, Reason: audit.StopReasonUserPanic})
+	if err != nil || summary.Reason != audit.StopReasonUserPanic {
+		t.Fatalf("stop: %+v (%v)", summary, err)
+	}
+	state.revokeAllTokens(audit.StopReasonIntegrityFailure)
+
+	var refused *ResumeRefusedError
+	if err := state.InvokeResume(alice, ResumeRequest{Scope: audit.StopScopeGlobal, Justification: "mine"}); !errors.As(err, &refused) || refused.Reason != StopRefusedForbidden {
+		t.Fatalf("a global STOP must not be lifted over the admin surface, got %v", err)
+	}
+	if err := state.InvokeResume(alice, ResumeRequest{Scope: audit.StopScopePrincipal, Target: "bob", Justification: "mine"}); !errors.As(err, &refused) || refused.Reason != StopRefusedForbidden {
+		t.Fatalf("another principal's STOP must not be lifted, got %v", err)
+	}
+	if err := state.InvokeResume(alice, ResumeRequest{Scope: audit.StopScopePrincipal, Justification: "mine"}); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if halts := state.Halts(); len(halts) != 1 || halts[0].Reason != audit.StopReasonIntegrityFailure {
+		t.Fatalf("only the global STOP may remain, got %+v", halts)
+	}
+}
@@ -0,0 +1,326 @@
+// WHY: A revocation reached adapters in the kernel's process at once, but
+// an out-of-process adapter host or an external executor only learned of
+// it when it next polled, and could act on a revoked token until then. A
+// RevocationNotifier pushes a notice of every STOP and token revocation to
+// one registered endpoint, in order, retrying with backoff until the
+// endpoint accepts it or the attempts run out. A STOP never waits on a
+// notice, and every notice's delivery, or its abandonment, is ledgered.
+package kernel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RevocationScopeSession is the scope of a notice for the tokens of a
+// closed session
+const RevocationScopeSession = "session"
+
+// Defaults for NotifyPolicy
+const (
+	DefaultNotifyAttempts   = 5
+	DefaultNotifyMinBackoff = 100 * time.Millisecond
+	DefaultNotifyMaxBackoff = 30 * time.Second
+)
+
+// revocationWebhookTimeout bounds one webhook delivery attempt
+const revocationWebhookTimeout = 10 * time.Second
+
+// RevocationNotice tells an endpoint which tokens were revoked and why
+type RevocationNotice struct {
+	// NoticeID is unique per notice; endpoints may see a notice more than
+	// once and should deduplicate on it
+	NoticeID string `json:"notice_id"`
+
+	// Scope is a STOP scope or RevocationScopeSession; Target and Reason
+	// are those of the STOP
+	Scope  string `json:"scope"`
+	Target string `json:"target,omitempty"`
+	Reason string `json:"reason,omitempty"`
+
+	TokenDigests []string `json:"token_digests"`
+	RevokedAt    int64    `json:"revoked_at"`
+}
+
+// RevocationEndpoint receives revocation notices
+type RevocationEndpoint interface {
+	// Name identifies the endpoint in delivery receipts and status
+	Name() string
+
+	// Notify delivers notice and returns nil only once the endpoint has
+	// accepted it
+	Notify(ctx context.Context, notice RevocationNotice) error
+}
+
+// NotifyPolicy tunes retry for a notifier
+type NotifyPolicy struct {
+	// MaxAttempts caps deliveries of one notice before it is abandoned
+	// (default DefaultNotifyAttempts)
+	MaxAttempts int
+
+	// MinBackoff and MaxBackoff bound the exponential retry delay
+	// (defaults DefaultNotifyMinBackoff and DefaultNotifyMaxBackoff)
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// NotifierStatus reports a notifier's progress
+type NotifierStatus struct {
+	Endpoint string
+
+	// Pending counts notices queued and not yet delivered or abandoned
+	Pending int
+
+	Delivered int64
+	Abandoned int64
+
+	// Failures counts failed delivery attempts; LastError is the most recent
+	Failures  int64
+	LastError string
+}
+
+// RevocationNotifier delivers revocation notices to one endpoint
+type RevocationNotifier struct {
+	state    *SystemState
+	endpoint RevocationEndpoint
+	policy   NotifyPolicy
+
+	mu     sync.Mutex
+	queue  []RevocationNotice
+	status NotifierStatus
+
+	wake     chan struct{}
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NotifyRevocations starts pushing a notice of every revocation from now
+// on to endpoint in the background
+func (s *SystemState) NotifyRevocations(endpoint RevocationEndpoint, policy NotifyPolicy) (*RevocationNotifier, error) {
+	if endpoint == nil {
+		return nil, fmt.Errorf("nil revocation endpoint")
+	}
+	if policy.MaxAttempts < 0 {
+		return nil, fmt.Errorf("notify attempts must not be negative")
+	}
+	if policy.MaxAttempts == 0 {
+		policy.MaxAttempts = DefaultNotifyAttempts
+	}
+	if policy.MinBackoff <= 0 {
+		policy.MinBackoff = DefaultNotifyMinBackoff
+	}
+	if policy.MaxBackoff < policy.MinBackoff {
+		policy.MaxBackoff = DefaultNotifyMaxBackoff
+	}
+
+	n := &RevocationNotifier{
+		state:    s,
+		endpoint: endpoint,
+		policy:   policy,
+		status:   NotifierStatus{Endpoint: endpoint.Name()},
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	s.mu.Lock()
+	s.revocationNotifiers = append(s.revocationNotifiers, n)
+	s.mu.Unlock()
+	go n.run()
+	return n, nil
+}
+
+// Status returns a snapshot of the notifier's progress
+func (n *RevocationNotifier) Status() NotifierStatus {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	status := n.status
+	status.Pending = len(n.queue)
+	return status
+}
+
+// Stop halts the notifier; a delivery in flight is cancelled and notices
+// still queued are not sent
+func (n *RevocationNotifier) Stop() {
+	n.stopOnce.Do(func() {
+		s := n.state
+		s.mu.Lock()
+		for i, notifier := range s.revocationNotifiers {
+			if notifier == n {
+				s.revocationNotifiers = append(s.revocationNotifiers[:i], s.revocationNotifiers[i+1:]...)
+				break
+			}
+		}
+		s.mu.Unlock()
+		close(n.stop)
+	})
+	<-n.done
+}
+
+// enqueue queues notice without waiting on delivery
+func (n *RevocationNotifier) enqueue(notice RevocationNotice) {
+	n.mu.Lock()
+	n.queue = append(n.queue, notice)
+	n.mu.Unlock()
+	select {
+	case n.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (n *RevocationNotifier) run() {
+	defer close(n.done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-n.stop
+		cancel()
+	}()
+
+	for {
+		n.mu.Lock()
+		if len(n.queue) == 0 {
+			n.mu.Unlock()
+			select {
+			case <-n.wake:
+				continue
+			case <-n.stop:
+				return
+			}
+		}
+		notice := n.queue[0]
+		n.mu.Unlock()
+
+		if !n.deliver(ctx, notice) {
+			return
+		}
+		n.mu.Lock()
+		n.queue = n.queue[1:]
+		n.mu.Unlock()
+	}
+}
+
+// deliver retries notice until the endpoint accepts it or the attempts
+// run out, and ledgers the outcome; it returns false if the notifier was
+// stopped first
+func (n *RevocationNotifier) deliver(ctx context.Context, notice RevocationNotice) bool {
+	backoff := n.policy.MinBackoff
+	for attempt := 1; ; attempt++ {
+		err := n.endpoint.Notify(ctx, notice)
+		if ctx.Err() != nil {
+			return false
+		}
+		if err == nil {
+			n.mu.Lock()
+			n.status.Delivered++
+			n.mu.Unlock()
+			n.state.AuditLedger.AppendRevocationNotice(n.state.attribution(""), n.endpoint.Name(), notice.NoticeID, notice.Scope, len(notice.TokenDigests), attempt, "")
+			return true
+		}
+
+		n.mu.Lock()
+		n.status.Failures++
+		n.status.LastError = err.Error()
+		n.mu.Unlock()
+		if attempt >= n.policy.MaxAttempts {
+			n.mu.Lock()
+			n.status.Abandoned++
+			n.mu.Unlock()
+			n.state.AuditLedger.AppendRevocationNotice(n.state.attribution(""), n.endpoint.Name(), notice.NoticeID, notice.Scope, len(notice.TokenDigests), attempt, err.Error())
+			return true
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-n.stop:
+			return false
+		}
+		backoff *= 2
+		if backoff > n.policy.MaxBackoff {
+			backoff = n.policy.MaxBackoff
+		}
+	}
+}
+
+// notifyRevocation queues a notice of a revocation on every notifier
+func (s *SystemState) notifyRevocation(scope string, target string, reason string, digests []string) {
+	s.mu.RLock()
+	notifiers := append([]*RevocationNotifier(nil), s.revocationNotifiers...)
+	s.mu.RUnlock()
+	if len(notifiers) == 0 {
+		return
+	}
+
+	notice := RevocationNotice{
+		NoticeID:     newRequestID(),
+		Scope:        scope,
+		Target:       target,
+		Reason:       reason,
+		TokenDigests: append([]string{}, digests...),
+		RevokedAt:    s.now().Unix(),
+	}
+	for _, notifier := range notifiers {
+		notifier.enqueue(notice)
+	}
+}
+
+// RevocationWebhook POSTs revocation notices to an HTTP endpoint
+type RevocationWebhook struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewRevocationWebhook creates an endpoint for url; headers (e.g.
+// Authorization) are sent with every notice
+func NewRevocationWebhook(url string, headers map[string]string) *RevocationWebhook {
+	copied := make(map[string]string, len(headers))
+	for key, value := range headers {
+		copied[key] = value
+	}
+	return &RevocationWebhook{
+		url:     url,
+		headers: copied,
+		client:  &http.Client{Timeout: revocationWebhookTimeout},
+	}
+}
+
+// Name identifies the endpoint
+func (w *RevocationWebhook) Name() string {
+	return "webhook:" + w.url
+}
+
+// Notify POSTs notice as JSON and treats only a 2xx response as acceptance
+func (w *RevocationWebhook) Notify(ctx context.Context, notice RevocationNotice) error {
+	body, err := json.Marshal(notice)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("revocation webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range w.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("revocation webhook post: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("revocation webhook rejected notice: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+// WHY: Proves every STOP and token revocation is pushed to registered
+// endpoints in order, retried until accepted or abandoned, and that each
+// outcome leaves a delivery receipt.
+package kernel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+)
+
+// flakyEndpoint refuses the first failures notices it is sent, then
+// records every notice it accepts
+type flakyEndpoint struct {
+	mu       sync.Mutex
+	failures int
+	accepted []RevocationNotice
+}
+
+func (f *flakyEndpoint) Name() string {
+	return "flaky"
+}
+
+func (f *flakyEndpoint) Notify(ctx context.Context, notice RevocationNotice) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failures > 0 {
+		f.failures--
+		return fmt.Errorf("host unreachable")
+	}
+	f.accepted = append(f.accepted, notice)
+	return nil
+}
+
+// awaitNotices waits until notifier has settled settled notices
+func awaitNotices(t *testing.T, notifier *RevocationNotifier, settled int64) NotifierStatus {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		status := notifier.Status()
+		if status.Delivered+status.Abandoned >= settled && status.Pending == 0 {
+			return status
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("notices not settled: %+v", status)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestRevocationsArePushedInOrderWithRetries proves a notice is retried
+// until accepted, abandoned once its attempts run out, and never
+// reordered
+func TestRevocationsArePushedInOrderWithRetries(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	endpoint := &flakyEndpoint{failures: 2}
+	notifier, err := state.NotifyRevocations(endpoint, NotifyPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	defer notifier.Stop()
+
+	alice := mintApprover(t, state, "alice", "scope1")
+	state.RevokeTokensFor("alice")
+	state.RevokeTokensFor("nobody")
+	state.RevokeAllTokens()
+	awaitNotices(t, notifier, 2)
+
+	if len(endpoint.accepted) != 2 {
+		t.Fatalf("a STOP that revoked nothing is not pushed, got %+v", endpoint.accepted)
+	}
+	first, second := endpoint.accepted[0], endpoint.accepted[1]
+	if first.Scope != audit.StopScopePrincipal || first.Target != "alice" || first.TokenDigests[0] != alice.Digest || first.Reason != audit.StopReasonUserPanic {
+		t.Fatalf("the principal STOP must be pushed first with its tokens, got %+v", first)
+	}
+	if second.Scope != audit.StopScopeGlobal || second.NoticeID == first.NoticeID {
+		t.Fatalf("the global STOP must follow as its own notice, got %+v", second)
+	}
+
+	endpoint.mu.Lock()
+	endpoint.failures = 3
+	endpoint.mu.Unlock()
+	state.RevokeAllTokens()
+	status := awaitNotices(t, notifier, 3)
+	if status.Abandoned != 1 || status.Failures != 5 {
+		t.Fatalf("a notice must be abandoned after its attempts, got %+v", status)
+	}
+
+	page, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"revocation_notice"}})
+	if len(page.Receipts) != 3 {
+		t.Fatalf("every notice must leave a receipt, got %d", len(page.Receipts))
+	}
+	if page.Receipts[0].EventData["attempts"] != 3 || page.Receipts[0].EventData["delivered"] != true {
+		t.Fatalf("the receipt must record the attempts a delivery took, got %+v", page.Receipts[0].EventData)
+	}
+	if abandoned := page.Receipts[2]; abandoned.EventData["delivered"] != false || abandoned.Severity != audit.SeverityWarn {
+		t.Fatalf("an abandoned notice must be a warning, got %+v", abandoned)
+	}
+
+	notifier.Stop()
+	state.RevokeAllTokens()
+	if status := notifier.Status(); status.Pending != 0 {
+		t.Fatalf("a stopped notifier must take no notices, got %+v", status)
+	}
+}
+
+// TestRevocationWebhookPostsNotices proves the webhook endpoint sends
+// the notice as JSON and accepts only a 2xx response
+func TestRevocationWebhookPostsNotices(t *testing.T) {
+	var mu sync.Mutex
+	var received []RevocationNotice
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if r.Header.Get("Authorization") != "Bearer host-secret" || calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var notice RevocationNotice
+		json.NewDecoder(r.Body).Decode(&notice)
+		received = append(received, notice)
+	}))
+	defer server.Close()
+
+	state := newSessionKernel(t)
+	webhook := NewRevocationWebhook(server.URL, map[string]string{"Authorization": "Bearer host-secret"})
+	notifier, err := state.NotifyRevocations(webhook, NotifyPolicy{MinBackoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	defer notifier.Stop()
+
+	session, _ := state.Sessions.Open("alice", "tenant_a")
+	if resp, err := Execute(&Request{RawInput: "test request", SessionID: session.ID}, state); err != nil || !resp.Success {
+		t.Fatalf("execute: %+v (%v)", resp, err)
+	}
+	if err := state.Sessions.Close(session.ID); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	awaitNotices(t, notifier, 1)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 2 || len(received) != 1 || received[0].Scope != RevocationScopeSession || len(received[0].TokenDigests) != 1 {
+		t.Fatalf("the session's revocation must be retried and delivered, got %d calls, %+v", calls, received)
+	}
+}
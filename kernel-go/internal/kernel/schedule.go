@@ -0,0 +1,145 @@
+// WHY: Posture windows tighten the kernel on the calendar. The schedule
+// lives in the governance capsule and is read against the world pack's
+// time at the start of every request; each window's raise, its end, and
+// any manual override are posture transitions, so all of them are
+// ledgered as posture_change receipts. A window's end only undoes the
+// schedule's own raises; anything lower is a governed downgrade.
+package kernel
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// ConsentPostureOverride is the consent a manual override of an active
+// posture window needs
+const ConsentPostureOverride = "posture_override"
+
+// scheduledPosture is what the posture schedule last applied
+type scheduledPosture struct {
+	mu sync.Mutex
+
+	// window is the window whose level is applied, or "" if none, and
+	// applied the transitions the schedule made for it; the first raised
+	// the posture from the level before any window
+	window  string
+	applied []posture.Transition
+
+	// override is the window a manual override holds off until it ends
+	override string
+}
+
+// worldTime is the time posture windows are read at: the world pack's,
+// if set, and the kernel clock's otherwise
+func (s *SystemState) worldTime() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.WorldPack.Timestamp != 0 {
+		return time.Unix(s.WorldPack.Timestamp, 0)
+	}
+	if s.clock != nil {
+		return s.clock.Now()
+	}
+	return time.Now()
+}
+
+// ApplyPostureSchedule raises the posture to the active window's level
+// and, once the window ends, restores the level before it.
+// WHY: A window only undoes its own raise; if the posture moved since,
+// the window's end leaves it where it is.
+func (s *SystemState) ApplyPostureSchedule() error {
+	schedule := s.GovernanceCapsule.PostureSchedule
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+	window, active := schedule.ActiveAt(s.worldTime())
+
+	scheduled := &s.scheduled
+	scheduled.mu.Lock()
+	defer scheduled.mu.Unlock()
+
+	if scheduled.override != "" && (!active || window.Name != scheduled.override) {
+		scheduled.override = ""
+	}
+	if active && (window.Name == scheduled.window || window.Name == scheduled.override) {
+		return nil
+	}
+
+	// A posture that moved since the schedule last set it is left alone
+	if scheduled.window != "" && s.Posture.IsLatest(scheduled.applied) {
+		ended, applied := scheduled.window, scheduled.applied
+		scheduled.window, scheduled.applied = "", nil
+		restore := applied[0].FromLevel
+		if !active {
+			s.Posture.Revert(applied, restore, fmt.Sprintf("posture window %s ended", ended))
+			return nil
+		}
+		level, current := window.Level, applied[len(applied)-1].ToLevel
+		if restore > level {
+			level = restore
+		}
+		reason := fmt.Sprintf("posture window %s replaces %s", window.Name, ended)
+		var transition posture.Transition
+		moved := level == current
+		if level > current {
+			transition, moved = s.Posture.Raise(level, reason)
+			applied = append(applied, transition)
+		} else if level < current {
+			transition, moved = s.Posture.Revert(applied, level, reason)
+			applied = append(applied, transition)
+		}
+		if moved {
+			scheduled.window, scheduled.applied = window.Name, applied
+		}
+		return nil
+	}
+	scheduled.window, scheduled.applied = "", nil
+	if !active {
+		return nil
+	}
+
+	if transition, raised := s.Posture.Raise(window.Level, fmt.Sprintf("posture window %s", window.Name)); raised {
+		scheduled.window, scheduled.applied = window.Name, []posture.Transition{transition}
+	}
+	return nil
+}
+
+// OverridePostureSchedule sets the posture by hand while a window is
+// active, holding the window off until it ends.
+// WHY: A window exists because governance decided the period needs
+// constraint, so setting it aside takes the user's posture_override
+// consent, and an override that lowers the posture is a downgrade like
+// any other: it waits out the cooldown and has its approver.
+func (s *SystemState) OverridePostureSchedule(override posture.Downgrade) error {
+	if !posture.IsValid(override.Level) {
+		return fmt.Errorf("posture %d is not defined", override.Level)
+	}
+	if !s.activeConsents()[ConsentPostureOverride] {
+		return fmt.Errorf("posture override requires consent %s", ConsentPostureOverride)
+	}
+	schedule := s.GovernanceCapsule.PostureSchedule
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+	window, active := schedule.ActiveAt(s.worldTime())
+	if !active {
+		return fmt.Errorf("no posture window is active")
+	}
+
+	s.scheduled.mu.Lock()
+	defer s.scheduled.mu.Unlock()
+	override.Reason = fmt.Sprintf("override of posture window %s: %s", window.Name, override.Reason)
+	if override.Level < s.Posture.Level() {
+		if err := s.lowerPosture(s.Posture, ConsentPostureOverride, override); err != nil {
+			return err
+		}
+	} else if _, err := s.Posture.SetLevel(override.Level, override.Reason); err != nil {
+		return err
+	}
+	s.scheduled.override = window.Name
+	s.scheduled.window, s.scheduled.applied = "", nil
+	return nil
+}
@@ -0,0 +1,68 @@
+// WHY: Proves posture windows raise the corridor's posture at request
+// time and restore it when they end, and overriding one takes consent.
+package kernel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/clock"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// TestPostureWindowsApplyAtRequestTime proves a window raises the posture
+// for requests inside it and the posture is restored after it
+func TestPostureWindowsApplyAtRequestTime(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	state.GovernanceCapsule.PostureSchedule = posture.Schedule{Windows: []posture.Window{
+		{Name: "after_hours", Level: posture.P3, Start: 18 * time.Hour, End: 8 * time.Hour},
+	}}
+	request := &Request{RawInput: "test request", Metadata: map[string]interface{}{}}
+
+	state.WorldPack.Timestamp = time.Date(2026, 3, 2, 20, 0, 0, 0, time.UTC).Unix()
+	Execute(request, state)
+	if state.PostureLevel() != posture.P3 {
+		t.Fatalf("a request after hours must run at P3, got P%d", state.PostureLevel())
+	}
+	state.WorldPack.Timestamp = time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC).Unix()
+	Execute(request, state)
+	if state.PostureLevel() != posture.P1 {
+		t.Fatalf("the window's end must restore P1, got P%d", state.PostureLevel())
+	}
+
+	page, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"posture_change"}})
+	if len(page.Receipts) != 2 || page.Receipts[0].EventData["reason"] != "posture window after_hours" {
+		t.Fatalf("the window's raise and end must be ledgered, got %+v", page.Receipts)
+	}
+}
+
+// TestPostureWindowOverrideNeedsConsent proves an active window can only
+// be set aside with the posture_override consent, once the downgrade
+// cooldown has passed, and stays set aside
+func TestPostureWindowOverrideNeedsConsent(t *testing.T) {
+	now := clock.NewFake(time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+	state := NewSystemState("test_principal", "test_namespace")
+	state.SetClock(now)
+	state.GovernanceCapsule.PostureSchedule = posture.Schedule{Windows: []posture.Window{
+		{Name: "freeze", Level: posture.P4},
+	}}
+	state.ApplyPostureSchedule()
+	override := posture.Downgrade{Level: posture.P2, Reason: "release hotfix", RequestedBy: "test_principal"}
+
+	if err := state.OverridePostureSchedule(override); err == nil {
+		t.Fatal("an override without consent must be refused")
+	}
+	state.GrantConsent(ConsentPostureOverride, 0, "operator")
+	if err := state.OverridePostureSchedule(override); err == nil {
+		t.Fatal("an override lowering the posture must wait out the cooldown")
+	}
+	now.Advance(posture.DefaultDowngradeCooldown)
+	if err := state.OverridePostureSchedule(override); err != nil {
+		t.Fatalf("override: %v", err)
+	}
+	state.ApplyPostureSchedule()
+	if state.PostureLevel() != posture.P2 {
+		t.Fatalf("an overridden window must not reapply, got P%d", state.PostureLevel())
+	}
+}
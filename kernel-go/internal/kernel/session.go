@@ -0,0 +1,350 @@
+// WHY: The identity capsule names one principal, so a kernel used to act
+// for one user at a time. A SessionManager lets one kernel process serve
+// many: each session carries its own principal and namespace, consents,
+// tokens, ephemeral memory, and leak budget, and a request naming the
+// session runs as it. Sessions idle out, and closing one revokes its
+// tokens and drops its ephemeral memory, so nothing it was granted
+// outlives it.
+package kernel
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/memory"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// DefaultSessionIdleTimeout is how long a session may go unused before it
+// is closed
+const DefaultSessionIdleTimeout = 30 * time.Minute
+
+// DefaultSessionLeakBudget is the bytes of output one session may carry
+// over its life
+const DefaultSessionLeakBudget = 100 * posture.DefaultLeakBudget
+
+// Reasons a session is closed
+const (
+	SessionClosed = "closed"
+	SessionIdle   = "idle"
+)
+
+// SessionConfig bounds the sessions a SessionManager opens
+type SessionConfig struct {
+	// IdleTimeout is how long a session may go unused
+	IdleTimeout time.Duration
+
+	// LeakBudget is the bytes of output a session may carry in total
+	LeakBudget int
+}
+
+// Session is one principal's use of the kernel
+type Session struct {
+	ID          string
+	PrincipalID string
+	NamespaceID string
+
+	// Memory is the session's own view for ephemeral memory; its
+	// ephemeral entries are dropped when the session closes
+	Memory *memory.Manager
+
+	mu         sync.Mutex
+	consents   map[string]bool
+	tokens     []string
+	leakBudget int
+	lastUsed   time.Time
+	closed     bool
+}
+
+// SessionManager opens, tracks, idles out, and closes sessions
+type SessionManager struct {
+	state *SystemState
+
+	mu       sync.Mutex
+	config   SessionConfig
+	sessions map[string]*Session
+}
+
+// newSessionManager returns the session manager of state
+func newSessionManager(state *SystemState) *SessionManager {
+	return &SessionManager{
+		state:    state,
+		config:   SessionConfig{IdleTimeout: DefaultSessionIdleTimeout, LeakBudget: DefaultSessionLeakBudget},
+		sessions: make(map[string]*Session),
+	}
+}
+
+// SetConfig sets the bounds of sessions opened from now on; the idle
+// timeout applies to open sessions too
+func (m *SessionManager) SetConfig(config SessionConfig) error {
+	if config.IdleTimeout <= 0 || config.LeakBudget <= 0 {
+		return fmt.Errorf("session config needs a positive idle timeout and leak budget")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config = config
+	return nil
+}
+
+// Open starts a session for principalID in namespaceID
+func (m *SessionManager) Open(principalID string, namespaceID string) (*Session, error) {
+	if principalID == "" || namespaceID == "" {
+		return nil, fmt.Errorf("a session needs a principal and a namespace")
+	}
+	id := newSessionID()
+
+	m.mu.Lock()
+	session := &Session{
+		ID:          id,
+		PrincipalID: principalID,
+		NamespaceID: namespaceID,
+		Memory:      m.state.MemoryManager.Namespace(sessionNamespace(namespaceID, id)),
+		consents:    make(map[string]bool),
+		leakBudget:  m.config.LeakBudget,
+		lastUsed:    m.state.now(),
+	}
+	m.sessions[id] = session
+	m.mu.Unlock()
+
+	m.state.AuditLedger.AppendSessionOpened(session.attribution(), id)
+	return session, nil
+}
+
+// Get returns the open session id
+func (m *SessionManager) Get(id string) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	session, ok := m.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("session %s is not open", id)
+	}
+	return session, nil
+}
+
+// List returns the IDs of the open sessions, sorted
+func (m *SessionManager) List() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ids := make([]string, 0, len(m.sessions))
+	for id := range m.sessions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Close ends session id, revoking its tokens and dropping its ephemeral
+// memory
+func (m *SessionManager) Close(id string) error {
+	session, err := m.Get(id)
+	if err != nil {
+		return err
+	}
+	m.close(session, SessionClosed)
+	return nil
+}
+
+// Sweep closes every session idle past the timeout and returns how many
+// it closed
+func (m *SessionManager) Sweep() int {
+	now := m.state.now()
+	m.mu.Lock()
+	timeout := m.config.IdleTimeout
+	var idle []*Session
+	for _, session := range m.sessions {
+		if session.idleSince(now) >= timeout {
+			idle = append(idle, session)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, session := range idle {
+		m.close(session, SessionIdle)
+	}
+	return len(idle)
+}
+
+// use returns the open session id for a request, closing it instead if
+// it has idled out. WHY: A session is only as live as its last use, so
+// one left idle is closed when next touched even between sweeps.
+func (m *SessionManager) use(id string) (*Session, error) {
+	session, err := m.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	now := m.state.now()
+	m.mu.Lock()
+	timeout := m.config.IdleTimeout
+	m.mu.Unlock()
+	if session.idleSince(now) >= timeout {
+		m.close(session, SessionIdle)
+		return nil, fmt.Errorf("session %s has idled out", id)
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if session.closed {
+		return nil, fmt.Errorf("session %s is not open", id)
+	}
+	session.lastUsed = now
+	return session, nil
+}
+
+// close tears session down once: its tokens are revoked and forgotten,
+// its ephemeral memory is dropped, and the close is ledgered
+func (m *SessionManager) close(session *Session, reason string) {
+	session.mu.Lock()
+	if session.closed {
+		session.mu.Unlock()
+		return
+	}
+	session.closed = true
+	digests := session.tokens
+	session.tokens = nil
+	session.mu.Unlock()
+
+	m.mu.Lock()
+	delete(m.sessions, session.ID)
+	m.mu.Unlock()
+
+	revoked := m.state.revokeTokens(digests)
+	if entries, err := session.Memory.List(memory.PartitionEphemeral, memory.EntryFilter{}); err == nil {
+		for _, entry := range entries {
+			session.Memory.Delete(memory.PartitionEphemeral, entry.ID)
+		}
+	}
+	m.state.AuditLedger.AppendSessionClosed(session.attribution(), session.ID, reason, revoked)
+}
+
+// GrantConsent records the session principal's consent to name
+func (s *Session) GrantConsent(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.consents[name] = true
+}
+
+// RevokeConsent withdraws the session principal's consent to name
+func (s *Session) RevokeConsent(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.consents, name)
+}
+
+// Consents returns a copy of the session's active consents
+func (s *Session) Consents() map[string]bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	consents := make(map[string]bool, len(s.consents))
+	for name, active := range s.consents {
+		consents[name] = active
+	}
+	return consents
+}
+
+// LeakBudget returns the bytes of output the session may still carry
+func (s *Session) LeakBudget() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.leakBudget
+}
+
+// addToken records a token minted for the session. WHY: A session closed
+// while the token was minted has already revoked what it held, so the
+// token is refused rather than left outliving it.
+func (s *Session) addToken(digest string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return fmt.Errorf("session %s closed", s.ID)
+	}
+	s.tokens = append(s.tokens, digest)
+	return nil
+}
+
+// charge returns the leak budget of a response that would use used bytes
+// under a per-response limit, and takes what it may carry from the
+// session's budget
+func (s *Session) charge(used int, limit int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	budget := limit
+	if s.leakBudget < budget {
+		budget = s.leakBudget
+	}
+	if used > budget {
+		used = budget
+	}
+	s.leakBudget -= used
+	return budget
+}
+
+// idleSince returns how long the session has gone unused at now
+func (s *Session) idleSince(now time.Time) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return now.Sub(s.lastUsed)
+}
+
+// attribution attributes receipts to the session's principal and
+// namespace
+func (s *Session) attribution() audit.Attribution {
+	return audit.Attribution{PrincipalID: s.PrincipalID, NamespaceID: s.NamespaceID}
+}
+
+// sessionNamespace is the memory namespace of a session's ephemeral
+// memory, inside its namespace
+func sessionNamespace(namespace string, id string) string {
+	return namespace + "/session/" + id
+}
+
+// newSessionID returns a random session ID
+func newSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("kernel: session ID entropy unavailable: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+// now reads the kernel clock
+func (s *SystemState) now() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.nowLocked()
+}
+
+// nowLocked reads the kernel clock; s.mu is held
+func (s *SystemState) nowLocked() time.Time {
+	if s.clock != nil {
+		return s.clock.Now()
+	}
+	return time.Now()
+}
+
+// revokeTokens revokes and forgets the active tokens with digests, holds
+// them revoked, and returns how many it revoked
+func (s *SystemState) revokeTokens(digests []string) int {
+	s.mu.Lock()
+	revoked := 0
+	for _, digest := range digests {
+		if token, ok := s.ActiveCapabilityTokens[digest]; ok {
+			token.Revoke()
+			delete(s.ActiveCapabilityTokens, digest)
+			revoked++
+		}
+	}
+	s.holdRevoked(digests)
+	s.mu.Unlock()
+
+	s.AdapterRegistry.Stop(digests...)
+	if len(digests) > 0 {
+		s.persistRevocation()
+		s.notifyRevocation(RevocationScopeSession, "", "", digests)
+	}
+	return revoked
+}
@@ -0,0 +1,124 @@
+// WHY: Proves sessions let one kernel act for several principals, each
+// with its own tokens, consents, memory, and leak budget, and that closing
+// or idling out a session takes back everything it was granted.
+package kernel
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/adapters"
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/clock"
+	"github.com/user/oi/kernel-go/internal/memory"
+)
+
+// newSessionKernel returns a kernel ready to serve requests
+func newSessionKernel(t *testing.T) *SystemState {
+	t.Helper()
+	state := NewSystemState("kernel_principal", "kernel_namespace")
+	state.AdapterRegistry.Register(adapters.NewMockAdapter("mock_adapter"))
+	state.GovernanceCapsule.Rules = map[string]interface{}{"exists": true}
+	return state
+}
+
+// TestSessionsActForTheirOwnPrincipal proves a session's requests mint
+// tokens for its principal and namespace, and closing it revokes them and
+// drops its ephemeral memory
+func TestSessionsActForTheirOwnPrincipal(t *testing.T) {
+	state := newSessionKernel(t)
+	alice, _ := state.Sessions.Open("alice", "tenant_a")
+	bob, _ := state.Sessions.Open("bob", "tenant_b")
+	alice.GrantConsent("high_risk_operations")
+	if bob.Consents()["high_risk_operations"] {
+		t.Fatal("a consent must belong to the session it was granted in")
+	}
+
+	resp, err := Execute(&Request{RawInput: "test request", Metadata: map[string]interface{}{}, SessionID: alice.ID}, state)
+	if err != nil || !resp.Success {
+		t.Fatalf("session request: %v", err)
+	}
+	if len(state.ActiveCapabilityTokens) != 1 {
+		t.Fatalf("expected one token, got %d", len(state.ActiveCapabilityTokens))
+	}
+	var token string
+	for digest, minted := range state.ActiveCapabilityTokens {
+		if minted.PrincipalID != "alice" || minted.NamespaceID != "tenant_a" {
+			t.Fatalf("the token must be minted for the session, got %s in %s", minted.PrincipalID, minted.NamespaceID)
+		}
+		token = digest
+	}
+	if _, err := Execute(&Request{RawInput: "test request", NamespaceID: "tenant_b", SessionID: alice.ID}, state); err == nil {
+		t.Fatal("a session request must not act in another namespace")
+	}
+
+	alice.Memory.Write(memory.PartitionEphemeral, "scratch", "draft", nil)
+	if err := state.Sessions.Close(alice.ID); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if _, active := state.ActiveCapabilityTokens[token]; active {
+		t.Fatal("closing a session must revoke its tokens")
+	}
+	if _, err := alice.Memory.Read(memory.PartitionEphemeral, "scratch"); err == nil {
+		t.Fatal("closing a session must drop its ephemeral memory")
+	}
+	if _, err := Execute(&Request{RawInput: "test request", SessionID: alice.ID}, state); err == nil {
+		t.Fatal("a closed session must not serve requests")
+	}
+	if ids := state.Sessions.List(); len(ids) != 1 || ids[0] != bob.ID {
+		t.Fatalf("only bob's session must stay open, got %v", ids)
+	}
+
+	page, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"session_closed"}})
+	if len(page.Receipts) != 1 || page.Receipts[0].EventData["principal_id"] != "alice" || page.Receipts[0].EventData["tokens_revoked"] != 1 {
+		t.Fatalf("the close must be ledgered for the session's principal, got %+v", page.Receipts)
+	}
+}
+
+// TestIdleSessionsAreClosed proves a sweep closes sessions idle past the
+// timeout, and an idled-out session refuses its next request
+func TestIdleSessionsAreClosed(t *testing.T) {
+	now := clock.NewFake(time.Unix(1_700_000_000, 0))
+	state := newSessionKernel(t)
+	state.SetClock(now)
+	state.Sessions.SetConfig(SessionConfig{IdleTimeout: time.Minute, LeakBudget: DefaultSessionLeakBudget})
+	active, _ := state.Sessions.Open("alice", "tenant_a")
+	idle, _ := state.Sessions.Open("bob", "tenant_b")
+
+	now.Advance(45 * time.Second)
+	Execute(&Request{RawInput: "test request", SessionID: active.ID}, state)
+	now.Advance(30 * time.Second)
+	if closed := state.Sessions.Sweep(); closed != 1 {
+		t.Fatalf("expected one idle session closed, got %d", closed)
+	}
+	if _, err := state.Sessions.Get(idle.ID); err == nil {
+		t.Fatal("the idle session must be closed")
+	}
+
+	now.Advance(time.Minute)
+	if _, err := Execute(&Request{RawInput: "test request", SessionID: active.ID}, state); err == nil {
+		t.Fatal("a session idled out between sweeps must refuse its next request")
+	}
+}
+
+// TestSessionLeakBudgetSpansRequests proves a session's leak budget is
+// spent across its requests and output beyond it is redacted
+func TestSessionLeakBudgetSpansRequests(t *testing.T) {
+	state := newSessionKernel(t)
+	state.Sessions.SetConfig(SessionConfig{IdleTimeout: time.Hour, LeakBudget: 40})
+	session, _ := state.Sessions.Open("alice", "tenant_a")
+	request := &Request{RawInput: "test request", SessionID: session.ID}
+
+	resp, _ := Execute(request, state)
+	if !resp.Success || strings.Contains(resp.Content, "leak budget exceeded") {
+		t.Fatalf("the first response fits the budget: %+v", resp)
+	}
+	resp, _ = Execute(request, state)
+	if !resp.Success || !strings.Contains(resp.Content, "leak budget exceeded") {
+		t.Fatalf("the second response must be cut at the session budget, got %q", resp.Content)
+	}
+	if session.LeakBudget() != 0 {
+		t.Fatalf("the session budget must be spent, %d left", session.LeakBudget())
+	}
+}
@@ -0,0 +1,456 @@
+// WHY: A kernel that crashes used to come back at its defaults: consents
+// it had been given, the governance it had been configured with, and the
+// posture it had been raised to were all gone. A StateSnapshot checkpoints
+// that state as hashed sections under one digest signed by the kernel's
+// audit key, and Restore checks the signature and every hash before
+// applying anything. A snapshot is not a way around what governs the
+// kernel: its governance must be the governance already in force, and a
+// consent comes back only if the ledger still holds the receipt that
+// granted it. Restoring only ever tightens what it cannot vouch for: the
+// posture is raised to the snapshot's, never lowered, integrity takes the
+// worse of the two, and tokens outstanding at the checkpoint come back
+// revoked.
+package kernel
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/posture"
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+// SnapshotVersion is the StateSnapshot format this kernel writes and reads
+const SnapshotVersion = 2
+
+// snapshotSignatureDomain separates snapshot signatures from every other
+// message the audit key signs
+const snapshotSignatureDomain = "oi.state_snapshot.v2|"
+
+// Sections of a StateSnapshot, in the order they are written
+const (
+	SectionIdentity   = "identity"
+	SectionAuthority  = "authority"
+	SectionGovernance = "governance"
+	SectionPosture    = "posture"
+	SectionTokens     = "tokens"
+	SectionIntegrity  = "integrity"
+)
+
+// snapshotSections lists every section a snapshot must carry
+var snapshotSections = []string{
+	SectionIdentity, SectionAuthority, SectionGovernance, SectionPosture, SectionTokens, SectionIntegrity,
+}
+
+// StateSnapshot is a kernel's governance state at one moment
+type StateSnapshot struct {
+	Version   int
+	CreatedAt int64
+	Sections  []SnapshotSection
+
+	// Digest is the hex SHA-256 of the section names and hashes, in order
+	Digest string
+
+	// KeyID and Signature are the audit key's signature over Digest
+	KeyID     string
+	Signature []byte
+}
+
+// SnapshotSection is one part of the state, encoded, with its hash
+type SnapshotSection struct {
+	Name string
+	Data json.RawMessage
+
+	// Hash is the hex SHA-256 of Data
+	Hash string
+}
+
+// governanceSection is the governance capsule as a snapshot carries it.
+// WHY: A time.Location does not encode, so the schedule's is carried by
+// name, with its offset for a fixed zone no database knows.
+type governanceSection struct {
+	Capsule          GovernanceCapsule
+	ScheduleLocation string
+	ScheduleOffset   int
+}
+
+// postureSection is the posture of the kernel and of each namespace held
+// beyond it
+type postureSection struct {
+	Level      int
+	Namespaces map[string]int
+}
+
+// tokensSection lists the digests of the tokens outstanding at the
+// checkpoint and of those already revoked
+type tokensSection struct {
+	Active  []string
+	Revoked []string
+}
+
+// integrityRank orders integrity states from best to worst
+var integrityRank = map[IntegrityState]int{IntegrityOK: 0, IntegrityDegraded: 1, IntegrityVoid: 2}
+
+// Serialize encodes the kernel's governance state as a StateSnapshot
+// signed by its audit key
+func (s *SystemState) Serialize() ([]byte, error) {
+	snapshot, err := s.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	signer := s.auditSigner
+	s.mu.RUnlock()
+	if signer == nil {
+		return nil, fmt.Errorf("state snapshot: no audit key to sign it with")
+	}
+	snapshot.KeyID = signer.KeyID()
+	if snapshot.Signature, err = signer.Sign(snapshotSigningMessage(snapshot.Digest)); err != nil {
+		return nil, fmt.Errorf("sign state snapshot: %w", err)
+	}
+	return json.Marshal(snapshot)
+}
+
+// snapshot captures the state in one StateSnapshot. WHY: Every section
+// is read under one lock, so none reflects a change another has not seen.
+func (s *SystemState) snapshot() (*StateSnapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	postures := postureSection{Level: s.Posture.Level(), Namespaces: map[string]int{}}
+	for namespace, state := range s.namespacePostures {
+		postures.Namespaces[namespace] = state.Level()
+	}
+	governance := governanceSection{Capsule: s.GovernanceCapsule}
+	if location := s.GovernanceCapsule.PostureSchedule.Location; location != nil {
+		governance.ScheduleLocation = location.String()
+		_, governance.ScheduleOffset = s.nowLocked().In(location).Zone()
+	}
+	tokens := tokensSection{Active: []string{}, Revoked: []string{}}
+	for digest, token := range s.ActiveCapabilityTokens {
+		if token.RevokedAt == nil && !s.revokedTokens[digest] {
+			tokens.Active = append(tokens.Active, digest)
+		} else if !s.revokedTokens[digest] {
+			tokens.Revoked = append(tokens.Revoked, digest)
+		}
+	}
+	for digest := range s.revokedTokens {
+		tokens.Revoked = append(tokens.Revoked, digest)
+	}
+	sort.Strings(tokens.Active)
+	sort.Strings(tokens.Revoked)
+
+	values := map[string]interface{}{
+		SectionIdentity:   s.IdentityCapsule,
+		SectionAuthority:  s.AuthorityCapsule,
+		SectionGovernance: governance,
+		SectionPosture:    postures,
+		SectionTokens:     tokens,
+		SectionIntegrity:  s.IntegrityState,
+	}
+	snapshot := &StateSnapshot{Version: SnapshotVersion, CreatedAt: s.nowLocked().Unix()}
+	for _, name := range snapshotSections {
+		data, err := json.Marshal(values[name])
+		if err != nil {
+			return nil, fmt.Errorf("snapshot section %s: %w", name, err)
+		}
+		snapshot.Sections = append(snapshot.Sections, SnapshotSection{Name: name, Data: data, Hash: hashHex(data)})
+	}
+	snapshot.Digest = snapshot.digest()
+	return snapshot, nil
+}
+
+// Restore applies a snapshot Serialize wrote, once its signature by the
+// kernel's audit key, its version, every section hash, and its digest
+// check out, and its governance is the governance in force; nothing is
+// applied otherwise. The identity capsule is the snapshot's; its
+// revocations and halts are added to the kernel's, and each of its
+// consents is granted again only if the ledger's last consent_change for
+// the scope is the grant that made it. The posture is raised to the
+// snapshot's, integrity takes the worse of the two, and every token
+// digest in it is held revoked.
+func (s *SystemState) Restore(data []byte) error {
+	var snapshot StateSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("state snapshot unreadable: %w", err)
+	}
+	s.mu.RLock()
+	signer := s.auditSigner
+	s.mu.RUnlock()
+	if signer == nil {
+		return fmt.Errorf("state snapshot: no audit key to verify it with")
+	}
+	if snapshot.KeyID != signer.KeyID() {
+		return fmt.Errorf("state snapshot signed by %q, not the audit key %q", snapshot.KeyID, signer.KeyID())
+	}
+	if err := signing.Verify(signer.Public(), snapshotSigningMessage(snapshot.Digest), snapshot.Signature); err != nil {
+		return fmt.Errorf("state snapshot signature: %w", err)
+	}
+	sections, err := snapshot.verify()
+	if err != nil {
+		return err
+	}
+
+	var identity IdentityCapsule
+	var authority AuthorityCapsule
+	var governance governanceSection
+	var postures postureSection
+	var tokens tokensSection
+	var integrity IntegrityState
+	for name, value := range map[string]interface{}{
+		SectionIdentity:   &identity,
+		SectionAuthority:  &authority,
+		SectionGovernance: &governance,
+		SectionPosture:    &postures,
+		SectionTokens:     &tokens,
+		SectionIntegrity:  &integrity,
+	} {
+		if err := json.Unmarshal(sections[name], value); err != nil {
+			return fmt.Errorf("snapshot section %s unreadable: %w", name, err)
+		}
+	}
+	if _, known := integrityRank[integrity]; !known {
+		return fmt.Errorf("snapshot integrity state %q is not known", integrity)
+	}
+	if !posture.IsValid(postures.Level) {
+		return fmt.Errorf("snapshot posture %d is not defined", postures.Level)
+	}
+	for namespace, level := range postures.Namespaces {
+		if !posture.IsValid(level) {
+			return fmt.Errorf("snapshot posture %d of namespace %s is not defined", level, namespace)
+		}
+	}
+	governance.Capsule.PostureSchedule.Location = nil
+	if governance.ScheduleLocation != "" {
+		location, err := time.LoadLocation(governance.ScheduleLocation)
+		if err != nil {
+			location = time.FixedZone(governance.ScheduleLocation, governance.ScheduleOffset)
+		}
+		governance.Capsule.PostureSchedule.Location = location
+	}
+	if err := s.checkSnapshotGovernance(governance.Capsule); err != nil {
+		return err
+	}
+	consents, dropped := s.ledgeredConsents(authority.ActiveConsents)
+
+	s.mu.Lock()
+	s.IdentityCapsule = identity
+	for scope, consent := range consents {
+		s.AuthorityCapsule.ActiveConsents[scope] = consent
+	}
+	s.AuthorityCapsule.Revocations = mergeRevocations(authority.Revocations, s.AuthorityCapsule.Revocations)
+	for key, halt := range authority.Halts {
+		if s.AuthorityCapsule.Halts == nil {
+			s.AuthorityCapsule.Halts = make(map[string]Halt)
+		}
+		if _, held := s.AuthorityCapsule.Halts[key]; !held {
+			s.AuthorityCapsule.Halts[key] = halt
+		}
+	}
+	s.holdRevoked(append(tokens.Active, tokens.Revoked...))
+	worse := integrityRank[integrity] > integrityRank[s.IntegrityState]
+	s.mu.Unlock()
+
+	reason := fmt.Sprintf("restored from state snapshot %s", snapshot.Digest)
+	if worse {
+		s.SetIntegrityState(integrity)
+	}
+	s.Posture.Raise(postures.Level, reason)
+	for namespace, level := range postures.Namespaces {
+		s.NamespacePosture(namespace).Raise(level, reason)
+	}
+	s.AuditLedger.AppendStateRestored(s.attribution(""), snapshot.Digest, snapshot.CreatedAt, dropped)
+	return s.persistRevocation()
+}
+
+// checkSnapshotGovernance refuses a snapshot's governance unless it is the
+// governance committed, or the capsule in force if none is yet. WHY: The
+// pinned bundle key and CommitGovernance are the only ways governance
+// changes; a snapshot restoring its own would move the pin with it.
+func (s *SystemState) checkSnapshotGovernance(capsule GovernanceCapsule) error {
+	restored, err := capsule.Hash()
+	if err != nil {
+		return err
+	}
+	s.mu.RLock()
+	expected := s.governance.CapsuleHash
+	if expected == "" {
+		expected, err = s.GovernanceCapsule.Hash()
+	}
+	s.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	if restored != expected {
+		return fmt.Errorf("state snapshot governance %s is not the governance in force %s", restored, expected)
+	}
+	return nil
+}
+
+// ledgeredConsents returns the consents whose grant is the ledger's last
+// consent_change for their scope, and the scopes of those whose is not,
+// sorted. WHY: GrantConsent ledgers every grant with its expiry and a
+// hash of its evidence; a consent without that receipt was never granted
+// through it, and one revoked since must stay revoked.
+func (s *SystemState) ledgeredConsents(consents map[string]Consent) (map[string]Consent, []string) {
+	last := map[string]audit.Receipt{}
+	for _, receipt := range s.AuditLedger.GetReceipts() {
+		if receipt.EventType != "consent_change" {
+			continue
+		}
+		if scope, ok := receipt.EventData["scope"].(string); ok {
+			last[scope] = receipt
+		}
+	}
+
+	kept := make(map[string]Consent, len(consents))
+	dropped := []string{}
+	for scope, consent := range consents {
+		receipt, ok := last[scope]
+		evidenceHash := sha256.Sum256([]byte(consent.Evidence))
+		if ok && consent.Scope == scope &&
+			receipt.EventData["granted"] == true &&
+			eventInt64(receipt.EventData["expires_at"]) == consent.ExpiresAt &&
+			receipt.EventData["evidence_hash"] == hex.EncodeToString(evidenceHash[:]) {
+			kept[scope] = consent
+		} else {
+			dropped = append(dropped, scope)
+		}
+	}
+	sort.Strings(dropped)
+	return kept, dropped
+}
+
+// eventInt64 reads an integer from in-memory or JSON-decoded event data
+func eventInt64(value interface{}) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	default:
+		return -1
+	}
+}
+
+// mergeRevocations adds the revocations of restored not already in held
+func mergeRevocations(restored, held []Revocation) []Revocation {
+	seen := make(map[Revocation]bool, len(held))
+	for _, revocation := range held {
+		seen[revocation] = true
+	}
+	merged := []Revocation{}
+	for _, revocation := range restored {
+		if !seen[revocation] {
+			seen[revocation] = true
+			merged = append(merged, revocation)
+		}
+	}
+	return append(merged, held...)
+}
+
+// TokenRevoked reports whether digest names a token held revoked
+func (s *SystemState) TokenRevoked(digest string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.revokedTokens[digest]
+}
+
+// SaveSnapshot writes the kernel's state snapshot to path, replacing it
+// only once the new one is on disk
+func (s *SystemState) SaveSnapshot(path string) error {
+	data, err := s.Serialize()
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// writeFileAtomic replaces the file at path with data only once data is
+// on disk
+func writeFileAtomic(path string, data []byte) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), ".snapshot-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), path)
+}
+
+// LoadSnapshot restores the state snapshot at path
+func (s *SystemState) LoadSnapshot(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return s.Restore(data)
+}
+
+// verify checks the snapshot's version, that it carries every section
+// once with a matching hash, and its digest; it returns the sections by
+// name
+func (snapshot *StateSnapshot) verify() (map[string][]byte, error) {
+	if snapshot.Version != SnapshotVersion {
+		return nil, fmt.Errorf("state snapshot version %d is not supported", snapshot.Version)
+	}
+	if snapshot.digest() != snapshot.Digest {
+		return nil, fmt.Errorf("state snapshot digest mismatch")
+	}
+	sections := make(map[string][]byte, len(snapshot.Sections))
+	for _, section := range snapshot.Sections {
+		if _, seen := sections[section.Name]; seen {
+			return nil, fmt.Errorf("state snapshot section %s appears twice", section.Name)
+		}
+		if hashHex(section.Data) != section.Hash {
+			return nil, fmt.Errorf("state snapshot section %s hash mismatch", section.Name)
+		}
+		sections[section.Name] = section.Data
+	}
+	for _, name := range snapshotSections {
+		if _, ok := sections[name]; !ok {
+			return nil, fmt.Errorf("state snapshot section %s is missing", name)
+		}
+	}
+	return sections, nil
+}
+
+// snapshotSigningMessage is the message signed for a snapshot digest
+func snapshotSigningMessage(digest string) []byte {
+	return []byte(snapshotSignatureDomain + digest)
+}
+
+// digest hashes the section names and hashes in order
+func (snapshot *StateSnapshot) digest() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d\n%d\n", snapshot.Version, snapshot.CreatedAt)
+	for _, section := range snapshot.Sections {
+		fmt.Fprintf(h, "%s:%s\n", section.Name, section.Hash)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashHex returns the hex SHA-256 of data
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,178 @@
+// WHY: Proves a snapshot brings governance state back after a restart,
+// that a tampered or foreign one is refused whole, that it can neither
+// change governance nor grant a consent the ledger does not hold, and
+// that restoring never relaxes the posture or integrity of the kernel it
+// lands in.
+package kernel
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// restartOn returns a kernel restarted on a copy of state's ledger and
+// its audit key, as one reopening a persisted ledger is
+func restartOn(t *testing.T, state *SystemState) *SystemState {
+	t.Helper()
+	ledger, err := audit.OpenLedger(&failingStore{receipts: state.AuditLedger.GetReceipts()})
+	if err != nil {
+		t.Fatalf("reopen ledger: %v", err)
+	}
+	restarted := NewSystemState("user_123", "namespace_abc")
+	if err := restarted.AttachLedger(ledger, state.auditSigner); err != nil {
+		t.Fatalf("attach ledger: %v", err)
+	}
+	return restarted
+}
+
+// TestSnapshotRestoresGovernanceState proves consents, the posture, and
+// outstanding tokens survive a save and load into a kernel on the same
+// ledger and key
+func TestSnapshotRestoresGovernanceState(t *testing.T) {
+	state := NewSystemState("user_123", "namespace_abc")
+	state.GrantConsent("high_risk_operations", time.Hour, "settings page")
+	state.AuthorityCapsule.Revocations = append(state.AuthorityCapsule.Revocations, Revocation{Timestamp: 1, Scope: "email"})
+	state.GovernanceCapsule.PolicyVersion = "v7"
+	state.GovernanceCapsule.PostureSchedule.Location = time.FixedZone("UTC+2", 2*60*60)
+	state.Posture.Raise(posture.P3, "incident")
+	state.NamespacePosture("tenant_a").Raise(posture.P4, "tenant incident")
+	token, err := capabilities.Mint("kernel", "user_123", "mock_adapter", []string{"adapter:mock_adapter"}, capabilities.Limits{}, time.Minute, capabilities.PostureBounds{}, "namespace_abc", "user_123")
+	if err != nil {
+		t.Fatalf("mint: %v", err)
+	}
+	state.AddToken(token)
+
+	path := filepath.Join(t.TempDir(), "state.snapshot")
+	if err := state.SaveSnapshot(path); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	restarted := restartOn(t, state)
+	restarted.GovernanceCapsule.PolicyVersion = "v7"
+	restarted.GovernanceCapsule.PostureSchedule.Location = time.FixedZone("UTC+2", 2*60*60)
+	if err := restarted.LoadSnapshot(path); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if consent := restarted.AuthorityCapsule.ActiveConsents["high_risk_operations"]; consent.ExpiresAt == 0 || len(restarted.AuthorityCapsule.Revocations) != 1 {
+		t.Fatalf("consents and revocations must be restored, got %+v", restarted.AuthorityCapsule)
+	}
+	if restarted.PostureLevel() != posture.P3 || restarted.PostureLevelFor("tenant_a") != posture.P4 {
+		t.Fatalf("posture must be restored, got P%d and P%d", restarted.PostureLevel(), restarted.PostureLevelFor("tenant_a"))
+	}
+	if !restarted.TokenRevoked(token.Digest) {
+		t.Fatal("a token outstanding at the checkpoint must come back revoked")
+	}
+	if err := restarted.VerifyAuditLedger(); err != nil {
+		t.Fatalf("ledger: %v", err)
+	}
+}
+
+// TestSnapshotRefusesTampering proves an altered section, digest, or
+// signature, or another key's snapshot, is refused and nothing is applied
+func TestSnapshotRefusesTampering(t *testing.T) {
+	state := NewSystemState("user_123", "namespace_abc")
+	state.GrantConsent("high_risk_operations", 0, "settings page")
+	data, err := state.Serialize()
+	if err != nil {
+		t.Fatalf("serialize: %v", err)
+	}
+
+	tamper := map[string]func(*StateSnapshot){
+		"section": func(s *StateSnapshot) {
+			s.Sections[1].Data = json.RawMessage(`{"ActiveConsents":{"everything":{"Scope":"everything"}}}`)
+		},
+		"digest":  func(s *StateSnapshot) { s.CreatedAt++ },
+		"missing": func(s *StateSnapshot) { s.Sections = s.Sections[1:]; s.Digest = s.digest() },
+		"version": func(s *StateSnapshot) { s.Version++ },
+		"signature": func(s *StateSnapshot) {
+			s.Sections[1].Data = json.RawMessage(`{"ActiveConsents":{}}`)
+			s.Sections[1].Hash = hashHex(s.Sections[1].Data)
+			s.Digest = s.digest()
+		},
+		"key": func(s *StateSnapshot) { s.KeyID = "another_key" },
+	}
+	for name, alter := range tamper {
+		var snapshot StateSnapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		alter(&snapshot)
+		altered, _ := json.Marshal(snapshot)
+
+		fresh := restartOn(t, state)
+		if err := fresh.Restore(altered); err == nil {
+			t.Fatalf("%s: a tampered snapshot must be refused", name)
+		}
+		if len(fresh.AuthorityCapsule.ActiveConsents) != 0 {
+			t.Fatalf("%s: nothing may be applied from a refused snapshot", name)
+		}
+	}
+
+	foreign := NewSystemState("user_123", "namespace_abc")
+	if err := foreign.Restore(data); err == nil || len(foreign.AuthorityCapsule.ActiveConsents) != 0 {
+		t.Fatal("a snapshot another kernel key signed must be refused")
+	}
+}
+
+// TestRestoreHoldsToGovernanceAndLedger proves a snapshot cannot bring in
+// governance other than the kernel's, nor a consent whose grant the
+// ledger does not hold or has since revoked
+func TestRestoreHoldsToGovernanceAndLedger(t *testing.T) {
+	state := NewSystemState("user_123", "namespace_abc")
+	state.GrantConsent("high_risk_operations", 0, "settings page")
+	state.GrantConsent("email", time.Hour, "settings page")
+	state.AuthorityCapsule.ActiveConsents["everything"] = Consent{Scope: "everything", Evidence: "none"}
+	data, err := state.Serialize()
+	if err != nil {
+		t.Fatalf("serialize: %v", err)
+	}
+	state.RevokeConsent("email")
+
+	lax := restartOn(t, state)
+	lax.GovernanceCapsule.PolicyVersion = "lax"
+	if err := lax.Restore(data); err == nil || len(lax.AuthorityCapsule.ActiveConsents) != 0 {
+		t.Fatal("a snapshot whose governance is not in force must be refused")
+	}
+
+	restarted := restartOn(t, state)
+	if err := restarted.Restore(data); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	consents := restarted.AuthorityCapsule.ActiveConsents
+	if _, ok := consents["high_risk_operations"]; !ok || len(consents) != 1 {
+		t.Fatalf("only the consent the ledger still grants may come back, got %+v", consents)
+	}
+	restored, _ := restarted.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"state_restored"}})
+	if dropped := restored.Receipts[0].EventData["consents_dropped"]; len(dropped.([]string)) != 2 {
+		t.Fatalf("the dropped consents must be ledgered, got %v", dropped)
+	}
+}
+
+// TestRestoreNeverRelaxes proves restoring an older, laxer snapshot keeps
+// the posture and integrity the kernel already has
+func TestRestoreNeverRelaxes(t *testing.T) {
+	old := NewSystemState("user_123", "namespace_abc")
+	data, err := old.Serialize()
+	if err != nil {
+		t.Fatalf("serialize: %v", err)
+	}
+
+	state := restartOn(t, old)
+	state.Posture.Raise(posture.P4, "incident")
+	state.SetIntegrityState(IntegrityDegraded)
+	if err := state.Restore(data); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if state.PostureLevel() != posture.P4 {
+		t.Fatalf("restore must not lower the posture, got P%d", state.PostureLevel())
+	}
+	if state.GetIntegrityState() != IntegrityDegraded {
+		t.Fatalf("restore must keep the worse integrity, got %s", state.GetIntegrityState())
+	}
+}
@@ -3,13 +3,21 @@
 package kernel
 
 import (
+	"encoding/json"
+	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/user/oi/kernel-go/internal/adapters"
 	"github.com/user/oi/kernel-go/internal/audit"
 	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/clock"
+	"github.com/user/oi/kernel-go/internal/identity"
 	"github.com/user/oi/kernel-go/internal/memory"
+	"github.com/user/oi/kernel-go/internal/metrics"
 	"github.com/user/oi/kernel-go/internal/posture"
+	"github.com/user/oi/kernel-go/internal/signing"
 )
 
 // SystemState contains all governance-relevant state.
@@ -26,24 +34,94 @@ type SystemState struct {
 	// World model and semantic indexes
 	WorldPack       WorldPack
 	SemanticIndexes SemanticIndexes
-	ProfileStore    ProfileStore
+	ProfileStore    *ProfileStore
 
 	// Audit and integrity
 	AuditLedger    *audit.Ledger
 	IntegrityState IntegrityState
 
 	// Posture and capabilities
-	PostureLevel          int
+	Posture               *posture.State
+	PostureController     *posture.Controller
 	ActiveCapabilityTokens map[string]*capabilities.Token
 
 	// Adapters
 	AdapterRegistry *adapters.Registry
+	ModelAdapter    string // registered adapter the corridor routes requests to
 
 	// Memory subsystem
 	MemoryManager *memory.Manager
 
+	// Sessions holds the principals the kernel serves beyond its own
+	Sessions *SessionManager
+
 	// Declassification tracking
 	DeclassificationLedger DeclassificationLedger
+
+	// Operational metrics (mechanics only, never content)
+	Metrics *metrics.Kernel
+
+	// pseudonymizer, if set, applies to every attached audit ledger
+	pseudonymizer *audit.Pseudonymizer
+
+	// auditSigner signs the kernel's receipts and state snapshots (see
+	// AttachLedger)
+	auditSigner signing.Signer
+
+	// attestationSigner, if set, signs the kernel's attestation to plugins
+	attestationSigner signing.Signer
+
+	// verifierKeys holds the public keys of quarantine verifiers
+	verifierKeys *signing.KeyRing
+
+	// clock, if set, is the kernel clock (see SetClock)
+	clock clock.Clock
+
+	// scheduled is what the posture schedule last applied
+	scheduled scheduledPosture
+
+	// namespacePostures holds the posture of each namespace constrained
+	// beyond the kernel's
+	namespacePostures map[string]*posture.State
+
+	// revokedTokens holds the digests of tokens revoked, or named by a
+	// restored snapshot, that must never be honoured again
+	revokedTokens map[string]bool
+
+	// governance is the governance capsule committed (see
+	// CommitGovernance)
+	governance audit.Governance
+
+	// authorityPath, if set, is the authority store (see
+	// AttachAuthorityStore); authorityMu orders its writes
+	authorityPath string
+	authorityMu   sync.Mutex
+
+	// identityVerifier, if set, is what every request's bearer token is
+	// checked with (see SetIdentityVerifier)
+	identityVerifier *identity.Verifier
+
+	// adminVerifier, if set, is what admin bearer tokens are checked with
+	// (see SetAdminVerifier)
+	adminVerifier *identity.Verifier
+
+	// tokenSigner, if set, signs every token the kernel mints (see
+	// SetTokenSigner)
+	tokenSigner signing.Signer
+
+	// ledgerCheck is the outcome of the last VerifyAndEnforce
+	ledgerCheck LedgerCheck
+
+	// revocationNotifiers push a notice of every revocation to their
+	// endpoints (see NotifyRevocations)
+	revocationNotifiers []*RevocationNotifier
+
+	// corridors admits requests to Execute until the kernel quiesces
+	corridors corridorGate
+
+	// stopLatencySLO is how long a STOP may take to settle (see
+	// SetStopLatencySLO)
+	stopLatencySLO time.Duration
 }
 
 // IdentityCapsule holds user/principal identity information
@@ -55,8 +133,12 @@ type IdentityCapsule struct {
 
 // AuthorityCapsule holds authorization and consent state
 type AuthorityCapsule struct {
-	ActiveConsents map[string]bool
+	ActiveConsents map[string]Consent
 	Revocations    []Revocation
+
+	// Halts are the STOPs no token may be minted under until Resume, by
+	// haltKey
+	Halts map[string]Halt `json:",omitempty"`
 }
 
 // Revocation records when consent was withdrawn
@@ -70,10 +152,37 @@ type GovernanceCapsule struct {
 	PolicyVersion string
 	Rules         map[string]interface{}
 	Commitments   map[string]string // commitment_id -> hash
+
+	// Token minting policy: templates by name, and decision reason -> template name
+	TokenTemplates    map[string]TokenTemplate
+	TemplateSelectors map[string]string
+
+	// AdapterBuilds pins each plugin adapter's build hash (adapters.HashBuild);
+	// a plugin without a pin is an unknown binary and is not started
+	AdapterBuilds map[string]string
+
+	// QuarantineVerifiers maps each principal who may promote or reject
+	// quarantined memory to the key ID it signs with, and
+	// VerificationMethods lists the methods accepted
+	QuarantineVerifiers map[string]string
+	VerificationMethods []string
+
+	// PosturePolicy says what each posture level permits: token scopes,
+	// the sensitivity ceiling, confirmations, and the leak budget
+	PosturePolicy posture.Policy
+
+	// PostureSchedule holds the windows that raise posture on the calendar
+	PostureSchedule posture.Schedule
+
+	// PostureDowngrade governs lowering the posture: the cooldown and
+	// whether a second approver is needed
+	PostureDowngrade posture.DowngradeRules
 }
 
 // WorldPack holds environmental context
 type WorldPack struct {
+	// Timestamp, if set, is the world time (Unix seconds) posture windows
+	// are read at
 	Timestamp int64
 	Context   map[string]interface{}
 }
@@ -83,11 +192,6 @@ type SemanticIndexes struct {
 	Indexes map[string]interface{}
 }
 
-// ProfileStore holds user preferences and history
-type ProfileStore struct {
-	Profiles map[string]interface{}
-}
-
 // IntegrityState tracks system integrity
 type IntegrityState string
 
@@ -108,42 +212,381 @@ type DeclassificationEntry struct {
 	ContentHash string
 	Reason      string
 	Approver    string
+
+	// TokenDigest is the digest of the token the approver declassified with
+	TokenDigest string
+}
+
+// DefaultCheckpointInterval is the number of receipts between ledger checkpoints
+const DefaultCheckpointInterval = 100
+
+// DefaultModelAdapter is the adapter requests route to until one is configured
+const DefaultModelAdapter = "mock_adapter"
+
+// AuditKeyID names the kernel key that signs audit receipts
+const AuditKeyID = "kernel_audit"
+
+// DefaultGovernanceCapsule returns the governance a kernel starts with
+func DefaultGovernanceCapsule() GovernanceCapsule {
+	return GovernanceCapsule{
+		PolicyVersion: "v1",
+		Rules:         make(map[string]interface{}),
+		Commitments:   make(map[string]string),
+		AdapterBuilds: make(map[string]string),
+
+		QuarantineVerifiers: make(map[string]string),
+		VerificationMethods: []string{memory.MethodHumanReview, memory.MethodSourceSignature},
+
+		TokenTemplates:    DefaultTokenTemplates(),
+		TemplateSelectors: DefaultTemplateSelectors(),
+
+		PosturePolicy:    posture.DefaultPolicy(),
+		PostureDowngrade: posture.DefaultDowngradeRules(),
+	}
 }
 
 // NewSystemState creates a new system state with default values.
 // WHY: Fail-closed initialization - start with minimal permissions.
 func NewSystemState(principalID, namespaceID string) *SystemState {
-	return &SystemState{
+	state := &SystemState{
 		IdentityCapsule: IdentityCapsule{
 			PrincipalID: principalID,
 			NamespaceID: namespaceID,
 			Attributes:  make(map[string]string),
 		},
 		AuthorityCapsule: AuthorityCapsule{
-			ActiveConsents: make(map[string]bool),
+			ActiveConsents: make(map[string]Consent),
 			Revocations:    []Revocation{},
 		},
-		GovernanceCapsule: GovernanceCapsule{
-			PolicyVersion: "v1",
-			Rules:         make(map[string]interface{}),
-			Commitments:   make(map[string]string),
-		},
+		GovernanceCapsule: DefaultGovernanceCapsule(),
 		WorldPack: WorldPack{
 			Context: make(map[string]interface{}),
 		},
 		SemanticIndexes: SemanticIndexes{
 			Indexes: make(map[string]interface{}),
 		},
-		ProfileStore: ProfileStore{
-			Profiles: make(map[string]interface{}),
-		},
+		ProfileStore: NewProfileStore(),
 		AuditLedger:               audit.NewLedger(),
 		IntegrityState:            IntegrityOK,
-		PostureLevel:              posture.P1, // Default to most restrictive
+		Posture:                   posture.NewState(), // Default to most restrictive
 		ActiveCapabilityTokens:    make(map[string]*capabilities.Token),
+		revokedTokens:             make(map[string]bool),
 		AdapterRegistry:           adapters.NewRegistry(),
-		MemoryManager:             memory.NewManager(),
+		ModelAdapter:              DefaultModelAdapter,
+		MemoryManager:             memory.NewManager().Namespace(namespaceID),
 		DeclassificationLedger:    DeclassificationLedger{Entries: []DeclassificationEntry{}},
+		Metrics:                   metrics.NewKernel(),
+	}
+
+	// Breaker and lifecycle changes are not tied to one request, so they
+	// carry no request ID
+	state.AdapterRegistry.OnBreakerChange(func(change adapters.BreakerTransition) {
+		state.AuditLedger.AppendBreakerStateChange(state.attribution(""), change.Adapter, change.From, change.To, change.Reason)
+	})
+	state.AdapterRegistry.OnAdapterChange(func(change adapters.AdapterChange) {
+		state.AuditLedger.AppendAdapterLifecycle(state.attribution(""), change.Adapter, change.Action, change.InFlight)
+	})
+	state.Sessions = newSessionManager(state)
+	state.MemoryManager.OnOperation(state.recordMemoryOperation)
+	state.Posture.OnTransition(state.recordPostureChange)
+	state.PostureController, _ = posture.NewController(state.Posture, posture.DefaultRules())
+	state.MemoryManager.SetVerificationPolicy(state.verificationPolicy)
+	state.AdapterRegistry.SetPostureAllowlist(state.adapterAllowlist)
+	state.Metrics.SetAdapterStats(state.adapterSamples)
+
+	// WHY: A kernel that cannot sign its receipts cannot prove its history,
+	// so it starts with integrity void rather than unsigned.
+	signer, err := signing.GenerateLocalSigner(AuditKeyID)
+	if err == nil {
+		err = state.AttachLedger(state.AuditLedger, signer)
+	}
+	if err != nil {
+		state.IntegrityState = IntegrityVoid
+	}
+
+	return state
+}
+
+// recordMemoryOperation writes a receipt for a memory operation. Like
+// breaker changes, memory operations carry no request ID.
+func (s *SystemState) recordMemoryOperation(op memory.Operation) {
+	actor := s.attribution("")
+	// Adapters write through views of the namespace their token names
+	actor.NamespaceID = op.Namespace
+	switch op.Action {
+	case memory.OpWrite:
+		s.AuditLedger.AppendMemoryWrite(actor, op.Partition, op.ID, op.ContentHash)
+	case memory.OpRead:
+		s.AuditLedger.AppendMemoryRead(actor, op.Partition, op.ID, op.ContentHash)
+	case memory.OpPromote:
+		s.AuditLedger.AppendMemoryPromotion(actor, op.Partition, op.ID, op.ContentHash, op.Verifier, op.Method)
+	case memory.OpReject:
+		s.AuditLedger.AppendQuarantineClosed(actor, op.ID, op.ContentHash, memory.QuarantineRejected, op.Verifier, op.Method)
+	case memory.OpExpire:
+		s.AuditLedger.AppendQuarantineClosed(actor, op.ID, op.ContentHash, memory.QuarantineExpired, "", "")
+	case memory.OpRefuse:
+		s.AuditLedger.AppendMemoryQuotaExceeded(actor, op.Principal, op.Partition, op.ID, op.Reason)
+	case memory.OpDelete:
+		s.AuditLedger.AppendMemoryDeletion(actor, op.Partition, op.ID, op.ContentHash)
+	}
+}
+
+// adapterSamples reads the adapter registry's stats for the metrics
+func (s *SystemState) adapterSamples() []metrics.AdapterSample {
+	stats := s.AdapterRegistry.Stats()
+	samples := make([]metrics.AdapterSample, len(stats))
+	for i, adapter := range stats {
+		samples[i] = metrics.AdapterSample{
+			Adapter:          adapter.Adapter,
+			Successes:        adapter.Successes,
+			Errors:           adapter.Errors,
+			Timeouts:         adapter.Timeouts,
+			Refused:          adapter.Refused,
+			TotalLatency:     adapter.TotalLatency,
+			MaxLatency:       adapter.MaxLatency,
+			PromptTokens:     adapter.PromptTokens,
+			CompletionTokens: adapter.CompletionTokens,
+			Cost:             adapter.Cost,
+		}
+	}
+	return samples
+}
+
+// AttachLedger makes ledger the kernel's audit ledger, with checkpoints
+// stored as evidence and receipts signed by signer.
+// WHY: Persistent ledgers are opened outside the kernel but must carry the
+// same checkpoint and signing policy as the default in-memory one.
+func (s *SystemState) AttachLedger(ledger *audit.Ledger, signer signing.Signer) error {
+	ledger.SetCheckpointPolicy(DefaultCheckpointInterval, s.storeCheckpoint)
+	if err := ledger.SetSigner(signer); err != nil {
+		return fmt.Errorf("audit signer: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ledger.SetPseudonymizer(s.pseudonymizer)
+	s.AuditLedger = ledger
+	s.auditSigner = signer
+	return nil
+}
+
+// LoadAdapterManifest registers the adapters a manifest declares and routes
+// requests to its model adapter, if it names one
+func (s *SystemState) LoadAdapterManifest(path string) error {
+	manifest, err := adapters.LoadManifest(path)
+	if err != nil {
+		return err
+	}
+	deps := adapters.ManifestDeps{
+		Memory:         s.MemoryManager,
+		Consents:       s.activeConsents,
+		IntegrityState: func() string { return string(s.GetIntegrityState()) },
+		Attestor:       s.attestor(),
+	}
+	if err := manifest.Register(s.AdapterRegistry, deps); err != nil {
+		return fmt.Errorf("adapter manifest %s: %w", path, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if manifest.ModelAdapter != "" {
+		s.ModelAdapter = manifest.ModelAdapter
+	}
+	return nil
+}
+
+// AttachMemoryStore persists the memory manager's persistent partitions in
+// store and loads what it already holds. It must be called before anything
+// is written to those partitions.
+func (s *SystemState) AttachMemoryStore(store memory.Store) error {
+	if err := s.MemoryManager.Attach(store); err != nil {
+		return fmt.Errorf("memory store: %w", err)
+	}
+	return nil
+}
+
+// SetGovernanceKeys sets the keys governance signs commitment updates
+// with. WHY: Without them the commitments partition can be neither
+// written nor read, so commitments are never trusted unsigned.
+func (s *SystemState) SetGovernanceKeys(keys *signing.KeyRing) {
+	s.MemoryManager.SetCommitmentKeys(keys)
+}
+
+// ReadMemory reads an entry from the kernel's memory redacted under the
+// governance posture policy for the current posture (see
+// memory.ReadWithPolicy)
+func (s *SystemState) ReadMemory(partition string, id string) (*memory.Entry, error) {
+	policy, err := s.GovernanceCapsule.PostureLevelPolicy(s.PostureLevel())
+	if err != nil {
+		return nil, err
+	}
+	return s.MemoryManager.ReadWithPolicy(partition, id, policy)
+}
+
+// SetClock sets the clock memory entries, receipts, and capability tokens
+// are stamped and checked with. WHY: One clock for the whole kernel keeps
+// a token's expiry and the receipts about it on the same timeline, and
+// lets tests move time instead of sleeping.
+func (s *SystemState) SetClock(c clock.Clock) {
+	if c == nil {
+		c = clock.Real{}
+	}
+	s.mu.Lock()
+	s.clock = c
+	for _, state := range s.namespacePostures {
+		state.SetClock(c)
+	}
+	s.mu.Unlock()
+	s.MemoryManager.SetClock(c)
+	s.AuditLedger.SetClock(c)
+	s.Posture.SetClock(c)
+	s.PostureController.SetClock(c)
+	capabilities.SetClock(c)
+}
+
+// SetQuarantineVerifierKeys sets the public keys quarantine verifiers sign
+// with; the governance capsule names which verifier uses which key
+func (s *SystemState) SetQuarantineVerifierKeys(keys *signing.KeyRing) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.verifierKeys = keys
+}
+
+// verificationPolicy reads the quarantine verification policy from the
+// governance capsule. WHY: Until verifier keys are set, no record can
+// verify, so nothing leaves quarantine.
+func (s *SystemState) verificationPolicy() memory.VerificationPolicy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	verifiers := make(map[string]string, len(s.GovernanceCapsule.QuarantineVerifiers))
+	for principal, keyID := range s.GovernanceCapsule.QuarantineVerifiers {
+		verifiers[principal] = keyID
+	}
+	return memory.VerificationPolicy{
+		Verifiers: verifiers,
+		Methods:   append([]string(nil), s.GovernanceCapsule.VerificationMethods...),
+		Keys:      s.verifierKeys,
+	}
+}
+
+// SetAttestationSigner sets the key the kernel attests itself to plugins
+// with. WHY: Plugins pin the public half, so it must be a deployment key
+// rather than one generated at startup.
+func (s *SystemState) SetAttestationSigner(signer signing.Signer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attestationSigner = signer
+}
+
+// attestor builds the registry side of plugin attestation: the namespace
+// is the registry identity, and policy version and pins are read live
+func (s *SystemState) attestor() *adapters.Attestor {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return &adapters.Attestor{
+		RegistryID: s.IdentityCapsule.NamespaceID,
+		Signer:     s.attestationSigner,
+		PolicyVersion: func() string {
+			s.mu.RLock()
+			defer s.mu.RUnlock()
+			return s.GovernanceCapsule.PolicyVersion
+		},
+		BuildHashes: func() map[string]string {
+			s.mu.RLock()
+			defer s.mu.RUnlock()
+			pins := make(map[string]string, len(s.GovernanceCapsule.AdapterBuilds))
+			for name, hash := range s.GovernanceCapsule.AdapterBuilds {
+				pins[name] = hash
+			}
+			return pins
+		},
+	}
+}
+
+// SetAuditPseudonymizer enables pseudonymous audit receipts with a
+// per-deployment salt; nil disables it.
+// WHY: The setting belongs to the deployment, so it follows the kernel
+// across AttachLedger rather than living on one ledger.
+func (s *SystemState) SetAuditPseudonymizer(p *audit.Pseudonymizer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pseudonymizer = p
+	s.AuditLedger.SetPseudonymizer(p)
+}
+
+// storeCheckpoint writes a ledger checkpoint into the evidence partition.
+// WHY: The evidence partition is append-only, so a checkpoint written there
+// outlives any later rewrite of the receipt chain.
+func (s *SystemState) storeCheckpoint(checkpoint audit.Checkpoint) error {
+	content, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	return s.MemoryManager.Write(memory.PartitionEvidence, checkpointEntryID(checkpoint), string(content), map[string]interface{}{
+		"kind": "ledger_checkpoint",
+	})
+}
+
+// VerifyAuditCheckpoints checks the chain against the checkpoint copies held
+// in the evidence partition: each must be recorded in the chain and match
+// it, and each checkpoint in the chain must have its copy.
+// WHY: Detects history rewrites that recomputed every hash in the chain.
+// The evidence is the anchor, so it is what is walked: a rewrite that also
+// dropped checkpoint receipts must not shrink what is checked.
+func (s *SystemState) VerifyAuditCheckpoints() error {
+	// Listing the evidence is itself ledgered, and may cut a checkpoint
+	inChain := s.AuditLedger.Checkpoints()
+	entries, err := s.MemoryManager.List(memory.PartitionEvidence, memory.EntryFilter{
+		Metadata: map[string]interface{}{"kind": "ledger_checkpoint"},
+	})
+	if err != nil {
+		return fmt.Errorf("checkpoint evidence unreadable: %w", err)
+	}
+	external := []audit.Checkpoint{}
+	held := map[string]bool{}
+	for _, entry := range entries {
+		var stored audit.Checkpoint
+		if err := json.Unmarshal([]byte(entry.Content), &stored); err != nil {
+			return fmt.Errorf("checkpoint evidence %s unreadable: %w", entry.ID, err)
+		}
+		external = append(external, stored)
+		held[checkpointEntryID(stored)] = true
+	}
+	for _, recorded := range inChain {
+		if !held[checkpointEntryID(recorded)] {
+			return fmt.Errorf("checkpoint evidence missing: %s", checkpointEntryID(recorded))
+		}
+	}
+	return s.AuditLedger.VerifyCheckpoints(external)
+}
+
+// VerifyAuditLedger verifies the receipt chain, its signatures, and its
+// checkpoints against the evidence partition.
+// WHY: Failures are counted so monitoring sees tampering without reading
+// the ledger.
+func (s *SystemState) VerifyAuditLedger() error {
+	_, err := s.AuditLedger.Verify()
+	if err == nil {
+		err = s.VerifyAuditCheckpoints()
+	}
+	if err != nil {
+		s.Metrics.LedgerVerifyFailures.Inc()
+	}
+	return err
+}
+
+// checkpointEntryID names the evidence entry for a checkpoint
+func checkpointEntryID(checkpoint audit.Checkpoint) string {
+	return fmt.Sprintf("ledger_checkpoint_%d_%d", checkpoint.StartSequence, checkpoint.EndSequence)
+}
+
+// attribution attributes receipts to this kernel's principal and namespace
+func (s *SystemState) attribution(requestID string) audit.Attribution {
+	return audit.Attribution{
+		PrincipalID: s.IdentityCapsule.PrincipalID,
+		NamespaceID: s.IdentityCapsule.NamespaceID,
+		RequestID:   requestID,
 	}
 }
 
@@ -151,11 +594,14 @@ func NewSystemState(principalID, namespaceID string) *SystemState {
 // WHY: State transitions must be explicit and auditable.
 func (s *SystemState) SetIntegrityState(state IntegrityState) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	s.IntegrityState = state
 	// Log to audit
-	s.AuditLedger.AppendIntegrityStateChange(string(state))
+	s.AuditLedger.AppendIntegrityStateChange(s.attribution(""), string(state))
+	s.mu.Unlock()
+
+	if state != IntegrityOK {
+		s.observePosture(posture.SignalIntegrity, string(state))
+	}
 }
 
 // GetIntegrityState returns current integrity state (thread-safe)
@@ -165,25 +611,129 @@ func (s *SystemState) GetIntegrityState() IntegrityState {
 	return s.IntegrityState
 }
 
+// activeConsents returns the user's unexpired consents (thread-safe)
+func (s *SystemState) activeConsents() map[string]bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	now := s.nowLocked().Unix()
+	consents := make(map[string]bool, len(s.AuthorityCapsule.ActiveConsents))
+	for scope, consent := range s.AuthorityCapsule.ActiveConsents {
+		if consent.activeAt(now) {
+			consents[scope] = true
+		}
+	}
+	return consents
+}
+
 // RevokeAllTokens implements STOP dominance by revoking all active tokens.
 // WHY: User STOP must immediately revoke all capability.
 func (s *SystemState) RevokeAllTokens() {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.revokeAllTokens(audit.StopReasonUserPanic)
+}
 
-	for _, token := range s.ActiveCapabilityTokens {
+// revokeAllTokens implements the global STOP for reason
+func (s *SystemState) revokeAllTokens(reason string) {
+	s.stopWhere(s.attribution(""), audit.StopScopeGlobal, "", reason, func(*capabilities.Token) bool {
+		return true
+	})
+}
+
+// RevokeTokensFor implements STOP for one principal by revoking the
+// active tokens minted to principalID, and returns how many it revoked.
+// WHY: A user's STOP must take back their capability at once without
+// taking everyone else's; RevokeAllTokens remains the operator's STOP.
+func (s *SystemState) RevokeTokensFor(principalID string) int {
+	actor := s.attribution("")
+	actor.PrincipalID = principalID
+	digests, _ := s.stopWhere(actor, audit.StopScopePrincipal, principalID, audit.StopReasonUserPanic, func(token *capabilities.Token) bool {
+		return token.PrincipalID == principalID && token.RevokedAt == nil
+	})
+	return len(digests)
+}
+
+// RevokeToken implements STOP for one token by revoking the active token
+// with digest, and reports whether there was one to revoke
+func (s *SystemState) RevokeToken(digest string) bool {
+	digests, _ := s.stopWhere(s.attribution(""), audit.StopScopeToken, digest, audit.StopReasonOperatorAction, func(token *capabilities.Token) bool {
+		return token.Digest == digest && token.RevokedAt == nil
+	})
+	return len(digests) > 0
+}
+
+// stopWhere revokes the held tokens match selects, cancels their calls in
+// flight, and ledgers a STOP of scope on target for reason. A global or
+// principal STOP also halts minting for what it covers until Resume. It
+// returns the revoked digests, sorted, and how many calls it cancelled.
+func (s *SystemState) stopWhere(actor audit.Attribution, scope string, target string, reason string, match func(*capabilities.Token) bool) ([]string, int) {
+	started := time.Now()
+	s.mu.Lock()
+	// A drained quiesce leaves nothing to halt; the kernel is closed
+	if scope != audit.StopScopeToken && reason != audit.StopReasonQuiesce {
+		s.holdHalt(Halt{Scope: scope, Target: target, Reason: reason, StoppedAt: s.nowLocked().Unix()})
+	}
+	var digests []string
+	for digest, token := range s.ActiveCapabilityTokens {
+		if !match(token) {
+			continue
+		}
 		token.Revoke()
+		digests = append(digests, digest)
 	}
+	sort.Strings(digests)
+	s.holdRevoked(digests)
+	s.Metrics.TokensRevoked.Add(float64(len(digests)))
 
 	// Log to audit
-	s.AuditLedger.AppendStopEvent(len(s.ActiveCapabilityTokens))
+	s.AuditLedger.AppendScopedStopEvent(actor, scope, target, reason, len(digests))
+	s.mu.Unlock()
+	revoked := time.Now()
+
+	// Calls already inside an adapter are cancelled, not left to finish
+	var released []<-chan struct{}
+	if scope == audit.StopScopeGlobal {
+		released = s.AdapterRegistry.StopAllCalls()
+	} else {
+		released = s.AdapterRegistry.StopCalls(digests...)
+	}
+	s.measureStop(actor, scope, target, reason, started, revoked, released)
+	cancelled := len(released)
+
+	// The tokens are already revoked; a failed write only degrades integrity
+	s.persistRevocation()
+	if scope == audit.StopScopeGlobal || len(digests) > 0 {
+		s.notifyRevocation(scope, target, reason, digests)
+	}
+	return digests, cancelled
 }
 
-// AddToken registers a new active capability token
-func (s *SystemState) AddToken(token *capabilities.Token) {
+// AddToken registers a new active capability token, signing it with the
+// kernel's token key if it has one
+func (s *SystemState) AddToken(token *capabilities.Token) error {
+	if err := s.signToken(token); err != nil {
+		return err
+	}
+	s.addToken(token, "")
+	return nil
+}
+
+// addToken registers a token minted for the given request
+func (s *SystemState) addToken(token *capabilities.Token, requestID string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	s.ActiveCapabilityTokens[token.Digest] = token
-	s.AuditLedger.AppendTokenMint(token.Digest, token.Scope)
+	s.Metrics.TokensMinted.Inc(token.Provenance.TemplateID)
+	actor := audit.Attribution{
+		PrincipalID: token.PrincipalID,
+		NamespaceID: token.NamespaceID,
+		RequestID:   requestID,
+	}
+	s.AuditLedger.AppendTokenMint(actor, audit.TokenMint{
+		TokenDigest:  token.Digest,
+		Scope:        token.Scope,
+		ParentDigest: token.Provenance.ParentDigest,
+		RequestHash:  token.Provenance.RequestHash,
+		DecisionID:   token.Provenance.DecisionID,
+		TemplateID:   token.Provenance.TemplateID,
+	})
 }
@@ -0,0 +1,200 @@
+// WHY: STOP could only be pulled by code holding the state, so an operator
+// or a user outside the process had no way to reach it. InvokeStop takes a
+// STOP from an authenticated caller, globally, for a principal, or for one
+// token, and StopHandler serves it as an admin endpoint. STOP may always be
+// pulled, but only on everything or on what is the caller's own, and every
+// invocation leaves one receipt summarizing what it revoked and cancelled.
+package kernel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/capabilities"
+)
+
+// maxStopRequestBytes bounds the body StopHandler reads
+const maxStopRequestBytes = 1 << 16
+
+// Reasons reported in StopRefusedError and ResumeRefusedError
+const (
+	StopRefusedUnauthenticated = "unauthenticated"
+	StopRefusedInvalid         = "invalid"
+	StopRefusedForbidden       = "forbidden"
+)
+
+// StopRequest names what a STOP revokes
+type StopRequest struct {
+	// Scope is audit.StopScopeGlobal, audit.StopScopePrincipal, or
+	// audit.StopScopeToken
+	Scope string `json:"scope"`
+
+	// Target is the principal of a principal STOP (empty means the
+	// caller's own) or the token digest of a token STOP
+	Target string `json:"target,omitempty"`
+
+	// Reason is audit.StopReasonUserPanic, StopReasonIntegrityFailure, or
+	// StopReasonOperatorAction; empty means StopReasonOperatorAction
+	Reason string `json:"reason,omitempty"`
+}
+
+// StopSummary is what a STOP revoked and cancelled
+type StopSummary struct {
+	Scope          string   `json:"scope"`
+	Target         string   `json:"target,omitempty"`
+	Reason         string   `json:"reason"`
+	TokensRevoked  int      `json:"tokens_revoked"`
+	TokenDigests   []string `json:"token_digests"`
+	CallsCancelled int      `json:"calls_cancelled"`
+}
+
+// StopRefusedError reports a STOP invocation that was not carried out.
+// WHY: A distinct type lets the admin endpoint tell an unverified caller
+// from a malformed or overreaching request.
+type StopRefusedError struct {
+	Reason string // StopRefusedUnauthenticated, StopRefusedInvalid, or StopRefusedForbidden
+	Err    error
+}
+
+func (e *StopRefusedError) Error() string {
+	return fmt.Sprintf("stop refused (%s): %v", e.Reason, e.Err)
+}
+
+func (e *StopRefusedError) Unwrap() error {
+	return e.Err
+}
+
+// InvokeStop carries out stop for the caller bearer identifies and
+// returns what it revoked. bearer must verify under the identity verifier
+// set with SetIdentityVerifier.
+func (s *SystemState) InvokeStop(bearer string, stop StopRequest) (StopSummary, error) {
+	actor := s.attribution("")
+	caller, err := s.authenticate(bearer, actor)
+	if err != nil {
+		s.AuditLedger.AppendStopRequest(actor, stop.Scope, stop.Target, stop.Reason, StopRefusedUnauthenticated, nil, 0)
+		return StopSummary{}, &StopRefusedError{Reason: StopRefusedUnauthenticated, Err: err}
+	}
+	actor.PrincipalID, actor.NamespaceID = caller.PrincipalID, caller.NamespaceID
+
+	if stop.Scope == audit.StopScopePrincipal && stop.Target == "" {
+		stop.Target = caller.PrincipalID
+	}
+	if stop.Reason == "" {
+		stop.Reason = audit.StopReasonOperatorAction
+	}
+	match, refusal := s.stopMatch(caller, stop)
+	if refusal != nil {
+		s.AuditLedger.AppendStopRequest(actor, stop.Scope, stop.Target, stop.Reason, refusal.Reason, nil, 0)
+		return StopSummary{}, refusal
+	}
+
+	digests, cancelled := s.stopWhere(actor, stop.Scope, stop.Target, stop.Reason, match)
+	s.AuditLedger.AppendStopRequest(actor, stop.Scope, stop.Target, stop.Reason, "", digests, cancelled)
+	return StopSummary{
+		Scope:          stop.Scope,
+		Target:         stop.Target,
+		Reason:         stop.Reason,
+		TokensRevoked:  len(digests),
+		TokenDigests:   append([]string{}, digests...),
+		CallsCancelled: cancelled,
+	}, nil
+}
+
+// stopMatch returns the tokens stop revokes for caller, or why caller may
+// not invoke it. WHY: A global STOP takes everything, so anyone may pull
+// it; a narrower one must not reach into another principal's capability.
+func (s *SystemState) stopMatch(caller IdentityCapsule, stop StopRequest) (func(*capabilities.Token) bool, *StopRefusedError) {
+	switch stop.Reason {
+	case audit.StopReasonUserPanic, audit.StopReasonIntegrityFailure, audit.StopReasonOperatorAction:
+	default:
+		return nil, &StopRefusedError{Reason: StopRefusedInvalid, Err: fmt.Errorf("STOP reason %q is not defined", stop.Reason)}
+	}
+	switch stop.Scope {
+	case audit.StopScopeGlobal:
+		if stop.Target != "" {
+			return nil, &StopRefusedError{Reason: StopRefusedInvalid, Err: fmt.Errorf("a global STOP takes no target")}
+		}
+		return func(*capabilities.Token) bool { return true }, nil
+	case audit.StopScopePrincipal:
+		if stop.Target != caller.PrincipalID {
+			return nil, &StopRefusedError{Reason: StopRefusedForbidden, Err: fmt.Errorf("principal %s may not STOP principal %s", caller.PrincipalID, stop.Target)}
+		}
+		return func(token *capabilities.Token) bool {
+			return token.PrincipalID == stop.Target && token.RevokedAt == nil
+		}, nil
+	case audit.StopScopeToken:
+		if stop.Target == "" {
+			return nil, &StopRefusedError{Reason: StopRefusedInvalid, Err: fmt.Errorf("a token STOP needs a token digest")}
+		}
+		s.mu.RLock()
+		held, ok := s.ActiveCapabilityTokens[stop.Target]
+		s.mu.RUnlock()
+		if !ok || held.PrincipalID != caller.PrincipalID || held.NamespaceID != caller.NamespaceID {
+			return nil, &StopRefusedError{Reason: StopRefusedForbidden, Err: fmt.Errorf("token %s is not one principal %s holds", stop.Target, caller.PrincipalID)}
+		}
+		return func(token *capabilities.Token) bool {
+			return token.Digest == stop.Target && token.RevokedAt == nil
+		}, nil
+	default:
+		return nil, &StopRefusedError{Reason: StopRefusedInvalid, Err: fmt.Errorf("STOP scope %q is not defined", stop.Scope)}
+	}
+}
+
+// StopHandler serves InvokeStop as an admin endpoint. A POST carries a
+// JSON StopRequest and the caller's bearer token in Authorization; the
+// response is the JSON StopSummary, or {"error": ...} with 400, 401, or
+// 403 when the STOP is refused.
+func (s *SystemState) StopHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeStopResponse(w, http.StatusMethodNotAllowed, map[string]string{"error": "STOP takes POST"})
+			return
+		}
+		bearer, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		var stop StopRequest
+		decoder := json.NewDecoder(io.LimitReader(r.Body, maxStopRequestBytes))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&stop); err != nil {
+			writeStopResponse(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("STOP request: %v", err)})
+			return
+		}
+
+		summary, err := s.InvokeStop(bearer, stop)
+		var refused *StopRefusedError
+		if err == nil {
+			writeStopResponse(w, http.StatusOK, summary)
+			return
+		}
+		reason := StopRefusedInvalid
+		if errors.As(err, &refused) {
+			reason = refused.Reason
+		}
+		writeStopResponse(w, refusalStatus(reason), map[string]string{"error": err.Error()})
+	})
+}
+
+// refusalStatus is the HTTP status of a STOP or Resume refused for reason
+func refusalStatus(reason string) int {
+	switch reason {
+	case StopRefusedUnauthenticated:
+		return http.StatusUnauthorized
+	case StopRefusedForbidden:
+		return http.StatusForbidden
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// writeStopResponse writes body as JSON with status
+func writeStopResponse(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
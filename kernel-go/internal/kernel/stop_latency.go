@@ -0,0 +1,84 @@
+// WHY: The conformance claim that STOP preempts in-flight operations was
+// only ever asserted, never timed. Every STOP now measures how long it
+// took to settle: from its invocation until its tokens were revoked, and
+// on until the last adapter call it cancelled had really returned, which
+// is the last side effect it could not prevent. The measurement is
+// ledgered as stop_latency against a latency SLO and observed in the
+// oi_stop_latency_seconds histogram. A STOP never waits on it.
+package kernel
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+)
+
+// DefaultStopLatencySLO is how long a STOP may take to settle unless
+// SetStopLatencySLO says otherwise
+const DefaultStopLatencySLO = 100 * time.Millisecond
+
+// stopLatencyHorizon bounds how long a STOP's cancelled calls are waited
+// on; a call still running then is counted outstanding
+const stopLatencyHorizon = 30 * time.Second
+
+// SetStopLatencySLO sets how long a STOP may take to settle before its
+// stop_latency receipt is a warning
+func (s *SystemState) SetStopLatencySLO(slo time.Duration) error {
+	if slo <= 0 {
+		return fmt.Errorf("stop latency SLO must be positive, got %s", slo)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopLatencySLO = slo
+	return nil
+}
+
+// measureStop records the latency of a STOP invoked at started whose
+// tokens were revoked at revoked, once every cancelled call in released
+// has returned; with calls to wait on it does so in the background
+func (s *SystemState) measureStop(actor audit.Attribution, scope string, target string, reason string, started time.Time, revoked time.Time, released []<-chan struct{}) {
+	s.mu.RLock()
+	slo := s.stopLatencySLO
+	s.mu.RUnlock()
+	if slo == 0 {
+		slo = DefaultStopLatencySLO
+	}
+
+	record := func() {
+		settled := revoked
+		outstanding := 0
+		expired := false
+		horizon := time.NewTimer(stopLatencyHorizon)
+		defer horizon.Stop()
+		for _, call := range released {
+			if expired {
+				select {
+				case <-call:
+				default:
+					outstanding++
+				}
+				continue
+			}
+			select {
+			case <-call:
+				settled = time.Now()
+			case <-horizon.C:
+				expired = true
+				outstanding++
+			}
+		}
+		if outstanding > 0 {
+			settled = time.Now()
+		}
+
+		latency := settled.Sub(started)
+		s.Metrics.StopLatency.Observe(latency.Seconds(), scope)
+		s.AuditLedger.AppendStopLatency(actor, scope, target, reason, revoked.Sub(started), latency, slo, len(released), outstanding)
+	}
+	if len(released) == 0 {
+		record()
+		return
+	}
+	go record()
+}
@@ -0,0 +1,88 @@
+// WHY: Proves every STOP leaves a stop_latency receipt and a histogram
+// observation, timed to the last cancelled call really returning, and
+// that a STOP slower than its SLO is a warning.
+package kernel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/adapters"
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/capabilities"
+)
+
+// lingeringAdapter finishes its side effect for linger after its call is
+// cancelled
+type lingeringAdapter struct {
+	*adapters.MockAdapter
+	linger time.Duration
+}
+
+func (a *lingeringAdapter) Invoke(ctx context.Context, token *capabilities.Token, params map[string]interface{}) (*adapters.AdapterResult, error) {
+	<-ctx.Done()
+	time.Sleep(a.linger)
+	return nil, ctx.Err()
+}
+
+// awaitStopLatency waits until count stop_latency receipts are ledgered
+func awaitStopLatency(t *testing.T, state *SystemState, count int) []audit.Receipt {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		page, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"stop_latency"}})
+		if len(page.Receipts) >= count {
+			return page.Receipts
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d stop_latency receipts, got %d", count, len(page.Receipts))
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestStopLatencyIsMeasuredToTheLastSideEffect proves a STOP is timed
+// until its cancelled call returns, not only until its caller is released
+func TestStopLatencyIsMeasuredToTheLastSideEffect(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	if err := state.SetStopLatencySLO(0); err == nil {
+		t.Fatal("a zero SLO must be refused")
+	}
+
+	state.RevokeAllTokens()
+	idle := awaitStopLatency(t, state, 1)[0]
+	if idle.EventData["within_slo"] != true || idle.EventData["calls_cancelled"] != 0 || idle.Severity != audit.SeverityInfo {
+		t.Fatalf("a STOP with nothing in flight must settle within the SLO, got %+v", idle.EventData)
+	}
+
+	if err := state.SetStopLatencySLO(time.Millisecond); err != nil {
+		t.Fatalf("set SLO: %v", err)
+	}
+	adapter := &lingeringAdapter{MockAdapter: adapters.NewMockAdapter("lingering_adapter"), linger: 20 * time.Millisecond}
+	state.AdapterRegistry.Register(adapter)
+	token := mintApprover(t, state, "alice", "lingering_adapter")
+	go state.AdapterRegistry.Invoke("lingering_adapter", token, 1, map[string]interface{}{})
+	deadline := time.Now().Add(time.Second)
+	for state.AdapterRegistry.InFlight(token.Digest) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("call never started")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	state.RevokeTokensFor("alice")
+	slow := awaitStopLatency(t, state, 2)[1]
+	if slow.EventData["stop_scope"] != audit.StopScopePrincipal || slow.EventData["calls_cancelled"] != 1 || slow.EventData["calls_outstanding"] != 0 {
+		t.Fatalf("the receipt must account for the cancelled call, got %+v", slow.EventData)
+	}
+	if latency := slow.EventData["latency_us"].(int64); latency < adapter.linger.Microseconds() {
+		t.Fatalf("latency must run until the adapter returned, got %dus", latency)
+	}
+	if slow.EventData["within_slo"] != false || slow.Severity != audit.SeverityWarn {
+		t.Fatalf("a STOP slower than its SLO must be a warning, got %+v", slow)
+	}
+	if state.Metrics.StopLatency.Count(audit.StopScopeGlobal) != 1 || state.Metrics.StopLatency.Count(audit.StopScopePrincipal) != 1 {
+		t.Fatal("every STOP must be observed in the latency histogram")
+	}
+}
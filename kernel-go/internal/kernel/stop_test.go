@@ -0,0 +1,138 @@
+// WHY: Proves STOP reached through the admin surface acts only for a
+// verified caller, only on everything or on the caller's own capability,
+// and leaves a receipt summarizing what it revoked.
+package kernel
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/identity"
+)
+
+// stopKernel returns a kernel verifying bearer tokens signed with the
+// returned key
+func stopKernel(t *testing.T) (*SystemState, ed25519.PrivateKey) {
+	t.Helper()
+	state := NewSystemState("test_principal", "test_namespace")
+	public, key, _ := ed25519.GenerateKey(rand.Reader)
+	if err := state.SetIdentityVerifier(&identity.Verifier{
+		Issuer:   "https://issuer.example",
+		Audience: "oi-kernel",
+		Keys:     identity.KeySet{"issuer_key": public},
+	}); err != nil {
+		t.Fatalf("set verifier: %v", err)
+	}
+	return state, key
+}
+
+// TestInvokeStopReachesOnlyTheCallersOwn proves a principal or token STOP
+// is confined to the caller, and a global STOP is open to any of them
+func TestInvokeStopReachesOnlyTheCallersOwn(t *testing.T) {
+	state, key := stopKernel(t)
+	expires := time.Now().Add(time.Hour)
+	alice := bearerFor(t, key, "alice", "test_namespace", expires)
+	aliceToken := mintApprover(t, state, "alice", "scope1")
+	aliceOther := mintApprover(t, state, "alice", "scope2")
+	bobToken := mintApprover(t, state, "bob", "scope1")
+
+	var refused *StopRefusedError
+	for name, tc := range map[string]struct {
+		bearer string
+		stop   StopRequest
+		reason string
+	}{
+		"no bearer":         {"", StopRequest{Scope: audit.StopScopeGlobal}, StopRefusedUnauthenticated},
+		"another principal": {alice, StopRequest{Scope: audit.StopScopePrincipal, Target: "bob"}, StopRefusedForbidden},
+		"another's token":   {alice, StopRequest{Scope: audit.StopScopeToken, Target: bobToken.Digest}, StopRefusedForbidden},
+		"undefined scope":   {alice, StopRequest{Scope: "namespace"}, StopRefusedInvalid},
+	} {
+		if _, err := state.InvokeStop(tc.bearer, tc.stop); !errors.As(err, &refused) || refused.Reason != tc.reason {
+			t.Fatalf("%s: expected %s refusal, got %v", name, tc.reason, err)
+		}
+	}
+	if bobToken.RevokedAt != nil || aliceToken.RevokedAt != nil {
+		t.Fatal("a refused STOP must revoke nothing")
+	}
+
+	summary, err := state.InvokeStop(alice, StopRequest{Scope: audit.StopScopeToken, Target: aliceToken.Digest})
+	if err != nil || summary.TokensRevoked != 1 || aliceToken.RevokedAt == nil || aliceOther.RevokedAt != nil {
+		t.Fatalf("a token STOP must revoke only that token: %+v (%v)", summary, err)
+	}
+	summary, err = state.InvokeStop(alice, StopRequest{Scope: audit.StopScopePrincipal})
+	if err != nil || summary.Target != "alice" || summary.TokensRevoked != 1 || bobToken.RevokedAt != nil {
+		t.Fatalf("a principal STOP must revoke only the caller's live tokens: %+v (%v)", summary, err)
+	}
+	summary, err = state.InvokeStop(alice, StopRequest{Scope: audit.StopScopeGlobal})
+	if err != nil || bobToken.RevokedAt == nil {
+		t.Fatalf("a global STOP must revoke every token: %+v (%v)", summary, err)
+	}
+
+	page, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"stop_request"}})
+	if len(page.Receipts) != 7 {
+		t.Fatalf("every invocation must be ledgered, got %d receipts", len(page.Receipts))
+	}
+	last := page.Receipts[len(page.Receipts)-1]
+	if last.EventData["accepted"] != true || last.EventData["stop_scope"] != audit.StopScopeGlobal || last.EventData["principal_id"] != "alice" {
+		t.Fatalf("the receipt must summarize the STOP and its caller, got %+v", last)
+	}
+	if page.Receipts[0].Severity != audit.SeverityWarn {
+		t.Fatalf("a refused STOP must be a warning, got %s", page.Receipts[0].Severity)
+	}
+}
+
+// TestStopHandlerServesInvokeStop proves the admin endpoint maps refusals
+// to their status and returns the summary of a STOP it carried out
+func TestStopHandlerServesInvokeStop(t *testing.T) {
+	state, key := stopKernel(t)
+	token := mintApprover(t, state, "alice", "scope1")
+	server := httptest.NewServer(state.StopHandler())
+	defer server.Close()
+
+	post := func(bearer string, body string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(body))
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("post: %v", err)
+		}
+		return resp
+	}
+	alice := bearerFor(t, key, "alice", "test_namespace", time.Now().Add(time.Hour))
+
+	if resp, _ := http.Get(server.URL); resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("GET must be refused, got %d", resp.StatusCode)
+	}
+	for body, status := range map[string]int{
+		`{"scope":"global"}`:                   http.StatusUnauthorized,
+		`{"scope":"principal","target":"bob"}`: http.StatusForbidden,
+		`{"scope":"global","everything":true}`: http.StatusBadRequest,
+	} {
+		bearer := alice
+		if status == http.StatusUnauthorized {
+			bearer = ""
+		}
+		if resp := post(bearer, body); resp.StatusCode != status {
+			t.Fatalf("%s: expected %d, got %d", body, status, resp.StatusCode)
+		}
+	}
+
+	resp := post(alice, `{"scope":"principal"}`)
+	var summary StopSummary
+	if err := json.NewDecoder(resp.Body).Decode(&summary); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("STOP: %d (%v)", resp.StatusCode, err)
+	}
+	if summary.TokensRevoked != 1 || summary.TokenDigests[0] != token.Digest || token.RevokedAt == nil {
+		t.Fatalf("the response must summarize the revocation, got %+v", summary)
+	}
+}
@@ -0,0 +1,83 @@
+// WHY: Token minting is policy, not code. Named, versioned templates in the
+// governance capsule decide scope, TTL, limits, and posture bounds, so every
+// minted token can be traced to the exact policy revision that shaped it.
+package kernel
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// DefaultTemplateSelector is consulted when no selector matches a decision reason
+const DefaultTemplateSelector = "default"
+
+// TokenTemplate is a named, versioned capability token policy
+type TokenTemplate struct {
+	Name          string
+	Version       string
+	Scope         []string // upper bound on granted scope; CDI may narrow further
+	TTL           time.Duration
+	Limits        capabilities.Limits
+	PostureBounds capabilities.PostureBounds
+}
+
+// ID identifies the template revision as "name@version"
+func (t TokenTemplate) ID() string {
+	return t.Name + "@" + t.Version
+}
+
+// DefaultTokenTemplates returns the built-in templates installed by NewSystemState
+func DefaultTokenTemplates() map[string]TokenTemplate {
+	return map[string]TokenTemplate{
+		"standard": {
+			Name:          "standard",
+			Version:       "v1",
+			Scope:         []string{"*"},
+			TTL:           5 * time.Minute,
+			Limits:        capabilities.Limits{MaxDepth: 10, MaxBudget: 1000, WorkspaceBounds: []string{}, MaxConcurrent: 4, Timeout: time.Minute},
+			PostureBounds: capabilities.PostureBounds{MinPosture: posture.P1, MaxPosture: posture.P4},
+		},
+		"degraded": {
+			Name:          "degraded",
+			Version:       "v1",
+			Scope:         []string{"*"},
+			TTL:           1 * time.Minute,
+			Limits:        capabilities.Limits{MaxDepth: 3, MaxBudget: 100, WorkspaceBounds: []string{}, MaxConcurrent: 1, Timeout: 30 * time.Second},
+			PostureBounds: capabilities.PostureBounds{MinPosture: posture.P1, MaxPosture: posture.P4},
+		},
+	}
+}
+
+// DefaultTemplateSelectors maps CDI decision reasons to template names
+func DefaultTemplateSelectors() map[string]string {
+	return map[string]string{
+		"clean_low_sensitivity": "standard",
+		"medium_sensitivity":    "degraded",
+		"integrity_degraded":    "degraded",
+		DefaultTemplateSelector: "standard",
+	}
+}
+
+// TemplateFor selects the token template for a CDI decision reason.
+// WHY: Fail-closed - a reason with no selector and no default mints nothing.
+func (g *GovernanceCapsule) TemplateFor(reason string) (TokenTemplate, error) {
+	name, ok := g.TemplateSelectors[reason]
+	if !ok {
+		name, ok = g.TemplateSelectors[DefaultTemplateSelector]
+	}
+	if !ok {
+		return TokenTemplate{}, fmt.Errorf("no token template selected for reason %q", reason)
+	}
+
+	template, ok := g.TokenTemplates[name]
+	if !ok {
+		return TokenTemplate{}, fmt.Errorf("token template %q not defined", name)
+	}
+	if template.TTL <= 0 {
+		return TokenTemplate{}, fmt.Errorf("token template %s has non-positive TTL", template.ID())
+	}
+	return template, nil
+}
@@ -0,0 +1,42 @@
+// WHY: A token's authority was checked against the kernel's record by
+// digest, and Token.Sign and VerifySignature went unused, so an adapter
+// registry shared with another caller took any token that matched the
+// checks it could make itself. With a token signer set, every token the
+// kernel mints is signed and the registry refuses one that is not signed
+// by the kernel's key, whoever hands it over.
+package kernel
+
+import (
+	"fmt"
+
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+// SetTokenSigner signs every token minted from now on with signer, and
+// requires calls through the adapter registry to carry a token it signed
+func (s *SystemState) SetTokenSigner(signer signing.Signer) error {
+	if signer == nil {
+		return fmt.Errorf("nil token signer")
+	}
+	keys := signing.NewKeyRing()
+	if err := keys.AddSigner(signer); err != nil {
+		return fmt.Errorf("token signer: %w", err)
+	}
+	s.mu.Lock()
+	s.tokenSigner = signer
+	s.mu.Unlock()
+	s.AdapterRegistry.SetTokenKeys(keys)
+	return nil
+}
+
+// signToken signs token with the token signer, if one is set
+func (s *SystemState) signToken(token *capabilities.Token) error {
+	s.mu.RLock()
+	signer := s.tokenSigner
+	s.mu.RUnlock()
+	if signer == nil {
+		return nil
+	}
+	return token.Sign(signer)
+}
@@ -0,0 +1,78 @@
+// WHY: Proves a kernel with a token signer signs what it mints and that
+// its adapter registry refuses a token the kernel's key did not sign.
+package kernel
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/adapters"
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/posture"
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+// TestTokenSignerGatesTheRegistry proves minted and registered tokens are
+// signed and invoke adapters, while an unsigned token or an altered copy
+// of a signed one is refused before the adapter runs
+func TestTokenSignerGatesTheRegistry(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	mock := adapters.NewMockAdapter("mock_adapter")
+	state.AdapterRegistry.Register(mock)
+	state.GovernanceCapsule.Rules = map[string]interface{}{"exists": true}
+	signer, err := signing.GenerateLocalSigner("token_key")
+	if err != nil {
+		t.Fatalf("signer: %v", err)
+	}
+	if err := state.SetTokenSigner(signer); err != nil {
+		t.Fatalf("set token signer: %v", err)
+	}
+	keys := signing.NewKeyRing()
+	keys.AddSigner(signer)
+
+	resp, err := Execute(&Request{RawInput: "test request", Metadata: map[string]interface{}{}}, state)
+	if err != nil || !resp.Success {
+		t.Fatalf("a request must run on a signed token: %v %+v", err, resp)
+	}
+	for _, token := range state.ActiveCapabilityTokens {
+		if err := token.VerifySignature(keys); err != nil {
+			t.Fatalf("minted token must carry the kernel's signature: %v", err)
+		}
+	}
+
+	mint := func() *capabilities.Token {
+		token, err := capabilities.Mint("kernel", "alice", "adapters", []string{"mock_adapter"}, capabilities.Limits{}, time.Minute,
+			capabilities.PostureBounds{MinPosture: posture.P1, MaxPosture: posture.P4}, "test_namespace", "alice")
+		if err != nil {
+			t.Fatalf("mint: %v", err)
+		}
+		return token
+	}
+	invoke := func(token *capabilities.Token) error {
+		_, err := state.AdapterRegistry.InvokeContext(context.Background(), "mock_adapter", token, posture.P2, map[string]interface{}{})
+		return err
+	}
+
+	calls := len(mock.GetInvocations())
+	if err := invoke(mint()); err == nil || !strings.Contains(err.Error(), "not signed") {
+		t.Fatalf("an unsigned token must be refused, got %v", err)
+	}
+
+	registered := mint()
+	if err := state.AddToken(registered); err != nil {
+		t.Fatalf("add token: %v", err)
+	}
+	altered := *registered
+	altered.Scope = append([]string{"fs:write:/**"}, registered.Scope...)
+	if err := invoke(&altered); err == nil {
+		t.Fatal("an altered copy of a signed token must be refused")
+	}
+	if n := len(mock.GetInvocations()); n != calls {
+		t.Fatalf("a refused token must not reach the adapter, got %d calls", n-calls)
+	}
+	if err := invoke(registered); err != nil {
+		t.Fatalf("a token the kernel registered is signed by it: %v", err)
+	}
+}
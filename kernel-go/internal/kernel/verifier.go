@@ -0,0 +1,106 @@
+// WHY: Tamper detection only protects the corridor if something acts on it.
+// The verifier re-checks the audit ledger on a schedule and, on failure,
+// fails the kernel closed: integrity goes VOID (so CDI denies everything)
+// and every capability token is revoked.
+package kernel
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// DefaultVerifyInterval is how often the background verifier checks the ledger
+const DefaultVerifyInterval = time.Minute
+
+// LedgerVerifier runs VerifyAndEnforce on a fixed interval until stopped
+type LedgerVerifier struct {
+	state    *SystemState
+	interval time.Duration
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// StartLedgerVerifier starts a background verifier for the kernel's audit ledger
+func (s *SystemState) StartLedgerVerifier(interval time.Duration) (*LedgerVerifier, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("verify interval must be positive, got %s", interval)
+	}
+
+	v := &LedgerVerifier{
+		state:    s,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go v.run()
+	return v, nil
+}
+
+func (v *LedgerVerifier) run() {
+	defer close(v.done)
+
+	ticker := time.NewTicker(v.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			v.state.VerifyAndEnforce()
+		case <-v.stop:
+			return
+		}
+	}
+}
+
+// Stop halts the verifier and waits for an in-flight check to finish
+func (v *LedgerVerifier) Stop() {
+	v.stopOnce.Do(func() { close(v.stop) })
+	<-v.done
+}
+
+// LedgerCheck is the outcome of a VerifyAndEnforce
+type LedgerCheck struct {
+	// CheckedAt is when it ran, in Unix seconds; 0 if it never has
+	CheckedAt int64 `json:"checked_at"`
+
+	// Error is why the ledger failed to verify; empty if it verified
+	Error string `json:"error,omitempty"`
+}
+
+// LastLedgerCheck returns the outcome of the last VerifyAndEnforce
+func (s *SystemState) LastLedgerCheck() LedgerCheck {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ledgerCheck
+}
+
+// VerifyAndEnforce verifies the audit ledger and, if it fails, records the
+// tamper, signals the posture controller, sets INTEGRITY_VOID, and revokes
+// all tokens. It returns the
+// verification error.
+// WHY: A kernel already VOID has nothing left to revoke; re-enforcing on
+// every tick would only flood the ledger with duplicate receipts.
+func (s *SystemState) VerifyAndEnforce() error {
+	err := s.VerifyAuditLedger()
+	s.mu.Lock()
+	s.ledgerCheck = LedgerCheck{CheckedAt: s.nowLocked().Unix()}
+	if err != nil {
+		s.ledgerCheck.Error = err.Error()
+	}
+	s.mu.Unlock()
+	if err == nil || s.GetIntegrityState() == IntegrityVoid {
+		return err
+	}
+
+	s.AuditLedger.AppendTamperDetected(s.attribution(""), err.Error())
+	s.observePosture(posture.SignalLedgerFailure, err.Error())
+	s.SetIntegrityState(IntegrityVoid)
+	s.revokeAllTokens(audit.StopReasonIntegrityFailure)
+	return err
+}
@@ -0,0 +1,140 @@
+// WHY: Proves ledger failures are acted on, not just detectable.
+package kernel
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/adapters"
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+// failingStore is an in-memory store whose appends can be made to fail
+type failingStore struct {
+	receipts []audit.Receipt
+	fail     bool
+}
+
+func (f *failingStore) Append(receipt audit.Receipt) error {
+	if f.fail {
+		return fmt.Errorf("disk full")
+	}
+	f.receipts = append(f.receipts, receipt)
+	return nil
+}
+
+func (f *failingStore) Load() ([]audit.Receipt, error) { return f.receipts, nil }
+func (f *failingStore) Close() error                   { return nil }
+
+// newVerifiedState returns a kernel whose ledger writes to store, with one
+// successful request already executed
+func newVerifiedState(t *testing.T, store *failingStore) *SystemState {
+	t.Helper()
+
+	state := NewSystemState("test_principal", "test_namespace")
+	state.AdapterRegistry.Register(adapters.NewMockAdapter("mock_adapter"))
+	state.GovernanceCapsule.Rules = map[string]interface{}{"exists": true}
+
+	ledger, err := audit.OpenLedger(store)
+	if err != nil {
+		t.Fatalf("open ledger: %v", err)
+	}
+	signer, err := signing.GenerateLocalSigner(AuditKeyID)
+	if err != nil {
+		t.Fatalf("signer: %v", err)
+	}
+	if err := state.AttachLedger(ledger, signer); err != nil {
+		t.Fatalf("attach ledger: %v", err)
+	}
+
+	if resp, err := Execute(&Request{RawInput: "test request", Metadata: map[string]interface{}{}}, state); err != nil || !resp.Success {
+		t.Fatalf("pipeline should succeed: %v", err)
+	}
+	return state
+}
+
+// TestVerifyAndEnforceVoidsOnFailure proves a failed verification voids
+// integrity, revokes every token, and records the tamper
+func TestVerifyAndEnforceVoidsOnFailure(t *testing.T) {
+	store := &failingStore{}
+	state := newVerifiedState(t, store)
+
+	if err := state.VerifyAndEnforce(); err != nil {
+		t.Fatalf("healthy ledger should verify: %v", err)
+	}
+	if state.GetIntegrityState() != IntegrityOK {
+		t.Fatal("healthy ledger must not change integrity")
+	}
+
+	// A lost receipt poisons the ledger
+	store.fail = true
+	state.AuditLedger.AppendPostureChange(state.attribution(""), 1, 2, "test")
+	store.fail = false
+
+	if err := state.VerifyAndEnforce(); err == nil {
+		t.Fatal("ledger with a lost receipt should fail verification")
+	}
+	if state.GetIntegrityState() != IntegrityVoid {
+		t.Fatalf("integrity should be VOID, got %s", state.GetIntegrityState())
+	}
+	for digest, token := range state.ActiveCapabilityTokens {
+		if token.RevokedAt == nil {
+			t.Fatalf("token %s should be revoked", digest)
+		}
+	}
+	if state.Metrics.LedgerVerifyFailures.Value() != 1 {
+		t.Fatal("verify failure should be counted")
+	}
+
+	page, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"tamper_detected"}})
+	if len(page.Receipts) != 1 {
+		t.Fatalf("expected one tamper receipt, got %d", len(page.Receipts))
+	}
+
+	// Already VOID: later failures are counted but not re-enforced
+	state.VerifyAndEnforce()
+	page, _ = state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"tamper_detected"}})
+	if len(page.Receipts) != 1 {
+		t.Fatal("a VOID kernel should not record the tamper again")
+	}
+
+	resp, _ := Execute(&Request{RawInput: "test request", Metadata: map[string]interface{}{}}, state)
+	if resp.Success {
+		t.Fatal("a VOID kernel must deny every request")
+	}
+	if state.Metrics.Denials.Value("input", "integrity_void") != 1 {
+		t.Fatal("request should be denied for integrity_void")
+	}
+}
+
+// TestLedgerVerifierRunsInBackground proves the scheduled verifier enforces without a caller
+func TestLedgerVerifierRunsInBackground(t *testing.T) {
+	store := &failingStore{}
+	state := newVerifiedState(t, store)
+
+	if _, err := state.StartLedgerVerifier(0); err == nil {
+		t.Fatal("non-positive interval should be rejected")
+	}
+
+	verifier, err := state.StartLedgerVerifier(5 * time.Millisecond)
+	if err != nil {
+		t.Fatalf("start verifier: %v", err)
+	}
+	defer verifier.Stop()
+
+	store.fail = true
+	state.AuditLedger.AppendPostureChange(state.attribution(""), 1, 2, "test")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for state.GetIntegrityState() != IntegrityVoid {
+		if time.Now().After(deadline) {
+			t.Fatal("background verifier never voided integrity")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	verifier.Stop()
+	verifier.Stop() // idempotent
+}
@@ -0,0 +1,237 @@
+// WHY: The world pack was an empty map nothing filled, so a rule about
+// time, locale, deployment, or an ongoing incident had nothing to read but
+// what a caller chose to assert. World providers fill it, a WorldUpdater
+// refreshes it on a schedule, and each update is ledgered with the hash of
+// what it set. A provider that fails has its part dropped rather than left
+// stale, so no rule reads an old answer as a current one.
+package kernel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/clock"
+)
+
+// DefaultWorldInterval is how often the background updater refreshes the
+// world pack
+const DefaultWorldInterval = time.Minute
+
+// Names of the built-in world providers, and the world pack keys they fill
+const (
+	WorldTime        = "time"
+	WorldLocale      = "locale"
+	WorldEnvironment = "environment"
+	WorldIncidents   = "incidents"
+)
+
+// WorldProvider supplies one part of the world pack
+type WorldProvider interface {
+	// Name is the world pack key the provider fills
+	Name() string
+
+	// Provide returns the provider's current value
+	Provide() (interface{}, error)
+}
+
+// TimeProvider provides the current time, in Unix seconds, from Clock or
+// the system clock. The updater also sets the world pack's Timestamp from
+// it, so posture windows are read at it.
+type TimeProvider struct {
+	Clock clock.Clock
+}
+
+// Name returns WorldTime
+func (TimeProvider) Name() string { return WorldTime }
+
+// Provide returns the current Unix time
+func (p TimeProvider) Provide() (interface{}, error) {
+	if p.Clock != nil {
+		return p.Clock.Now().Unix(), nil
+	}
+	return time.Now().Unix(), nil
+}
+
+// StaticProvider provides a fixed value under a name, such as the
+// deployment environment or locale a process was started with
+type StaticProvider struct {
+	Key   string
+	Value string
+}
+
+// Name returns the provider's key
+func (p StaticProvider) Name() string { return p.Key }
+
+// Provide returns the value, or an error if it is empty
+func (p StaticProvider) Provide() (interface{}, error) {
+	if p.Value == "" {
+		return nil, fmt.Errorf("world %s is not set", p.Key)
+	}
+	return p.Value, nil
+}
+
+// EnvProvider provides the value of an environment variable under a name
+type EnvProvider struct {
+	Key      string
+	Variable string
+}
+
+// Name returns the provider's key
+func (p EnvProvider) Name() string { return p.Key }
+
+// Provide returns the variable's value, or an error if it is unset
+func (p EnvProvider) Provide() (interface{}, error) {
+	return StaticProvider{Key: p.Key, Value: os.Getenv(p.Variable)}.Provide()
+}
+
+// IncidentFileProvider provides the incident flags listed in a JSON file
+// as an array of strings; a missing file means no incidents
+type IncidentFileProvider struct {
+	Path string
+}
+
+// Name returns WorldIncidents
+func (IncidentFileProvider) Name() string { return WorldIncidents }
+
+// Provide returns the sorted incident flags
+func (p IncidentFileProvider) Provide() (interface{}, error) {
+	data, err := os.ReadFile(p.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var flags []string
+	if err := json.Unmarshal(data, &flags); err != nil {
+		return nil, fmt.Errorf("incident file %s: %w", p.Path, err)
+	}
+	sort.Strings(flags)
+	return flags, nil
+}
+
+// UpdateWorld refreshes the world pack from providers and ledgers the
+// update with the hash of the world pack it leaves. A provider that fails
+// has its key removed; the error names every one that did.
+func (s *SystemState) UpdateWorld(providers []WorldProvider) error {
+	values := make(map[string]interface{}, len(providers))
+	var updated, failed []string
+	var errs []error
+	for _, provider := range providers {
+		value, err := provider.Provide()
+		if err != nil {
+			failed = append(failed, provider.Name())
+			errs = append(errs, fmt.Errorf("world provider %s: %w", provider.Name(), err))
+			continue
+		}
+		values[provider.Name()] = value
+		updated = append(updated, provider.Name())
+	}
+
+	s.mu.Lock()
+	context := make(map[string]interface{}, len(s.WorldPack.Context)+len(values))
+	for key, value := range s.WorldPack.Context {
+		context[key] = value
+	}
+	for _, key := range failed {
+		delete(context, key)
+		if key == WorldTime {
+			s.WorldPack.Timestamp = 0
+		}
+	}
+	for key, value := range values {
+		context[key] = value
+	}
+	s.WorldPack.Context = context
+	if now, ok := values[WorldTime].(int64); ok {
+		s.WorldPack.Timestamp = now
+	}
+	hash, err := s.WorldPack.Hash()
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	s.AuditLedger.AppendWorldUpdate(s.attribution(""), hash, updated, failed)
+	return errors.Join(errs...)
+}
+
+// Hash returns the hex SHA-256 of the world pack's encoding
+func (w *WorldPack) Hash() (string, error) {
+	data, err := json.Marshal(w)
+	if err != nil {
+		return "", fmt.Errorf("encode world pack: %w", err)
+	}
+	return hashHex(data), nil
+}
+
+// worldContext returns a copy of the world pack's context
+func (s *SystemState) worldContext() map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	context := make(map[string]interface{}, len(s.WorldPack.Context))
+	for key, value := range s.WorldPack.Context {
+		context[key] = value
+	}
+	return context
+}
+
+// WorldUpdater runs UpdateWorld on a fixed interval until stopped
+type WorldUpdater struct {
+	state     *SystemState
+	interval  time.Duration
+	providers []WorldProvider
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// StartWorldUpdater updates the world pack from providers now and then on
+// every interval
+func (s *SystemState) StartWorldUpdater(interval time.Duration, providers ...WorldProvider) (*WorldUpdater, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("world update interval must be positive, got %s", interval)
+	}
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("world updater needs a provider")
+	}
+
+	u := &WorldUpdater{
+		state:     s,
+		interval:  interval,
+		providers: providers,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	s.UpdateWorld(providers)
+	go u.run()
+	return u, nil
+}
+
+func (u *WorldUpdater) run() {
+	defer close(u.done)
+
+	ticker := time.NewTicker(u.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			u.state.UpdateWorld(u.providers)
+		case <-u.stop:
+			return
+		}
+	}
+}
+
+// Stop halts the updater and waits for an in-flight update to finish
+func (u *WorldUpdater) Stop() {
+	u.stopOnce.Do(func() { close(u.stop) })
+	<-u.done
+}
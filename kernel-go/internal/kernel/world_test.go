@@ -0,0 +1,117 @@
+// WHY: Proves world providers fill the world pack, that each update is
+// ledgered with its hash, that a failing provider's part is dropped rather
+// than left stale, and that the updater runs without a caller.
+package kernel
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/clock"
+)
+
+// TestWorldProvidersFillTheWorldPack proves an update sets every
+// provider's part, the world time, and a hashed receipt
+func TestWorldProvidersFillTheWorldPack(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	now := clock.NewFake(time.Unix(1_700_000_000, 0))
+	incidents := filepath.Join(t.TempDir(), "incidents.json")
+	providers := []WorldProvider{
+		TimeProvider{Clock: now},
+		StaticProvider{Key: WorldLocale, Value: "en-GB"},
+		StaticProvider{Key: WorldEnvironment, Value: "production"},
+		IncidentFileProvider{Path: incidents},
+	}
+
+	if err := state.UpdateWorld(providers); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	world := state.worldContext()
+	if world[WorldTime] != now.Now().Unix() || world[WorldLocale] != "en-GB" || world[WorldEnvironment] != "production" {
+		t.Fatalf("every provider's part must be set, got %v", world)
+	}
+	if flags, _ := world[WorldIncidents].([]string); len(flags) != 0 {
+		t.Fatalf("a missing incident file means no incidents, got %v", flags)
+	}
+	if state.worldTime().Unix() != now.Now().Unix() {
+		t.Fatal("posture windows must be read at the provided time")
+	}
+
+	page, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"world_update"}})
+	hash, _ := state.WorldPack.Hash()
+	if len(page.Receipts) != 1 || page.Receipts[0].EventData["world_hash"] != hash {
+		t.Fatalf("the update must be ledgered with the world pack's hash, got %+v", page.Receipts)
+	}
+
+	if err := os.WriteFile(incidents, []byte(`["outage","breach"]`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	state.UpdateWorld(providers)
+	if flags, _ := state.worldContext()[WorldIncidents].([]string); len(flags) != 2 || flags[0] != "breach" {
+		t.Fatalf("incident flags must be read from the file, got %v", flags)
+	}
+}
+
+// TestFailingWorldProviderIsDropped proves a provider that fails has its
+// previous answer removed, not kept
+func TestFailingWorldProviderIsDropped(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	incidents := filepath.Join(t.TempDir(), "incidents.json")
+	os.WriteFile(incidents, []byte(`["outage"]`), 0o600)
+	providers := []WorldProvider{IncidentFileProvider{Path: incidents}, StaticProvider{Key: WorldLocale, Value: "en-GB"}}
+	if err := state.UpdateWorld(providers); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	os.WriteFile(incidents, []byte(`not json`), 0o600)
+	if err := state.UpdateWorld(providers); err == nil {
+		t.Fatal("a failing provider must be reported")
+	}
+	world := state.worldContext()
+	if _, stale := world[WorldIncidents]; stale {
+		t.Fatal("a failing provider's previous answer must be dropped")
+	}
+	if world[WorldLocale] != "en-GB" {
+		t.Fatal("the other providers must still update")
+	}
+	page, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"world_update"}})
+	if failed, _ := page.Receipts[1].EventData["failed"].([]string); len(failed) != 1 || failed[0] != WorldIncidents {
+		t.Fatalf("the receipt must name the failed provider, got %v", page.Receipts[1].EventData)
+	}
+}
+
+// TestWorldUpdaterRunsInBackground proves the updater refreshes the world
+// pack without a caller
+func TestWorldUpdaterRunsInBackground(t *testing.T) {
+	state := NewSystemState("test_principal", "test_namespace")
+	if _, err := state.StartWorldUpdater(0, TimeProvider{}); err == nil {
+		t.Fatal("a non-positive interval must be refused")
+	}
+	if _, err := state.StartWorldUpdater(time.Millisecond); err == nil {
+		t.Fatal("an updater without providers must be refused")
+	}
+
+	updater, err := state.StartWorldUpdater(5*time.Millisecond, StaticProvider{Key: WorldEnvironment, Value: "staging"})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		page, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"world_update"}})
+		if len(page.Receipts) >= 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the updater did not run")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	updater.Stop()
+	updater.Stop()
+	if state.worldContext()[WorldEnvironment] != "staging" {
+		t.Fatal("the updater must fill the world pack")
+	}
+}
@@ -0,0 +1,165 @@
+// WHY: System commitments are promises the kernel keeps across sessions,
+// so any code path holding the manager must not be able to edit one. The
+// commitments partition takes only updates signed with a governance key,
+// each with a version higher than the last, and re-verifies the signature
+// whenever a commitment is read, so an entry altered in memory or in the
+// store is refused instead of trusted.
+package memory
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+// commitmentDomain separates commitment signatures from every other
+// signature a governance key might make
+const commitmentDomain = "oi-commitment-update-v1"
+
+// Metadata keys a commitment entry carries its signed update under
+const (
+	MetadataCommitmentVersion   = "commitment_version"
+	MetadataCommitmentKeyID     = "commitment_key_id"
+	MetadataCommitmentSignature = "commitment_signature"
+)
+
+// CommitmentUpdate is a governance-signed write to the commitments partition
+type CommitmentUpdate struct {
+	ID      string
+	Content string
+
+	// Version must exceed the version of the commitment it replaces
+	Version uint64
+
+	KeyID     string
+	Signature []byte
+}
+
+// SigningMessage is the canonical encoding the signature covers
+func (u *CommitmentUpdate) SigningMessage() []byte {
+	message, _ := json.Marshal([]string{
+		commitmentDomain, u.ID, hashContent(u.Content), strconv.FormatUint(u.Version, 10), u.KeyID,
+	})
+	return message
+}
+
+// Sign sets the update's key ID and signature
+func (u *CommitmentUpdate) Sign(signer signing.Signer) error {
+	u.KeyID = signer.KeyID()
+	signature, err := signer.Sign(u.SigningMessage())
+	if err != nil {
+		return fmt.Errorf("sign commitment %s: %w", u.ID, err)
+	}
+	u.Signature = signature
+	return nil
+}
+
+// SetCommitmentKeys sets the governance keys commitment updates must be
+// signed with. Until it is called, commitments can be neither written
+// nor read.
+func (m *Manager) SetCommitmentKeys(keys *signing.KeyRing) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.commitmentKeys = keys
+}
+
+// WriteCommitment verifies a signed update and writes it to the
+// commitments partition
+func (m *Manager) WriteCommitment(update CommitmentUpdate) error {
+	entry, err := m.writeCommitment(update)
+	if err != nil {
+		return err
+	}
+	m.notify(Operation{Action: OpWrite, Partition: PartitionCommitments, ID: update.ID, ContentHash: entry.ContentHash})
+	return nil
+}
+
+// writeCommitment is WriteCommitment without the notification
+func (m *Manager) writeCommitment(update CommitmentUpdate) (*Entry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.verifyUpdateLocked(update); err != nil {
+		return nil, err
+	}
+	p := m.partitions[PartitionCommitments]
+	if current, exists := p.Entries[m.key(update.ID)]; exists {
+		// WHY: A replayed older update, even correctly signed, would roll
+		// the commitment back
+		previous, err := m.verifyCommitmentLocked(current)
+		if err != nil {
+			return nil, err
+		}
+		if update.Version <= previous.Version {
+			return nil, fmt.Errorf("commitment %s version %d does not exceed current version %d", update.ID, update.Version, previous.Version)
+		}
+	}
+
+	entry := &Entry{
+		ID:          update.ID,
+		Partition:   PartitionCommitments,
+		Content:     update.Content,
+		ContentHash: hashContent(update.Content),
+		Metadata: map[string]interface{}{
+			MetadataCommitmentVersion:   strconv.FormatUint(update.Version, 10),
+			MetadataCommitmentKeyID:     update.KeyID,
+			MetadataCommitmentSignature: hex.EncodeToString(update.Signature),
+		},
+		Timestamp: m.now(),
+		Namespace: m.namespace,
+	}
+	if err := m.persist(p, entry); err != nil {
+		return nil, err
+	}
+	m.insertLocked(p, m.key(update.ID), entry)
+	return entry, nil
+}
+
+// verifyUpdateLocked checks an update's signature; m.mu is held
+func (m *Manager) verifyUpdateLocked(update CommitmentUpdate) error {
+	if m.commitmentKeys == nil {
+		return fmt.Errorf("no governance keys for commitments")
+	}
+	if update.Version == 0 {
+		return fmt.Errorf("commitment %s has no version", update.ID)
+	}
+	if err := m.commitmentKeys.Verify(update.KeyID, update.SigningMessage(), update.Signature); err != nil {
+		return fmt.Errorf("commitment %s: %w", update.ID, err)
+	}
+	return nil
+}
+
+// verifyCommitmentLocked rebuilds the signed update an entry was written
+// from and verifies it; m.mu is held
+func (m *Manager) verifyCommitmentLocked(entry *Entry) (CommitmentUpdate, error) {
+	version, _ := entry.Metadata[MetadataCommitmentVersion].(string)
+	keyID, _ := entry.Metadata[MetadataCommitmentKeyID].(string)
+	signature, _ := entry.Metadata[MetadataCommitmentSignature].(string)
+	update := CommitmentUpdate{ID: entry.ID, Content: entry.Content, KeyID: keyID}
+
+	var err error
+	if update.Version, err = strconv.ParseUint(version, 10, 64); err != nil {
+		return update, fmt.Errorf("commitment %s has no valid version", entry.ID)
+	}
+	if update.Signature, err = hex.DecodeString(signature); err != nil {
+		return update, fmt.Errorf("commitment %s has no valid signature", entry.ID)
+	}
+	if err := m.verifyUpdateLocked(update); err != nil {
+		return update, err
+	}
+	return update, nil
+}
+
+// ReadCommitment returns a commitment and the version it was signed at
+func (m *Manager) ReadCommitment(id string) (*Entry, uint64, error) {
+	entry, err := m.Read(PartitionCommitments, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	recorded, _ := entry.Metadata[MetadataCommitmentVersion].(string)
+	version, _ := strconv.ParseUint(recorded, 10, 64)
+	return entry, version, nil
+}
@@ -0,0 +1,108 @@
+// WHY: These tests prove commitments change only through governance-signed
+// updates with rising versions, and an entry altered after it was written
+// is refused on read.
+package memory
+
+import (
+	"testing"
+
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+// withGovernance trusts a governance key for commitments and returns it
+func withGovernance(t *testing.T, manager *Manager) *signing.LocalSigner {
+	t.Helper()
+	signer, err := signing.GenerateLocalSigner("governance")
+	if err != nil {
+		t.Fatalf("signer: %v", err)
+	}
+	keys := signing.NewKeyRing()
+	keys.AddSigner(signer)
+	manager.SetCommitmentKeys(keys)
+	return signer
+}
+
+// signedUpdate returns a commitment update signed by signer
+func signedUpdate(t *testing.T, signer signing.Signer, id string, content string, version uint64) CommitmentUpdate {
+	t.Helper()
+	update := CommitmentUpdate{ID: id, Content: content, Version: version}
+	if err := update.Sign(signer); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return update
+}
+
+// TestCommitmentsNeedSignedRisingVersions proves unsigned writes, foreign
+// signatures, and rollbacks are refused
+func TestCommitmentsNeedSignedRisingVersions(t *testing.T) {
+	manager := NewManager()
+	if err := manager.WriteCommitment(CommitmentUpdate{ID: "retention", Content: "30 days", Version: 1}); err == nil {
+		t.Fatal("without governance keys no commitment may be written")
+	}
+	governance := withGovernance(t, manager)
+	stranger, _ := signing.GenerateLocalSigner("governance")
+
+	if err := manager.Write(PartitionCommitments, "retention", "forever", nil); err == nil {
+		t.Fatal("plain writes to commitments must be refused")
+	}
+	if err := manager.WriteCommitment(signedUpdate(t, stranger, "retention", "forever", 1)); err == nil {
+		t.Fatal("an update signed with another key must be refused")
+	}
+
+	if err := manager.WriteCommitment(signedUpdate(t, governance, "retention", "30 days", 1)); err != nil {
+		t.Fatalf("write v1: %v", err)
+	}
+	if err := manager.WriteCommitment(signedUpdate(t, governance, "retention", "7 days", 2)); err != nil {
+		t.Fatalf("write v2: %v", err)
+	}
+	if err := manager.WriteCommitment(signedUpdate(t, governance, "retention", "30 days", 1)); err == nil {
+		t.Fatal("a replayed older version must be refused")
+	}
+	if err := manager.WriteCommitment(signedUpdate(t, governance, "retention", "90 days", 2)); err == nil {
+		t.Fatal("a repeated version must be refused")
+	}
+
+	entry, version, err := manager.ReadCommitment("retention")
+	if err != nil || entry.Content != "7 days" || version != 2 {
+		t.Fatalf("expected v2, got %v %d (%v)", entry, version, err)
+	}
+	if err := manager.Delete(PartitionCommitments, "retention"); err == nil {
+		t.Fatal("commitments must not be deleted")
+	}
+}
+
+// TestAlteredCommitmentIsRefusedOnRead proves an entry edited in place no
+// longer reads or lists
+func TestAlteredCommitmentIsRefusedOnRead(t *testing.T) {
+	manager := NewManager()
+	governance := withGovernance(t, manager)
+	manager.WriteCommitment(signedUpdate(t, governance, "retention", "30 days", 1))
+
+	manager.partitions[PartitionCommitments].Entries[manager.key("retention")].Content = "forever"
+	if _, err := manager.Read(PartitionCommitments, "retention"); err == nil {
+		t.Fatal("an altered commitment must be refused on read")
+	}
+	if _, err := manager.List(PartitionCommitments, EntryFilter{}); err == nil {
+		t.Fatal("an altered commitment must be refused when listed")
+	}
+}
+
+// TestCommitmentsSurviveRestartVerified proves stored commitments verify
+// after a reload and keep their version floor
+func TestCommitmentsSurviveRestartVerified(t *testing.T) {
+	dir := t.TempDir()
+	manager := reopen(t, dir)
+	governance := withGovernance(t, manager)
+	manager.WriteCommitment(signedUpdate(t, governance, "retention", "30 days", 3))
+
+	restarted := reopen(t, dir)
+	keys := signing.NewKeyRing()
+	keys.AddSigner(governance)
+	restarted.SetCommitmentKeys(keys)
+	if _, version, err := restarted.ReadCommitment("retention"); err != nil || version != 3 {
+		t.Fatalf("a stored commitment must verify after restart: %d (%v)", version, err)
+	}
+	if err := restarted.WriteCommitment(signedUpdate(t, governance, "retention", "forever", 2)); err == nil {
+		t.Fatal("the version floor must survive a restart")
+	}
+}
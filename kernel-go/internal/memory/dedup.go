@@ -0,0 +1,129 @@
+// WHY: Retrieved documents and attachments arrive again and again, and
+// each copy used to hold its own content. A deduplicated partition keeps
+// one copy per content hash, counted by reference, while every entry keeps
+// its own ID, metadata, and provenance; the copy is dropped with its last
+// reference. Stores still receive whole entries, so a backend can share
+// content by ContentHash in its own way.
+package memory
+
+import "fmt"
+
+// blob is one shared copy of content and the entries referencing it
+type blob struct {
+	content string
+	refs    int
+}
+
+// DedupStats summarizes shared content across deduplicated partitions
+type DedupStats struct {
+	// Blobs is the number of distinct contents held, and References the
+	// entries pointing at them
+	Blobs      int
+	References int
+
+	// Bytes is the content held; SavedBytes what the references would
+	// have held without sharing
+	Bytes      int64
+	SavedBytes int64
+}
+
+// SetDeduplication turns content sharing on or off for a partition.
+// Entries already in the partition are shared or unshared immediately.
+func (m *Manager) SetDeduplication(partition string, enabled bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, exists := m.partitions[partition]
+	if !exists {
+		return fmt.Errorf("partition %s does not exist", partition)
+	}
+	if p.Policy.Deduplicate == enabled {
+		return nil
+	}
+	for _, entry := range p.Entries {
+		if enabled {
+			m.internLocked(entry)
+		} else {
+			m.releaseLocked(entry)
+		}
+	}
+	p.Policy.Deduplicate = enabled
+	return nil
+}
+
+// ContentReferences returns how many entries share the content with hash
+func (m *Manager) ContentReferences(contentHash string) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if shared, ok := m.blobs[contentHash]; ok {
+		return shared.refs
+	}
+	return 0
+}
+
+// Deduplication returns statistics over all shared content
+func (m *Manager) Deduplication() DedupStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stats := DedupStats{Blobs: len(m.blobs)}
+	for _, shared := range m.blobs {
+		size := int64(len(shared.content))
+		stats.References += shared.refs
+		stats.Bytes += size
+		stats.SavedBytes += size * int64(shared.refs-1)
+	}
+	return stats
+}
+
+// insertLocked puts entry under key in p, releasing any entry it
+// replaces, sharing its content if p deduplicates, and clearing the
+// tombstone of an entry deleted under key; m.mu is held
+func (m *Manager) insertLocked(p *Partition, key string, entry *Entry) {
+	m.removeLocked(p, key)
+	if p.Policy.Deduplicate {
+		m.internLocked(entry)
+	}
+	p.Entries[key] = entry
+	delete(m.tombstones, tombstoneKey{partition: p.Name, key: key})
+}
+
+// removeLocked drops the entry under key from p, releasing its content;
+// m.mu is held
+func (m *Manager) removeLocked(p *Partition, key string) {
+	previous, exists := p.Entries[key]
+	if !exists {
+		return
+	}
+	if p.Policy.Deduplicate {
+		m.releaseLocked(previous)
+	}
+	delete(p.Entries, key)
+}
+
+// internLocked points entry at the shared copy of its content; m.mu is held
+func (m *Manager) internLocked(entry *Entry) {
+	if m.blobs == nil {
+		m.blobs = make(map[string]*blob)
+	}
+	shared, ok := m.blobs[entry.ContentHash]
+	if !ok {
+		shared = &blob{content: entry.Content}
+		m.blobs[entry.ContentHash] = shared
+	}
+	entry.Content = shared.content
+	shared.refs++
+}
+
+// releaseLocked drops entry's reference to its shared content; m.mu is held
+func (m *Manager) releaseLocked(entry *Entry) {
+	shared, ok := m.blobs[entry.ContentHash]
+	if !ok {
+		return
+	}
+	shared.refs--
+	if shared.refs <= 0 {
+		delete(m.blobs, entry.ContentHash)
+	}
+}
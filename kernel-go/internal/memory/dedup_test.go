@@ -0,0 +1,63 @@
+// WHY: These tests prove identical content is held once per hash while
+// each reference keeps its own metadata, and the shared copy follows its
+// references through replacement, deletion, and toggling.
+package memory
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestDeduplicationSharesContentPerReference proves repeated artifacts are
+// counted once and each entry keeps its own provenance
+func TestDeduplicationSharesContentPerReference(t *testing.T) {
+	manager := NewManager()
+	if err := manager.SetDeduplication(PartitionDurable, true); err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+	document := strings.Repeat("warranty terms ", 100)
+	manager.Write(PartitionDurable, "mail/1", document, map[string]interface{}{"source": "mail"})
+	manager.Write(PartitionDurable, "drive/1", document, map[string]interface{}{"source": "drive"})
+	manager.Namespace("work").Write(PartitionDurable, "mail/1", document, nil)
+
+	first, _ := manager.Read(PartitionDurable, "mail/1")
+	second, _ := manager.Read(PartitionDurable, "drive/1")
+	if first.Metadata["source"] != "mail" || second.Metadata["source"] != "drive" || second.Content != document {
+		t.Fatal("each reference must keep its own metadata and read the full content")
+	}
+	stats := manager.Deduplication()
+	if stats.Blobs != 1 || stats.References != 3 || stats.SavedBytes != 2*int64(len(document)) {
+		t.Fatalf("expected one blob with three references, got %+v", stats)
+	}
+
+	manager.Delete(PartitionDurable, "mail/1")
+	manager.Write(PartitionDurable, "drive/1", "replaced", nil)
+	if refs := manager.ContentReferences(first.ContentHash); refs != 1 {
+		t.Fatalf("deleted and replaced entries must release their reference, got %d", refs)
+	}
+	manager.Namespace("work").Delete(PartitionDurable, "mail/1")
+	if refs := manager.ContentReferences(first.ContentHash); refs != 0 || manager.Deduplication().Blobs != 1 {
+		t.Fatalf("content must be dropped with its last reference, got %d", refs)
+	}
+}
+
+// TestDeduplicationToggleCountsExistingEntries proves enabling shares what
+// is already stored and disabling releases it
+func TestDeduplicationToggleCountsExistingEntries(t *testing.T) {
+	manager := NewManager()
+	manager.Write(PartitionQuarantine, "a", "same page", nil)
+	manager.Write(PartitionQuarantine, "b", "same page", nil)
+
+	manager.SetDeduplication(PartitionQuarantine, true)
+	if stats := manager.Deduplication(); stats.Blobs != 1 || stats.References != 2 {
+		t.Fatalf("enabling must share existing entries, got %+v", stats)
+	}
+	manager.Write(PartitionQuarantine, "c", "same page", nil)
+	manager.SetDeduplication(PartitionQuarantine, false)
+	if stats := manager.Deduplication(); stats.Blobs != 0 || stats.References != 0 {
+		t.Fatalf("disabling must release every reference, got %+v", stats)
+	}
+	if err := manager.SetDeduplication("missing", true); err == nil {
+		t.Fatal("unknown partitions must be refused")
+	}
+}
@@ -0,0 +1,177 @@
+// WHY: Memory that is never collected grows without bound and keeps
+// content past the point anyone meant it to live. Garbage collection
+// expires ephemeral entries, compacts the tombstones durable deletions
+// leave, and prunes quarantine past an age, and reports what it removed as
+// one summary per partition: counts, bytes, and a digest over the removed
+// entries' identities and content hashes, so the audit trail can account
+// for every removal without a receipt per entry.
+package memory
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// Tombstone records a deleted persistent entry until it is compacted
+type Tombstone struct {
+	Partition   string
+	ID          string
+	ContentHash string
+	DeletedAt   int64
+	Namespace   string `json:",omitempty"`
+}
+
+// tombstoneKey is a tombstone's key in the space
+type tombstoneKey struct {
+	partition string
+	key       string
+}
+
+// GCPolicy says what a collection removes; a zero age removes nothing of
+// that kind
+type GCPolicy struct {
+	// EphemeralMaxAge expires ephemeral entries older than it
+	EphemeralMaxAge time.Duration
+
+	// TombstoneMaxAge compacts tombstones older than it
+	TombstoneMaxAge time.Duration
+
+	// QuarantineMaxAge prunes quarantined entries older than it, whether
+	// or not they were closed
+	QuarantineMaxAge time.Duration
+}
+
+// GCReport summarizes what one collection removed from one partition
+type GCReport struct {
+	Partition string
+	Removed   int
+	Bytes     int64
+
+	// Digest is the hex SHA-256 over the removed entries' namespaces, IDs,
+	// and content hashes, in key order
+	Digest string
+}
+
+// removal is one entry or tombstone a collection removed
+type removal struct {
+	key         string
+	namespace   string
+	id          string
+	contentHash string
+	bytes       int64
+}
+
+// Tombstones returns the view's uncompacted tombstones, oldest first
+func (m *Manager) Tombstones() []Tombstone {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tombstones := []Tombstone{}
+	for _, tombstone := range m.tombstones {
+		if tombstone.Namespace == m.namespace {
+			tombstones = append(tombstones, *tombstone)
+		}
+	}
+	sort.Slice(tombstones, func(i, j int) bool {
+		if tombstones[i].DeletedAt != tombstones[j].DeletedAt {
+			return tombstones[i].DeletedAt < tombstones[j].DeletedAt
+		}
+		return tombstones[i].ID < tombstones[j].ID
+	})
+	return tombstones
+}
+
+// CollectGarbage removes what policy says has outlived its use, in every
+// namespace, and returns a report for each partition it removed from.
+// WHY: Collection is deployment maintenance, not a caller's request, so it
+// bypasses append-only and reports summaries rather than notifying per
+// entry; the kernel ledgers the summaries.
+func (m *Manager) CollectGarbage(policy GCPolicy) []GCReport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.clock.Now()
+	reports := []GCReport{}
+	for _, collect := range []struct {
+		partition string
+		maxAge    time.Duration
+	}{
+		{PartitionEphemeral, policy.EphemeralMaxAge},
+		{PartitionQuarantine, policy.QuarantineMaxAge},
+	} {
+		if collect.maxAge <= 0 {
+			continue
+		}
+		cutoff := now.Add(-collect.maxAge).Unix()
+		p := m.partitions[collect.partition]
+		removed := []removal{}
+		for key, entry := range p.Entries {
+			if entry.Timestamp < cutoff {
+				removed = append(removed, removal{key: key, namespace: entry.Namespace, id: entry.ID, contentHash: entry.ContentHash, bytes: int64(len(entry.Content))})
+			}
+		}
+		for _, gone := range removed {
+			m.removeLocked(p, gone.key)
+		}
+		if report, ok := summarize(collect.partition, removed); ok {
+			reports = append(reports, report)
+		}
+	}
+
+	if policy.TombstoneMaxAge > 0 {
+		cutoff := now.Add(-policy.TombstoneMaxAge).Unix()
+		byPartition := map[string][]removal{}
+		for key, tombstone := range m.tombstones {
+			if tombstone.DeletedAt < cutoff {
+				byPartition[key.partition] = append(byPartition[key.partition], removal{key: key.key, namespace: tombstone.Namespace, id: tombstone.ID, contentHash: tombstone.ContentHash})
+				delete(m.tombstones, key)
+			}
+		}
+		partitions := make([]string, 0, len(byPartition))
+		for partition := range byPartition {
+			partitions = append(partitions, partition)
+		}
+		sort.Strings(partitions)
+		for _, partition := range partitions {
+			report, _ := summarize(partition, byPartition[partition])
+			reports = append(reports, report)
+		}
+	}
+	return reports
+}
+
+// tombstoneLocked records the deletion of entry from p; m.mu is held
+func (m *Manager) tombstoneLocked(p *Partition, key string, entry *Entry) {
+	if m.tombstones == nil {
+		m.tombstones = make(map[tombstoneKey]*Tombstone)
+	}
+	m.tombstones[tombstoneKey{partition: p.Name, key: key}] = &Tombstone{
+		Partition:   p.Name,
+		ID:          entry.ID,
+		ContentHash: entry.ContentHash,
+		DeletedAt:   m.now(),
+		Namespace:   entry.Namespace,
+	}
+}
+
+// summarize builds the report for removals from partition; it reports
+// false when nothing was removed
+func summarize(partition string, removed []removal) (GCReport, bool) {
+	if len(removed) == 0 {
+		return GCReport{}, false
+	}
+	sort.Slice(removed, func(i, j int) bool { return removed[i].key < removed[j].key })
+	identities := make([][]string, len(removed))
+	report := GCReport{Partition: partition, Removed: len(removed)}
+	for i, gone := range removed {
+		identities[i] = []string{gone.namespace, gone.id, gone.contentHash}
+		report.Bytes += gone.bytes
+	}
+	encoded, _ := json.Marshal(identities)
+	sum := sha256.Sum256(encoded)
+	report.Digest = hex.EncodeToString(sum[:])
+	return report, true
+}
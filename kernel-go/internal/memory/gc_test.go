@@ -0,0 +1,96 @@
+// WHY: These tests prove collection removes only what its policy names,
+// across namespaces, reports each partition's removals as one summary,
+// and that a durable deletion's tombstone keeps a backup from restoring it
+// until the tombstone is compacted.
+package memory
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/clock"
+)
+
+// TestCollectGarbageRemovesAgedEntries proves aged ephemeral and
+// quarantined entries are removed, fresh ones and other partitions stay,
+// and each partition is summarized once
+func TestCollectGarbageRemovesAgedEntries(t *testing.T) {
+	manager := NewManager()
+	now := clock.NewFake(time.Now())
+	manager.SetClock(now)
+	manager.SetDeduplication(PartitionQuarantine, true)
+
+	manager.Write(PartitionEphemeral, "draft", "old draft", nil)
+	manager.Namespace("work").Write(PartitionEphemeral, "draft", "old work draft", nil)
+	manager.Write(PartitionQuarantine, "page", "fetched page", nil)
+	manager.Write(PartitionDurable, "note", "keep me", nil)
+	now.Advance(2 * time.Hour)
+	manager.Write(PartitionEphemeral, "fresh", "new draft", nil)
+
+	if reports := manager.CollectGarbage(GCPolicy{}); len(reports) != 0 {
+		t.Fatalf("an empty policy must remove nothing, got %+v", reports)
+	}
+	reports := manager.CollectGarbage(GCPolicy{EphemeralMaxAge: time.Hour, QuarantineMaxAge: time.Hour})
+	if len(reports) != 2 || reports[0].Partition != PartitionEphemeral || reports[1].Partition != PartitionQuarantine {
+		t.Fatalf("expected an ephemeral and a quarantine summary, got %+v", reports)
+	}
+	if reports[0].Removed != 2 || reports[0].Bytes != int64(len("old draft")+len("old work draft")) || len(reports[0].Digest) != 64 {
+		t.Fatalf("both namespaces' aged drafts must be summarized, got %+v", reports[0])
+	}
+	if _, err := manager.Read(PartitionEphemeral, "fresh"); err != nil {
+		t.Fatal("entries younger than the policy must stay")
+	}
+	if _, err := manager.Read(PartitionDurable, "note"); err != nil {
+		t.Fatal("durable entries are never aged out")
+	}
+	if _, err := manager.QuarantinedHash("page"); err == nil || manager.Deduplication().Blobs != 0 {
+		t.Fatal("pruned quarantine entries must be gone, with their shared content")
+	}
+}
+
+// TestTombstonesBlockRestoreUntilCompacted proves a durable deletion
+// leaves a tombstone, a snapshot cannot restore the entry while it stands,
+// and compaction removes it
+func TestTombstonesBlockRestoreUntilCompacted(t *testing.T) {
+	key := snapshotKey(t)
+	manager := NewManager()
+	now := clock.NewFake(time.Now())
+	manager.SetClock(now)
+	manager.Write(PartitionDurable, "note", "remember this", nil)
+	var archive bytes.Buffer
+	if _, err := manager.ExportSnapshot(&archive, key); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	manager.Delete(PartitionDurable, "note")
+	manager.Delete(PartitionEphemeral, "missing")
+	tombstones := manager.Tombstones()
+	if len(tombstones) != 1 || tombstones[0].ID != "note" || tombstones[0].DeletedAt != now.Now().Unix() {
+		t.Fatalf("a durable deletion must leave one tombstone, got %+v", tombstones)
+	}
+	if len(manager.Namespace("work").Tombstones()) != 0 {
+		t.Fatal("tombstones must stay in their namespace")
+	}
+	if _, err := manager.ImportSnapshot(bytes.NewReader(archive.Bytes()), key); err == nil {
+		t.Fatal("a snapshot must not restore a tombstoned entry")
+	}
+
+	if reports := manager.CollectGarbage(GCPolicy{TombstoneMaxAge: time.Hour}); len(reports) != 0 {
+		t.Fatalf("a fresh tombstone must not be compacted, got %+v", reports)
+	}
+	now.Advance(2 * time.Hour)
+	reports := manager.CollectGarbage(GCPolicy{TombstoneMaxAge: time.Hour})
+	if len(reports) != 1 || reports[0].Partition != PartitionDurable || reports[0].Removed != 1 || len(manager.Tombstones()) != 0 {
+		t.Fatalf("an aged tombstone must be compacted and summarized, got %+v", reports)
+	}
+	if _, err := manager.ImportSnapshot(bytes.NewReader(archive.Bytes()), key); err != nil {
+		t.Fatalf("after compaction the snapshot must import: %v", err)
+	}
+
+	manager.Delete(PartitionDurable, "note")
+	manager.Write(PartitionDurable, "note", "written again", nil)
+	if len(manager.Tombstones()) != 0 {
+		t.Fatal("writing a deleted ID again must clear its tombstone")
+	}
+}
@@ -0,0 +1,116 @@
+// WHY: Durable memory is user-custodied, and an overwrite used to replace
+// an entry without a trace of what it said before. Each durable write now
+// archives the version it replaces in the append-only provenance
+// partition and links to it by hash, so an entry's history can be walked
+// back and any altered or missing version is detected.
+package memory
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// MetadataVersionOf names, on an archived version, the entry it is a
+// version of
+const MetadataVersionOf = "version_of"
+
+// versionHash identifies one version of the entry with id. WHY: It covers
+// content by hash, the version number, time, and the link to the version
+// before, but not metadata, whose JSON types drift through a store.
+func versionHash(id string, entry *Entry) string {
+	message, _ := json.Marshal([]string{
+		entry.Namespace, id, entry.ContentHash,
+		strconv.FormatUint(entry.Version, 10), strconv.FormatInt(entry.Timestamp, 10), entry.Previous,
+	})
+	sum := sha256.Sum256(message)
+	return hex.EncodeToString(sum[:])
+}
+
+// versionID is the provenance ID of the version of id with hash
+func versionID(id string, hash string) string {
+	return id + "@" + hash
+}
+
+// versionLocked numbers entry and, if it replaces a durable entry, puts
+// that entry's archive in b and links entry to it. It returns the
+// archived entry, or nil if nothing new was archived; m.mu is held.
+func (m *Manager) versionLocked(b *batch, p *Partition, entry *Entry) (*Entry, error) {
+	if p.Name != PartitionDurable {
+		return nil, nil
+	}
+	entry.Version = 1
+	previous, exists := p.Entries[m.key(entry.ID)]
+	if !exists {
+		return nil, nil
+	}
+	hash := versionHash(previous.ID, previous)
+	entry.Version = previous.Version + 1
+	entry.Previous = hash
+
+	provenance := m.partitions[PartitionProvenance]
+	archivedID := versionID(previous.ID, hash)
+	if existing, ok := provenance.Entries[m.key(archivedID)]; ok {
+		// WHY: A write interrupted after archiving leaves the version
+		// behind; anything else under its ID is not that version
+		if existing.Metadata[MetadataVersionOf] != previous.ID || versionHash(previous.ID, existing) != hash {
+			return nil, fmt.Errorf("provenance entry %s is not the version it names", archivedID)
+		}
+		return nil, nil
+	}
+	archived := copyEntry(previous)
+	archived.Partition = PartitionProvenance
+	archived.ID = archivedID
+	archived.Metadata[MetadataVersionOf] = previous.ID
+	b.put(provenance, m.key(archivedID), archived)
+	return archived, nil
+}
+
+// History returns every version of a durable entry, oldest first; the
+// last is the current entry and the others are as archived in provenance.
+// WHY: Each link is re-hashed, so a version that was altered or removed
+// fails the walk rather than being skipped.
+func (m *Manager) History(id string) ([]*Entry, error) {
+	versions, err := m.history(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, version := range versions {
+		m.notify(Operation{Action: OpRead, Partition: version.Partition, ID: version.ID, ContentHash: version.ContentHash})
+	}
+	return versions, nil
+}
+
+// history is History without the notifications
+func (m *Manager) history(id string) ([]*Entry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	current, exists := m.partitions[PartitionDurable].Entries[m.key(id)]
+	if !exists {
+		return nil, fmt.Errorf("entry %s not found in partition %s", id, PartitionDurable)
+	}
+	provenance := m.partitions[PartitionProvenance]
+	versions := []*Entry{copyEntry(current)}
+	for link := current.Previous; link != ""; {
+		// WHY: A cycle would need a hash collision; the bound is a guard
+		if len(versions) > len(provenance.Entries) {
+			return nil, fmt.Errorf("history of %s does not end", id)
+		}
+		archived, ok := provenance.Entries[m.key(versionID(id, link))]
+		if !ok {
+			return nil, fmt.Errorf("history of %s is missing version %s", id, link)
+		}
+		if versionHash(id, archived) != link {
+			return nil, fmt.Errorf("history of %s: version %s was altered", id, link)
+		}
+		versions = append(versions, copyEntry(archived))
+		link = archived.Previous
+	}
+	for i, j := 0, len(versions)-1; i < j; i, j = i+1, j-1 {
+		versions[i], versions[j] = versions[j], versions[i]
+	}
+	return versions, nil
+}
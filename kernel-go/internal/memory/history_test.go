@@ -0,0 +1,82 @@
+// WHY: These tests prove a durable overwrite keeps the version it replaces
+// in provenance, the chain walks back to the first write across a
+// restart, and an altered or missing version breaks the walk.
+package memory
+
+import (
+	"testing"
+)
+
+// TestOverwritesKeepPriorVersions proves each overwrite archives the
+// replaced version and History returns them all, oldest first
+func TestOverwritesKeepPriorVersions(t *testing.T) {
+	manager := NewManager()
+	var archived []string
+	manager.OnOperation(func(op Operation) {
+		if op.Action == OpWrite && op.Partition == PartitionProvenance {
+			archived = append(archived, op.ID)
+		}
+	})
+	for _, content := range []string{"likes tea", "likes coffee", "likes neither"} {
+		if err := manager.Write(PartitionDurable, "drinks", content, map[string]interface{}{"kind": "preference"}); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	versions, err := manager.History("drinks")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if len(versions) != 3 || versions[0].Content != "likes tea" || versions[2].Content != "likes neither" {
+		t.Fatalf("expected three versions oldest first, got %+v", versions)
+	}
+	for i, version := range versions {
+		if version.Version != uint64(i+1) {
+			t.Fatalf("version %d is numbered %d", i+1, version.Version)
+		}
+	}
+	if versions[0].Partition != PartitionProvenance || versions[0].Metadata[MetadataVersionOf] != "drinks" || versions[2].Partition != PartitionDurable {
+		t.Fatalf("prior versions must be archived in provenance: %+v", versions[0])
+	}
+	if len(archived) != 2 || archived[1] != versions[1].ID {
+		t.Fatalf("each archived version must be reported, got %v", archived)
+	}
+	if other, _ := manager.Namespace("work").History("drinks"); other != nil {
+		t.Fatal("history must stay in its namespace")
+	}
+}
+
+// TestHistoryDetectsAlteredAndMissingVersions proves a version changed or
+// removed from provenance fails the walk
+func TestHistoryDetectsAlteredAndMissingVersions(t *testing.T) {
+	manager := NewManager()
+	manager.Write(PartitionDurable, "note", "first", nil)
+	manager.Write(PartitionDurable, "note", "second", nil)
+	manager.Write(PartitionDurable, "note", "third", nil)
+	versions, _ := manager.History("note")
+
+	provenance := manager.partitions[PartitionProvenance].Entries
+	first := provenance[manager.key(versions[0].ID)]
+	first.ContentHash = hashContent("rewritten")
+	if _, err := manager.History("note"); err == nil {
+		t.Fatal("an altered version must break the history")
+	}
+	delete(provenance, manager.key(versions[1].ID))
+	if _, err := manager.History("note"); err == nil {
+		t.Fatal("a missing version must break the history")
+	}
+}
+
+// TestHistorySurvivesRestart proves archived versions persist with the
+// provenance partition
+func TestHistorySurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	manager := reopen(t, dir)
+	manager.Write(PartitionDurable, "note", "before", nil)
+	manager.Write(PartitionDurable, "note", "after", nil)
+
+	versions, err := reopen(t, dir).History("note")
+	if err != nil || len(versions) != 2 || versions[0].Content != "before" {
+		t.Fatalf("history must survive a restart: %+v (%v)", versions, err)
+	}
+}
@@ -7,6 +7,9 @@ import (
 	"encoding/hex"
 	"fmt"
 	"sync"
+
+	"github.com/user/oi/kernel-go/internal/clock"
+	"github.com/user/oi/kernel-go/internal/signing"
 )
 
 // Partition types define trust boundaries
@@ -28,12 +31,93 @@ type Entry struct {
 	Metadata    map[string]interface{}
 	Timestamp   int64
 	Verified    bool // for quarantine promotion
+
+	// Principal is who the entry is written for; quotas are counted
+	// against it
+	Principal string `json:",omitempty"`
+
+	// Namespace is the namespace the entry belongs to
+	Namespace string `json:",omitempty"`
+
+	// Version counts a durable entry's writes under its ID, and Previous
+	// is the version hash of the entry it replaced (see history.go)
+	Version  uint64 `json:",omitempty"`
+	Previous string `json:",omitempty"`
 }
 
-// Manager manages all memory partitions
+// Manager manages all memory partitions. Each Manager is a view of one
+// namespace; Namespace returns views of others over the same partitions.
 type Manager struct {
+	*space
+	namespace string
+}
+
+// space is the partitions and configuration every namespace view shares
+type space struct {
 	mu         sync.RWMutex
 	partitions map[string]*Partition
+
+	// store, if attached, persists the persistent partitions
+	store Store
+
+	// onOperation, if set, is told of every write, protected read,
+	// quarantine outcome, and deletion
+	onOperation func(Operation)
+
+	// commitmentKeys holds the governance keys commitment updates are
+	// signed with
+	commitmentKeys *signing.KeyRing
+
+	// quotas bound each principal's usage per partition
+	quotas quotas
+
+	// blobs holds the shared content of deduplicated partitions
+	blobs map[string]*blob
+
+	// verificationPolicy, if set, reports who may close quarantined
+	// entries; without it nothing leaves quarantine
+	verificationPolicy func() VerificationPolicy
+
+	// vectors, if an embedder is set, indexes durable entries for Search
+	vectors *vectorIndex
+
+	// clock stamps entries; it never goes backwards
+	clock clock.Clock
+
+	// tombstones record deleted persistent entries until collected
+	tombstones map[tombstoneKey]*Tombstone
+}
+
+// Operation actions reported to OnOperation
+const (
+	OpWrite   = "write"
+	OpRead    = "read"
+	OpPromote = "promote"
+	OpDelete  = "delete"
+	OpReject  = "reject"
+	OpExpire  = "expire"
+	OpRefuse  = "refuse"
+)
+
+// Operation describes one completed memory operation
+type Operation struct {
+	Action      string
+	Partition   string
+	ID          string
+	ContentHash string
+
+	// Verifier and Method name who closed a quarantined entry and how,
+	// for promotions and rejections
+	Verifier string
+	Method   string
+
+	// Principal is who a write or refused write was for, and Reason why
+	// a write was refused
+	Principal string
+	Reason    string
+
+	// Namespace is the namespace of the view the operation went through
+	Namespace string
 }
 
 // Partition represents a single memory partition
@@ -49,13 +133,24 @@ type PartitionPolicy struct {
 	AllowRead       bool
 	RequireCapability bool
 	AppendOnly      bool
+
+	// Persistent partitions are written to the attached Store
+	Persistent bool
+
+	// Signed partitions take only signed, versioned updates, and verify
+	// each entry's signature when it is read
+	Signed bool
+
+	// Deduplicate partitions share one copy of identical content
+	Deduplicate bool
 }
 
 // NewManager creates a new memory manager with default partitions
 func NewManager() *Manager {
-	m := &Manager{
+	m := &Manager{space: &space{
 		partitions: make(map[string]*Partition),
-	}
+		clock:      clock.Monotonic(clock.Real{}),
+	}}
 
 	// Initialize standard partitions
 	m.partitions[PartitionEphemeral] = &Partition{
@@ -77,6 +172,7 @@ func NewManager() *Manager {
 			AllowRead:         true,
 			RequireCapability: true,
 			AppendOnly:        false,
+			Persistent:        true,
 		},
 	}
 
@@ -84,10 +180,12 @@ func NewManager() *Manager {
 		Name:    PartitionCommitments,
 		Entries: make(map[string]*Entry),
 		Policy: PartitionPolicy{
-			AllowWrite:        true, // requires signed update
+			AllowWrite:        true, // only through WriteCommitment
 			AllowRead:         true,
 			RequireCapability: true,
 			AppendOnly:        false,
+			Persistent:        true,
+			Signed:            true,
 		},
 	}
 
@@ -99,6 +197,7 @@ func NewManager() *Manager {
 			AllowRead:         true,
 			RequireCapability: false,
 			AppendOnly:        true,
+			Persistent:        true,
 		},
 	}
 
@@ -121,37 +220,102 @@ func NewManager() *Manager {
 			AllowRead:         true,
 			RequireCapability: true,
 			AppendOnly:        true,
+			Persistent:        true,
 		},
 	}
 
 	return m
 }
 
+// OnOperation registers fn to receive every write, every read of a
+// partition that requires a capability, every promotion, rejection, and
+// expiry of quarantined content, and every deletion. fn is called after
+// the manager's lock is released.
+// WHY: The manager has no ledger; the kernel records the operations.
+func (m *Manager) OnOperation(fn func(Operation)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onOperation = fn
+}
+
+// notify reports a completed operation in this namespace; m.mu must not
+// be held
+func (m *Manager) notify(operation Operation) {
+	operation.Namespace = m.namespace
+	m.mu.RLock()
+	fn := m.onOperation
+	m.mu.RUnlock()
+	if fn != nil {
+		fn(operation)
+	}
+}
+
 // Write adds an entry to a partition.
 // WHY: Partition discipline - every write declares its partition.
 func (m *Manager) Write(partition string, id string, content string, metadata map[string]interface{}) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	return m.WriteAs("", partition, id, content, metadata)
+}
+
+// WriteAs adds an entry to a partition on behalf of principal, counting
+// it against the principal's quota
+func (m *Manager) WriteAs(principal string, partition string, id string, content string, metadata map[string]interface{}) error {
+	entry, archived, err := m.write(principal, partition, id, content, metadata)
+	if err != nil {
+		m.notifyRefused(id, err)
+		return err
+	}
+	m.notifyArchived(archived)
+	m.notify(Operation{Action: OpWrite, Partition: partition, ID: id, ContentHash: entry.ContentHash, Principal: principal})
+	return nil
+}
+
+// notifyArchived reports a version archived to provenance, if any
+func (m *Manager) notifyArchived(archived *Entry) {
+	if archived != nil {
+		m.notify(Operation{Action: OpWrite, Partition: PartitionProvenance, ID: archived.ID, ContentHash: archived.ContentHash, Principal: archived.Principal})
+	}
+}
+
+// write is WriteAs without the notifications; it also returns the version
+// the write archived, if any
+func (m *Manager) write(principal string, partition string, id string, content string, metadata map[string]interface{}) (*Entry, *Entry, error) {
+	results, _, err := m.commit([]stagedWrite{{
+		principal: principal, partition: partition, id: id, content: content, metadata: metadata,
+	}})
+	if err != nil {
+		return nil, nil, err
+	}
+	return results[0].entry, results[0].archived, nil
+}
+
+// stageLocked checks one write and puts it in b, with the version it
+// archives; m.mu is held
+func (m *Manager) stageLocked(b *batch, write stagedWrite) (*Entry, *Entry, error) {
+	principal, partition, id, content, metadata := write.principal, write.partition, write.id, write.content, write.metadata
 
 	p, exists := m.partitions[partition]
 	if !exists {
-		return fmt.Errorf("partition %s does not exist", partition)
+		return nil, nil, fmt.Errorf("partition %s does not exist", partition)
 	}
 
 	// Check policy
 	if !p.Policy.AllowWrite {
-		return fmt.Errorf("partition %s is read-only", partition)
+		return nil, nil, fmt.Errorf("partition %s is read-only", partition)
+	}
+	if p.Policy.Signed {
+		return nil, nil, fmt.Errorf("partition %s takes only signed updates", partition)
 	}
 
 	// Check if append-only
-	if p.Policy.AppendOnly && p.Entries[id] != nil {
-		return fmt.Errorf("partition %s is append-only, cannot overwrite entry %s", partition, id)
+	if p.Policy.AppendOnly && p.Entries[m.key(id)] != nil {
+		return nil, nil, fmt.Errorf("partition %s is append-only, cannot overwrite entry %s", partition, id)
+	}
+	if err := m.checkQuotaLocked(p, principal, id, len(content)); err != nil {
+		return nil, nil, err
 	}
 
 	// Compute content hash
-	h := sha256.New()
-	h.Write([]byte(content))
-	contentHash := hex.EncodeToString(h.Sum(nil))
+	contentHash := hashContent(content)
 
 	// Initialize metadata if nil
 	if metadata == nil {
@@ -164,73 +328,195 @@ func (m *Manager) Write(partition string, id string, content string, metadata ma
 		Content:     content,
 		ContentHash: contentHash,
 		Metadata:    metadata,
-		Timestamp:   currentTimestamp(),
+		Timestamp:   m.now(),
 		Verified:    false,
+		Principal:   principal,
+		Namespace:   m.namespace,
 	}
 
-	p.Entries[id] = entry
+	archived, err := m.versionLocked(b, p, entry)
+	if err != nil {
+		return nil, nil, err
+	}
+	b.put(p, m.key(id), entry)
+	return entry, archived, nil
+}
+
+// persist writes entry to the attached store if its partition is
+// persistent; m.mu is held. WHY: The store is written first, so an entry
+// the store refused is never served from memory.
+func (m *Manager) persist(p *Partition, entry *Entry) error {
+	if m.store == nil || !p.Policy.Persistent {
+		return nil
+	}
+	if err := m.store.Put(entry); err != nil {
+		return fmt.Errorf("persist entry %s in %s: %w", entry.ID, p.Name, err)
+	}
 	return nil
 }
 
+// hashContent returns the hex SHA-256 of content
+func hashContent(content string) string {
+	h := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(h[:])
+}
+
 // Read retrieves an entry from a partition
 func (m *Manager) Read(partition string, id string) (*Entry, error) {
+	entry, protected, err := m.read(partition, id)
+	if err != nil {
+		return nil, err
+	}
+	if protected {
+		m.notify(Operation{Action: OpRead, Partition: partition, ID: id, ContentHash: entry.ContentHash})
+	}
+	return entry, nil
+}
+
+// read is Read without the notification; protected reports whether the
+// partition requires a capability
+func (m *Manager) read(partition string, id string) (*Entry, bool, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
 	p, exists := m.partitions[partition]
 	if !exists {
-		return nil, fmt.Errorf("partition %s does not exist", partition)
+		return nil, false, fmt.Errorf("partition %s does not exist", partition)
 	}
 
 	// Check policy
 	if !p.Policy.AllowRead {
-		return nil, fmt.Errorf("partition %s is write-only", partition)
+		return nil, false, fmt.Errorf("partition %s is write-only", partition)
 	}
 
-	entry, exists := p.Entries[id]
+	entry, exists := p.Entries[m.key(id)]
 	if !exists {
-		return nil, fmt.Errorf("entry %s not found in partition %s", id, partition)
+		return nil, false, fmt.Errorf("entry %s not found in partition %s", id, partition)
+	}
+	if p.Policy.Signed {
+		if _, err := m.verifyCommitmentLocked(entry); err != nil {
+			return nil, false, err
+		}
 	}
 
-	return entry, nil
+	return entry, p.Policy.RequireCapability, nil
 }
 
 // PromoteFromQuarantine moves content from quarantine to durable after verification.
 // WHY: Quarantined content is never promoted without explicit verification ritual.
-func (m *Manager) PromoteFromQuarantine(id string, verificationRecord string) error {
+func (m *Manager) PromoteFromQuarantine(id string, record VerificationRecord) error {
+	promoted, archived, err := m.promote(id, record)
+	if err != nil {
+		m.notifyRefused(id, err)
+		return err
+	}
+	m.notifyArchived(archived)
+	m.notify(Operation{Action: OpPromote, Partition: PartitionDurable, ID: id, ContentHash: promoted.ContentHash,
+		Verifier: record.Verifier, Method: record.Method})
+	return nil
+}
+
+// promote is PromoteFromQuarantine without the notifications; it also
+// returns the version the promotion archived, if any
+func (m *Manager) promote(id string, record VerificationRecord) (*Entry, *Entry, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	quarantine := m.partitions[PartitionQuarantine]
-	entry, exists := quarantine.Entries[id]
-	if !exists {
-		return fmt.Errorf("entry %s not found in quarantine", id)
+	entry, err := m.checkVerificationLocked(id, VerdictPromote, record)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Require verification record
-	if verificationRecord == "" {
-		return fmt.Errorf("promotion requires verification record")
+	metadata := make(map[string]interface{}, len(entry.Metadata)+len(record.metadata()))
+	for key, value := range entry.Metadata {
+		metadata[key] = value
+	}
+	for key, value := range record.metadata() {
+		metadata[key] = value
 	}
-
-	// Mark as verified
-	entry.Verified = true
-	entry.Metadata["verification_record"] = verificationRecord
 
 	// Copy to durable partition
 	durable := m.partitions[PartitionDurable]
-	durable.Entries[id] = &Entry{
+	if err := m.checkQuotaLocked(durable, entry.Principal, id, len(entry.Content)); err != nil {
+		return nil, nil, err
+	}
+	promoted := &Entry{
 		ID:          entry.ID,
 		Partition:   PartitionDurable,
 		Content:     entry.Content,
 		ContentHash: entry.ContentHash,
-		Metadata:    entry.Metadata,
-		Timestamp:   currentTimestamp(),
+		Metadata:    metadata,
+		Timestamp:   m.now(),
 		Verified:    true,
+		Principal:   entry.Principal,
+		Namespace:   m.namespace,
+	}
+	// WHY: The durable copy and the version it replaces are recorded
+	// together, and the quarantined entry is marked only once they are
+	b := &batch{m: m}
+	archived, err := m.versionLocked(b, durable, promoted)
+	if err != nil {
+		return nil, nil, err
+	}
+	b.put(durable, m.key(id), promoted)
+	if err := b.commit(); err != nil {
+		return nil, nil, err
 	}
 
+	// Mark as verified
+	entry.Verified = true
+	for key, value := range record.metadata() {
+		entry.Metadata[key] = value
+	}
+	entry.Metadata[MetadataQuarantineStatus] = QuarantinePromoted
+
+	return promoted, archived, nil
+}
+
+// Delete removes an entry from a partition.
+// WHY: Append-only partitions are history and quarantine is never
+// rewritten, so only mutable partitions can forget an entry; a persistent
+// entry is removed from the store first so it cannot come back on restart.
+func (m *Manager) Delete(partition string, id string) error {
+	entry, err := m.remove(partition, id)
+	if err != nil {
+		return err
+	}
+	m.notify(Operation{Action: OpDelete, Partition: partition, ID: id, ContentHash: entry.ContentHash})
 	return nil
 }
 
+// remove is Delete without the notification
+func (m *Manager) remove(partition string, id string) (*Entry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, exists := m.partitions[partition]
+	if !exists {
+		return nil, fmt.Errorf("partition %s does not exist", partition)
+	}
+	if p.Policy.AppendOnly {
+		return nil, fmt.Errorf("partition %s is append-only, cannot delete entry %s", partition, id)
+	}
+	if p.Policy.Signed {
+		return nil, fmt.Errorf("partition %s takes only signed updates, cannot delete entry %s", partition, id)
+	}
+	entry, exists := p.Entries[m.key(id)]
+	if !exists {
+		return nil, fmt.Errorf("entry %s not found in partition %s", id, partition)
+	}
+	if m.store != nil && p.Policy.Persistent {
+		if err := m.store.Delete(entry); err != nil {
+			return nil, fmt.Errorf("delete entry %s in %s: %w", id, partition, err)
+		}
+	}
+	m.removeLocked(p, m.key(id))
+	if p.Policy.Persistent {
+		m.tombstoneLocked(p, m.key(id), entry)
+	}
+	return entry, nil
+}
+
 // ListPartitions returns all partition names
 func (m *Manager) ListPartitions() []string {
 	m.mu.RLock()
@@ -243,7 +529,16 @@ func (m *Manager) ListPartitions() []string {
 	return names
 }
 
-func currentTimestamp() int64 {
-	// Simple timestamp - in production would use time.Now().Unix()
-	return 0
+// SetClock sets the clock entries are stamped with. WHY: Entry times
+// decide quarantine expiry and listing order, so a clock stepping
+// backwards is held at its last reading.
+func (m *Manager) SetClock(c clock.Clock) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.clock = clock.Monotonic(c)
+}
+
+// now is the entry timestamp in Unix seconds; m.mu is held
+func (m *Manager) now() int64 {
+	return m.clock.Now().Unix()
 }
@@ -4,6 +4,9 @@ package memory
 
 import (
 	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/clock"
 )
 
 // TestMemoryWriteRequiresPartitionAndPolicy proves MI-1
@@ -33,14 +36,16 @@ func TestQuarantinePromotionRequiresVerification(t *testing.T) {
 		t.Fatalf("write to quarantine failed: %v", err)
 	}
 
+	signer := withVerifier(t, manager)
+
 	// Attempt promotion without verification record - should fail
-	err = manager.PromoteFromQuarantine("untrusted_1", "")
+	err = manager.PromoteFromQuarantine("untrusted_1", VerificationRecord{})
 	if err == nil {
 		t.Fatal("expected error for promotion without verification")
 	}
 
 	// Promotion with verification record should succeed
-	err = manager.PromoteFromQuarantine("untrusted_1", "verification_signature_xyz")
+	err = manager.PromoteFromQuarantine("untrusted_1", signedRecord(t, manager, signer, "untrusted_1", VerdictPromote))
 	if err != nil {
 		t.Fatalf("promotion with verification failed: %v", err)
 	}
@@ -192,3 +197,81 @@ func TestListPartitions(t *testing.T) {
 		}
 	}
 }
+
+// TestOperationsAreReported proves writes, protected reads, promotions,
+// and deletions are reported, while unprotected reads are not
+func TestOperationsAreReported(t *testing.T) {
+	manager := NewManager()
+	signer := withVerifier(t, manager)
+	var seen []Operation
+	manager.OnOperation(func(op Operation) { seen = append(seen, op) })
+
+	manager.Write(PartitionEphemeral, "scratch", "draft", nil)
+	manager.Read(PartitionEphemeral, "scratch")
+	manager.Write(PartitionDurable, "note", "remember this", nil)
+	manager.Read(PartitionDurable, "note")
+	manager.Write(PartitionQuarantine, "fetched", "untrusted", nil)
+	manager.PromoteFromQuarantine("fetched", signedRecord(t, manager, signer, "fetched", VerdictPromote))
+	if err := manager.Delete(PartitionDurable, "note"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	want := []string{
+		OpWrite + " " + PartitionEphemeral,
+		OpWrite + " " + PartitionDurable,
+		OpRead + " " + PartitionDurable,
+		OpWrite + " " + PartitionQuarantine,
+		OpPromote + " " + PartitionDurable,
+		OpDelete + " " + PartitionDurable,
+	}
+	if len(seen) != len(want) {
+		t.Fatalf("expected %d operations, got %+v", len(want), seen)
+	}
+	for i, op := range seen {
+		if op.Action+" "+op.Partition != want[i] || op.ContentHash == "" {
+			t.Fatalf("operation %d: expected %s, got %+v", i, want[i], op)
+		}
+	}
+	if seen[5].ContentHash != seen[1].ContentHash {
+		t.Fatal("a deletion must carry the hash of what was deleted")
+	}
+}
+
+// TestDeleteRespectsAppendOnly proves history and quarantine cannot be deleted
+func TestDeleteRespectsAppendOnly(t *testing.T) {
+	manager := NewManager()
+	manager.Write(PartitionEvidence, "checkpoint", "root", nil)
+	manager.Write(PartitionQuarantine, "fetched", "untrusted", nil)
+
+	if err := manager.Delete(PartitionEvidence, "checkpoint"); err == nil {
+		t.Fatal("append-only entries must not be deleted")
+	}
+	if err := manager.Delete(PartitionQuarantine, "fetched"); err == nil {
+		t.Fatal("quarantined entries must not be deleted")
+	}
+	if err := manager.Delete(PartitionDurable, "missing"); err == nil {
+		t.Fatal("deleting a missing entry must fail")
+	}
+}
+
+// TestEntriesAreStampedByTheClock proves entries carry the clock's time
+// and a clock stepping backwards never stamps a later entry earlier
+func TestEntriesAreStampedByTheClock(t *testing.T) {
+	manager := NewManager()
+	start := time.Unix(1_700_000_000, 0)
+	now := clock.NewFake(start)
+	manager.SetClock(now)
+
+	manager.Write(PartitionDurable, "first", "one", nil)
+	now.Set(start.Add(-time.Hour))
+	manager.Write(PartitionDurable, "second", "two", nil)
+
+	first, _ := manager.Read(PartitionDurable, "first")
+	second, _ := manager.Read(PartitionDurable, "second")
+	if first.Timestamp != start.Unix() {
+		t.Fatalf("expected the clock's time %d, got %d", start.Unix(), first.Timestamp)
+	}
+	if second.Timestamp < first.Timestamp {
+		t.Fatalf("a later entry must not be stamped earlier: %d < %d", second.Timestamp, first.Timestamp)
+	}
+}
@@ -0,0 +1,32 @@
+// WHY: Every namespace used to share one map per partition, so two
+// namespaces writing the same entry ID overwrote each other and each could
+// read the other's memory. Entries are now keyed by namespace and ID, and
+// a Manager only sees the namespace it was opened for, while partition
+// policy, quotas, hooks, and the store stay shared by the deployment.
+package memory
+
+import "strconv"
+
+// Namespace returns a view of namespace over the same partitions. Views
+// never see each other's entries; the view NewManager returns is the
+// default namespace "".
+func (m *Manager) Namespace(namespace string) *Manager {
+	return &Manager{space: m.space, namespace: namespace}
+}
+
+// NamespaceID returns the namespace this view reads and writes
+func (m *Manager) NamespaceID() string {
+	return m.namespace
+}
+
+// entryKey is an entry's key in its partition's map. WHY: The namespace
+// is length-prefixed, so no namespace and ID pair can produce another
+// pair's key whatever characters either holds.
+func entryKey(namespace string, id string) string {
+	return strconv.Itoa(len(namespace)) + ":" + namespace + id
+}
+
+// key is id's key in this namespace
+func (m *Manager) key(id string) string {
+	return entryKey(m.namespace, id)
+}
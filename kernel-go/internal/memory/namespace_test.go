@@ -0,0 +1,90 @@
+// WHY: These tests prove two namespaces writing the same entry IDs never
+// collide, and no operation through one namespace's view reaches another's
+// entries, in memory or after a restart.
+package memory
+
+import "testing"
+
+// TestNamespacesDoNotCollide proves the same ID holds separate entries
+func TestNamespacesDoNotCollide(t *testing.T) {
+	shared := NewManager()
+	home, work := shared.Namespace("home"), shared.Namespace("work")
+
+	home.Write(PartitionDurable, "note", "home note", nil)
+	work.Write(PartitionDurable, "note", "work note", nil)
+	home.Write(PartitionEvidence, "checkpoint", "home root", nil)
+	if err := work.Write(PartitionEvidence, "checkpoint", "work root", nil); err != nil {
+		t.Fatalf("append-only must be per namespace: %v", err)
+	}
+
+	for view, want := range map[*Manager]string{home: "home note", work: "work note"} {
+		entry, err := view.Read(PartitionDurable, "note")
+		if err != nil || entry.Content != want || entry.Namespace != view.NamespaceID() {
+			t.Fatalf("%s: expected %q, got %+v (%v)", view.NamespaceID(), want, entry, err)
+		}
+	}
+	if _, err := shared.Read(PartitionDurable, "note"); err == nil {
+		t.Fatal("the default namespace must not see named namespaces")
+	}
+}
+
+// TestCrossNamespaceAccessIsDenied proves listing, deleting, promoting,
+// and quotas stay inside a namespace
+func TestCrossNamespaceAccessIsDenied(t *testing.T) {
+	shared := NewManager()
+	home, work := shared.Namespace("home"), shared.Namespace("work")
+	signer := withVerifier(t, shared)
+	var namespaces []string
+	shared.OnOperation(func(op Operation) { namespaces = append(namespaces, op.Namespace) })
+
+	home.Write(PartitionDurable, "private", "home secret", nil)
+	home.Write(PartitionQuarantine, "fetched", "untrusted", nil)
+
+	if entries, _ := work.List(PartitionDurable, EntryFilter{}); len(entries) != 0 {
+		t.Fatalf("listing must not cross namespaces, got %d entries", len(entries))
+	}
+	if err := work.Delete(PartitionDurable, "private"); err == nil {
+		t.Fatal("deleting must not cross namespaces")
+	}
+	if _, err := work.QuarantinedHash("fetched"); err == nil {
+		t.Fatal("quarantine hashes must not cross namespaces")
+	}
+	record := signedRecord(t, home, signer, "fetched", VerdictPromote)
+	if err := work.PromoteFromQuarantine("fetched", record); err == nil {
+		t.Fatal("promotion must not cross namespaces")
+	}
+	if err := home.PromoteFromQuarantine("fetched", record); err != nil {
+		t.Fatalf("promote in home: %v", err)
+	}
+	if _, err := work.Read(PartitionDurable, "fetched"); err == nil {
+		t.Fatal("a promoted entry must stay in its namespace")
+	}
+
+	shared.SetQuota(PartitionDurable, Quota{MaxEntries: 2})
+	if err := work.WriteAs("", PartitionDurable, "first", "work", nil); err != nil {
+		t.Fatalf("another namespace's usage must not count: %v", err)
+	}
+	for _, namespace := range namespaces {
+		if namespace != "home" && namespace != "work" {
+			t.Fatalf("operations must name their namespace, got %q", namespace)
+		}
+	}
+}
+
+// TestNamespacesSurviveRestart proves stored entries reload into their
+// own namespaces
+func TestNamespacesSurviveRestart(t *testing.T) {
+	dir := t.TempDir()
+	shared := reopen(t, dir)
+	shared.Namespace("home").Write(PartitionDurable, "note", "home note", nil)
+	shared.Namespace("work").Write(PartitionDurable, "note", "work note", nil)
+	shared.Write(PartitionDurable, "note", "default note", nil)
+
+	restarted := reopen(t, dir)
+	for namespace, want := range map[string]string{"home": "home note", "work": "work note", "": "default note"} {
+		entry, err := restarted.Namespace(namespace).Read(PartitionDurable, "note")
+		if err != nil || entry.Content != want {
+			t.Fatalf("%q: expected %q after restart, got %+v (%v)", namespace, want, entry, err)
+		}
+	}
+}
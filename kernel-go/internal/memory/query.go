@@ -0,0 +1,124 @@
+// WHY: Embedders need to enumerate durable memory and walk the provenance
+// chain. Filtering inside the manager gives them copies that match one set
+// of rules, under the partition's read policy, without reaching into the
+// unexported maps or holding entries the manager may still change.
+package memory
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// EntryFilter selects entries. Zero-valued fields match everything.
+type EntryFilter struct {
+	// Since and Until bound the entry timestamp (Unix seconds); Since is
+	// inclusive and Until exclusive
+	Since int64
+	Until int64
+
+	// IDPrefix matches entries whose ID starts with it
+	IDPrefix string
+
+	// ContentHash matches entries with exactly this content hash
+	ContentHash string
+
+	// MetadataKeys must all be present; Metadata values must all be equal
+	MetadataKeys []string
+	Metadata     map[string]interface{}
+}
+
+// matches reports whether entry passes the filter
+func (f EntryFilter) matches(entry *Entry) bool {
+	if f.Since != 0 && entry.Timestamp < f.Since {
+		return false
+	}
+	if f.Until != 0 && entry.Timestamp >= f.Until {
+		return false
+	}
+	if !strings.HasPrefix(entry.ID, f.IDPrefix) {
+		return false
+	}
+	if f.ContentHash != "" && entry.ContentHash != f.ContentHash {
+		return false
+	}
+	for _, key := range f.MetadataKeys {
+		if _, ok := entry.Metadata[key]; !ok {
+			return false
+		}
+	}
+	for key, want := range f.Metadata {
+		got, ok := entry.Metadata[key]
+		if !ok || !reflect.DeepEqual(got, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// List returns copies of the entries in a partition that match filter,
+// oldest first (ties by ID). Each entry returned from a partition that
+// requires a capability is reported as a read, as Read would.
+// WHY: Quarantine is write-only until promoted, so it cannot be listed.
+func (m *Manager) List(partition string, filter EntryFilter) ([]*Entry, error) {
+	entries, protected, err := m.list(partition, filter)
+	if err != nil {
+		return nil, err
+	}
+	if protected {
+		for _, entry := range entries {
+			m.notify(Operation{Action: OpRead, Partition: partition, ID: entry.ID, ContentHash: entry.ContentHash})
+		}
+	}
+	return entries, nil
+}
+
+// list is List without the notifications
+func (m *Manager) list(partition string, filter EntryFilter) ([]*Entry, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	p, exists := m.partitions[partition]
+	if !exists {
+		return nil, false, fmt.Errorf("partition %s does not exist", partition)
+	}
+	if !p.Policy.AllowRead {
+		return nil, false, fmt.Errorf("partition %s is write-only", partition)
+	}
+
+	entries := []*Entry{}
+	for _, entry := range p.Entries {
+		if entry.Namespace != m.namespace || !filter.matches(entry) {
+			continue
+		}
+		if p.Policy.Signed {
+			if _, err := m.verifyCommitmentLocked(entry); err != nil {
+				return nil, false, err
+			}
+		}
+		entries = append(entries, copyEntry(entry))
+	}
+	sortEntries(entries)
+	return entries, p.Policy.RequireCapability, nil
+}
+
+// sortEntries orders entries oldest first, ties by ID
+func sortEntries(entries []*Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Timestamp != entries[j].Timestamp {
+			return entries[i].Timestamp < entries[j].Timestamp
+		}
+		return entries[i].ID < entries[j].ID
+	})
+}
+
+// copyEntry copies an entry and its metadata map
+func copyEntry(entry *Entry) *Entry {
+	copied := *entry
+	copied.Metadata = make(map[string]interface{}, len(entry.Metadata))
+	for key, value := range entry.Metadata {
+		copied.Metadata[key] = value
+	}
+	return &copied
+}
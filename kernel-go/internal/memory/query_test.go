@@ -0,0 +1,79 @@
+// WHY: These tests prove listing applies every filter field, returns
+// copies, respects the quarantine's read policy, and is audited like Read.
+package memory
+
+import "testing"
+
+// TestListFiltersEntries proves each filter field narrows the listing
+func TestListFiltersEntries(t *testing.T) {
+	manager := NewManager()
+	manager.Write(PartitionDurable, "note/a", "alpha", map[string]interface{}{"kind": "note", "pinned": true})
+	manager.Write(PartitionDurable, "note/b", "beta", map[string]interface{}{"kind": "note"})
+	manager.Write(PartitionDurable, "task/a", "gamma", map[string]interface{}{"kind": "task"})
+	manager.partitions[PartitionDurable].Entries[manager.key("note/b")].Timestamp = 100
+	beta, _ := manager.Read(PartitionDurable, "note/b")
+
+	for name, tc := range map[string]struct {
+		filter EntryFilter
+		want   []string
+	}{
+		"everything":     {EntryFilter{}, []string{"note/b", "note/a", "task/a"}},
+		"id prefix":      {EntryFilter{IDPrefix: "note/"}, []string{"note/b", "note/a"}},
+		"content hash":   {EntryFilter{ContentHash: beta.ContentHash}, []string{"note/b"}},
+		"metadata key":   {EntryFilter{MetadataKeys: []string{"pinned"}}, []string{"note/a"}},
+		"metadata value": {EntryFilter{Metadata: map[string]interface{}{"kind": "task"}}, []string{"task/a"}},
+		"until":          {EntryFilter{Until: 101}, []string{"note/b"}},
+		"since":          {EntryFilter{Since: 101}, []string{"note/a", "task/a"}},
+	} {
+		entries, err := manager.List(PartitionDurable, tc.filter)
+		if err != nil {
+			t.Fatalf("%s: list: %v", name, err)
+		}
+		if len(entries) != len(tc.want) {
+			t.Fatalf("%s: expected %v, got %d entries", name, tc.want, len(entries))
+		}
+		for i, entry := range entries {
+			if entry.ID != tc.want[i] {
+				t.Fatalf("%s: expected %v, got %s at %d", name, tc.want, entry.ID, i)
+			}
+		}
+	}
+}
+
+// TestListReturnsCopiesAndRespectsPolicy proves listed entries cannot be
+// used to change memory, quarantine cannot be listed, and protected
+// entries are reported as reads
+func TestListReturnsCopiesAndRespectsPolicy(t *testing.T) {
+	manager := NewManager()
+	manager.Write(PartitionDurable, "note", "remember this", map[string]interface{}{"kind": "note"})
+	manager.Write(PartitionEphemeral, "scratch", "draft", nil)
+	manager.Write(PartitionQuarantine, "fetched", "untrusted", nil)
+	reads := 0
+	manager.OnOperation(func(op Operation) {
+		if op.Action == OpRead {
+			reads++
+		}
+	})
+
+	entries, _ := manager.List(PartitionDurable, EntryFilter{})
+	entries[0].Content = "rewritten"
+	entries[0].Metadata["kind"] = "rewritten"
+	stored, _ := manager.Read(PartitionDurable, "note")
+	if stored.Content != "remember this" || stored.Metadata["kind"] != "note" {
+		t.Fatal("listed entries must be copies")
+	}
+	if reads != 2 {
+		t.Fatalf("listing and reading a protected entry must each be reported, got %d reads", reads)
+	}
+
+	manager.List(PartitionEphemeral, EntryFilter{})
+	if reads != 2 {
+		t.Fatal("unprotected listings must not be reported")
+	}
+	if _, err := manager.List(PartitionQuarantine, EntryFilter{}); err == nil {
+		t.Fatal("quarantine must not be listable")
+	}
+	if _, err := manager.List("missing", EntryFilter{}); err == nil {
+		t.Fatal("unknown partitions must be refused")
+	}
+}
@@ -0,0 +1,157 @@
+// WHY: In a shared deployment every principal writes into the same
+// partitions, so one tenant filling durable memory would starve the rest.
+// Quotas bound each principal's entries and bytes per partition; a write
+// over quota is refused with a QuotaError and reported, so the ledger
+// shows who hit the limit.
+package memory
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Quota reasons reported in QuotaError
+const (
+	QuotaEntries = "entries"
+	QuotaBytes   = "bytes"
+)
+
+// Quota bounds one principal's usage of one partition within a namespace;
+// zero fields are unlimited
+type Quota struct {
+	MaxEntries int
+	MaxBytes   int64
+}
+
+// QuotaError reports a write refused because it would exceed a quota.
+// WHY: A distinct type lets callers tell a full quota from a policy
+// violation.
+type QuotaError struct {
+	Principal string
+	Partition string
+	Reason    string // QuotaEntries or QuotaBytes
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("principal %q over %s quota in partition %s", e.Principal, e.Reason, e.Partition)
+}
+
+// quotas holds the configured quotas by partition
+type quotas struct {
+	// defaults apply to every principal without an override
+	defaults map[string]Quota
+
+	// overrides are keyed by principal, then partition
+	overrides map[string]map[string]Quota
+}
+
+// SetQuota sets the quota each principal gets in partition
+func (m *Manager) SetQuota(partition string, quota Quota) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkQuotaConfigLocked(partition, quota); err != nil {
+		return err
+	}
+	if m.quotas.defaults == nil {
+		m.quotas.defaults = make(map[string]Quota)
+	}
+	m.quotas.defaults[partition] = quota
+	return nil
+}
+
+// SetPrincipalQuota overrides the quota one principal gets in partition
+func (m *Manager) SetPrincipalQuota(principal string, partition string, quota Quota) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkQuotaConfigLocked(partition, quota); err != nil {
+		return err
+	}
+	if m.quotas.overrides == nil {
+		m.quotas.overrides = make(map[string]map[string]Quota)
+	}
+	if m.quotas.overrides[principal] == nil {
+		m.quotas.overrides[principal] = make(map[string]Quota)
+	}
+	m.quotas.overrides[principal][partition] = quota
+	return nil
+}
+
+// checkQuotaConfigLocked validates a quota for partition; m.mu is held
+func (m *Manager) checkQuotaConfigLocked(partition string, quota Quota) error {
+	if _, exists := m.partitions[partition]; !exists {
+		return fmt.Errorf("partition %s does not exist", partition)
+	}
+	if quota.MaxEntries < 0 || quota.MaxBytes < 0 {
+		return fmt.Errorf("invalid quota %+v", quota)
+	}
+	return nil
+}
+
+// quotaLocked returns principal's quota in partition; m.mu is held
+func (m *Manager) quotaLocked(principal string, partition string) Quota {
+	if quota, ok := m.quotas.overrides[principal][partition]; ok {
+		return quota
+	}
+	return m.quotas.defaults[partition]
+}
+
+// Usage returns how many entries and content bytes principal holds in
+// partition in this namespace
+func (m *Manager) Usage(principal string, partition string) (int, int64) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	p, exists := m.partitions[partition]
+	if !exists {
+		return 0, 0
+	}
+	return m.usageLocked(p, principal, "")
+}
+
+// usageLocked counts principal's entries in p in this namespace, leaving
+// out the entry with ID skip; m.mu is held
+func (m *Manager) usageLocked(p *Partition, principal string, skip string) (int, int64) {
+	entries, bytes := 0, int64(0)
+	for _, entry := range p.Entries {
+		if entry.Namespace != m.namespace || entry.Principal != principal || entry.ID == skip {
+			continue
+		}
+		entries++
+		bytes += int64(len(entry.Content))
+	}
+	return entries, bytes
+}
+
+// checkQuotaLocked refuses a write of size bytes to id in p that would
+// take principal over quota. An entry being replaced stops counting.
+// WHY: Replacing another principal's entry would move usage between
+// tenants, so it is refused outright.
+func (m *Manager) checkQuotaLocked(p *Partition, principal string, id string, size int) error {
+	if current, exists := p.Entries[m.key(id)]; exists && current.Principal != principal {
+		return fmt.Errorf("entry %s in partition %s belongs to another principal", id, p.Name)
+	}
+	quota := m.quotaLocked(principal, p.Name)
+	if quota.MaxEntries == 0 && quota.MaxBytes == 0 {
+		return nil
+	}
+	entries, bytes := m.usageLocked(p, principal, id)
+	if quota.MaxEntries > 0 && entries+1 > quota.MaxEntries {
+		return &QuotaError{Principal: principal, Partition: p.Name, Reason: QuotaEntries}
+	}
+	if quota.MaxBytes > 0 && bytes+int64(size) > quota.MaxBytes {
+		return &QuotaError{Principal: principal, Partition: p.Name, Reason: QuotaBytes}
+	}
+	return nil
+}
+
+// notifyRefused reports a write refused for quota; other refusals are
+// the caller's error alone
+func (m *Manager) notifyRefused(id string, err error) {
+	var over *QuotaError
+	if !errors.As(err, &over) {
+		return
+	}
+	m.notify(Operation{Action: OpRefuse, Partition: over.Partition, ID: id, Principal: over.Principal, Reason: over.Reason})
+}
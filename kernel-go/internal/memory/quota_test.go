@@ -0,0 +1,73 @@
+// WHY: These tests prove quotas are counted per principal and partition,
+// overrides win over defaults, replacing an entry does not double count,
+// and refusals are reported.
+package memory
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestQuotasBoundEachPrincipal proves one principal filling its quota
+// leaves another's untouched
+func TestQuotasBoundEachPrincipal(t *testing.T) {
+	manager := NewManager()
+	manager.SetQuota(PartitionDurable, Quota{MaxEntries: 2, MaxBytes: 10})
+	var refused []Operation
+	manager.OnOperation(func(op Operation) {
+		if op.Action == OpRefuse {
+			refused = append(refused, op)
+		}
+	})
+
+	manager.WriteAs("alice", PartitionDurable, "a1", "1234", nil)
+	manager.WriteAs("alice", PartitionDurable, "a2", "1234", nil)
+	err := manager.WriteAs("alice", PartitionDurable, "a3", "1", nil)
+	var over *QuotaError
+	if !errors.As(err, &over) || over.Reason != QuotaEntries || over.Principal != "alice" {
+		t.Fatalf("a third entry must exceed the entry quota, got %v", err)
+	}
+	if err := manager.WriteAs("alice", PartitionDurable, "a2", "123456", nil); err != nil {
+		t.Fatalf("replacing an entry must count only the new content: %v", err)
+	}
+	if err := manager.WriteAs("alice", PartitionDurable, "a2", "1234567", nil); !errors.As(err, &over) || over.Reason != QuotaBytes {
+		t.Fatalf("content over the byte quota must be refused, got %v", err)
+	}
+	if err := manager.WriteAs("bob", PartitionDurable, "b1", "1234", nil); err != nil {
+		t.Fatalf("another principal must keep its own quota: %v", err)
+	}
+	if err := manager.WriteAs("alice", PartitionEphemeral, "e1", strings.Repeat("x", 100), nil); err != nil {
+		t.Fatalf("quotas must apply only to their partition: %v", err)
+	}
+
+	if entries, bytes := manager.Usage("alice", PartitionDurable); entries != 2 || bytes != 10 {
+		t.Fatalf("expected alice to hold 2 entries and 10 bytes, got %d and %d", entries, bytes)
+	}
+	if len(refused) != 2 || refused[0].Principal != "alice" || refused[0].ID != "a3" || refused[1].Reason != QuotaBytes {
+		t.Fatalf("each refusal must be reported, got %+v", refused)
+	}
+}
+
+// TestQuotaOverridesAndOwnership proves a principal override applies, and
+// no principal can replace another's entry
+func TestQuotaOverridesAndOwnership(t *testing.T) {
+	manager := NewManager()
+	manager.SetQuota(PartitionDurable, Quota{MaxEntries: 1})
+	manager.SetPrincipalQuota("service", PartitionDurable, Quota{})
+
+	for _, id := range []string{"s1", "s2", "s3"} {
+		if err := manager.WriteAs("service", PartitionDurable, id, "data", nil); err != nil {
+			t.Fatalf("an unlimited override must win over the default: %v", err)
+		}
+	}
+	if err := manager.WriteAs("alice", PartitionDurable, "s1", "mine now", nil); err == nil {
+		t.Fatal("an entry must not be replaced by another principal")
+	}
+	if err := manager.SetQuota(PartitionDurable, Quota{MaxBytes: -1}); err == nil {
+		t.Fatal("a negative quota must be refused")
+	}
+	if err := manager.SetQuota("missing", Quota{MaxEntries: 1}); err == nil {
+		t.Fatal("a quota on an unknown partition must be refused")
+	}
+}
@@ -0,0 +1,64 @@
+// WHY: Read hands back an entry's content as stored, whatever the posture
+// of the session asking. A restricted session (an oracle at P4) must not
+// be able to pull raw high-sensitivity entries out of durable memory, so
+// ReadRedacted runs content through the same CIF egress redaction the
+// corridor applies to output, keyed by the entry's sensitivity.
+package memory
+
+import (
+	"fmt"
+
+	"github.com/user/oi/kernel-go/internal/cif"
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// MetadataSensitivity is the entry metadata key holding its sensitivity:
+// "low", "medium", or "high"
+const MetadataSensitivity = "sensitivity"
+
+// MetadataRedaction is set on a redacted read to why the content was
+// replaced
+const MetadataRedaction = "redaction"
+
+// ReadRedacted reads an entry as a session at postureLevel may see it
+// under the default posture policy. An entry without a sensitivity is
+// low; any value the policy does not know is redacted at every posture.
+// The returned entry is a copy, and its ContentHash still names the
+// stored content.
+func (m *Manager) ReadRedacted(partition string, id string, postureLevel int) (*Entry, error) {
+	// WHY: An undefined posture would pass every sensitivity check
+	if !posture.IsValid(postureLevel) {
+		return nil, fmt.Errorf("read %s: posture %d is not defined", id, postureLevel)
+	}
+	return m.ReadWithPolicy(partition, id, posture.DefaultPolicy().Level(postureLevel))
+}
+
+// ReadWithPolicy reads an entry redacted under the policy of a posture
+// level. WHY: An entry is whole content, not a response, so the policy's
+// leak budget does not truncate it; only its sensitivity ceiling applies.
+func (m *Manager) ReadWithPolicy(partition string, id string, policy posture.LevelPolicy) (*Entry, error) {
+	stored, err := m.Read(partition, id)
+	if err != nil {
+		return nil, err
+	}
+	entry := copyEntry(stored)
+
+	sensitivity := "low"
+	if value, ok := entry.Metadata[MetadataSensitivity]; ok {
+		sensitivity, _ = value.(string)
+	}
+	policy.LeakBudget = len(entry.Content)
+	response, err := cif.EgressWithPolicy(&cif.OutputArtifact{
+		Content:          entry.Content,
+		ContentHash:      entry.ContentHash,
+		SensitivityLevel: sensitivity,
+	}, policy)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", id, err)
+	}
+	if response.Redacted {
+		entry.Content = response.Content
+		entry.Metadata[MetadataRedaction] = response.RedactionReason
+	}
+	return entry, nil
+}
@@ -0,0 +1,56 @@
+// WHY: These tests prove a redacted read hides sensitive content from
+// restricted postures, fails closed on unknown sensitivities and postures,
+// and never changes what is stored.
+package memory
+
+import (
+	"testing"
+
+	"github.com/user/oi/kernel-go/internal/posture"
+)
+
+// TestReadRedactedFollowsPostureAndSensitivity proves each sensitivity is
+// redacted from the posture CIF egress names, and stored content stays raw
+func TestReadRedactedFollowsPostureAndSensitivity(t *testing.T) {
+	manager := NewManager()
+	manager.Write(PartitionDurable, "recovery", "recovery code 1234", map[string]interface{}{MetadataSensitivity: "high"})
+	manager.Write(PartitionDurable, "address", "12 Elm Street", map[string]interface{}{MetadataSensitivity: "medium"})
+	manager.Write(PartitionDurable, "note", "likes tea", nil)
+	manager.Write(PartitionDurable, "odd", "unclassified", map[string]interface{}{MetadataSensitivity: "secret"})
+
+	for _, tc := range []struct {
+		id       string
+		level    int
+		redacted bool
+	}{
+		{"recovery", posture.P1, false},
+		{"recovery", posture.P4, true},
+		{"address", posture.P2, false},
+		{"address", posture.P3, true},
+		{"note", posture.P4, false},
+		{"odd", posture.P1, true},
+	} {
+		entry, err := manager.ReadRedacted(PartitionDurable, tc.id, tc.level)
+		if err != nil {
+			t.Fatalf("%s at P%d: %v", tc.id, tc.level, err)
+		}
+		_, marked := entry.Metadata[MetadataRedaction]
+		if marked != tc.redacted || (entry.Content == storedContent(manager, tc.id)) == tc.redacted {
+			t.Fatalf("%s at P%d: expected redacted=%v, got %+v", tc.id, tc.level, tc.redacted, entry)
+		}
+	}
+
+	stored, _ := manager.Read(PartitionDurable, "recovery")
+	if stored.Content != "recovery code 1234" || stored.Metadata[MetadataRedaction] != nil {
+		t.Fatal("a redacted read must not change the stored entry")
+	}
+	if _, err := manager.ReadRedacted(PartitionDurable, "note", posture.P0); err == nil {
+		t.Fatal("an undefined posture must be refused")
+	}
+}
+
+// storedContent returns the content stored under id
+func storedContent(manager *Manager, id string) string {
+	entry, _ := manager.Read(PartitionDurable, id)
+	return entry.Content
+}
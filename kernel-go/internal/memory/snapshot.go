@@ -0,0 +1,244 @@
+// WHY: A user's durable memory and the commitments made to them belong to
+// them, so they must be able to take both to another kernel. A snapshot is
+// an encrypted archive whose manifest commits to every entry's content
+// hash; import re-checks each hash and each commitment signature before
+// anything is written, so a damaged or altered archive changes nothing.
+package memory
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// SnapshotPartitions are the partitions a snapshot carries
+var SnapshotPartitions = []string{PartitionDurable, PartitionCommitments}
+
+// snapshotMagic prefixes every archive and is authenticated with it
+const snapshotMagic = "OI-MEMORY-SNAPSHOT-1\n"
+
+// SnapshotKeySize is the archive key size (AES-256-GCM)
+const SnapshotKeySize = 32
+
+// SnapshotManifest lists what an archive holds
+type SnapshotManifest struct {
+	CreatedAt int64
+	Entries   []SnapshotRecord
+
+	// Digest is the hex SHA-256 of the encoded records. WHY: The records
+	// carry each content hash, so the digest commits to every entry's
+	// content; metadata is covered by the archive's authentication.
+	Digest string
+}
+
+// SnapshotRecord is the manifest line for one entry
+type SnapshotRecord struct {
+	Partition   string
+	ID          string
+	ContentHash string
+}
+
+// snapshot is the archive's plaintext
+type snapshot struct {
+	Manifest SnapshotManifest
+	Entries  []*Entry
+}
+
+// ExportSnapshot writes this namespace's durable and commitments entries
+// to w as an archive encrypted under key, and returns its manifest.
+// WHY: Commitments are verified on the way out, so an archive never
+// carries one this kernel would refuse to read.
+func (m *Manager) ExportSnapshot(w io.Writer, key []byte) (SnapshotManifest, error) {
+	m.mu.RLock()
+	entries := []*Entry{}
+	for _, name := range SnapshotPartitions {
+		p := m.partitions[name]
+		for _, entry := range p.Entries {
+			if entry.Namespace != m.namespace {
+				continue
+			}
+			if p.Policy.Signed {
+				if _, err := m.verifyCommitmentLocked(entry); err != nil {
+					m.mu.RUnlock()
+					return SnapshotManifest{}, fmt.Errorf("export: %w", err)
+				}
+			}
+			entries = append(entries, copyEntry(entry))
+		}
+	}
+	createdAt := m.now()
+	m.mu.RUnlock()
+
+	sortSnapshot(entries)
+	manifest, err := snapshotManifest(entries)
+	if err != nil {
+		return SnapshotManifest{}, err
+	}
+	manifest.CreatedAt = createdAt
+	plaintext, err := json.Marshal(snapshot{Manifest: manifest, Entries: entries})
+	if err != nil {
+		return SnapshotManifest{}, fmt.Errorf("encode snapshot: %w", err)
+	}
+
+	aead, err := snapshotCipher(key)
+	if err != nil {
+		return SnapshotManifest{}, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return SnapshotManifest{}, fmt.Errorf("snapshot nonce: %w", err)
+	}
+	archive := append([]byte(snapshotMagic), nonce...)
+	archive = aead.Seal(archive, nonce, plaintext, []byte(snapshotMagic))
+	if _, err := w.Write(archive); err != nil {
+		return SnapshotManifest{}, fmt.Errorf("write snapshot: %w", err)
+	}
+	return manifest, nil
+}
+
+// ImportSnapshot reads an archive exported under key and writes its
+// entries into this manager, returning the manifest it verified.
+// WHY: Import is for migration into a kernel that does not hold the same
+// entries yet; any ID already present is refused rather than merged, and
+// commitments must verify under this kernel's governance keys.
+func (m *Manager) ImportSnapshot(r io.Reader, key []byte) (SnapshotManifest, error) {
+	archive, err := io.ReadAll(r)
+	if err != nil {
+		return SnapshotManifest{}, fmt.Errorf("read snapshot: %w", err)
+	}
+	aead, err := snapshotCipher(key)
+	if err != nil {
+		return SnapshotManifest{}, err
+	}
+	header := len(snapshotMagic) + aead.NonceSize()
+	if len(archive) < header || string(archive[:len(snapshotMagic)]) != snapshotMagic {
+		return SnapshotManifest{}, fmt.Errorf("not a memory snapshot")
+	}
+	plaintext, err := aead.Open(nil, archive[len(snapshotMagic):header], archive[header:], []byte(snapshotMagic))
+	if err != nil {
+		return SnapshotManifest{}, fmt.Errorf("snapshot does not decrypt under this key")
+	}
+	var contents snapshot
+	if err := json.Unmarshal(plaintext, &contents); err != nil {
+		return SnapshotManifest{}, fmt.Errorf("decode snapshot: %w", err)
+	}
+
+	imported, err := m.importSnapshot(contents)
+	if err != nil {
+		return SnapshotManifest{}, err
+	}
+	for _, entry := range imported {
+		m.notify(Operation{Action: OpWrite, Partition: entry.Partition, ID: entry.ID, ContentHash: entry.ContentHash})
+	}
+	return contents.Manifest, nil
+}
+
+// importSnapshot checks every entry against the manifest and this
+// manager, then writes them all; nothing is written if any check fails
+func (m *Manager) importSnapshot(contents snapshot) ([]*Entry, error) {
+	manifest, err := snapshotManifest(contents.Entries)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Digest != contents.Manifest.Digest || len(manifest.Entries) != len(contents.Manifest.Entries) {
+		return nil, fmt.Errorf("snapshot entries do not match its manifest")
+	}
+	for i, record := range manifest.Entries {
+		if record != contents.Manifest.Entries[i] {
+			return nil, fmt.Errorf("snapshot entry %s does not match its manifest", record.ID)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	allowed := map[string]bool{}
+	for _, name := range SnapshotPartitions {
+		allowed[name] = true
+	}
+	seen := map[string]bool{}
+	for _, entry := range contents.Entries {
+		if !allowed[entry.Partition] {
+			return nil, fmt.Errorf("snapshot entry %s is in partition %s, which snapshots do not carry", entry.ID, entry.Partition)
+		}
+		if hashContent(entry.Content) != entry.ContentHash {
+			return nil, fmt.Errorf("snapshot entry %s does not match its content hash", entry.ID)
+		}
+		// WHY: A snapshot moves between namespaces as well as kernels,
+		// so entries land in this view's namespace
+		entry.Namespace = m.namespace
+		// History stays with the kernel it was written in, so an imported
+		// entry starts a new chain
+		entry.Previous = ""
+		p := m.partitions[entry.Partition]
+		if _, exists := p.Entries[m.key(entry.ID)]; exists || seen[entry.Partition+"/"+entry.ID] {
+			return nil, fmt.Errorf("partition %s already holds entry %s", entry.Partition, entry.ID)
+		}
+		// WHY: A restored backup must not bring back what was deleted
+		// since it was taken; the deletion has to be collected first
+		if _, deleted := m.tombstones[tombstoneKey{partition: entry.Partition, key: m.key(entry.ID)}]; deleted {
+			return nil, fmt.Errorf("entry %s was deleted from %s; its tombstone has not been collected", entry.ID, entry.Partition)
+		}
+		seen[entry.Partition+"/"+entry.ID] = true
+		if entry.Metadata == nil {
+			entry.Metadata = make(map[string]interface{})
+		}
+		if p.Policy.Signed {
+			if _, err := m.verifyCommitmentLocked(entry); err != nil {
+				return nil, fmt.Errorf("import: %w", err)
+			}
+		}
+	}
+
+	b := &batch{m: m}
+	for _, entry := range contents.Entries {
+		b.put(m.partitions[entry.Partition], m.key(entry.ID), entry)
+	}
+	if err := b.commit(); err != nil {
+		return nil, fmt.Errorf("import: %w", err)
+	}
+	return contents.Entries, nil
+}
+
+// snapshotManifest computes the manifest of entries in archive order
+func snapshotManifest(entries []*Entry) (SnapshotManifest, error) {
+	manifest := SnapshotManifest{Entries: make([]SnapshotRecord, len(entries))}
+	for i, entry := range entries {
+		manifest.Entries[i] = SnapshotRecord{Partition: entry.Partition, ID: entry.ID, ContentHash: entry.ContentHash}
+	}
+	encoded, err := json.Marshal(manifest.Entries)
+	if err != nil {
+		return SnapshotManifest{}, fmt.Errorf("encode snapshot manifest: %w", err)
+	}
+	digest := sha256.Sum256(encoded)
+	manifest.Digest = hex.EncodeToString(digest[:])
+	return manifest, nil
+}
+
+// sortSnapshot orders entries by partition, then ID
+func sortSnapshot(entries []*Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Partition != entries[j].Partition {
+			return entries[i].Partition < entries[j].Partition
+		}
+		return entries[i].ID < entries[j].ID
+	})
+}
+
+// snapshotCipher returns the AEAD for a snapshot key
+func snapshotCipher(key []byte) (cipher.AEAD, error) {
+	if len(key) != SnapshotKeySize {
+		return nil, fmt.Errorf("snapshot key must be %d bytes", SnapshotKeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
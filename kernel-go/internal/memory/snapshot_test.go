@@ -0,0 +1,105 @@
+// WHY: These tests prove a snapshot carries durable memory and commitments
+// to another kernel intact, and that an archive under the wrong key,
+// altered in transit, or clashing with existing entries writes nothing.
+package memory
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+// snapshotKey returns a fresh archive key
+func snapshotKey(t *testing.T) []byte {
+	t.Helper()
+	key := make([]byte, SnapshotKeySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("key: %v", err)
+	}
+	return key
+}
+
+// exported returns a manager holding a note and a commitment, its
+// governance key, and a snapshot of it under key
+func exported(t *testing.T, key []byte) (*Manager, *signing.LocalSigner, []byte) {
+	t.Helper()
+	source := NewManager()
+	governance := withGovernance(t, source)
+	source.Write(PartitionDurable, "note", "remember this", map[string]interface{}{"kind": "note"})
+	source.Write(PartitionEphemeral, "scratch", "draft", nil)
+	source.WriteCommitment(signedUpdate(t, governance, "retention", "30 days", 2))
+
+	var archive bytes.Buffer
+	manifest, err := source.ExportSnapshot(&archive, key)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if len(manifest.Entries) != 2 {
+		t.Fatalf("only durable memory and commitments belong in a snapshot, got %+v", manifest.Entries)
+	}
+	return source, governance, archive.Bytes()
+}
+
+// TestSnapshotMovesMemoryBetweenKernels proves import restores entries
+// and commitments, which still verify under the governance key
+func TestSnapshotMovesMemoryBetweenKernels(t *testing.T) {
+	key := snapshotKey(t)
+	_, governance, archive := exported(t, key)
+
+	target := NewManager()
+	keys := signing.NewKeyRing()
+	keys.AddSigner(governance)
+	target.SetCommitmentKeys(keys)
+	if _, err := target.ImportSnapshot(bytes.NewReader(archive), key); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	note, err := target.Read(PartitionDurable, "note")
+	if err != nil || note.Content != "remember this" || note.Metadata["kind"] != "note" {
+		t.Fatalf("the note must be imported: %+v (%v)", note, err)
+	}
+	if _, version, err := target.ReadCommitment("retention"); err != nil || version != 2 {
+		t.Fatalf("the commitment must import at its version: %d (%v)", version, err)
+	}
+	if _, err := target.Read(PartitionEphemeral, "scratch"); err == nil {
+		t.Fatal("ephemeral memory must not travel")
+	}
+}
+
+// TestSnapshotImportFailsClosed proves a bad archive or a clash leaves the
+// target untouched
+func TestSnapshotImportFailsClosed(t *testing.T) {
+	key := snapshotKey(t)
+	_, governance, archive := exported(t, key)
+	keys := signing.NewKeyRing()
+	keys.AddSigner(governance)
+
+	tampered := append([]byte(nil), archive...)
+	tampered[len(tampered)-1] ^= 1
+	clashing := NewManager()
+	clashing.SetCommitmentKeys(keys)
+	clashing.Write(PartitionDurable, "note", "already here", nil)
+	ungoverned := NewManager()
+
+	for name, tc := range map[string]struct {
+		manager *Manager
+		archive []byte
+		key     []byte
+	}{
+		"wrong key":          {NewManager(), archive, snapshotKey(t)},
+		"altered archive":    {NewManager(), tampered, key},
+		"existing entry":     {clashing, archive, key},
+		"no governance keys": {ungoverned, archive, key},
+	} {
+		if _, err := tc.manager.ImportSnapshot(bytes.NewReader(tc.archive), tc.key); err == nil {
+			t.Fatalf("%s: import must fail", name)
+		}
+	}
+	if note, _ := clashing.Read(PartitionDurable, "note"); note.Content != "already here" {
+		t.Fatal("a failed import must not overwrite anything")
+	}
+	if _, err := ungoverned.Read(PartitionDurable, "note"); err == nil {
+		t.Fatal("a failed import must write nothing")
+	}
+}
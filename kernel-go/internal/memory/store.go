@@ -0,0 +1,364 @@
+// WHY: A "durable" partition that lives only in a map is durable until the
+// next restart. A Store persists the partitions whose policy says they
+// must outlive the process, so user memory, commitments, provenance, and
+// evidence survive restarts, and every entry's content hash is re-checked
+// when it is loaded back.
+package memory
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// Store persists entries of persistent partitions for a Manager.
+// WHY: The interface is small so a deployment can back it with an
+// embedded database (BoltDB, Badger) it links itself; the kernel ships
+// only the filesystem store and stays dependency-free.
+type Store interface {
+	// Put durably records an entry before returning, replacing any entry
+	// with the same partition and ID
+	Put(entry *Entry) error
+
+	// Delete durably removes an entry; removing a missing entry is not
+	// an error
+	Delete(entry *Entry) error
+
+	// Load returns every stored entry
+	Load() ([]*Entry, error)
+
+	// Close releases the backend
+	Close() error
+}
+
+// FileStore keeps one JSON file per entry under a directory per partition,
+// written to a temporary file, synced, and renamed into place.
+type FileStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// OpenFileStore opens (or creates) a memory store rooted at dir
+func OpenFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("open memory store: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// entryFileName names an entry's file. WHY: IDs and namespaces are
+// caller-chosen and may contain path separators, so the file is named by
+// their hashes; the default namespace has none, so its files keep the
+// shorter name and can never match a namespaced one.
+func entryFileName(namespace string, id string) string {
+	sum := sha256.Sum256([]byte(id))
+	name := hex.EncodeToString(sum[:]) + ".json"
+	if namespace == "" {
+		return name
+	}
+	space := sha256.Sum256([]byte(namespace))
+	return hex.EncodeToString(space[:]) + "-" + name
+}
+
+// Put writes one entry and syncs it to stable storage
+func (f *FileStore) Put(entry *Entry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.dir == "" {
+		return fmt.Errorf("memory store is closed")
+	}
+	return f.putLocked(entry)
+}
+
+// putLocked writes one entry in place; f.mu is held
+func (f *FileStore) putLocked(entry *Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encode entry %s: %w", entry.ID, err)
+	}
+
+	partitionDir := filepath.Join(f.dir, entry.Partition)
+	if err := os.MkdirAll(partitionDir, 0o700); err != nil {
+		return fmt.Errorf("store entry %s: %w", entry.ID, err)
+	}
+	if err := replaceFile(partitionDir, ".entry-*", entryFileName(entry.Namespace, entry.ID), data); err != nil {
+		return fmt.Errorf("store entry %s: %w", entry.ID, err)
+	}
+	return nil
+}
+
+// journalName is the file a batch is held in until all its entries are
+// in place
+const journalName = "batch.journal"
+
+// PutBatch writes entries as one batch. WHY: The batch is first synced
+// to a journal, and Load finishes a journal a crash left behind, so after
+// a restart either every entry of the batch is stored or none is. A batch
+// that fails once journaled puts back the files it replaced and drops its
+// journal before reporting the failure, so Load never replays a batch its
+// caller was told failed; one it cannot put back keeps its journal, and
+// no later batch starts over it.
+func (f *FileStore) PutBatch(entries []*Entry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.dir == "" {
+		return fmt.Errorf("memory store is closed")
+	}
+	journalPath := filepath.Join(f.dir, journalName)
+	if _, err := os.Stat(journalPath); err == nil {
+		return fmt.Errorf("journal batch: an earlier batch is unfinished")
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("journal batch: %w", err)
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("encode batch: %w", err)
+	}
+	previous := make([][]byte, len(entries))
+	for i, entry := range entries {
+		data, err := os.ReadFile(f.entryPath(entry))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("journal batch: %w", err)
+		}
+		previous[i] = data
+	}
+	if err := replaceFile(f.dir, ".journal-*", journalName, data); err != nil {
+		return fmt.Errorf("journal batch: %w", err)
+	}
+	for i, entry := range entries {
+		if err := f.putLocked(entry); err != nil {
+			if restoreErr := f.restoreLocked(entries[:i+1], previous); restoreErr != nil {
+				return fmt.Errorf("%w; batch left journaled: %v", err, restoreErr)
+			}
+			return err
+		}
+	}
+	if err := os.Remove(journalPath); err != nil {
+		return fmt.Errorf("drop journal: %w", err)
+	}
+	return syncDir(f.dir)
+}
+
+// entryPath is where an entry's file is kept
+func (f *FileStore) entryPath(entry *Entry) string {
+	return filepath.Join(f.dir, entry.Partition, entryFileName(entry.Namespace, entry.ID))
+}
+
+// restoreLocked puts back the files a failed batch replaced, removes the
+// ones it added, and then drops its journal; f.mu is held
+func (f *FileStore) restoreLocked(entries []*Entry, previous [][]byte) error {
+	for i, entry := range entries {
+		path := f.entryPath(entry)
+		var err error
+		if previous[i] == nil {
+			err = os.Remove(path)
+			if errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ENOTDIR) {
+				// Never written, or its partition could not be made
+				continue
+			}
+			if err == nil {
+				err = syncDir(filepath.Dir(path))
+			}
+		} else {
+			err = replaceFile(filepath.Dir(path), ".entry-*", filepath.Base(path), previous[i])
+		}
+		if err != nil {
+			return fmt.Errorf("restore entry %s: %w", entry.ID, err)
+		}
+	}
+	if err := os.Remove(filepath.Join(f.dir, journalName)); err != nil {
+		return fmt.Errorf("drop journal: %w", err)
+	}
+	return syncDir(f.dir)
+}
+
+// applyJournalLocked puts the journaled entries in place and drops the
+// journal; f.mu is held
+func (f *FileStore) applyJournalLocked(entries []*Entry) error {
+	for _, entry := range entries {
+		if err := f.putLocked(entry); err != nil {
+			return err
+		}
+	}
+	if err := os.Remove(filepath.Join(f.dir, journalName)); err != nil {
+		return fmt.Errorf("drop journal: %w", err)
+	}
+	return syncDir(f.dir)
+}
+
+// replaceFile writes data to name in dir through a synced temporary file
+// matching pattern, renamed into place
+func replaceFile(dir string, pattern string, name string, data []byte) error {
+	temp, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(temp.Name(), filepath.Join(dir, name)); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
+
+// Delete removes one entry's file and syncs the removal
+func (f *FileStore) Delete(entry *Entry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.dir == "" {
+		return fmt.Errorf("memory store is closed")
+	}
+	partitionDir := filepath.Join(f.dir, entry.Partition)
+	err := os.Remove(filepath.Join(partitionDir, entryFileName(entry.Namespace, entry.ID)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("delete entry %s: %w", entry.ID, err)
+	}
+	return syncDir(partitionDir)
+}
+
+// syncDir makes a rename in dir durable
+func syncDir(dir string) error {
+	handle, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer handle.Close()
+	return handle.Sync()
+}
+
+// Load reads every entry under the store's directory
+func (f *FileStore) Load() ([]*Entry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.dir == "" {
+		return nil, fmt.Errorf("memory store is closed")
+	}
+	// WHY: A journal still present is a batch interrupted part way in
+	journal, err := os.ReadFile(filepath.Join(f.dir, journalName))
+	if err == nil {
+		var entries []*Entry
+		if err := json.Unmarshal(journal, &entries); err != nil {
+			return nil, fmt.Errorf("read journal: %w", err)
+		}
+		if err := f.applyJournalLocked(entries); err != nil {
+			return nil, fmt.Errorf("finish journal: %w", err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("read journal: %w", err)
+	}
+	partitions, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, fmt.Errorf("read memory store: %w", err)
+	}
+
+	entries := []*Entry{}
+	for _, partition := range partitions {
+		if !partition.IsDir() {
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(f.dir, partition.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read partition %s: %w", partition.Name(), err)
+		}
+		for _, file := range files {
+			if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+				continue
+			}
+			path := filepath.Join(f.dir, partition.Name(), file.Name())
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("read entry %s: %w", path, err)
+			}
+			var entry Entry
+			if err := json.Unmarshal(data, &entry); err != nil {
+				return nil, fmt.Errorf("entry %s: %w", path, err)
+			}
+			// WHY: A file moved between partitions or renamed would
+			// otherwise load as an entry it was never written as
+			if entry.Partition != partition.Name() || entryFileName(entry.Namespace, entry.ID) != file.Name() {
+				return nil, fmt.Errorf("entry %s does not belong at %s", entry.ID, path)
+			}
+			entries = append(entries, &entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Partition != entries[j].Partition {
+			return entries[i].Partition < entries[j].Partition
+		}
+		return entries[i].ID < entries[j].ID
+	})
+	return entries, nil
+}
+
+// Close releases the store; later calls fail
+func (f *FileStore) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.dir = ""
+	return nil
+}
+
+// Attach persists the manager's persistent partitions in store, loading
+// what it already holds. WHY: Entries written before the store was
+// attached would exist in only one place, so the partitions must still be
+// empty; a stored entry whose content does not match its hash stops the
+// load rather than being served.
+func (m *Manager) Attach(store Store) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.store != nil {
+		return fmt.Errorf("memory store already attached")
+	}
+	for name, partition := range m.partitions {
+		if partition.Policy.Persistent && len(partition.Entries) > 0 {
+			return fmt.Errorf("partition %s already holds entries; attach the store first", name)
+		}
+	}
+
+	entries, err := store.Load()
+	if err != nil {
+		return fmt.Errorf("load memory: %w", err)
+	}
+	for _, entry := range entries {
+		partition, exists := m.partitions[entry.Partition]
+		if !exists || !partition.Policy.Persistent {
+			return fmt.Errorf("stored entry %s is in non-persistent partition %s", entry.ID, entry.Partition)
+		}
+		if hashContent(entry.Content) != entry.ContentHash {
+			return fmt.Errorf("stored entry %s in %s does not match its content hash", entry.ID, entry.Partition)
+		}
+		if entry.Metadata == nil {
+			entry.Metadata = make(map[string]interface{})
+		}
+		m.insertLocked(partition, entryKey(entry.Namespace, entry.ID), entry)
+	}
+	m.store = store
+	return nil
+}
@@ -0,0 +1,107 @@
+// WHY: These tests prove persistent partitions survive a restart with
+// their policies intact, ephemeral and quarantined content does not, and
+// a stored entry that was tampered with stops the load.
+package memory
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// reopen attaches a fresh manager to the store directory, as a restart would
+func reopen(t *testing.T, dir string) *Manager {
+	t.Helper()
+	store, err := OpenFileStore(dir)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	manager := NewManager()
+	if err := manager.Attach(store); err != nil {
+		t.Fatalf("attach: %v", err)
+	}
+	return manager
+}
+
+// TestPersistentPartitionsSurviveRestart proves durable, evidence, and
+// promoted entries are loaded back, and append-only still holds
+func TestPersistentPartitionsSurviveRestart(t *testing.T) {
+	dir := t.TempDir()
+	manager := reopen(t, dir)
+	signer := withVerifier(t, manager)
+	writes := map[string]string{
+		PartitionDurable:   "user note",
+		PartitionEvidence:  "checkpoint",
+		PartitionEphemeral: "scratch",
+	}
+	for partition, content := range writes {
+		if err := manager.Write(partition, "a/b", content, map[string]interface{}{"kind": "test"}); err != nil {
+			t.Fatalf("write %s: %v", partition, err)
+		}
+	}
+	manager.Write(PartitionQuarantine, "fetched", "untrusted", nil)
+	if err := manager.PromoteFromQuarantine("fetched", signedRecord(t, manager, signer, "fetched", VerdictPromote)); err != nil {
+		t.Fatalf("promote: %v", err)
+	}
+
+	restarted := reopen(t, dir)
+	for _, partition := range []string{PartitionDurable, PartitionEvidence} {
+		entry, err := restarted.Read(partition, "a/b")
+		if err != nil || entry.Content != writes[partition] || entry.Metadata["kind"] != "test" {
+			t.Fatalf("%s entry must survive a restart: %+v (%v)", partition, entry, err)
+		}
+	}
+	if _, err := restarted.Read(PartitionEphemeral, "a/b"); err == nil {
+		t.Fatal("ephemeral entries must not survive a restart")
+	}
+	promoted, err := restarted.Read(PartitionDurable, "fetched")
+	if err != nil || !promoted.Verified || promoted.Metadata["verified_by"] != "reviewer" {
+		t.Fatalf("a promoted entry must survive as verified: %+v (%v)", promoted, err)
+	}
+	if err := restarted.Write(PartitionEvidence, "a/b", "rewritten", nil); err == nil {
+		t.Fatal("append-only partitions must stay append-only across restarts")
+	}
+}
+
+// TestStoreRefusesTamperedAndLateAttach proves a stored entry whose content
+// no longer matches its hash is refused, and a store cannot be attached
+// once persistent partitions hold entries only in memory
+func TestStoreRefusesTamperedAndLateAttach(t *testing.T) {
+	dir := t.TempDir()
+	manager := reopen(t, dir)
+	manager.Write(PartitionDurable, "policy", "allow nothing", nil)
+
+	path := filepath.Join(dir, PartitionDurable, entryFileName("", "policy"))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read stored entry: %v", err)
+	}
+	os.WriteFile(path, []byte(strings.Replace(string(data), "allow nothing", "allow everything", 1)), 0o600)
+
+	store, _ := OpenFileStore(dir)
+	if err := NewManager().Attach(store); err == nil || !strings.Contains(err.Error(), "content hash") {
+		t.Fatalf("a tampered entry must stop the load, got %v", err)
+	}
+
+	late := NewManager()
+	late.Write(PartitionDurable, "early", "written before attach", nil)
+	if err := late.Attach(store); err == nil {
+		t.Fatal("attaching after persistent writes must be refused")
+	}
+}
+
+// TestDeletedEntriesStayDeleted proves a deleted persistent entry does not
+// come back on restart
+func TestDeletedEntriesStayDeleted(t *testing.T) {
+	dir := t.TempDir()
+	manager := reopen(t, dir)
+	manager.Write(PartitionDurable, "note", "forget me", nil)
+	if err := manager.Delete(PartitionDurable, "note"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := reopen(t, dir).Read(PartitionDurable, "note"); err == nil {
+		t.Fatal("a deleted entry must not survive a restart")
+	}
+}
@@ -0,0 +1,215 @@
+// WHY: A promotion writes the durable copy and archives the version it
+// replaces, and each used to reach the store on its own, so a crash
+// between them left one without the other. Every write now goes through a
+// batch: entries are checked and staged in memory under the lock, the
+// store records them together, and a refusal anywhere undoes the whole
+// batch. Tx offers the same to callers updating several entries at once.
+package memory
+
+import "fmt"
+
+// BatchStore is a Store that can record several entries atomically.
+// WHY: Without it a batch is put entry by entry and undone on refusal,
+// which a crash part way through can still interrupt.
+type BatchStore interface {
+	Store
+
+	// PutBatch durably records every entry before returning, or none
+	PutBatch(entries []*Entry) error
+}
+
+// Tx stages writes that Commit applies together, or not at all
+type Tx struct {
+	m      *Manager
+	writes []stagedWrite
+	closed bool
+}
+
+// stagedWrite is one write a transaction holds until Commit
+type stagedWrite struct {
+	principal string
+	partition string
+	id        string
+	content   string
+	metadata  map[string]interface{}
+}
+
+// committed is one write a commit applied, and the version it archived
+type committed struct {
+	entry    *Entry
+	archived *Entry
+}
+
+// Begin starts a transaction in the view's namespace
+func (m *Manager) Begin() *Tx {
+	return &Tx{m: m}
+}
+
+// Write stages an entry for Commit
+func (tx *Tx) Write(partition string, id string, content string, metadata map[string]interface{}) error {
+	return tx.WriteAs("", partition, id, content, metadata)
+}
+
+// WriteAs stages an entry for Commit on behalf of principal. Partition
+// policy and quotas are checked at Commit, against memory as it is then.
+func (tx *Tx) WriteAs(principal string, partition string, id string, content string, metadata map[string]interface{}) error {
+	if tx.closed {
+		return fmt.Errorf("transaction is closed")
+	}
+	for _, staged := range tx.writes {
+		if staged.partition == partition && staged.id == id {
+			return fmt.Errorf("entry %s in partition %s is already staged", id, partition)
+		}
+	}
+	// WHY: The caller may reuse its map before Commit
+	copied := make(map[string]interface{}, len(metadata))
+	for key, value := range metadata {
+		copied[key] = value
+	}
+	tx.writes = append(tx.writes, stagedWrite{
+		principal: principal, partition: partition, id: id, content: content, metadata: copied,
+	})
+	return nil
+}
+
+// Commit applies every staged write, or none if any is refused. The
+// transaction is closed either way.
+func (tx *Tx) Commit() error {
+	if tx.closed {
+		return fmt.Errorf("transaction is closed")
+	}
+	tx.closed = true
+	results, refused, err := tx.m.commit(tx.writes)
+	tx.writes = nil
+	if err != nil {
+		tx.m.notifyRefused(refused, err)
+		return err
+	}
+	for _, result := range results {
+		tx.m.notifyArchived(result.archived)
+		tx.m.notify(Operation{Action: OpWrite, Partition: result.entry.Partition, ID: result.entry.ID,
+			ContentHash: result.entry.ContentHash, Principal: result.entry.Principal})
+	}
+	return nil
+}
+
+// Rollback discards the staged writes; rolling back a closed transaction
+// does nothing
+func (tx *Tx) Rollback() {
+	tx.closed = true
+	tx.writes = nil
+}
+
+// commit stages writes in one batch and commits it. It returns what each
+// write applied, or the ID of the write refused and why.
+func (m *Manager) commit(writes []stagedWrite) ([]committed, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b := &batch{m: m}
+	results := make([]committed, 0, len(writes))
+	for _, write := range writes {
+		entry, archived, err := m.stageLocked(b, write)
+		if err != nil {
+			b.undo()
+			return nil, write.id, err
+		}
+		results = append(results, committed{entry: entry, archived: archived})
+	}
+	if err := b.commit(); err != nil {
+		return nil, "", err
+	}
+	return results, "", nil
+}
+
+// batch is the entries one commit puts, each with what it replaced
+type batch struct {
+	m       *Manager
+	changes []change
+}
+
+// change is one entry a batch put and what it replaced under its key
+type change struct {
+	partition *Partition
+	key       string
+	entry     *Entry
+	previous  *Entry
+	tombstone *Tombstone
+}
+
+// put inserts entry under key in p; m.mu is held
+func (b *batch) put(p *Partition, key string, entry *Entry) {
+	b.changes = append(b.changes, change{
+		partition: p,
+		key:       key,
+		entry:     entry,
+		previous:  p.Entries[key],
+		tombstone: b.m.tombstones[tombstoneKey{partition: p.Name, key: key}],
+	})
+	b.m.insertLocked(p, key, entry)
+}
+
+// undo restores memory as it was before the batch; m.mu is held
+func (b *batch) undo() {
+	for i := len(b.changes) - 1; i >= 0; i-- {
+		c := b.changes[i]
+		if c.previous != nil {
+			b.m.insertLocked(c.partition, c.key, c.previous)
+		} else {
+			b.m.removeLocked(c.partition, c.key)
+		}
+		if c.tombstone != nil {
+			b.m.tombstones[tombstoneKey{partition: c.partition.Name, key: c.key}] = c.tombstone
+		}
+	}
+	b.changes = nil
+}
+
+// commit records the batch's persistent entries in the store, undoing
+// the batch if the store refuses; m.mu is held. WHY: The lock is held
+// from staging to here, so no reader sees an entry the store refused.
+func (b *batch) commit() error {
+	var persistent []change
+	for _, c := range b.changes {
+		if c.partition.Policy.Persistent {
+			persistent = append(persistent, c)
+		}
+	}
+	if b.m.store == nil || len(persistent) == 0 {
+		return nil
+	}
+	if err := b.persist(persistent); err != nil {
+		b.undo()
+		return err
+	}
+	return nil
+}
+
+// persist writes changes to the store as one batch if it can, and entry
+// by entry otherwise, putting back what it wrote if one is refused
+func (b *batch) persist(changes []change) error {
+	store := b.m.store
+	if batchStore, ok := store.(BatchStore); ok && len(changes) > 1 {
+		entries := make([]*Entry, len(changes))
+		for i, c := range changes {
+			entries[i] = c.entry
+		}
+		if err := batchStore.PutBatch(entries); err != nil {
+			return fmt.Errorf("persist %d entries: %w", len(entries), err)
+		}
+		return nil
+	}
+	for i, c := range changes {
+		if err := store.Put(c.entry); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				if changes[j].previous != nil {
+					store.Put(changes[j].previous)
+				} else {
+					store.Delete(changes[j].entry)
+				}
+			}
+			return fmt.Errorf("persist entry %s in %s: %w", c.entry.ID, c.partition.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+// WHY: These tests prove a transaction applies all its writes or none, a
+// promotion the store refuses leaves quarantine untouched, and a batch a
+// crash interrupted is finished when the store is loaded, while one that
+// failed is never replayed.
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestTransactionCommitsAllOrNothing proves a refused write undoes the
+// writes staged before it, and a clean commit reports each write
+func TestTransactionCommitsAllOrNothing(t *testing.T) {
+	manager := NewManager()
+	manager.Write(PartitionProvenance, "source", "first record", nil)
+	var written []string
+	manager.OnOperation(func(op Operation) {
+		if op.Action == OpWrite {
+			written = append(written, op.Partition+"/"+op.ID)
+		}
+	})
+
+	tx := manager.Begin()
+	tx.Write(PartitionEphemeral, "draft", "scratch", nil)
+	tx.Write(PartitionDurable, "note", "keep me", nil)
+	tx.Write(PartitionProvenance, "source", "second record", nil)
+	if err := tx.Commit(); err == nil {
+		t.Fatal("a commit with an append-only overwrite must be refused")
+	}
+	_, draftErr := manager.Read(PartitionEphemeral, "draft")
+	_, noteErr := manager.Read(PartitionDurable, "note")
+	if draftErr == nil || noteErr == nil || len(written) != 0 {
+		t.Fatal("a refused commit must not leave its other writes behind")
+	}
+	if err := tx.Write(PartitionEphemeral, "late", "x", nil); err == nil {
+		t.Fatal("a committed transaction must be closed")
+	}
+
+	tx = manager.Begin()
+	tx.Write(PartitionEphemeral, "draft", "scratch", nil)
+	if err := tx.Write(PartitionEphemeral, "draft", "again", nil); err == nil {
+		t.Fatal("staging the same entry twice must be refused")
+	}
+	tx.Write(PartitionDurable, "note", "keep me", nil)
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	if len(written) != 2 || written[0] != "ephemeral/draft" || written[1] != "durable/note" {
+		t.Fatalf("each committed write must be reported, got %v", written)
+	}
+
+	tx = manager.Begin()
+	tx.Write(PartitionEphemeral, "discarded", "never", nil)
+	tx.Rollback()
+	if err := tx.Commit(); err == nil {
+		t.Fatal("a rolled back transaction must not commit")
+	}
+	if _, err := manager.Read(PartitionEphemeral, "discarded"); err == nil {
+		t.Fatal("a rolled back write must not be applied")
+	}
+}
+
+// refusingStore is a FileStore whose batches are refused
+type refusingStore struct {
+	*FileStore
+}
+
+func (r refusingStore) PutBatch(entries []*Entry) error {
+	return errors.New("disk full")
+}
+
+// TestRefusedPromotionLeavesNothingBehind proves a promotion whose durable
+// copy and archived version the store refuses changes neither memory nor
+// the quarantined entry
+func TestRefusedPromotionLeavesNothingBehind(t *testing.T) {
+	dir := t.TempDir()
+	store, err := OpenFileStore(dir)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	manager := NewManager()
+	if err := manager.Attach(refusingStore{store}); err != nil {
+		t.Fatalf("attach: %v", err)
+	}
+	signer := withVerifier(t, manager)
+	manager.Write(PartitionDurable, "fetched", "earlier note", nil)
+	manager.Write(PartitionQuarantine, "fetched", "untrusted", nil)
+
+	if err := manager.PromoteFromQuarantine("fetched", signedRecord(t, manager, signer, "fetched", VerdictPromote)); err == nil {
+		t.Fatal("a promotion the store refuses must fail")
+	}
+	entry, err := manager.Read(PartitionDurable, "fetched")
+	if err != nil || entry.Content != "earlier note" || entry.Version != 1 {
+		t.Fatalf("the durable entry must be as it was: %+v (%v)", entry, err)
+	}
+	if len(manager.partitions[PartitionProvenance].Entries) != 0 {
+		t.Fatal("the archived version must be undone with the promotion")
+	}
+	if quarantined := manager.partitions[PartitionQuarantine].Entries[manager.key("fetched")]; quarantined.Verified {
+		t.Fatal("the quarantined entry must not be marked verified")
+	}
+	if loaded, _ := store.Load(); len(loaded) != 1 {
+		t.Fatalf("the store must hold only the earlier note, got %d entries", len(loaded))
+	}
+}
+
+// TestInterruptedBatchIsFinishedOnLoad proves a journaled batch a crash
+// left half applied is completed when the store is next loaded
+func TestInterruptedBatchIsFinishedOnLoad(t *testing.T) {
+	dir := t.TempDir()
+	manager := reopen(t, dir)
+	manager.Write(PartitionDurable, "note", "first", nil)
+	manager.Write(PartitionDurable, "note", "second", nil)
+	versions, err := manager.History("note")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+
+	// Put the store back as it was when the overwrite's journal had been
+	// synced but neither entry was in place
+	journal, _ := json.Marshal([]*Entry{versions[0], versions[1]})
+	if err := replaceFile(dir, ".journal-*", journalName, journal); err != nil {
+		t.Fatalf("write journal: %v", err)
+	}
+	os.RemoveAll(filepath.Join(dir, PartitionProvenance))
+	before := copyEntry(versions[0])
+	before.Partition, before.ID = PartitionDurable, "note"
+	delete(before.Metadata, MetadataVersionOf)
+	store, _ := OpenFileStore(dir)
+	if err := store.Put(before); err != nil {
+		t.Fatalf("restore earlier entry: %v", err)
+	}
+
+	restarted := reopen(t, dir)
+	history, err := restarted.History("note")
+	if err != nil || len(history) != 2 || history[1].Content != "second" {
+		t.Fatalf("the journaled batch must be finished on load: %+v (%v)", history, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, journalName)); !os.IsNotExist(err) {
+		t.Fatalf("the journal must be dropped once applied, got %v", err)
+	}
+}
+
+// TestFailedBatchIsPutBack proves a batch that fails once journaled puts
+// back what it replaced and drops its journal, so neither a restart nor
+// the next batch brings back any part of it
+func TestFailedBatchIsPutBack(t *testing.T) {
+	dir := t.TempDir()
+	store, err := OpenFileStore(dir)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	before := &Entry{ID: "note", Partition: PartitionDurable, Content: "first", ContentHash: hashContent("first")}
+	if err := store.Put(before); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	// A file where the second entry's partition belongs fails its write
+	// after the first entry is in place
+	blocked := filepath.Join(dir, PartitionProvenance)
+	if err := os.WriteFile(blocked, nil, 0o600); err != nil {
+		t.Fatalf("block partition: %v", err)
+	}
+	after := &Entry{ID: "note", Partition: PartitionDurable, Content: "second", ContentHash: hashContent("second")}
+	archived := &Entry{ID: "note@1", Partition: PartitionProvenance, Content: "first", ContentHash: hashContent("first")}
+	if err := store.PutBatch([]*Entry{after, archived}); err == nil {
+		t.Fatal("a batch whose write fails must be reported")
+	}
+	if _, err := os.Stat(filepath.Join(dir, journalName)); !os.IsNotExist(err) {
+		t.Fatalf("a failed batch must drop its journal, got %v", err)
+	}
+	loaded, err := store.Load()
+	if err != nil || len(loaded) != 1 || loaded[0].Content != "first" {
+		t.Fatalf("a failed batch must leave the store as it was: %+v (%v)", loaded, err)
+	}
+
+	os.Remove(blocked)
+	other := &Entry{ID: "other", Partition: PartitionDurable, Content: "x", ContentHash: hashContent("x")}
+	otherArchived := &Entry{ID: "other@1", Partition: PartitionProvenance, Content: "x", ContentHash: hashContent("x")}
+	if err := store.PutBatch([]*Entry{other, otherArchived}); err != nil {
+		t.Fatalf("next batch: %v", err)
+	}
+	loaded, _ = store.Load()
+	for _, entry := range loaded {
+		if entry.Content == "second" || entry.ID == "note@1" {
+			t.Fatalf("no part of the failed batch may come back, got %+v", entry)
+		}
+	}
+	if len(loaded) != 3 {
+		t.Fatalf("the store must hold the first note and the next batch, got %d entries", len(loaded))
+	}
+}
@@ -0,0 +1,156 @@
+// WHY: An assistant's long-term memory is only useful if it can find what
+// it remembered by meaning, not only by ID. The vector index embeds
+// durable entries and ranks them against a query, but returns IDs and
+// scores only: content still has to be read through Read, so every recall
+// is an audited protected read, and callers hand what they read back
+// through CIF and CDI before it reaches an adapter.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// Embedder turns text into a vector. WHY: Embedding models live outside
+// the kernel (a local model server or a hosted API), so the index only
+// needs this one call.
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float64, error)
+}
+
+// Match is one durable entry ranked against a query
+type Match struct {
+	ID    string
+	Score float64
+}
+
+// vectorIndex holds the embeddings of durable entries across namespaces
+type vectorIndex struct {
+	// mu serializes indexing and searches; the embedder is called under
+	// it but never under the manager's lock
+	mu       sync.Mutex
+	embedder Embedder
+	vectors  map[string]*vector
+}
+
+// vector is one entry's embedding and the content it was made from
+type vector struct {
+	namespace   string
+	id          string
+	contentHash string
+	values      []float64
+}
+
+// indexed is a durable entry as seen when an index is brought up to date
+type indexed struct {
+	key         string
+	id          string
+	content     string
+	contentHash string
+}
+
+// SetEmbedder enables the vector index over durable entries, embedding
+// them with embedder; nil disables it and drops every embedding
+func (m *Manager) SetEmbedder(embedder Embedder) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if embedder == nil {
+		m.vectors = nil
+		return
+	}
+	m.vectors = &vectorIndex{embedder: embedder, vectors: make(map[string]*vector)}
+}
+
+// Search ranks the view's durable entries by cosine similarity to query
+// and returns at most k, best first. Entries written, replaced, or deleted
+// since the last search are re-embedded or dropped first.
+func (m *Manager) Search(ctx context.Context, query string, k int) ([]Match, error) {
+	if k < 1 {
+		return nil, fmt.Errorf("search: k must be at least 1")
+	}
+
+	m.mu.RLock()
+	index := m.vectors
+	current := []indexed{}
+	for key, entry := range m.partitions[PartitionDurable].Entries {
+		if entry.Namespace == m.namespace {
+			current = append(current, indexed{key: key, id: entry.ID, content: entry.Content, contentHash: entry.ContentHash})
+		}
+	}
+	m.mu.RUnlock()
+	if index == nil {
+		return nil, fmt.Errorf("search: no vector index; set an embedder first")
+	}
+
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	if err := index.syncLocked(ctx, m.namespace, current); err != nil {
+		return nil, err
+	}
+	probe, err := index.embedder.Embed(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("search: embed query: %w", err)
+	}
+
+	matches := make([]Match, 0, len(current))
+	for _, entry := range current {
+		stored := index.vectors[entry.key]
+		if len(stored.values) != len(probe) {
+			return nil, fmt.Errorf("search: entry %s has %d dimensions, query has %d", entry.id, len(stored.values), len(probe))
+		}
+		matches = append(matches, Match{ID: entry.id, Score: cosine(probe, stored.values)})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	if len(matches) > k {
+		matches = matches[:k]
+	}
+	return matches, nil
+}
+
+// syncLocked embeds entries whose content is new to the index and drops
+// the namespace's embeddings of entries no longer present
+func (v *vectorIndex) syncLocked(ctx context.Context, namespace string, current []indexed) error {
+	present := make(map[string]bool, len(current))
+	for _, entry := range current {
+		present[entry.key] = true
+		if stored, ok := v.vectors[entry.key]; ok && stored.contentHash == entry.contentHash {
+			continue
+		}
+		values, err := v.embedder.Embed(ctx, entry.content)
+		if err != nil {
+			return fmt.Errorf("search: embed entry %s: %w", entry.id, err)
+		}
+		v.vectors[entry.key] = &vector{namespace: namespace, id: entry.id, contentHash: entry.contentHash, values: values}
+	}
+	for key, stored := range v.vectors {
+		if stored.namespace == namespace && !present[key] {
+			delete(v.vectors, key)
+		}
+	}
+	return nil
+}
+
+// cosine returns the cosine similarity of two vectors of equal length; a
+// zero vector is similar to nothing
+func cosine(a []float64, b []float64) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
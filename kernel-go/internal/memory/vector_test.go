@@ -0,0 +1,113 @@
+// WHY: These tests prove the vector index ranks a namespace's durable
+// entries by meaning, keeps up with writes and deletions without
+// re-embedding unchanged content, and never answers across namespaces.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// wordEmbedder embeds text as counts of a fixed vocabulary
+type wordEmbedder struct {
+	vocabulary []string
+	calls      int
+	fail       bool
+}
+
+func (w *wordEmbedder) Embed(ctx context.Context, text string) ([]float64, error) {
+	w.calls++
+	if w.fail {
+		return nil, fmt.Errorf("model offline")
+	}
+	values := make([]float64, len(w.vocabulary))
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		for i, known := range w.vocabulary {
+			if word == known {
+				values[i]++
+			}
+		}
+	}
+	return values, nil
+}
+
+// newWordEmbedder returns an embedder over a small vocabulary
+func newWordEmbedder() *wordEmbedder {
+	return &wordEmbedder{vocabulary: []string{"dentist", "appointment", "tuesday", "allergic", "peanuts", "flight"}}
+}
+
+// TestSearchRanksDurableEntries proves the closest entries come first and
+// only durable entries of the view's namespace are searched
+func TestSearchRanksDurableEntries(t *testing.T) {
+	manager := NewManager()
+	manager.SetEmbedder(newWordEmbedder())
+	manager.Write(PartitionDurable, "health", "allergic to peanuts", nil)
+	manager.Write(PartitionDurable, "dentist", "dentist appointment on tuesday", nil)
+	manager.Write(PartitionDurable, "travel", "flight on tuesday", nil)
+	manager.Write(PartitionEphemeral, "scratch", "dentist dentist dentist", nil)
+	manager.Namespace("work").Write(PartitionDurable, "dentist", "dentist appointment", nil)
+
+	matches, err := manager.Search(context.Background(), "is the dentist appointment on tuesday", 2)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(matches) != 2 || matches[0].ID != "dentist" || matches[1].ID != "travel" {
+		t.Fatalf("expected dentist then travel, got %+v", matches)
+	}
+	if matches[0].Score <= matches[1].Score {
+		t.Fatalf("matches must be ordered best first, got %+v", matches)
+	}
+
+	work, _ := manager.Namespace("work").Search(context.Background(), "dentist", 5)
+	if len(work) != 1 || work[0].ID != "dentist" {
+		t.Fatalf("a namespace must only search its own entries, got %+v", work)
+	}
+}
+
+// TestSearchFollowsWritesAndDeletions proves only new or changed content
+// is embedded and deleted entries stop matching
+func TestSearchFollowsWritesAndDeletions(t *testing.T) {
+	embedder := newWordEmbedder()
+	manager := NewManager()
+	manager.SetEmbedder(embedder)
+	manager.Write(PartitionDurable, "a", "dentist appointment", nil)
+	manager.Write(PartitionDurable, "b", "allergic to peanuts", nil)
+
+	manager.Search(context.Background(), "dentist", 5)
+	if embedder.calls != 3 {
+		t.Fatalf("two entries and the query must be embedded, got %d calls", embedder.calls)
+	}
+	manager.Search(context.Background(), "dentist", 5)
+	if embedder.calls != 4 {
+		t.Fatalf("unchanged entries must not be embedded again, got %d calls", embedder.calls)
+	}
+
+	manager.Write(PartitionDurable, "b", "flight on tuesday", nil)
+	manager.Delete(PartitionDurable, "a")
+	matches, _ := manager.Search(context.Background(), "flight", 5)
+	if embedder.calls != 6 || len(matches) != 1 || matches[0].ID != "b" || matches[0].Score == 0 {
+		t.Fatalf("replaced entries must be re-embedded and deleted ones dropped, got %+v after %d calls", matches, embedder.calls)
+	}
+}
+
+// TestSearchNeedsAWorkingEmbedder proves search fails without an index or
+// when embedding fails, rather than returning partial results
+func TestSearchNeedsAWorkingEmbedder(t *testing.T) {
+	manager := NewManager()
+	manager.Write(PartitionDurable, "a", "dentist", nil)
+	if _, err := manager.Search(context.Background(), "dentist", 1); err == nil {
+		t.Fatal("search without an embedder must fail")
+	}
+
+	embedder := newWordEmbedder()
+	embedder.fail = true
+	manager.SetEmbedder(embedder)
+	if _, err := manager.Search(context.Background(), "dentist", 1); err == nil || !strings.Contains(err.Error(), "model offline") {
+		t.Fatalf("an embedding failure must fail the search, got %v", err)
+	}
+	if _, err := manager.Search(context.Background(), "dentist", 0); err == nil {
+		t.Fatal("k below one must be refused")
+	}
+}
@@ -0,0 +1,244 @@
+// WHY: A free-form string was enough to promote quarantined content, so
+// any code path holding the manager could launder untrusted text into
+// durable memory. Promotion and rejection now take a signed
+// VerificationRecord bound to the entry and its content hash, checked
+// against a policy naming the principals, keys, and methods governance
+// accepts. Entries nobody verifies can be expired, and every outcome is
+// reported for the audit ledger.
+package memory
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/signing"
+)
+
+// Verdicts a verification record can carry
+const (
+	VerdictPromote = "promote"
+	VerdictReject  = "reject"
+)
+
+// Verification methods governance commonly accepts
+const (
+	MethodHumanReview     = "human_review"
+	MethodSourceSignature = "source_signature"
+	MethodRescan          = "rescan"
+)
+
+// Quarantine outcomes recorded under MetadataQuarantineStatus
+const (
+	MetadataQuarantineStatus = "quarantine_status"
+
+	QuarantinePromoted = "promoted"
+	QuarantineRejected = "rejected"
+	QuarantineExpired  = "expired"
+)
+
+// verificationDomain separates verification signatures from every other
+// signature a verifier key might make
+const verificationDomain = "oi-quarantine-verification-v1"
+
+// VerificationRecord is a verifier's signed verdict on one quarantined entry
+type VerificationRecord struct {
+	// EntryID and ContentHash bind the verdict to the content reviewed
+	EntryID     string
+	ContentHash string
+	Verdict     string
+
+	// Verifier is the principal who reviewed the entry, Method how, and
+	// Findings what they found
+	Verifier string
+	Method   string
+	Findings string
+
+	// VerifiedAt is when the verdict was reached (Unix seconds)
+	VerifiedAt int64
+
+	KeyID     string
+	Signature []byte
+}
+
+// SigningMessage is the canonical encoding the signature covers
+func (r *VerificationRecord) SigningMessage() []byte {
+	message, _ := json.Marshal([]string{
+		verificationDomain, r.EntryID, r.ContentHash, r.Verdict,
+		r.Verifier, r.Method, r.Findings, strconv.FormatInt(r.VerifiedAt, 10), r.KeyID,
+	})
+	return message
+}
+
+// Sign sets the record's key ID and signature, and its time if unset
+func (r *VerificationRecord) Sign(signer signing.Signer) error {
+	if r.VerifiedAt == 0 {
+		r.VerifiedAt = time.Now().Unix()
+	}
+	r.KeyID = signer.KeyID()
+	signature, err := signer.Sign(r.SigningMessage())
+	if err != nil {
+		return fmt.Errorf("sign verification: %w", err)
+	}
+	r.Signature = signature
+	return nil
+}
+
+// metadata is what the record leaves on the entries it closes. WHY:
+// Findings stay with the entry, not in the ledger, since they may quote
+// the content.
+func (r *VerificationRecord) metadata() map[string]interface{} {
+	return map[string]interface{}{
+		"verified_by":            r.Verifier,
+		"verification_method":    r.Method,
+		"verification_findings":  r.Findings,
+		"verification_key_id":    r.KeyID,
+		"verification_signature": hex.EncodeToString(r.Signature),
+		"verified_at":            r.VerifiedAt,
+	}
+}
+
+// VerificationPolicy is who may close quarantined entries and how
+type VerificationPolicy struct {
+	// Verifiers maps each accepted principal to the key ID it signs with
+	Verifiers map[string]string
+
+	// Methods lists the accepted verification methods
+	Methods []string
+
+	// Keys holds the verifiers' public keys
+	Keys *signing.KeyRing
+}
+
+// Check verifies a record's verifier, method, and signature
+func (p VerificationPolicy) Check(record VerificationRecord) error {
+	keyID, ok := p.Verifiers[record.Verifier]
+	if !ok || record.Verifier == "" {
+		return fmt.Errorf("verifier %q is not accepted", record.Verifier)
+	}
+	if keyID != record.KeyID {
+		return fmt.Errorf("verifier %s signs with key %s, not %s", record.Verifier, keyID, record.KeyID)
+	}
+	accepted := false
+	for _, method := range p.Methods {
+		accepted = accepted || method == record.Method
+	}
+	if !accepted {
+		return fmt.Errorf("verification method %q is not accepted", record.Method)
+	}
+	if p.Keys == nil {
+		return fmt.Errorf("no verifier keys")
+	}
+	if err := p.Keys.Verify(record.KeyID, record.SigningMessage(), record.Signature); err != nil {
+		return fmt.Errorf("verification record: %w", err)
+	}
+	return nil
+}
+
+// SetVerificationPolicy registers where the current verification policy
+// is read from. WHY: The policy lives in governance and can change, so it
+// is read at each promotion rather than copied in once.
+func (m *Manager) SetVerificationPolicy(policy func() VerificationPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.verificationPolicy = policy
+}
+
+// QuarantinedHash returns the content hash of a quarantined entry, which
+// is what a verifier signs. WHY: The hash is already in the ledger, so
+// exposing it opens nothing; the content stays write-only.
+func (m *Manager) QuarantinedHash(id string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entry, exists := m.partitions[PartitionQuarantine].Entries[m.key(id)]
+	if !exists {
+		return "", fmt.Errorf("entry %s not found in quarantine", id)
+	}
+	return entry.ContentHash, nil
+}
+
+// checkVerificationLocked finds an open quarantined entry and checks a
+// record closing it with verdict; m.mu is held
+func (m *Manager) checkVerificationLocked(id string, verdict string, record VerificationRecord) (*Entry, error) {
+	entry, exists := m.partitions[PartitionQuarantine].Entries[m.key(id)]
+	if !exists {
+		return nil, fmt.Errorf("entry %s not found in quarantine", id)
+	}
+	if status, closed := entry.Metadata[MetadataQuarantineStatus]; closed {
+		return nil, fmt.Errorf("quarantined entry %s is already %v", id, status)
+	}
+	if m.verificationPolicy == nil {
+		return nil, fmt.Errorf("no verification policy; quarantined content cannot leave quarantine")
+	}
+	if record.EntryID != id || record.ContentHash != entry.ContentHash || record.Verdict != verdict {
+		return nil, fmt.Errorf("verification record is for %s %s (%s), not this entry", record.EntryID, record.ContentHash, record.Verdict)
+	}
+	if err := m.verificationPolicy().Check(record); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// RejectQuarantined closes a quarantined entry as rejected; it can never
+// be promoted
+func (m *Manager) RejectQuarantined(id string, record VerificationRecord) error {
+	entry, err := m.reject(id, record)
+	if err != nil {
+		return err
+	}
+	m.notify(Operation{Action: OpReject, Partition: PartitionQuarantine, ID: id, ContentHash: entry.ContentHash,
+		Verifier: record.Verifier, Method: record.Method})
+	return nil
+}
+
+// reject is RejectQuarantined without the notification
+func (m *Manager) reject(id string, record VerificationRecord) (*Entry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, err := m.checkVerificationLocked(id, VerdictReject, record)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range record.metadata() {
+		entry.Metadata[key] = value
+	}
+	entry.Metadata[MetadataQuarantineStatus] = QuarantineRejected
+	return entry, nil
+}
+
+// ExpireQuarantine closes every open quarantined entry in this namespace
+// older than maxAge as expired and returns their IDs.
+// WHY: Content nobody verified in time is stale evidence of what arrived,
+// not a candidate for memory; quarantine is append-only, so the entries
+// stay but can never be promoted.
+func (m *Manager) ExpireQuarantine(maxAge time.Duration) []string {
+	expired := m.expire(maxAge)
+	ids := make([]string, len(expired))
+	for i, entry := range expired {
+		ids[i] = entry.ID
+		m.notify(Operation{Action: OpExpire, Partition: PartitionQuarantine, ID: entry.ID, ContentHash: entry.ContentHash})
+	}
+	return ids
+}
+
+// expire is ExpireQuarantine without the notifications
+func (m *Manager) expire(maxAge time.Duration) []*Entry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cutoff := m.clock.Now().Add(-maxAge).Unix()
+	expired := []*Entry{}
+	for _, entry := range m.partitions[PartitionQuarantine].Entries {
+		if _, closed := entry.Metadata[MetadataQuarantineStatus]; closed || entry.Namespace != m.namespace || entry.Timestamp >= cutoff {
+			continue
+		}
+		entry.Metadata[MetadataQuarantineStatus] = QuarantineExpired
+		expired = append(expired, entry)
+	}
+	sortEntries(expired)
+	return expired
+}
@@ -0,0 +1,133 @@
+// WHY: One place names every corridor metric, so dashboards and alerts
+// have a stable contract and the pipeline only calls typed fields.
+package metrics
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Kernel holds the corridor's metric families
+type Kernel struct {
+	Registry *Registry
+
+	// Decisions counts CDI input decisions by outcome (ALLOW, DENY, DEGRADE)
+	Decisions *CounterVec
+	// Denials counts DENY decisions by stage (input, output) and reason
+	Denials *CounterVec
+	// AdapterLatency times adapter invocations by adapter and acceptance
+	AdapterLatency *HistogramVec
+	// AdapterThrottles counts calls refused by adapter rate limits by adapter and reason
+	AdapterThrottles *CounterVec
+	// AdapterTimeouts counts adapter calls abandoned at their deadline by adapter
+	AdapterTimeouts *CounterVec
+	// TokensMinted counts minted capability tokens by governance template
+	TokensMinted *CounterVec
+	// TokensRevoked counts tokens revoked by STOP
+	TokensRevoked *CounterVec
+	// StopLatency times a STOP from its invocation until its last
+	// cancelled call returned, by scope
+	StopLatency *HistogramVec
+	// LeakBudgetConsumed counts egress bytes charged against leak budgets
+	LeakBudgetConsumed *CounterVec
+	// LedgerVerifyFailures counts failed audit ledger verifications
+	LedgerVerifyFailures *CounterVec
+
+	// The adapter registry's per-adapter stats, read at scrape time:
+	// calls by outcome (success, error, timeout, refused), total and
+	// slowest latency, and usage tokens by kind and cost
+	AdapterCalls        *CollectedVec
+	AdapterLatencyTotal *CollectedVec
+	AdapterMaxLatency   *CollectedVec
+	AdapterTokens       *CollectedVec
+	AdapterCost         *CollectedVec
+
+	mu           sync.Mutex
+	adapterStats func() []AdapterSample
+}
+
+// AdapterSample is one adapter's cumulative stats
+type AdapterSample struct {
+	Adapter          string
+	Successes        int64
+	Errors           int64 // including timeouts
+	Timeouts         int64
+	Refused          int64
+	TotalLatency     time.Duration
+	MaxLatency       time.Duration
+	PromptTokens     int64
+	CompletionTokens int64
+	Cost             float64
+}
+
+// NewKernel registers the corridor metric families on a fresh registry
+func NewKernel() *Kernel {
+	r := NewRegistry()
+	k := &Kernel{
+		Registry:             r,
+		Decisions:            r.NewCounterVec("oi_cdi_decisions_total", "CDI input decisions by outcome.", "outcome"),
+		Denials:              r.NewCounterVec("oi_cdi_denials_total", "CDI denials by stage and reason.", "stage", "reason"),
+		AdapterLatency:       r.NewHistogramVec("oi_adapter_call_duration_seconds", "Adapter invocation latency.", DefaultBuckets, "adapter", "accepted"),
+		AdapterThrottles:     r.NewCounterVec("oi_adapter_throttles_total", "Adapter calls refused by rate limits.", "adapter", "reason"),
+		AdapterTimeouts:      r.NewCounterVec("oi_adapter_timeouts_total", "Adapter calls abandoned at their deadline.", "adapter"),
+		TokensMinted:         r.NewCounterVec("oi_tokens_minted_total", "Capability tokens minted by template.", "template"),
+		TokensRevoked:        r.NewCounterVec("oi_tokens_revoked_total", "Capability tokens revoked by STOP."),
+		StopLatency:          r.NewHistogramVec("oi_stop_latency_seconds", "Time from STOP until its last cancelled call returned.", DefaultBuckets, "scope"),
+		LeakBudgetConsumed:   r.NewCounterVec("oi_leak_budget_consumed_bytes_total", "Egress bytes charged against leak budgets."),
+		LedgerVerifyFailures: r.NewCounterVec("oi_ledger_verify_failures_total", "Failed audit ledger verifications."),
+	}
+	k.AdapterCalls = r.NewCounterFunc("oi_adapter_calls_total", "Adapter calls by outcome.", k.adapterSamples(func(s AdapterSample) []Sample {
+		return []Sample{
+			{[]string{s.Adapter, "success"}, float64(s.Successes)},
+			{[]string{s.Adapter, "error"}, float64(s.Errors - s.Timeouts)},
+			{[]string{s.Adapter, "timeout"}, float64(s.Timeouts)},
+			{[]string{s.Adapter, "refused"}, float64(s.Refused)},
+		}
+	}), "adapter", "outcome")
+	k.AdapterLatencyTotal = r.NewCounterFunc("oi_adapter_latency_seconds_total", "Total latency of adapter calls.", k.adapterSamples(func(s AdapterSample) []Sample {
+		return []Sample{{[]string{s.Adapter}, s.TotalLatency.Seconds()}}
+	}), "adapter")
+	k.AdapterMaxLatency = r.NewGaugeFunc("oi_adapter_max_latency_seconds", "Slowest adapter call.", k.adapterSamples(func(s AdapterSample) []Sample {
+		return []Sample{{[]string{s.Adapter}, s.MaxLatency.Seconds()}}
+	}), "adapter")
+	k.AdapterTokens = r.NewCounterFunc("oi_adapter_usage_tokens_total", "Tokens adapters reported consuming by kind.", k.adapterSamples(func(s AdapterSample) []Sample {
+		return []Sample{
+			{[]string{s.Adapter, "prompt"}, float64(s.PromptTokens)},
+			{[]string{s.Adapter, "completion"}, float64(s.CompletionTokens)},
+		}
+	}), "adapter", "kind")
+	k.AdapterCost = r.NewCounterFunc("oi_adapter_cost_total", "Cost adapters reported, in each provider's currency.", k.adapterSamples(func(s AdapterSample) []Sample {
+		return []Sample{{[]string{s.Adapter}, s.Cost}}
+	}), "adapter")
+	return k
+}
+
+// SetAdapterStats sets where the adapter families read their stats
+func (k *Kernel) SetAdapterStats(fn func() []AdapterSample) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.adapterStats = fn
+}
+
+// adapterSamples returns a collector expanding each adapter's stats with series
+func (k *Kernel) adapterSamples(series func(AdapterSample) []Sample) func() []Sample {
+	return func() []Sample {
+		k.mu.Lock()
+		fn := k.adapterStats
+		k.mu.Unlock()
+		if fn == nil {
+			return nil
+		}
+		var samples []Sample
+		for _, stats := range fn() {
+			samples = append(samples, series(stats)...)
+		}
+		return samples
+	}
+}
+
+// Handler serves the kernel metrics for a /metrics endpoint
+func (k *Kernel) Handler() http.Handler {
+	return k.Registry.Handler()
+}
@@ -0,0 +1,311 @@
+// WHY: Operators watch dashboards, not ledgers. Counters and histograms in
+// the Prometheus text format let existing monitoring see decision rates,
+// denials, adapter latency, and ledger health. This is a deliberately small
+// stdlib implementation so the kernel stays dependency-free; metrics never
+// carry user content, only mechanics.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are latency buckets in seconds
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// collector writes one metric family in text exposition format
+type collector interface {
+	write(w *bufio.Writer)
+}
+
+// Registry holds metric families in registration order
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+	names      map[string]bool
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+func (r *Registry) register(name string, c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.names[name] {
+		panic(fmt.Sprintf("metrics: %s registered twice", name))
+	}
+	r.names[name] = true
+	r.collectors = append(r.collectors, c)
+}
+
+// WriteText writes every metric in Prometheus text format 0.0.4
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	buf := bufio.NewWriter(w)
+	for _, c := range collectors {
+		c.write(buf)
+	}
+	return buf.Flush()
+}
+
+// Handler serves the registry for Prometheus scrapes
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteText(w)
+	})
+}
+
+// CounterVec is a monotonically increasing counter partitioned by labels
+type CounterVec struct {
+	name   string
+	help   string
+	labels []string
+
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewCounterVec registers a counter family; labels may be empty
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{name: name, help: help, labels: labels, values: make(map[string]float64)}
+	r.register(name, c)
+	return c
+}
+
+// Add increases the counter for the given label values. Negative deltas
+// are ignored: counters never go down.
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		return
+	}
+	key := labelKey(c.labels, labelValues)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] += delta
+}
+
+// Inc adds one
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Value returns the current count for the given label values
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	key := labelKey(c.labels, labelValues)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[key]
+}
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	writeHeader(w, c.name, c.help, "counter")
+	for _, key := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, key, formatFloat(c.values[key]))
+	}
+}
+
+// Sample is one series read by a collected family
+type Sample struct {
+	LabelValues []string
+	Value       float64
+}
+
+// CollectedVec is a family whose values are read from their owner at
+// scrape time.
+// WHY: When a component already keeps totals, copying them into a second
+// set of counters would let the two drift.
+type CollectedVec struct {
+	name    string
+	help    string
+	kind    string
+	labels  []string
+	collect func() []Sample
+}
+
+// NewCounterFunc registers a counter family read by collect at scrape
+// time; collect must return values that never go down
+func (r *Registry) NewCounterFunc(name, help string, collect func() []Sample, labels ...string) *CollectedVec {
+	c := &CollectedVec{name: name, help: help, kind: "counter", labels: labels, collect: collect}
+	r.register(name, c)
+	return c
+}
+
+// NewGaugeFunc registers a gauge family read by collect at scrape time
+func (r *Registry) NewGaugeFunc(name, help string, collect func() []Sample, labels ...string) *CollectedVec {
+	c := &CollectedVec{name: name, help: help, kind: "gauge", labels: labels, collect: collect}
+	r.register(name, c)
+	return c
+}
+
+func (c *CollectedVec) write(w *bufio.Writer) {
+	values := make(map[string]float64)
+	for _, sample := range c.collect() {
+		values[labelKey(c.labels, sample.LabelValues)] += sample.Value
+	}
+
+	writeHeader(w, c.name, c.help, c.kind)
+	for _, key := range sortedKeys(values) {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, key, formatFloat(values[key]))
+	}
+}
+
+// HistogramVec observes value distributions partitioned by labels
+type HistogramVec struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*histogram
+}
+
+type histogram struct {
+	labelValues []string
+	counts      []uint64 // per bucket, not cumulative
+	count       uint64
+	sum         float64
+}
+
+// NewHistogramVec registers a histogram family with ascending bucket bounds
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	h := &HistogramVec{name: name, help: help, labels: labels, buckets: sorted, series: make(map[string]*histogram)}
+	r.register(name, h)
+	return h
+}
+
+// Observe records one value for the given label values
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	key := labelKey(h.labels, labelValues)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogram{labelValues: padLabels(h.labels, labelValues), counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			s.counts[i]++
+			break
+		}
+	}
+	s.count++
+	s.sum += value
+}
+
+// Count returns how many values were observed for the given label values
+func (h *HistogramVec) Count(labelValues ...string) uint64 {
+	key := labelKey(h.labels, labelValues)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s, ok := h.series[key]; ok {
+		return s.count
+	}
+	return 0
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	writeHeader(w, h.name, h.help, "histogram")
+	keys := make([]string, 0, len(h.series))
+	for key := range h.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := h.series[key]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, bucketLabels(h.labels, s.labelValues, formatFloat(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, bucketLabels(h.labels, s.labelValues, "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, key, formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, key, s.count)
+	}
+}
+
+// bucketLabels renders series labels plus the "le" bucket bound
+func bucketLabels(names, values []string, bound string) string {
+	allNames := append(append([]string(nil), names...), "le")
+	allValues := append(append([]string(nil), values...), bound)
+	return formatLabels(allNames, allValues)
+}
+
+func writeHeader(w *bufio.Writer, name, help, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, strings.ReplaceAll(strings.ReplaceAll(help, `\`, `\\`), "\n", `\n`))
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, kind)
+}
+
+// labelKey renders label pairs; it doubles as the series key
+func labelKey(names, values []string) string {
+	return formatLabels(names, padLabels(names, values))
+}
+
+// padLabels fits values to the declared label names; missing values are empty
+func padLabels(names, values []string) []string {
+	padded := make([]string, len(names))
+	copy(padded, values)
+	return padded
+}
+
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + `="` + escapeLabel(values[i]) + `"`
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func escapeLabel(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return strings.ReplaceAll(value, "\n", `\n`)
+}
+
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func sortedKeys(values map[string]float64) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
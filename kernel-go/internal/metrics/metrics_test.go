@@ -0,0 +1,140 @@
+// WHY: The exposition text is the contract with Prometheus; these tests pin it.
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCounterTextFormat proves counters render with HELP, TYPE, and sorted labeled series
+func TestCounterTextFormat(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounterVec("oi_test_total", "Test counter.", "outcome")
+	c.Inc("DENY")
+	c.Add(2, "ALLOW")
+	c.Add(-5, "ALLOW") // ignored
+
+	var out strings.Builder
+	if err := r.WriteText(&out); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	want := "# HELP oi_test_total Test counter.\n" +
+		"# TYPE oi_test_total counter\n" +
+		"oi_test_total{outcome=\"ALLOW\"} 2\n" +
+		"oi_test_total{outcome=\"DENY\"} 1\n"
+	if out.String() != want {
+		t.Fatalf("unexpected exposition:\n%s", out.String())
+	}
+}
+
+// TestHistogramBucketsAreCumulative proves bucket counts accumulate and end in +Inf
+func TestHistogramBucketsAreCumulative(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogramVec("oi_latency_seconds", "Latency.", []float64{1, 0.1}, "adapter")
+	h.Observe(0.05, "a")
+	h.Observe(0.5, "a")
+	h.Observe(5, "a")
+
+	var out strings.Builder
+	r.WriteText(&out)
+	for _, line := range []string{
+		`oi_latency_seconds_bucket{adapter="a",le="0.1"} 1`,
+		`oi_latency_seconds_bucket{adapter="a",le="1"} 2`,
+		`oi_latency_seconds_bucket{adapter="a",le="+Inf"} 3`,
+		`oi_latency_seconds_sum{adapter="a"} 5.55`,
+		`oi_latency_seconds_count{adapter="a"} 3`,
+	} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Fatalf("missing %q in:\n%s", line, out.String())
+		}
+	}
+	if h.Count("a") != 3 || h.Count("b") != 0 {
+		t.Fatal("histogram counts wrong")
+	}
+}
+
+// TestLabelValuesAreEscaped proves quotes, backslashes, and newlines cannot break a series line
+func TestLabelValuesAreEscaped(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounterVec("oi_escape_total", "Escapes.", "reason").Inc("a\"b\\c\nd")
+
+	var out strings.Builder
+	r.WriteText(&out)
+	if !strings.Contains(out.String(), `oi_escape_total{reason="a\"b\\c\nd"} 1`) {
+		t.Fatalf("label not escaped:\n%s", out.String())
+	}
+}
+
+// TestKernelHandlerServesMetrics proves the /metrics handler exposes every kernel family
+func TestKernelHandlerServesMetrics(t *testing.T) {
+	k := NewKernel()
+	k.Decisions.Inc("ALLOW")
+
+	rec := httptest.NewRecorder()
+	k.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Fatalf("unexpected content type %q", rec.Header().Get("Content-Type"))
+	}
+	body := rec.Body.String()
+	for _, name := range []string{
+		"oi_cdi_decisions_total", "oi_cdi_denials_total", "oi_adapter_call_duration_seconds",
+		"oi_tokens_minted_total", "oi_tokens_revoked_total",
+		"oi_leak_budget_consumed_bytes_total", "oi_ledger_verify_failures_total",
+	} {
+		if !strings.Contains(body, "# TYPE "+name+" ") {
+			t.Fatalf("missing metric family %s", name)
+		}
+	}
+	if !strings.Contains(body, `oi_cdi_decisions_total{outcome="ALLOW"} 1`) {
+		t.Fatal("decision count not exposed")
+	}
+}
+
+// TestDuplicateRegistrationPanics proves two families cannot share a name
+func TestDuplicateRegistrationPanics(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounterVec("oi_dup_total", "Dup.")
+	defer func() {
+		if recover() == nil {
+			t.Fatal("duplicate registration should panic")
+		}
+	}()
+	r.NewCounterVec("oi_dup_total", "Dup.")
+}
+
+// TestAdapterStatsAreReadAtScrapeTime proves the adapter families expose
+// whatever the stats source holds when scraped, with timeouts split out
+// of errors
+func TestAdapterStatsAreReadAtScrapeTime(t *testing.T) {
+	k := NewKernel()
+	var out strings.Builder
+	k.Registry.WriteText(&out)
+	if !strings.Contains(out.String(), "# TYPE oi_adapter_calls_total counter\n") || strings.Contains(out.String(), "oi_adapter_calls_total{") {
+		t.Fatalf("families must exist with no series before a source is set:\n%s", out.String())
+	}
+
+	k.SetAdapterStats(func() []AdapterSample {
+		return []AdapterSample{{Adapter: "model", Successes: 3, Errors: 2, Timeouts: 1, Refused: 4,
+			TotalLatency: 1500 * time.Millisecond, MaxLatency: time.Second, PromptTokens: 7, Cost: 0.5}}
+	})
+	out.Reset()
+	k.Registry.WriteText(&out)
+	for _, line := range []string{
+		`oi_adapter_calls_total{adapter="model",outcome="success"} 3`,
+		`oi_adapter_calls_total{adapter="model",outcome="error"} 1`,
+		`oi_adapter_calls_total{adapter="model",outcome="timeout"} 1`,
+		`oi_adapter_calls_total{adapter="model",outcome="refused"} 4`,
+		`oi_adapter_latency_seconds_total{adapter="model"} 1.5`,
+		`# TYPE oi_adapter_max_latency_seconds gauge`,
+		`oi_adapter_max_latency_seconds{adapter="model"} 1`,
+		`oi_adapter_usage_tokens_total{adapter="model",kind="prompt"} 7`,
+		`oi_adapter_cost_total{adapter="model"} 0.5`,
+	} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Fatalf("missing %q in:\n%s", line, out.String())
+		}
+	}
+}
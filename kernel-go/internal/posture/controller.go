@@ -0,0 +1,180 @@
+// WHY: A posture nobody changes is a posture that never tightens when the
+// system is under pressure. The controller counts signals (tainted
+// inputs, DENY decisions, integrity loss, ledger verification failures)
+// and raises the posture when a rule's threshold is reached within its
+// window. It only ever raises: relaxing a posture is a human decision.
+package posture
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/clock"
+)
+
+// Signal kinds a Controller counts
+const (
+	SignalTaint         = "taint"                 // CIF labeled an input tainted
+	SignalDeny          = "deny"                  // CDI denied a request or its output
+	SignalIntegrity     = "integrity_lost"        // integrity left INTEGRITY_OK
+	SignalLedgerFailure = "ledger_verify_failure" // the audit ledger failed verification
+)
+
+// Signal is one observation a Controller counts
+type Signal struct {
+	Kind string
+
+	// Detail says what raised the signal, e.g. the DENY reason
+	Detail string
+}
+
+// Rule raises the posture to Level once Threshold signals of Kind arrive
+// within Window; a zero Window counts every signal observed
+type Rule struct {
+	Name      string
+	Kind      string
+	Threshold int
+	Window    time.Duration
+	Level     int
+}
+
+// DefaultRules returns the escalation rules a kernel starts with
+func DefaultRules() []Rule {
+	return []Rule{
+		{Name: "taint_rate", Kind: SignalTaint, Threshold: 5, Window: 10 * time.Minute, Level: P2},
+		{Name: "deny_rate", Kind: SignalDeny, Threshold: 10, Window: 10 * time.Minute, Level: P3},
+		{Name: "integrity_lost", Kind: SignalIntegrity, Threshold: 1, Level: P3},
+		{Name: "ledger_verify_failure", Kind: SignalLedgerFailure, Threshold: 1, Level: P4},
+	}
+}
+
+// Controller raises a State's posture as signals arrive
+type Controller struct {
+	mu    sync.Mutex
+	state *State
+	rules []Rule
+	clock clock.Clock
+
+	// seen holds, per kind, the times of the latest signals; no more are
+	// kept than the largest threshold for that kind
+	seen map[string][]time.Time
+
+	// onTransition, if set, is told of every transition the controller makes
+	onTransition func(Transition)
+}
+
+// NewController returns a controller raising state under rules
+func NewController(state *State, rules []Rule) (*Controller, error) {
+	c := &Controller{state: state, clock: clock.Real{}, seen: make(map[string][]time.Time)}
+	if err := c.SetRules(rules); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// SetRules replaces the controller's rules; signals already seen still count
+func (c *Controller) SetRules(rules []Rule) error {
+	for _, rule := range rules {
+		if rule.Kind == "" || rule.Threshold < 1 || rule.Window < 0 {
+			return fmt.Errorf("posture rule %s: needs a kind, a positive threshold, and a non-negative window", rule.Name)
+		}
+		if !IsValid(rule.Level) {
+			return fmt.Errorf("posture rule %s: posture %d is not defined", rule.Name, rule.Level)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rules = append([]Rule(nil), rules...)
+	return nil
+}
+
+// SetClock sets the clock signals are timed by
+func (c *Controller) SetClock(clk clock.Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock = clk
+}
+
+// OnTransition registers fn to receive every transition the controller
+// makes; fn is called after the controller's lock is released
+func (c *Controller) OnTransition(fn func(Transition)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onTransition = fn
+}
+
+// Level returns the posture the controller holds
+func (c *Controller) Level() int {
+	return c.state.Level()
+}
+
+// Observe counts signal and raises the posture to the highest level of
+// any rule it fires. It returns the transition, if one was made.
+func (c *Controller) Observe(signal Signal) (Transition, bool) {
+	level, reason, fired := c.observe(signal)
+	if !fired {
+		return Transition{}, false
+	}
+	transition, raised := c.state.Raise(level, reason)
+	if raised {
+		c.mu.Lock()
+		fn := c.onTransition
+		c.mu.Unlock()
+		if fn != nil {
+			fn(transition)
+		}
+	}
+	return transition, raised
+}
+
+// observe counts signal and returns the level and reason of the highest
+// rule it fires, if any
+func (c *Controller) observe(signal Signal) (int, string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	keep := 0
+	for _, rule := range c.rules {
+		if rule.Kind == signal.Kind && rule.Threshold > keep {
+			keep = rule.Threshold
+		}
+	}
+	if keep == 0 {
+		return 0, "", false
+	}
+	seen := append(c.seen[signal.Kind], now)
+	if len(seen) > keep {
+		seen = seen[len(seen)-keep:]
+	}
+	c.seen[signal.Kind] = seen
+
+	var fired *Rule
+	for i, rule := range c.rules {
+		if rule.Kind != signal.Kind || len(seen) < rule.Threshold {
+			continue
+		}
+		// WHY: The rule fires if its Threshold-th latest signal is still
+		// inside the window
+		if rule.Window > 0 && now.Sub(seen[len(seen)-rule.Threshold]) > rule.Window {
+			continue
+		}
+		if fired == nil || rule.Level > fired.Level {
+			fired = &c.rules[i]
+		}
+	}
+	if fired == nil {
+		return 0, "", false
+	}
+
+	reason := fmt.Sprintf("rule %s: %d %s signals", fired.Name, fired.Threshold, fired.Kind)
+	if fired.Window > 0 {
+		reason += fmt.Sprintf(" within %s", fired.Window)
+	}
+	if signal.Detail != "" {
+		reason += fmt.Sprintf(" (last: %s)", signal.Detail)
+	}
+	return fired.Level, reason, true
+}
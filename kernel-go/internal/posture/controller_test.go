@@ -0,0 +1,76 @@
+// WHY: These tests prove the controller raises posture only when a rule's
+// threshold is reached inside its window, never lowers it, and reports
+// each transition it makes.
+package posture
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/clock"
+)
+
+// TestControllerRaisesOnThresholdWithinWindow proves spaced-out signals do
+// not fire a rule, a burst does, and the highest fired rule wins
+func TestControllerRaisesOnThresholdWithinWindow(t *testing.T) {
+	now := clock.NewFake(time.Unix(1_700_000_000, 0))
+	state := NewState()
+	state.SetClock(now)
+	controller, err := NewController(state, []Rule{
+		{Name: "taint_burst", Kind: SignalTaint, Threshold: 3, Window: time.Minute, Level: P2},
+		{Name: "taint_flood", Kind: SignalTaint, Threshold: 3, Window: 10 * time.Second, Level: P3},
+	})
+	if err != nil {
+		t.Fatalf("new controller: %v", err)
+	}
+	controller.SetClock(now)
+	var reported []Transition
+	controller.OnTransition(func(transition Transition) { reported = append(reported, transition) })
+
+	for i := 0; i < 3; i++ {
+		if _, raised := controller.Observe(Signal{Kind: SignalTaint}); raised {
+			t.Fatal("signals spread past the window must not fire a rule")
+		}
+		now.Advance(2 * time.Minute)
+	}
+	controller.Observe(Signal{Kind: SignalDeny})
+	controller.Observe(Signal{Kind: SignalTaint})
+	controller.Observe(Signal{Kind: SignalTaint})
+	transition, raised := controller.Observe(Signal{Kind: SignalTaint, Detail: "instruction_smuggling_attempt"})
+	if !raised || transition.ToLevel != P3 || controller.Level() != P3 {
+		t.Fatalf("a burst inside both windows must raise to the higher rule, got %+v", transition)
+	}
+	if !strings.Contains(transition.Reason, "taint_flood") || !strings.Contains(transition.Reason, "instruction_smuggling_attempt") {
+		t.Fatalf("the transition must say which rule fired and why, got %q", transition.Reason)
+	}
+	if len(reported) != 1 || reported[0] != transition || len(state.History) != 1 {
+		t.Fatalf("the transition must be reported and logged once, got %+v", reported)
+	}
+}
+
+// TestControllerNeverLowersPosture proves a rule below the current posture
+// does nothing
+func TestControllerNeverLowersPosture(t *testing.T) {
+	state := NewState()
+	state.SetLevel(P4, "oracle mode")
+	controller, _ := NewController(state, DefaultRules())
+	if _, raised := controller.Observe(Signal{Kind: SignalIntegrity}); raised || controller.Level() != P4 {
+		t.Fatalf("the controller must not lower P4, got P%d", controller.Level())
+	}
+}
+
+// TestControllerRefusesMalformedRules proves a rule must name a kind, a
+// positive threshold, and a defined posture
+func TestControllerRefusesMalformedRules(t *testing.T) {
+	for name, rule := range map[string]Rule{
+		"no kind":           {Name: "r", Threshold: 1, Level: P2},
+		"zero threshold":    {Name: "r", Kind: SignalDeny, Level: P2},
+		"negative window":   {Name: "r", Kind: SignalDeny, Threshold: 1, Window: -time.Second, Level: P2},
+		"undefined posture": {Name: "r", Kind: SignalDeny, Threshold: 1, Level: P0},
+	} {
+		if _, err := NewController(NewState(), []Rule{rule}); err == nil {
+			t.Errorf("%s: must be refused", name)
+		}
+	}
+}
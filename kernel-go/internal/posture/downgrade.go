@@ -0,0 +1,134 @@
+// WHY: Raising the posture only adds constraint, so anything may do it.
+// Lowering it relaxes constraint, so it is never a bare assignment: a
+// Downgrade names who asked and why, DowngradeRules say how long a
+// posture must hold before it may be lowered and whether a second
+// principal must approve, and both travel with the transition into the
+// receipt. Only a caller undoing its own raises may lower without one.
+package posture
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultDowngradeCooldown is how long a posture holds before it may be
+// lowered, unless governance says otherwise
+const DefaultDowngradeCooldown = 15 * time.Minute
+
+// Downgrade asks for the posture to be lowered to Level
+type Downgrade struct {
+	Level  int
+	Reason string
+
+	// RequestedBy is the principal asking for the downgrade
+	RequestedBy string
+
+	// ApprovedBy is the second principal approving it, if the rules ask
+	// for one
+	ApprovedBy string
+}
+
+// DowngradeRules govern when the posture may be lowered
+type DowngradeRules struct {
+	// Cooldown is how long the posture must have held since its last
+	// transition
+	Cooldown time.Duration `json:"cooldown"`
+
+	// RequireApprover asks for a second principal, other than the one
+	// requesting, to approve every downgrade
+	RequireApprover bool `json:"require_approver"`
+}
+
+// DefaultDowngradeRules returns the downgrade rules a kernel starts with
+func DefaultDowngradeRules() DowngradeRules {
+	return DowngradeRules{Cooldown: DefaultDowngradeCooldown}
+}
+
+// Validate checks the cooldown is not negative
+func (r DowngradeRules) Validate() error {
+	if r.Cooldown < 0 {
+		return fmt.Errorf("posture downgrade rules: cooldown must not be negative")
+	}
+	return nil
+}
+
+// check reports why d may not lower the posture under r, if it may not
+func (r DowngradeRules) check(d Downgrade) error {
+	if !IsValid(d.Level) {
+		return fmt.Errorf("posture %d is not defined", d.Level)
+	}
+	if d.RequestedBy == "" || d.Reason == "" {
+		return fmt.Errorf("posture downgrade needs a requester and a reason")
+	}
+	if r.RequireApprover && (d.ApprovedBy == "" || d.ApprovedBy == d.RequestedBy) {
+		return fmt.Errorf("posture downgrade needs a second approver other than %s", d.RequestedBy)
+	}
+	return nil
+}
+
+// Lower lowers the posture as d asks, if rules allow it. The transition
+// records who asked and who approved.
+func (s *State) Lower(d Downgrade, rules DowngradeRules) (Transition, error) {
+	if err := rules.Validate(); err != nil {
+		return Transition{}, err
+	}
+	if err := rules.check(d); err != nil {
+		return Transition{}, err
+	}
+
+	s.mu.Lock()
+	if d.Level >= s.CurrentLevel {
+		s.mu.Unlock()
+		return Transition{}, fmt.Errorf("posture P%d is not below P%d", d.Level, s.CurrentLevel)
+	}
+	if len(s.History) > 0 {
+		last := s.History[len(s.History)-1]
+		held := time.Duration(s.now().Unix()-last.Timestamp) * time.Second
+		if held < rules.Cooldown {
+			s.mu.Unlock()
+			return Transition{}, fmt.Errorf("posture P%d has held %s of its %s cooldown", s.CurrentLevel, held, rules.Cooldown)
+		}
+	}
+	transition := s.recordLocked(Transition{
+		ToLevel: d.Level, Reason: d.Reason, RequestedBy: d.RequestedBy, ApprovedBy: d.ApprovedBy,
+	})
+	s.unlockAndReport(transition)
+	return transition, nil
+}
+
+// Revert lowers the posture to level, undoing own, if own are still the
+// latest transitions and level is no lower than the posture own started
+// from. WHY: Undoing one's own transitions relaxes nothing that held
+// before them, so it needs no downgrade.
+func (s *State) Revert(own []Transition, level int, reason string) (Transition, bool) {
+	s.mu.Lock()
+	if !s.latestLocked(own) || level < own[0].FromLevel || level >= s.CurrentLevel {
+		s.mu.Unlock()
+		return Transition{}, false
+	}
+	transition := s.recordLocked(Transition{ToLevel: level, Reason: reason})
+	s.unlockAndReport(transition)
+	return transition, true
+}
+
+// IsLatest reports whether transitions are the latest in the history, in
+// order
+func (s *State) IsLatest(transitions []Transition) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.latestLocked(transitions)
+}
+
+// latestLocked is IsLatest with s.mu held
+func (s *State) latestLocked(transitions []Transition) bool {
+	if len(transitions) == 0 || len(transitions) > len(s.History) {
+		return false
+	}
+	tail := s.History[len(s.History)-len(transitions):]
+	for i, transition := range transitions {
+		if tail[i] != transition {
+			return false
+		}
+	}
+	return true
+}
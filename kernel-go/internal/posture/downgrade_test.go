@@ -0,0 +1,76 @@
+// WHY: These tests prove the posture is only lowered by a downgrade that
+// names its requester, waits out the cooldown, and has its second
+// approver, and that a raise can be undone only while nothing followed it.
+package posture
+
+import (
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/clock"
+)
+
+// TestLoweringNeedsAGovernedDowngrade proves SetLevel refuses to lower,
+// and Lower enforces the cooldown and the second approver
+func TestLoweringNeedsAGovernedDowngrade(t *testing.T) {
+	now := clock.NewFake(time.Unix(1_700_000_000, 0))
+	state := NewState()
+	state.SetClock(now)
+	state.SetLevel(P3, "incident")
+	if _, err := state.SetLevel(P1, "all clear"); err == nil || state.Level() != P3 {
+		t.Fatal("SetLevel must not lower the posture")
+	}
+
+	rules := DowngradeRules{Cooldown: 10 * time.Minute, RequireApprover: true}
+	downgrade := Downgrade{Level: P1, Reason: "all clear", RequestedBy: "alice"}
+	if _, err := state.Lower(downgrade, rules); err == nil {
+		t.Fatal("a downgrade without its second approver must be refused")
+	}
+	downgrade.ApprovedBy = "alice"
+	if _, err := state.Lower(downgrade, rules); err == nil {
+		t.Fatal("the requester must not approve their own downgrade")
+	}
+	downgrade.ApprovedBy = "bob"
+	if _, err := state.Lower(downgrade, rules); err == nil {
+		t.Fatal("a downgrade inside the cooldown must be refused")
+	}
+
+	now.Advance(10 * time.Minute)
+	transition, err := state.Lower(downgrade, rules)
+	if err != nil || state.Level() != P1 {
+		t.Fatalf("lower: %v", err)
+	}
+	if transition.RequestedBy != "alice" || transition.ApprovedBy != "bob" || transition.Reason != "all clear" {
+		t.Fatalf("the transition must say who asked, who approved, and why, got %+v", transition)
+	}
+	if _, err := state.Lower(Downgrade{Level: P1, Reason: "again", RequestedBy: "alice"}, DowngradeRules{}); err == nil {
+		t.Fatal("a downgrade must lower the posture")
+	}
+}
+
+// TestRevertOnlyUndoesTheLatestRaises proves raises are undone only to a
+// level no lower than they started from, and not once another transition
+// followed them
+func TestRevertOnlyUndoesTheLatestRaises(t *testing.T) {
+	state := NewState()
+	state.SetLevel(P2, "elevated")
+	first, _ := state.Raise(P3, "window")
+	second, _ := state.Raise(P4, "stricter window")
+	own := []Transition{first, second}
+
+	if _, reverted := state.Revert(own, P1, "below the start"); reverted {
+		t.Fatal("a revert must not go below the level the raises started from")
+	}
+	if _, reverted := state.Revert(own[1:], P2, "window ended"); reverted {
+		t.Fatal("a revert must not go below the level its own first raise started from")
+	}
+	if _, reverted := state.Revert(own, P2, "window ended"); !reverted || state.Level() != P2 {
+		t.Fatalf("the latest raises must be undone, got P%d", state.Level())
+	}
+
+	raise, _ := state.Raise(P3, "window")
+	state.Raise(P4, "incident")
+	if _, reverted := state.Revert([]Transition{raise}, P2, "window ended"); reverted || state.Level() != P4 {
+		t.Fatal("a raise another transition followed must not be undone")
+	}
+}
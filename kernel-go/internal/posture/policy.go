@@ -0,0 +1,206 @@
+// WHY: What a posture level permits used to live in switch statements
+// spread over cif, cdi, and this package, so changing it meant changing
+// code in three places. A Policy states it as data: the scopes tokens may
+// carry, the highest sensitivity that may leave, the risks that need
+// confirmation, the leak budget, and the adapters within reach, per
+// level. The governance capsule holds one; DefaultPolicy is the behavior
+// the switches encoded, with exec adapters out of reach at P3 and only
+// low-risk readers at P4.
+package posture
+
+import "fmt"
+
+// Sensitivities in rising order
+const (
+	SensitivityLow    = "low"
+	SensitivityMedium = "medium"
+	SensitivityHigh   = "high"
+)
+
+// Adapter side effects an allowlist may name, as adapters declare them
+const (
+	SideEffectRead    = "read"
+	SideEffectWrite   = "write"
+	SideEffectNetwork = "network"
+	SideEffectExec    = "exec"
+)
+
+// Operation risks in rising order
+const (
+	RiskLow    = "low"
+	RiskMedium = "medium"
+	RiskHigh   = "high"
+)
+
+// DefaultLeakBudget is the bytes of output one response may carry
+const DefaultLeakBudget = 10000
+
+// sensitivityRank orders sensitivities and risks alike
+var sensitivityRank = map[string]int{SensitivityLow: 1, SensitivityMedium: 2, SensitivityHigh: 3}
+
+// IsSensitivity reports whether sensitivity is one the policy orders
+func IsSensitivity(sensitivity string) bool {
+	_, known := sensitivityRank[sensitivity]
+	return known
+}
+
+// LevelPolicy is what one posture level permits
+type LevelPolicy struct {
+	// AllowedScopes bounds the scope of tokens minted at this level;
+	// empty leaves the token template's scope as it is
+	AllowedScopes []string `json:"allowed_scopes,omitempty"`
+
+	// SensitivityCeiling is the highest sensitivity that may leave
+	// unredacted; empty lets nothing out
+	SensitivityCeiling string `json:"sensitivity_ceiling"`
+
+	// ConfirmRisks lists the operation risks that need user confirmation;
+	// a risk not known to the policy always does
+	ConfirmRisks []string `json:"confirm_risks,omitempty"`
+
+	// LeakBudget is the bytes of output one response may carry
+	LeakBudget int `json:"leak_budget"`
+
+	// Adapters bounds the adapters calls at this level may reach, on top
+	// of what their tokens allow
+	Adapters AdapterAllowlist `json:"adapters"`
+}
+
+// AdapterAllowlist bounds the adapters reachable at a level by name, by
+// declared risk, and by declared side effects; an empty list leaves its
+// dimension open
+type AdapterAllowlist struct {
+	Names       []string `json:"names,omitempty"`
+	Risks       []string `json:"risks,omitempty"`
+	SideEffects []string `json:"side_effects,omitempty"`
+}
+
+// Policy is what each posture level permits
+type Policy map[int]LevelPolicy
+
+// DefaultPolicy returns the posture semantics a kernel starts with
+func DefaultPolicy() Policy {
+	return Policy{
+		P1: {SensitivityCeiling: SensitivityHigh, LeakBudget: DefaultLeakBudget},
+		P2: {SensitivityCeiling: SensitivityMedium, ConfirmRisks: []string{RiskHigh}, LeakBudget: DefaultLeakBudget},
+		P3: {SensitivityCeiling: SensitivityLow, ConfirmRisks: []string{RiskMedium, RiskHigh}, LeakBudget: DefaultLeakBudget,
+			Adapters: AdapterAllowlist{SideEffects: []string{SideEffectRead, SideEffectWrite, SideEffectNetwork}}},
+		P4: {SensitivityCeiling: SensitivityLow, ConfirmRisks: []string{RiskLow, RiskMedium, RiskHigh}, LeakBudget: DefaultLeakBudget,
+			Adapters: AdapterAllowlist{Risks: []string{RiskLow}, SideEffects: []string{SideEffectRead}}},
+	}
+}
+
+// Validate checks that every defined level P1 to P4 has a policy with a
+// known ceiling, known risks, and a positive leak budget
+func (p Policy) Validate() error {
+	for level := P1; level <= P4; level++ {
+		policy, ok := p[level]
+		if !ok {
+			return fmt.Errorf("posture policy: P%d is not defined", level)
+		}
+		if _, known := sensitivityRank[policy.SensitivityCeiling]; !known && policy.SensitivityCeiling != "" {
+			return fmt.Errorf("posture policy: P%d ceiling %q is not a sensitivity", level, policy.SensitivityCeiling)
+		}
+		for _, risk := range policy.ConfirmRisks {
+			if _, known := sensitivityRank[risk]; !known {
+				return fmt.Errorf("posture policy: P%d confirm risk %q is not a risk", level, risk)
+			}
+		}
+		if err := policy.Adapters.validate(); err != nil {
+			return fmt.Errorf("posture policy: P%d %w", level, err)
+		}
+		if policy.LeakBudget <= 0 {
+			return fmt.Errorf("posture policy: P%d leak budget must be positive", level)
+		}
+	}
+	for level := range p {
+		if !IsValid(level) {
+			return fmt.Errorf("posture policy: P%d is not a posture", level)
+		}
+	}
+	return nil
+}
+
+// Level returns the policy of level. WHY: An undefined level gets the
+// zero policy, which redacts everything, confirms everything, and has no
+// leak budget.
+func (p Policy) Level(level int) LevelPolicy {
+	if !IsValid(level) {
+		return LevelPolicy{}
+	}
+	return p[level]
+}
+
+// RequiresConfirmation reports whether an operation of risk at level
+// needs user confirmation
+func (p Policy) RequiresConfirmation(level int, risk string) bool {
+	return p.Level(level).RequiresConfirmation(risk)
+}
+
+// Permits reports whether content of sensitivity may leave under the
+// level; an unknown sensitivity never may
+func (l LevelPolicy) Permits(sensitivity string) bool {
+	rank, known := sensitivityRank[sensitivity]
+	return known && rank <= sensitivityRank[l.SensitivityCeiling]
+}
+
+// RequiresConfirmation reports whether an operation of risk needs user
+// confirmation under the level; an unknown risk always does
+func (l LevelPolicy) RequiresConfirmation(risk string) bool {
+	if _, known := sensitivityRank[risk]; !known || !l.isDefined() {
+		return true
+	}
+	return contains(l.ConfirmRisks, risk)
+}
+
+// Permits reports why an adapter named name, declaring risk and
+// sideEffects, is out of reach under the allowlist, if it is
+func (a AdapterAllowlist) Permits(name string, risk string, sideEffects []string) error {
+	if len(a.Names) > 0 && !contains(a.Names, name) {
+		return fmt.Errorf("adapter %s is not on the allowlist", name)
+	}
+	if len(a.Risks) > 0 && !contains(a.Risks, risk) {
+		return fmt.Errorf("adapter %s risk %q is not allowed", name, risk)
+	}
+	if len(a.SideEffects) > 0 {
+		for _, effect := range sideEffects {
+			if !contains(a.SideEffects, effect) {
+				return fmt.Errorf("adapter %s side effect %q is not allowed", name, effect)
+			}
+		}
+	}
+	return nil
+}
+
+// validate checks the allowlist names only known risks and side effects
+func (a AdapterAllowlist) validate() error {
+	for _, risk := range a.Risks {
+		if _, known := sensitivityRank[risk]; !known {
+			return fmt.Errorf("adapter risk %q is not a risk", risk)
+		}
+	}
+	for _, effect := range a.SideEffects {
+		switch effect {
+		case SideEffectRead, SideEffectWrite, SideEffectNetwork, SideEffectExec:
+		default:
+			return fmt.Errorf("adapter side effect %q is not a side effect", effect)
+		}
+	}
+	return nil
+}
+
+// contains reports whether values holds value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// isDefined reports whether the level came from a policy rather than
+// being the zero policy of an undefined level
+func (l LevelPolicy) isDefined() bool {
+	return l.LeakBudget > 0
+}
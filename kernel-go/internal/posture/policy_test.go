@@ -0,0 +1,92 @@
+// WHY: These tests prove the default policy keeps the posture semantics
+// the switch statements encoded, adapter allowlists check every
+// dimension, and a malformed policy is refused.
+package posture
+
+import "testing"
+
+// TestDefaultPolicyMatchesPostureSemantics proves redaction and
+// confirmation tighten with posture as before, and undefined postures
+// and unknown labels fail closed
+func TestDefaultPolicyMatchesPostureSemantics(t *testing.T) {
+	policy := DefaultPolicy()
+	if err := policy.Validate(); err != nil {
+		t.Fatalf("the default policy must be valid: %v", err)
+	}
+
+	permits := map[int][3]bool{ // low, medium, high
+		P1: {true, true, true},
+		P2: {true, true, false},
+		P3: {true, false, false},
+		P4: {true, false, false},
+		P0: {false, false, false},
+	}
+	confirms := map[int][3]bool{
+		P1: {false, false, false},
+		P2: {false, false, true},
+		P3: {false, true, true},
+		P4: {true, true, true},
+		P0: {true, true, true},
+	}
+	for level, want := range permits {
+		for i, label := range []string{SensitivityLow, SensitivityMedium, SensitivityHigh} {
+			if got := policy.Level(level).Permits(label); got != want[i] {
+				t.Errorf("P%d permits %s: expected %v", level, label, want[i])
+			}
+			if got := policy.RequiresConfirmation(level, label); got != confirms[level][i] {
+				t.Errorf("P%d confirms %s: expected %v", level, label, confirms[level][i])
+			}
+		}
+		if policy.Level(level).Permits("secret") || !policy.RequiresConfirmation(level, "unknown") {
+			t.Errorf("P%d must fail closed on unknown labels", level)
+		}
+	}
+}
+
+// TestPolicyValidation proves every posture must be defined with known
+// labels and a leak budget
+func TestPolicyValidation(t *testing.T) {
+	for name, edit := range map[string]func(Policy){
+		"missing level":   func(p Policy) { delete(p, P3) },
+		"unknown ceiling": func(p Policy) { p[P1] = LevelPolicy{SensitivityCeiling: "secret", LeakBudget: 1} },
+		"unknown risk":    func(p Policy) { p[P2] = LevelPolicy{ConfirmRisks: []string{"extreme"}, LeakBudget: 1} },
+		"no leak budget":  func(p Policy) { p[P4] = LevelPolicy{SensitivityCeiling: SensitivityLow} },
+		"extra level":     func(p Policy) { p[7] = LevelPolicy{LeakBudget: 1} },
+	} {
+		policy := DefaultPolicy()
+		edit(policy)
+		if err := policy.Validate(); err == nil {
+			t.Errorf("%s: must be refused", name)
+		}
+	}
+}
+
+// TestAdapterAllowlistChecksEveryDimension proves an adapter must pass
+// the name, risk, and side-effect lists, each open when empty, and a
+// policy naming an unknown side effect is refused
+func TestAdapterAllowlistChecksEveryDimension(t *testing.T) {
+	allowlist := AdapterAllowlist{Names: []string{"fetch"}, Risks: []string{RiskLow, RiskMedium}, SideEffects: []string{SideEffectRead, SideEffectNetwork}}
+	if err := allowlist.Permits("fetch", RiskMedium, []string{SideEffectNetwork}); err != nil {
+		t.Fatalf("an adapter within every list must be permitted: %v", err)
+	}
+	for name, err := range map[string]error{
+		"name":        allowlist.Permits("mailer", RiskMedium, nil),
+		"risk":        allowlist.Permits("fetch", RiskHigh, nil),
+		"side effect": allowlist.Permits("fetch", RiskLow, []string{SideEffectRead, SideEffectWrite}),
+	} {
+		if err == nil {
+			t.Fatalf("an adapter outside the %s list must be refused", name)
+		}
+	}
+	if err := (AdapterAllowlist{}).Permits("anything", RiskHigh, []string{SideEffectExec}); err != nil {
+		t.Fatalf("an empty allowlist must leave every dimension open: %v", err)
+	}
+
+	policy := DefaultPolicy()
+	level := policy[P3]
+	level.Adapters.SideEffects = []string{"teleport"}
+	policy[P3] = level
+	if err := policy.Validate(); err == nil {
+		t.Fatal("an unknown side effect must be refused")
+	}
+}
@@ -3,6 +3,15 @@
 // privilege escalation model.
 package posture
 
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/clock"
+)
+
 const (
 	// P0 is undefined/unknown - fails closed for high-risk operations
 	P0 = 0
@@ -28,24 +37,32 @@ func IsValid(level int) bool {
 	return level >= P1 && level <= P4
 }
 
-// RequiresConfirmation determines if an operation at this posture needs user confirmation
-func RequiresConfirmation(level int, operationRisk string) bool {
-	// P0 is undefined - always require confirmation for any risk
-	if level == P0 {
-		return true
+// Name returns the name of a defined level, e.g. "P3", or "" for any
+// other
+func Name(level int) string {
+	if !IsValid(level) {
+		return ""
 	}
+	return fmt.Sprintf("P%d", level)
+}
 
-	// Higher postures have more restrictions
-	switch operationRisk {
-	case "high":
-		return level >= P2
-	case "medium":
-		return level >= P3
-	case "low":
-		return level >= P4
-	default:
-		return true // unknown risk - fail closed
+// Parse returns the level a name such as "P3" stands for.
+// WHY: Levels are ordered integers; a name outside P1 to P4, such as a
+// "LOW" or "HIGH" that says nothing of its order, is refused rather than
+// guessed at.
+func Parse(name string) (int, error) {
+	for level := P1; level <= P4; level++ {
+		if strings.EqualFold(name, Name(level)) {
+			return level, nil
+		}
 	}
+	return P0, fmt.Errorf("posture %q is not defined", name)
+}
+
+// RequiresConfirmation determines if an operation at this posture needs
+// user confirmation under DefaultPolicy
+func RequiresConfirmation(level int, operationRisk string) bool {
+	return DefaultPolicy().RequiresConfirmation(level, operationRisk)
 }
 
 // FailClosed returns true if this posture should fail-closed for high-risk ops
@@ -57,18 +74,32 @@ func FailClosed(level int, operationRisk string) bool {
 	return false
 }
 
-// State tracks posture transitions
+// State tracks posture transitions. It is safe for concurrent use
+// through its methods; CurrentLevel and History are read directly only
+// where no transition can race.
 type State struct {
+	mu           sync.Mutex
 	CurrentLevel int
 	History      []Transition
+
+	// clock stamps transitions
+	clock clock.Clock
+
+	// onTransition, if set, is told of every transition
+	onTransition func(Transition)
 }
 
 // Transition records a posture change
 type Transition struct {
+	// Timestamp is Unix seconds; it never precedes the transition before it
 	Timestamp int64
 	FromLevel int
 	ToLevel   int
 	Reason    string
+
+	// RequestedBy and ApprovedBy name the principals behind a downgrade
+	RequestedBy string
+	ApprovedBy  string
 }
 
 // NewState creates a new posture state with default P1 level
@@ -76,22 +107,102 @@ func NewState() *State {
 	return &State{
 		CurrentLevel: P1,
 		History:      []Transition{},
+		clock:        clock.Real{},
+	}
+}
+
+// SetClock sets the clock transitions are stamped with
+func (s *State) SetClock(c clock.Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = c
+}
+
+// OnTransition registers fn to receive every transition; fn is called
+// after the state's lock is released.
+// WHY: The state has no ledger; the kernel records the transitions.
+func (s *State) OnTransition(fn func(Transition)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onTransition = fn
+}
+
+// Level returns the current posture level
+func (s *State) Level() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.CurrentLevel
+}
+
+// Transitions returns a copy of the transition history, oldest first
+func (s *State) Transitions() []Transition {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Transition(nil), s.History...)
+}
+
+// SetLevel changes the posture level and records the transition.
+// WHY: Lowering relaxes constraint, so it is refused here and goes
+// through Lower instead.
+func (s *State) SetLevel(newLevel int, reason string) (Transition, error) {
+	s.mu.Lock()
+	if newLevel < s.CurrentLevel {
+		err := fmt.Errorf("lowering posture P%d to P%d needs a downgrade", s.CurrentLevel, newLevel)
+		s.mu.Unlock()
+		return Transition{}, err
 	}
+	transition := s.recordLocked(Transition{ToLevel: newLevel, Reason: reason})
+	s.unlockAndReport(transition)
+	return transition, nil
 }
 
-// SetLevel changes the posture level and records the transition
-func (s *State) SetLevel(newLevel int, reason string) {
-	transition := Transition{
-		Timestamp: currentTimestamp(),
-		FromLevel: s.CurrentLevel,
-		ToLevel:   newLevel,
-		Reason:    reason,
+// Raise changes the posture level only if newLevel is more restrictive
+// than the current one, and reports whether it did
+func (s *State) Raise(newLevel int, reason string) (Transition, bool) {
+	s.mu.Lock()
+	if newLevel <= s.CurrentLevel {
+		s.mu.Unlock()
+		return Transition{}, false
 	}
+	transition := s.recordLocked(Transition{ToLevel: newLevel, Reason: reason})
+	s.unlockAndReport(transition)
+	return transition, true
+}
+
+// recordLocked stamps transition, sets its starting level, and applies
+// it; s.mu is held
+func (s *State) recordLocked(transition Transition) Transition {
+	transition.Timestamp = s.timestamp()
+	transition.FromLevel = s.CurrentLevel
 	s.History = append(s.History, transition)
-	s.CurrentLevel = newLevel
+	s.CurrentLevel = transition.ToLevel
+	return transition
+}
+
+// unlockAndReport releases s.mu and tells the hook of transition
+func (s *State) unlockAndReport(transition Transition) {
+	fn := s.onTransition
+	s.mu.Unlock()
+	if fn != nil {
+		fn(transition)
+	}
 }
 
-func currentTimestamp() int64 {
-	// Simple timestamp - in production would use time.Now().Unix()
-	return 0
+// now reads the state's clock; s.mu is held
+func (s *State) now() time.Time {
+	if s.clock == nil {
+		return time.Now()
+	}
+	return s.clock.Now()
+}
+
+// timestamp stamps the next transition; s.mu is held. WHY: History is
+// read in order, so a wall clock stepping backwards is held at the
+// previous transition.
+func (s *State) timestamp() int64 {
+	now := s.now().Unix()
+	if len(s.History) > 0 && now < s.History[len(s.History)-1].Timestamp {
+		return s.History[len(s.History)-1].Timestamp
+	}
+	return now
 }
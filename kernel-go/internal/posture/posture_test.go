@@ -0,0 +1,78 @@
+// WHY: These tests prove posture transitions carry real timestamps in the
+// order they happened, even when the wall clock steps backwards.
+package posture
+
+import (
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/clock"
+)
+
+// TestTransitionsAreStampedInOrder proves each transition takes the
+// clock's time and never precedes the one before it
+func TestTransitionsAreStampedInOrder(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	now := clock.NewFake(start)
+	state := NewState()
+	state.SetClock(now)
+
+	state.SetLevel(P2, "elevated")
+	now.Set(start.Add(-time.Hour))
+	state.SetLevel(P3, "restricted")
+	now.Set(start.Add(time.Minute))
+	state.Lower(Downgrade{Level: P1, Reason: "restored", RequestedBy: "operator"}, DowngradeRules{})
+
+	want := []int64{start.Unix(), start.Unix(), start.Add(time.Minute).Unix()}
+	for i, transition := range state.History {
+		if transition.Timestamp != want[i] {
+			t.Fatalf("transition %d: expected %d, got %d", i, want[i], transition.Timestamp)
+		}
+	}
+
+	system := NewState()
+	system.SetLevel(P2, "elevated")
+	if system.History[0].Timestamp == 0 {
+		t.Fatal("a state without a set clock must read the system clock")
+	}
+}
+
+// TestRaiseOnlyTightensAndTransitionsAreReported proves Raise ignores a
+// lower or equal level, and every transition reaches the hook
+func TestRaiseOnlyTightensAndTransitionsAreReported(t *testing.T) {
+	state := NewState()
+	var reported []Transition
+	state.OnTransition(func(transition Transition) { reported = append(reported, transition) })
+
+	state.SetLevel(P3, "restricted")
+	if _, raised := state.Raise(P2, "lower"); raised {
+		t.Fatal("Raise must not lower the posture")
+	}
+	if _, raised := state.Raise(P4, "oracle"); !raised || state.Level() != P4 {
+		t.Fatalf("Raise must tighten the posture, got P%d", state.Level())
+	}
+	if len(reported) != 2 || reported[0].ToLevel != P3 || reported[1].FromLevel != P3 || len(state.Transitions()) != 2 {
+		t.Fatalf("each transition must be reported once, got %+v", reported)
+	}
+}
+
+// TestPostureNamesRoundTrip proves every defined level has a name that
+// parses back to it, and names that say nothing of order are refused
+func TestPostureNamesRoundTrip(t *testing.T) {
+	for level := P1; level <= P4; level++ {
+		if parsed, err := Parse(Name(level)); err != nil || parsed != level {
+			t.Fatalf("P%d must round-trip, got %d (%v)", level, parsed, err)
+		}
+	}
+	if level, err := Parse("p2"); err != nil || level != P2 {
+		t.Fatalf("names must parse regardless of case, got %d (%v)", level, err)
+	}
+	for _, name := range []string{"P0", "P5", "LOW", ""} {
+		if _, err := Parse(name); err == nil {
+			t.Fatalf("%q must be refused", name)
+		}
+	}
+	if Name(P0) != "" {
+		t.Fatal("an undefined level must have no name")
+	}
+}
@@ -0,0 +1,114 @@
+// WHY: Some constraint is known in advance: fewer people watch outside
+// business hours, and an incident freeze has a start and an end. A
+// Schedule states those periods as windows, each raising the posture to
+// a level while it is active, so the kernel tightens on the calendar
+// rather than on someone remembering to.
+package posture
+
+import (
+	"fmt"
+	"time"
+)
+
+// Window raises the posture to Level while it is active. A window with
+// Start equal to End is active all day; End before Start wraps past
+// midnight. From and Until, if set, bound the window to a span of time.
+type Window struct {
+	Name  string
+	Level int
+
+	// Weekdays limits the window to the days it starts on; empty means
+	// every day
+	Weekdays []time.Weekday
+
+	// Start and End are offsets into the day
+	Start time.Duration
+	End   time.Duration
+
+	From  time.Time
+	Until time.Time
+}
+
+// Schedule is a set of posture windows read in one location
+type Schedule struct {
+	Windows []Window
+
+	// Location is the time zone days are read in; nil means UTC
+	Location *time.Location
+}
+
+// Validate checks every window names a defined posture and fits in a day
+func (s Schedule) Validate() error {
+	names := map[string]bool{}
+	for _, window := range s.Windows {
+		if window.Name == "" || names[window.Name] {
+			return fmt.Errorf("posture window %q: needs a unique name", window.Name)
+		}
+		names[window.Name] = true
+		if !IsValid(window.Level) {
+			return fmt.Errorf("posture window %s: posture %d is not defined", window.Name, window.Level)
+		}
+		if window.Start < 0 || window.Start >= 24*time.Hour || window.End < 0 || window.End >= 24*time.Hour {
+			return fmt.Errorf("posture window %s: start and end must fall within a day", window.Name)
+		}
+		if !window.From.IsZero() && !window.Until.IsZero() && !window.Until.After(window.From) {
+			return fmt.Errorf("posture window %s: until must follow from", window.Name)
+		}
+	}
+	return nil
+}
+
+// ActiveAt returns the active window with the highest level at t, if any
+func (s Schedule) ActiveAt(t time.Time) (Window, bool) {
+	location := s.Location
+	if location == nil {
+		location = time.UTC
+	}
+	t = t.In(location)
+
+	var active Window
+	found := false
+	for _, window := range s.Windows {
+		if window.activeAt(t) && (!found || window.Level > active.Level) {
+			active, found = window, true
+		}
+	}
+	return active, found
+}
+
+// activeAt reports whether the window is active at t, read in t's location
+func (w Window) activeAt(t time.Time) bool {
+	if !w.From.IsZero() && t.Before(w.From) {
+		return false
+	}
+	if !w.Until.IsZero() && !t.Before(w.Until) {
+		return false
+	}
+
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := t.Sub(midnight)
+	started := midnight
+	switch {
+	case w.Start == w.End:
+	case w.Start < w.End:
+		if offset < w.Start || offset >= w.End {
+			return false
+		}
+	case offset >= w.Start:
+	case offset < w.End:
+		// WHY: Past midnight, the window belongs to the day it started
+		started = midnight.AddDate(0, 0, -1)
+	default:
+		return false
+	}
+
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+	for _, day := range w.Weekdays {
+		if day == started.Weekday() {
+			return true
+		}
+	}
+	return false
+}
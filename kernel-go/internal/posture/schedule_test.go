@@ -0,0 +1,52 @@
+// WHY: These tests prove posture windows are active on the days and hours
+// they name, wrap past midnight, respect a fixed span, and that the
+// highest active window wins.
+package posture
+
+import (
+	"testing"
+	"time"
+)
+
+// TestScheduleWindowsFollowTheCalendar proves after-hours and freeze
+// windows are active exactly when they say
+func TestScheduleWindowsFollowTheCalendar(t *testing.T) {
+	freezeFrom := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	schedule := Schedule{Windows: []Window{
+		{Name: "after_hours", Level: P3, Weekdays: []time.Weekday{time.Monday, time.Tuesday}, Start: 18 * time.Hour, End: 8 * time.Hour},
+		{Name: "freeze", Level: P4, From: freezeFrom, Until: freezeFrom.Add(24 * time.Hour)},
+	}}
+	if err := schedule.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	for _, tc := range []struct {
+		at   time.Time
+		want string
+	}{
+		{time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC), ""},           // Monday morning
+		{time.Date(2026, 3, 2, 19, 0, 0, 0, time.UTC), "freeze"},     // Monday evening, both active
+		{time.Date(2026, 3, 4, 3, 0, 0, 0, time.UTC), "after_hours"}, // Wednesday night, started Tuesday
+		{time.Date(2026, 3, 5, 3, 0, 0, 0, time.UTC), ""},            // Thursday night, started Wednesday
+		{time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC), ""},           // freeze over
+	} {
+		window, active := schedule.ActiveAt(tc.at)
+		if (tc.want == "") == active || (active && window.Name != tc.want) {
+			t.Errorf("%s: expected %q, got %q (active %v)", tc.at, tc.want, window.Name, active)
+		}
+	}
+}
+
+// TestScheduleValidation proves malformed windows are refused
+func TestScheduleValidation(t *testing.T) {
+	for name, window := range map[string]Window{
+		"no name":           {Level: P3},
+		"undefined posture": {Name: "w", Level: P0},
+		"past a day":        {Name: "w", Level: P3, End: 25 * time.Hour},
+		"inverted span":     {Name: "w", Level: P3, From: time.Unix(100, 0), Until: time.Unix(50, 0)},
+	} {
+		if err := (Schedule{Windows: []Window{window}}).Validate(); err == nil {
+			t.Errorf("%s: must be refused", name)
+		}
+	}
+}
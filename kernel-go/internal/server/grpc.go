@@ -0,0 +1,340 @@
+// WHY: Services embedding the corridor want the typed contract in
+// proto/oi/kernel/v1/kernel.proto rather than JSON read off the docs.
+// GRPCServer serves that contract over HTTP/2 with the gRPC framing and
+// status trailers, so stubs generated from the .proto in any language can
+// call it, while the kernel keeps no dependency beyond the standard
+// library. It adds no authority over Server: the same kernel calls, the
+// same confinement to the caller's own tokens and receipts, the same body
+// limit and auth hook.
+package server
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/user/oi/kernel-go/internal/kernel"
+)
+
+// GRPCServiceName is the fully qualified name of the OIKernel service
+const GRPCServiceName = "oi.kernel.v1.OIKernel"
+
+// streamChunkBytes bounds one output chunk of ExecuteStream
+const streamChunkBytes = 256
+
+// Code is a gRPC status code
+type Code int
+
+// The gRPC status codes the kernel reports
+const (
+	CodeOK                Code = 0
+	CodeInvalidArgument   Code = 3
+	CodePermissionDenied  Code = 7
+	CodeResourceExhausted Code = 8
+	CodeUnimplemented     Code = 12
+	CodeInternal          Code = 13
+	CodeUnavailable       Code = 14
+	CodeUnauthenticated   Code = 16
+)
+
+// StatusError is a call that ended with a non-OK gRPC status
+type StatusError struct {
+	Code    Code
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("grpc status %d: %s", e.Code, e.Message)
+}
+
+// grpcMethod serves one call: in is the request message, and send writes
+// one response message
+type grpcMethod func(g *GRPCServer, bearer string, in []byte, send func([]byte) error) error
+
+var grpcMethods = map[string]grpcMethod{
+	"Execute":         (*GRPCServer).execute,
+	"ExecuteStream":   (*GRPCServer).executeStream,
+	"Stop":            (*GRPCServer).stop,
+	"GetReceipts":     (*GRPCServer).receipts,
+	"IntrospectToken": (*GRPCServer).introspectToken,
+}
+
+// GRPCServer serves the OIKernel service
+type GRPCServer struct {
+	state   *kernel.SystemState
+	options Options
+}
+
+// NewGRPC creates a gRPC server for state. It needs HTTP/2: serve it with
+// TLS, or with http.Protocols allowing unencrypted HTTP/2.
+func NewGRPC(state *kernel.SystemState, options Options) *GRPCServer {
+	if options.MaxBodyBytes <= 0 {
+		options.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+	return &GRPCServer{state: state, options: options}
+}
+
+// ServeHTTP serves one call, reporting its status in the grpc-status and
+// grpc-message trailers
+func (g *GRPCServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "gRPC calls are POST", http.StatusMethodNotAllowed)
+		return
+	}
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		http.Error(w, "gRPC calls are application/grpc", http.StatusUnsupportedMediaType)
+		return
+	}
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+
+	status := &StatusError{Code: CodeOK}
+	if err := g.serve(w, r); err != nil && !errors.As(err, &status) {
+		status = &StatusError{Code: CodeInternal, Message: err.Error()}
+	}
+	w.Header().Set("Grpc-Status", strconv.Itoa(int(status.Code)))
+	if status.Message != "" {
+		w.Header().Set("Grpc-Message", encodeGRPCMessage(status.Message))
+	}
+}
+
+// serve vets the call, reads its request message, and runs its method
+func (g *GRPCServer) serve(w http.ResponseWriter, r *http.Request) error {
+	name, ok := strings.CutPrefix(r.URL.Path, "/"+GRPCServiceName+"/")
+	method := grpcMethods[name]
+	if !ok || method == nil {
+		return &StatusError{Code: CodeUnimplemented, Message: fmt.Sprintf("unknown method %s", r.URL.Path)}
+	}
+	if g.options.Auth != nil {
+		if err := g.options.Auth(r); err != nil {
+			return &StatusError{Code: CodeUnauthenticated, Message: err.Error()}
+		}
+	}
+	in, err := readMessage(r.Body, g.options.MaxBodyBytes)
+	if errors.Is(err, io.EOF) {
+		return &StatusError{Code: CodeInvalidArgument, Message: "the call carries no request message"}
+	}
+	if err != nil {
+		return err
+	}
+	flusher, _ := w.(http.Flusher)
+	send := func(out []byte) error {
+		if err := writeMessage(w, out); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	}
+	return method(g, bearerToken(r), in, send)
+}
+
+// execute runs one request through the corridor
+func (g *GRPCServer) execute(bearer string, in []byte, send func([]byte) error) error {
+	result, err := g.run(bearer, in, nil)
+	if err != nil {
+		return err
+	}
+	return send(encodeExecuteResponse(result))
+}
+
+// executeStream runs one request through the corridor, sending each audit
+// trail stage as it is reached, then the shaped output in chunks, then the
+// result without its content. WHY: The output is only sent once egress
+// has shaped it, never as the model produces it.
+func (g *GRPCServer) executeStream(bearer string, in []byte, send func([]byte) error) error {
+	result, err := g.run(bearer, in, func(stage string) {
+		send(encodeExecuteEvent(ExecuteEvent{Stage: stage}))
+	})
+	if err != nil {
+		return err
+	}
+	for _, chunk := range chunkContent(result.Content, streamChunkBytes) {
+		if err := send(encodeExecuteEvent(ExecuteEvent{Chunk: chunk})); err != nil {
+			return err
+		}
+	}
+	result.Content = ""
+	return send(encodeExecuteEvent(ExecuteEvent{Result: &result}))
+}
+
+// run executes the request in as the bearer's principal. A refusal by the
+// corridor is a result; refused identity and a quiescing kernel are
+// errors.
+func (g *GRPCServer) run(bearer string, in []byte, onStage func(stage string)) (ExecuteResponse, error) {
+	req, err := decodeExecuteRequest(in)
+	if err != nil {
+		return ExecuteResponse{}, &StatusError{Code: CodeInvalidArgument, Message: fmt.Sprintf("request message: %v", err)}
+	}
+	if bearer == "" {
+		return ExecuteResponse{}, &StatusError{Code: CodeUnauthenticated, Message: "a bearer token is required"}
+	}
+
+	resp, err := kernel.Execute(&kernel.Request{
+		RawInput:    req.Input,
+		Metadata:    req.Metadata,
+		NamespaceID: req.NamespaceID,
+		SessionID:   req.SessionID,
+		BearerToken: bearer,
+		OnStage:     onStage,
+	}, g.state)
+	if resp == nil {
+		return ExecuteResponse{}, fmt.Errorf("execute: %v", err)
+	}
+	switch executeStatus(resp) {
+	case http.StatusUnauthorized:
+		return ExecuteResponse{}, &StatusError{Code: CodeUnauthenticated, Message: resp.Error}
+	case http.StatusServiceUnavailable:
+		return ExecuteResponse{}, &StatusError{Code: CodeUnavailable, Message: resp.Error}
+	}
+	return ExecuteResponse{
+		RequestID:  resp.RequestID,
+		Success:    resp.Success,
+		Content:    resp.Content,
+		Error:      resp.Error,
+		AuditTrail: resp.AuditTrail,
+		Receipts:   requestReceipts(g.state, resp.RequestID),
+	}, nil
+}
+
+// stop pulls a STOP as the bearer's principal
+func (g *GRPCServer) stop(bearer string, in []byte, send func([]byte) error) error {
+	stop, err := decodeStopRequest(in)
+	if err != nil {
+		return &StatusError{Code: CodeInvalidArgument, Message: fmt.Sprintf("request message: %v", err)}
+	}
+	summary, err := g.state.InvokeStop(bearer, stop)
+	if err != nil {
+		var refused *kernel.StopRefusedError
+		if errors.As(err, &refused) {
+			return &StatusError{Code: refusalCode(refused.Reason), Message: err.Error()}
+		}
+		return err
+	}
+	return send(encodeStopSummary(summary))
+}
+
+// receipts returns a page of the caller's own receipts
+func (g *GRPCServer) receipts(bearer string, in []byte, send func([]byte) error) error {
+	filter, err := decodeReceiptFilter(in)
+	if err != nil {
+		return &StatusError{Code: CodeInvalidArgument, Message: fmt.Sprintf("request message: %v", err)}
+	}
+	page, err := g.state.QueryReceipts(bearer, filter)
+	if err != nil {
+		return accessError(err)
+	}
+	out, err := encodeReceiptsResponse(ReceiptsResponse{Receipts: page.Receipts, NextSequence: page.NextSequence, HasMore: page.HasMore})
+	if err != nil {
+		return err
+	}
+	return send(out)
+}
+
+// introspectToken describes one of the caller's tokens
+func (g *GRPCServer) introspectToken(bearer string, in []byte, send func([]byte) error) error {
+	digest, err := decodeIntrospectTokenRequest(in)
+	if err != nil {
+		return &StatusError{Code: CodeInvalidArgument, Message: fmt.Sprintf("request message: %v", err)}
+	}
+	info, err := g.state.IntrospectToken(bearer, digest)
+	if err != nil {
+		return accessError(err)
+	}
+	return send(encodeTokenInfo(info))
+}
+
+// accessError is the status of a refused read
+func accessError(err error) error {
+	var refused *kernel.AccessRefusedError
+	if !errors.As(err, &refused) {
+		return err
+	}
+	return &StatusError{Code: refusalCode(refused.Reason), Message: err.Error()}
+}
+
+// refusalCode is the status of a StopRefused reason
+func refusalCode(reason string) Code {
+	switch reason {
+	case kernel.StopRefusedUnauthenticated:
+		return CodeUnauthenticated
+	case kernel.StopRefusedForbidden:
+		return CodePermissionDenied
+	default:
+		return CodeInvalidArgument
+	}
+}
+
+// chunkContent splits content into pieces of at most size bytes, never
+// inside a UTF-8 sequence
+func chunkContent(content string, size int) []string {
+	var chunks []string
+	for len(content) > size {
+		end := size
+		for end > 0 && !utf8.RuneStart(content[end]) {
+			end--
+		}
+		if end == 0 {
+			end = size
+		}
+		chunks = append(chunks, content[:end])
+		content = content[end:]
+	}
+	if content != "" {
+		chunks = append(chunks, content)
+	}
+	return chunks
+}
+
+// readMessage reads one length-prefixed gRPC message of at most limit
+// bytes
+func readMessage(r io.Reader, limit int64) ([]byte, error) {
+	var prefix [5]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		return nil, &StatusError{Code: CodeInvalidArgument, Message: fmt.Sprintf("message prefix: %v", err)}
+	}
+	if prefix[0] != 0 {
+		return nil, &StatusError{Code: CodeUnimplemented, Message: "compressed messages are not supported"}
+	}
+	length := binary.BigEndian.Uint32(prefix[1:])
+	if int64(length) > limit {
+		return nil, &StatusError{Code: CodeResourceExhausted, Message: fmt.Sprintf("message of %d bytes exceeds %d", length, limit)}
+	}
+	message := make([]byte, length)
+	if _, err := io.ReadFull(r, message); err != nil {
+		return nil, &StatusError{Code: CodeInvalidArgument, Message: fmt.Sprintf("message: %v", err)}
+	}
+	return message, nil
+}
+
+// writeMessage writes message with its uncompressed length prefix
+func writeMessage(w io.Writer, message []byte) error {
+	frame := make([]byte, 5, 5+len(message))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(message)))
+	_, err := w.Write(append(frame, message...))
+	return err
+}
+
+// encodeGRPCMessage percent-encodes a status message as the gRPC protocol
+// requires of the grpc-message trailer
+func encodeGRPCMessage(message string) string {
+	var b strings.Builder
+	for i := 0; i < len(message); i++ {
+		c := message[i]
+		if c < ' ' || c > '~' || c == '%' {
+			fmt.Fprintf(&b, "%%%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,178 @@
+// WHY: A Go service embedding the corridor should call it through the
+// typed contract, not hand-roll frames. Client speaks the OIKernel
+// service to any server of it, this package's or one generated from the
+// .proto, and reports a non-OK status as a *StatusError. The bearer token
+// is passed per call because the principal belongs to the call, not the
+// connection.
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/kernel"
+)
+
+// MaxReceiveBytes bounds one message the client accepts
+const MaxReceiveBytes = 4 << 20
+
+// Client calls the OIKernel service
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+// NewClient creates a client for the server at baseURL, e.g.
+// "http://localhost:9090". httpClient must speak HTTP/2; nil means one
+// that speaks it unencrypted to http:// URLs and over TLS to https://.
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		protocols := new(http.Protocols)
+		protocols.SetHTTP2(true)
+		protocols.SetUnencryptedHTTP2(true)
+		httpClient = &http.Client{Transport: &http.Transport{Protocols: protocols}}
+	}
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), http: httpClient}
+}
+
+// Execute runs req through the corridor as the principal bearer names. A
+// refusal by the corridor is a response with Success false, not an error.
+func (c *Client) Execute(ctx context.Context, bearer string, req ExecuteRequest) (ExecuteResponse, error) {
+	out, err := c.unary(ctx, "Execute", bearer, encodeExecuteRequest(req))
+	if err != nil {
+		return ExecuteResponse{}, err
+	}
+	return decodeExecuteResponse(out)
+}
+
+// ExecuteStream runs req through the corridor, calling handle with each
+// event as it arrives; an error from handle ends the call
+func (c *Client) ExecuteStream(ctx context.Context, bearer string, req ExecuteRequest, handle func(ExecuteEvent) error) error {
+	return c.call(ctx, "ExecuteStream", bearer, encodeExecuteRequest(req), func(out []byte) error {
+		event, err := decodeExecuteEvent(out)
+		if err != nil {
+			return err
+		}
+		return handle(event)
+	})
+}
+
+// Stop pulls stop as the principal bearer names
+func (c *Client) Stop(ctx context.Context, bearer string, stop kernel.StopRequest) (kernel.StopSummary, error) {
+	out, err := c.unary(ctx, "Stop", bearer, encodeStopRequest(stop))
+	if err != nil {
+		return kernel.StopSummary{}, err
+	}
+	return decodeStopSummary(out)
+}
+
+// GetReceipts returns a page of the receipts of the principal bearer
+// names that filter matches
+func (c *Client) GetReceipts(ctx context.Context, bearer string, filter audit.ReceiptFilter) (ReceiptsResponse, error) {
+	out, err := c.unary(ctx, "GetReceipts", bearer, encodeReceiptFilter(filter))
+	if err != nil {
+		return ReceiptsResponse{}, err
+	}
+	return decodeReceiptsResponse(out)
+}
+
+// IntrospectToken describes the token with digest, if the principal
+// bearer names holds it
+func (c *Client) IntrospectToken(ctx context.Context, bearer string, digest string) (kernel.TokenInfo, error) {
+	out, err := c.unary(ctx, "IntrospectToken", bearer, encodeIntrospectTokenRequest(digest))
+	if err != nil {
+		return kernel.TokenInfo{}, err
+	}
+	return decodeTokenInfo(out)
+}
+
+// unary makes a call that answers with exactly one message
+func (c *Client) unary(ctx context.Context, method string, bearer string, in []byte) ([]byte, error) {
+	var out []byte
+	received := false
+	err := c.call(ctx, method, bearer, in, func(message []byte) error {
+		if received {
+			return &StatusError{Code: CodeInternal, Message: fmt.Sprintf("%s answered with more than one message", method)}
+		}
+		out, received = message, true
+		return nil
+	})
+	if err == nil && !received {
+		err = &StatusError{Code: CodeInternal, Message: fmt.Sprintf("%s answered with no message", method)}
+	}
+	return out, err
+}
+
+// call sends in to method and passes each response message to receive,
+// then returns the call's status
+func (c *Client) call(ctx context.Context, method string, bearer string, in []byte, receive func([]byte) error) error {
+	var body bytes.Buffer
+	writeMessage(&body, in)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/"+GRPCServiceName+"/"+method, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: HTTP status %s", method, resp.Status)
+	}
+	// WHY: A call that fails before answering may carry its status in the
+	// headers alone
+	if err := callStatus(resp.Header); err != nil {
+		return err
+	}
+	for {
+		message, err := readMessage(resp.Body, MaxReceiveBytes)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := receive(message); err != nil {
+			return err
+		}
+	}
+	if resp.Trailer.Get("Grpc-Status") == "" && resp.Header.Get("Grpc-Status") == "" {
+		return &StatusError{Code: CodeInternal, Message: fmt.Sprintf("%s ended without a status", method)}
+	}
+	return callStatus(resp.Trailer)
+}
+
+// callStatus is the error of a non-OK grpc-status in header, or nil
+func callStatus(header http.Header) error {
+	value := header.Get("Grpc-Status")
+	if value == "" {
+		return nil
+	}
+	code, err := strconv.Atoi(value)
+	if err != nil {
+		return &StatusError{Code: CodeInternal, Message: fmt.Sprintf("malformed grpc-status %q", value)}
+	}
+	if Code(code) == CodeOK {
+		return nil
+	}
+	message := header.Get("Grpc-Message")
+	if decoded, err := url.PathUnescape(message); err == nil {
+		message = decoded
+	}
+	return &StatusError{Code: Code(code), Message: message}
+}
@@ -0,0 +1,160 @@
+// WHY: Proves the gRPC surface carries the same corridor as the HTTP one
+// over real HTTP/2: calls run as the bearer's principal, ExecuteStream
+// reports stages before output, refusals map to gRPC statuses, and
+// message sizes are bounded.
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/kernel"
+)
+
+// serveGRPC serves handler over unencrypted HTTP/2 and returns a client
+// for it
+func serveGRPC(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+	ts := httptest.NewUnstartedServer(handler)
+	ts.Config.Protocols = new(http.Protocols)
+	ts.Config.Protocols.SetUnencryptedHTTP2(true)
+	ts.Start()
+	t.Cleanup(ts.Close)
+	return NewClient(ts.URL, nil)
+}
+
+// statusCode is the gRPC code of err, or CodeOK
+func statusCode(err error) Code {
+	var status *StatusError
+	if errors.As(err, &status) {
+		return status.Code
+	}
+	if err != nil {
+		return CodeInternal
+	}
+	return CodeOK
+}
+
+// TestGRPCCallsRunAsTheBearer proves each unary call reaches the kernel
+// as the principal its bearer names and is confined to that principal
+func TestGRPCCallsRunAsTheBearer(t *testing.T) {
+	state, key := newServerKernel(t)
+	client := serveGRPC(t, NewGRPC(state, Options{}))
+	ctx := context.Background()
+	alice := bearerFor(key, "alice", "tenant_a")
+
+	if _, err := client.Execute(ctx, "", ExecuteRequest{Input: "test request"}); statusCode(err) != CodeUnauthenticated {
+		t.Fatalf("a call without a bearer token must be unauthenticated, got %v", err)
+	}
+	if _, err := client.Execute(ctx, alice+"x", ExecuteRequest{Input: "test request"}); statusCode(err) != CodeUnauthenticated {
+		t.Fatalf("an unverifiable bearer must be unauthenticated, got %v", err)
+	}
+
+	result, err := client.Execute(ctx, alice, ExecuteRequest{Input: "test request"})
+	if err != nil || !result.Success || result.RequestID == "" || len(result.Receipts) == 0 {
+		t.Fatalf("execute: %+v %v", result, err)
+	}
+
+	page, err := client.GetReceipts(ctx, alice, audit.ReceiptFilter{EventTypes: []string{"token_mint"}, RequestID: result.RequestID})
+	if err != nil || len(page.Receipts) != 1 {
+		t.Fatalf("alice must read her request's mint, got %+v %v", page, err)
+	}
+	digest, _ := page.Receipts[0].EventData["token_digest"].(string)
+	info, err := client.IntrospectToken(ctx, alice, digest)
+	if err != nil || info.PrincipalID != "alice" || !info.Active {
+		t.Fatalf("alice must introspect her token, got %+v %v", info, err)
+	}
+	if _, err := client.IntrospectToken(ctx, bearerFor(key, "bob", "tenant_a"), digest); statusCode(err) != CodePermissionDenied {
+		t.Fatalf("bob must not introspect alice's token, got %v", err)
+	}
+
+	if _, err := client.Stop(ctx, alice, kernel.StopRequest{Scope: "everything"}); statusCode(err) != CodeInvalidArgument {
+		t.Fatalf("an undefined STOP scope must be invalid, got %v", err)
+	}
+	summary, err := client.Stop(ctx, alice, kernel.StopRequest{Scope: audit.StopScopePrincipal})
+	if err != nil || summary.TokensRevoked != 1 || summary.TokenDigests[0] != digest {
+		t.Fatalf("alice's STOP must revoke her token, got %+v %v", summary, err)
+	}
+	result, err = client.Execute(ctx, alice, ExecuteRequest{Input: "test request"})
+	if err != nil || result.Success {
+		t.Fatalf("a request under alice's STOP must be refused with a result, got %+v %v", result, err)
+	}
+}
+
+// TestGRPCExecuteStreamSendsStagesThenOutput proves the stream reports
+// every audit trail stage before any output, and the output whole
+func TestGRPCExecuteStreamSendsStagesThenOutput(t *testing.T) {
+	state, key := newServerKernel(t)
+	client := serveGRPC(t, NewGRPC(state, Options{}))
+
+	var stages []string
+	var content strings.Builder
+	var result *ExecuteResponse
+	err := client.ExecuteStream(context.Background(), bearerFor(key, "alice", "tenant_a"), ExecuteRequest{Input: "test request"}, func(event ExecuteEvent) error {
+		switch {
+		case event.Result != nil:
+			result = event.Result
+		case event.Chunk != "":
+			content.WriteString(event.Chunk)
+		default:
+			if content.Len() > 0 {
+				t.Errorf("stage %q arrived after output", event.Stage)
+			}
+			stages = append(stages, event.Stage)
+		}
+		return nil
+	})
+	if err != nil || result == nil || !result.Success {
+		t.Fatalf("stream: %+v %v", result, err)
+	}
+	if strings.Join(stages, ",") != strings.Join(result.AuditTrail, ",") {
+		t.Fatalf("the stream must report each stage of %v, got %v", result.AuditTrail, stages)
+	}
+	if content.Len() == 0 || result.Content != "" {
+		t.Fatalf("output must arrive in chunks, not in the result, got %q and %q", content.String(), result.Content)
+	}
+}
+
+// TestGRPCRefusesBeforeTheKernel proves the auth hook, the message limit,
+// and unknown methods are refused with their gRPC statuses
+func TestGRPCRefusesBeforeTheKernel(t *testing.T) {
+	state, key := newServerKernel(t)
+	alice := bearerFor(key, "alice", "tenant_a")
+	ctx := context.Background()
+
+	hooked := serveGRPC(t, NewGRPC(state, Options{Auth: func(r *http.Request) error { return errors.New("no client certificate") }}))
+	if _, err := hooked.Execute(ctx, alice, ExecuteRequest{Input: "test request"}); statusCode(err) != CodeUnauthenticated || !strings.Contains(err.Error(), "no client certificate") {
+		t.Fatalf("the auth hook must refuse the call, got %v", err)
+	}
+
+	limited := serveGRPC(t, NewGRPC(state, Options{MaxBodyBytes: 64}))
+	if _, err := limited.Execute(ctx, alice, ExecuteRequest{Input: strings.Repeat("x", 128)}); statusCode(err) != CodeResourceExhausted {
+		t.Fatalf("an oversized message must be refused, got %v", err)
+	}
+
+	client := serveGRPC(t, NewGRPC(state, Options{}))
+	if err := client.call(ctx, "Reload", alice, nil, func([]byte) error { return nil }); statusCode(err) != CodeUnimplemented {
+		t.Fatalf("an unknown method must be unimplemented, got %v", err)
+	}
+}
+
+// TestChunkContentKeepsRunesWhole proves chunks never split a UTF-8
+// sequence and reassemble to the content
+func TestChunkContentKeepsRunesWhole(t *testing.T) {
+	content := strings.Repeat("aé€", 100)
+	chunks := chunkContent(content, 7)
+	for _, chunk := range chunks {
+		if len(chunk) > 7 || !utf8.ValidString(chunk) {
+			t.Fatalf("chunk %q is too long or splits a rune", chunk)
+		}
+	}
+	if strings.Join(chunks, "") != content {
+		t.Fatalf("chunks must reassemble to the content")
+	}
+}
@@ -0,0 +1,521 @@
+// WHY: The gRPC contract in proto/oi/kernel/v1/kernel.proto is encoded
+// here by field number, with no protobuf dependency, so the kernel stays
+// stdlib-only while clients generated from the .proto interoperate. Each
+// message has one encoder and one decoder; unknown fields are skipped, as
+// protobuf requires, so the contract can grow without breaking old peers.
+package server
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/kernel"
+)
+
+// Protobuf wire types used by the contract
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// ExecuteEvent is one event of ExecuteStream: exactly one of Stage,
+// Chunk, or Result is set
+type ExecuteEvent struct {
+	Stage  string
+	Chunk  string
+	Result *ExecuteResponse
+}
+
+// protoWriter appends protobuf fields, omitting proto3 defaults
+type protoWriter struct {
+	buf []byte
+}
+
+func (w *protoWriter) tag(field int, wire int) {
+	w.buf = binary.AppendUvarint(w.buf, uint64(field)<<3|uint64(wire))
+}
+
+func (w *protoWriter) varint(field int, value uint64) {
+	if value == 0 {
+		return
+	}
+	w.tag(field, wireVarint)
+	w.buf = binary.AppendUvarint(w.buf, value)
+}
+
+func (w *protoWriter) int64(field int, value int64) {
+	w.varint(field, uint64(value))
+}
+
+func (w *protoWriter) bool(field int, value bool) {
+	if value {
+		w.varint(field, 1)
+	}
+}
+
+func (w *protoWriter) bytes(field int, value []byte) {
+	if len(value) == 0 {
+		return
+	}
+	w.rawBytes(field, value)
+}
+
+func (w *protoWriter) string(field int, value string) {
+	w.bytes(field, []byte(value))
+}
+
+// rawBytes writes a length-delimited field even when empty, as repeated
+// elements, map entries, and oneof members must be
+func (w *protoWriter) rawBytes(field int, value []byte) {
+	w.tag(field, wireBytes)
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(value)))
+	w.buf = append(w.buf, value...)
+}
+
+func (w *protoWriter) strings(field int, values []string) {
+	for _, value := range values {
+		w.rawBytes(field, []byte(value))
+	}
+}
+
+// protoField is one decoded field: value for varints, data for
+// length-delimited fields
+type protoField struct {
+	number int
+	wire   int
+	value  uint64
+	data   []byte
+}
+
+func (f protoField) int64() int64 {
+	return int64(f.value)
+}
+
+func (f protoField) int32() int {
+	return int(int32(f.value))
+}
+
+func (f protoField) string() string {
+	return string(f.data)
+}
+
+// readProto calls visit with each field of a message in order, skipping
+// fixed-width fields the contract does not use
+func readProto(data []byte, visit func(protoField) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 || key>>3 == 0 || key>>3 > math.MaxInt32 {
+			return fmt.Errorf("malformed field key")
+		}
+		data = data[n:]
+		field := protoField{number: int(key >> 3), wire: int(key & 7)}
+		switch field.wire {
+		case wireVarint:
+			field.value, n = binary.Uvarint(data)
+			if n <= 0 {
+				return fmt.Errorf("malformed varint in field %d", field.number)
+			}
+			data = data[n:]
+		case wireBytes:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || length > uint64(len(data)-n) {
+				return fmt.Errorf("malformed length in field %d", field.number)
+			}
+			field.data = data[n : n+int(length)]
+			data = data[n+int(length):]
+		case wireFixed64, wireFixed32:
+			size := 8
+			if field.wire == wireFixed32 {
+				size = 4
+			}
+			if len(data) < size {
+				return fmt.Errorf("truncated field %d", field.number)
+			}
+			data = data[size:]
+			continue
+		default:
+			return fmt.Errorf("unsupported wire type %d in field %d", field.wire, field.number)
+		}
+		if err := visit(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeExecuteRequest(req ExecuteRequest) []byte {
+	w := &protoWriter{}
+	w.string(1, req.Input)
+	for key, value := range req.Metadata {
+		entry := &protoWriter{}
+		entry.string(1, key)
+		entry.string(2, fmt.Sprint(value))
+		w.rawBytes(2, entry.buf)
+	}
+	w.string(3, req.NamespaceID)
+	w.string(4, req.SessionID)
+	return w.buf
+}
+
+func decodeExecuteRequest(data []byte) (ExecuteRequest, error) {
+	req := ExecuteRequest{Metadata: map[string]interface{}{}}
+	err := readProto(data, func(f protoField) error {
+		switch f.number {
+		case 1:
+			req.Input = f.string()
+		case 2:
+			var key, value string
+			err := readProto(f.data, func(entry protoField) error {
+				switch entry.number {
+				case 1:
+					key = entry.string()
+				case 2:
+					value = entry.string()
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			req.Metadata[key] = value
+		case 3:
+			req.NamespaceID = f.string()
+		case 4:
+			req.SessionID = f.string()
+		}
+		return nil
+	})
+	return req, err
+}
+
+func encodeExecuteResponse(resp ExecuteResponse) []byte {
+	w := &protoWriter{}
+	w.string(1, resp.RequestID)
+	w.bool(2, resp.Success)
+	w.string(3, resp.Content)
+	w.string(4, resp.Error)
+	w.strings(5, resp.AuditTrail)
+	for _, receipt := range resp.Receipts {
+		summary := &protoWriter{}
+		summary.int64(1, receipt.Sequence)
+		summary.string(2, receipt.EventType)
+		summary.string(3, string(receipt.Severity))
+		summary.string(4, receipt.CurrentHash)
+		w.rawBytes(6, summary.buf)
+	}
+	return w.buf
+}
+
+func decodeExecuteResponse(data []byte) (ExecuteResponse, error) {
+	resp := ExecuteResponse{AuditTrail: []string{}, Receipts: []ReceiptSummary{}}
+	err := readProto(data, func(f protoField) error {
+		switch f.number {
+		case 1:
+			resp.RequestID = f.string()
+		case 2:
+			resp.Success = f.value != 0
+		case 3:
+			resp.Content = f.string()
+		case 4:
+			resp.Error = f.string()
+		case 5:
+			resp.AuditTrail = append(resp.AuditTrail, f.string())
+		case 6:
+			var summary ReceiptSummary
+			err := readProto(f.data, func(g protoField) error {
+				switch g.number {
+				case 1:
+					summary.Sequence = g.int64()
+				case 2:
+					summary.EventType = g.string()
+				case 3:
+					summary.Severity = audit.Severity(g.string())
+				case 4:
+					summary.CurrentHash = g.string()
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			resp.Receipts = append(resp.Receipts, summary)
+		}
+		return nil
+	})
+	return resp, err
+}
+
+func encodeExecuteEvent(event ExecuteEvent) []byte {
+	w := &protoWriter{}
+	switch {
+	case event.Result != nil:
+		w.rawBytes(3, encodeExecuteResponse(*event.Result))
+	case event.Chunk != "":
+		w.rawBytes(2, []byte(event.Chunk))
+	default:
+		w.rawBytes(1, []byte(event.Stage))
+	}
+	return w.buf
+}
+
+func decodeExecuteEvent(data []byte) (ExecuteEvent, error) {
+	var event ExecuteEvent
+	err := readProto(data, func(f protoField) error {
+		switch f.number {
+		case 1:
+			event = ExecuteEvent{Stage: f.string()}
+		case 2:
+			event = ExecuteEvent{Chunk: f.string()}
+		case 3:
+			result, err := decodeExecuteResponse(f.data)
+			if err != nil {
+				return err
+			}
+			event = ExecuteEvent{Result: &result}
+		}
+		return nil
+	})
+	return event, err
+}
+
+func encodeStopRequest(stop kernel.StopRequest) []byte {
+	w := &protoWriter{}
+	w.string(1, stop.Scope)
+	w.string(2, stop.Target)
+	w.string(3, stop.Reason)
+	return w.buf
+}
+
+func decodeStopRequest(data []byte) (kernel.StopRequest, error) {
+	var stop kernel.StopRequest
+	err := readProto(data, func(f protoField) error {
+		switch f.number {
+		case 1:
+			stop.Scope = f.string()
+		case 2:
+			stop.Target = f.string()
+		case 3:
+			stop.Reason = f.string()
+		}
+		return nil
+	})
+	return stop, err
+}
+
+func encodeStopSummary(summary kernel.StopSummary) []byte {
+	w := &protoWriter{}
+	w.string(1, summary.Scope)
+	w.string(2, summary.Target)
+	w.string(3, summary.Reason)
+	w.int64(4, int64(summary.TokensRevoked))
+	w.strings(5, summary.TokenDigests)
+	w.int64(6, int64(summary.CallsCancelled))
+	return w.buf
+}
+
+func decodeStopSummary(data []byte) (kernel.StopSummary, error) {
+	summary := kernel.StopSummary{TokenDigests: []string{}}
+	err := readProto(data, func(f protoField) error {
+		switch f.number {
+		case 1:
+			summary.Scope = f.string()
+		case 2:
+			summary.Target = f.string()
+		case 3:
+			summary.Reason = f.string()
+		case 4:
+			summary.TokensRevoked = f.int32()
+		case 5:
+			summary.TokenDigests = append(summary.TokenDigests, f.string())
+		case 6:
+			summary.CallsCancelled = f.int32()
+		}
+		return nil
+	})
+	return summary, err
+}
+
+func encodeReceiptFilter(filter audit.ReceiptFilter) []byte {
+	w := &protoWriter{}
+	w.strings(1, filter.EventTypes)
+	w.string(2, filter.TokenDigest)
+	w.string(3, filter.RequestID)
+	w.string(4, filter.Decision)
+	w.string(5, string(filter.MinSeverity))
+	w.int64(6, filter.Since)
+	w.int64(7, filter.Until)
+	w.int64(8, filter.FromSequence)
+	w.int64(9, int64(filter.Limit))
+	return w.buf
+}
+
+func decodeReceiptFilter(data []byte) (audit.ReceiptFilter, error) {
+	var filter audit.ReceiptFilter
+	err := readProto(data, func(f protoField) error {
+		switch f.number {
+		case 1:
+			filter.EventTypes = append(filter.EventTypes, f.string())
+		case 2:
+			filter.TokenDigest = f.string()
+		case 3:
+			filter.RequestID = f.string()
+		case 4:
+			filter.Decision = f.string()
+		case 5:
+			filter.MinSeverity = audit.Severity(f.string())
+		case 6:
+			filter.Since = f.int64()
+		case 7:
+			filter.Until = f.int64()
+		case 8:
+			filter.FromSequence = f.int64()
+		case 9:
+			filter.Limit = f.int32()
+		}
+		return nil
+	})
+	return filter, err
+}
+
+func encodeReceiptsResponse(page ReceiptsResponse) ([]byte, error) {
+	w := &protoWriter{}
+	for _, receipt := range page.Receipts {
+		eventData, err := json.Marshal(receipt.EventData)
+		if err != nil {
+			return nil, fmt.Errorf("receipt %d: %w", receipt.Sequence, err)
+		}
+		r := &protoWriter{}
+		r.int64(1, receipt.Sequence)
+		r.int64(2, receipt.Timestamp)
+		r.string(3, receipt.EventType)
+		r.bytes(4, eventData)
+		r.string(5, receipt.PrevHash)
+		r.string(6, receipt.CurrentHash)
+		r.string(7, string(receipt.Severity))
+		r.string(8, string(receipt.Category))
+		r.bytes(9, receipt.Signature)
+		r.string(10, receipt.SignerKeyID)
+		r.int64(11, int64(receipt.HashVersion))
+		r.int64(12, int64(receipt.SchemaVersion))
+		w.rawBytes(1, r.buf)
+	}
+	w.int64(2, page.NextSequence)
+	w.bool(3, page.HasMore)
+	return w.buf, nil
+}
+
+func decodeReceiptsResponse(data []byte) (ReceiptsResponse, error) {
+	page := ReceiptsResponse{Receipts: []audit.Receipt{}}
+	err := readProto(data, func(f protoField) error {
+		switch f.number {
+		case 1:
+			var receipt audit.Receipt
+			err := readProto(f.data, func(g protoField) error {
+				switch g.number {
+				case 1:
+					receipt.Sequence = g.int64()
+				case 2:
+					receipt.Timestamp = g.int64()
+				case 3:
+					receipt.EventType = g.string()
+				case 4:
+					if err := json.Unmarshal(g.data, &receipt.EventData); err != nil {
+						return fmt.Errorf("receipt event data: %w", err)
+					}
+				case 5:
+					receipt.PrevHash = g.string()
+				case 6:
+					receipt.CurrentHash = g.string()
+				case 7:
+					receipt.Severity = audit.Severity(g.string())
+				case 8:
+					receipt.Category = audit.Category(g.string())
+				case 9:
+					receipt.Signature = append([]byte{}, g.data...)
+				case 10:
+					receipt.SignerKeyID = g.string()
+				case 11:
+					receipt.HashVersion = g.int32()
+				case 12:
+					receipt.SchemaVersion = g.int32()
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			page.Receipts = append(page.Receipts, receipt)
+		case 2:
+			page.NextSequence = f.int64()
+		case 3:
+			page.HasMore = f.value != 0
+		}
+		return nil
+	})
+	return page, err
+}
+
+func encodeIntrospectTokenRequest(digest string) []byte {
+	w := &protoWriter{}
+	w.string(1, digest)
+	return w.buf
+}
+
+func decodeIntrospectTokenRequest(data []byte) (string, error) {
+	var digest string
+	err := readProto(data, func(f protoField) error {
+		if f.number == 1 {
+			digest = f.string()
+		}
+		return nil
+	})
+	return digest, err
+}
+
+func encodeTokenInfo(info kernel.TokenInfo) []byte {
+	w := &protoWriter{}
+	w.string(1, info.Digest)
+	w.strings(2, info.Scope)
+	w.string(3, info.PrincipalID)
+	w.string(4, info.NamespaceID)
+	w.int64(5, info.IssuedAt.Unix())
+	w.int64(6, info.ExpiresAt.Unix())
+	w.bool(7, info.Active)
+	if info.RevokedAt != nil {
+		w.int64(8, info.RevokedAt.Unix())
+	}
+	return w.buf
+}
+
+func decodeTokenInfo(data []byte) (kernel.TokenInfo, error) {
+	info := kernel.TokenInfo{Scope: []string{}}
+	err := readProto(data, func(f protoField) error {
+		switch f.number {
+		case 1:
+			info.Digest = f.string()
+		case 2:
+			info.Scope = append(info.Scope, f.string())
+		case 3:
+			info.PrincipalID = f.string()
+		case 4:
+			info.NamespaceID = f.string()
+		case 5:
+			info.IssuedAt = time.Unix(f.int64(), 0)
+		case 6:
+			info.ExpiresAt = time.Unix(f.int64(), 0)
+		case 7:
+			info.Active = f.value != 0
+		case 8:
+			revokedAt := time.Unix(f.int64(), 0)
+			info.RevokedAt = &revokedAt
+		}
+		return nil
+	})
+	return info, err
+}
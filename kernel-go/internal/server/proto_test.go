@@ -0,0 +1,100 @@
+// WHY: Proves each message of the OIKernel contract survives a round trip
+// through the codec, that proto3 defaults are omitted, and that unknown
+// fields are skipped so the contract can grow.
+package server
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/kernel"
+)
+
+// TestMessagesRoundTrip proves every message decodes to what was encoded
+func TestMessagesRoundTrip(t *testing.T) {
+	req := ExecuteRequest{Input: "test request", Metadata: map[string]interface{}{"lang": "en"}, NamespaceID: "tenant_a", SessionID: "s1"}
+	if got, err := decodeExecuteRequest(encodeExecuteRequest(req)); err != nil || !reflect.DeepEqual(got, req) {
+		t.Fatalf("ExecuteRequest: %+v %v", got, err)
+	}
+
+	resp := ExecuteResponse{
+		RequestID:  "req_1",
+		Success:    true,
+		Content:    "héllo",
+		AuditTrail: []string{"ingress", "egress"},
+		Receipts:   []ReceiptSummary{{Sequence: 0, EventType: "token_mint", Severity: audit.SeverityInfo, CurrentHash: "abc"}},
+	}
+	if got, err := decodeExecuteResponse(encodeExecuteResponse(resp)); err != nil || !reflect.DeepEqual(got, resp) {
+		t.Fatalf("ExecuteResponse: %+v %v", got, err)
+	}
+
+	for _, event := range []ExecuteEvent{{Stage: "ingress"}, {Stage: ""}, {Chunk: "out"}, {Result: &resp}} {
+		if got, err := decodeExecuteEvent(encodeExecuteEvent(event)); err != nil || !reflect.DeepEqual(got, event) {
+			t.Fatalf("ExecuteEvent %+v: %+v %v", event, got, err)
+		}
+	}
+
+	stop := kernel.StopRequest{Scope: audit.StopScopeToken, Target: "digest", Reason: audit.StopReasonUserPanic}
+	if got, err := decodeStopRequest(encodeStopRequest(stop)); err != nil || got != stop {
+		t.Fatalf("StopRequest: %+v %v", got, err)
+	}
+	summary := kernel.StopSummary{Scope: audit.StopScopeGlobal, Reason: audit.StopReasonOperatorAction, TokensRevoked: 2, TokenDigests: []string{"a", "b"}, CallsCancelled: 1}
+	if got, err := decodeStopSummary(encodeStopSummary(summary)); err != nil || !reflect.DeepEqual(got, summary) {
+		t.Fatalf("StopSummary: %+v %v", got, err)
+	}
+
+	filter := audit.ReceiptFilter{EventTypes: []string{"token_mint", "stop"}, RequestID: "req_1", MinSeverity: audit.SeverityWarn, Since: 10, FromSequence: 3, Limit: 50}
+	if got, err := decodeReceiptFilter(encodeReceiptFilter(filter)); err != nil || !reflect.DeepEqual(got, filter) {
+		t.Fatalf("ReceiptFilter: %+v %v", got, err)
+	}
+
+	page := ReceiptsResponse{
+		Receipts: []audit.Receipt{{
+			Sequence: 4, Timestamp: 1700000000, EventType: "token_mint", EventData: map[string]interface{}{"token_digest": "abc"},
+			PrevHash: "p", CurrentHash: "c", Severity: audit.SeverityInfo, Category: audit.CategoryCapability,
+			Signature: []byte{1, 2, 3}, SignerKeyID: "audit", HashVersion: 2, SchemaVersion: 1,
+		}},
+		NextSequence: 5,
+		HasMore:      true,
+	}
+	encoded, err := encodeReceiptsResponse(page)
+	if err != nil {
+		t.Fatalf("encode receipts: %v", err)
+	}
+	if got, err := decodeReceiptsResponse(encoded); err != nil || !reflect.DeepEqual(got, page) {
+		t.Fatalf("GetReceiptsResponse: %+v %v", got, err)
+	}
+
+	issued := time.Unix(1700000000, 0)
+	revoked := issued.Add(time.Minute)
+	info := kernel.TokenInfo{Digest: "abc", Scope: []string{"model:invoke"}, PrincipalID: "alice", NamespaceID: "tenant_a", IssuedAt: issued, ExpiresAt: issued.Add(time.Hour), RevokedAt: &revoked}
+	if got, err := decodeTokenInfo(encodeTokenInfo(info)); err != nil || !reflect.DeepEqual(got, info) {
+		t.Fatalf("TokenInfo: %+v %v", got, err)
+	}
+}
+
+// TestDefaultsAreOmittedAndUnknownFieldsSkipped proves an empty message
+// encodes to nothing and a field from a newer peer is ignored
+func TestDefaultsAreOmittedAndUnknownFieldsSkipped(t *testing.T) {
+	if encoded := encodeStopRequest(kernel.StopRequest{}); len(encoded) != 0 {
+		t.Fatalf("an empty message must encode to nothing, got %x", encoded)
+	}
+
+	w := &protoWriter{}
+	w.string(1, "digest")
+	w.varint(20, 7)
+	w.string(21, "future")
+	w.buf = binary.AppendUvarint(w.buf, 22<<3|wireFixed64)
+	w.buf = append(w.buf, make([]byte, 8)...)
+	digest, err := decodeIntrospectTokenRequest(w.buf)
+	if err != nil || digest != "digest" {
+		t.Fatalf("unknown fields must be skipped, got %q %v", digest, err)
+	}
+
+	if _, err := decodeIntrospectTokenRequest([]byte{0x0a, 0x05, 'a'}); err == nil {
+		t.Fatalf("a truncated field must be refused")
+	}
+}
@@ -0,0 +1,308 @@
+// WHY: The corridor could only be reached by linking the kernel or by a
+// one-shot CLI flag. Server exposes it over HTTP for callers in other
+// processes, adding no authority of its own: every request still goes
+// through kernel.Execute as the principal its bearer token names, reads
+// are confined to the caller's own tokens and receipts, and STOP is the
+// kernel's own admin endpoint. Bodies are size-limited and an optional
+// auth hook can refuse a request before the kernel sees it.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/kernel"
+)
+
+// DefaultMaxBodyBytes bounds a request body unless Options says otherwise
+const DefaultMaxBodyBytes = 1 << 20
+
+// DefaultReadTimeout bounds how long a stream waits for its request
+// message unless Options says otherwise
+const DefaultReadTimeout = 10 * time.Second
+
+// AuthHook vets an HTTP request before it reaches the kernel, e.g. for a
+// client certificate or a network policy; a non-nil error refuses it with
+// 401. It cannot grant anything: the bearer token still names the
+// principal.
+type AuthHook func(r *http.Request) error
+
+// Options tunes a Server
+type Options struct {
+	// MaxBodyBytes bounds a request body (default DefaultMaxBodyBytes)
+	MaxBodyBytes int64
+
+	// ReadTimeout bounds how long a stream waits for its request message,
+	// which carries its bearer token (default DefaultReadTimeout)
+	ReadTimeout time.Duration
+
+	// Auth, if set, vets every request but the health probes first
+	Auth AuthHook
+}
+
+// ExecuteRequest is the body of POST /v1/execute
+type ExecuteRequest struct {
+	Input    string                 `json:"input"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+
+	// NamespaceID and SessionID are as in kernel.Request
+	NamespaceID string `json:"namespace_id,omitempty"`
+	SessionID   string `json:"session_id,omitempty"`
+}
+
+// ExecuteResponse is the result of POST /v1/execute with the receipts the
+// request left
+type ExecuteResponse struct {
+	RequestID  string           `json:"request_id"`
+	Success    bool             `json:"success"`
+	Content    string           `json:"content,omitempty"`
+	Error      string           `json:"error,omitempty"`
+	AuditTrail []string         `json:"audit_trail"`
+	Receipts   []ReceiptSummary `json:"receipts"`
+}
+
+// ReceiptSummary identifies one receipt in the ledger
+type ReceiptSummary struct {
+	Sequence    int64          `json:"sequence"`
+	EventType   string         `json:"event_type"`
+	Severity    audit.Severity `json:"severity,omitempty"`
+	CurrentHash string         `json:"current_hash"`
+}
+
+// ReceiptsResponse is one page of GET /v1/receipts
+type ReceiptsResponse struct {
+	Receipts     []audit.Receipt `json:"receipts"`
+	NextSequence int64           `json:"next_sequence,omitempty"`
+	HasMore      bool            `json:"has_more"`
+}
+
+// Server serves the corridor over HTTP
+type Server struct {
+	state   *kernel.SystemState
+	options Options
+	mux     *http.ServeMux
+	health  http.Handler
+}
+
+// New creates a server for state. WHY: Without an identity verifier set on
+// state every request is refused, since nothing could name its principal.
+func New(state *kernel.SystemState, options Options) *Server {
+	if options.MaxBodyBytes <= 0 {
+		options.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+	if options.ReadTimeout <= 0 {
+		options.ReadTimeout = DefaultReadTimeout
+	}
+	s := &Server{state: state, options: options, mux: http.NewServeMux(), health: state.HealthHandler()}
+	s.mux.HandleFunc("POST /v1/execute", s.execute)
+	s.mux.HandleFunc("GET /v1/execute/stream", s.executeStream)
+	s.mux.Handle("POST /v1/stop", state.StopHandler())
+	s.mux.HandleFunc("GET /v1/receipts", s.receipts)
+	s.mux.HandleFunc("GET /v1/tokens/{digest}", s.token)
+	return s
+}
+
+// ServeHTTP vets and size-limits the request, then routes it. WHY: An
+// orchestrator's probes carry no credential, and the health probes grant
+// nothing and name no principal, so they skip the auth hook.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/healthz" || r.URL.Path == "/readyz" {
+		s.health.ServeHTTP(w, r)
+		return
+	}
+	if s.options.Auth != nil {
+		if err := s.options.Auth(r); err != nil {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+			return
+		}
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, s.options.MaxBodyBytes)
+	s.mux.ServeHTTP(w, r)
+}
+
+// execute runs one request through the corridor as the bearer's principal
+func (s *Server) execute(w http.ResponseWriter, r *http.Request) {
+	bearer := bearerToken(r)
+	if bearer == "" {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "a bearer token is required"})
+		return
+	}
+	var req ExecuteRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if req.Metadata == nil {
+		req.Metadata = map[string]interface{}{}
+	}
+
+	resp, err := kernel.Execute(&kernel.Request{
+		RawInput:    req.Input,
+		Metadata:    req.Metadata,
+		NamespaceID: req.NamespaceID,
+		SessionID:   req.SessionID,
+		BearerToken: bearer,
+	}, s.state)
+	if resp == nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("execute: %v", err)})
+		return
+	}
+
+	result := ExecuteResponse{
+		RequestID:  resp.RequestID,
+		Success:    resp.Success,
+		Content:    resp.Content,
+		Error:      resp.Error,
+		AuditTrail: resp.AuditTrail,
+		Receipts:   requestReceipts(s.state, resp.RequestID),
+	}
+	writeJSON(w, executeStatus(resp), result)
+}
+
+// requestReceipts summarizes the receipts attributed to requestID. WHY:
+// The server minted the request ID, so they are all the caller's own.
+func requestReceipts(state *kernel.SystemState, requestID string) []ReceiptSummary {
+	summaries := []ReceiptSummary{}
+	filter := audit.ReceiptFilter{RequestID: requestID, Limit: audit.MaxQueryLimit}
+	for {
+		page, err := state.AuditLedger.Query(filter)
+		if err != nil {
+			return summaries
+		}
+		for _, receipt := range page.Receipts {
+			summaries = append(summaries, ReceiptSummary{
+				Sequence:    receipt.Sequence,
+				EventType:   receipt.EventType,
+				Severity:    receipt.Severity,
+				CurrentHash: receipt.CurrentHash,
+			})
+		}
+		if !page.HasMore {
+			return summaries
+		}
+		filter.FromSequence = page.NextSequence
+	}
+}
+
+// executeStatus is the HTTP status of a corridor response: refused
+// identity is 401, a quiescing kernel 503, and any other failure a
+// refusal by the corridor
+func executeStatus(resp *kernel.Response) int {
+	switch {
+	case resp.Success:
+		return http.StatusOK
+	case strings.HasPrefix(resp.Error, "authentication_failed"):
+		return http.StatusUnauthorized
+	case strings.HasPrefix(resp.Error, "quiesced"):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusForbidden
+	}
+}
+
+// receipts serves a page of the caller's own receipts. Query parameters:
+// type (comma-separated), token, request, decision, severity, since,
+// until, from, and limit.
+func (s *Server) receipts(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseReceiptFilter(r.URL.Query())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	page, err := s.state.QueryReceipts(bearerToken(r), filter)
+	if err != nil {
+		writeJSON(w, accessStatus(err), map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, ReceiptsResponse{Receipts: page.Receipts, NextSequence: page.NextSequence, HasMore: page.HasMore})
+}
+
+// token serves the introspection of one of the caller's tokens
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	info, err := s.state.IntrospectToken(bearerToken(r), r.PathValue("digest"))
+	if err != nil {
+		writeJSON(w, accessStatus(err), map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, info)
+}
+
+// parseReceiptFilter reads a receipt filter from query parameters
+func parseReceiptFilter(query url.Values) (audit.ReceiptFilter, error) {
+	filter := audit.ReceiptFilter{
+		TokenDigest: query.Get("token"),
+		RequestID:   query.Get("request"),
+		Decision:    query.Get("decision"),
+		MinSeverity: audit.Severity(query.Get("severity")),
+	}
+	if types := query.Get("type"); types != "" {
+		filter.EventTypes = strings.Split(types, ",")
+	}
+	integers := map[string]*int64{"since": &filter.Since, "until": &filter.Until, "from": &filter.FromSequence}
+	for name, field := range integers {
+		if value := query.Get(name); value != "" {
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return audit.ReceiptFilter{}, fmt.Errorf("%s: %v", name, err)
+			}
+			*field = parsed
+		}
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return audit.ReceiptFilter{}, fmt.Errorf("limit: %v", err)
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
+
+// accessStatus is the HTTP status of a refused read
+func accessStatus(err error) int {
+	var refused *kernel.AccessRefusedError
+	if !errors.As(err, &refused) {
+		return http.StatusInternalServerError
+	}
+	switch refused.Reason {
+	case kernel.StopRefusedUnauthenticated:
+		return http.StatusUnauthorized
+	case kernel.StopRefusedForbidden:
+		return http.StatusForbidden
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// bearerToken returns the bearer token in the Authorization header
+func bearerToken(r *http.Request) string {
+	bearer, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return bearer
+}
+
+// writeDecodeError reports a body that could not be read: 413 if it was
+// too large, 400 otherwise
+func writeDecodeError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit)})
+		return
+	}
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("request body: %v", err)})
+}
+
+// writeJSON writes body as JSON with status
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
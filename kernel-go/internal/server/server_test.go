@@ -0,0 +1,163 @@
+// WHY: Proves the HTTP surface runs requests through the corridor as the
+// bearer's principal, returns each request's receipts, and confines
+// reads, STOP, and body sizes the way the kernel does.
+package server
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/adapters"
+	"github.com/user/oi/kernel-go/internal/identity"
+	"github.com/user/oi/kernel-go/internal/kernel"
+)
+
+// newServerKernel returns a kernel that verifies bearer tokens signed
+// with the returned key
+func newServerKernel(t *testing.T) (*kernel.SystemState, ed25519.PrivateKey) {
+	t.Helper()
+	state := kernel.NewSystemState("kernel_principal", "kernel_namespace")
+	state.AdapterRegistry.Register(adapters.NewMockAdapter("mock_adapter"))
+	state.GovernanceCapsule.Rules = map[string]interface{}{"exists": true}
+	public, key, _ := ed25519.GenerateKey(rand.Reader)
+	if err := state.SetIdentityVerifier(&identity.Verifier{
+		Issuer:   "https://issuer.example",
+		Audience: "oi-kernel",
+		Keys:     identity.KeySet{"issuer_key": public},
+	}); err != nil {
+		t.Fatalf("set verifier: %v", err)
+	}
+	return state, key
+}
+
+// bearerFor signs a bearer token for subject in namespace
+func bearerFor(key ed25519.PrivateKey, subject string, namespace string) string {
+	head, _ := json.Marshal(map[string]string{"alg": identity.AlgorithmEdDSA, "kid": "issuer_key"})
+	body, _ := json.Marshal(map[string]interface{}{
+		"iss": "https://issuer.example", "aud": "oi-kernel", "sub": subject, "namespace": namespace, "exp": time.Now().Add(time.Hour).Unix(),
+	})
+	signed := base64.RawURLEncoding.EncodeToString(head) + "." + base64.RawURLEncoding.EncodeToString(body)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(ed25519.Sign(key, []byte(signed)))
+}
+
+// call sends method to path on server with bearer and body, and decodes
+// the JSON response into out
+func call(t *testing.T, server http.Handler, method string, path string, bearer string, body string, out interface{}) int {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	if out != nil {
+		if err := json.NewDecoder(rec.Body).Decode(out); err != nil {
+			t.Fatalf("%s %s: decode: %v", method, path, err)
+		}
+	}
+	return rec.Code
+}
+
+// TestExecuteReturnsTheRequestsReceipts proves a request runs as the
+// bearer's principal and comes back with the receipts it left
+func TestExecuteReturnsTheRequestsReceipts(t *testing.T) {
+	state, key := newServerKernel(t)
+	server := New(state, Options{})
+	alice := bearerFor(key, "alice", "tenant_a")
+
+	if code := call(t, server, http.MethodPost, "/v1/execute", "", `{"input":"test request"}`, nil); code != http.StatusUnauthorized {
+		t.Fatalf("a request without a bearer token must be refused, got %d", code)
+	}
+	var refused ExecuteResponse
+	if code := call(t, server, http.MethodPost, "/v1/execute", bearerFor(key, "alice", "tenant_a")+"x", `{"input":"test request"}`, &refused); code != http.StatusUnauthorized || refused.Success {
+		t.Fatalf("an unverifiable bearer must be refused, got %d %+v", code, refused)
+	}
+
+	var result ExecuteResponse
+	if code := call(t, server, http.MethodPost, "/v1/execute", alice, `{"input":"test request"}`, &result); code != http.StatusOK || !result.Success {
+		t.Fatalf("execute: %d %+v", code, result)
+	}
+	mints := 0
+	for _, receipt := range result.Receipts {
+		if receipt.EventType == "token_mint" {
+			mints++
+		}
+	}
+	if result.RequestID == "" || mints != 1 {
+		t.Fatalf("the response must carry the request's receipts, got %+v", result)
+	}
+
+	var page ReceiptsResponse
+	if code := call(t, server, http.MethodGet, "/v1/receipts?type=token_mint&request="+result.RequestID, alice, "", &page); code != http.StatusOK || len(page.Receipts) != 1 {
+		t.Fatalf("alice must read her request's mint, got %d %+v", code, page)
+	}
+	digest, _ := page.Receipts[0].EventData["token_digest"].(string)
+	var info kernel.TokenInfo
+	if code := call(t, server, http.MethodGet, "/v1/tokens/"+digest, alice, "", &info); code != http.StatusOK || info.PrincipalID != "alice" {
+		t.Fatalf("alice must introspect her token, got %d %+v", code, info)
+	}
+	bob := bearerFor(key, "bob", "tenant_a")
+	if code := call(t, server, http.MethodGet, "/v1/tokens/"+digest, bob, "", nil); code != http.StatusForbidden {
+		t.Fatalf("bob must not introspect alice's token, got %d", code)
+	}
+	if code := call(t, server, http.MethodGet, "/v1/receipts?limit=many", alice, "", nil); code != http.StatusBadRequest {
+		t.Fatalf("a malformed query must be refused, got %d", code)
+	}
+
+	var summary kernel.StopSummary
+	if code := call(t, server, http.MethodPost, "/v1/stop", alice, `{"scope":"principal"}`, &summary); code != http.StatusOK || summary.TokensRevoked != 1 {
+		t.Fatalf("alice's STOP must revoke her token, got %d %+v", code, summary)
+	}
+	if code := call(t, server, http.MethodPost, "/v1/execute", alice, `{"input":"test request"}`, &result); code != http.StatusForbidden || result.Success {
+		t.Fatalf("a request under alice's STOP must be refused, got %d %+v", code, result)
+	}
+}
+
+// TestServerLimitsAndVets proves oversized bodies are refused, the auth
+// hook runs before the kernel sees anything, and the health probes answer
+// without it
+func TestServerLimitsAndVets(t *testing.T) {
+	state, key := newServerKernel(t)
+	alice := bearerFor(key, "alice", "tenant_a")
+
+	server := New(state, Options{MaxBodyBytes: 64})
+	body := fmt.Sprintf(`{"input":%q}`, strings.Repeat("a", 100))
+	if code := call(t, server, http.MethodPost, "/v1/execute", alice, body, nil); code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("an oversized body must be refused, got %d", code)
+	}
+	if code := call(t, server, http.MethodPost, "/v1/execute", alice, `{"input":"x","admin":true}`, nil); code != http.StatusBadRequest {
+		t.Fatalf("an unknown field must be refused, got %d", code)
+	}
+
+	vetted := New(state, Options{Auth: func(r *http.Request) error {
+		if r.Header.Get("X-Client-Cert") == "" {
+			return fmt.Errorf("no client certificate")
+		}
+		return nil
+	}})
+	if code := call(t, vetted, http.MethodPost, "/v1/execute", alice, `{"input":"test request"}`, nil); code != http.StatusUnauthorized {
+		t.Fatalf("the auth hook must refuse the request, got %d", code)
+	}
+	if code := call(t, vetted, http.MethodGet, "/v1/execute", alice, "", nil); code != http.StatusUnauthorized {
+		t.Fatalf("the auth hook must run before routing, got %d", code)
+	}
+	var health kernel.Health
+	if code := call(t, vetted, http.MethodGet, "/readyz", "", "", &health); code != http.StatusOK || !health.Ready {
+		t.Fatalf("an orchestrator's probe must not need the auth hook's credential, got %d %+v", code, health)
+	}
+	state.RevokeAllTokens()
+	if code := call(t, vetted, http.MethodGet, "/readyz", "", "", nil); code != http.StatusServiceUnavailable {
+		t.Fatalf("a kernel under a global STOP must not be ready, got %d", code)
+	}
+	if code := call(t, vetted, http.MethodGet, "/healthz", "", "", nil); code != http.StatusOK {
+		t.Fatalf("a kernel under a global STOP is still alive, got %d", code)
+	}
+}
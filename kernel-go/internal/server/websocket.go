@@ -0,0 +1,368 @@
+// WHY: An interactive frontend wants to show a request moving through the
+// corridor, judging then executing then shaping, and the response as it
+// arrives, which one JSON reply cannot do. GET /v1/execute/stream upgrades
+// to a WebSocket (RFC 6455, written here to keep the kernel stdlib-only),
+// takes one request, and streams each audit trail stage as the corridor
+// reaches it, then the egress-shaped output in chunks, then the result.
+// Output is never streamed before egress has shaped it. The stream
+// carries no ambient credential: the bearer token names the principal as
+// on every other route.
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/kernel"
+)
+
+// websocketGUID is appended to the client's key to accept a handshake
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// closeWait bounds how long a closing stream waits for the client's
+// close frame
+const closeWait = time.Second
+
+// WebSocket opcodes
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// WebSocket close codes
+const (
+	closeNormal        = 1000
+	closeProtocolError = 1002
+	closeInvalidData   = 1007
+	closePolicy        = 1008
+	closeTooBig        = 1009
+	closeInternalError = 1011
+)
+
+// StreamRequest is the one message a client sends on /v1/execute/stream.
+// WHY: Browsers cannot set an Authorization header on a WebSocket, so the
+// bearer token may come in the message instead.
+type StreamRequest struct {
+	ExecuteRequest
+	Bearer string `json:"bearer,omitempty"`
+}
+
+// StreamEvent is one message the server sends on /v1/execute/stream
+type StreamEvent struct {
+	// Type is "stage", "chunk", "result", or "error"
+	Type string `json:"type"`
+
+	// Stage is the audit trail entry reached and Phase the part of the
+	// corridor it belongs to: "ingress", "judging", "executing", or
+	// "shaping"
+	Stage string `json:"stage,omitempty"`
+	Phase string `json:"phase,omitempty"`
+
+	// Chunk is part of the shaped output
+	Chunk string `json:"chunk,omitempty"`
+
+	// Result is the response without its content, which came in chunks;
+	// Status is the HTTP status /v1/execute would have answered with
+	Result *ExecuteResponse `json:"result,omitempty"`
+	Status int              `json:"status,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// executeStream runs one request through the corridor over a WebSocket
+func (s *Server) executeStream(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgradeWebSocket(w, r, s.options.ReadTimeout)
+	if err != nil {
+		return
+	}
+	defer conn.close()
+
+	message, err := conn.readText(s.options.MaxBodyBytes)
+	if err != nil {
+		code, reason := closeProtocolError, err.Error()
+		var netErr net.Error
+		switch {
+		case errors.Is(err, errFrameTooLarge):
+			code = closeTooBig
+		case errors.As(err, &netErr) && netErr.Timeout():
+			code, reason = closePolicy, "no request message in time"
+		}
+		conn.closeWith(code, reason)
+		return
+	}
+	var req StreamRequest
+	decoder := json.NewDecoder(bytes.NewReader(message))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		conn.send(StreamEvent{Type: "error", Status: http.StatusBadRequest, Error: fmt.Sprintf("request message: %v", err)})
+		conn.closeWith(closeInvalidData, "malformed request")
+		return
+	}
+	bearer := bearerToken(r)
+	if bearer == "" {
+		bearer = req.Bearer
+	}
+	if bearer == "" {
+		conn.send(StreamEvent{Type: "error", Status: http.StatusUnauthorized, Error: "a bearer token is required"})
+		conn.closeWith(closePolicy, "unauthenticated")
+		return
+	}
+	if req.Metadata == nil {
+		req.Metadata = map[string]interface{}{}
+	}
+
+	resp, err := kernel.Execute(&kernel.Request{
+		RawInput:    req.Input,
+		Metadata:    req.Metadata,
+		NamespaceID: req.NamespaceID,
+		SessionID:   req.SessionID,
+		BearerToken: bearer,
+		OnStage: func(stage string) {
+			conn.send(StreamEvent{Type: "stage", Stage: stage, Phase: stagePhase(stage)})
+		},
+	}, s.state)
+	if resp == nil {
+		conn.send(StreamEvent{Type: "error", Status: http.StatusInternalServerError, Error: fmt.Sprintf("execute: %v", err)})
+		conn.closeWith(closeInternalError, "execute failed")
+		return
+	}
+
+	for _, chunk := range chunkContent(resp.Content, streamChunkBytes) {
+		if err := conn.send(StreamEvent{Type: "chunk", Chunk: chunk}); err != nil {
+			return
+		}
+	}
+	conn.send(StreamEvent{
+		Type: "result",
+		Result: &ExecuteResponse{
+			RequestID:  resp.RequestID,
+			Success:    resp.Success,
+			Error:      resp.Error,
+			AuditTrail: resp.AuditTrail,
+			Receipts:   requestReceipts(s.state, resp.RequestID),
+		},
+		Status: executeStatus(resp),
+	})
+	conn.closeWith(closeNormal, "")
+}
+
+// stagePhase is the part of the corridor an audit trail stage belongs to
+func stagePhase(stage string) string {
+	switch {
+	case strings.HasPrefix(stage, "cif_ingress"):
+		return "ingress"
+	case strings.HasPrefix(stage, "cdi_output_decision"), strings.HasPrefix(stage, "cif_egress"):
+		return "shaping"
+	case strings.HasPrefix(stage, "cdi_decision"), stage == "deny_terminal":
+		return "judging"
+	default:
+		return "executing"
+	}
+}
+
+// errFrameTooLarge reports a message over the stream's size limit
+var errFrameTooLarge = errors.New("message exceeds the size limit")
+
+// wsConn is the server side of one WebSocket
+type wsConn struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+}
+
+// upgradeWebSocket completes the opening handshake of r, answering a
+// request that is not one with 400, and gives the client readTimeout to
+// send its request message
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request, readTimeout time.Duration) (*wsConn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if !headerHasToken(r.Header, "Connection", "upgrade") || !headerHasToken(r.Header, "Upgrade", "websocket") ||
+		r.Header.Get("Sec-WebSocket-Version") != "13" || key == "" {
+		err := errors.New("a WebSocket upgrade (version 13) is required")
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return nil, err
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		err := errors.New("the connection cannot be upgraded")
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return nil, err
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	// WHY: The server's deadlines were set for the HTTP request. The
+	// bearer token only arrives with the request message, so until then
+	// the client is anonymous and gets readTimeout to send it, or anyone
+	// could hold a hijacked connection open. Nothing is read after that
+	// message but the close, which sets its own deadline, so the deadline
+	// is never lifted.
+	conn.SetDeadline(time.Time{})
+	conn.SetReadDeadline(time.Now().Add(readTimeout))
+
+	digest := sha1.Sum([]byte(key + websocketGUID))
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", base64.StdEncoding.EncodeToString(digest[:]))
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &wsConn{conn: conn, rw: rw}, nil
+}
+
+// headerHasToken reports whether the comma-separated header name lists
+// token, case-insensitively
+func headerHasToken(header http.Header, name string, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readText reads one text message of at most limit bytes, answering
+// pings on the way
+func (c *wsConn) readText(limit int64) ([]byte, error) {
+	var message []byte
+	started := false
+	for {
+		fin, opcode, payload, err := c.readFrame(limit - int64(len(message)))
+		if err != nil {
+			return nil, err
+		}
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			return nil, errors.New("the client closed the stream")
+		case opText:
+			if started {
+				return nil, errors.New("a new message began inside a fragmented one")
+			}
+			started = true
+		case opContinuation:
+			if !started {
+				return nil, errors.New("a continuation frame began a message")
+			}
+		default:
+			return nil, fmt.Errorf("opcode %d is not a text message", opcode)
+		}
+		message = append(message, payload...)
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+// readFrame reads one masked client frame with a payload of at most limit
+// bytes
+func (c *wsConn) readFrame(limit int64) (bool, byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.rw, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin, opcode := head[0]&0x80 != 0, head[0]&0x0F
+	if head[1]&0x80 == 0 {
+		return false, 0, nil, errors.New("client frames must be masked")
+	}
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(c.rw, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(c.rw, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+	if length > uint64(max(limit, 0)) {
+		return false, 0, nil, errFrameTooLarge
+	}
+	var mask [4]byte
+	if _, err := io.ReadFull(c.rw, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.rw, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, opcode, payload, nil
+}
+
+// writeFrame writes one unfragmented, unmasked frame
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	frame := []byte{0x80 | opcode}
+	switch {
+	case len(payload) < 126:
+		frame = append(frame, byte(len(payload)))
+	case len(payload) <= 0xFFFF:
+		frame = append(frame, 126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(len(payload)))
+	default:
+		frame = append(frame, 127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(len(payload)))
+	}
+	c.rw.Write(append(frame, payload...))
+	return c.rw.Flush()
+}
+
+// send writes event as a text message
+func (c *wsConn) send(event StreamEvent) error {
+	message, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return c.writeFrame(opText, message)
+}
+
+// closeWith starts the closing handshake with code and reason, then waits
+// briefly for the client to answer
+func (c *wsConn) closeWith(code int, reason string) {
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	if len(reason) > 123 {
+		reason = reason[:123]
+	}
+	if err := c.writeFrame(opClose, append(payload, reason...)); err != nil {
+		return
+	}
+	c.conn.SetReadDeadline(time.Now().Add(closeWait))
+	for {
+		_, opcode, _, err := c.readFrame(125)
+		if err != nil || opcode == opClose {
+			return
+		}
+	}
+}
+
+// close closes the underlying connection
+func (c *wsConn) close() {
+	c.conn.Close()
+}
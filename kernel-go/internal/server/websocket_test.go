@@ -0,0 +1,158 @@
+// WHY: Proves the WebSocket stream runs a request as the bearer's
+// principal and reports every stage before any output, and that a
+// malformed or unauthenticated stream is refused without running.
+package server
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// dialStream opens /v1/execute/stream on server, sends message as one
+// masked text frame unless it is empty, and returns the events received
+// until the close frame, with its close code
+func dialStream(t *testing.T, server *httptest.Server, header string, message string) ([]StreamEvent, int) {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	conn.Write([]byte("GET /v1/execute/stream HTTP/1.1\r\nHost: kernel\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n" + header + "\r\n"))
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil || resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake: %v %v", resp, err)
+	}
+	if accept := resp.Header.Get("Sec-WebSocket-Accept"); accept != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("the handshake must accept the key, got %q", accept)
+	}
+
+	mask := []byte{1, 2, 3, 4}
+	frame := []byte{0x80 | opText, 0x80 | 126}
+	frame = binary.BigEndian.AppendUint16(frame, uint16(len(message)))
+	frame = append(frame, mask...)
+	for i := range len(message) {
+		frame = append(frame, message[i]^mask[i%4])
+	}
+	if message != "" {
+		conn.Write(frame)
+	}
+
+	var events []StreamEvent
+	for {
+		var head [2]byte
+		if _, err := io.ReadFull(reader, head[:]); err != nil {
+			t.Fatalf("read frame: %v", err)
+		}
+		length := int(head[1] & 0x7F)
+		switch length {
+		case 126:
+			var extended [2]byte
+			io.ReadFull(reader, extended[:])
+			length = int(binary.BigEndian.Uint16(extended[:]))
+		case 127:
+			var extended [8]byte
+			io.ReadFull(reader, extended[:])
+			length = int(binary.BigEndian.Uint64(extended[:]))
+		}
+		payload := make([]byte, length)
+		io.ReadFull(reader, payload)
+		if head[0]&0x0F == opClose {
+			conn.Write([]byte{0x80 | opClose, 0x80, 0, 0, 0, 0})
+			return events, int(binary.BigEndian.Uint16(payload))
+		}
+		var event StreamEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			t.Fatalf("event: %v", err)
+		}
+		events = append(events, event)
+	}
+}
+
+// TestStreamSendsStagesThenOutput proves the stream reports each stage
+// with its phase before any output, then the output, then the result
+func TestStreamSendsStagesThenOutput(t *testing.T) {
+	state, key := newServerKernel(t)
+	ts := httptest.NewServer(New(state, Options{}))
+	defer ts.Close()
+
+	message, _ := json.Marshal(StreamRequest{ExecuteRequest: ExecuteRequest{Input: "test request"}, Bearer: bearerFor(key, "alice", "tenant_a")})
+	events, code := dialStream(t, ts, "", string(message))
+	if code != closeNormal || len(events) == 0 {
+		t.Fatalf("the stream must end normally, got %d after %+v", code, events)
+	}
+
+	var stages, phases []string
+	var content strings.Builder
+	for _, event := range events[:len(events)-1] {
+		switch event.Type {
+		case "stage":
+			if content.Len() > 0 {
+				t.Fatalf("stage %q arrived after output", event.Stage)
+			}
+			stages = append(stages, event.Stage)
+			if len(phases) == 0 || phases[len(phases)-1] != event.Phase {
+				phases = append(phases, event.Phase)
+			}
+		case "chunk":
+			content.WriteString(event.Chunk)
+		default:
+			t.Fatalf("unexpected event %+v", event)
+		}
+	}
+	result := events[len(events)-1]
+	if result.Type != "result" || result.Status != http.StatusOK || !result.Result.Success || result.Result.Content != "" {
+		t.Fatalf("the stream must end with the result, got %+v", result)
+	}
+	if strings.Join(stages, ",") != strings.Join(result.Result.AuditTrail, ",") || content.Len() == 0 {
+		t.Fatalf("the stream must carry each stage of %v and the output, got %v and %q", result.Result.AuditTrail, stages, content.String())
+	}
+	if strings.Join(phases, ",") != "ingress,judging,executing,shaping" {
+		t.Fatalf("the phases must follow the corridor, got %v", phases)
+	}
+}
+
+// TestStreamRefusesWithoutRunning proves a stream without a bearer, with
+// a malformed request, or with no request in time leaves no receipts
+func TestStreamRefusesWithoutRunning(t *testing.T) {
+	state, key := newServerKernel(t)
+	ts := httptest.NewServer(New(state, Options{}))
+	defer ts.Close()
+
+	before := len(state.AuditLedger.GetReceipts())
+	events, code := dialStream(t, ts, "", `{"input":"test request"}`)
+	if code != closePolicy || len(events) != 1 || events[0].Status != http.StatusUnauthorized {
+		t.Fatalf("a stream without a bearer must be refused, got %d %+v", code, events)
+	}
+	events, code = dialStream(t, ts, "Authorization: Bearer "+bearerFor(key, "alice", "tenant_a")+"\r\n", `{"input":"test request","posture":5}`)
+	if code != closeInvalidData || len(events) != 1 || events[0].Status != http.StatusBadRequest {
+		t.Fatalf("a malformed request must be refused, got %d %+v", code, events)
+	}
+	if n := len(state.AuditLedger.GetReceipts()); n != before {
+		t.Fatalf("a refused stream must not reach the corridor, got %d new receipts", n-before)
+	}
+
+	silent := httptest.NewServer(New(state, Options{ReadTimeout: 50 * time.Millisecond}))
+	defer silent.Close()
+	started := time.Now()
+	events, code = dialStream(t, silent, "", "")
+	if code != closePolicy || len(events) != 0 || time.Since(started) > 2*time.Second {
+		t.Fatalf("a stream that never sends its request must be closed after the read timeout, got %d %+v", code, events)
+	}
+
+	if code := call(t, New(state, Options{}), http.MethodGet, "/v1/execute/stream", "", "", nil); code != http.StatusBadRequest {
+		t.Fatalf("a plain GET must be refused, got %d", code)
+	}
+}
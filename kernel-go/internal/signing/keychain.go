@@ -0,0 +1,133 @@
+// WHY: The OS keychain keeps the signing seed encrypted at rest and out of
+// config files. The seed is fetched per signature and wiped immediately,
+// so it never lingers in process memory between operations.
+package signing
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// KeychainReader fetches a secret from an OS credential store
+type KeychainReader interface {
+	Read(service string, account string) ([]byte, error)
+}
+
+// SystemKeychain reads secrets with the platform keychain CLI:
+// `security` on macOS and `secret-tool` (libsecret) on Linux.
+type SystemKeychain struct{}
+
+// Read returns the stored secret for service/account
+func (SystemKeychain) Read(service string, account string) ([]byte, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w")
+	case "linux":
+		cmd = exec.Command("secret-tool", "lookup", "service", service, "account", account)
+	default:
+		return nil, fmt.Errorf("no keychain support on %s", runtime.GOOS)
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("keychain lookup %s/%s: %w", service, account, err)
+	}
+	return bytes.TrimSpace(out), nil
+}
+
+// KeychainSigner signs with an Ed25519 seed stored hex-encoded in a keychain
+type KeychainSigner struct {
+	keyID   string
+	service string
+	account string
+	reader  KeychainReader
+	public  ed25519.PublicKey
+}
+
+// NewKeychainSigner loads the public key once and verifies the entry is usable
+func NewKeychainSigner(keyID string, service string, account string, reader KeychainReader) (*KeychainSigner, error) {
+	if keyID == "" {
+		return nil, fmt.Errorf("empty key ID")
+	}
+	if reader == nil {
+		reader = SystemKeychain{}
+	}
+
+	s := &KeychainSigner{
+		keyID:   keyID,
+		service: service,
+		account: account,
+		reader:  reader,
+	}
+
+	private, err := s.loadKey()
+	if err != nil {
+		return nil, err
+	}
+	s.public = append(ed25519.PublicKey(nil), private.Public().(ed25519.PublicKey)...)
+	wipe(private)
+
+	return s, nil
+}
+
+// loadKey fetches and decodes the seed; callers must wipe the result
+func (s *KeychainSigner) loadKey() (ed25519.PrivateKey, error) {
+	encoded, err := s.reader.Read(s.service, s.account)
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(encoded)
+
+	seed := make([]byte, hex.DecodedLen(len(encoded)))
+	defer wipe(seed)
+	if _, err := hex.Decode(seed, encoded); err != nil {
+		return nil, fmt.Errorf("keychain entry %s/%s is not hex: %w", s.service, s.account, err)
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("keychain entry %s/%s has seed size %d, want %d",
+			s.service, s.account, len(seed), ed25519.SeedSize)
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// KeyID returns the key identifier
+func (s *KeychainSigner) KeyID() string {
+	return s.keyID
+}
+
+// Algorithm returns AlgorithmEd25519
+func (s *KeychainSigner) Algorithm() string {
+	return AlgorithmEd25519
+}
+
+// Public returns the Ed25519 public key
+func (s *KeychainSigner) Public() crypto.PublicKey {
+	return s.public
+}
+
+// Sign fetches the seed, signs, and wipes the key material
+func (s *KeychainSigner) Sign(message []byte) ([]byte, error) {
+	private, err := s.loadKey()
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(private)
+
+	if !bytes.Equal(private.Public().(ed25519.PublicKey), s.public) {
+		return nil, fmt.Errorf("keychain entry %s/%s changed since signer creation", s.service, s.account)
+	}
+	return ed25519.Sign(private, message), nil
+}
+
+// wipe zeroes sensitive bytes
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,103 @@
+// WHY: Local in-memory keys are the development and test default. They are
+// simple and fast, but the private key lives in process memory, so
+// production deployments should prefer the keychain or remote signers.
+package signing
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+)
+
+// LocalSigner signs with an Ed25519 key held in process memory
+type LocalSigner struct {
+	keyID   string
+	private ed25519.PrivateKey
+}
+
+// NewLocalSigner wraps an existing Ed25519 private key
+func NewLocalSigner(keyID string, private ed25519.PrivateKey) (*LocalSigner, error) {
+	if keyID == "" {
+		return nil, fmt.Errorf("empty key ID")
+	}
+	if len(private) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid ed25519 private key size %d", len(private))
+	}
+	return &LocalSigner{keyID: keyID, private: private}, nil
+}
+
+// GenerateLocalSigner creates a signer with a fresh random key
+func GenerateLocalSigner(keyID string) (*LocalSigner, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate ed25519 key: %w", err)
+	}
+	return NewLocalSigner(keyID, private)
+}
+
+// LoadLocalSigner reads a PEM-encoded PKCS#8 Ed25519 private key file
+func LoadLocalSigner(keyID string, path string) (*LocalSigner, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read key file: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("key file %s is not a PEM private key", path)
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %w", err)
+	}
+
+	private, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("key file %s holds %T, want ed25519", path, parsed)
+	}
+	return NewLocalSigner(keyID, private)
+}
+
+// WriteKeyFile writes the private key as a PEM-encoded PKCS#8 file only
+// its owner can read, the form LoadLocalSigner reads. It refuses to
+// overwrite an existing file.
+func (s *LocalSigner) WriteKeyFile(path string) error {
+	der, err := x509.MarshalPKCS8PrivateKey(s.private)
+	if err != nil {
+		return fmt.Errorf("encode private key: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := pem.Encode(file, &pem.Block{Type: "PRIVATE KEY", Bytes: der}); err != nil {
+		file.Close()
+		return fmt.Errorf("write key file: %w", err)
+	}
+	return file.Close()
+}
+
+// KeyID returns the key identifier
+func (s *LocalSigner) KeyID() string {
+	return s.keyID
+}
+
+// Algorithm returns AlgorithmEd25519
+func (s *LocalSigner) Algorithm() string {
+	return AlgorithmEd25519
+}
+
+// Public returns the Ed25519 public key
+func (s *LocalSigner) Public() crypto.PublicKey {
+	return s.private.Public()
+}
+
+// Sign signs the message with the in-memory key
+func (s *LocalSigner) Sign(message []byte) ([]byte, error) {
+	return ed25519.Sign(s.private, message), nil
+}
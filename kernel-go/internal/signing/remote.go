@@ -0,0 +1,122 @@
+// WHY: Cloud KMS and PKCS#11 HSMs never release private keys. The kernel
+// sends a digest and receives a signature, so a compromised kernel process
+// can misuse the key while it runs but can never exfiltrate it.
+package signing
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// RemoteKey is an ECDSA P-256 private key held by an external system.
+// Implementations wrap the vendor SDK (AWS/GCP/Azure KMS, a PKCS#11
+// session) outside the kernel; the kernel depends only on this interface.
+type RemoteKey interface {
+	// PublicKey returns the key's ECDSA P-256 public key
+	PublicKey(ctx context.Context) (crypto.PublicKey, error)
+
+	// SignDigest signs a SHA-256 digest. The signature may be ASN.1 DER
+	// (KMS convention) or raw 64-byte r||s (PKCS#11 CKM_ECDSA convention).
+	SignDigest(ctx context.Context, digest []byte) ([]byte, error)
+}
+
+// RemoteSigner signs through a RemoteKey with a bounded call time
+type RemoteSigner struct {
+	keyID   string
+	key     RemoteKey
+	public  *ecdsa.PublicKey
+	timeout time.Duration
+}
+
+// NewRemoteSigner fetches and validates the remote public key.
+// WHY: Fail-closed at startup - a key we cannot inspect is not used.
+func NewRemoteSigner(keyID string, key RemoteKey, timeout time.Duration) (*RemoteSigner, error) {
+	if keyID == "" {
+		return nil, fmt.Errorf("empty key ID")
+	}
+	if key == nil {
+		return nil, fmt.Errorf("nil remote key")
+	}
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	public, err := key.PublicKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch remote public key %s: %w", keyID, err)
+	}
+	ecdsaKey, ok := public.(*ecdsa.PublicKey)
+	if !ok || ecdsaKey.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("remote key %s is %T, want ECDSA P-256", keyID, public)
+	}
+
+	return &RemoteSigner{
+		keyID:   keyID,
+		key:     key,
+		public:  ecdsaKey,
+		timeout: timeout,
+	}, nil
+}
+
+// KeyID returns the key identifier
+func (s *RemoteSigner) KeyID() string {
+	return s.keyID
+}
+
+// Algorithm returns AlgorithmECDSAP256
+func (s *RemoteSigner) Algorithm() string {
+	return AlgorithmECDSAP256
+}
+
+// Public returns the ECDSA public key
+func (s *RemoteSigner) Public() crypto.PublicKey {
+	return s.public
+}
+
+// Sign hashes the message, asks the remote key to sign, and verifies the
+// result before returning it as ASN.1 DER.
+func (s *RemoteSigner) Sign(message []byte) ([]byte, error) {
+	digest := sha256.Sum256(message)
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	signature, err := s.key.SignDigest(ctx, digest[:])
+	if err != nil {
+		return nil, fmt.Errorf("remote sign with %s: %w", s.keyID, err)
+	}
+
+	if len(signature) == 64 {
+		signature, err = rawToASN1(signature)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Never hand out a signature that would not verify
+	if !ecdsa.VerifyASN1(s.public, digest[:], signature) {
+		return nil, fmt.Errorf("remote key %s returned an invalid signature", s.keyID)
+	}
+	return signature, nil
+}
+
+// rawToASN1 converts a PKCS#11 r||s signature into ASN.1 DER
+func rawToASN1(raw []byte) ([]byte, error) {
+	r := new(big.Int).SetBytes(raw[:32])
+	sv := new(big.Int).SetBytes(raw[32:])
+	der, err := asn1.Marshal(struct{ R, S *big.Int }{r, sv})
+	if err != nil {
+		return nil, fmt.Errorf("encode ecdsa signature: %w", err)
+	}
+	return der, nil
+}
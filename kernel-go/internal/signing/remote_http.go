@@ -0,0 +1,96 @@
+// WHY: RemoteSigner took any RemoteKey, but the kernel shipped none, so a
+// deployment could not name a KMS or HSM in its config. HTTPRemoteKey
+// reaches one through a signing service, typically a sidecar holding the
+// vendor SDK or PKCS#11 session: the kernel sends only a digest and gets
+// back a signature, and the private key never leaves the service.
+package signing
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxRemoteResponse bounds a signing service response
+const maxRemoteResponse = 64 << 10
+
+// HTTPRemoteKey is a RemoteKey behind a signing service at a base URL:
+// GET {url}/public_key answers the PEM public key, and POST {url}/sign
+// takes {"digest": base64} and answers {"signature": base64}
+type HTTPRemoteKey struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewHTTPRemoteKey creates a key for the service at url; headers (e.g.
+// Authorization) are sent with every request
+func NewHTTPRemoteKey(url string, headers map[string]string) *HTTPRemoteKey {
+	copied := make(map[string]string, len(headers))
+	for key, value := range headers {
+		copied[key] = value
+	}
+	return &HTTPRemoteKey{url: strings.TrimSuffix(url, "/"), headers: copied, client: &http.Client{}}
+}
+
+// PublicKey fetches the service's public key
+func (k *HTTPRemoteKey) PublicKey(ctx context.Context) (crypto.PublicKey, error) {
+	body, err := k.do(ctx, http.MethodGet, "/public_key", nil)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePublicKey(body)
+}
+
+// SignDigest asks the service to sign digest
+func (k *HTTPRemoteKey) SignDigest(ctx context.Context, digest []byte) ([]byte, error) {
+	request, err := json.Marshal(map[string]string{"digest": base64.StdEncoding.EncodeToString(digest)})
+	if err != nil {
+		return nil, err
+	}
+	body, err := k.do(ctx, http.MethodPost, "/sign", request)
+	if err != nil {
+		return nil, err
+	}
+	var response struct {
+		Signature string `json:"signature"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("signing service response: %w", err)
+	}
+	return base64.StdEncoding.DecodeString(response.Signature)
+}
+
+// do sends one request and returns the body of a 2xx response
+func (k *HTTPRemoteKey) do(ctx context.Context, method string, path string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, k.url+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("signing service request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range k.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("signing service: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteResponse))
+	if err != nil {
+		return nil, fmt.Errorf("signing service: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("signing service %s %s: %s", method, path, resp.Status)
+	}
+	return data, nil
+}
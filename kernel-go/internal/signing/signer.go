@@ -0,0 +1,165 @@
+// WHY: Signing keys are the root of trust for tokens and receipts.
+// A narrow Signer interface lets production deployments keep private key
+// material in a keychain, cloud KMS, or HSM while the kernel only ever
+// handles public keys and signatures.
+package signing
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Signature algorithms produced by signers in this package
+const (
+	AlgorithmEd25519   = "ed25519"
+	AlgorithmECDSAP256 = "ecdsa-p256-sha256"
+)
+
+// Signer produces signatures over messages.
+// WHY: Callers never see private keys, so the backend can move from local
+// memory to hardware without touching the corridor.
+type Signer interface {
+	// KeyID identifies the key so verifiers can select the right public key
+	KeyID() string
+
+	// Algorithm names the signature scheme (AlgorithmEd25519, AlgorithmECDSAP256)
+	Algorithm() string
+
+	// Public returns the verification key
+	Public() crypto.PublicKey
+
+	// Sign signs the full message. Digesting, if any, is the signer's job.
+	Sign(message []byte) ([]byte, error)
+}
+
+// Verify checks a signature produced by any Signer in this package.
+// WHY: Fail-closed - unknown key types and bad signatures are errors.
+func Verify(public crypto.PublicKey, message []byte, signature []byte) error {
+	switch key := public.(type) {
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, message, signature) {
+			return fmt.Errorf("ed25519 signature invalid")
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(message)
+		if !ecdsa.VerifyASN1(key, digest[:], signature) {
+			return fmt.Errorf("ecdsa signature invalid")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported public key type %T", public)
+	}
+}
+
+// LoadPublicKey reads a PEM-encoded PKIX public key file (Ed25519 or ECDSA).
+// WHY: Auditors verify receipts with the public half only; they should
+// never need the kernel's private key on their machine.
+func LoadPublicKey(path string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read key file: %w", err)
+	}
+	public, err := ParsePublicKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("key file %s: %w", path, err)
+	}
+	return public, nil
+}
+
+// ParsePublicKey decodes a PEM-encoded PKIX public key (Ed25519 or ECDSA)
+func ParsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("not a PEM public key")
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %w", err)
+	}
+
+	switch parsed.(type) {
+	case ed25519.PublicKey, *ecdsa.PublicKey:
+		return parsed, nil
+	default:
+		return nil, fmt.Errorf("public key is %T, want ed25519 or ecdsa", parsed)
+	}
+}
+
+// EncodePublicKey encodes a public key as PEM PKIX, the form
+// ParsePublicKey reads
+func EncodePublicKey(public crypto.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return nil, fmt.Errorf("encode public key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// Fingerprint returns the hex SHA-256 of a public key's PKIX encoding.
+// WHY: A fingerprint is short enough to pin in configuration, so a key
+// shipped alongside what it signs can be checked against one held apart.
+func Fingerprint(public crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return "", fmt.Errorf("encode public key: %w", err)
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// KeyRing maps key IDs to trusted public keys.
+// WHY: Verifiers must pin which keys they trust; a signature from an
+// unknown key is as bad as no signature.
+type KeyRing struct {
+	mu   sync.RWMutex
+	keys map[string]crypto.PublicKey
+}
+
+// NewKeyRing creates an empty key ring
+func NewKeyRing() *KeyRing {
+	return &KeyRing{
+		keys: make(map[string]crypto.PublicKey),
+	}
+}
+
+// Add trusts a public key under the given key ID
+func (r *KeyRing) Add(keyID string, public crypto.PublicKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if keyID == "" {
+		return fmt.Errorf("empty key ID")
+	}
+	if _, exists := r.keys[keyID]; exists {
+		return fmt.Errorf("key %s already trusted", keyID)
+	}
+	r.keys[keyID] = public
+	return nil
+}
+
+// AddSigner trusts the public half of a signer
+func (r *KeyRing) AddSigner(signer Signer) error {
+	return r.Add(signer.KeyID(), signer.Public())
+}
+
+// Verify checks a signature against the key trusted under keyID
+func (r *KeyRing) Verify(keyID string, message []byte, signature []byte) error {
+	r.mu.RLock()
+	public, exists := r.keys[keyID]
+	r.mu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("key %s is not trusted", keyID)
+	}
+	return Verify(public, message, signature)
+}
@@ -0,0 +1,270 @@
+// WHY: These tests prove every signer backend produces signatures the
+// shared verifier accepts, and that key material handling fails closed.
+package signing
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+// TestLocalSignerRoundTrip proves local signatures verify and bind the message
+func TestLocalSignerRoundTrip(t *testing.T) {
+	signer, err := GenerateLocalSigner("local_1")
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	signature, err := signer.Sign([]byte("receipt"))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if err := Verify(signer.Public(), []byte("receipt"), signature); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if err := Verify(signer.Public(), []byte("tampered"), signature); err == nil {
+		t.Fatal("signature must not verify for a different message")
+	}
+}
+
+// TestLoadLocalSignerFromPEM proves PKCS#8 key files load
+func TestLoadLocalSignerFromPEM(t *testing.T) {
+	_, private, _ := ed25519.GenerateKey(rand.Reader)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "mint.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	signer, err := LoadLocalSigner("file_key", path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !private.Public().(ed25519.PublicKey).Equal(signer.Public()) {
+		t.Fatal("loaded signer has wrong public key")
+	}
+}
+
+// TestLoadPublicKeyFromPEM proves PKIX public key files load and private
+// key files are refused
+func TestLoadPublicKeyFromPEM(t *testing.T) {
+	public, private, _ := ed25519.GenerateKey(rand.Reader)
+	der, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.pub.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	loaded, err := LoadPublicKey(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !public.Equal(loaded) {
+		t.Fatal("loaded wrong public key")
+	}
+
+	privateDER, _ := x509.MarshalPKCS8PrivateKey(private)
+	privatePath := filepath.Join(dir, "audit.pem")
+	os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0o600)
+	if _, err := LoadPublicKey(privatePath); err == nil {
+		t.Fatal("private key file must not load as a public key")
+	}
+}
+
+// fakeKeychain serves a fixed secret and counts reads
+type fakeKeychain struct {
+	secret string
+	reads  int
+}
+
+func (k *fakeKeychain) Read(service string, account string) ([]byte, error) {
+	k.reads++
+	if service != "oi-kernel" || account != "mint" {
+		return nil, fmt.Errorf("no entry")
+	}
+	return []byte(k.secret), nil
+}
+
+// TestKeychainSignerFetchesSeedPerSignature proves the seed is not cached
+func TestKeychainSignerFetchesSeedPerSignature(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	rand.Read(seed)
+	keychain := &fakeKeychain{secret: hex.EncodeToString(seed)}
+
+	signer, err := NewKeychainSigner("keychain_1", "oi-kernel", "mint", keychain)
+	if err != nil {
+		t.Fatalf("new keychain signer: %v", err)
+	}
+
+	signature, err := signer.Sign([]byte("token"))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if err := Verify(signer.Public(), []byte("token"), signature); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if keychain.reads != 2 {
+		t.Fatalf("expected seed read at creation and per signature, got %d reads", keychain.reads)
+	}
+
+	// A swapped keychain entry must not silently change the signing key
+	other := make([]byte, ed25519.SeedSize)
+	rand.Read(other)
+	keychain.secret = hex.EncodeToString(other)
+	if _, err := signer.Sign([]byte("token")); err == nil {
+		t.Fatal("signer must refuse a changed keychain entry")
+	}
+
+	if _, err := NewKeychainSigner("missing", "oi-kernel", "absent", keychain); err == nil {
+		t.Fatal("missing keychain entry must fail")
+	}
+}
+
+// fakeRemoteKey is an in-test stand-in for a KMS or HSM key
+type fakeRemoteKey struct {
+	private *ecdsa.PrivateKey
+	raw     bool
+	corrupt bool
+}
+
+func (k *fakeRemoteKey) PublicKey(ctx context.Context) (crypto.PublicKey, error) {
+	return &k.private.PublicKey, nil
+}
+
+func (k *fakeRemoteKey) SignDigest(ctx context.Context, digest []byte) ([]byte, error) {
+	if k.corrupt {
+		digest = append([]byte{}, digest...)
+		digest[0] ^= 0xff
+	}
+	r, s, err := ecdsa.Sign(rand.Reader, k.private, digest)
+	if err != nil {
+		return nil, err
+	}
+	if k.raw {
+		out := make([]byte, 64)
+		r.FillBytes(out[:32])
+		s.FillBytes(out[32:])
+		return out, nil
+	}
+	return asn1.Marshal(struct{ R, S *big.Int }{r, s})
+}
+
+// TestRemoteSignerKMSAndPKCS11Formats proves both signature encodings verify
+func TestRemoteSignerKMSAndPKCS11Formats(t *testing.T) {
+	private, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	for _, raw := range []bool{false, true} {
+		signer, err := NewRemoteSigner("kms_1", &fakeRemoteKey{private: private, raw: raw}, 0)
+		if err != nil {
+			t.Fatalf("new remote signer: %v", err)
+		}
+		signature, err := signer.Sign([]byte("receipt"))
+		if err != nil {
+			t.Fatalf("sign (raw=%v): %v", raw, err)
+		}
+		if err := Verify(signer.Public(), []byte("receipt"), signature); err != nil {
+			t.Fatalf("verify (raw=%v): %v", raw, err)
+		}
+	}
+
+	bad, _ := NewRemoteSigner("kms_bad", &fakeRemoteKey{private: private, corrupt: true}, 0)
+	if _, err := bad.Sign([]byte("receipt")); err == nil {
+		t.Fatal("remote signer must reject a signature that does not verify")
+	}
+}
+
+// TestHTTPRemoteKeySignsThroughService proves a remote signer reaches a
+// signing service over HTTP, sending its headers, and that a service
+// refusing to sign fails the signature
+func TestHTTPRemoteKeySignsThroughService(t *testing.T) {
+	private, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	key := &fakeRemoteKey{private: private, raw: true}
+	var refuse atomic.Bool
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer service-token" || refuse.Load() {
+			http.Error(w, "refused", http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/kms/public_key":
+			encoded, _ := EncodePublicKey(&private.PublicKey)
+			w.Write(encoded)
+		case "/kms/sign":
+			var request struct{ Digest string }
+			json.NewDecoder(r.Body).Decode(&request)
+			digest, _ := base64.StdEncoding.DecodeString(request.Digest)
+			signature, _ := key.SignDigest(r.Context(), digest)
+			json.NewEncoder(w).Encode(map[string]string{"signature": base64.StdEncoding.EncodeToString(signature)})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer service.Close()
+
+	remote := NewHTTPRemoteKey(service.URL+"/kms/", map[string]string{"Authorization": "Bearer service-token"})
+	signer, err := NewRemoteSigner("kms_http", remote, 0)
+	if err != nil {
+		t.Fatalf("new remote signer: %v", err)
+	}
+	signature, err := signer.Sign([]byte("token"))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if err := Verify(signer.Public(), []byte("token"), signature); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+
+	refuse.Store(true)
+	if _, err := signer.Sign([]byte("token")); err == nil {
+		t.Fatal("a service that refuses must fail the signature")
+	}
+	if _, err := NewRemoteSigner("kms_http", NewHTTPRemoteKey(service.URL+"/kms", nil), 0); err == nil {
+		t.Fatal("a key whose public half cannot be fetched must not be used")
+	}
+}
+
+// TestKeyRingRejectsUnknownKeys proves verifiers pin trusted keys
+func TestKeyRingRejectsUnknownKeys(t *testing.T) {
+	trusted, _ := GenerateLocalSigner("trusted")
+	rogue, _ := GenerateLocalSigner("rogue")
+
+	ring := NewKeyRing()
+	if err := ring.AddSigner(trusted); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if err := ring.AddSigner(trusted); err == nil {
+		t.Fatal("re-trusting a key ID must fail")
+	}
+
+	signature, _ := rogue.Sign([]byte("msg"))
+	if err := ring.Verify("rogue", []byte("msg"), signature); err == nil {
+		t.Fatal("untrusted key ID must fail verification")
+	}
+	if err := ring.Verify("trusted", []byte("msg"), signature); err == nil {
+		t.Fatal("signature from a different key must fail")
+	}
+}
@@ -3,6 +3,7 @@
 package C1_corridor_bypass
 
 import (
+	"context"
 	"testing"
 
 	"github.com/user/oi/kernel-go/internal/adapters"
@@ -71,7 +72,7 @@ func TestHiddenFallbackPath(t *testing.T) {
 	adapter := adapters.NewMockAdapter("fallback_test")
 
 	// Attempt invoke with nil token
-	_, _ = adapter.Invoke(nil, map[string]interface{}{"test": "data"})
+	_, _ = adapter.Invoke(context.Background(), nil, map[string]interface{}{"test": "data"})
 
 	// Even though Invoke might not check the token, VerifyToken should be called first
 	// and reject the request. This tests that there's no hidden fallback.
@@ -3,10 +3,13 @@
 package C7_stop_dominance
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/user/oi/kernel-go/internal/adapters"
+	"github.com/user/oi/kernel-go/internal/audit"
 	"github.com/user/oi/kernel-go/internal/capabilities"
 	"github.com/user/oi/kernel-go/internal/kernel"
 )
@@ -107,7 +110,7 @@ func TestAdapterRecheckStopBeforeSideEffect(t *testing.T) {
 	}
 
 	// Attempt to invoke should fail
-	_, err = adapter.Invoke(token, map[string]interface{}{})
+	_, err = adapter.Invoke(context.Background(), token, map[string]interface{}{})
 	// Note: current mock doesn't re-verify in Invoke, but VerifyToken should be called first
 	// In production, adapters must check STOP in VerifyToken before every operation
 
@@ -185,3 +188,146 @@ func TestStopAuditLogging(t *testing.T) {
 
 	t.Log("PASS: STOP event logged with token count")
 }
+
+// TestPrincipalStopRevokesOnlyTheirTokens proves a principal's STOP
+// revokes every token minted to them and none minted to anyone else
+func TestPrincipalStopRevokesOnlyTheirTokens(t *testing.T) {
+	state := kernel.NewSystemState("test_principal", "test_namespace")
+
+	mint := func(principal string) *capabilities.Token {
+		token, _ := capabilities.Mint("issuer", "subject", "audience",
+			[]string{"scope1"},
+			capabilities.Limits{MaxDepth: 10, MaxBudget: 100},
+			5*time.Minute,
+			capabilities.PostureBounds{MinPosture: 1, MaxPosture: 4},
+			"ns1", principal)
+		state.AddToken(token)
+		return token
+	}
+	alice1, alice2, bob := mint("alice"), mint("alice"), mint("bob")
+
+	// Invoke alice's STOP
+	if revoked := state.RevokeTokensFor("alice"); revoked != 2 {
+		t.Fatalf("FAIL: expected 2 tokens revoked, got %d", revoked)
+	}
+	if valid, _ := alice1.Verify(1); valid {
+		t.Fatal("FAIL: alice's first token should be revoked")
+	}
+	if valid, _ := alice2.Verify(1); valid {
+		t.Fatal("FAIL: alice's second token should be revoked")
+	}
+	if valid, err := bob.Verify(1); !valid {
+		t.Fatalf("FAIL: bob's token should survive alice's STOP: %v", err)
+	}
+
+	// The global STOP still takes everything
+	state.RevokeAllTokens()
+	if valid, _ := bob.Verify(1); valid {
+		t.Fatal("FAIL: global STOP should revoke bob's token")
+	}
+
+	scopes := []interface{}{}
+	for _, receipt := range state.AuditLedger.GetReceipts() {
+		if receipt.EventType == "stop_event" {
+			scopes = append(scopes, receipt.EventData["stop_scope"])
+		}
+	}
+	if len(scopes) != 2 || scopes[0] != "principal" || scopes[1] != "global" {
+		t.Fatalf("FAIL: STOP receipts must record their scope, got %v", scopes)
+	}
+
+	t.Log("PASS: principal STOP confined to the principal, global STOP still total")
+}
+
+// blockingAdapter holds every call until its context is done
+type blockingAdapter struct {
+	*adapters.MockAdapter
+	cancelled chan error
+}
+
+func (b *blockingAdapter) Invoke(ctx context.Context, token *capabilities.Token, params map[string]interface{}) (*adapters.AdapterResult, error) {
+	<-ctx.Done()
+	b.cancelled <- ctx.Err()
+	return nil, ctx.Err()
+}
+
+// TestStopPreemptsInFlightCalls proves STOP cancels calls already inside
+// an adapter, within a bounded time, and a principal's STOP only theirs
+func TestStopPreemptsInFlightCalls(t *testing.T) {
+	state := kernel.NewSystemState("test_principal", "test_namespace")
+	adapter := &blockingAdapter{MockAdapter: adapters.NewMockAdapter("blocking_adapter"), cancelled: make(chan error, 2)}
+	state.AdapterRegistry.Register(adapter)
+	state.AdapterRegistry.SetTimeout("blocking_adapter", time.Hour)
+
+	start := func(principal string) (*capabilities.Token, chan error) {
+		token, _ := capabilities.Mint("issuer", "subject", "audience",
+			[]string{"blocking_adapter"},
+			capabilities.Limits{MaxDepth: 10, MaxBudget: 100},
+			5*time.Minute,
+			capabilities.PostureBounds{MinPosture: 1, MaxPosture: 4},
+			"ns1", principal)
+		state.AddToken(token)
+		result := make(chan error, 1)
+		go func() {
+			_, err := state.AdapterRegistry.Invoke("blocking_adapter", token, 1, map[string]interface{}{})
+			result <- err
+		}()
+		deadline := time.Now().Add(time.Second)
+		for state.AdapterRegistry.InFlight(token.Digest) == 0 {
+			if time.Now().After(deadline) {
+				t.Fatalf("call for %s never started", principal)
+			}
+			time.Sleep(time.Millisecond)
+		}
+		return token, result
+	}
+	preempted := func(result chan error, who string) {
+		var stopped *adapters.StoppedError
+		select {
+		case err := <-result:
+			if !errors.As(err, &stopped) {
+				t.Fatalf("FAIL: %s's call should end in a StoppedError, got %v", who, err)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("FAIL: STOP did not preempt %s's in-flight call", who)
+		}
+		if err := <-adapter.cancelled; err != context.Canceled {
+			t.Fatalf("FAIL: the adapter should see %s's call cancelled, got %v", who, err)
+		}
+	}
+	_, alice := start("alice")
+	_, bob := start("bob")
+
+	// alice's STOP preempts only alice's call
+	state.RevokeTokensFor("alice")
+	preempted(alice, "alice")
+	select {
+	case err := <-bob:
+		t.Fatalf("FAIL: bob's call should survive alice's STOP, got %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// The global STOP preempts the rest
+	state.RevokeAllTokens()
+	preempted(bob, "bob")
+
+	// Each STOP is timed until its cancelled call returned
+	deadline := time.Now().Add(time.Second)
+	for {
+		page, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"stop_latency"}})
+		if len(page.Receipts) == 2 {
+			for _, receipt := range page.Receipts {
+				if receipt.EventData["calls_cancelled"] != 1 || receipt.EventData["within_slo"] != true {
+					t.Fatalf("FAIL: STOP should settle within its SLO, got %+v", receipt.EventData)
+				}
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("FAIL: every STOP should leave a stop_latency receipt, got %d", len(page.Receipts))
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Log("PASS: STOP preempts in-flight adapter calls")
+}
@@ -0,0 +1,126 @@
+// WHY: C9 conformance - prove namespace isolation
+// A capability minted for one namespace must not act for another: not
+// through an adapter, not on memory, and not by replaying a session's
+// token. Every attempt must be refused and leave a security receipt.
+package C9_namespace_isolation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/user/oi/kernel-go/internal/adapters"
+	"github.com/user/oi/kernel-go/internal/audit"
+	"github.com/user/oi/kernel-go/internal/capabilities"
+	"github.com/user/oi/kernel-go/internal/kernel"
+	"github.com/user/oi/kernel-go/internal/memory"
+)
+
+// mint mints and registers a token in namespace for principal with scope
+func mint(t *testing.T, state *kernel.SystemState, namespace string, principal string, scope ...string) *capabilities.Token {
+	t.Helper()
+	token, err := capabilities.Mint("issuer", principal, "audience", scope,
+		capabilities.Limits{MaxDepth: 10, MaxBudget: 100},
+		5*time.Minute,
+		capabilities.PostureBounds{MinPosture: 1, MaxPosture: 4},
+		namespace, principal)
+	if err != nil {
+		t.Fatalf("mint: %v", err)
+	}
+	state.AddToken(token)
+	return token
+}
+
+// violations returns the namespace violation receipts on the ledger
+func violations(state *kernel.SystemState) []audit.Receipt {
+	page, _ := state.AuditLedger.Query(audit.ReceiptFilter{EventTypes: []string{"namespace_violation"}})
+	return page.Receipts
+}
+
+// TestForeignTokenCannotDriveAdapter proves an adapter call acting for
+// namespace B is refused a token minted in namespace A
+// Pass condition: no side effect; the refusal is typed
+func TestForeignTokenCannotDriveAdapter(t *testing.T) {
+	registry := adapters.NewRegistry()
+	adapter := adapters.NewMockAdapter("isolated_adapter")
+	registry.Register(adapter)
+	state := kernel.NewSystemState("test_principal", "test_namespace")
+	token := mint(t, state, "tenant_a", "alice", "isolated_adapter")
+
+	_, err := registry.InvokeInNamespace(context.Background(), "tenant_b", "isolated_adapter", token, 1, map[string]interface{}{})
+	if _, ok := err.(*adapters.NamespaceError); !ok {
+		t.Fatalf("FAIL: cross-namespace call must be refused with a NamespaceError, got %v", err)
+	}
+	if len(adapter.GetInvocations()) != 0 {
+		t.Fatal("FAIL: cross-namespace call reached the adapter")
+	}
+
+	t.Log("PASS: foreign token refused before the adapter")
+}
+
+// TestForeignTokenCannotReachMemory proves memory reads and writes for
+// namespace B are refused a token minted in namespace A
+// Pass condition: B's memory is unchanged; each attempt is a critical receipt
+func TestForeignTokenCannotReachMemory(t *testing.T) {
+	state := kernel.NewSystemState("test_principal", "test_namespace")
+	alice := mint(t, state, "tenant_a", "alice", kernel.ScopeMemoryRead, kernel.ScopeMemoryWrite)
+	bob := mint(t, state, "tenant_b", "bob", kernel.ScopeMemoryRead, kernel.ScopeMemoryWrite)
+
+	if err := state.WriteMemoryIn("tenant_b", bob, memory.PartitionDurable, "plan", "bob's plan", nil); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := state.ReadMemoryIn("tenant_b", alice, memory.PartitionDurable, "plan"); err == nil {
+		t.Fatal("FAIL: tenant_a token read tenant_b memory")
+	}
+	if err := state.WriteMemoryIn("tenant_b", alice, memory.PartitionDurable, "plan", "poisoned", nil); err == nil {
+		t.Fatal("FAIL: tenant_a token wrote tenant_b memory")
+	}
+	entry, err := state.ReadMemoryIn("tenant_b", bob, memory.PartitionDurable, "plan")
+	if err != nil || entry.Content != "bob's plan" {
+		t.Fatalf("FAIL: tenant_b memory changed: %+v (%v)", entry, err)
+	}
+
+	receipts := violations(state)
+	if len(receipts) != 2 {
+		t.Fatalf("FAIL: want 2 namespace violation receipts, got %d", len(receipts))
+	}
+	for _, receipt := range receipts {
+		if receipt.Severity != audit.SeverityCritical {
+			t.Fatalf("FAIL: namespace violation must be critical, got %s", receipt.Severity)
+		}
+	}
+
+	t.Log("PASS: foreign token refused on memory and ledgered")
+}
+
+// TestSessionTokenStaysInItsNamespace proves a token a session request
+// was minted cannot be replayed against another namespace's memory
+// Pass condition: the replay is refused and ledgered against the target
+func TestSessionTokenStaysInItsNamespace(t *testing.T) {
+	state := kernel.NewSystemState("test_principal", "test_namespace")
+	state.AdapterRegistry.Register(adapters.NewMockAdapter(kernel.DefaultModelAdapter))
+	state.GovernanceCapsule.Rules = map[string]interface{}{"exists": true}
+	session, _ := state.Sessions.Open("alice", "tenant_a")
+
+	resp, err := kernel.Execute(&kernel.Request{RawInput: "test request", SessionID: session.ID}, state)
+	if err != nil || !resp.Success {
+		t.Fatalf("session request: %+v (%v)", resp, err)
+	}
+	var minted *capabilities.Token
+	for _, token := range state.ActiveCapabilityTokens {
+		minted = token
+	}
+	if minted == nil || minted.NamespaceID != "tenant_a" {
+		t.Fatalf("FAIL: session token must be minted in its namespace, got %+v", minted)
+	}
+
+	if _, err := state.ReadMemoryIn("tenant_b", minted, memory.PartitionDurable, "anything"); err == nil {
+		t.Fatal("FAIL: session token acted for another namespace")
+	}
+	receipts := violations(state)
+	if len(receipts) != 1 || receipts[0].EventData["namespace_id"] != "tenant_b" || receipts[0].EventData["token_digest"] != minted.Digest {
+		t.Fatalf("FAIL: replay must be ledgered against tenant_b, got %+v", receipts)
+	}
+
+	t.Log("PASS: session token confined to its namespace")
+}
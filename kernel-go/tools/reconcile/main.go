@@ -0,0 +1,133 @@
+// WHY: Forensic reconciliation must run on a clean machine against copies
+// of the evidence, never against a live kernel. This tool reads two JSONL
+// receipt files (for example the host ledger and a sink's copy), compares
+// them by sequence and hash, and reports where they diverge.
+//
+// Usage:
+//
+//	reconcile -local receipts.jsonl -remote sink_copy.jsonl [-json]
+//
+// Exit status is 0 when the copies are consistent, 1 when they diverge,
+// and 2 on usage or read errors.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run reconciles two ledger copies and returns the process exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	localPath := flags.String("local", "", "JSONL receipts held by the host")
+	remotePath := flags.String("remote", "", "JSONL receipts held off-box")
+	asJSON := flags.Bool("json", false, "emit the report as JSON")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *localPath == "" || *remotePath == "" {
+		fmt.Fprintln(stderr, "reconcile: -local and -remote are required")
+		return 2
+	}
+
+	local, err := readReceipts(*localPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "reconcile: %v\n", err)
+		return 2
+	}
+	remote, err := readReceipts(*remotePath)
+	if err != nil {
+		fmt.Fprintf(stderr, "reconcile: %v\n", err)
+		return 2
+	}
+
+	report := audit.Reconcile(local, remote)
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			fmt.Fprintf(stderr, "reconcile: %v\n", err)
+			return 2
+		}
+	} else {
+		writeText(stdout, report)
+	}
+
+	if !report.Consistent() {
+		return 1
+	}
+	return 0
+}
+
+// readReceipts reads a JSONL receipt file without opening it as a ledger,
+// so a tampered copy can still be examined
+func readReceipts(path string) ([]audit.Receipt, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	receipts, err := audit.ReadReceipts(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return receipts, nil
+}
+
+// writeText prints a human-readable reconciliation report
+func writeText(w io.Writer, report audit.ReconciliationReport) {
+	fmt.Fprintf(w, "local receipts:  %d (%d duplicates)\n", report.LocalReceipts, report.LocalDuplicates)
+	fmt.Fprintf(w, "remote receipts: %d (%d duplicates)\n", report.RemoteReceipts, report.RemoteDuplicates)
+	fmt.Fprintf(w, "matched:         %d\n", report.Matched)
+
+	if report.LocalChainError != "" {
+		fmt.Fprintf(w, "local chain:     BROKEN: %s\n", report.LocalChainError)
+	}
+	if report.RemoteChainError != "" {
+		fmt.Fprintf(w, "remote chain:    BROKEN: %s\n", report.RemoteChainError)
+	}
+	if len(report.LocalConflicts) > 0 {
+		fmt.Fprintf(w, "local conflicting copies:  %v\n", report.LocalConflicts)
+	}
+	if len(report.RemoteConflicts) > 0 {
+		fmt.Fprintf(w, "remote conflicting copies: %v\n", report.RemoteConflicts)
+	}
+
+	if report.FirstDivergence >= 0 {
+		fmt.Fprintf(w, "first divergence at sequence %d\n", report.FirstDivergence)
+	}
+	for _, d := range report.Divergences {
+		fmt.Fprintf(w, "  seq %d: local %s (%s) remote %s (%s)\n",
+			d.Sequence, d.LocalHash, intact(d.LocalIntact), d.RemoteHash, intact(d.RemoteIntact))
+	}
+	for _, r := range report.MissingLocal {
+		fmt.Fprintf(w, "missing locally:  %d-%d\n", r.Start, r.End)
+	}
+	for _, r := range report.MissingRemote {
+		fmt.Fprintf(w, "missing remotely: %d-%d\n", r.Start, r.End)
+	}
+
+	if report.Consistent() {
+		fmt.Fprintln(w, "CONSISTENT")
+	} else {
+		fmt.Fprintln(w, "DIVERGENT")
+	}
+}
+
+func intact(ok bool) string {
+	if ok {
+		return "hash intact"
+	}
+	return "hash mismatch"
+}
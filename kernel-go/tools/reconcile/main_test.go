@@ -0,0 +1,61 @@
+// WHY: Proves the tool reports consistent copies as such and flags a
+// tampered copy with a failing exit status.
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/user/oi/kernel-go/internal/audit"
+)
+
+// writeLedger writes a small unsigned ledger to path and returns its receipts
+func writeLedger(t *testing.T, path string) []audit.Receipt {
+	t.Helper()
+	store, err := audit.OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	ledger, err := audit.OpenLedger(store)
+	if err != nil {
+		t.Fatalf("open ledger: %v", err)
+	}
+	defer ledger.Close()
+
+	actor := audit.Attribution{PrincipalID: "p", NamespaceID: "n"}
+	ledger.AppendCDIDecision(actor, "DENY", "h", "", "d1")
+	ledger.AppendStopEvent(actor, 0)
+	return ledger.GetReceipts()
+}
+
+// TestReconcileReportsTamperedCopy proves identical copies pass and an
+// edited copy is reported at the edited sequence
+func TestReconcileReportsTamperedCopy(t *testing.T) {
+	dir := t.TempDir()
+	local := filepath.Join(dir, "local.jsonl")
+	writeLedger(t, local)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-local", local, "-remote", local}, &stdout, &stderr); code != 0 {
+		t.Fatalf("identical copies should be consistent (%d): %s%s", code, stdout.String(), stderr.String())
+	}
+
+	content, _ := os.ReadFile(local)
+	remote := filepath.Join(dir, "remote.jsonl")
+	os.WriteFile(remote, bytes.Replace(content, []byte(`"DENY"`), []byte(`"ALLOW"`), 1), 0o600)
+
+	stdout.Reset()
+	if code := run([]string{"-local", local, "-remote", remote}, &stdout, &stderr); code != 1 {
+		t.Fatalf("tampered copy should exit 1, got %d", code)
+	}
+	if !strings.Contains(stdout.String(), "first divergence at sequence 1") || !strings.Contains(stdout.String(), "DIVERGENT") {
+		t.Fatalf("report should locate the edit:\n%s", stdout.String())
+	}
+
+	if code := run([]string{"-local", local}, &stdout, &stderr); code != 2 {
+		t.Fatal("missing -remote should be a usage error")
+	}
+}